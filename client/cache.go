@@ -0,0 +1,267 @@
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// CacheStore stores the cached responses. Implementations must be safe for concurrent
+	// use.
+	CacheStore interface {
+		// Get returns the entry stored under key if any and if it has not expired.
+		Get(key string) (*CacheEntry, bool)
+		// Set stores the entry under key. The entry expires after ttl, a ttl of 0 means
+		// that the entry never expires.
+		Set(key string, entry *CacheEntry, ttl time.Duration)
+	}
+
+	// CacheEntry is a cached response.
+	CacheEntry struct {
+		// StatusCode is the response status code.
+		StatusCode int
+		// Header is the response header.
+		Header http.Header
+		// Body is the response body.
+		Body []byte
+		// ETag is the value of the response ETag header used to revalidate the entry.
+		ETag string
+		// Expires is the time after which the entry is stale.
+		Expires time.Time
+		// Vary lists the names of the request headers listed in the response Vary header.
+		// The entries stored under the request method and URL key only record the Vary
+		// header names, the responses are stored under keys that also include the values
+		// of these request headers.
+		Vary []string
+	}
+
+	// LRUCache is an in-memory CacheStore that evicts the least recently used entries when it
+	// holds more entries than its capacity.
+	LRUCache struct {
+		mu       sync.Mutex
+		capacity int
+		entries  map[string]*list.Element
+		order    *list.List
+	}
+
+	// lruItem is an element of the LRUCache list.
+	lruItem struct {
+		key       string
+		entry     *CacheEntry
+		expiresAt time.Time
+	}
+)
+
+// DoCacheable sends a GET request made to a cacheable action. If the client Cache is set fresh
+// cached responses are served without sending the request, stale cached responses that have an
+// ETag are revalidated with a conditional request and the responses that carry a Cache-Control
+// max-age directive or an ETag are cached. DoCacheable behaves like DoIdempotent otherwise.
+func (c *Client) DoCacheable(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.Cache == nil || req.Method != "GET" {
+		return c.DoIdempotent(ctx, req)
+	}
+	key := req.Method + " " + req.URL.String()
+	entry, ok := c.Cache.Get(key)
+	if ok && len(entry.Vary) > 0 {
+		entry, ok = c.Cache.Get(variantKey(key, entry.Vary, req.Header))
+	}
+	if ok {
+		if time.Now().Before(entry.Expires) {
+			return entry.response(req), nil
+		}
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+	}
+	resp, err := c.DoIdempotent(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		refreshed := *entry
+		refreshed.Header = cloneHeader(entry.Header)
+		for _, h := range []string{"Cache-Control", "Date", "Expires", "Etag"} {
+			if v, ok := resp.Header[h]; ok {
+				refreshed.Header[h] = v
+			}
+		}
+		maxAge, _ := cacheMaxAge(refreshed.Header)
+		refreshed.Expires = time.Now().Add(maxAge)
+		c.store(key, req, &refreshed)
+		return refreshed.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	maxAge, cacheable := cacheMaxAge(resp.Header)
+	etag := resp.Header.Get("ETag")
+	if !cacheable || maxAge == 0 && etag == "" {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	entry = &CacheEntry{
+		StatusCode: resp.StatusCode,
+		Header:     cloneHeader(resp.Header),
+		Body:       body,
+		ETag:       etag,
+		Expires:    time.Now().Add(maxAge),
+	}
+	if vary := varyHeaders(resp.Header); vary != nil {
+		if len(vary) == 1 && vary[0] == "*" {
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			return resp, nil
+		}
+		entry.Vary = vary
+	}
+	c.store(key, req, entry)
+	return entry.response(req), nil
+}
+
+// store saves entry in the client cache. Entries without ETag are evicted once stale since they
+// cannot be revalidated.
+func (c *Client) store(key string, req *http.Request, entry *CacheEntry) {
+	var ttl time.Duration
+	if entry.ETag == "" {
+		ttl = time.Until(entry.Expires)
+		if ttl <= 0 {
+			return
+		}
+	}
+	if len(entry.Vary) > 0 {
+		c.Cache.Set(key, &CacheEntry{Vary: entry.Vary}, ttl)
+		key = variantKey(key, entry.Vary, req.Header)
+	}
+	c.Cache.Set(key, entry, ttl)
+}
+
+// response builds a response from the entry.
+func (e *CacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cloneHeader(e.Header),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// cacheMaxAge returns the freshness lifetime of the response with the given header. It returns
+// false if the response must not be stored. Responses whose Cache-Control header has the
+// no-cache directive or no max-age directive have a lifetime of 0.
+func cacheMaxAge(h http.Header) (time.Duration, bool) {
+	var maxAge time.Duration
+	for _, v := range h["Cache-Control"] {
+		for _, d := range strings.Split(v, ",") {
+			d = strings.ToLower(strings.TrimSpace(d))
+			switch {
+			case d == "no-store", d == "private":
+				return 0, false
+			case d == "no-cache":
+				return 0, true
+			case strings.HasPrefix(d, "max-age="):
+				secs, err := strconv.Atoi(strings.Trim(d[len("max-age="):], `"`))
+				if err == nil && secs > 0 {
+					maxAge = time.Duration(secs) * time.Second
+				}
+			}
+		}
+	}
+	return maxAge, true
+}
+
+// varyHeaders returns the sorted canonical names of the headers listed in the Vary header.
+func varyHeaders(h http.Header) []string {
+	var names []string
+	for _, v := range h["Vary"] {
+		for _, n := range strings.Split(v, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				names = append(names, http.CanonicalHeaderKey(n))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// variantKey returns the key of the response to the request with the given key and header that
+// varies by the given header names.
+func variantKey(key string, vary []string, h http.Header) string {
+	parts := make([]string, len(vary)+1)
+	parts[0] = key
+	for i, n := range vary {
+		parts[i+1] = n + ": " + strings.Join(h[n], ", ")
+	}
+	return strings.Join(parts, "\n")
+}
+
+// NewLRUCache returns an empty LRUCache that holds up to capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the entry stored under key if any and if it has not expired.
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	item := el.Value.(*lruItem)
+	if !item.expiresAt.IsZero() && time.Now().After(item.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return item.entry, true
+}
+
+// Set stores the entry under key and evicts the least recently used entry if the cache is full.
+func (c *LRUCache) Set(key string, entry *CacheEntry, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = &lruItem{key: key, entry: entry, expiresAt: expiresAt}
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruItem{key: key, entry: entry, expiresAt: expiresAt})
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*lruItem).key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
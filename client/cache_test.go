@@ -0,0 +1,151 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DoCacheable", func() {
+	var (
+		server       *httptest.Server
+		hits         int32
+		cacheControl string
+		etag         string
+		vary         string
+		c            *client.Client
+	)
+
+	BeforeEach(func() {
+		hits = 0
+		cacheControl = "max-age=60"
+		etag = ""
+		vary = ""
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&hits, 1)
+			if cacheControl != "" {
+				w.Header().Set("Cache-Control", cacheControl)
+			}
+			if vary != "" {
+				w.Header().Set("Vary", vary)
+			}
+			if etag != "" {
+				w.Header().Set("ETag", etag)
+				if r.Header.Get("If-None-Match") == etag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			}
+			fmt.Fprintf(w, "hit %d %s", n, r.Header.Get("Accept-Language"))
+		}))
+		c = client.New(nil)
+		c.Cache = client.NewLRUCache(10)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func(lang string) string {
+		req, err := http.NewRequest("GET", server.URL+"/bottles/1", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		if lang != "" {
+			req.Header.Set("Accept-Language", lang)
+		}
+		resp, err := c.DoCacheable(context.Background(), req)
+		Ω(err).ShouldNot(HaveOccurred())
+		defer resp.Body.Close()
+		Ω(resp.StatusCode).Should(Equal(200))
+		body, err := ioutil.ReadAll(resp.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(body)
+	}
+
+	It("serves fresh responses from the cache", func() {
+		Ω(get("")).Should(Equal("hit 1 "))
+		Ω(get("")).Should(Equal("hit 1 "))
+		Ω(atomic.LoadInt32(&hits)).Should(BeEquivalentTo(1))
+	})
+
+	It("does not cache responses without max-age nor ETag", func() {
+		cacheControl = ""
+		Ω(get("")).Should(Equal("hit 1 "))
+		Ω(get("")).Should(Equal("hit 2 "))
+	})
+
+	It("does not cache no-store responses", func() {
+		cacheControl = "no-store, max-age=60"
+		get("")
+		get("")
+		Ω(atomic.LoadInt32(&hits)).Should(BeEquivalentTo(2))
+	})
+
+	It("revalidates stale responses that have an ETag", func() {
+		cacheControl = "max-age=1"
+		etag = `"v1"`
+		Ω(get("")).Should(Equal("hit 1 "))
+		time.Sleep(1100 * time.Millisecond)
+		Ω(get("")).Should(Equal("hit 1 "))
+		Ω(atomic.LoadInt32(&hits)).Should(BeEquivalentTo(2))
+		Ω(get("")).Should(Equal("hit 1 "))
+		Ω(atomic.LoadInt32(&hits)).Should(BeEquivalentTo(2))
+	})
+
+	It("fetches the new response when the ETag changed", func() {
+		cacheControl = "no-cache"
+		etag = `"v1"`
+		Ω(get("")).Should(Equal("hit 1 "))
+		etag = `"v2"`
+		Ω(get("")).Should(Equal("hit 2 "))
+		Ω(get("")).Should(Equal("hit 2 "))
+		Ω(atomic.LoadInt32(&hits)).Should(BeEquivalentTo(3))
+	})
+
+	It("includes the Vary headers in the cache key", func() {
+		vary = "Accept-Language"
+		Ω(get("en")).Should(Equal("hit 1 en"))
+		Ω(get("fr")).Should(Equal("hit 2 fr"))
+		Ω(get("en")).Should(Equal("hit 1 en"))
+		Ω(atomic.LoadInt32(&hits)).Should(BeEquivalentTo(2))
+	})
+
+	It("does not cache when the client has no cache", func() {
+		c.Cache = nil
+		get("")
+		get("")
+		Ω(atomic.LoadInt32(&hits)).Should(BeEquivalentTo(2))
+	})
+})
+
+var _ = Describe("LRUCache", func() {
+	It("evicts the least recently used entries", func() {
+		cache := client.NewLRUCache(2)
+		cache.Set("a", &client.CacheEntry{}, 0)
+		cache.Set("b", &client.CacheEntry{}, 0)
+		_, ok := cache.Get("a")
+		Ω(ok).Should(BeTrue())
+		cache.Set("c", &client.CacheEntry{}, 0)
+		Ω(cache.Len()).Should(Equal(2))
+		_, ok = cache.Get("b")
+		Ω(ok).Should(BeFalse())
+		_, ok = cache.Get("a")
+		Ω(ok).Should(BeTrue())
+	})
+
+	It("expires entries after their TTL", func() {
+		cache := client.NewLRUCache(2)
+		cache.Set("a", &client.CacheEntry{}, time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+		_, ok := cache.Get("a")
+		Ω(ok).Should(BeFalse())
+	})
+})
@@ -4,14 +4,22 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"reflect"
+	"sync/atomic"
 
+	"github.com/goadesign/goa"
 	"golang.org/x/net/websocket"
 )
 
+// StreamErrorExitStatus is the exit status used by HandleStreamError.
+const StreamErrorExitStatus = 6
+
 // HandleResponse logs the response details and exits the process with a status computed from
 // the response status code. The mapping of response status code to exit status is as follows:
 //
@@ -94,3 +102,136 @@ func WSRead(ws *websocket.Conn) {
 		fmt.Printf("<< %s\n", msg[:n])
 	}
 }
+
+// PrintStream writes the results returned by recv to w as JSON lines until recv returns io.EOF.
+// It then writes the final result returned by result if result is not nil and returns a non nil
+// value. PrintStream closes the stream with close before returning, it also closes the stream when
+// the process receives an interrupt signal in which case it returns nil.
+func PrintStream(w io.Writer, recv func() (interface{}, error), result func() (interface{}, error), close func() error) error {
+	stop := closeOnInterrupt(close)
+	enc := json.NewEncoder(w)
+	err := func() error {
+		for {
+			v, err := recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+		}
+		if result == nil {
+			return nil
+		}
+		v, err := result()
+		if err != nil || isNil(v) {
+			return err
+		}
+		return enc.Encode(v)
+	}()
+	if stop() {
+		return nil
+	}
+	if cerr := close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WSStream sends the newline delimited JSON messages read from in to the websocket connection
+// and concurrently writes the messages received from the connection to w, one per line. It
+// returns once the server closes the connection or the process receives an interrupt signal, in
+// both cases the connection is closed gracefully and WSStream returns nil. WSStream returns the
+// error that caused the connection to be closed otherwise.
+func WSStream(ws *websocket.Conn, in io.Reader, w io.Writer) error {
+	stop := closeOnInterrupt(ws.Close)
+	sent := make(chan error, 1)
+	go func() {
+		dec := json.NewDecoder(in)
+		for {
+			var msg json.RawMessage
+			err := dec.Decode(&msg)
+			if err == io.EOF {
+				return
+			}
+			if err == nil {
+				err = websocket.Message.Send(ws, string(msg))
+			}
+			if err != nil {
+				sent <- err
+				ws.Close()
+				return
+			}
+		}
+	}()
+	var err error
+	for {
+		var msg string
+		if err = websocket.Message.Receive(ws, &msg); err != nil {
+			break
+		}
+		if _, err = fmt.Fprintln(w, msg); err != nil {
+			break
+		}
+	}
+	if stop() {
+		return nil
+	}
+	select {
+	case serr := <-sent:
+		return serr
+	default:
+	}
+	ws.Close()
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// HandleStreamError writes the error that interrupted a stream to STDERR prefixed with the error
+// name and exits the process with StreamErrorExitStatus. The error name is the error code for
+// goa errors and the Go type of the error otherwise.
+func HandleStreamError(err error) {
+	name := reflect.TypeOf(err).String()
+	if e, ok := err.(*goa.ErrorResponse); ok && e.Code != "" {
+		name = e.Code
+	}
+	fmt.Fprintf(os.Stderr, "error: %s: %s\n", name, err)
+	os.Exit(StreamErrorExitStatus)
+}
+
+// closeOnInterrupt calls closeFn when the process receives an interrupt signal. The returned
+// function stops listening for the signal and returns true if closeFn was called, it must be
+// called exactly once.
+func closeOnInterrupt(closeFn func() error) func() bool {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	done := make(chan struct{})
+	var interrupted int32
+	go func() {
+		select {
+		case <-sig:
+			atomic.StoreInt32(&interrupted, 1)
+			closeFn()
+		case <-done:
+		}
+	}()
+	return func() bool {
+		signal.Stop(sig)
+		close(done)
+		return atomic.LoadInt32(&interrupted) == 1
+	}
+}
+
+// isNil returns true if v is nil or a nil pointer.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
@@ -0,0 +1,120 @@
+package client_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/goadesign/goa/client"
+	"golang.org/x/net/websocket"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrintStream", func() {
+	type progress struct {
+		Done int `json:"done"`
+	}
+
+	var (
+		results []interface{}
+		recvErr error
+		result  func() (interface{}, error)
+		closed  bool
+		out     bytes.Buffer
+		err     error
+	)
+
+	BeforeEach(func() {
+		results = []interface{}{&progress{Done: 1}, &progress{Done: 2}}
+		recvErr = io.EOF
+		result = nil
+		closed = false
+		out.Reset()
+	})
+
+	JustBeforeEach(func() {
+		recv := func() (interface{}, error) {
+			if len(results) == 0 {
+				return nil, recvErr
+			}
+			v := results[0]
+			results = results[1:]
+			return v, nil
+		}
+		err = client.PrintStream(&out, recv, result, func() error { closed = true; return nil })
+	})
+
+	It("writes the results as JSON lines and closes the stream", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(out.String()).Should(Equal("{\"done\":1}\n{\"done\":2}\n"))
+		Ω(closed).Should(BeTrue())
+	})
+
+	Context("with a final result", func() {
+		BeforeEach(func() {
+			result = func() (interface{}, error) { return &progress{Done: 3}, nil }
+		})
+
+		It("writes the final result last", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(out.String()).Should(HaveSuffix("{\"done\":3}\n"))
+		})
+	})
+
+	Context("with a stream that ended without a final result", func() {
+		BeforeEach(func() {
+			result = func() (interface{}, error) { return (*progress)(nil), nil }
+		})
+
+		It("only writes the results", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(strings.Count(out.String(), "\n")).Should(Equal(2))
+		})
+	})
+
+	Context("with an error mid-stream", func() {
+		BeforeEach(func() {
+			recvErr = errors.New("boom")
+		})
+
+		It("returns the error and closes the stream", func() {
+			Ω(err).Should(MatchError("boom"))
+			Ω(out.String()).Should(Equal("{\"done\":1}\n{\"done\":2}\n"))
+			Ω(closed).Should(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("WSStream", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+			for i := 0; i < 2; i++ {
+				var msg string
+				if err := websocket.Message.Receive(ws, &msg); err != nil {
+					return
+				}
+				websocket.Message.Send(ws, "echo "+msg)
+			}
+			ws.Close()
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sends the input messages and writes the received messages", func() {
+		ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), "", server.URL)
+		Ω(err).ShouldNot(HaveOccurred())
+		var out bytes.Buffer
+		in := strings.NewReader("{\"name\":\"a\"}\n{\"name\":\"b\"}\n")
+		Ω(client.WSStream(ws, in, &out)).ShouldNot(HaveOccurred())
+		Ω(out.String()).Should(Equal("echo {\"name\":\"a\"}\necho {\"name\":\"b\"}\n"))
+	})
+})
@@ -33,6 +33,11 @@ type (
 		UserAgent string
 		// Dump indicates whether to dump request response.
 		Dump bool
+		// Singleflight coalesces the concurrent identical requests sent with DoIdempotent
+		// if not nil.
+		Singleflight *Singleflight
+		// Cache stores the responses to the requests sent with DoCacheable if not nil.
+		Cache CacheStore
 	}
 )
 
@@ -89,6 +94,15 @@ func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, err
 	return resp, err
 }
 
+// DoIdempotent sends a request made to an idempotent action. It coalesces the request with the
+// concurrent identical requests if the client Singleflight is set and behaves like Do otherwise.
+func (c *Client) DoIdempotent(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.Singleflight == nil {
+		return c.Do(ctx, req)
+	}
+	return c.Singleflight.Do(ctx, req, c.Do)
+}
+
 // Dump request if needed.
 func (c *Client) dumpRequest(ctx context.Context, req *http.Request) {
 	reqBody, err := dumpReqBody(req)
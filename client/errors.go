@@ -0,0 +1,152 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/goadesign/goa"
+)
+
+// MaxErrorBodySize is the maximum number of bytes of the response body kept by the errors that
+// describe a response.
+var MaxErrorBodySize = 4096
+
+type (
+	// ResponseError is the interface implemented by the errors that describe a HTTP response.
+	// It gives access to the response status code, headers and raw body for debugging.
+	ResponseError interface {
+		error
+		// StatusCode returns the response status code.
+		StatusCode() int
+		// Header returns the response headers.
+		Header() http.Header
+		// Body returns the first MaxErrorBodySize bytes of the response body.
+		Body() []byte
+	}
+
+	// HTTPError is the generic error that describes a response the client does not expect,
+	// for example a response whose status code is not listed in the design.
+	HTTPError struct {
+		status    int
+		header    http.Header
+		body      []byte
+		truncated bool
+	}
+
+	// DecodeError is the error returned by the generated client when decoding a response body
+	// fails. It records the raw body and content type of the response.
+	DecodeError struct {
+		*HTTPError
+		// ContentType is the response content type.
+		ContentType string
+		// Err is the error returned by the decoder.
+		Err error
+	}
+
+	// responseRecorder is implemented by the decoded types that record the response they
+	// were decoded from.
+	responseRecorder interface {
+		SetResponse(status int, header http.Header, body []byte)
+	}
+)
+
+// The errors returned by the generated clients describe the response.
+var (
+	_ ResponseError = (*HTTPError)(nil)
+	_ ResponseError = (*DecodeError)(nil)
+	_ ResponseError = (*goa.ErrorResponse)(nil)
+)
+
+// NewHTTPError reads the response body and returns the error that describes the response. Only
+// the first MaxErrorBodySize bytes of the body are kept.
+func NewHTTPError(resp *http.Response) (*HTTPError, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return newHTTPError(resp, body), nil
+}
+
+// DecodeResponse decodes the response body into v using the decoder registered for the response
+// content type. The body is read in full prior to decoding so that the returned *DecodeError
+// includes the beginning of the body if decoding fails. Decoded error responses record the
+// response status code, headers and body. DecodeResponse leaves v untouched if the response cannot
+// have a body.
+func DecodeResponse(decoder *goa.HTTPDecoder, v interface{}, resp *http.Response) error {
+	if !hasBody(resp) {
+		resp.Body.Close()
+		return nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	ct := resp.Header.Get("Content-Type")
+	if err := decoder.Decode(v, bytes.NewReader(body), ct); err != nil {
+		return &DecodeError{HTTPError: newHTTPError(resp, body), ContentType: ct, Err: err}
+	}
+	if r, ok := v.(responseRecorder); ok {
+		e := newHTTPError(resp, body)
+		r.SetResponse(e.status, e.header, e.body)
+	}
+	return nil
+}
+
+// hasBody returns false if the response cannot have a body: responses to HEAD requests and
+// responses with status 1xx, 204 or 304.
+func hasBody(resp *http.Response) bool {
+	if resp.Request != nil && resp.Request.Method == "HEAD" {
+		return false
+	}
+	return !goa.BodylessStatus(resp.StatusCode)
+}
+
+// newHTTPError builds the error that describes the response with the given body, the body is
+// truncated to MaxErrorBodySize bytes.
+func newHTTPError(resp *http.Response, body []byte) *HTTPError {
+	e := &HTTPError{status: resp.StatusCode, header: resp.Header}
+	if len(body) > MaxErrorBodySize {
+		body = body[:MaxErrorBodySize]
+		e.truncated = true
+	}
+	e.body = make([]byte, len(body))
+	copy(e.body, body)
+	return e
+}
+
+// Error returns the response status code and body.
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected response status %d: %s", e.status, e.snippet())
+}
+
+// StatusCode returns the response status code.
+func (e *HTTPError) StatusCode() int { return e.status }
+
+// Header returns the response headers.
+func (e *HTTPError) Header() http.Header { return e.header }
+
+// Body returns the first MaxErrorBodySize bytes of the response body.
+func (e *HTTPError) Body() []byte { return e.body }
+
+// Truncated returns true if the response body is larger than MaxErrorBodySize.
+func (e *HTTPError) Truncated() bool { return e.truncated }
+
+// snippet returns the quoted body, followed by an ellipsis if truncated.
+func (e *HTTPError) snippet() string {
+	s := fmt.Sprintf("%q", e.body)
+	if e.truncated {
+		s += "..."
+	}
+	return s
+}
+
+// Error returns the decoding error along with the response content type and body.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode %q response body with status %d: %s, body: %s",
+		e.ContentType, e.status, e.Err, e.snippet())
+}
+
+// Cause returns the error returned by the decoder.
+func (e *DecodeError) Cause() error { return e.Err }
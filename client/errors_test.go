@@ -0,0 +1,142 @@
+package client_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecodeResponse", func() {
+	var (
+		body    string
+		status  int
+		decoded interface{}
+		err     error
+	)
+
+	BeforeEach(func() {
+		status = 200
+	})
+
+	JustBeforeEach(func() {
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {"application/json"}, "X-Request-Id": {"abc"}},
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		}
+		decoder := goa.NewHTTPDecoder()
+		decoder.Register(goa.NewJSONDecoder, "application/json")
+		err = client.DecodeResponse(decoder, decoded, resp)
+	})
+
+	Context("with a valid body", func() {
+		var v map[string]string
+
+		BeforeEach(func() {
+			body = `{"name":"foo"}`
+			v = nil
+			decoded = &v
+		})
+
+		It("decodes the body", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(v).Should(Equal(map[string]string{"name": "foo"}))
+		})
+	})
+
+	Context("with an error response", func() {
+		var e goa.ErrorResponse
+
+		BeforeEach(func() {
+			status = 400
+			body = `{"id":"1","code":"bad_request","status":400,"detail":"invalid"}`
+			e = goa.ErrorResponse{}
+			decoded = &e
+		})
+
+		It("records the response in the decoded error", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			var re client.ResponseError = &e
+			Ω(re.StatusCode()).Should(Equal(400))
+			Ω(re.Header().Get("X-Request-Id")).Should(Equal("abc"))
+			Ω(string(re.Body())).Should(Equal(body))
+		})
+	})
+
+	Context("with a no content response", func() {
+		var v map[string]string
+
+		BeforeEach(func() {
+			status = 204
+			body = "garbage"
+			v = map[string]string{"name": "foo"}
+			decoded = &v
+		})
+
+		It("leaves the value untouched", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(v).Should(Equal(map[string]string{"name": "foo"}))
+		})
+	})
+
+	Context("with an invalid body", func() {
+		BeforeEach(func() {
+			status = 502
+			body = "<html>Bad Gateway</html>"
+			var v map[string]string
+			decoded = &v
+		})
+
+		It("returns a decode error with the body and content type", func() {
+			Ω(err).Should(HaveOccurred())
+			de, ok := err.(*client.DecodeError)
+			Ω(ok).Should(BeTrue())
+			Ω(de.ContentType).Should(Equal("application/json"))
+			Ω(de.StatusCode()).Should(Equal(502))
+			Ω(de.Header().Get("X-Request-Id")).Should(Equal("abc"))
+			Ω(string(de.Body())).Should(Equal(body))
+			Ω(de.Truncated()).Should(BeFalse())
+			Ω(de.Error()).Should(ContainSubstring(`"<html>Bad Gateway</html>"`))
+		})
+	})
+
+	Context("with a huge invalid body", func() {
+		BeforeEach(func() {
+			body = strings.Repeat("x", client.MaxErrorBodySize*3)
+			var v map[string]string
+			decoded = &v
+		})
+
+		It("truncates the body", func() {
+			Ω(err).Should(HaveOccurred())
+			de, ok := err.(*client.DecodeError)
+			Ω(ok).Should(BeTrue())
+			Ω(de.Body()).Should(HaveLen(client.MaxErrorBodySize))
+			Ω(de.Truncated()).Should(BeTrue())
+			Ω(de.Error()).Should(HaveSuffix("..."))
+		})
+	})
+})
+
+var _ = Describe("NewHTTPError", func() {
+	It("keeps the beginning of huge bodies", func() {
+		resp := &http.Response{
+			StatusCode: 500,
+			Header:     http.Header{"Content-Type": {"text/plain"}},
+			Body:       ioutil.NopCloser(strings.NewReader(strings.Repeat("y", client.MaxErrorBodySize+1))),
+		}
+		e, err := client.NewHTTPError(resp)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(e.StatusCode()).Should(Equal(500))
+		Ω(e.Body()).Should(HaveLen(client.MaxErrorBodySize))
+		Ω(e.Truncated()).Should(BeTrue())
+		Ω(e.Error()).Should(HavePrefix("unexpected response status 500: "))
+	})
+})
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+)
+
+// QueryOption sets query string parameters of the paths built by the generated path functions.
+type QueryOption func(url.Values)
+
+// AppendQuery returns the path with the query string built by applying opts appended to it.
+func AppendQuery(path string, opts ...QueryOption) string {
+	if len(opts) == 0 {
+		return path
+	}
+	values := make(url.Values)
+	for _, opt := range opts {
+		opt(values)
+	}
+	if len(values) == 0 {
+		return path
+	}
+	return path + "?" + values.Encode()
+}
+
+// EscapeCatchAll escapes the value of a catch-all path parameter. The value may be a sub-path made
+// of multiple segments, each segment is escaped but the slashes that separate them are not.
+func EscapeCatchAll(p string) string {
+	segments := strings.Split(strings.TrimLeft(p, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// SetURLPath sets the path and the query string of u from p. p may contain escaped characters and a
+// query string such as the paths built by the generated path functions, the escaped characters are
+// sent as is.
+func SetURLPath(u *url.URL, p string) {
+	if i := strings.IndexByte(p, '?'); i >= 0 {
+		u.RawQuery = p[i+1:]
+		p = p[:i]
+	}
+	u.Path = p
+	if unescaped, err := url.PathUnescape(p); err == nil && unescaped != p {
+		u.Path = unescaped
+		u.RawPath = p
+	}
+}
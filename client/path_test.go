@@ -0,0 +1,48 @@
+package client_test
+
+import (
+	"net/url"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("path helpers", func() {
+	filter := func(v string) client.QueryOption {
+		return func(values url.Values) { values.Set("filter", v) }
+	}
+
+	Context("AppendQuery", func() {
+		It("appends the query options", func() {
+			Ω(client.AppendQuery("/orders", filter("a b"))).Should(Equal("/orders?filter=a+b"))
+		})
+
+		It("returns the path as is without options", func() {
+			Ω(client.AppendQuery("/orders")).Should(Equal("/orders"))
+		})
+	})
+
+	Context("EscapeCatchAll", func() {
+		It("escapes the segments but not the slashes", func() {
+			Ω(client.EscapeCatchAll("/docs/a b/c%d")).Should(Equal("docs/a%20b/c%25d"))
+		})
+	})
+
+	Context("SetURLPath", func() {
+		It("keeps the escaped characters", func() {
+			u := url.URL{Scheme: "http", Host: "example.com"}
+			client.SetURLPath(&u, "/users/a%2Fb?filter=x")
+			Ω(u.Path).Should(Equal("/users/a/b"))
+			Ω(u.RawQuery).Should(Equal("filter=x"))
+			Ω(u.String()).Should(Equal("http://example.com/users/a%2Fb?filter=x"))
+		})
+
+		It("escapes unescaped paths", func() {
+			u := url.URL{Scheme: "http", Host: "example.com"}
+			client.SetURLPath(&u, "/users/a b")
+			Ω(u.String()).Should(Equal("http://example.com/users/a%20b"))
+		})
+	})
+})
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// Singleflight coalesces concurrent identical requests into a single HTTP call. Two requests
+	// are identical if they use the same method, URL, headers and body. The callers waiting on
+	// a coalesced call each get their own copy of the response so that they may read and
+	// decode the body independently.
+	Singleflight struct {
+		mu    sync.Mutex
+		calls map[string]*flight
+	}
+
+	// flight is a call in progress or completed.
+	flight struct {
+		done chan struct{}
+		resp *http.Response
+		body []byte
+		err  error
+	}
+
+	// detachedContext is a context that carries the values of its parent but is never
+	// canceled.
+	detachedContext struct {
+		context.Context
+	}
+)
+
+// NewSingleflight returns a Singleflight with no call in progress.
+func NewSingleflight() *Singleflight {
+	return &Singleflight{calls: make(map[string]*flight)}
+}
+
+// Do calls do with req unless an identical request is already in flight in which case it waits
+// for the result of that call. The call made by do is not canceled when the context of a caller
+// is canceled so that the other callers still get the result, the callers whose context is
+// canceled return the context error without waiting. The error returned by do is returned to all
+// the callers. Do must only be used for requests made to idempotent actions.
+func (s *Singleflight) Do(ctx context.Context, req *http.Request, do func(context.Context, *http.Request) (*http.Response, error)) (*http.Response, error) {
+	key, err := requestKey(req)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	f, ok := s.calls[key]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		s.calls[key] = f
+		go s.call(ctx, key, f, req, do)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := *f.resp
+	resp.Header = cloneHeader(f.resp.Header)
+	resp.Trailer = cloneHeader(f.resp.Trailer)
+	resp.Body = ioutil.NopCloser(bytes.NewReader(f.body))
+	return &resp, nil
+}
+
+// call makes the request and records the result in f.
+func (s *Singleflight) call(ctx context.Context, key string, f *flight, req *http.Request, do func(context.Context, *http.Request) (*http.Response, error)) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.calls, key)
+		s.mu.Unlock()
+		close(f.done)
+	}()
+	resp, err := do(detachedContext{ctx}, req)
+	if err != nil {
+		f.err = err
+		return
+	}
+	defer resp.Body.Close()
+	f.body, f.err = ioutil.ReadAll(resp.Body)
+	f.resp = resp
+}
+
+// requestKey returns the key identifying identical requests. It reads the request body and
+// replaces it with a reader over the bytes read.
+func requestKey(req *http.Request) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.String() + "\n"))
+	names := make([]string, 0, len(req.Header))
+	for n := range req.Header {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		for _, v := range req.Header[n] {
+			h.Write([]byte(n + ": " + v + "\n"))
+		}
+	}
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		h.Write([]byte("\n"))
+		h.Write(body)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cloneHeader returns a deep copy of h.
+func cloneHeader(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
+
+// Deadline returns no deadline.
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+// Done returns nil so that the context is never canceled.
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+// Err returns nil.
+func (detachedContext) Err() error { return nil }
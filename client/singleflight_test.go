@@ -0,0 +1,151 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Singleflight", func() {
+	const callers = 10
+
+	var (
+		sf      *client.Singleflight
+		calls   int32
+		release chan struct{}
+		callErr error
+		do      func(context.Context, *http.Request) (*http.Response, error)
+	)
+
+	newRequest := func(url string) *http.Request {
+		req, err := http.NewRequest("GET", url, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		return req
+	}
+
+	BeforeEach(func() {
+		sf = client.NewSingleflight()
+		calls = 0
+		release = make(chan struct{})
+		callErr = nil
+		do = func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			if callErr != nil {
+				return nil, callErr
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"name":"bottle"}`)),
+			}, nil
+		}
+	})
+
+	// run makes concurrent requests with the given contexts and URLs and waits for all the
+	// callers to be waiting before releasing the call.
+	run := func(ctxs []context.Context, urls []string) ([]*http.Response, []error) {
+		resps := make([]*http.Response, len(ctxs))
+		errs := make([]error, len(ctxs))
+		var wg sync.WaitGroup
+		for i := range ctxs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				resps[i], errs[i] = sf.Do(ctxs[i], newRequest(urls[i]), do)
+			}(i)
+		}
+		Eventually(func() int32 { return atomic.LoadInt32(&calls) }).ShouldNot(BeZero())
+		time.Sleep(50 * time.Millisecond) // let the other callers join the call in flight
+		close(release)
+		wg.Wait()
+		return resps, errs
+	}
+
+	sameRequests := func() ([]context.Context, []string) {
+		ctxs := make([]context.Context, callers)
+		urls := make([]string, callers)
+		for i := range ctxs {
+			ctxs[i] = context.Background()
+			urls[i] = "http://example.com/bottles/1"
+		}
+		return ctxs, urls
+	}
+
+	It("coalesces concurrent identical requests", func() {
+		resps, errs := run(sameRequests())
+		Ω(atomic.LoadInt32(&calls)).Should(BeNumerically("<", callers))
+		for i, resp := range resps {
+			Ω(errs[i]).ShouldNot(HaveOccurred())
+			body, err := ioutil.ReadAll(resp.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(body)).Should(Equal(`{"name":"bottle"}`))
+		}
+	})
+
+	It("gives each caller its own copy of the response", func() {
+		resps, _ := run(sameRequests())
+		resps[0].Header.Set("Content-Type", "text/plain")
+		Ω(resps[1].Header.Get("Content-Type")).Should(Equal("application/json"))
+	})
+
+	It("does not coalesce different requests", func() {
+		ctxs, urls := sameRequests()
+		urls[1] = "http://example.com/bottles/2"
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				sf.Do(ctxs[i], newRequest(urls[i]), do)
+			}(i)
+		}
+		Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(BeEquivalentTo(2))
+		close(release)
+		wg.Wait()
+	})
+
+	It("returns the call error to all the callers", func() {
+		callErr = errors.New("boom")
+		_, errs := run(sameRequests())
+		for _, err := range errs {
+			Ω(err).Should(MatchError("boom"))
+		}
+	})
+
+	It("does not cancel the shared call when a caller context is canceled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		canceled := make(chan error, 1)
+		go func() {
+			_, err := sf.Do(ctx, newRequest("http://example.com/bottles/1"), do)
+			canceled <- err
+		}()
+		Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(BeEquivalentTo(1))
+		var (
+			resp *http.Response
+			err  error
+			wg   sync.WaitGroup
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err = sf.Do(context.Background(), newRequest("http://example.com/bottles/1"), do)
+		}()
+		cancel()
+		Eventually(canceled).Should(Receive(Equal(context.Canceled)))
+		close(release)
+		wg.Wait()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resp.StatusCode).Should(Equal(200))
+	})
+})
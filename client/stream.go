@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/goadesign/goa"
+)
+
+// ResultStreamReader reads the results sent by actions that stream results followed by an
+// optional final result. The generated code wraps ResultStreamReader with typed Recv methods and
+// an accessor to the final result.
+type ResultStreamReader struct {
+	recv   func(*goa.StreamFrame) error
+	close  func() error
+	result json.RawMessage
+	done   bool
+}
+
+// NewResultStreamReader returns a reader that reads the frames with recv and closes the
+// underlying transport with close. recv must return io.EOF when the stream ends.
+func NewResultStreamReader(recv func(*goa.StreamFrame) error, close func() error) *ResultStreamReader {
+	return &ResultStreamReader{recv: recv, close: close}
+}
+
+// NewNDJSONResultStreamReader returns a reader that reads the frames from the newline delimited
+// JSON body of the given response.
+func NewNDJSONResultStreamReader(body io.ReadCloser) *ResultStreamReader {
+	dec := json.NewDecoder(body)
+	return NewResultStreamReader(func(f *goa.StreamFrame) error { return dec.Decode(f) }, body.Close)
+}
+
+// NewSSEResultStreamReader returns a reader that reads the frames from the server-sent events
+// body of the given response. Server-sent events do not carry a final result.
+func NewSSEResultStreamReader(body io.ReadCloser) *ResultStreamReader {
+	r := bufio.NewReader(body)
+	recv := func(f *goa.StreamFrame) error {
+		var data []byte
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			line = strings.TrimRight(line, "\r\n")
+			if line == "" {
+				if len(data) > 0 {
+					break
+				}
+				continue
+			}
+			if strings.HasPrefix(line, "data:") {
+				if len(data) > 0 {
+					data = append(data, '\n')
+				}
+				data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")...)
+			}
+		}
+		f.Data = data
+		return nil
+	}
+	return NewResultStreamReader(recv, body.Close)
+}
+
+// Recv decodes the next streamed result into v. It returns io.EOF once the final result is
+// received or the stream ends.
+func (r *ResultStreamReader) Recv(v interface{}) error {
+	if r.done {
+		return io.EOF
+	}
+	var f goa.StreamFrame
+	if err := r.recv(&f); err != nil {
+		if err == io.EOF {
+			r.done = true
+		}
+		return err
+	}
+	if f.Terminal {
+		r.done = true
+		r.result = f.Data
+		return io.EOF
+	}
+	return json.Unmarshal(f.Data, v)
+}
+
+// Result decodes the final result into v. It returns false if the final result has not been
+// received, either because Recv has not returned io.EOF yet or because the stream ended without
+// one.
+func (r *ResultStreamReader) Result(v interface{}) (bool, error) {
+	if r.result == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(r.result, v)
+}
+
+// Close closes the underlying transport.
+func (r *ResultStreamReader) Close() error {
+	return r.close()
+}
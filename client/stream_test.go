@@ -0,0 +1,59 @@
+package client_test
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResultStreamReader", func() {
+	type progress struct {
+		Done int `json:"done"`
+	}
+	type summary struct {
+		Imported int `json:"imported"`
+	}
+
+	Context("with newline delimited JSON", func() {
+		It("reads the results followed by the final result", func() {
+			body := `{"data":{"done":1}}` + "\n" + `{"data":{"done":2}}` + "\n" + `{"terminal":true,"data":{"imported":2}}` + "\n"
+			r := client.NewNDJSONResultStreamReader(ioutil.NopCloser(strings.NewReader(body)))
+			var p progress
+			Ω(r.Recv(&p)).ShouldNot(HaveOccurred())
+			Ω(p.Done).Should(Equal(1))
+			var s summary
+			ok, err := r.Result(&s)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ok).Should(BeFalse())
+			Ω(r.Recv(&p)).ShouldNot(HaveOccurred())
+			Ω(p.Done).Should(Equal(2))
+			Ω(r.Recv(&p)).Should(Equal(io.EOF))
+			ok, err = r.Result(&s)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ok).Should(BeTrue())
+			Ω(s.Imported).Should(Equal(2))
+			Ω(r.Recv(&p)).Should(Equal(io.EOF))
+		})
+	})
+
+	Context("with server-sent events", func() {
+		It("reads the events until the stream ends", func() {
+			body := "data: {\"done\":1}\n\n: comment\ndata: {\"done\":2}\n\n"
+			r := client.NewSSEResultStreamReader(ioutil.NopCloser(strings.NewReader(body)))
+			var p progress
+			Ω(r.Recv(&p)).ShouldNot(HaveOccurred())
+			Ω(p.Done).Should(Equal(1))
+			Ω(r.Recv(&p)).ShouldNot(HaveOccurred())
+			Ω(p.Done).Should(Equal(2))
+			Ω(r.Recv(&p)).Should(Equal(io.EOF))
+			ok, err := r.Result(&summary{})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ok).Should(BeFalse())
+		})
+	})
+})
@@ -19,6 +19,9 @@ const (
 	logContextKey
 	errKey
 	securityScopesKey
+	signatureKeyProviderKey
+	fileCachingKey
+	securityScopeExprKey
 )
 
 type (
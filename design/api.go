@@ -63,6 +63,54 @@ const (
 	HTTPVersionNotSupported = "HTTPVersionNotSupported"
 )
 
+// List of async action operations, see ActionDefinition.AsyncOperation.
+const (
+	// PublishOperation is the operation of actions that publish messages to a channel.
+	PublishOperation = "publish"
+	// SubscribeOperation is the operation of actions that consume messages from a channel.
+	SubscribeOperation = "subscribe"
+)
+
+// List of transports used to stream action results, see ActionDefinition.StreamTransport.
+const (
+	// WebSocketStream sends each result in a websocket frame.
+	WebSocketStream = "websocket"
+	// NDJSONStream sends each result as a line of a newline delimited JSON response body.
+	NDJSONStream = "ndjson"
+	// SSEStream sends each result as a server-sent event.
+	SSEStream = "sse"
+)
+
+// List of trailing slash policies, see APIDefinition.TrailingSlash.
+const (
+	// TrailingSlashRedirect removes the trailing slashes of the route paths and redirects
+	// requests with a trailing slash with a 308 status.
+	TrailingSlashRedirect = "redirect"
+	// TrailingSlashStrict keeps the route paths as defined and responds with 404 to
+	// requests whose path only differs from a route path by a trailing slash.
+	TrailingSlashStrict = "strict"
+	// TrailingSlashIgnore removes the trailing slashes of the route paths and handles
+	// requests with a trailing slash as if they had none.
+	TrailingSlashIgnore = "ignore"
+)
+
+// ViewHeader is the name of the request header clients may use instead of the action ViewParam
+// query string parameter to select the view that renders the response.
+const ViewHeader = "View"
+
+// VersionPlaceholder is replaced with the major API version in the API base path, e.g. the base
+// path "/api/{version}" of an API with version "2.0" becomes "/api/v2".
+const VersionPlaceholder = "{version}"
+
+const (
+	// NDJSONMIMEType is the MIME type of newline delimited JSON response bodies.
+	NDJSONMIMEType = "application/x-ndjson"
+	// SSEMIMEType is the MIME type of server-sent events response bodies.
+	SSEMIMEType = "text/event-stream"
+	// BinaryMIMEType is the default MIME type of binary response bodies.
+	BinaryMIMEType = "application/octet-stream"
+)
+
 var (
 	// Design being built by DSL.
 	Design *APIDefinition
@@ -79,6 +127,9 @@ var (
 	// WildcardRegex is the regular expression used to capture path parameters.
 	WildcardRegex = regexp.MustCompile(`/(?::|\*)([a-zA-Z0-9_]+)`)
 
+	// ServerVariableRegex is the regular expression used to capture server URL variables.
+	ServerVariableRegex = regexp.MustCompile(`\{([a-zA-Z0-9_]+)\}`)
+
 	// DefaultDecoders contains the decoding definitions used when no Consumes DSL is found.
 	DefaultDecoders []*EncodingDefinition
 
@@ -127,6 +178,48 @@ var (
 	// Gob by default.
 	GobContentTypes = []string{"application/gob", "application/x-gob"}
 
+	// SupportedSchemes lists the URL schemes that may be used by the API, resources and
+	// actions.
+	SupportedSchemes = []string{"http", "https", "ws", "wss", "grpc", "grpcs"}
+
+	// SupportedSignatureAlgorithms lists the algorithms that may be used to sign action
+	// requests.
+	SupportedSignatureAlgorithms = []string{"hmac-sha1", "hmac-sha256", "hmac-sha512"}
+
+	// SupportedCompressions lists the compression algorithms that may be enabled on
+	// resources.
+	SupportedCompressions = []string{"gzip", "deflate", "br"}
+
+	// HTTPVerbs lists the HTTP methods that may be used in action routes.
+	HTTPVerbs = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "TRACE", "CONNECT"}
+
+	// NotFoundMethods lists the HTTP methods handled by the resource not found actions.
+	NotFoundMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+	// NotFoundWildcard is the name of the catch-all wildcard of the not found routes. The
+	// corresponding parameter contains the unmatched part of the request path.
+	NotFoundWildcard = "goaNotFoundPath"
+
+	// DefaultRequestIDHeader is the name of the header that carries the request correlation
+	// ID unless overridden with RequestID.
+	DefaultRequestIDHeader = "X-Request-ID"
+
+	// TimeFormatRFC3339 encodes DateTime values as RFC 3339 strings.
+	TimeFormatRFC3339 = "rfc3339"
+
+	// TimeFormatUnix encodes DateTime values as integer numbers of seconds since the Unix epoch.
+	TimeFormatUnix = "unix"
+
+	// TimeFormatUnixMilli encodes DateTime values as integer numbers of milliseconds since the
+	// Unix epoch.
+	TimeFormatUnixMilli = "unix-ms"
+
+	// DurationFormatString encodes Duration values as Go duration strings, e.g. "1h30m".
+	DurationFormatString = "string"
+
+	// DurationFormatMilli encodes Duration values as integer numbers of milliseconds.
+	DurationFormatMilli = "ms"
+
 	// ErrorMediaIdentifier is the media type identifier used for error responses.
 	ErrorMediaIdentifier = "application/vnd.goa.error"
 
@@ -171,6 +264,11 @@ var (
 			Description: "a human-readable explanation specific to this occurrence of the problem.",
 			Example:     "Value of ID must be an integer",
 		},
+		"message_key": &AttributeDefinition{
+			Type:        String,
+			Description: "a stable identifier of the detail message that clients may use to look up translations.",
+			Example:     "invalid_attribute_type",
+		},
 		"meta": &AttributeDefinition{
 			Type: &Hash{
 				KeyType:  &AttributeDefinition{Type: String},
@@ -200,6 +298,14 @@ func init() {
 		{MIMETypes: GobContentTypes, PackagePath: goa, Function: "NewGobDecoder"},
 	}
 	errorMediaView.Parent = ErrorMedia
+
+	dslengine.RegisterMetadataKeys("swagger", "generate", "read-only", "write-only", "summary", "curl", "audience", "tag:", "extension:")
+	dslengine.RegisterMetadataKeys("struct", "field:name", "field:type", "tag:")
+	dslengine.RegisterMetadataKeys("goa", "timeout", "since")
+	dslengine.RegisterMetadataKeys("deploy", "image", "replicas")
+	dslengine.RegisterMetadataKeys("format", "time")
+	dslengine.RegisterMetadataKeys("http", "cacheable", "negotiate")
+	dslengine.RegisterMetadataKeys("style", "paths", "fields")
 }
 
 // CanonicalIdentifier returns the media type identifier sans suffix
@@ -36,6 +36,8 @@ import (
 //        Security("oauth2", func() {
 //            Scope("api:read")
 //        })
+//        ETag()                         // Compute ETag header from file modification time and size
+//        MaxAge(3600)                   // Set Cache-Control header to "max-age=3600"
 //    })
 func Files(path, filename string, dsls ...func()) {
 	if r, ok := resourceDefinition(); ok {
@@ -54,6 +56,19 @@ func Files(path, filename string, dsls ...func()) {
 	}
 }
 
+// ETag can be used in: Files
+//
+// ETag causes the file server responses to include an ETag header computed from the served file
+// modification time and size. Requests whose If-None-Match header matches the ETag receive a 304
+// Not Modified response.
+func ETag() {
+	if f, ok := dslengine.CurrentDefinition().(*design.FileServerDefinition); ok {
+		f.ETag = true
+	} else {
+		dslengine.IncompatibleDSL()
+	}
+}
+
 // Action used in: Resource
 //
 // Action implements the action definition DSL. Action definitions describe specific API endpoints
@@ -112,11 +127,313 @@ func Action(name string, dsl func()) {
 		if !dslengine.Execute(dsl, action) {
 			return
 		}
+		if action.PayloadBody != "" {
+			payloadBody(action)
+		}
 		r.Actions[name] = action
 	}
 }
 
-// Routing used in: Action
+// StaticEndpoint can be used in: Resource
+//
+// StaticEndpoint defines an action that returns a canned response. The generated code serves the
+// response directly: static actions are not part of the controller interface and do not need an
+// implementation, which is handy for version or info endpoints and for prototyping a design.
+// StaticEndpoint takes the action name, its route, the response status and body. The body must be
+// compatible with the type of the action response with the given status. The optional DSL uses
+// the same functions as Action and may define that response, e.g. to use a media type. By default
+// the response type is String for string bodies and Any otherwise. Static actions are documented
+// like other actions. Example:
+//
+//	Resource("meta", func() {
+//		StaticEndpoint("version", GET("/version"), http.StatusOK, map[string]interface{}{
+//			"version": "1.2.0",
+//		}, func() {
+//			Description("Returns the API version")
+//			Response(OK, VersionMedia)
+//		})
+//	})
+func StaticEndpoint(name string, route *design.RouteDefinition, status int, body interface{}, dsl ...func()) {
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments given to StaticEndpoint")
+		return
+	}
+	if route == nil {
+		return
+	}
+	Action(name, func() {
+		Routing(route)
+		if len(dsl) == 1 {
+			dsl[0]()
+		}
+		a, ok := actionDefinition()
+		if !ok {
+			return
+		}
+		a.Static = &design.StaticResponseDefinition{Status: status, Body: body}
+		resp := a.StaticResponse()
+		if resp == nil {
+			addStaticResponse(a, status, body)
+			return
+		}
+		// A declared response body type that is not a media type must not be rendered with
+		// the resource default "text/plain" media type.
+		if resp.Type != nil && !resp.Type.IsPrimitive() && resp.MediaType == "text/plain" {
+			if _, ok := resp.Type.(*design.MediaTypeDefinition); !ok {
+				resp.MediaType = "application/json"
+			}
+		}
+	})
+}
+
+// addStaticResponse adds the response returned by the static action a when the DSL does not
+// define it. The response uses the default response name for status if there is one.
+func addStaticResponse(a *design.ActionDefinition, status int, body interface{}) {
+	name := fmt.Sprintf("Status%d", status)
+	for n, r := range design.Design.DefaultResponses {
+		if r.Status == status {
+			name = n
+			break
+		}
+	}
+	resp := &design.ResponseDefinition{Name: name, Status: status, Parent: a}
+	switch body.(type) {
+	case nil:
+	case string:
+		resp.Type = design.String
+		resp.MediaType = "text/plain"
+	default:
+		resp.Type = design.Any
+		resp.MediaType = "application/json"
+	}
+	if a.Responses == nil {
+		a.Responses = make(map[string]*design.ResponseDefinition)
+	}
+	a.Responses[name] = resp
+}
+
+// Webhook can be used in: Resource
+//
+// Webhook describes a request sent by the API to a URL registered by a client, for example to
+// notify the client of an event. The DSL uses the same functions as Action: Description, Routing,
+// Payload, Signature and Response. The route path is appended to the URL given when sending the
+// request and cannot define wildcards. Webhook responses are acknowledgments and cannot define a
+// body. The Swagger specification lists the webhooks under the "x-webhooks" extension and the
+// generated client exposes a method that sends the request to a given URL. Example:
+//
+//	Resource("order", func() {
+//		Webhook("order.shipped", func() {
+//			Description("Notifies the client that an order shipped")
+//			Routing(POST("/"))
+//			Payload(ShipmentEvent)
+//			Signature(func() {
+//				Header("X-Signature")
+//				Algorithm("hmac-sha256")
+//			})
+//			Response(NoContent)
+//		})
+//	})
+func Webhook(name string, dsl func()) {
+	if r, ok := resourceDefinition(); ok {
+		if _, ok := r.Webhooks[name]; ok {
+			dslengine.ReportError("webhook %#v is defined twice", name)
+			return
+		}
+		webhook := &design.ActionDefinition{
+			Parent:   r,
+			Name:     name,
+			Webhook:  true,
+			Metadata: make(dslengine.MetadataDefinition),
+		}
+		if !dslengine.Execute(dsl, webhook) {
+			return
+		}
+		if r.Webhooks == nil {
+			r.Webhooks = make(map[string]*design.ActionDefinition)
+		}
+		r.Webhooks[name] = webhook
+	}
+}
+
+// Publish can be used in: Resource
+//
+// Publish describes messages published by the API to a message broker channel such as a Kafka
+// topic or a NATS subject. Publish actions have no HTTP mapping: the DSL sets the channel with
+// Channel and the message type with Message, HTTP specific DSLs such as Routing, Payload or
+// Response are reported as validation errors. The "app" generator produces the message types
+// and functions that encode and publish the messages through a Publisher, the "asyncapi"
+// generator produces the AsyncAPI document describing the channels. Example:
+//
+//	Resource("order", func() {
+//		Publish("created", func() {
+//			Description("Published when an order is created")
+//			Channel("orders.created")
+//			Message(OrderEvent)
+//		})
+//	})
+func Publish(name string, dsl func()) {
+	asyncAction(design.PublishOperation, name, dsl)
+}
+
+// Subscribe can be used in: Resource
+//
+// Subscribe describes messages consumed by the API from a message broker channel. The DSL is
+// identical to Publish. The "app" generator produces functions that decode and validate the
+// messages received through a Subscriber. Example:
+//
+//	Resource("order", func() {
+//		Subscribe("paid", func() {
+//			Channel("payments.completed")
+//			Message(func() {
+//				Attribute("order_id", String)
+//				Required("order_id")
+//			})
+//		})
+//	})
+func Subscribe(name string, dsl func()) {
+	asyncAction(design.SubscribeOperation, name, dsl)
+}
+
+func asyncAction(operation, name string, dsl func()) {
+	if r, ok := resourceDefinition(); ok {
+		if _, ok := r.AsyncActions[name]; ok {
+			dslengine.ReportError("async action %#v is defined twice", name)
+			return
+		}
+		action := &design.ActionDefinition{
+			Parent:         r,
+			Name:           name,
+			AsyncOperation: operation,
+			Metadata:       make(dslengine.MetadataDefinition),
+		}
+		if !dslengine.Execute(dsl, action) {
+			return
+		}
+		if r.AsyncActions == nil {
+			r.AsyncActions = make(map[string]*design.ActionDefinition)
+		}
+		r.AsyncActions[name] = action
+	}
+}
+
+// Channel can be used in: Publish, Subscribe
+//
+// Channel sets the name of the message broker channel the messages are published to or consumed
+// from, for example a Kafka topic or a NATS subject.
+func Channel(name string) {
+	if a, ok := actionDefinition(); ok {
+		if a.AsyncOperation == "" {
+			dslengine.ReportError("Channel can only be used in Publish or Subscribe")
+			return
+		}
+		a.Channel = name
+	}
+}
+
+// Message can be used in: Publish, Subscribe
+//
+// Message describes the messages published or consumed by an async action. The function accepts
+// the same arguments as Payload. Messages that use a type or a media type defined in the design
+// share the corresponding generated data structure with the HTTP actions. Examples:
+//
+//	Message(OrderEvent)		// Messages are described by the OrderEvent type
+//
+//	Message(func() {		// Messages are objects described inline
+//		Attribute("order_id", String)
+//	})
+func Message(p interface{}, dsls ...func()) {
+	if len(dsls) > 1 {
+		dslengine.ReportError("too many arguments given to Message")
+		return
+	}
+	if a, ok := actionDefinition(); ok {
+		if a.AsyncOperation == "" {
+			dslengine.ReportError("Message can only be used in Publish or Subscribe")
+			return
+		}
+		if len(dsls) == 0 {
+			switch actual := p.(type) {
+			case *design.UserTypeDefinition:
+				a.Message = actual
+				return
+			case *design.MediaTypeDefinition:
+				a.Message = actual.UserTypeDefinition
+				return
+			}
+		}
+		att := payloadAttribute(a, p, dsls...)
+		if att == nil {
+			return
+		}
+		a.Message = &design.UserTypeDefinition{
+			AttributeDefinition: att,
+			TypeName:            fmt.Sprintf("%s%sMessage", camelize(a.Name), camelize(a.Parent.Name)),
+		}
+	}
+}
+
+// Idempotent can be used in: Action
+//
+// Idempotent marks the action as idempotent: sending the same request multiple times has the same
+// effect as sending it once. Actions whose routes all use the GET, HEAD, OPTIONS, PUT or DELETE
+// HTTP verbs are idempotent by default, Idempotent is meant for actions using other verbs. The
+// generated clients may coalesce concurrent identical requests made to idempotent actions.
+func Idempotent() {
+	if a, ok := actionDefinition(); ok {
+		a.Idempotent = true
+	}
+}
+
+// StreamingResult can be used in: Action
+//
+// StreamingResult defines the type of the results streamed by the action. The argument must be a
+// type or a media type. The results are sent as websocket frames if the action uses the ws or
+// wss scheme, as lines of a newline delimited JSON body if the action produces
+// "application/x-ndjson" or as server-sent events if the action produces "text/event-stream".
+// Example:
+//
+//	Action("import", func() {
+//		Routing(POST("/import"))
+//		Produces("application/x-ndjson")
+//		StreamingResult(Progress)	// Progress events streamed while the import runs
+//		Result(Summary)			// Final summary sent once the import completes
+//	})
+func StreamingResult(t interface{}) {
+	if a, ok := actionDefinition(); ok {
+		if ut := resultType("StreamingResult", t); ut != nil {
+			a.StreamingResult = ut
+		}
+	}
+}
+
+// Result can be used in: Action
+//
+// Result defines the type of the final result sent by an action that streams results once all
+// the streamed results have been sent. The argument must be a type or a media type. Result
+// requires a transport that can tag the final frame so server-sent events are not supported.
+// See StreamingResult for an example.
+func Result(t interface{}) {
+	if a, ok := actionDefinition(); ok {
+		if ut := resultType("Result", t); ut != nil {
+			a.Result = ut
+		}
+	}
+}
+
+// resultType returns the user type described by t, t must be a type or a media type.
+func resultType(dsl string, t interface{}) *design.UserTypeDefinition {
+	switch actual := t.(type) {
+	case *design.UserTypeDefinition:
+		return actual
+	case *design.MediaTypeDefinition:
+		return actual.UserTypeDefinition
+	default:
+		dslengine.ReportError("invalid %s argument, must be a type or a media type", dsl)
+		return nil
+	}
+}
+
+// Routing used in: Action, Webhook
 //
 // Routing lists the action route. Each route is defined with a function named after the HTTP method.
 // The route function takes the path as argument. Route paths may use wildcards as described in the
@@ -385,7 +702,176 @@ func Params(dsl func()) {
 	}
 }
 
-// Payload can be used in: Action
+// NoInheritedParams can be used in: Action
+//
+// NoInheritedParams removes the given path parameters inherited from the resource path from the
+// action routes. The resource path includes the API base path and the path of the canonical action
+// of the parent resource. The action must bind the parameters to query string parameters using
+// Params or to headers using Headers. A header binds a parameter when its name with dashes replaced
+// by underscores and the "X-" prefix removed matches the parameter name ignoring case.
+// Example:
+//
+//	Resource("bottle", func() {
+//		Parent("account")			// Canonical path of parent is "/accounts/:accountID"
+//		BasePath("/bottles")
+//		Action("search", func() {
+//			Routing(GET("/search"))		// Full path is "/accounts/bottles/search"
+//			NoInheritedParams("accountID")
+//			Headers(func() {
+//				Header("X-AccountID")	// Binds accountID
+//			})
+//		})
+//	})
+func NoInheritedParams(names ...string) {
+	if a, ok := actionDefinition(); ok {
+		a.NoInheritedParams = append(a.NoInheritedParams, names...)
+	}
+}
+
+// OmitParam can be used in: Action
+//
+// OmitParam removes the given parameters defined with Params in the action resource from the
+// action parameters. Path parameters used by the action routes cannot be omitted, see
+// NoInheritedParams. Example:
+//
+//	Resource("bottle", func() {
+//		Params(func() {
+//			Param("tenant")
+//			Required("tenant")
+//		})
+//		Action("health", func() {
+//			Routing(GET("/health"))
+//			OmitParam("tenant")	// Action does not take the "tenant" query string parameter
+//		})
+//	})
+func OmitParam(names ...string) {
+	if a, ok := actionDefinition(); ok {
+		a.OmittedParams = append(a.OmittedParams, names...)
+	}
+}
+
+// OmitHeader can be used in: Action
+//
+// OmitHeader removes the given headers defined with Headers in the action resource from the
+// action headers. Example:
+//
+//	Resource("bottle", func() {
+//		Headers(func() {
+//			Header("X-Tenant")
+//			Required("X-Tenant")
+//		})
+//		Action("health", func() {
+//			Routing(GET("/health"))
+//			OmitHeader("X-Tenant")	// Action does not require the X-Tenant header
+//		})
+//	})
+func OmitHeader(names ...string) {
+	if a, ok := actionDefinition(); ok {
+		a.OmittedHeaders = append(a.OmittedHeaders, names...)
+	}
+}
+
+// Timeout can be used in: Action
+//
+// Timeout sets the maximum duration allowed for handling requests made to the action. The value
+// must be a positive duration as accepted by time.ParseDuration. The generated code wraps the action
+// handler with a middleware that cancels the request context once the timeout expires. Example:
+//
+//	Action("export", func() {
+//		Routing(GET("/export"))
+//		Timeout("30s")	// Request context is canceled after 30 seconds
+//	})
+func Timeout(d string) {
+	if a, ok := actionDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.TimeoutMetadataKey] = []string{d}
+	}
+}
+
+// Since can be used in: Action
+//
+// Since records the API version that introduced the action. The version must be made of dot
+// separated numbers optionally prefixed with "v" and cannot be greater than the API version. The
+// Swagger generator mentions it in the operation description. Example:
+//
+//	Action("export", func() {
+//		Routing(GET("/export"))
+//		Since("1.2")	// Introduced in version 1.2 of the API
+//	})
+func Since(version string) {
+	if a, ok := actionDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.SinceMetadataKey] = []string{version}
+	}
+}
+
+// ViewParam can be used in: Action
+//
+// ViewParam lets clients select the view used to render the action response with the given query
+// string parameter or with the View request header. The response is the success response with the
+// lowest status whose media type defines views. The parameter value must be one of the view names,
+// it defaults to "default". Requests that specify another value are rejected with a validation error.
+// The generated context exposes the selected view and defines a response helper that renders the
+// response with it. Example:
+//
+//	Action("show", func() {
+//		Routing(GET("/:id"))
+//		ViewParam("view")	// GET /bottles/1?view=tiny renders the "tiny" view
+//		Response(OK, BottleMedia)
+//	})
+func ViewParam(name string) {
+	if a, ok := actionDefinition(); ok {
+		a.ViewParam = name
+	}
+}
+
+// Signature can be used in: Action, Webhook
+//
+// Signature defines how the action request bodies are signed. The DSL must set the name of the
+// header containing the signature with Header and the signature algorithm with Algorithm. The
+// supported algorithms are "hmac-sha1", "hmac-sha256" and "hmac-sha512", the signature is the hex
+// encoded HMAC of the request body.
+//
+// Generated clients compute the signature after encoding the body using the key returned by the
+// client SignatureKeyProvider. Generated servers verify the signature before decoding the body
+// using the key returned by the provider stored in the service context with
+// goa.WithSignatureKeyProvider. The action must define an Unauthorized or Forbidden response used
+// to report verification failures. Websocket actions cannot be signed. Example:
+//
+//	Action("deliver", func() {
+//		Routing(POST("/webhooks"))
+//		Payload(Delivery)
+//		Signature(func() {
+//			Header("X-Signature")
+//			Algorithm("hmac-sha256")
+//		})
+//		Response(NoContent)
+//		Response(Unauthorized)
+//	})
+func Signature(dsl func()) {
+	if a, ok := actionDefinition(); ok {
+		signature := &design.SignatureDefinition{Parent: a}
+		if !dslengine.Execute(dsl, signature) {
+			return
+		}
+		a.Signature = signature
+	}
+}
+
+// Algorithm can be used in: Signature
+//
+// Algorithm sets the name of the algorithm used to compute the request signature.
+func Algorithm(name string) {
+	if s, ok := signatureDefinition(); ok {
+		s.Algorithm = name
+	}
+}
+
+// Payload can be used in: Action, Webhook
 //
 // Payload implements the action payload DSL. An action payload describes the HTTP request body
 // data structure. The function accepts either a type or a DSL that describes the payload members
@@ -424,48 +910,18 @@ func payload(isOptional bool, p interface{}, dsls ...func()) {
 		return
 	}
 	if a, ok := actionDefinition(); ok {
-		var att *design.AttributeDefinition
-		var dsl func()
-		switch actual := p.(type) {
-		case func():
-			dsl = actual
-			att = newAttribute(a.Parent.MediaType)
-			att.Type = design.Object{}
-		case *design.AttributeDefinition:
-			att = design.DupAtt(actual)
-		case *design.UserTypeDefinition:
-			if len(dsls) == 0 {
-				a.Payload = actual
-				a.PayloadOptional = isOptional
-				return
-			}
-			att = design.DupAtt(actual.Definition())
-		case *design.MediaTypeDefinition:
-			att = design.DupAtt(actual.AttributeDefinition)
-		case string:
-			ut, ok := design.Design.Types[actual]
-			if !ok {
-				dslengine.ReportError("unknown payload type %s", actual)
-			}
-			att = design.DupAtt(ut.AttributeDefinition)
-		case *design.Array:
-			att = &design.AttributeDefinition{Type: actual}
-		case *design.Hash:
-			att = &design.AttributeDefinition{Type: actual}
-		case design.Primitive:
-			att = &design.AttributeDefinition{Type: actual}
-		default:
-			dslengine.ReportError("invalid Payload argument, must be a type, a media type or a DSL building a type")
+		if len(a.Payloads) > 0 {
+			dslengine.ReportError("Payload cannot be used together with ContentTypePayload")
 			return
 		}
-		if len(dsls) == 1 {
-			if dsl != nil {
-				dslengine.ReportError("invalid arguments in Payload call, must be (type), (dsl) or (type, dsl)")
-			}
-			dsl = dsls[0]
+		if ut, ok := p.(*design.UserTypeDefinition); ok && len(dsls) == 0 {
+			a.Payload = ut
+			a.PayloadOptional = isOptional
+			return
 		}
-		if dsl != nil {
-			dslengine.Execute(dsl, att)
+		att := payloadAttribute(a, p, dsls...)
+		if att == nil {
+			return
 		}
 		rn := camelize(a.Parent.Name)
 		an := camelize(a.Name)
@@ -477,6 +933,97 @@ func payload(isOptional bool, p interface{}, dsls ...func()) {
 	}
 }
 
+// ContentTypePayload can be used in: Action
+//
+// ContentTypePayload defines the request body accepted by the action when the request has the
+// given content type. The function may be called multiple times to accept request bodies with
+// different content types and shapes on the same action. The other arguments are identical to
+// the Payload DSL. Example:
+//
+//	Action("receive", func() {
+//		Routing(POST("/hooks"))
+//		ContentTypePayload("application/json", HookEvent)	// Structured event
+//		ContentTypePayload("text/plain", String)		// Raw signed payload
+//		Response(NoContent)
+//	})
+//
+// The action payload is then an object with one attribute per content type named after it
+// ("application_json" and "text_plain" in the example above). Only the attribute corresponding
+// to the request content type is set. Requests with any other content type are rejected.
+// String payloads with a "text" content type are loaded as is instead of being decoded.
+//
+// ContentTypePayload cannot be used together with Payload or MultipartForm.
+func ContentTypePayload(contentType string, p interface{}, dsls ...func()) {
+	if len(dsls) > 1 {
+		dslengine.ReportError("too many arguments given to ContentTypePayload")
+		return
+	}
+	if a, ok := actionDefinition(); ok {
+		if a.Payload != nil && len(a.Payloads) == 0 {
+			dslengine.ReportError("ContentTypePayload cannot be used together with Payload")
+			return
+		}
+		var att *design.AttributeDefinition
+		if ut, ok := p.(*design.UserTypeDefinition); ok && len(dsls) == 0 {
+			att = &design.AttributeDefinition{Type: ut}
+		} else if att = payloadAttribute(a, p, dsls...); att == nil {
+			return
+		}
+		pd := &design.PayloadDefinition{ContentType: contentType, Attribute: att, Parent: a}
+		if a.Payload == nil {
+			a.Payload = &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+				TypeName:            fmt.Sprintf("%s%sPayload", camelize(a.Name), camelize(a.Parent.Name)),
+			}
+		}
+		a.Payload.Type.(design.Object)[pd.AttributeName()] = att
+		a.Payloads = append(a.Payloads, pd)
+	}
+}
+
+// payloadAttribute builds the attribute describing the payload given to the Payload DSL.
+func payloadAttribute(a *design.ActionDefinition, p interface{}, dsls ...func()) *design.AttributeDefinition {
+	var att *design.AttributeDefinition
+	var dsl func()
+	switch actual := p.(type) {
+	case func():
+		dsl = actual
+		att = newAttribute(a.Parent.MediaType)
+		att.Type = design.Object{}
+	case *design.AttributeDefinition:
+		att = design.DupAtt(actual)
+	case *design.UserTypeDefinition:
+		att = design.DupAtt(actual.Definition())
+	case *design.MediaTypeDefinition:
+		att = design.DupAtt(actual.AttributeDefinition)
+	case string:
+		ut, ok := design.Design.Types[actual]
+		if !ok {
+			dslengine.ReportError("unknown payload type %s", actual)
+		}
+		att = design.DupAtt(ut.AttributeDefinition)
+	case *design.Array:
+		att = &design.AttributeDefinition{Type: actual}
+	case *design.Hash:
+		att = &design.AttributeDefinition{Type: actual}
+	case design.Primitive:
+		att = &design.AttributeDefinition{Type: actual}
+	default:
+		dslengine.ReportError("invalid Payload argument, must be a type, a media type or a DSL building a type")
+		return nil
+	}
+	if len(dsls) == 1 {
+		if dsl != nil {
+			dslengine.ReportError("invalid arguments in Payload call, must be (type), (dsl) or (type, dsl)")
+		}
+		dsl = dsls[0]
+	}
+	if dsl != nil {
+		dslengine.Execute(dsl, att)
+	}
+	return att
+}
+
 // MultipartForm can be used in: Action
 //
 // MultipartForm implements the action multipart form DSL. An action multipart form indicates that
@@ -489,6 +1036,100 @@ func MultipartForm() {
 	}
 }
 
+// Body can be used in: Action, Response
+//
+// Body uses a single attribute of the action payload or of the response media type as the
+// whole request or response body. The attribute may be of any type including arrays, hashes and
+// primitive types. Example:
+//
+//	Action("update", func() {
+//		Routing(PUT("/:id"))
+//		Payload(UpdatePayload)	// UpdatePayload defines the "data" attribute
+//		Body("data")		// Request body is the "data" attribute value
+//		Response(OK, func() {
+//			Media(PageMedia)	// PageMedia defines the "items" attribute
+//			Body("items")		// Response body is the "items" attribute value
+//		})
+//	})
+//
+// Primitive bodies are written as plain text when the content type is textual, e.g.
+// "text/plain", and encoded otherwise. Bytes bodies are read and written as is when the action
+// consumes (see Consumes) or the response has (see ContentType) a MIME type that the API does not
+// decode or encode, e.g. "application/octet-stream" or "image/png":
+//
+//	Action("upload", func() {
+//		Routing(PUT("/:id/image"))
+//		Consumes("image/png", "image/jpeg")
+//		Payload(func() {
+//			Member("data", Bytes, func() {
+//				MaxLength(1 << 20)	// Request bodies bigger than 1MB are rejected
+//			})
+//		})
+//		Body("data")
+//		Response(NoContent)
+//	})
+func Body(name string) {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		def.PayloadBody = name
+	case *design.ResponseDefinition:
+		def.BodyAttribute = name
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// payloadBody sets the action payload to the type of the payload attribute given to Body.
+func payloadBody(a *design.ActionDefinition) {
+	if a.Payload == nil || !a.Payload.IsObject() {
+		dslengine.ReportError("Body requires the action payload to be an object")
+		return
+	}
+	att, ok := a.Payload.ToObject()[a.PayloadBody]
+	if !ok {
+		dslengine.ReportError("unknown payload attribute %#v", a.PayloadBody)
+		return
+	}
+	optional := a.PayloadOptional || !a.Payload.IsRequired(a.PayloadBody)
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		a.Payload = actual
+	case *design.MediaTypeDefinition:
+		a.Payload = actual.UserTypeDefinition
+	default:
+		a.Payload = &design.UserTypeDefinition{
+			AttributeDefinition: design.DupAtt(att),
+			TypeName:            fmt.Sprintf("%s%sPayload", camelize(a.Name), camelize(a.Parent.Name)),
+		}
+	}
+	a.PayloadOptional = optional
+}
+
+// responseBody sets the response type to the type of the media type attribute given to Body.
+func responseBody(r *design.ResponseDefinition) {
+	t := r.Type
+	if t == nil {
+		if mt := design.Design.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+			t = mt
+		}
+	}
+	if t == nil || !t.IsObject() {
+		dslengine.ReportError("Body requires the response media type to be an object")
+		return
+	}
+	att, ok := t.ToObject()[r.BodyAttribute]
+	if !ok {
+		dslengine.ReportError("unknown response attribute %#v", r.BodyAttribute)
+		return
+	}
+	r.Type = att.Type
+	r.MediaType = ""
+	r.ViewName = ""
+	if mt, ok := att.Type.(*design.MediaTypeDefinition); ok {
+		r.MediaType = mt.Identifier
+	}
+}
+
 // newAttribute creates a new attribute definition using the media type with the given identifier
 // as base type.
 func newAttribute(baseMT string) *design.AttributeDefinition {
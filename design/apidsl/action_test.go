@@ -2,6 +2,7 @@ package apidsl_test
 
 import (
 	"strconv"
+	"time"
 
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
@@ -187,6 +188,226 @@ var _ = Describe("Action", func() {
 		})
 	})
 
+	Context("with typed and array headers", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				Headers(func() {
+					Header("Retry-After", Integer)
+					Header("If-Modified-Since", DateTime)
+					Header("X-Ids", ArrayOf(Integer))
+				})
+			}
+		})
+
+		It("produces a valid action", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Validate()).ShouldNot(HaveOccurred())
+			Ω(action.Headers.Type.(Object)).Should(HaveLen(3))
+		})
+	})
+
+	Context("with an object header", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				Headers(func() {
+					Header("X-Obj", func() {
+						Attribute("foo")
+					})
+				})
+			}
+		})
+
+		It("produces an invalid action", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("Header X-Obj has an invalid type, headers must be primitives or arrays of primitives"))
+		})
+	})
+
+	Context("with a timeout", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				Timeout("30s")
+			}
+		})
+
+		It("stores the timeout", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Metadata[TimeoutMetadataKey]).Should(Equal([]string{"30s"}))
+			timeout, ok := action.Timeout()
+			Ω(ok).Should(BeTrue())
+			Ω(timeout).Should(Equal(30 * time.Second))
+		})
+	})
+
+	Context("with an invalid timeout", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				Timeout("soon")
+			}
+		})
+
+		It("produces an invalid action", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid timeout "soon"`))
+		})
+	})
+
+	Context("with a negative timeout", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/:id"))
+				Timeout("-1s")
+			}
+		})
+
+		It("produces an invalid action", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("timeout must be positive"))
+		})
+	})
+
+	Context("with a view param", func() {
+		var bottle *MediaTypeDefinition
+
+		BeforeEach(func() {
+			name = "foo"
+			bottle = MediaType("application/vnd.bottle", func() {
+				Attributes(func() {
+					Attribute("id", Integer)
+				})
+				View("default", func() {
+					Attribute("id")
+				})
+				View("tiny", func() {
+					Attribute("id")
+				})
+			})
+			dsl = func() {
+				Routing(GET("/:id"))
+				ViewParam("view")
+				Response(OK, bottle)
+			}
+		})
+
+		It("adds the parameter that selects the view", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.ViewNames()).Should(Equal([]string{"default", "tiny"}))
+			view := action.QueryParams.Type.ToObject()["view"]
+			Ω(view).ShouldNot(BeNil())
+			Ω(view.Type).Should(Equal(String))
+			Ω(view.DefaultValue).Should(Equal("default"))
+			Ω(view.Validation.Values).Should(Equal([]interface{}{"default", "tiny"}))
+		})
+
+		Context("and no response with views", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					ViewParam("view")
+					Response(NoContent)
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`ViewParam "view" requires a success response whose media type defines views`))
+			})
+		})
+
+		Context("and a parameter with the same name", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("view")
+					})
+					ViewParam("view")
+					Response(OK, bottle)
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`ViewParam "view" conflicts with the parameter with the same name`))
+			})
+		})
+	})
+
+	Context("with a signature", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(POST("/"))
+				Signature(func() {
+					Header("X-Signature")
+					Algorithm("hmac-sha256")
+				})
+				Response(NoContent)
+				Response(Unauthorized)
+			}
+		})
+
+		It("stores the signature", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Signature).ShouldNot(BeNil())
+			Ω(action.Signature.Header).Should(Equal("X-Signature"))
+			Ω(action.Signature.Algorithm).Should(Equal("hmac-sha256"))
+			Ω(action.SignatureErrorStatus()).Should(Equal(401))
+		})
+	})
+
+	Context("with a signature and no error response", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(POST("/"))
+				Signature(func() {
+					Header("X-Signature")
+					Algorithm("hmac-md5")
+				})
+			}
+		})
+
+		It("produces an invalid action", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unsupported signature algorithm "hmac-md5"`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("action must define an Unauthorized or Forbidden response"))
+		})
+	})
+
+	Context("with a signed websocket action", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/"))
+				Scheme("ws")
+				Signature(func() {
+					Header("X-Signature")
+					Algorithm("hmac-sha256")
+				})
+				Response(Forbidden)
+			}
+		})
+
+		It("produces an invalid action", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("websocket actions cannot be signed"))
+		})
+	})
+
 	Context("using a response with a media type modifier", func() {
 		const mtID = "application/vnd.app.foo+json"
 
@@ -364,3 +585,502 @@ var _ = Describe("Payload", func() {
 	})
 
 })
+
+var _ = Describe("ContentTypePayload", func() {
+	var contentType string
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		contentType = "text/plain"
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		Resource("foo", func() {
+			Action("bar", func() {
+				Routing(POST(""))
+				ContentTypePayload("application/json", func() {
+					Member("name")
+					Required("name")
+				})
+				ContentTypePayload(contentType, String)
+				if dsl != nil {
+					dsl()
+				}
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the payloads and the payload type", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		a := Design.Resources["foo"].Actions["bar"]
+		Ω(a.Payloads).Should(HaveLen(2))
+		Ω(a.Payloads[0].ContentType).Should(Equal("application/json"))
+		Ω(a.Payloads[0].Attribute.Type.IsObject()).Should(BeTrue())
+		Ω(a.Payloads[1].ContentType).Should(Equal("text/plain"))
+		Ω(a.Payloads[1].Attribute.Type).Should(Equal(String))
+		Ω(a.Payloads[1].Raw()).Should(BeTrue())
+		Ω(a.Payload).ShouldNot(BeNil())
+		Ω(a.Payload.TypeName).Should(Equal("BarFooPayload"))
+		o := a.Payload.Type.ToObject()
+		Ω(o).Should(HaveLen(2))
+		Ω(o).Should(HaveKey("application_json"))
+		Ω(o).Should(HaveKey("text_plain"))
+		Ω(a.Payload.IsRequired("application_json")).Should(BeFalse())
+	})
+
+	Context("with overlapping content types", func() {
+		BeforeEach(func() {
+			contentType = "Application/JSON; charset=utf-8"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`content type overlaps with "application/json"`))
+		})
+	})
+
+	Context("with a wildcard content type", func() {
+		BeforeEach(func() {
+			contentType = "text/*"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("content type cannot contain wildcards"))
+		})
+	})
+
+	Context("used together with Payload", func() {
+		BeforeEach(func() {
+			dsl = func() { Payload(String) }
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("Payload cannot be used together with ContentTypePayload"))
+		})
+	})
+})
+
+var _ = Describe("Body", func() {
+	var body string
+	var resp func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		body = ""
+		resp = nil
+	})
+
+	JustBeforeEach(func() {
+		page := MediaType("application/vnd.page", func() {
+			Attributes(func() {
+				Attribute("items", ArrayOf(String))
+				Attribute("count", Integer)
+			})
+			View("default", func() {
+				Attribute("items")
+				Attribute("count")
+			})
+		})
+		Resource("foo", func() {
+			Action("bar", func() {
+				Routing(PUT("/:id"))
+				Payload(func() {
+					Member("data", func() {
+						Member("name")
+					})
+					Member("tags", ArrayOf(String))
+					Member("labels", HashOf(String, String))
+					Member("count", Integer)
+					Required("data")
+				})
+				if body != "" {
+					Body(body)
+				}
+				Response(OK, func() {
+					Media(page)
+					if resp != nil {
+						resp()
+					}
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with an object attribute", func() {
+		BeforeEach(func() {
+			body = "data"
+		})
+
+		It("uses the attribute as the request body", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			a := Design.Resources["foo"].Actions["bar"]
+			Ω(a.PayloadBody).Should(Equal("data"))
+			Ω(a.Payload.TypeName).Should(Equal("BarFooPayload"))
+			Ω(a.Payload.Type.ToObject()).Should(HaveKey("name"))
+			Ω(a.PayloadOptional).Should(BeFalse())
+		})
+	})
+
+	Context("with an array attribute", func() {
+		BeforeEach(func() {
+			body = "tags"
+		})
+
+		It("uses the attribute as the request body", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			a := Design.Resources["foo"].Actions["bar"]
+			Ω(a.Payload.IsArray()).Should(BeTrue())
+			Ω(a.PayloadOptional).Should(BeTrue())
+		})
+	})
+
+	Context("with a hash attribute", func() {
+		BeforeEach(func() {
+			body = "labels"
+		})
+
+		It("uses the attribute as the request body", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["foo"].Actions["bar"].Payload.IsHash()).Should(BeTrue())
+		})
+	})
+
+	Context("with a primitive attribute", func() {
+		BeforeEach(func() {
+			body = "count"
+		})
+
+		It("uses the attribute as the request body", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			a := Design.Resources["foo"].Actions["bar"]
+			Ω(a.Payload.Type).Should(Equal(Integer))
+			Ω(IsText(a.Payload)).Should(BeTrue())
+		})
+	})
+
+	Context("with an unknown attribute", func() {
+		BeforeEach(func() {
+			body = "unknown"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unknown payload attribute "unknown"`))
+		})
+	})
+
+	Context("in a response", func() {
+		BeforeEach(func() {
+			resp = func() { Body("items") }
+		})
+
+		It("uses the media type attribute as the response body", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			r := Design.Resources["foo"].Actions["bar"].Responses["OK"]
+			Ω(r.BodyAttribute).Should(Equal("items"))
+			Ω(r.Type.IsArray()).Should(BeTrue())
+			Ω(r.MediaType).Should(BeEmpty())
+		})
+	})
+
+	Context("in a response with an unknown attribute", func() {
+		BeforeEach(func() {
+			resp = func() { Body("unknown") }
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unknown response attribute "unknown"`))
+		})
+	})
+})
+
+var _ = Describe("StreamingResult", func() {
+	var dsl func()
+	var action *ActionDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		progress := Type("Progress", func() {
+			Attribute("done", Integer)
+		})
+		summary := Type("Summary", func() {
+			Attribute("imported", Integer)
+		})
+		Resource("task", func() {
+			Action("import", func() {
+				Routing(POST("/import"))
+				StreamingResult(progress)
+				Result(summary)
+				if dsl != nil {
+					dsl()
+				}
+			})
+		})
+		dslengine.Run()
+		action = Design.Resources["task"].Actions["import"]
+	})
+
+	Context("with a newline delimited JSON response", func() {
+		BeforeEach(func() {
+			dsl = func() { Produces("application/x-ndjson") }
+		})
+
+		It("streams the results followed by the final result", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.StreamingResult.TypeName).Should(Equal("Progress"))
+			Ω(action.Result.TypeName).Should(Equal("Summary"))
+			Ω(action.Streaming).Should(BeTrue())
+			Ω(action.StreamTransport()).Should(Equal(NDJSONStream))
+		})
+	})
+
+	Context("with a websocket", func() {
+		BeforeEach(func() {
+			dsl = func() { Scheme("ws") }
+		})
+
+		It("streams the results over the websocket", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.StreamTransport()).Should(Equal(WebSocketStream))
+		})
+	})
+
+	Context("with server-sent events", func() {
+		BeforeEach(func() {
+			dsl = func() { Produces("text/event-stream") }
+		})
+
+		It("rejects the final result", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("final result cannot be sent with server-sent events"))
+		})
+	})
+
+	Context("with no streaming transport", func() {
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("actions that stream results must use the ws or wss scheme or produce application/x-ndjson or text/event-stream"))
+		})
+	})
+})
+
+var _ = Describe("StaticEndpoint", func() {
+	var body interface{}
+	var dsl func()
+
+	var action *ActionDefinition
+	var versionType *UserTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		body = map[string]interface{}{"version": "1.2.0"}
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {})
+		versionType = Type("Version", func() {
+			Attribute("version", String)
+			Required("version")
+		})
+		Resource("meta", func() {
+			if dsl != nil {
+				StaticEndpoint("version", GET("/version"), 200, body, dsl)
+			} else {
+				StaticEndpoint("version", GET("/version"), 200, body)
+			}
+		})
+		dslengine.Run()
+		action = Design.Resources["meta"].Actions["version"]
+	})
+
+	It("defines a static action", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(action).ShouldNot(BeNil())
+		Ω(action.Static).ShouldNot(BeNil())
+		Ω(action.Static.Status).Should(Equal(200))
+		Ω(action.Static.Body).Should(Equal(body))
+		Ω(action.Routes).Should(HaveLen(1))
+	})
+
+	It("adds a JSON response for the static status", func() {
+		resp := action.StaticResponse()
+		Ω(resp).ShouldNot(BeNil())
+		Ω(resp.Name).Should(Equal(OK))
+		Ω(resp.MediaType).Should(Equal("application/json"))
+		Ω(resp.BodyExample()).Should(Equal(body))
+	})
+
+	Context("with a string body", func() {
+		BeforeEach(func() {
+			body = "1.2.0"
+		})
+
+		It("adds a text response", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			resp := action.StaticResponse()
+			Ω(resp.Type).Should(Equal(String))
+			Ω(resp.MediaType).Should(Equal("text/plain"))
+		})
+	})
+
+	Context("with a declared response type", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Description("Returns the API version")
+				Response(OK, versionType)
+			}
+		})
+
+		It("uses the declared response", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action.Description).Should(Equal("Returns the API version"))
+			resp := action.StaticResponse()
+			Ω(resp.Type).Should(Equal(versionType))
+			Ω(resp.MediaType).Should(Equal("application/json"))
+		})
+
+		Context("and an incompatible body", func() {
+			BeforeEach(func() {
+				body = map[string]interface{}{"build": 42}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid static response body: body: missing required attribute "version"`))
+			})
+		})
+	})
+
+	Context("with a payload", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Payload(String)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("static actions cannot define a payload"))
+		})
+	})
+})
+
+var _ = Describe("Link", func() {
+	var target string
+	var action *ActionDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		target = "show"
+	})
+
+	JustBeforeEach(func() {
+		Resource("account", func() {
+			Action("show", func() {
+				Routing(GET("/accounts/:accountID"))
+			})
+		})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/bottles/:bottleID"))
+				Link("owner", "account", target)
+			})
+		})
+		dslengine.Run()
+		action = Design.Resources["bottle"].Actions["show"]
+	})
+
+	It("records the link to the target action", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(action.Links).Should(HaveLen(1))
+		link := action.Links[0]
+		Ω(link.Rel).Should(Equal("owner"))
+		Ω(link.Target()).Should(Equal(Design.Resources["account"].Actions["show"]))
+		Ω(link.URITemplate()).Should(Equal("/accounts/:accountID"))
+	})
+
+	Context("referencing a missing target", func() {
+		BeforeEach(func() {
+			target = "get"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`link targets unknown action "get" of resource "account"`))
+		})
+	})
+})
+
+var _ = Describe("Since", func() {
+	var since string
+	var action *ActionDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		since = "1.2"
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			Version("v2.0")
+		})
+		Resource("bottle", func() {
+			Action("export", func() {
+				Routing(GET("/export"))
+				Since(since)
+			})
+		})
+		dslengine.Run()
+		action = Design.Resources["bottle"].Actions["export"]
+	})
+
+	It("records the version in the action metadata", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(action.Metadata[SinceMetadataKey]).Should(Equal([]string{"1.2"}))
+		Ω(action.Since()).Should(Equal("1.2"))
+	})
+
+	Context("with the API version", func() {
+		BeforeEach(func() {
+			since = "2"
+		})
+
+		It("is valid", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a future version", func() {
+		BeforeEach(func() {
+			since = "2.1"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`action is introduced in version "2.1" which is greater than the API version "v2.0"`))
+		})
+	})
+
+	Context("with an invalid version", func() {
+		BeforeEach(func() {
+			since = "next"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid version "next"`))
+		})
+	})
+})
@@ -34,6 +34,9 @@ import (
 //			URL("doc URL")
 //		})
 //		Host("goa.design")			// API hostname
+//		Server("https://staging.goa.design", func() { // Server listed in docs
+//			Label("staging")			// Environment label, defaults to the host
+//		})
 //		Scheme("http")
 //		BasePath("/base/:param")		// Common base path to all API actions
 //		Params(func() {				// Common parameters to all API actions
@@ -99,10 +102,20 @@ func Version(ver string) {
 	}
 }
 
-// Description can be used in: API, Resource, Action, MediaType, Attribute, Response or ResponseTemplate
+// Description can be used in: API, Resource, Action, Mount, MediaType, Attribute, Response or ResponseTemplate
+//
+// Description sets the definition description. Multi-line descriptions are normalized so that
+// they may be written with Go raw strings indented like the surrounding code: the leading and
+// trailing blank lines are removed as well as the whitespace prefix common to all the other lines.
+// Single line descriptions are used as is.
+//
+//	Description(`
+//		Lists the bottles.
 //
-// Description sets the definition description.
+//		The bottles are sorted by name.
+//	`)
 func Description(d string) {
+	d = normalizeDescription(d)
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition:
 		def.Description = d
@@ -110,6 +123,8 @@ func Description(d string) {
 		def.Description = d
 	case *design.FileServerDefinition:
 		def.Description = d
+	case *design.MountDefinition:
+		def.Description = d
 	case *design.ActionDefinition:
 		def.Description = d
 	case *design.MediaTypeDefinition:
@@ -122,16 +137,68 @@ func Description(d string) {
 		def.Description = d
 	case *design.SecuritySchemeDefinition:
 		def.Description = d
+	case *design.ServerDefinition:
+		def.Description = d
+	case *design.ResponseExampleDefinition:
+		def.Description = d
 	default:
 		dslengine.IncompatibleDSL()
 	}
 }
 
-// BasePath can used in: API, Resource
+// normalizeDescription removes the leading and trailing blank lines of multi-line descriptions
+// and the whitespace prefix common to the remaining non-blank lines. Blank lines are emptied.
+func normalizeDescription(d string) string {
+	if !strings.Contains(d, "\n") {
+		return d
+	}
+	lines := strings.Split(d, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	var prefix string
+	first := true
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		indent := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		i := 0
+		for i < len(prefix) && i < len(indent) && prefix[i] == indent[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	for i, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = strings.TrimPrefix(l, prefix)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BasePath can used in: API, Resource, Mount
 //
 // BasePath defines the API base path, i.e. the common path prefix to all the API actions.
 // The path may define wildcards (see Routing for a description of the wildcard syntax).
-// The corresponding parameters must be described using Params.
+// The corresponding parameters must be described using Params. The API base path may also use
+// the {version} placeholder which is replaced with the major API version prefixed with "v":
+//
+//	API("cellar", func() {
+//		Version("2.0")
+//		BasePath("/api/{version}") // "/api/v2"
+//	})
+//
+// The base path of a Mount replaces the API base path in the routes served through the mount.
 func BasePath(val string) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition:
@@ -149,8 +216,39 @@ func BasePath(val string) {
 				}
 			}
 		}
+	case *design.MountDefinition:
+		def.BasePath = val
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// ResponseHeaders can be used in: API, Resource
+//
+// ResponseHeaders lists constant headers sent with all the responses, including the error
+// responses and the files served by the file servers. Each header must define its value with
+// Default. The resource response headers override the API response headers with the same names.
+// Example:
+//
+//	ResponseHeaders(func() {
+//		Header("X-Content-Type-Options", String, func() {
+//			Default("nosniff")
+//		})
+//	})
+func ResponseHeaders(dsl func()) {
+	var headers **design.AttributeDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		headers = &def.ResponseHeaders
+	case *design.ResourceDefinition:
+		headers = &def.ResponseHeaders
 	default:
 		dslengine.IncompatibleDSL()
+		return
+	}
+	h := &design.AttributeDefinition{Type: design.Object{}}
+	if dslengine.Execute(dsl, h) {
+		*headers = (*headers).Merge(h)
 	}
 }
 
@@ -172,16 +270,70 @@ func BasePath(val string) {
 //
 //        Origin("/(api|swagger)[.]goa[.]design/", func() {}) // Define CORS policy with a regular expression
 func Origin(origin string, dsl func()) {
-	cors := &design.CORSDefinition{Origin: origin}
+	cors := newCORS(origin)
+	if !dslengine.Execute(dsl, cors) {
+		return
+	}
+	addOrigin(origin, cors)
+}
+
+// CORS can be used in: Resource, API
+//
+// CORS defines a single CORS policy shared by a list of origins. It is equivalent to using the
+// Origin DSL with the same policy for each origin listed with Origins. Resources that do not
+// define a policy for a given origin inherit the API policy.
+// Example:
+//
+//        CORS(func() {
+//                Origins("https://goa.design", "https://*.goa.design") // One or more origins
+//                Methods("GET", "POST")                                // One or more authorized HTTP methods
+//                Headers("X-Shared-Secret")                            // One or more authorized headers
+//                MaxAge(600)                                           // How long to cache a preflight request response
+//                Credentials()                                         // Sets Access-Control-Allow-Credentials header
+//        })
+func CORS(dsl func()) {
+	policy := &design.CORSDefinition{}
+	if !dslengine.Execute(dsl, policy) {
+		return
+	}
+	if len(policy.Origins) == 0 {
+		dslengine.ReportError("CORS must list at least one origin with Origins")
+		return
+	}
+	for _, origin := range policy.Origins {
+		cors := newCORS(origin)
+		cors.Headers = policy.Headers
+		cors.Methods = policy.Methods
+		cors.Exposed = policy.Exposed
+		cors.MaxAge = policy.MaxAge
+		cors.Credentials = policy.Credentials
+		addOrigin(origin, cors)
+	}
+}
+
+// Origins can be used in: CORS
+//
+// Origins lists the origins the CORS policy applies to. Origins may use the same wildcard and
+// regular expression syntax as the Origin DSL.
+func Origins(vals ...string) {
+	if cors, ok := corsDefinition(); ok {
+		cors.Origins = append(cors.Origins, vals...)
+	}
+}
 
+// newCORS initializes the CORS policy for the given origin, origins wrapped into "/" are
+// regular expressions.
+func newCORS(origin string) *design.CORSDefinition {
+	cors := &design.CORSDefinition{Origin: origin}
 	if strings.HasPrefix(origin, "/") && strings.HasSuffix(origin, "/") {
 		cors.Regexp = true
 		cors.Origin = strings.Trim(origin, "/")
 	}
+	return cors
+}
 
-	if !dslengine.Execute(dsl, cors) {
-		return
-	}
+// addOrigin records the CORS policy in the current API or resource definition.
+func addOrigin(origin string, cors *design.CORSDefinition) {
 	var parent dslengine.Definition
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition:
@@ -203,7 +355,7 @@ func Origin(origin string, dsl func()) {
 	cors.Parent = parent
 }
 
-// Methods can be used in: Origin
+// Methods can be used in: Origin, CORS
 //
 // Methods sets the origin allowed methods.
 func Methods(vals ...string) {
@@ -212,7 +364,7 @@ func Methods(vals ...string) {
 	}
 }
 
-// Expose can be used in: Origin
+// Expose can be used in: Origin, CORS
 //
 // Expose sets the origin exposed headers.
 func Expose(vals ...string) {
@@ -221,16 +373,31 @@ func Expose(vals ...string) {
 	}
 }
 
-// MaxAge can be used in: Origin
+// MaxAge can be used in: Origin, CORS, Files
 //
-// MaxAge sets the cache expiry for preflight request responses.
-func MaxAge(val uint) {
-	if cors, ok := corsDefinition(); ok {
-		cors.MaxAge = val
+// MaxAge sets the cache expiry in seconds for preflight request responses when used in Origin.
+// When used in Files MaxAge sets the max-age directive of the Cache-Control header of the file
+// server responses:
+//
+//	Files("/assets/*filepath", "public/assets", func() {
+//		MaxAge(3600)
+//	})
+func MaxAge(val int) {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.CORSDefinition:
+		if val < 0 {
+			dslengine.ReportError("invalid max age %d, must be positive or zero", val)
+			return
+		}
+		def.MaxAge = uint(val)
+	case *design.FileServerDefinition:
+		def.MaxAge = &val
+	default:
+		dslengine.IncompatibleDSL()
 	}
 }
 
-// Credentials can be used in: Origin
+// Credentials can be used in: Origin, CORS
 //
 // Credentials sets the allow credentials response header.
 func Credentials() {
@@ -271,8 +438,8 @@ func Host(host string) {
 func Scheme(vals ...string) {
 	ok := true
 	for _, v := range vals {
-		if v != "http" && v != "https" && v != "ws" && v != "wss" {
-			dslengine.ReportError(`invalid scheme "%s", must be one of "http", "https", "ws" or "wss"`, v)
+		if !design.IsSupportedScheme(v) {
+			dslengine.ReportError(`invalid scheme "%s", must be one of %s`, v, strings.Join(design.SupportedSchemes, ", "))
 			ok = false
 		}
 	}
@@ -292,6 +459,147 @@ func Scheme(vals ...string) {
 	}
 }
 
+// HealthCheck can be used in: API, Resource
+//
+// HealthCheck defines an endpoint that responds to GET requests made to the given path with a 200
+// status. The path is relative to the API or resource base path. The optional second argument
+// sets the response body, it defaults to "OK". The generated code mounts the endpoint
+// automatically, it does not require a controller action implementation. Example:
+//
+//	API("cellar", func() {
+//		BasePath("/api")
+//		HealthCheck("/health") // GET /api/health returns 200 "OK"
+//	})
+//
+//	Resource("bottle", func() {
+//		BasePath("/bottles")
+//		HealthCheck("/ping", "pong") // GET /api/bottles/ping returns 200 "pong"
+//	})
+func HealthCheck(path string, body ...string) {
+	if len(body) > 1 {
+		dslengine.ReportError("too many arguments given to HealthCheck")
+		return
+	}
+	check := &design.HealthCheckDefinition{Path: path, Body: "OK"}
+	if len(body) == 1 {
+		check.Body = body[0]
+	}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		check.Parent = def
+		def.HealthCheck = check
+	case *design.ResourceDefinition:
+		check.Parent = def
+		def.HealthCheck = check
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// ValidationErrorStatus can be used in: API
+//
+// ValidationErrorStatus sets the status code of the responses sent when a request fails validation,
+// for example because a required parameter is missing. The status must be a 4xx code, it defaults
+// to 400. Example:
+//
+//	API("cellar", func() {
+//		ValidationErrorStatus(422)
+//	})
+func ValidationErrorStatus(status int) {
+	if a, ok := apiDefinition(); ok {
+		a.ValidationErrorStatus = status
+	}
+}
+
+// RequestID can be used in: API
+//
+// RequestID sets the name of the header that carries the request correlation ID. The generated
+// service reads the ID from the header or generates one if the request has none and sends it back
+// in the same response header. The header defaults to "X-Request-ID". Example:
+//
+//	API("cellar", func() {
+//		RequestID("X-Correlation-ID")
+//	})
+func RequestID(header string) {
+	if a, ok := apiDefinition(); ok {
+		a.RequestIDHeader = header
+	}
+}
+
+// RequireDescriptions can be used in: API
+//
+// RequireDescriptions makes the design validation fail if an action has no description. It lets
+// CI pipelines enforce documentation completeness. Example:
+//
+//	API("cellar", func() {
+//		RequireDescriptions()
+//	})
+func RequireDescriptions() {
+	if a, ok := apiDefinition(); ok {
+		a.RequireDescriptions = true
+	}
+}
+
+// TrailingSlash can be used in: API
+//
+// TrailingSlash sets the policy used to handle the requests whose path differs from a route path
+// only by a trailing slash or by duplicate slashes. The policy is one of:
+//
+//   - TrailingSlashRedirect: trailing slashes are removed from the route paths and requests
+//     with a trailing slash are redirected with a 308 status.
+//   - TrailingSlashStrict: route paths are kept as defined and other requests get a 404
+//     response. Routes that only differ by a trailing slash are rejected.
+//   - TrailingSlashIgnore: trailing slashes are removed from the route paths and requests
+//     with a trailing slash are handled as if they had none.
+//
+// Without a policy the generated mux redirects the requests with a 301 status. Example:
+//
+//	API("cellar", func() {
+//		TrailingSlash(TrailingSlashIgnore)
+//	})
+func TrailingSlash(policy string) {
+	if a, ok := apiDefinition(); ok {
+		a.TrailingSlash = policy
+	}
+}
+
+// ResponseExample can be used in: API
+//
+// ResponseExample defines the example body of the API responses with the given status. The example
+// documents the responses of all the actions that do not define their own example with Example in
+// the Response DSL. Responses with no body have no example. The DSL may also set a description.
+// Example:
+//
+//	API("cellar", func() {
+//		ResponseExample(404, func() {
+//			Description("Bottle not found")
+//			Example(map[string]interface{}{
+//				"id":     "3F1FKVRR",
+//				"status": "404",
+//				"code":   "not_found",
+//				"detail": "bottle 42 not found",
+//			})
+//		})
+//	})
+func ResponseExample(status int, dsl func()) {
+	a, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	if _, ok := a.ResponseExamples[status]; ok {
+		dslengine.ReportError("response example for status %d is defined twice", status)
+		return
+	}
+	ex := &design.ResponseExampleDefinition{Status: status}
+	if !dslengine.Execute(dsl, ex) {
+		return
+	}
+	if a.ResponseExamples == nil {
+		a.ResponseExamples = make(map[int]*design.ResponseExampleDefinition)
+	}
+	a.ResponseExamples[status] = ex
+}
+
 // Contact can be used in: API
 //
 // Contact sets the API contact information.
@@ -338,15 +646,90 @@ func Docs(dsl func()) {
 	}
 }
 
-// Name can be used in: Contact, License.
+// Server can be used in: API
+//
+// Server adds a server hosting the API. The optional DSL may set a label used by documentation
+// generators to group the servers by environment and a description. The label defaults to the
+// host of the server URL. The DSL may also set a name used by generated clients to select the
+// server, names must be unique. Generated clients use the server marked with Default, or the first
+// server if none is, by default. Example:
+//
+//	API("cellar", func() {
+//		Server("https://api.example.com", func() {
+//			Name("production")
+//			Label("production")
+//			Default()
+//		})
+//		Server("https://eu.staging.example.com")
+//		Server("https://us.staging.example.com", func() {
+//			Label("staging")
+//			Description("US staging server")
+//		})
+//	})
 //
-// Name sets the contact or license name.
+// Servers keep the order in which they are first declared. Declaring a server with the URL of a
+// server already declared runs the DSL on the existing server rather than adding a new one. The
+// default ports are removed from the server URLs so that "https://example.com:443" and
+// "https://example.com" designate the same server.
+func Server(url string, dsl ...func()) {
+	a, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	server := a.ServerByURL(url)
+	if server == nil {
+		server = &design.ServerDefinition{URL: url}
+		a.Servers = append(a.Servers, server)
+	}
+	if len(dsl) > 0 {
+		dslengine.Execute(dsl[0], server)
+	}
+}
+
+// Variable can be used in: Server
+//
+// Variable defines a variable used in the server URL. Variables appear in the URL enclosed in
+// curly braces and must have a default value. Variable accepts the same arguments as Attribute.
+// Example:
+//
+//	Server("https://{region}.example.com/{version}", func() {
+//		Variable("region", String, "Deployment region", func() {
+//			Enum("us", "eu")
+//			Default("us")
+//		})
+//		Variable("version", func() {
+//			Default("v1")
+//		})
+//	})
+func Variable(name string, args ...interface{}) {
+	if s, ok := serverDefinition(); ok {
+		if s.Variables == nil {
+			s.Variables = &design.AttributeDefinition{Type: make(design.Object)}
+		}
+		dslengine.Execute(func() { Attribute(name, args...) }, s.Variables)
+	}
+}
+
+// Label can be used in: Server
+//
+// Label sets the name of the environment the server belongs to.
+func Label(label string) {
+	if s, ok := serverDefinition(); ok {
+		s.Label = label
+	}
+}
+
+// Name can be used in: Contact, License, Server.
+//
+// Name sets the contact, license or server name.
 func Name(name string) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.ContactDefinition:
 		def.Name = name
 	case *design.LicenseDefinition:
 		def.Name = name
+	case *design.ServerDefinition:
+		def.Name = name
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -377,32 +760,78 @@ func URL(url string) {
 	}
 }
 
-// Consumes can be used in: API
+// Consumes can be used in: API, Resource, Action
 //
 // Consumes adds a MIME type to the list of MIME types the APIs supports when accepting requests.
 // Consumes may also specify the path of the decoding package.
 // The package must expose a DecoderFactory method that returns an object which implements
 // goa.DecoderFactory.
+//
+// Used in a resource or action DSL, Consumes overrides the list of MIME types accepted by the
+// resource or action which otherwise inherits the API list. The MIME types should be consumed by
+// the API as the decoders are registered at the API level, a warning is reported otherwise.
+// Decoding packages can only be specified at the API level.
 func Consumes(args ...interface{}) {
-	if a, ok := apiDefinition(); ok {
-		if def := buildEncodingDefinition(false, args...); def != nil {
-			a.Consumes = append(a.Consumes, def)
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		if enc := buildEncodingDefinition(false, args...); enc != nil {
+			def.Consumes = append(def.Consumes, enc)
 		}
+	case *design.ResourceDefinition:
+		def.Consumes = append(def.Consumes, encodingMIMETypes(false, args...)...)
+	case *design.ActionDefinition:
+		def.Consumes = append(def.Consumes, encodingMIMETypes(false, args...)...)
+	default:
+		dslengine.IncompatibleDSL()
 	}
 }
 
-// Produces can be used in: API
+// Produces can be used in: API, Resource, Action
 //
 // Produces adds a MIME type to the list of MIME types the APIs can encode responses with.
 // Produces may also specify the path of the encoding package.
 // The package must expose a EncoderFactory method that returns an object which implements
 // goa.EncoderFactory.
+//
+// Used in a resource or action DSL, Produces overrides the list of MIME types produced by the
+// resource or action which otherwise inherits the API list. The MIME types should be produced by
+// the API as the encoders are registered at the API level, a warning is reported otherwise.
+// Encoding packages can only be specified at the API level.
 func Produces(args ...interface{}) {
-	if a, ok := apiDefinition(); ok {
-		if def := buildEncodingDefinition(true, args...); def != nil {
-			a.Produces = append(a.Produces, def)
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		if enc := buildEncodingDefinition(true, args...); enc != nil {
+			def.Produces = append(def.Produces, enc)
 		}
+	case *design.ResourceDefinition:
+		def.Produces = append(def.Produces, encodingMIMETypes(true, args...)...)
+	case *design.ActionDefinition:
+		def.Produces = append(def.Produces, encodingMIMETypes(true, args...)...)
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// encodingMIMETypes returns the MIME types listed in a resource or action Consumes or Produces.
+func encodingMIMETypes(encoding bool, args ...interface{}) []string {
+	funcName := "Consumes"
+	if encoding {
+		funcName = "Produces"
+	}
+	if len(args) == 0 {
+		dslengine.ReportError("missing argument in call to %s", funcName)
+		return nil
+	}
+	mimeTypes := make([]string, len(args))
+	for i, arg := range args {
+		mimeType, ok := arg.(string)
+		if !ok {
+			dslengine.ReportError("argument #%d of %s must be a string (MIME type), encoding packages can only be set at the API level", i, funcName)
+			return nil
+		}
+		mimeTypes[i] = mimeType
 	}
+	return mimeTypes
 }
 
 // buildEncodingDefinition builds up an encoding definition.
@@ -76,6 +76,37 @@ var _ = Describe("API", func() {
 			})
 		})
 
+		Context("with an indented multi-line description", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Description(`
+						Manages the bottles.
+
+						  - list
+						  - show
+					`)
+				}
+			})
+
+			It("removes the common indentation and the surrounding blank lines", func() {
+				Ω(Design.Description).Should(Equal("Manages the bottles.\n\n  - list\n  - show"))
+			})
+		})
+
+		Context("with a multi-line description starting on the first line", func() {
+			const description = "Manages the bottles.\n    Indented."
+
+			BeforeEach(func() {
+				dsl = func() {
+					Description(description)
+				}
+			})
+
+			It("keeps the description as is", func() {
+				Ω(Design.Description).Should(Equal(description))
+			})
+		})
+
 		Context("with a title", func() {
 			const title = "title"
 
@@ -201,7 +232,7 @@ var _ = Describe("API", func() {
 		})
 
 		Context("with a BasePath", func() {
-			const basePath = "basePath"
+			const basePath = "/basePath"
 
 			BeforeEach(func() {
 				dsl = func() {
@@ -214,6 +245,196 @@ var _ = Describe("API", func() {
 			})
 		})
 
+		Context("with a BasePath that does not start with a slash", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					BasePath("api")
+				}
+			})
+
+			It("prefixes the API base path with a slash", func() {
+				Ω(Design.BasePath).Should(Equal("/api"))
+			})
+		})
+
+		Context("with a BasePath using the version placeholder", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Version("2.0")
+					BasePath("/api/{version}")
+				}
+			})
+
+			It("replaces the placeholder with the major version", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.BasePath).Should(Equal("/api/v2"))
+			})
+		})
+
+		Context("with no BasePath", func() {
+			BeforeEach(func() {
+				name = "foo"
+			})
+
+			It("sets the API base path to /", func() {
+				Ω(Design.BasePath).Should(Equal("/"))
+			})
+		})
+
+		Context("with Servers", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("https://api.example.com", func() {
+						Label("production")
+						Description("production server")
+					})
+					Server("https://us.staging.example.com:8443")
+					Server("https://eu.staging.example.com", func() {
+						Label("staging")
+					})
+					Server("https://us.example.com", func() {
+						Label("production")
+					})
+				}
+			})
+
+			It("stores the server labels", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.Servers).Should(HaveLen(4))
+				Ω(Design.Servers[0].URL).Should(Equal("https://api.example.com"))
+				Ω(Design.Servers[0].Label).Should(Equal("production"))
+				Ω(Design.Servers[0].Description).Should(Equal("production server"))
+				Ω(Design.Servers[2].Label).Should(Equal("staging"))
+			})
+
+			It("defaults the label to the server host", func() {
+				Ω(Design.Servers[1].Label).Should(Equal("us.staging.example.com"))
+			})
+
+			It("lists the distinct labels", func() {
+				Ω(Design.ServerLabels()).Should(Equal([]string{"production", "us.staging.example.com", "staging"}))
+			})
+		})
+
+		Context("with named servers", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("https://api.example.com", func() {
+						Name("production")
+					})
+					Server("https://eu.example.com")
+					Server("https://staging.example.com", func() {
+						Name("staging")
+					})
+				}
+			})
+
+			It("stores the server names", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.Servers[0].Name).Should(Equal("production"))
+				Ω(Design.Servers[1].Name).Should(BeEmpty())
+				Ω(Design.NamedServers()).Should(Equal([]*ServerDefinition{Design.Servers[0], Design.Servers[2]}))
+			})
+		})
+
+		Context("with duplicate server URLs", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("https://api.example.com", func() {
+						Name("production")
+					})
+					Server("https://staging.example.com")
+					Server("https://api.example.com", func() {
+						Description("Production server")
+					})
+					Server("https://dev.example.com")
+				}
+			})
+
+			It("collapses the duplicates and preserves the declaration order", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				var urls []string
+				Design.IterateServers(func(s *ServerDefinition) error {
+					urls = append(urls, s.URL)
+					return nil
+				})
+				Ω(urls).Should(Equal([]string{"https://api.example.com", "https://staging.example.com", "https://dev.example.com"}))
+				Ω(Design.Servers[0].Name).Should(Equal("production"))
+				Ω(Design.Servers[0].Description).Should(Equal("Production server"))
+			})
+		})
+
+		Context("with server URLs that include ports", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("https://api.example.com:443", func() {
+						Name("production")
+					})
+					Server("HTTP://{region}.example.com:80/v1", func() {
+						Variable("region", func() {
+							Default("us")
+						})
+					})
+					Server("https://staging.example.com:8443")
+					Server("http://localhost:8080")
+					Server("https://api.example.com", func() {
+						Description("Production server")
+					})
+				}
+			})
+
+			It("removes the default ports", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.Servers).Should(HaveLen(4))
+				Ω(Design.Servers[0].URL).Should(Equal("https://api.example.com"))
+				Ω(Design.Servers[0].Label).Should(Equal("api.example.com"))
+				Ω(Design.Servers[0].Description).Should(Equal("Production server"))
+				Ω(Design.Servers[1].URL).Should(Equal("http://{region}.example.com/v1"))
+				Ω(Design.Servers[1].Port()).Should(Equal(80))
+			})
+
+			It("keeps the other ports", func() {
+				Ω(Design.Servers[2].URL).Should(Equal("https://staging.example.com:8443"))
+				Ω(Design.Servers[2].Port()).Should(Equal(8443))
+				Ω(Design.Servers[3].URL).Should(Equal("http://localhost:8080"))
+			})
+		})
+
+		Context("with a default server", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("https://api.example.com", func() {
+						Name("production")
+					})
+					Server("https://staging.example.com", func() {
+						Name("staging")
+						Default()
+					})
+				}
+			})
+
+			It("uses it as the default server", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.Servers[0].Default).Should(BeFalse())
+				Ω(Design.Servers[1].Default).Should(BeTrue())
+				Ω(Design.DefaultServer()).Should(Equal(Design.Servers[1]))
+			})
+		})
+
+		Context("with no default server", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("https://api.example.com")
+					Server("https://staging.example.com")
+				}
+			})
+
+			It("uses the first server as the default server", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.DefaultServer()).Should(Equal(Design.Servers[0]))
+			})
+		})
+
 		Context("with Params", func() {
 			const param1Name = "accountID"
 			const param1Type = Integer
@@ -450,4 +671,657 @@ var _ = Describe("API", func() {
 		})
 	})
 
+	Context("with invalid DSL", func() {
+		Context("with a relative server URL", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("/api")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid server URL"))
+			})
+		})
+
+		Context("with a server URL using an unsupported scheme", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("ftp://localhost:21")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unsupported server URL scheme "ftp"`))
+			})
+		})
+
+		Context("with a gRPC server URL", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("grpc://localhost:8090")
+				}
+			})
+
+			It("is valid", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with duplicate server names", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("https://api.example.com", func() {
+						Name("production")
+					})
+					Server("https://us.example.com", func() {
+						Name("production")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`server name "production" is already used by server https://api.example.com`))
+			})
+		})
+
+		Context("with multiple default servers", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Server("https://api.example.com", func() {
+						Default()
+					})
+					Server("https://staging.example.com", func() {
+						Default()
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("only one server can be the default, server https://api.example.com is already the default"))
+			})
+		})
+
+		Context("with a BasePath using the version placeholder", func() {
+			Context("and no version", func() {
+				BeforeEach(func() {
+					dsl = func() {
+						BasePath("/api/{version}")
+					}
+				})
+
+				It("produces an error", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(dslengine.Errors.Error()).Should(ContainSubstring(`base path "/api/{version}" uses {version} but the API does not define a version`))
+				})
+			})
+
+			Context("and a version that is not a valid path segment", func() {
+				BeforeEach(func() {
+					dsl = func() {
+						Version("beta 1")
+						BasePath("/api/{version}")
+					}
+				})
+
+				It("produces an error", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid base path "/api/vbeta 1"`))
+				})
+			})
+		})
+	})
+
+})
+
+var _ = Describe("HealthCheck", func() {
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		API("foo", func() {
+			BasePath("/api")
+			HealthCheck("/health")
+		})
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			HealthCheck("/ping", "pong")
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Params(func() {
+					Param("id")
+				})
+			})
+			if dsl != nil {
+				dsl()
+			}
+		})
+		dslengine.Run()
+	})
+
+	It("adds the health check endpoints to the routes", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.HealthCheck).ShouldNot(BeNil())
+		Ω(Design.HealthCheck.Body).Should(Equal("OK"))
+		Ω(Design.Resources["bottle"].HealthCheck.Body).Should(Equal("pong"))
+		var paths []string
+		for _, r := range Design.Routes() {
+			paths = append(paths, r.Verb+" "+r.FullPath())
+		}
+		Ω(paths).Should(Equal([]string{
+			"GET /api/bottles/:id",
+			"GET /api/health",
+			"GET /api/bottles/ping",
+		}))
+	})
+
+	Context("colliding with an action route", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Action("ping", func() {
+					Routing(GET("/ping"))
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`health check path "/api/bottles/ping" collides with route GET "/ping"`))
+		})
+	})
+
+	Context("colliding with an action route with a trailing slash", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Action("ping", func() {
+					Routing(GET("/ping/"))
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`health check path "/api/bottles/ping" collides with route GET "/ping/"`))
+		})
+	})
+
+	Context("colliding with an action route using a different wildcard name", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				HealthCheck("/:name")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`health check path "/api/bottles/:name" collides with route GET "/:id"`))
+		})
+	})
+})
+
+var _ = Describe("ResponseHeaders", func() {
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			ResponseHeaders(func() {
+				Header("X-Content-Type-Options", String, func() {
+					Default("nosniff")
+				})
+			})
+		})
+		Resource("bottle", func() {
+			ResponseHeaders(dsl)
+			Action("show", func() {
+				Routing(GET("/:id"))
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with constant headers", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Header("X-Content-Type-Options", String, func() {
+					Default("none")
+				})
+				Header("Vary", ArrayOf(String), func() {
+					Default([]interface{}{"Accept", "Origin"})
+				})
+			}
+		})
+
+		It("merges the API and resource headers", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			headers := Design.Resources["bottle"].StaticResponseHeaders()
+			Ω(headers).Should(Equal(map[string]string{
+				"X-Content-Type-Options": "none",
+				"Vary":                   "Accept, Origin",
+			}))
+		})
+	})
+
+	Context("with a header with no value", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Header("X-Request-ID")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`resource "bottle": response header "X-Request-ID" must define its value with Default`))
+		})
+	})
+})
+
+var _ = Describe("CORS", func() {
+	var apiDSL, resDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = func() {
+			CORS(func() {
+				Origins("https://goa.design", "https://*.goa.design")
+				Methods("GET", "POST")
+				Headers("X-Shared-Secret")
+				MaxAge(600)
+				Credentials()
+			})
+		}
+		resDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("foo", apiDSL)
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+			})
+			if resDSL != nil {
+				resDSL()
+			}
+		})
+		dslengine.Run()
+	})
+
+	It("records one policy per origin", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.Origins).Should(HaveLen(2))
+		o := Design.Origins["https://*.goa.design"]
+		Ω(o).ShouldNot(BeNil())
+		Ω(o.Methods).Should(Equal([]string{"GET", "POST"}))
+		Ω(o.Headers).Should(Equal([]string{"X-Shared-Secret"}))
+		Ω(o.MaxAge).Should(Equal(uint(600)))
+		Ω(o.Credentials).Should(BeTrue())
+	})
+
+	It("is inherited by resources lacking their own policy", func() {
+		origins := Design.Resources["bottle"].AllOrigins()
+		Ω(origins).Should(HaveLen(2))
+		Ω(origins[0].Origin).Should(Equal("https://*.goa.design"))
+		Ω(origins[1].Origin).Should(Equal("https://goa.design"))
+	})
+
+	Context("with a resource policy", func() {
+		BeforeEach(func() {
+			resDSL = func() {
+				CORS(func() {
+					Origins("https://goa.design")
+					Methods("GET")
+				})
+			}
+		})
+
+		It("overrides the API policy for the same origin", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			origins := Design.Resources["bottle"].AllOrigins()
+			Ω(origins).Should(HaveLen(2))
+			Ω(origins[1].Origin).Should(Equal("https://goa.design"))
+			Ω(origins[1].Methods).Should(Equal([]string{"GET"}))
+			Ω(origins[1].Credentials).Should(BeFalse())
+		})
+	})
+
+	Context("with a malformed origin", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				CORS(func() {
+					Origins("ftp://goa.design", "https://goa.design/path")
+				})
+			}
+		})
+
+		It("produces errors", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid origin, scheme must be http or https, got "ftp"`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"https://goa.design/path" must consist of a scheme, a host and a port only`))
+		})
+	})
+
+	Context("with no origin", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				CORS(func() {
+					Methods("GET")
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("CORS must list at least one origin with Origins"))
+		})
+	})
+})
+
+var _ = Describe("ValidationErrorStatus", func() {
+	var status int
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		status = 0
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			if status != 0 {
+				ValidationErrorStatus(status)
+			}
+		})
+		dslengine.Run()
+	})
+
+	It("defaults to 400", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.InvalidRequestStatus()).Should(Equal(400))
+	})
+
+	Context("with a 4xx status", func() {
+		BeforeEach(func() {
+			status = 422
+		})
+
+		It("overrides the status", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.InvalidRequestStatus()).Should(Equal(422))
+		})
+	})
+
+	Context("with a status that is not 4xx", func() {
+		BeforeEach(func() {
+			status = 500
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid validation error status 500, must be a 4xx status code"))
+		})
+	})
+})
+
+var _ = Describe("RequestID", func() {
+	var header string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		header = ""
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			if header != "" {
+				RequestID(header)
+			}
+		})
+		dslengine.Run()
+	})
+
+	It("defaults to X-Request-ID", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.RequestIDHeaderName()).Should(Equal("X-Request-ID"))
+	})
+
+	Context("with a custom header", func() {
+		BeforeEach(func() {
+			header = "X-Correlation-ID"
+		})
+
+		It("overrides the header", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.RequestIDHeaderName()).Should(Equal("X-Correlation-ID"))
+		})
+	})
+
+	Context("with a header name that is not a token", func() {
+		BeforeEach(func() {
+			header = "X Correlation: ID"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid request ID header name "X Correlation: ID"`))
+		})
+	})
+})
+
+var _ = Describe("ResponseExample", func() {
+	var example interface{}
+	var showExample interface{}
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		example = map[string]interface{}{"code": "not_found", "detail": "bottle not found"}
+		showExample = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			ResponseExample(404, func() {
+				Description("Bottle not found")
+				Example(example)
+			})
+		})
+		Resource("bottle", func() {
+			Action("list", func() {
+				Routing(GET(""))
+				Response(NotFound, ErrorMedia)
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(NotFound, ErrorMedia, func() {
+					if showExample != nil {
+						Example(showExample)
+					}
+				})
+			})
+			Action("delete", func() {
+				Routing(DELETE("/:id"))
+				Response(NotFound)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the API response example", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.ResponseExamples).Should(HaveKey(404))
+		Ω(Design.ResponseExamples[404].Description).Should(Equal("Bottle not found"))
+		Ω(Design.ResponseExamples[404].Value).Should(Equal(example))
+	})
+
+	It("is inherited by the action responses", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		list := Design.Resources["bottle"].Actions["list"]
+		Ω(list.Responses["NotFound"].BodyExample()).Should(Equal(example))
+	})
+
+	It("is not inherited by responses with no body", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		del := Design.Resources["bottle"].Actions["delete"]
+		Ω(del.Responses["NotFound"].BodyExample()).Should(BeNil())
+	})
+
+	Context("with an action response example", func() {
+		BeforeEach(func() {
+			showExample = map[string]interface{}{"code": "gone", "detail": "bottle was deleted"}
+		})
+
+		It("overrides the API response example", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			show := Design.Resources["bottle"].Actions["show"]
+			Ω(show.Responses["NotFound"].BodyExample()).Should(Equal(showExample))
+			list := Design.Resources["bottle"].Actions["list"]
+			Ω(list.Responses["NotFound"].BodyExample()).Should(Equal(example))
+		})
+	})
+
+	Context("with an example incompatible with the response type", func() {
+		BeforeEach(func() {
+			example = map[string]interface{}{"code": "not_found", "status": 404}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid response example: body.status"))
+		})
+	})
+
+	Context("with an example using an unknown attribute", func() {
+		BeforeEach(func() {
+			example = map[string]interface{}{"reason": "not_found"}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unknown attribute "reason"`))
+		})
+	})
+})
+
+var _ = Describe("TrailingSlash", func() {
+	var policy string
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		policy = ""
+		dsl = func() {
+			Action("list", func() {
+				Routing(GET("/"))
+			})
+			Action("show", func() {
+				Routing(GET("/:id/"))
+			})
+		}
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			if policy != "" {
+				TrailingSlash(policy)
+			}
+		})
+		Resource("user", func() {
+			BasePath("/users")
+			dsl()
+		})
+		dslengine.Run()
+	})
+
+	paths := func() []string {
+		var ps []string
+		for _, r := range Design.Routes() {
+			ps = append(ps, r.FullPath())
+		}
+		return ps
+	}
+
+	It("keeps the route paths by default", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(paths()).Should(Equal([]string{"/users/", "/users/:id/"}))
+	})
+
+	Context("with the redirect policy", func() {
+		BeforeEach(func() {
+			policy = TrailingSlashRedirect
+		})
+
+		It("removes the trailing slashes", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(paths()).Should(Equal([]string{"/users", "/users/:id"}))
+		})
+	})
+
+	Context("with the strict policy", func() {
+		BeforeEach(func() {
+			policy = TrailingSlashStrict
+		})
+
+		It("keeps the route paths", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(paths()).Should(Equal([]string{"/users/", "/users/:id/"}))
+		})
+
+		Context("and routes that only differ by a trailing slash", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Action("list", func() {
+						Routing(GET(""))
+					})
+					Action("index", func() {
+						Routing(GET("/"))
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`route GET "/users" only differs from route GET "/users/" of resource "user" action "index" by a trailing slash`))
+			})
+		})
+	})
+
+	Context("with the ignore policy and routes that only differ by a trailing slash", func() {
+		BeforeEach(func() {
+			policy = TrailingSlashIgnore
+			dsl = func() {
+				Action("list", func() {
+					Routing(GET(""))
+				})
+				Action("index", func() {
+					Routing(GET("/"))
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("once trailing slashes are removed"))
+		})
+	})
+
+	Context("with an invalid policy", func() {
+		BeforeEach(func() {
+			policy = "lenient"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid trailing slash policy "lenient"`))
+		})
+	})
 })
@@ -113,6 +113,10 @@ func Attribute(name string, args ...interface{}) {
 		}
 
 		baseAttr := attributeFromRef(name, parent.Reference)
+		var inherited []*design.AttributeProperty
+		if design.TrackProvenance && baseAttr != nil {
+			inherited = baseAttr.Properties()
+		}
 		dataType, description, dsl := parseAttributeArgs(baseAttr, args...)
 		if baseAttr != nil {
 			if description != "" {
@@ -135,10 +139,25 @@ func Attribute(name string, args ...interface{}) {
 			// DSL did not contain an "Attribute" declaration
 			baseAttr.Type = design.String
 		}
+		if inherited != nil {
+			baseAttr.RecordProvenance(inherited, referenceName(parent.Reference))
+		}
 		parent.Type.(design.Object)[name] = baseAttr
 	}
 }
 
+// referenceName returns the name of the reference data type used to record the provenance of
+// the inherited attribute properties, e.g. "BaseBottle".
+func referenceName(ref design.DataType) string {
+	switch t := ref.(type) {
+	case *design.UserTypeDefinition:
+		return t.TypeName
+	case *design.MediaTypeDefinition:
+		return t.TypeName
+	}
+	return ref.Name()
+}
+
 // attributeFromRef returns a base attribute given a reference data type.
 // It takes care of running the DSL on the reference type if it hasn't run yet.
 func attributeFromRef(name string, ref design.DataType) *design.AttributeDefinition {
@@ -241,14 +260,26 @@ func parseAttributeArgs(baseAttr *design.AttributeDefinition, args ...interface{
 	return dataType, description, dsl
 }
 
-// Header can be used in: Headers, APIKeySecurity, JWTSecurity
+// Header can be used in: Headers, APIKeySecurity, JWTSecurity, Signature
 //
 // Header is an alias of Attribute for the most part.
 //
 // Within an APIKeySecurity or JWTSecurity definition, Header
 // defines that an implementation must check the given header to get
 // the API Key.  In this case, no `args` parameter is necessary.
+//
+// Within a Signature definition, Header sets the name of the header
+// containing the request signature. No `args` parameter is necessary
+// either.
 func Header(name string, args ...interface{}) {
+	if s, ok := dslengine.CurrentDefinition().(*design.SignatureDefinition); ok {
+		if len(args) != 0 {
+			dslengine.ReportError("do not specify args")
+			return
+		}
+		s.Header = name
+		return
+	}
 	if _, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
 		if len(args) != 0 {
 			dslengine.ReportError("do not specify args")
@@ -275,11 +306,33 @@ func Param(name string, args ...interface{}) {
 	Attribute(name, args...)
 }
 
-// Default can be used in: Attribute
+// Default can be used in: Attribute, Server
 //
 // Default sets the default value for an attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor10.
-func Default(def interface{}) {
+//
+// When used in Server, Default takes no argument and marks the server as the one generated clients
+// send requests to when no environment is specified. At most one server may be the default, the
+// first server is used if none is. Example:
+//
+//	Server("https://api.example.com", func() {
+//		Name("production")
+//		Default()
+//	})
+func Default(args ...interface{}) {
+	if s, ok := dslengine.CurrentDefinition().(*design.ServerDefinition); ok {
+		if len(args) > 0 {
+			dslengine.ReportError("Default does not accept arguments when used in Server")
+			return
+		}
+		s.Default = true
+		return
+	}
+	if len(args) != 1 {
+		dslengine.ReportError("Default requires exactly one argument")
+		return
+	}
+	def := args[0]
 	if a, ok := attributeDefinition(); ok {
 		if a.Type != nil {
 			if !a.Type.CanHaveDefault() {
@@ -296,7 +349,7 @@ func Default(def interface{}) {
 	}
 }
 
-// Example can be used in: Attribute, Header, Param, HashOf, ArrayOf
+// Example can be used in: Attribute, Header, Param, HashOf, ArrayOf, Response, ResponseExample
 //
 // Example sets the example of an attribute to be used for the documentation:
 //
@@ -311,7 +364,22 @@ func Default(def interface{}) {
 //	})
 //
 // If you do not want an auto-generated example for an attribute, add NoExample() to it.
+//
+// Used in Response or ResponseExample, Example sets the example body of the response. The example
+// of a response overrides the API response example for the same status:
+//
+//	Response(NotFound, ErrorMedia, func() {
+//		Example(map[string]interface{}{"code": "not_found", "detail": "bottle not found"})
+//	})
 func Example(exp interface{}) {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ResponseDefinition:
+		def.Example = exp
+		return
+	case *design.ResponseExampleDefinition:
+		def.Value = exp
+		return
+	}
 	if a, ok := attributeDefinition(); ok {
 		if pass := a.SetExample(exp); !pass {
 			dslengine.ReportError("example value %#v is incompatible with attribute of type %s",
@@ -322,13 +390,24 @@ func Example(exp interface{}) {
 
 // ReadOnly can be used in: Attribute
 // ReadOnly sets the readOnly property of an attribute to true. It is used when attributes are computed in the API and
-// are not expected from the client
+// are not expected from the client. Read-only attributes are omitted from the generated request body types, a
+// payload may not require them.
 func ReadOnly() {
 	if a, ok := attributeDefinition(); ok {
 		a.SetReadOnly()
 	}
 }
 
+// WriteOnly can be used in: Attribute
+// WriteOnly sets the writeOnly property of an attribute to true. It is used for attributes that are sent by the
+// client but never returned by the API, e.g. passwords. Write-only attributes are omitted from the rendered media
+// types even if a view lists them. An attribute cannot be both read-only and write-only.
+func WriteOnly() {
+	if a, ok := attributeDefinition(); ok {
+		a.SetWriteOnly()
+	}
+}
+
 // NoExample can be used in: Attribute, Header, Param, HashOf, ArrayOf
 //
 // NoExample sets the example of an attribute to be blank for the documentation. It is used when
@@ -541,7 +620,7 @@ func Maximum(val interface{}) {
 // See http://json-schema.org/latest/json-schema-validation.html#anchor45.
 func MinLength(val int) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.StringKind && a.Type.Kind() != design.ArrayKind && a.Type.Kind() != design.HashKind {
+		if a.Type != nil && a.Type.Kind() != design.StringKind && a.Type.Kind() != design.BytesKind && a.Type.Kind() != design.ArrayKind && a.Type.Kind() != design.HashKind {
 			incompatibleAttributeType("minimum length", a.Type.Name(), "a string or an array")
 		} else {
 			if a.Validation == nil {
@@ -556,9 +635,11 @@ func MinLength(val int) {
 //
 // MaxLength adds a "maxItems" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor42.
+// The generated code also uses the maximum length of binary payloads (see Bytes) to limit the
+// size of the request bodies it reads.
 func MaxLength(val int) {
 	if a, ok := attributeDefinition(); ok {
-		if a.Type != nil && a.Type.Kind() != design.StringKind && a.Type.Kind() != design.ArrayKind {
+		if a.Type != nil && a.Type.Kind() != design.StringKind && a.Type.Kind() != design.BytesKind && a.Type.Kind() != design.ArrayKind {
 			incompatibleAttributeType("maximum length", a.Type.Name(), "a string or an array")
 		} else {
 			if a.Validation == nil {
@@ -596,6 +677,44 @@ func Required(names ...string) {
 	}
 }
 
+// RequiredTogether can be used in: Attributes, Headers, Payload, Type, Params
+//
+// RequiredTogether declares a group of attributes that must be either all present or all absent:
+// if one of them is set then the others must be as well. The generated validation code returns
+// an error for each missing attribute of a partially set group. Example:
+//
+//	Params(func() {
+//		Param("lat", Number)
+//		Param("lng", Number)
+//		RequiredTogether("lat", "lng")
+//	})
+func RequiredTogether(names ...string) {
+	var at *design.AttributeDefinition
+
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.AttributeDefinition:
+		at = def
+	case *design.MediaTypeDefinition:
+		at = def.AttributeDefinition
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+
+	if len(names) < 2 {
+		dslengine.ReportError("RequiredTogether requires at least two attribute names")
+		return
+	}
+	if at.Type != nil && at.Type.Kind() != design.ObjectKind {
+		incompatibleAttributeType("required together", at.Type.Name(), "an object")
+		return
+	}
+	if at.Validation == nil {
+		at.Validation = &dslengine.ValidationDefinition{}
+	}
+	at.Validation.AddTogether(names)
+}
+
 // incompatibleAttributeType reports an error for validations defined on
 // incompatible attributes (e.g. max value on string).
 func incompatibleAttributeType(validation, actual, expected string) {
@@ -610,6 +729,10 @@ func qualifiedTypeName(t design.DataType) string {
 	switch t.Kind() {
 	case design.DateTimeKind:
 		return "datetime"
+	case design.DurationKind:
+		return "duration"
+	case design.BytesKind:
+		return "bytes"
 	case design.ArrayKind:
 		return fmt.Sprintf("%s<%s>", t.Name(), qualifiedTypeName(t.ToArray().ElemType.Type))
 	case design.HashKind:
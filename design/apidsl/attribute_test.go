@@ -399,3 +399,135 @@ var _ = Describe("Attribute", func() {
 		})
 	})
 })
+
+var _ = Describe("RequiredTogether", func() {
+	var fields []string
+	var ut *UserTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		fields = []string{"lat", "lng"}
+	})
+
+	JustBeforeEach(func() {
+		ut = Type("point", func() {
+			Attribute("lat", Number)
+			Attribute("lng", Number)
+			RequiredTogether(fields...)
+		})
+		dslengine.Run()
+	})
+
+	It("records the group", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(ut.Validation.Together).Should(Equal([][]string{{"lat", "lng"}}))
+	})
+
+	Context("with an unknown field", func() {
+		BeforeEach(func() {
+			fields = []string{"lat", "alt"}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`field "alt" required together with lat, alt does not exist`))
+		})
+	})
+
+	Context("with a single field", func() {
+		BeforeEach(func() {
+			fields = []string{"lat"}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("ReadOnly and WriteOnly", func() {
+	var account *MediaTypeDefinition
+	var payloadDSL func()
+	var res *ResourceDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		payloadDSL = nil
+		account = MediaType("application/vnd.account", func() {
+			Attributes(func() {
+				Attribute("id", Integer, func() { ReadOnly() })
+				Attribute("name", String)
+				Attribute("password", String, func() { WriteOnly() })
+				Required("name")
+			})
+			View("default", func() {
+				Attribute("id")
+				Attribute("name")
+				Attribute("password")
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {})
+		res = Resource("account", func() {
+			Action("create", func() {
+				Routing(POST(""))
+				Payload(account)
+				if payloadDSL != nil {
+					payloadDSL()
+				}
+				Response(Created, account)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("excludes the read-only attributes from the request body", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		p := res.Actions["create"].Payload
+		Ω(p.TypeName).Should(Equal("CreateAccountPayload"))
+		Ω(p.Type.ToObject()).ShouldNot(HaveKey("id"))
+		Ω(p.Type.ToObject()).Should(HaveKey("password"))
+		Ω(account.Type.ToObject()).Should(HaveKey("id"))
+	})
+
+	It("excludes the write-only attributes from the rendered media type", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		p, _, err := account.Project("default")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(p.Type.ToObject()).Should(HaveKey("id"))
+		Ω(p.Type.ToObject()).ShouldNot(HaveKey("password"))
+	})
+
+	Context("with a payload that requires a read-only attribute", func() {
+		BeforeEach(func() {
+			payloadDSL = func() {
+				Payload(account, func() {
+					Required("id")
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`requires the read-only attribute "id"`))
+		})
+	})
+
+	Context("with an attribute both read-only and write-only", func() {
+		BeforeEach(func() {
+			Type("invalid", func() {
+				Attribute("secret", String, func() {
+					ReadOnly()
+					WriteOnly()
+				})
+			})
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("cannot be both read-only and write-only"))
+		})
+	})
+})
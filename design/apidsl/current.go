@@ -42,6 +42,26 @@ func contactDefinition() (*design.ContactDefinition, bool) {
 	return a, ok
 }
 
+// serverDefinition returns true and current context if it is a ServerDefinition,
+// nil and false otherwise.
+func serverDefinition() (*design.ServerDefinition, bool) {
+	s, ok := dslengine.CurrentDefinition().(*design.ServerDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return s, ok
+}
+
+// signatureDefinition returns true and current context if it is a SignatureDefinition,
+// nil and false otherwise.
+func signatureDefinition() (*design.SignatureDefinition, bool) {
+	s, ok := dslengine.CurrentDefinition().(*design.SignatureDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return s, ok
+}
+
 // licenseDefinition returns true and current context if it is an APIDefinition,
 // nil and false otherwise.
 func licenseDefinition() (*design.LicenseDefinition, bool) {
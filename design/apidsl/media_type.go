@@ -180,15 +180,41 @@ func Media(val interface{}, viewName ...string) {
 //
 // defines the "name" and "vintage" attributes with the same type and validations as defined in
 // the Bottle type.
+//
+// Reference may also be used in the Params, Headers and Payload DSLs to share the definitions of
+// request attributes across actions, in this case it overrides the resource default media type:
+//
+//	Params(func() {
+//		Reference(Pagination)
+//		Param("page")	// Inherits the type and validations of the Pagination "page" attribute
+//	})
+//
+// Reference must be called before the attributes that use it are defined.
 func Reference(t design.DataType) {
+	var att *design.AttributeDefinition
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.MediaTypeDefinition:
-		def.Reference = t
+		att = def.AttributeDefinition
 	case *design.AttributeDefinition:
-		def.Reference = t
+		att = def
 	default:
 		dslengine.IncompatibleDSL()
+		return
 	}
+	if att.Type != nil && att.Type.ToObject() != nil {
+		var early string
+		att.Type.ToObject().IterateAttributes(func(n string, _ *design.AttributeDefinition) error {
+			if early == "" && attributeFromRef(n, t) != nil {
+				early = n
+			}
+			return nil
+		})
+		if early != "" {
+			dslengine.ReportError("attribute %#v is defined before the call to Reference, move Reference first", early)
+			return
+		}
+	}
+	att.Reference = t
 }
 
 // TypeName can be used in: MediaType
@@ -206,16 +232,30 @@ func TypeName(name string) {
 	}
 }
 
-// ContentType can be used in: MediaType
+// ContentType can be used in: MediaType, Response
 //
 // ContentType sets the value of the Content-Type response header. By default the ID of the media
 // type is used.
 //
 //    ContentType("application/json")
 //
+// Used in a Response DSL, ContentType sets the Content-Type header of the responses whose type
+// overrides the media type, for example binary responses:
+//
+//	Response(OK, func() {
+//		Media(ImageMedia)	// ImageMedia defines the "data" Bytes attribute
+//		Body("data")		// Response body is the raw image
+//		ContentType("image/png")
+//	})
+//
 func ContentType(typ string) {
-	if mt, ok := mediaTypeDefinition(); ok {
-		mt.ContentType = typ
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.MediaTypeDefinition:
+		def.ContentType = typ
+	case *design.ResponseDefinition:
+		def.ContentType = typ
+	default:
+		dslengine.IncompatibleDSL()
 	}
 }
 
@@ -338,7 +378,7 @@ func Links(apidsl func()) {
 	}
 }
 
-// Link can be used in: Links
+// Link can be used in: Links, Action
 //
 // Link adds a link to a media type. At the minimum a link has a name corresponding to one of the
 // media type attribute names. A link may also define the view used to render the linked-to
@@ -346,7 +386,37 @@ func Links(apidsl func()) {
 //
 //	Link("origin")		// Use the "link" view of the "origin" attribute
 //	Link("account", "tiny")	// Use the "tiny" view of the "account" attribute
+//
+// When used in an action Link defines a link from the action responses to another action. The
+// first argument is the link relation type, the second and third the names of the resource and of
+// the target action. The code generators produce href builders from the path of the target action
+// first route and the Swagger generator describes the links in the x-links extension of the action
+// success responses. Example:
+//
+//	Action("show", func() {
+//		Routing(GET("/bottles/:bottleID"))
+//		Link("owner", "account", "show")	// Link to the show action of the account resource
+//	})
 func Link(name string, view ...string) {
+	if a, ok := dslengine.CurrentDefinition().(*design.ActionDefinition); ok {
+		if len(view) != 2 {
+			dslengine.ReportError("invalid syntax in Link definition for %#v, allowed syntax in actions is Link(rel, resource, action)", name)
+			return
+		}
+		for _, l := range a.Links {
+			if l.Rel == name {
+				dslengine.ReportError("duplicate definition for link %#v", name)
+				return
+			}
+		}
+		a.Links = append(a.Links, &design.ActionLinkDefinition{
+			Rel:      name,
+			Resource: view[0],
+			Action:   view[1],
+			Parent:   a,
+		})
+		return
+	}
 	if mt, ok := mediaTypeDefinition(); ok {
 		if mt.Links == nil {
 			mt.Links = make(map[string]*design.LinkDefinition)
@@ -823,3 +823,126 @@ var _ = Describe("Example", func() {
 		})
 	})
 })
+
+var _ = Describe("Reference", func() {
+	var Pagination *UserTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		Pagination = Type("Pagination", func() {
+			Attribute("page", Integer, "Page number", func() {
+				Minimum(1)
+			})
+			Attribute("token", String, func() {
+				MinLength(16)
+				Metadata("swagger:summary", "token")
+			})
+		})
+	})
+
+	Context("used in Params", func() {
+		BeforeEach(func() {
+			Resource("bottle", func() {
+				Action("list", func() {
+					Routing(GET(""))
+					Params(func() {
+						Reference(Pagination)
+						Param("page")
+					})
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("provides the parameter definitions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			page := Design.Resources["bottle"].Actions["list"].Params.Type.ToObject()["page"]
+			Ω(page).ShouldNot(BeNil())
+			Ω(page.Type).Should(Equal(Integer))
+			Ω(page.Description).Should(Equal("Page number"))
+			Ω(*page.Validation.Minimum).Should(Equal(1.0))
+		})
+	})
+
+	Context("used in Headers", func() {
+		BeforeEach(func() {
+			Resource("bottle", func() {
+				Action("list", func() {
+					Routing(GET(""))
+					Headers(func() {
+						Reference(Pagination)
+						Header("token")
+					})
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("provides the header validations", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			token := Design.Resources["bottle"].Actions["list"].Headers.Type.ToObject()["token"]
+			Ω(token).ShouldNot(BeNil())
+			Ω(*token.Validation.MinLength).Should(Equal(16))
+		})
+	})
+
+	Context("shared by resources with different mappings", func() {
+		BeforeEach(func() {
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Action("list", func() {
+					Routing(GET(""))
+					Params(func() {
+						Reference(Pagination)
+						Param("token", func() {
+							MaxLength(32)
+							Metadata("swagger:summary", "bottle token")
+						})
+					})
+				})
+			})
+			Resource("account", func() {
+				BasePath("/accounts")
+				Action("list", func() {
+					Routing(GET(""))
+					Headers(func() {
+						Reference(Pagination)
+						Header("token")
+					})
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("does not leak the definitions of one resource into the other", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			param := Design.Resources["bottle"].Actions["list"].Params.Type.ToObject()["token"]
+			Ω(*param.Validation.MaxLength).Should(Equal(32))
+			Ω(param.Metadata["swagger:summary"]).Should(Equal([]string{"token", "bottle token"}))
+			header := Design.Resources["account"].Actions["list"].Headers.Type.ToObject()["token"]
+			Ω(header.Validation.MaxLength).Should(BeNil())
+			Ω(header.Metadata["swagger:summary"]).Should(Equal([]string{"token"}))
+			Ω(Pagination.Type.ToObject()["token"].Metadata["swagger:summary"]).Should(Equal([]string{"token"}))
+		})
+	})
+
+	Context("called after the attributes it provides", func() {
+		BeforeEach(func() {
+			Resource("bottle", func() {
+				Action("list", func() {
+					Routing(GET(""))
+					Params(func() {
+						Param("page")
+						Reference(Pagination)
+					})
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`attribute "page" is defined before the call to Reference, move Reference first`))
+		})
+	})
+})
@@ -1,11 +1,14 @@
 package apidsl
 
 import (
+	"encoding/json"
+	"strings"
+
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 )
 
-// Metadata can be used in: Attributes, MediaType, Action, Response, Resource, API
+// Metadata can be used in: Attributes, MediaType, Action, Response, Resource, Mount, API
 //
 // Metadata is a set of key/value pairs that can be assigned to an object. Each value consists of a
 // slice of strings so that multiple invocation of the Metadata function on the same target using
@@ -46,6 +49,13 @@ import (
 //
 //        Metadata("swagger:summary", "Short summary of what action does")
 //
+// `swagger:curl`: appends a curl command making an example request to the operation description
+// when set to "true". The command uses the URL of the first server, the example values of the
+// path and required query string parameters and headers, and the example payload. Applicable to
+// API and actions, the value set on the action takes precedence.
+//
+//        Metadata("swagger:curl", "true")
+//
 // `swagger:tag:xxx`: sets the Swagger object field tag xxx.
 // Applicable to resources and actions.
 //
@@ -67,6 +77,34 @@ import (
 //
 //        Metadata("swagger:extension:x-api", `{"foo":"bar"}`)
 //
+// `deploy:image`: sets the container image that serves the resource. The "goagen deploy" command
+// generates a Dockerfile and a Kubernetes manifest for each resource that sets it.
+// Applicable to resources.
+//
+//        Metadata("deploy:image", "registry.example.com/cellar:1.0")
+//
+// `deploy:replicas`: sets the number of replicas of the Kubernetes deployments generated by the
+// "goagen deploy" command, defaults to 1.
+// Applicable to API and resources.
+//
+//        Metadata("deploy:replicas", "3")
+//
+// `http:cacheable`: set to "false" to prevent the generated clients from caching the responses to
+// the GET requests made to the actions.
+// Applicable to API, resources and actions.
+//
+//        Metadata("http:cacheable", "false")
+//
+// `http:negotiate`: set to "true" to make the generated handlers reject the requests whose
+// Content-Type is not consumed by the action with 415 responses and the requests whose Accept header
+// accepts none of the media types produced by the action with 406 responses before decoding the
+// request body. The response bodies list the supported media types.
+// Applicable to API, resources and actions.
+//
+//        Metadata("http:negotiate", "true")
+//
+// The Extension DSL provides a shorthand for setting swagger extensions.
+//
 // The special key names listed above may be used as follows:
 //
 //        var Account = Type("Account", func() {
@@ -97,6 +135,8 @@ func Metadata(name string, value ...string) {
 		def.Metadata = appendMetadata(def.Metadata, name, value...)
 	case *design.FileServerDefinition:
 		def.Metadata = appendMetadata(def.Metadata, name, value...)
+	case *design.MountDefinition:
+		def.Metadata = appendMetadata(def.Metadata, name, value...)
 	case *design.ResourceDefinition:
 		def.Metadata = appendMetadata(def.Metadata, name, value...)
 	case *design.ResponseDefinition:
@@ -111,3 +151,70 @@ func Metadata(name string, value ...string) {
 		dslengine.IncompatibleDSL()
 	}
 }
+
+// Extension can be used in: API, Resource, Action, Route, Response, Attribute, Security
+//
+// Extension sets a Swagger vendor extension on the definition. The name must start with "x-" and
+// the value may be any value that can be serialized to JSON. Extension is a shorthand for setting
+// the corresponding `swagger:extension:xxx` metadata:
+//
+//        Extension("x-amazon-apigateway-integration", map[string]interface{}{
+//                "type":       "http",
+//                "httpMethod": "GET",
+//        })
+//
+// is equivalent to:
+//
+//        Metadata("swagger:extension:x-amazon-apigateway-integration", `{"httpMethod":"GET","type":"http"}`)
+//
+func Extension(name string, value interface{}) {
+	if !strings.HasPrefix(name, "x-") {
+		dslengine.ReportError(`invalid extension name %#v, must start with "x-"`, name)
+		return
+	}
+	val, err := json.Marshal(value)
+	if err != nil {
+		dslengine.ReportError("invalid value for extension %#v: %s", name, err)
+		return
+	}
+	switch dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition, *design.ResourceDefinition, *design.ActionDefinition,
+		*design.RouteDefinition, *design.ResponseDefinition, *design.AttributeDefinition,
+		*design.SecurityDefinition:
+		Metadata("swagger:extension:"+name, string(val))
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// Audience can be used in: API, Resource, Action
+//
+// Audience declares the audiences of the API Swagger specifications when used in the API DSL.
+// The swagger generator produces one specification per declared audience in addition to the full
+// specification, e.g. swagger_public.json and swagger_public.yaml for the audience "public".
+//
+// Used in a resource or action DSL, Audience lists the audiences of the specifications that
+// document the resource or action. Action audiences override resource audiences. Resources and
+// actions with no audience are documented in the specifications of all audiences. The types that
+// are only used by the actions excluded from a specification are not included in it. Audience is
+// a shorthand for setting the `swagger:audience` metadata:
+//
+//        API("cellar", func() {
+//                Audience("public", "internal")
+//        })
+//
+//        Resource("bottle", func() {
+//                Audience("public")
+//                Action("audit", func() {
+//                        Audience("internal") // Only documented in swagger_internal.json
+//                })
+//        })
+//
+func Audience(names ...string) {
+	switch dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition, *design.ResourceDefinition, *design.ActionDefinition:
+		Metadata(design.AudienceMetadataKey, names...)
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
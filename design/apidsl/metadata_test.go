@@ -150,3 +150,63 @@ var _ = Describe("Metadata", func() {
 	})
 
 })
+
+var _ = Describe("Extension", func() {
+	var name string
+	var value interface{}
+	var api *APIDefinition
+	var rd *ResourceDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	JustBeforeEach(func() {
+		api = API("Example API", func() {
+			Extension(name, value)
+		})
+		rd = Resource("Example Resource", func() {
+			Extension(name, value)
+			Action("Example Action", func() {
+				Extension(name, value)
+				Routing(GET("/"))
+				Params(func() {
+					Param("param", func() {
+						Extension(name, value)
+					})
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with a valid extension name", func() {
+		BeforeEach(func() {
+			name = "x-foo"
+			value = map[string]interface{}{"bar": 1}
+		})
+
+		It("stores the JSON encoded value in the metadata", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			expected := dslengine.MetadataDefinition{"swagger:extension:x-foo": {`{"bar":1}`}}
+			Ω(api.Metadata).Should(Equal(expected))
+			Ω(rd.Metadata).Should(Equal(expected))
+			action := rd.Actions["Example Action"]
+			Ω(action.Metadata).Should(Equal(expected))
+			Ω(action.Params.Type.ToObject()["param"].Metadata).Should(Equal(expected))
+		})
+	})
+
+	Context("with a name missing the x- prefix", func() {
+		BeforeEach(func() {
+			name = "foo"
+			value = "bar"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`must start with "x-"`))
+			Ω(api.Metadata).Should(BeNil())
+		})
+	})
+})
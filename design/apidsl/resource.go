@@ -1,6 +1,8 @@
 package apidsl
 
 import (
+	"strings"
+
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 )
@@ -30,7 +32,9 @@ import (
 //		DefaultMedia(BottleMedia)	// Resource default media type
 //		BasePath("/bottles")		// Common resource action path prefix if not ""
 //		Parent("account")		// Name of parent resource if any
+//		Alias("wine")			// Alternative name of resource if any
 //		CanonicalActionName("get")	// Name of action that returns canonical representation if not "show"
+//		NotFoundActionName("fallback")	// Name of action that handles unmatched paths if any
 //		UseTrait("Authenticated")	// Included trait if any, can appear more than once
 //
 //		Origin("http://swagger.goa.design", func() { // Define CORS policy, may be prefixed with "*" wildcard
@@ -109,6 +113,22 @@ func DefaultMedia(val interface{}, viewName ...string) {
 	}
 }
 
+// Alias can be used in: Resource
+//
+// Alias defines an alternative name for the resource. Lookups by name such as the Parent DSL
+// resolve aliases so that designs referring to a resource by its former name keep working after
+// it is renamed. Aliases must not collide with resource names or with aliases of other resources.
+// Example:
+//
+//	Resource("wine", func() {
+//		Alias("bottle")	// Resource was previously named "bottle"
+//	})
+func Alias(name string) {
+	if r, ok := resourceDefinition(); ok {
+		r.Aliases = append(r.Aliases, name)
+	}
+}
+
 // Parent can be used in: Resource
 //
 // Parent sets the resource parent. The parent resource is used to compute the path to the resource
@@ -119,11 +139,197 @@ func Parent(p string) {
 	}
 }
 
+// Compress can be used in: Resource
+//
+// Compress enables the compression of the request and response bodies of the resource actions
+// using the given algorithms listed in order of preference. Supported algorithms are "gzip",
+// "deflate" and "br". The generated code negotiates the algorithm used to compress the response
+// using the request Accept-Encoding header and decompresses request bodies whose Content-Encoding
+// header matches one of the algorithms. Compression applies to the response body rendered with
+// the content type selected by the regular content negotiation. Example:
+//
+//	Resource("bottle", func() {
+//		Compress("br", "gzip")
+//	})
+//
+// The "br" algorithm requires registering an encoder and a decoder with the
+// github.com/goadesign/goa/middleware/compress package.
+func Compress(algorithms ...string) {
+	for _, a := range algorithms {
+		if !design.IsSupportedCompression(a) {
+			dslengine.ReportError(`invalid compression algorithm "%s", must be one of %s`, a, strings.Join(design.SupportedCompressions, ", "))
+			return
+		}
+	}
+	if r, ok := resourceDefinition(); ok {
+		r.Compression = append(r.Compression, algorithms...)
+	}
+}
+
+// DefaultResponseStatus can be used in: Resource
+//
+// DefaultResponseStatus sets the status code of the success response of the resource actions
+// whose routes use the given HTTP verb and that do not define a success response themselves. The
+// status code must be a 2xx code that has a default response (see Response). Example:
+//
+//	Resource("bottle", func() {
+//		DefaultResponseStatus("POST", 201)   // Actions routed with POST respond with Created
+//		DefaultResponseStatus("DELETE", 204) // Actions routed with DELETE respond with NoContent
+//
+//		Action("create", func() {
+//			Routing(POST(""))
+//			Payload(BottlePayload)
+//		})
+//	})
+func DefaultResponseStatus(verb string, code int) {
+	if r, ok := resourceDefinition(); ok {
+		if r.DefaultResponseStatuses == nil {
+			r.DefaultResponseStatuses = make(map[string]int)
+		}
+		r.DefaultResponseStatuses[strings.ToUpper(verb)] = code
+	}
+}
+
+// NoInheritedErrors can be used in: Resource
+//
+// NoInheritedErrors prevents the resource from inheriting the error responses defined at the API
+// level, generators then only consider the error responses defined by the resource and its actions.
+// Example:
+//
+//	Resource("health", func() {
+//		NoInheritedErrors()
+//	})
+func NoInheritedErrors() {
+	if r, ok := resourceDefinition(); ok {
+		r.NoInheritedErrors = true
+	}
+}
+
+// Servers can be used in: Resource
+//
+// Servers restricts the resource to the API servers with the given names. The schemes of the
+// servers URLs apply to the resource actions that do not define schemes and the documentation
+// lists the resource under these servers only. Child resources inherit the restriction.
+// Example:
+//
+//	API("cellar", func() {
+//		Server("https://api.cellar.com", func() {
+//			Name("public")
+//		})
+//		Server("http://cellar.internal:8080", func() {
+//			Name("internal")
+//		})
+//	})
+//
+//	Resource("admin", func() {
+//		Servers("internal") // Admin endpoints are only exposed on the internal host
+//	})
+func Servers(names ...string) {
+	if r, ok := resourceDefinition(); ok {
+		r.Servers = append(r.Servers, names...)
+	}
+}
+
+// NotFoundActionName can be used in: Resource
+//
+// NotFoundActionName designates the action that handles the requests made to paths under the
+// resource base path that do not match any route. The action is mounted on a catch-all route with
+// the lowest priority for all the HTTP methods listed in design.NotFoundMethods so that it never
+// shadows the routes defined in the design. The unmatched part of the request path is available
+// via the goaNotFoundPath parameter. The action keeps its own routes and must not require
+// parameters other than the ones defined in the resource base path. Example:
+//
+//	Resource("bottle", func() {
+//		BasePath("/bottles")
+//		NotFoundActionName("fallback") // Requests made to unmatched paths under /bottles run "fallback"
+//
+//		Action("fallback", func() {
+//			Routing(GET("/fallback"))
+//			Response(NotFound)
+//		})
+//	})
+func NotFoundActionName(action string) {
+	if r, ok := resourceDefinition(); ok {
+		r.NotFoundActionName = action
+	}
+}
+
+// Mount can be used in: Resource
+//
+// Mount defines a named mount of the resource. The resource actions are also served under the mount
+// base path which replaces the API base path in the action routes, the absolute routes are not
+// mounted. The actions served through the mount share their implementation with the default mount
+// but may use a different security. The generated code defines one Mount<Resource><Mount>Controller
+// function per mount and the Swagger specification tags the mount operations with the mount name.
+// The mount DSL may use BasePath (required), Description, Security, NoSecurity and Metadata.
+// Example:
+//
+//	Resource("bottle", func() {
+//		BasePath("/bottles") // Served under /api/v1/bottles by MountBottleController
+//
+//		Mount("partner", func() {
+//			Description("Partner access")
+//			BasePath("/partner/v1") // Served under /partner/v1/bottles by MountBottlePartnerController
+//			Security(PartnerKey)
+//		})
+//	})
+func Mount(name string, dsl func()) {
+	r, ok := resourceDefinition()
+	if !ok {
+		return
+	}
+	if r.Mount(name) != nil {
+		dslengine.ReportError("mount %#v is defined twice", name)
+		return
+	}
+	m := &design.MountDefinition{Name: name, Parent: r}
+	if !dslengine.Execute(dsl, m) {
+		return
+	}
+	r.Mounts = append(r.Mounts, m)
+}
+
+// StripPrefix can be used in: Resource
+//
+// StripPrefix makes the resource strip its full base path from the request paths before dispatching
+// them. It is used to mount the resource on an external router that forwards the requests made to
+// the base path: the generated code registers the resource handlers with paths relative to the
+// base path and defines a <Resource>Handler function that returns the http.StripPrefix handler to
+// mount on the router. The base path cannot define wildcards. Example:
+//
+//	Resource("bottle", func() {
+//		BasePath("/bottles")
+//		StripPrefix() // BottleHandler strips "/api/bottles" from the request paths
+//	})
+func StripPrefix() {
+	if r, ok := resourceDefinition(); ok {
+		r.StripPrefix = true
+	}
+}
+
 // CanonicalActionName sets the name of the action used to compute the resource collection and
 //
 // resource collection items hrefs. See Resource.
-func CanonicalActionName(a string) {
+//
+// The optional verb arguments set the action as the canonical action for the given HTTP verbs
+// only, for example:
+//
+//	CanonicalActionName("show")
+//	CanonicalActionName("update", "PUT", "PATCH")
+//
+// makes "update" the canonical action for PUT and PATCH requests and "show" the canonical action
+// for all the other verbs.
+func CanonicalActionName(a string, verbs ...string) {
 	if r, ok := resourceDefinition(); ok {
-		r.CanonicalActionName = a
+		if len(verbs) == 0 {
+			r.CanonicalActionName = a
+			return
+		}
+		if r.CanonicalActionNames == nil {
+			r.CanonicalActionNames = make(map[string]string)
+		}
+		for _, v := range verbs {
+			r.CanonicalActionNames[strings.ToUpper(v)] = a
+		}
 	}
 }
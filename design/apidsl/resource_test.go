@@ -61,6 +61,506 @@ var _ = Describe("Resource", func() {
 		})
 	})
 
+	Context("with a name already used by another resource", func() {
+		BeforeEach(func() {
+			name = "users"
+		})
+
+		It("produces an error", func() {
+			Ω(Resource(name, dsl)).Should(BeNil())
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`resource "users" is defined twice`))
+		})
+	})
+
+	Context("with a name that only differs from another resource name by case and separators", func() {
+		BeforeEach(func() {
+			name = "user_accounts"
+			Resource("UserAccounts", func() {})
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`resource name "user_accounts" conflicts with resource "UserAccounts"`))
+		})
+	})
+
+	Context("with a name that only differs from another resource name by case", func() {
+		BeforeEach(func() {
+			name = "useraccounts"
+			Resource("UserAccounts", func() {})
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`resource name "useraccounts" conflicts with resource "UserAccounts"`))
+		})
+	})
+
+	Context("with an alias", func() {
+		BeforeEach(func() {
+			name = "wines"
+			dsl = func() {
+				Alias("bottles")
+				BasePath("/wines")
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+			}
+			Resource("vintages", func() {
+				Parent("bottles")
+				Action("list", func() {
+					Routing(GET("/vintages"))
+				})
+			})
+		})
+
+		It("resolves the resource by its alias", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.Aliases).Should(Equal([]string{"bottles"}))
+			Ω(Design.Resource("bottles")).Should(Equal(res))
+			Ω(Design.Resource("wines")).Should(Equal(res))
+			Ω(Design.Resource("unknown")).Should(BeNil())
+			Ω(Design.Resources["vintages"].Parent()).Should(Equal(res))
+			Ω(Design.Resources["vintages"].FullPath()).Should(Equal("/wines/:id"))
+		})
+	})
+
+	Context("with an API server URL using variables", func() {
+		BeforeEach(func() {
+			API("cellar", func() {
+				BasePath("/api")
+				Server("https://{region}.example.com/{version}/", func() {
+					Variable("region", String, func() {
+						Enum("us", "eu")
+						Default("us")
+					})
+					Variable("version", func() {
+						Default("v1")
+					})
+				})
+			})
+			name = "bottles"
+			dsl = func() {
+				BasePath("/bottles")
+				Action("list", func() {
+					Routing(GET(""))
+				})
+			}
+		})
+
+		It("computes the example base URL using the variable defaults", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Servers[0].DefaultURL()).Should(Equal("https://us.example.com/v1/"))
+			Ω(res.ExampleBaseURL()).Should(Equal("https://us.example.com/v1/api/bottles"))
+		})
+	})
+
+	Context("with an API server URL using a variable without default", func() {
+		BeforeEach(func() {
+			API("cellar", func() {
+				Server("https://{region}.example.com", func() {
+					Variable("region")
+				})
+				Server("https://{zone}.example.com")
+			})
+			name = "bottles"
+			dsl = func() {
+				Action("list", func() {
+					Routing(GET(""))
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`server URL variable "region" must have a default value`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`server URL variable "zone" is not defined`))
+		})
+	})
+
+	Context("with API MIME types", func() {
+		BeforeEach(func() {
+			API("cellar", func() {
+				Consumes("application/json", "application/xml")
+				Produces("application/json", "application/xml")
+			})
+			name = "bottles"
+			dsl = func() {
+				Produces("application/xml")
+				Action("list", func() {
+					Routing(GET(""))
+				})
+				Action("create", func() {
+					Routing(POST(""))
+					Consumes("application/xml")
+					Produces("application/json")
+				})
+			}
+		})
+
+		It("materializes the effective MIME types on the actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(dslengine.Warnings).Should(BeEmpty())
+			list := res.Actions["list"]
+			Ω(list.Consumes).Should(Equal([]string{"application/json", "application/xml"}))
+			Ω(list.Produces).Should(Equal([]string{"application/xml"}))
+			create := res.Actions["create"]
+			Ω(create.Consumes).Should(Equal([]string{"application/xml"}))
+			Ω(create.Produces).Should(Equal([]string{"application/json"}))
+		})
+	})
+
+	Context("with an action producing a MIME type not produced by the API", func() {
+		BeforeEach(func() {
+			API("cellar", func() {
+				Produces("application/json")
+			})
+			name = "bottles"
+			dsl = func() {
+				Action("list", func() {
+					Routing(GET(""))
+					Produces("application/yaml")
+				})
+			}
+		})
+
+		It("records a warning", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(dslengine.Warnings).Should(Equal([]string{
+				`resource "bottles" action "list": MIME type "application/yaml" is not produced by the API`,
+			}))
+		})
+	})
+
+	Context("with an action producing a MIME type specific to the action", func() {
+		BeforeEach(func() {
+			API("cellar", func() {
+				Produces("application/json")
+			})
+			name = "bottles"
+			dsl = func() {
+				Action("list", func() {
+					Routing(GET(""))
+				})
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+				Action("export", func() {
+					Routing(GET("/export"))
+					Produces("text/csv")
+				})
+			}
+		})
+
+		It("overrides the API MIME types for that action only", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.Actions["list"].Produces).Should(Equal([]string{"application/json"}))
+			Ω(res.Actions["show"].Produces).Should(Equal([]string{"application/json"}))
+			Ω(res.Actions["export"].Produces).Should(Equal([]string{"text/csv"}))
+		})
+
+		It("includes the action MIME types in the resource MIME types", func() {
+			Ω(res.ProducedMIMETypes()).Should(Equal([]string{"application/json", "text/csv"}))
+			Ω(res.ConsumedMIMETypes()).Should(ContainElement("application/json"))
+			Ω(res.ConsumedMIMETypes()).ShouldNot(ContainElement("text/csv"))
+		})
+	})
+
+	Context("with actions declared in a different order than their paths", func() {
+		BeforeEach(func() {
+			name = "bottles"
+			dsl = func() {
+				BasePath("/bottles")
+				Action("update", func() {
+					Routing(PATCH("/:id"))
+				})
+				Action("list", func() {
+					Routing(GET(""))
+				})
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+				Action("rate", func() {
+					Routing(PUT("/:id/rate"), GET("/rated"))
+				})
+				Action("create", func() {
+					Routing(POST(""))
+				})
+			}
+		})
+
+		It("lists the actions sorted by path then verb", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			var names []string
+			for _, a := range res.ActionsByPath() {
+				names = append(names, a.Name)
+			}
+			Ω(names).Should(Equal([]string{"list", "create", "show", "update", "rate"}))
+		})
+
+		It("returns the same order on every call", func() {
+			first := res.ActionsByPath()
+			for i := 0; i < 10; i++ {
+				Ω(res.ActionsByPath()).Should(Equal(first))
+			}
+		})
+	})
+
+	Context("with a default response status", func() {
+		BeforeEach(func() {
+			name = "bottles"
+			dsl = func() {
+				DefaultResponseStatus("POST", 201)
+				Action("create", func() {
+					Routing(POST(""))
+				})
+				Action("update", func() {
+					Routing(POST("/:id"))
+					Response(OK)
+				})
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+			}
+		})
+
+		It("applies it to the actions that lack a success response", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.Actions["create"].Responses).Should(HaveKey(Created))
+			Ω(res.Actions["create"].Responses[Created].Status).Should(Equal(201))
+			Ω(res.Actions["update"].Responses).ShouldNot(HaveKey(Created))
+			Ω(res.Actions["show"].Responses).Should(BeEmpty())
+		})
+	})
+
+	Context("with an invalid default response status", func() {
+		BeforeEach(func() {
+			name = "bottles"
+			dsl = func() {
+				DefaultResponseStatus("FETCH", 404)
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid HTTP verb "FETCH"`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid default response status 404 for FETCH"))
+		})
+	})
+
+	Context("with a webhook", func() {
+		BeforeEach(func() {
+			name = "order"
+			dsl = func() {
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+				Webhook("order.shipped", func() {
+					Description("order shipped")
+					Routing(POST("/"))
+					Payload(func() {
+						Member("id", String)
+					})
+					Signature(func() {
+						Header("X-Signature")
+						Algorithm("hmac-sha256")
+					})
+					Response(NoContent)
+				})
+			}
+		})
+
+		It("records the webhook separately from the actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.Actions).Should(HaveLen(1))
+			Ω(res.Webhooks).Should(HaveKey("order.shipped"))
+			w := res.Webhooks["order.shipped"]
+			Ω(w.Webhook).Should(BeTrue())
+			Ω(w.Description).Should(Equal("order shipped"))
+			Ω(w.Payload).ShouldNot(BeNil())
+			Ω(w.Signature.Header).Should(Equal("X-Signature"))
+			Ω(w.Responses).Should(HaveKey(NoContent))
+			Ω(w.Responses[NoContent].Status).Should(Equal(204))
+		})
+	})
+
+	Context("with an invalid webhook", func() {
+		BeforeEach(func() {
+			name = "order"
+			dsl = func() {
+				Webhook("order.shipped", func() {
+					Routing(POST("/:id"))
+					Payload(func() {
+						Member("id", String)
+					})
+					Response(OK, "application/json")
+				})
+			}
+		})
+
+		It("produces errors", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`webhook "order.shipped": webhook route "/:id" cannot define path parameters`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("webhook response OK must be a 2xx acknowledgment with no body"))
+		})
+	})
+
+	Context("with async actions", func() {
+		BeforeEach(func() {
+			name = "order"
+			dsl = func() {
+				Publish("created", func() {
+					Description("order created")
+					Channel("orders.created")
+					Message(String)
+				})
+				Subscribe("paid", func() {
+					Channel("payments.completed")
+					Message(func() {
+						Attribute("order_id", String)
+						Required("order_id")
+					})
+				})
+			}
+		})
+
+		It("records the async actions separately from the actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.Actions).Should(BeEmpty())
+			Ω(res.AsyncActions).Should(HaveLen(2))
+			created := res.AsyncActions["created"]
+			Ω(created.AsyncOperation).Should(Equal(PublishOperation))
+			Ω(created.Channel).Should(Equal("orders.created"))
+			Ω(created.Description).Should(Equal("order created"))
+			paid := res.AsyncActions["paid"]
+			Ω(paid.AsyncOperation).Should(Equal(SubscribeOperation))
+			Ω(paid.Message).ShouldNot(BeNil())
+			Ω(paid.Message.TypeName).Should(Equal("PaidOrderMessage"))
+			Ω(paid.Message.IsRequired("order_id")).Should(BeTrue())
+		})
+	})
+
+	Context("with an async action using HTTP DSLs", func() {
+		BeforeEach(func() {
+			name = "order"
+			dsl = func() {
+				Publish("created", func() {
+					Routing(POST("/"))
+					Payload(String)
+				})
+			}
+		})
+
+		It("produces errors", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("async action must define a channel"))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("Routing cannot be used in async actions, they have no HTTP mapping"))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("Payload cannot be used in async actions, they have no HTTP mapping"))
+		})
+	})
+
+	Context("restricted to a server", func() {
+		BeforeEach(func() {
+			API("cellar", func() {
+				Scheme("https")
+				Server("https://api.cellar.com", func() {
+					Name("public")
+				})
+				Server("http://cellar.internal:8080", func() {
+					Name("internal")
+				})
+			})
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+			})
+			name = "admin"
+			dsl = func() {
+				BasePath("/admin")
+				Servers("internal")
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+			}
+		})
+
+		It("only consults the servers it is restricted to", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			servers := res.EffectiveServers()
+			Ω(servers).Should(HaveLen(1))
+			Ω(servers[0].Name).Should(Equal("internal"))
+			Ω(res.ServerSchemes()).Should(Equal([]string{"http"}))
+			Ω(res.Actions["show"].EffectiveSchemes()).Should(Equal([]string{"http"}))
+			Ω(res.ExampleBaseURL()).Should(Equal("http://cellar.internal:8080/admin"))
+		})
+
+		It("does not affect the other resources", func() {
+			bottle := Design.Resources["bottle"]
+			Ω(bottle.EffectiveServers()).Should(HaveLen(2))
+			Ω(bottle.ServerSchemes()).Should(BeNil())
+			Ω(bottle.Actions["show"].EffectiveSchemes()).Should(Equal([]string{"https"}))
+		})
+	})
+
+	Context("restricted to an unknown server", func() {
+		BeforeEach(func() {
+			API("cellar", func() {
+				Server("https://api.cellar.com", func() {
+					Name("public")
+				})
+			})
+			name = "admin"
+			dsl = func() {
+				Servers("internal")
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`server "internal" is not defined`))
+		})
+	})
+
+	Context("with an alias that collides with a resource name", func() {
+		BeforeEach(func() {
+			name = "wines"
+			dsl = func() {
+				Alias("bottles")
+			}
+			Resource("bottles", func() {})
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`alias "bottles" conflicts with resource "bottles"`))
+		})
+	})
+
+	Context("with an alias used by another resource", func() {
+		BeforeEach(func() {
+			name = "wines"
+			dsl = func() {
+				Alias("bottles")
+			}
+			Resource("spirits", func() {
+				Alias("bottles")
+			})
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`alias "bottles" is already an alias of resource "spirits"`))
+		})
+	})
+
 	Context("with a parent resource that does not exist", func() {
 		const parent = "parent"
 
@@ -133,6 +633,45 @@ var _ = Describe("Resource", func() {
 		})
 	})
 
+	Context("with cached files", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Files("/assets/*filepath", "public/assets", func() {
+					ETag()
+					MaxAge(3600)
+				})
+				Files("/index.html", "public/index.html")
+			}
+		})
+
+		It("sets the caching options", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.FileServers).Should(HaveLen(2))
+			Ω(res.FileServers[0].ETag).Should(BeTrue())
+			Ω(res.FileServers[0].MaxAge).ShouldNot(BeNil())
+			Ω(*res.FileServers[0].MaxAge).Should(Equal(3600))
+			Ω(res.FileServers[1].ETag).Should(BeFalse())
+			Ω(res.FileServers[1].MaxAge).Should(BeNil())
+		})
+	})
+
+	Context("with files using a negative max age", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Files("/index.html", "public/index.html", func() {
+					MaxAge(-1)
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid max age -1, must be positive or zero"))
+		})
+	})
+
 	Context("with a canonical action that does not exist", func() {
 		const can = "can"
 
@@ -168,42 +707,202 @@ var _ = Describe("Resource", func() {
 		})
 	})
 
-	Context("with a base path", func() {
-		const basePath = "basePath"
-
+	Context("with canonical actions per HTTP verb", func() {
 		BeforeEach(func() {
 			name = "foo"
 			dsl = func() {
-				BasePath(basePath)
+				Action("show", func() { Routing(GET("/:id")) })
+				Action("update", func() { Routing(PUT("/:id"), PATCH("/:id")) })
+				CanonicalActionName("update", "put", "PATCH")
 			}
 		})
 
-		It("sets the base path", func() {
+		It("resolves the canonical action by verb", func() {
 			Ω(res).ShouldNot(BeNil())
 			Ω(res.Validate()).ShouldNot(HaveOccurred())
-			Ω(res.BasePath).Should(Equal(basePath))
+			Ω(res.CanonicalActionFor("PUT").Name).Should(Equal("update"))
+			Ω(res.CanonicalActionFor("patch").Name).Should(Equal("update"))
+			Ω(res.CanonicalActionFor("GET").Name).Should(Equal("show"))
 		})
 	})
 
-	Context("with base params", func() {
-		const basePath = "basePath/:paramID"
-
+	Context("with a canonical action that has no route for the verb", func() {
 		BeforeEach(func() {
 			name = "foo"
 			dsl = func() {
-				BasePath(basePath)
-				Params(func() {
-					Param("paramID")
-				})
+				Action("show", func() { Routing(GET("/:id")) })
+				CanonicalActionName("show", "DELETE")
 			}
 		})
 
-		It("sets the base path and params", func() {
+		It("produces an invalid resource definition", func() {
 			Ω(res).ShouldNot(BeNil())
-			Ω(res.Validate()).ShouldNot(HaveOccurred())
-			Ω(res.BasePath).Should(Equal(basePath))
-			Ω(res.Params).ShouldNot(BeNil())
-			Ω(res.Params.Type).ShouldNot(BeNil())
+			err := res.Validate()
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(`canonical action "show" for DELETE has no DELETE route`))
+		})
+	})
+
+	Context("with a canonical action for an invalid verb", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Action("show", func() { Routing(GET("/:id")) })
+				CanonicalActionName("show", "FETCH")
+			}
+		})
+
+		It("produces an invalid resource definition", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a not found action that does not exist", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				NotFoundActionName("fallback")
+			}
+		})
+
+		It("produces an invalid resource definition", func() {
+			Ω(res.NotFoundActionName).Should(Equal("fallback"))
+			err := res.Validate()
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(`unknown not found action "fallback"`))
+		})
+	})
+
+	Context("with a not found action", func() {
+		var fallback func()
+
+		BeforeEach(func() {
+			name = "foo"
+			fallback = func() { Routing(GET("/fallback")) }
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			res = Resource(name, func() {
+				BasePath("/bottles")
+				NotFoundActionName("fallback")
+				Action("show", func() { Routing(GET("/:id")) })
+				Action("fallback", fallback)
+			})
+			dslengine.Run()
+		})
+
+		It("produces a valid resource definition with catch-all routes", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.NotFoundAction()).Should(Equal(res.Actions["fallback"]))
+			routes := res.NotFoundRoutes()
+			Ω(routes).Should(HaveLen(len(NotFoundMethods)))
+			for i, r := range routes {
+				Ω(r.Verb).Should(Equal(NotFoundMethods[i]))
+				Ω(r.FullPath()).Should(Equal("/bottles/*goaNotFoundPath"))
+			}
+			Ω(res.Actions["fallback"].Routes).Should(HaveLen(1))
+		})
+
+		Context("that requires a parameter", func() {
+			BeforeEach(func() {
+				fallback = func() {
+					Routing(GET("/fallback/:name"))
+					Params(func() {
+						Param("name")
+						Required("name")
+					})
+				}
+			})
+
+			It("produces an invalid resource definition", func() {
+				err := res.Validate()
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring(`not found action cannot require parameter "name"`))
+			})
+		})
+
+		Context("whose routes collide with another catch-all route", func() {
+			BeforeEach(func() {
+				fallback = func() { Routing(GET("/*path")) }
+			})
+
+			It("produces an invalid resource definition", func() {
+				err := res.Validate()
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring("not found routes collide with"))
+			})
+		})
+	})
+
+	Context("with a base path", func() {
+		const basePath = "basePath"
+
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				BasePath(basePath)
+			}
+		})
+
+		It("sets the base path", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.BasePath).Should(Equal(basePath))
+		})
+	})
+
+	Context("with compression", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Compress("br", "gzip")
+			}
+		})
+
+		It("records the enabled algorithms", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.Compression).Should(Equal([]string{"br", "gzip"}))
+		})
+	})
+
+	Context("with an unknown compression algorithm", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Compress("gzip", "lzma")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid compression algorithm "lzma"`))
+			Ω(res.Compression).Should(BeEmpty())
+		})
+	})
+
+	Context("with base params", func() {
+		const basePath = "basePath/:paramID"
+
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				BasePath(basePath)
+				Params(func() {
+					Param("paramID")
+				})
+			}
+		})
+
+		It("sets the base path and params", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.BasePath).Should(Equal(basePath))
+			Ω(res.Params).ShouldNot(BeNil())
+			Ω(res.Params.Type).ShouldNot(BeNil())
 			Ω(res.Params.Type.ToObject()).Should(HaveKey("paramID"))
 		})
 	})
@@ -340,3 +1039,537 @@ var _ = Describe("Resource", func() {
 		})
 	})
 })
+
+var _ = Describe("UndocumentedActions", func() {
+	var strict bool
+
+	var res *ResourceDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		strict = false
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			if strict {
+				RequireDescriptions()
+			}
+		})
+		res = Resource("bottle", func() {
+			Action("show", func() {
+				Description("Retrieve a bottle by ID")
+				Routing(GET("/:id"))
+			})
+			Action("list", func() {
+				Routing(GET(""))
+			})
+			Action("delete", func() {
+				Description("  ")
+				Routing(DELETE("/:id"))
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("returns the actions with no description", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		var names []string
+		for _, a := range res.UndocumentedActions() {
+			names = append(names, a.Name)
+		}
+		Ω(names).Should(Equal([]string{"delete", "list"}))
+	})
+
+	Context("with descriptions required", func() {
+		BeforeEach(func() {
+			strict = true
+		})
+
+		It("produces an error for each undocumented action", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`action "list"`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`action "delete"`))
+			Ω(dslengine.Errors.Error()).ShouldNot(ContainSubstring(`action "show"`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("missing description"))
+		})
+	})
+})
+
+var _ = Describe("StatusCodes", func() {
+	var res *ResourceDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("test", func() {})
+		res = Resource("bottle", func() {
+			Response(InternalServerError)
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(OK)
+				Response(NotFound)
+			})
+			Action("create", func() {
+				Routing(POST(""))
+				Response(Created)
+				Response(NotFound)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("returns the sorted distinct status codes of the resource responses", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(res.StatusCodes()).Should(Equal([]int{200, 201, 404, 500}))
+	})
+
+	Context("with API error responses", func() {
+		var health *ResourceDefinition
+
+		BeforeEach(func() {
+			dslengine.Reset()
+			API("test", func() {
+				Response(Unauthorized)
+			})
+			res = Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response(OK)
+				})
+			})
+			health = Resource("health", func() {
+				NoInheritedErrors()
+				Action("check", func() {
+					Routing(GET("/health"))
+					Response(OK)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("includes the API error responses inherited by the actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.StatusCodes()).Should(Equal([]int{200, 401}))
+			Ω(health.StatusCodes()).Should(Equal([]int{200}))
+		})
+	})
+})
+
+var _ = Describe("NoInheritedErrors", func() {
+	var bottle, health *ResourceDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("test", func() {
+			Response(InternalServerError)
+			Response(Unauthorized)
+		})
+		bottle = Resource("bottle", func() {
+			Response(NotFound)
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(OK)
+			})
+		})
+		health = Resource("health", func() {
+			NoInheritedErrors()
+			Response(ServiceUnavailable)
+			Action("check", func() {
+				Routing(GET("/health"))
+				Response(OK)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("inherits the API errors by default", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(bottle.InheritsAPIErrors()).Should(BeTrue())
+		Ω(responseNames(bottle.AllErrors())).Should(Equal([]string{InternalServerError, NotFound, Unauthorized}))
+		Ω(bottle.Actions["show"].Responses).Should(HaveKey(Unauthorized))
+	})
+
+	It("only returns the resource errors when opting out", func() {
+		Ω(health.InheritsAPIErrors()).Should(BeFalse())
+		Ω(responseNames(health.AllErrors())).Should(Equal([]string{ServiceUnavailable}))
+		Ω(health.Actions["check"].Responses).ShouldNot(HaveKey(Unauthorized))
+	})
+
+	Context("with an API response that is not an error", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			API("test", func() {
+				Response(OK)
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("API responses must be error responses, status 200 is not a 4xx or 5xx status code"))
+		})
+	})
+})
+
+// responseNames returns the names of the given responses.
+func responseNames(resps []*ResponseDefinition) []string {
+	names := make([]string, len(resps))
+	for i, r := range resps {
+		names[i] = r.Name
+	}
+	return names
+}
+
+var _ = Describe("StripPrefix", func() {
+	var basePath string
+
+	var res *ResourceDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		basePath = "/bottles"
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			BasePath("/api")
+		})
+		res = Resource("bottle", func() {
+			BasePath(basePath)
+			StripPrefix()
+			Action("list", func() {
+				Routing(GET(""))
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the option and computes the stripped prefix", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(res.StripPrefix).Should(BeTrue())
+		Ω(res.StrippedPrefix()).Should(Equal("/api/bottles"))
+	})
+
+	It("registers the routes relative to the prefix", func() {
+		Ω(res.Actions["list"].Routes[0].MuxPath()).Should(Equal("/"))
+		Ω(res.Actions["show"].Routes[0].MuxPath()).Should(Equal("/:id"))
+		Ω(res.Actions["show"].Routes[0].FullPath()).Should(Equal("/api/bottles/:id"))
+		Ω(res.PreflightPaths()).Should(Equal([]string{"/", "/:id"}))
+	})
+
+	Context("with a base path defining wildcards", func() {
+		BeforeEach(func() {
+			basePath = "/cellars/:cellarID/bottles"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`cannot strip base path "/api/cellars/:cellarID/bottles", it defines wildcards`))
+		})
+	})
+})
+
+var _ = Describe("Mount", func() {
+	var mountDSL func()
+
+	var res *ResourceDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		mountDSL = func() {
+			Description("Partner access")
+			BasePath("/partner/v1")
+			Security("partner_key")
+			Metadata("swagger:extension:x-partner", "true")
+		}
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			BasePath("/api/v1")
+			APIKeySecurity("partner_key", func() {
+				Query("key")
+			})
+		})
+		res = Resource("bottle", func() {
+			BasePath("/bottles")
+			Mount("partner", mountDSL)
+			Action("list", func() {
+				Routing(GET(""))
+			})
+			Action("show", func() {
+				Routing(GET("/:id"), GET("//bottle/:id"))
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("records the mount", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(res.Mounts).Should(HaveLen(1))
+		m := res.Mount("partner")
+		Ω(m).ShouldNot(BeNil())
+		Ω(m.Parent).Should(Equal(res))
+		Ω(m.Description).Should(Equal("Partner access"))
+		Ω(m.BasePath).Should(Equal("/partner/v1"))
+		Ω(m.Security).ShouldNot(BeNil())
+		Ω(m.Security.Scheme.SchemeName).Should(Equal("partner_key"))
+		Ω(m.Metadata).Should(HaveKey("swagger:extension:x-partner"))
+	})
+
+	It("serves the actions under the mount base path", func() {
+		m := res.Mount("partner")
+		routes := m.Routes(res.Actions["show"])
+		Ω(routes).Should(HaveLen(1))
+		Ω(routes[0].Verb).Should(Equal("GET"))
+		Ω(routes[0].FullPath()).Should(Equal("/partner/v1/bottles/:id"))
+		Ω(routes[0].MuxPath()).Should(Equal("/partner/v1/bottles/:id"))
+		Ω(res.Actions["list"].FullPaths()).Should(Equal([]string{"/api/v1/bottles", "/partner/v1/bottles"}))
+		Ω(res.Actions["show"].FullPaths()).Should(Equal([]string{"/api/v1/bottles/:id", "/bottle/:id", "/partner/v1/bottles/:id"}))
+		Ω(m.PreflightPaths()).Should(Equal([]string{"/partner/v1/bottles", "/partner/v1/bottles/:id"}))
+	})
+
+	It("overrides the security of the actions", func() {
+		m := res.Mount("partner")
+		Ω(res.Actions["show"].Security).Should(BeNil())
+		Ω(m.ActionSecurity(res.Actions["show"])).Should(Equal(m.Security))
+	})
+
+	Context("with NoSecurity", func() {
+		BeforeEach(func() {
+			mountDSL = func() {
+				BasePath("/partner/v1")
+				NoSecurity()
+			}
+		})
+
+		It("serves the actions without security", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.Mount("partner").ActionSecurity(res.Actions["show"])).Should(BeNil())
+		})
+	})
+
+	Context("with no base path", func() {
+		BeforeEach(func() {
+			mountDSL = func() {
+				Security("partner_key")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`mount "partner" of resource "bottle": missing base path`))
+		})
+	})
+
+	Context("with a base path that serves the routes of the default mount", func() {
+		BeforeEach(func() {
+			mountDSL = func() {
+				BasePath("/api/v1")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`route GET "/api/v1/bottles" of action "list" conflicts with route GET "/api/v1/bottles" of action "list" served by the default mount`))
+		})
+	})
+
+	Context("with mounts whose routes conflict", func() {
+		BeforeEach(func() {
+			mountDSL = func() {
+				BasePath("/partner")
+			}
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			API("test", func() {
+				BasePath("/api/v1")
+			})
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Mount("partner", mountDSL)
+				Mount("legacy", func() {
+					BasePath("/partner/")
+				})
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`mount "legacy" of resource "bottle": route GET "/partner/bottles/:id" of action "show" conflicts with route GET "/partner/bottles/:id" of action "show" served by mount "partner"`))
+		})
+	})
+
+	Context("defined twice", func() {
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			API("test", func() {})
+			Resource("bottle", func() {
+				Mount("partner", func() { BasePath("/partner") })
+				Mount("partner", func() { BasePath("/other") })
+				Action("show", func() { Routing(GET("/:id")) })
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`mount "partner" is defined twice`))
+		})
+	})
+})
+
+var _ = Describe("Response names", func() {
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			ResponseTemplate("Gone", func() {
+				Status(410)
+			})
+		})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+			})
+			dsl()
+		})
+		dslengine.Run()
+	})
+
+	Context("with two responses named not_found", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Response("not_found", func() {
+					Status(404)
+				})
+				Response("not_found", func() {
+					Status(404)
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("response not_found is defined twice"))
+		})
+	})
+
+	Context("with response names that only differ by case or separators", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Response("not_found", func() {
+					Status(404)
+				})
+				Response("NotFound")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`response name "not_found" conflicts with response "NotFound"`))
+		})
+	})
+
+	Context("with an action response conflicting with a resource response", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Response("not_found", func() {
+					Status(404)
+				})
+				Action("delete", func() {
+					Routing(DELETE("/:id"))
+					Response("NotFound")
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`action "delete": response name "NotFound" conflicts with response "not_found"`))
+		})
+	})
+
+	Context("with a response changing the status of the API response", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Response("Gone", func() {
+					Status(404)
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`response "Gone" has status 404 but the API response with the same name has status 410`))
+		})
+	})
+
+	Context("with a response reusing the API response", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Response("Gone")
+			}
+		})
+
+		It("does not produce an error", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("RegisterResourceValidator", func() {
+	var unregister func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		unregister = RegisterResourceValidator(func(r *ResourceDefinition) *dslengine.ValidationErrors {
+			verr := new(dslengine.ValidationErrors)
+			if r.Description == "" {
+				verr.Add(r, "missing description")
+			}
+			return verr.AsError()
+		})
+		API("test", func() {})
+		Resource("bottle", func() {
+			Description("A bottle of wine")
+			BasePath("/bottles")
+			Action("show", func() { Routing(GET("/:id")) })
+		})
+		Resource("cellar", func() {
+			BasePath("/cellars")
+			Action("show", func() { Routing(GET("/:id")) })
+		})
+	})
+
+	AfterEach(func() {
+		unregister()
+	})
+
+	It("merges the errors returned by the validator", func() {
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+		Ω(dslengine.Errors.Error()).Should(ContainSubstring(`resource "cellar": missing description`))
+		Ω(dslengine.Errors.Error()).ShouldNot(ContainSubstring(`resource "bottle"`))
+	})
+
+	It("stops running the validator once unregistered", func() {
+		unregister()
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+	})
+})
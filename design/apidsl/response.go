@@ -5,7 +5,7 @@ import (
 	"github.com/goadesign/goa/dslengine"
 )
 
-// Response can be used in: Action, Resource
+// Response can be used in: API, Action, Resource
 //
 // Response implements the response definition DSL. Response takes the name of the response as
 // first parameter. goa defines all the standard HTTP status name as global variables so they can be
@@ -72,6 +72,10 @@ import (
 // media type defined in the API DSL. In this latter case goa uses the media type definition to
 // generate helper response methods. These methods know how to render the views defined on the media
 // type and run the validations defined in the media type during rendering.
+//
+// Responses defined in the API DSL must be error responses (4xx or 5xx status codes). They apply
+// to the actions of all the resources that do not use NoInheritedErrors unless the action or its
+// resource define a response with the same name.
 func Response(name string, paramsAndDSL ...interface{}) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.ActionDefinition:
@@ -83,7 +87,7 @@ func Response(name string, paramsAndDSL ...interface{}) {
 			return
 		}
 		if resp := executeResponseDSL(name, paramsAndDSL...); resp != nil {
-			if resp.Status == 200 && resp.MediaType == "" {
+			if resp.Status == 200 && resp.MediaType == "" && resp.BodyAttribute == "" {
 				resp.MediaType = def.Parent.MediaType
 				resp.ViewName = def.Parent.DefaultViewName
 			}
@@ -100,7 +104,7 @@ func Response(name string, paramsAndDSL ...interface{}) {
 			return
 		}
 		if resp := executeResponseDSL(name, paramsAndDSL...); resp != nil {
-			if resp.Status == 200 && resp.MediaType == "" {
+			if resp.Status == 200 && resp.MediaType == "" && resp.BodyAttribute == "" {
 				resp.MediaType = def.MediaType
 				resp.ViewName = def.DefaultViewName
 			}
@@ -108,6 +112,19 @@ func Response(name string, paramsAndDSL ...interface{}) {
 			def.Responses[name] = resp
 		}
 
+	case *design.APIDefinition:
+		if def.Responses == nil {
+			def.Responses = make(map[string]*design.ResponseDefinition)
+		}
+		if _, ok := def.Responses[name]; ok {
+			dslengine.ReportError("response %s is defined twice", name)
+			return
+		}
+		if resp := executeResponseDSL(name, paramsAndDSL...); resp != nil {
+			resp.Parent = def
+			def.Responses[name] = resp
+		}
+
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -180,5 +197,8 @@ func executeResponseDSL(name string, paramsAndDSL ...interface{}) *design.Respon
 		resp.Type = dt
 		resp.Standard = false
 	}
+	if resp.BodyAttribute != "" {
+		responseBody(resp)
+	}
 	return resp
 }
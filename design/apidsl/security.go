@@ -5,7 +5,7 @@ import (
 	"github.com/goadesign/goa/dslengine"
 )
 
-// Security can be used in: API, Action, Files, Resource
+// Security can be used in: API, Action, Files, Resource, Mount
 //
 // Security defines an authentication requirements to access a goa Action.  When defined on a
 // Resource, it applies to all Actions, unless overriden by individual actions.  When defined at the
@@ -56,6 +56,8 @@ func Security(scheme interface{}, dsl ...func()) {
 		parent.Security = def
 	case *design.ResourceDefinition:
 		parent.Security = def
+	case *design.MountDefinition:
+		parent.Security = def
 	case *design.APIDefinition:
 		parent.Security = def
 	default:
@@ -64,7 +66,7 @@ func Security(scheme interface{}, dsl ...func()) {
 	}
 }
 
-// NoSecurity can be used in: API, Action, Files, Resource
+// NoSecurity can be used in: API, Action, Files, Resource, Mount
 //
 // NoSecurity resets the authentication schemes for an Action or a Resource. It also prevents
 // fallback to Resource or API-defined Security.
@@ -81,6 +83,8 @@ func NoSecurity() {
 		parent.Security = def
 	case *design.ResourceDefinition:
 		parent.Security = def
+	case *design.MountDefinition:
+		parent.Security = def
 	default:
 		dslengine.IncompatibleDSL()
 		return
@@ -294,6 +298,76 @@ func Scope(name string, desc ...string) {
 	}
 }
 
+// RequiredScopes can be used in: Security
+//
+// RequiredScopes defines a boolean expression of the scopes required to access the action. The
+// expression is built with AllScopes and AnyScope, a string argument requires a single scope. The expression
+// is required in addition to the scopes listed with Scope. All the scopes referenced by the
+// expression must be declared on the security scheme. Example:
+//
+//    Security("jwt", func() {
+//        RequiredScopes(AnyScope("admin", AllScopes("editor", "org:member")))
+//    })
+//
+func RequiredScopes(expr interface{}) {
+	current, ok := dslengine.CurrentDefinition().(*design.SecurityDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if current.ScopeExpr != nil {
+		dslengine.ReportError("required scopes already defined")
+		return
+	}
+	current.ScopeExpr = scopeOperand(expr)
+}
+
+// AllScopes can be used in: RequiredScopes
+//
+// AllScopes builds a scope expression that requires all the given operands. The operands are
+// scope names or expressions built with AllScopes or AnyScope.
+func AllScopes(operands ...interface{}) *design.ScopeExpr {
+	return scopeExpr(design.ScopeAnd, operands)
+}
+
+// AnyScope can be used in: RequiredScopes
+//
+// AnyScope builds a scope expression that requires any of the given operands. The operands are
+// scope names or expressions built with AllScopes or AnyScope.
+func AnyScope(operands ...interface{}) *design.ScopeExpr {
+	return scopeExpr(design.ScopeOr, operands)
+}
+
+// scopeExpr builds a scope expression with the given operator and operands.
+func scopeExpr(op design.ScopeOperator, operands []interface{}) *design.ScopeExpr {
+	if len(operands) < 2 {
+		dslengine.ReportError("scope expression requires at least two operands")
+		return nil
+	}
+	e := &design.ScopeExpr{Operator: op}
+	for _, o := range operands {
+		so := scopeOperand(o)
+		if so == nil {
+			return nil
+		}
+		e.Operands = append(e.Operands, so)
+	}
+	return e
+}
+
+// scopeOperand returns the scope expression corresponding to the given operand.
+func scopeOperand(o interface{}) *design.ScopeExpr {
+	switch val := o.(type) {
+	case string:
+		return &design.ScopeExpr{Operator: design.ScopeLeaf, Scope: val}
+	case *design.ScopeExpr:
+		return val
+	default:
+		dslengine.ReportError("invalid scope expression operand %#v, must be a string or an expression built with And or Or", o)
+		return nil
+	}
+}
+
 // inHeader is called by `Header()`, see documentation there.
 func inHeader(headerName string) {
 	if current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
@@ -175,6 +175,66 @@ var _ = Describe("Security", func() {
 
 	})
 
+	Context("with required scopes", func() {
+		var expr func() *ScopeExpr
+
+		BeforeEach(func() {
+			expr = func() *ScopeExpr { return AnyScope("admin", AllScopes("editor", "org:member")) }
+		})
+
+		JustBeforeEach(func() {
+			API("", func() {
+				JWTSecurity("jwt", func() {
+					Header("Authorization")
+					Scope("admin")
+					Scope("editor")
+					Scope("org:member")
+				})
+			})
+			Resource("one", func() {
+				Action("first", func() {
+					Routing(GET("/first"))
+					Security("jwt", func() {
+						RequiredScopes(expr())
+					})
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("stores the scope expression", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			e := Design.Resources["one"].Actions["first"].Security.ScopeExpr
+			Ω(e).ShouldNot(BeNil())
+			Ω(e.String()).Should(Equal("admin OR (editor AND org:member)"))
+			Ω(e.Scopes()).Should(Equal([]string{"admin", "editor", "org:member"}))
+			Ω(e.Eval(map[string]bool{"editor": true, "org:member": true})).Should(BeTrue())
+			Ω(e.Eval(map[string]bool{"editor": true})).Should(BeFalse())
+		})
+
+		Context("referencing an undeclared scope", func() {
+			BeforeEach(func() {
+				expr = func() *ScopeExpr { return AnyScope("admin", "owner") }
+			})
+
+			It("fails", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`scope "owner" used in required scopes "admin OR owner" is not declared by security scheme "jwt"`))
+			})
+		})
+
+		Context("with a single operand", func() {
+			BeforeEach(func() {
+				expr = func() *ScopeExpr { return AllScopes("admin") }
+			})
+
+			It("fails", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("scope expression requires at least two operands"))
+			})
+		})
+	})
+
 	Context("with resources and actions", func() {
 		It("should fallback properly to lower-level security", func() {
 			API("", func() {
@@ -3,10 +3,15 @@ package design
 import (
 	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/dimfeld/httppath"
 	"github.com/goadesign/goa/dslengine"
@@ -37,6 +42,18 @@ type (
 		Produces []*EncodingDefinition
 		// Origins defines the CORS policies that apply to this API.
 		Origins map[string]*CORSDefinition
+		// ResponseHeaders lists the constant headers sent with all the API responses.
+		ResponseHeaders *AttributeDefinition
+		// ValidationErrorStatus is the status code of the responses sent when a request fails
+		// validation, 0 means 400, see InvalidRequestStatus.
+		ValidationErrorStatus int
+		// RequestIDHeader is the name of the header used to read or generate the request
+		// correlation ID, empty means DefaultRequestIDHeader, see RequestIDHeaderName.
+		RequestIDHeader string
+		// TrailingSlash is the policy used to handle the request paths that differ from a
+		// route path only by a trailing slash, one of TrailingSlashRedirect,
+		// TrailingSlashStrict or TrailingSlashIgnore. Empty keeps the mux default behavior.
+		TrailingSlash string
 		// TermsOfService describes or links to the API terms of service
 		TermsOfService string
 		// Contact provides the API users with contact information
@@ -45,6 +62,11 @@ type (
 		License *LicenseDefinition
 		// Docs points to the API external documentation
 		Docs *DocsDefinition
+		// Servers lists the servers hosting the API in the order they are first declared,
+		// servers have distinct URLs.
+		Servers []*ServerDefinition
+		// HealthCheck is the API health check endpoint if any
+		HealthCheck *HealthCheckDefinition
 		// Resources is the set of exposed resources indexed by name
 		Resources map[string]*ResourceDefinition
 		// Types indexes the user defined types by name
@@ -55,6 +77,9 @@ type (
 		Traits map[string]*dslengine.TraitDefinition
 		// Responses available to all API actions indexed by name
 		Responses map[string]*ResponseDefinition
+		// ResponseExamples lists the example bodies of the API responses indexed by status,
+		// see ResponseDefinition.BodyExample.
+		ResponseExamples map[int]*ResponseExampleDefinition
 		// Response template factories available to all API actions indexed by name
 		ResponseTemplates map[string]*ResponseTemplateDefinition
 		// Built-in responses
@@ -74,6 +99,8 @@ type (
 		Security *SecurityDefinition
 		// NoExamples indicates whether to bypass automatic example generation.
 		NoExamples bool
+		// RequireDescriptions causes validation to fail if an action has no description.
+		RequireDescriptions bool
 
 		// rand is the random generator used to generate examples.
 		rand *RandomGenerator
@@ -105,12 +132,34 @@ type (
 		URL string `json:"url,omitempty"`
 	}
 
+	// ServerDefinition describes a server hosting the API. Documentation generators may group
+	// servers by label to list the API environments (e.g. "production" or "staging").
+	ServerDefinition struct {
+		// URL of the server
+		URL string `json:"url"`
+		// Name identifies the server in generated clients, must be unique.
+		Name string `json:"name,omitempty"`
+		// Label is the name of the environment, defaults to the URL host.
+		Label string `json:"label,omitempty"`
+		// Description of server
+		Description string `json:"description,omitempty"`
+		// Variables describes the variables used in the URL, each variable must have a
+		// default value.
+		Variables *AttributeDefinition `json:"variables,omitempty"`
+		// Default is true if generated clients send requests to the server when no
+		// environment is specified, see APIDefinition.DefaultServer.
+		Default bool `json:"default,omitempty"`
+	}
+
 	// ResourceDefinition describes a REST resource.
 	// It defines both a media type and a set of actions that can be executed through HTTP
 	// requests.
 	ResourceDefinition struct {
 		// Resource name
 		Name string
+		// Aliases lists alternative names the resource may be looked up with, e.g. names
+		// used before a rename.
+		Aliases []string
 		// Schemes is the supported API URL schemes
 		Schemes []string
 		// Common URL prefix to all resource action HTTP requests
@@ -129,12 +178,22 @@ type (
 		Actions map[string]*ActionDefinition
 		// FileServers is the list of static asset serving endpoints
 		FileServers []*FileServerDefinition
+		// HealthCheck is the resource health check endpoint if any
+		HealthCheck *HealthCheckDefinition
 		// Action with canonical resource path
 		CanonicalActionName string
+		// CanonicalActionNames indexes the names of the canonical actions set for specific
+		// HTTP verbs by verb, see CanonicalActionFor.
+		CanonicalActionNames map[string]string
+		// Action handling requests made to unmatched paths under the resource base path if any
+		NotFoundActionName string
 		// Map of response definitions that apply to all actions indexed by name.
 		Responses map[string]*ResponseDefinition
 		// Request headers that apply to all actions.
 		Headers *AttributeDefinition
+		// ResponseHeaders lists the constant headers sent with all the resource responses,
+		// they override the API response headers with the same names.
+		ResponseHeaders *AttributeDefinition
 		// Origins defines the CORS policies that apply to this resource.
 		Origins map[string]*CORSDefinition
 		// DSLFunc contains the DSL used to create this definition if any.
@@ -144,6 +203,56 @@ type (
 		// Security defines security requirements for the Resource,
 		// for actions that don't define one themselves.
 		Security *SecurityDefinition
+		// Compression lists the algorithms used to compress the request and response bodies
+		// of the resource actions in order of preference.
+		Compression []string
+		// Consumes lists the MIME types of the request bodies accepted by the resource
+		// actions, overrides the API Consumes.
+		Consumes []string
+		// Produces lists the MIME types of the response bodies produced by the resource
+		// actions, overrides the API Produces.
+		Produces []string
+		// DefaultResponseStatuses maps HTTP verbs to the status code of the success
+		// response added to the actions that do not define one.
+		DefaultResponseStatuses map[string]int
+		// Webhooks lists the requests sent by the API to URLs registered by clients
+		// indexed by name.
+		Webhooks map[string]*ActionDefinition
+		// AsyncActions lists the actions that publish or consume messages through a
+		// message broker indexed by name. Async actions have no HTTP mapping.
+		AsyncActions map[string]*ActionDefinition
+		// Servers lists the names of the API servers the resource is restricted to. The
+		// resource is exposed on all the API servers if empty.
+		Servers []string
+		// StripPrefix is true if the resource full base path is stripped from the request
+		// paths before they are dispatched, see StrippedPrefix.
+		StripPrefix bool
+		// NoInheritedErrors is true if the resource does not inherit the error responses
+		// defined at the API level, see InheritsAPIErrors.
+		NoInheritedErrors bool
+		// Mounts lists the named mounts that also serve the resource actions under other
+		// base paths, see MountDefinition.
+		Mounts []*MountDefinition
+	}
+
+	// MountDefinition describes a named mount of a resource. The resource actions are also
+	// served under the mount base path which replaces the API base path in the action routes.
+	// The actions share their implementation with the default mount but may use a different
+	// security.
+	MountDefinition struct {
+		// Name is the mount name used to tag the mount operations in the API docs.
+		Name string
+		// Description for docs
+		Description string
+		// BasePath is the path that replaces the API base path in the mount routes.
+		BasePath string
+		// Security defines the security requirements of the actions served through the
+		// mount, it overrides the security of the actions if set.
+		Security *SecurityDefinition
+		// Metadata is a list of key/value pairs
+		Metadata dslengine.MetadataDefinition
+		// Parent is the mounted resource.
+		Parent *ResourceDefinition
 	}
 
 	// CORSDefinition contains the definition for a specific origin CORS policy.
@@ -164,6 +273,9 @@ type (
 		Credentials bool
 		// Sets Whether the Origin string is a regular expression
 		Regexp bool
+		// List of origins set with the CORS DSL, each origin is recorded as a separate
+		// policy in the parent Origins once the DSL has run.
+		Origins []string
 	}
 
 	// EncodingDefinition defines an encoder supported by the API.
@@ -196,6 +308,14 @@ type (
 		MediaType string
 		// Response view name if MediaType is MediaTypeDefinition
 		ViewName string
+		// BodyAttribute is the name of the media type attribute used as response body if
+		// any. Type describes the attribute type once the response DSL has executed.
+		BodyAttribute string
+		// ContentType is the value of the Content-Type header of responses whose Type
+		// overrides the media type if any, e.g. "image/png" for Bytes bodies.
+		ContentType string
+		// Example is the example body of the response if any.
+		Example interface{}
 		// Response header definitions
 		Headers *AttributeDefinition
 		// Parent action or resource
@@ -206,6 +326,17 @@ type (
 		Standard bool
 	}
 
+	// ResponseExampleDefinition defines the example body shared by the API responses with a
+	// given status.
+	ResponseExampleDefinition struct {
+		// Status is the HTTP status of the responses
+		Status int
+		// Description of example
+		Description string
+		// Value is the example body
+		Value interface{}
+	}
+
 	// ResponseTemplateDefinition defines a response template.
 	// A response template is a function that takes an arbitrary number
 	// of strings and returns a response definition.
@@ -241,18 +372,120 @@ type (
 		Params *AttributeDefinition
 		// Query string parameters only
 		QueryParams *AttributeDefinition
+		// NoInheritedParams lists the path parameters of the resource path that are removed
+		// from the action routes. The action binds them to query string parameters or
+		// headers instead.
+		NoInheritedParams []string
+		// OmittedParams lists the resource parameters that do not apply to the action.
+		OmittedParams []string
+		// OmittedHeaders lists the resource headers that do not apply to the action.
+		OmittedHeaders []string
 		// Payload blueprint (request body) if any
 		Payload *UserTypeDefinition
 		// PayloadOptional is true if the request payload is optional, false otherwise.
 		PayloadOptional bool
 		// PayloadOptional is true if the request payload is multipart, false otherwise.
 		PayloadMultipart bool
+		// PayloadBody is the name of the payload attribute used as request body if any.
+		// Payload describes the attribute type once the action DSL has executed.
+		PayloadBody string
+		// Payloads lists the request bodies accepted by the action indexed by content type
+		// if any. Payload is then an object with one attribute per content type, only one
+		// of which is set.
+		Payloads []*PayloadDefinition
 		// Request headers that need to be made available to action
 		Headers *AttributeDefinition
 		// Metadata is a list of key/value pairs
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the action
 		Security *SecurityDefinition
+		// Signature describes the signature of the request bodies if any
+		Signature *SignatureDefinition
+		// ViewParam is the name of the query string parameter used by clients to select the
+		// view that renders the ViewedResponse if any. Clients may also use the ViewHeader
+		// request header.
+		ViewParam string
+		// Consumes lists the MIME types of the request bodies accepted by the action.
+		// Finalize sets it to the resource or API MIME types if not set explicitly.
+		Consumes []string
+		// Produces lists the MIME types of the response bodies produced by the action.
+		// Finalize sets it to the resource or API MIME types if not set explicitly.
+		Produces []string
+		// Streaming is true if the action streams its results over a websocket
+		// connection or a streamed response body. Finalize computes it from the action
+		// effective schemes and streaming result.
+		Streaming bool
+		// StreamingResult is the type of the results streamed by the action if any.
+		StreamingResult *UserTypeDefinition
+		// Result is the type of the final result sent after the streamed results if
+		// any, only valid together with StreamingResult.
+		Result *UserTypeDefinition
+		// Webhook is true if the action describes a request sent by the API to a
+		// URL registered by a client rather than a request handled by the API.
+		Webhook bool
+		// AsyncOperation is PublishOperation or SubscribeOperation for async actions and
+		// empty for HTTP actions.
+		AsyncOperation string
+		// Channel is the name of the channel async actions publish to or consume from.
+		Channel string
+		// Message describes the messages published or consumed by async actions.
+		Message *UserTypeDefinition
+		// Static is the canned response of static actions. Static actions are served by
+		// the generated code and are not implemented by the controller.
+		Static *StaticResponseDefinition
+		// Idempotent is true if the action is explicitly marked as idempotent, see
+		// IsIdempotent.
+		Idempotent bool
+		// Links lists the links from the action responses to other actions.
+		Links []*ActionLinkDefinition
+	}
+
+	// RouteTableEntry pairs a route with the action that handles it, see
+	// ResourceDefinition.RouteTable.
+	RouteTableEntry struct {
+		// Verb is the route HTTP verb.
+		Verb string
+		// Path is the route full path.
+		Path string
+		// Method is the name of the action that handles the route as returned by
+		// goa.ContextAction, the controller method is its Goified version.
+		Method string
+		// Action is the action that handles the route.
+		Action *ActionDefinition
+		// Route is the route definition.
+		Route *RouteDefinition
+	}
+
+	// StaticResponseDefinition describes the response returned by a static action.
+	StaticResponseDefinition struct {
+		// Status is the HTTP status of the response
+		Status int
+		// Body is the response body, it must be compatible with the type of the action
+		// response with the same status.
+		Body interface{}
+	}
+
+	// SignatureDefinition describes the signature of the action request bodies. Clients
+	// compute the signature after encoding the body and servers verify it before decoding.
+	SignatureDefinition struct {
+		// Header is the name of the request header containing the signature
+		Header string
+		// Algorithm is the name of the algorithm used to compute the signature, one of
+		// SupportedSignatureAlgorithms
+		Algorithm string
+		// Parent action
+		Parent *ActionDefinition
+	}
+
+	// PayloadDefinition describes the request body accepted by an action for a given
+	// content type.
+	PayloadDefinition struct {
+		// ContentType is the media type of the request body, e.g. "text/plain"
+		ContentType string
+		// Attribute describes the request body
+		Attribute *AttributeDefinition
+		// Parent action
+		Parent *ActionDefinition
 	}
 
 	// FileServerDefinition defines an endpoint that servers static assets.
@@ -271,6 +504,24 @@ type (
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the file server.
 		Security *SecurityDefinition
+		// ETag causes the responses to include an ETag header computed from the served file
+		// modification time and size.
+		ETag bool
+		// MaxAge is the value in seconds of the max-age directive of the Cache-Control
+		// response header if not nil.
+		MaxAge *int
+	}
+
+	// HealthCheckDefinition describes an endpoint that responds to GET requests with a 200
+	// status and a static body. Health check endpoints do not require a controller
+	// implementation.
+	HealthCheckDefinition struct {
+		// Parent is the API or resource definition
+		Parent dslengine.Definition
+		// Path is the request path relative to the parent base path
+		Path string
+		// Body is the response body
+		Body string
 	}
 
 	// LinkDefinition defines a media type link, it specifies a URL to a related resource.
@@ -286,6 +537,19 @@ type (
 		Parent *MediaTypeDefinition
 	}
 
+	// ActionLinkDefinition defines a link from the responses of an action to another action, e.g.
+	// the "owner" link of the bottle "show" action may target the account "show" action.
+	ActionLinkDefinition struct {
+		// Rel is the link relation type.
+		Rel string
+		// Resource is the name of the resource of the target action.
+		Resource string
+		// Action is the name of the target action.
+		Action string
+		// Parent is the action that defines the link.
+		Parent *ActionDefinition
+	}
+
 	// ViewDefinition defines which members and links to render when building a response.
 	// The view is a JSON object whose property names must match the names of the parent media
 	// type members.
@@ -335,6 +599,10 @@ type (
 		NonZeroAttributes map[string]bool
 		// DSLFunc contains the initialization DSL. This is used for user types.
 		DSLFunc func()
+		// Provenance maps the names of the properties inherited from a reference type to
+		// the name of the type that contributed them, see AttributeProperty. It is only
+		// recorded when TrackProvenance is true.
+		Provenance map[string]string
 	}
 
 	// ContainerDefinition defines a generic container definition that contains attributes.
@@ -356,6 +624,9 @@ type (
 	// ActionIterator is the type of functions given to IterateActions.
 	ActionIterator func(a *ActionDefinition) error
 
+	// ServerIterator is the type of functions given to IterateServers.
+	ServerIterator func(s *ServerDefinition) error
+
 	// FileServerIterator is the type of functions given to IterateFileServers.
 	FileServerIterator func(f *FileServerDefinition) error
 
@@ -612,6 +883,56 @@ func (a *APIDefinition) MediaTypeWithIdentifier(id string) *MediaTypeDefinition
 	return nil
 }
 
+// Routes returns the routes of all the API actions followed by the routes of the health check
+// endpoints. The routes are sorted by resource and action names.
+func (a *APIDefinition) Routes() []*RouteDefinition {
+	var routes []*RouteDefinition
+	a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(ac *ActionDefinition) error {
+			routes = append(routes, ac.Routes...)
+			return nil
+		})
+	})
+	if a.HealthCheck != nil {
+		routes = append(routes, a.HealthCheck.Route())
+	}
+	a.IterateResources(func(r *ResourceDefinition) error {
+		if r.HealthCheck != nil {
+			routes = append(routes, r.HealthCheck.Route())
+		}
+		return nil
+	})
+	return routes
+}
+
+// InvalidRequestStatus returns the HTTP status code of the responses sent when a request fails
+// validation, 400 unless overridden with ValidationErrorStatus.
+func (a *APIDefinition) InvalidRequestStatus() int {
+	if a.ValidationErrorStatus == 0 {
+		return http.StatusBadRequest
+	}
+	return a.ValidationErrorStatus
+}
+
+// RequestIDHeaderName returns the name of the header that carries the request correlation ID,
+// DefaultRequestIDHeader unless overridden with RequestIDHeader.
+func (a *APIDefinition) RequestIDHeaderName() string {
+	if a.RequestIDHeader == "" {
+		return DefaultRequestIDHeader
+	}
+	return a.RequestIDHeader
+}
+
+// HasAsyncActions returns true if at least one resource defines publish or subscribe actions.
+func (a *APIDefinition) HasAsyncActions() bool {
+	for _, r := range a.Resources {
+		if len(r.AsyncActions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // IterateResources calls the given iterator passing in each resource sorted in alphabetical order.
 // Iteration stops if an iterator returns an error and in this case IterateResources returns that
 // error.
@@ -625,12 +946,10 @@ func (a *APIDefinition) IterateResources(it ResourceIterator) error {
 	// Iterate parent resources first so that action parameters are
 	// finalized prior to child actions needing them.
 	isParent := func(p, c *ResourceDefinition) bool {
-		par := c.Parent()
-		for par != nil {
+		for _, par := range c.ParentChain() {
 			if par == p {
 				return true
 			}
-			par = par.Parent()
 		}
 		return false
 	}
@@ -651,11 +970,93 @@ func (a *APIDefinition) IterateResources(it ResourceIterator) error {
 	return nil
 }
 
+// Resource returns the resource with the given name or alias, nil if there isn't one.
+func (a *APIDefinition) Resource(name string) *ResourceDefinition {
+	if r, ok := a.Resources[name]; ok {
+		return r
+	}
+	for _, r := range a.Resources {
+		for _, alias := range r.Aliases {
+			if alias == name {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// PruneResources removes all the resources from the API definition except the ones with the given
+// names and their parent resources. User types, media types and API level definitions are kept so
+// that the pruned design remains consistent. PruneResources returns an error if a name does not
+// correspond to an existing resource.
+func (a *APIDefinition) PruneResources(names ...string) error {
+	keep := make(map[string]bool)
+	for _, n := range names {
+		r := a.Resource(n)
+		if r == nil {
+			return fmt.Errorf("unknown resource %#v", n)
+		}
+		for r != nil && !keep[r.Name] {
+			keep[r.Name] = true
+			r = r.Parent()
+		}
+	}
+	for n := range a.Resources {
+		if !keep[n] {
+			delete(a.Resources, n)
+		}
+	}
+	return nil
+}
+
 // DSL returns the initialization DSL.
 func (a *APIDefinition) DSL() func() {
 	return a.DSLFunc
 }
 
+// Prepare normalizes the API base path so that it always starts with "/", an empty base path
+// becomes "/". It replaces the VersionPlaceholder in the base path with the major API version if
+// the API defines a version. It also normalizes the server URLs, see NormalizeServerURL, and labels
+// the servers that don't have an explicit label with their host.
+func (a *APIDefinition) Prepare() {
+	if !strings.HasPrefix(a.BasePath, "/") {
+		a.BasePath = "/" + a.BasePath
+	}
+	if a.Version != "" {
+		a.BasePath = strings.Replace(a.BasePath, VersionPlaceholder, versionSegment(a.Version), -1)
+	}
+	for _, s := range a.Servers {
+		s.URL = NormalizeServerURL(s.URL)
+		if s.Label == "" {
+			s.Label = s.Host()
+		}
+	}
+}
+
+// versionSegment returns the base path segment corresponding to the given API version: the major
+// version prefixed with "v", e.g. "v2" for "2.0" or "v2.1".
+func versionSegment(version string) string {
+	major := strings.TrimLeft(version, "vV")
+	if i := strings.Index(major, "."); i >= 0 {
+		major = major[:i]
+	}
+	return "v" + major
+}
+
+// ServerLabels returns the distinct labels of the API servers in the order the servers are
+// defined.
+func (a *APIDefinition) ServerLabels() []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, s := range a.Servers {
+		if !seen[s.Label] {
+			seen[s.Label] = true
+			labels = append(labels, s.Label)
+		}
+	}
+	return labels
+}
+
 // Finalize sets the Consumes and Produces fields to the defaults if empty.
 // Also it records built-in media types that are used by the user design.
 func (a *APIDefinition) Finalize() {
@@ -748,6 +1149,181 @@ func (r *ResourceDefinition) IterateActions(it ActionIterator) error {
 	return nil
 }
 
+// RouteTable returns the routes of the resource actions paired with the actions that handle them.
+// The entries are sorted by full path then by HTTP verb then by action name so that the table is
+// the same for a given design. The routes of the webhooks and async actions are not included.
+func (r *ResourceDefinition) RouteTable() []*RouteTableEntry {
+	var entries []*RouteTableEntry
+	r.IterateActions(func(a *ActionDefinition) error {
+		for _, ro := range a.Routes {
+			entries = append(entries, &RouteTableEntry{
+				Verb:   ro.Verb,
+				Path:   ro.FullPath(),
+				Method: a.Name,
+				Action: a,
+				Route:  ro,
+			})
+		}
+		return nil
+	})
+	sort.SliceStable(entries, func(i, j int) bool {
+		ei, ej := entries[i], entries[j]
+		if ei.Path != ej.Path {
+			return ei.Path < ej.Path
+		}
+		if ei.Verb != ej.Verb {
+			return ei.Verb < ej.Verb
+		}
+		return ei.Method < ej.Method
+	})
+	return entries
+}
+
+// ActionsByPath returns the resource actions sorted by the full path of their first route then by
+// HTTP verb. Actions that have the same path and verb are sorted by name and actions with no route
+// come last. Use IterateActions to list the actions in alphabetical order.
+func (r *ResourceDefinition) ActionsByPath() []*ActionDefinition {
+	actions := make([]*ActionDefinition, 0, len(r.Actions))
+	for _, a := range r.Actions {
+		actions = append(actions, a)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		ai, aj := actions[i], actions[j]
+		if len(ai.Routes) == 0 || len(aj.Routes) == 0 {
+			if len(ai.Routes) != len(aj.Routes) {
+				return len(aj.Routes) == 0
+			}
+			return ai.Name < aj.Name
+		}
+		ri, rj := ai.Routes[0], aj.Routes[0]
+		if pi, pj := ri.FullPath(), rj.FullPath(); pi != pj {
+			return pi < pj
+		}
+		if ri.Verb != rj.Verb {
+			return ri.Verb < rj.Verb
+		}
+		return ai.Name < aj.Name
+	})
+	return actions
+}
+
+// UndocumentedActions returns the resource actions that have no description sorted in
+// alphabetical order. Documentation quality checks may use it to list the actions to document,
+// see also APIDefinition.RequireDescriptions.
+func (r *ResourceDefinition) UndocumentedActions() []*ActionDefinition {
+	var actions []*ActionDefinition
+	r.IterateActions(func(a *ActionDefinition) error {
+		if strings.TrimSpace(a.Description) == "" {
+			actions = append(actions, a)
+		}
+		return nil
+	})
+	return actions
+}
+
+// StatusCodes returns the sorted list of distinct HTTP status codes of the responses that the
+// resource actions may write, including the responses defined at the resource level and the API
+// error responses inherited by the actions (see AllErrors). Generators may use it to produce
+// exhaustive switch statements over the response status.
+func (r *ResourceDefinition) StatusCodes() []int {
+	seen := make(map[int]bool)
+	var codes []int
+	addCode := func(code int) {
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	add := func(resps map[string]*ResponseDefinition) {
+		for _, resp := range resps {
+			addCode(resp.Status)
+		}
+	}
+	add(r.Responses)
+	for _, resp := range r.AllErrors() {
+		addCode(resp.Status)
+	}
+	for _, a := range r.Actions {
+		add(a.Responses)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+// InheritsAPIErrors returns true if the error responses defined at the API level apply to the
+// resource, that is unless the resource uses NoInheritedErrors.
+func (r *ResourceDefinition) InheritsAPIErrors() bool {
+	return !r.NoInheritedErrors
+}
+
+// AllErrors returns the error responses (responses with a 4xx or 5xx status) that apply to all the
+// resource actions sorted by name: the error responses defined by the resource and, if the resource
+// inherits them, the error responses defined by the API that the resource does not override.
+func (r *ResourceDefinition) AllErrors() []*ResponseDefinition {
+	errs := make(map[string]*ResponseDefinition)
+	if r.InheritsAPIErrors() && Design != nil {
+		for n, resp := range Design.Responses {
+			if resp.Status >= 400 {
+				errs[n] = resp
+			}
+		}
+	}
+	for n, resp := range r.Responses {
+		delete(errs, n)
+		if resp.Status >= 400 {
+			errs[n] = resp
+		}
+	}
+	names := make([]string, 0, len(errs))
+	for n := range errs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	res := make([]*ResponseDefinition, len(names))
+	for i, n := range names {
+		res[i] = errs[n]
+	}
+	return res
+}
+
+// IterateWebhooks calls the given iterator passing in each resource webhook sorted in alphabetical
+// order. Iteration stops if an iterator returns an error and in this case IterateWebhooks returns
+// that error.
+func (r *ResourceDefinition) IterateWebhooks(it ActionIterator) error {
+	names := make([]string, len(r.Webhooks))
+	i := 0
+	for n := range r.Webhooks {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := it(r.Webhooks[n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IterateAsyncActions calls the given iterator passing in each resource async action sorted in
+// alphabetical order. Iteration stops if an iterator returns an error and in this case
+// IterateAsyncActions returns that error.
+func (r *ResourceDefinition) IterateAsyncActions(it ActionIterator) error {
+	names := make([]string, len(r.AsyncActions))
+	i := 0
+	for n := range r.AsyncActions {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := it(r.AsyncActions[n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // IterateFileServers calls the given iterator passing each resource file server sorted by file
 // path. Iteration stops if an iterator returns an error and in this case IterateFileServers returns
 // that error.
@@ -761,6 +1337,16 @@ func (r *ResourceDefinition) IterateFileServers(it FileServerIterator) error {
 	return nil
 }
 
+// Mount returns the named mount of the resource with the given name, nil if there isn't one.
+func (r *ResourceDefinition) Mount(name string) *MountDefinition {
+	for _, m := range r.Mounts {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
 // IterateHeaders calls the given iterator passing in each response sorted in alphabetical order.
 // Iteration stops if an iterator returns an error and in this case IterateHeaders returns that
 // error.
@@ -779,6 +1365,85 @@ func (r *ResourceDefinition) CanonicalAction() *ActionDefinition {
 	return ca
 }
 
+// CanonicalActionFor returns the canonical action of the resource for the given HTTP verb: the
+// action set for the verb with CanonicalActionName if any, the resource canonical action otherwise
+// (see CanonicalAction). The verb is case insensitive.
+func (r *ResourceDefinition) CanonicalActionFor(verb string) *ActionDefinition {
+	if name, ok := r.CanonicalActionNames[strings.ToUpper(verb)]; ok {
+		return r.Actions[name]
+	}
+	return r.CanonicalAction()
+}
+
+// HasStreamingActions returns true if at least one of the resource actions streams its
+// results. It relies on the Streaming flag computed when the actions are finalized.
+func (r *ResourceDefinition) HasStreamingActions() bool {
+	for _, a := range r.Actions {
+		if a.Streaming {
+			return true
+		}
+	}
+	return false
+}
+
+// NotFoundAction returns the action that handles requests made to paths under the resource base
+// path that do not match any route, nil if there isn't one. See NotFoundRoutes.
+func (r *ResourceDefinition) NotFoundAction() *ActionDefinition {
+	if r.NotFoundActionName == "" {
+		return nil
+	}
+	return r.Actions[r.NotFoundActionName]
+}
+
+// NotFoundRoutes returns the catch-all routes that map the requests made to unmatched paths under
+// the resource base path to the not found action, one per HTTP method. The routes are not part of
+// the action routes so that they do not show up in the API documentation and clients.
+// NotFoundRoutes returns nil if the resource does not define a not found action.
+func (r *ResourceDefinition) NotFoundRoutes() []*RouteDefinition {
+	a := r.NotFoundAction()
+	if a == nil {
+		return nil
+	}
+	routes := make([]*RouteDefinition, len(NotFoundMethods))
+	for i, verb := range NotFoundMethods {
+		routes[i] = &RouteDefinition{Verb: verb, Path: "/*" + NotFoundWildcard, Parent: a}
+	}
+	return routes
+}
+
+// StrippedPrefix returns the prefix stripped from the request paths when the resource is mounted
+// on an external router, that is the resource full base path without trailing slash. It returns
+// the empty string if the resource does not use StripPrefix.
+func (r *ResourceDefinition) StrippedPrefix() string {
+	if !r.StripPrefix {
+		return ""
+	}
+	if p := r.FullPath(); p != "/" {
+		return trimTrailingSlash(p)
+	}
+	return ""
+}
+
+// muxPath returns the path p relative to the prefix stripped by the resource r if any. It returns
+// p if r is nil, does not strip its base path or if p is not under the prefix.
+func muxPath(r *ResourceDefinition, p string) string {
+	if r == nil {
+		return p
+	}
+	prefix := r.StrippedPrefix()
+	if prefix == "" || !strings.HasPrefix(p, prefix) {
+		return p
+	}
+	rel := p[len(prefix):]
+	if rel == "" {
+		return "/"
+	}
+	if rel[0] != '/' {
+		return p
+	}
+	return rel
+}
+
 // URITemplate returns a URI template to this resource.
 // The result is the empty string if the resource does not have a "show" action
 // and does not define a different canonical action.
@@ -790,14 +1455,57 @@ func (r *ResourceDefinition) URITemplate() string {
 	return ca.Routes[0].FullPath()
 }
 
+// pathCleaner is the function used to normalize the paths returned by the FullPath methods.
+var pathCleaner = httppath.Clean
+
+// SetPathCleaner sets the function used to normalize the paths returned by the FullPath methods of
+// the resource, route and health check definitions. The default is httppath.Clean, passing nil
+// restores it.
+func SetPathCleaner(cleaner func(string) string) {
+	if cleaner == nil {
+		cleaner = httppath.Clean
+	}
+	pathCleaner = cleaner
+}
+
+// ExampleBaseURL returns the URL to the resource actions made of the URL of the first server
+// exposing the resource, with the variables replaced by their default values, followed by the
+// resource full path. The API host and first scheme are used instead of the server URL if the API
+// defines no server.
+func (r *ResourceDefinition) ExampleBaseURL() string {
+	return r.ExampleServerURL() + r.FullPath()
+}
+
+// ExampleServerURL returns the URL of the first server exposing the resource with the variables
+// replaced by their default values and without trailing slash. The URL is built from the API host
+// and first scheme if the API defines no server, ExampleServerURL returns an empty string if the
+// API defines no host either.
+func (r *ResourceDefinition) ExampleServerURL() string {
+	var base string
+	if servers := r.EffectiveServers(); len(servers) > 0 {
+		base = servers[0].DefaultURL()
+	} else if Design.Host != "" {
+		scheme := "http"
+		if len(Design.Schemes) > 0 {
+			scheme = Design.Schemes[0]
+		}
+		base = scheme + "://" + Design.Host
+	}
+	return strings.TrimSuffix(base, "/")
+}
+
 // FullPath computes the base path to the resource actions concatenating the API and parent resource
 // base paths as needed.
 func (r *ResourceDefinition) FullPath() string {
 	if strings.HasPrefix(r.BasePath, "//") {
-		return httppath.Clean(r.BasePath)
+		return pathCleaner(r.BasePath)
 	}
 	var basePath string
 	if p := r.Parent(); p != nil {
+		if r.hasCyclicParent() {
+			// Reported by validation, avoid infinite recursion.
+			return pathCleaner(path.Join(Design.BasePath, r.BasePath))
+		}
 		if ca := p.CanonicalAction(); ca != nil {
 			if routes := ca.Routes; len(routes) > 0 {
 				// Note: all these tests should be true at code generation time
@@ -809,19 +1517,113 @@ func (r *ResourceDefinition) FullPath() string {
 	} else {
 		basePath = Design.BasePath
 	}
-	return httppath.Clean(path.Join(basePath, r.BasePath))
+	return pathCleaner(path.Join(basePath, r.BasePath))
 }
 
 // Parent returns the parent resource if any, nil otherwise.
 func (r *ResourceDefinition) Parent() *ResourceDefinition {
 	if r.ParentName != "" {
-		if parent, ok := Design.Resources[r.ParentName]; ok {
-			return parent
-		}
+		return Design.Resource(r.ParentName)
 	}
 	return nil
 }
 
+// ParentChain returns the resource ancestors starting with the immediate parent and ending with
+// the root resource. ParentChain stops at the first resource already visited so that it terminates
+// on cyclic parent definitions.
+func (r *ResourceDefinition) ParentChain() []*ResourceDefinition {
+	var chain []*ResourceDefinition
+	seen := map[*ResourceDefinition]bool{r: true}
+	for p := r.Parent(); p != nil && !seen[p]; p = p.Parent() {
+		seen[p] = true
+		chain = append(chain, p)
+	}
+	return chain
+}
+
+// AllResponseHeaders returns the constant headers sent with all the resource responses: the API
+// response headers merged with the resource response headers, nil if there are none.
+func (r *ResourceDefinition) AllResponseHeaders() *AttributeDefinition {
+	headers := Object{}
+	if Design != nil && Design.ResponseHeaders != nil {
+		for n, h := range Design.ResponseHeaders.Type.ToObject() {
+			headers[n] = h
+		}
+	}
+	if r.ResponseHeaders != nil {
+		for n, h := range r.ResponseHeaders.Type.ToObject() {
+			headers[n] = h
+		}
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return &AttributeDefinition{Type: headers}
+}
+
+// StaticResponseHeaders returns the values of the headers returned by AllResponseHeaders indexed
+// by name.
+func (r *ResourceDefinition) StaticResponseHeaders() map[string]string {
+	return staticHeaders(r.AllResponseHeaders())
+}
+
+// EffectiveServers returns the API servers that expose the resource: the servers named by the
+// resource or by its closest parent restricted to specific servers, all the API servers otherwise.
+func (r *ResourceDefinition) EffectiveServers() []*ServerDefinition {
+	names := r.restrictedServers()
+	if len(names) == 0 {
+		return Design.Servers
+	}
+	var servers []*ServerDefinition
+	for _, s := range Design.Servers {
+		for _, n := range names {
+			if s.Name == n {
+				servers = append(servers, s)
+				break
+			}
+		}
+	}
+	return servers
+}
+
+// ServerSchemes returns the distinct URL schemes of the servers the resource is restricted to in
+// the order the servers are defined, nil if the resource is not restricted to specific servers.
+func (r *ResourceDefinition) ServerSchemes() []string {
+	if len(r.restrictedServers()) == 0 {
+		return nil
+	}
+	var schemes []string
+	seen := make(map[string]bool)
+	for _, s := range r.EffectiveServers() {
+		u, err := url.Parse(s.DefaultURL())
+		if err != nil || u.Scheme == "" || seen[u.Scheme] {
+			continue
+		}
+		seen[u.Scheme] = true
+		schemes = append(schemes, u.Scheme)
+	}
+	return schemes
+}
+
+// restrictedServers returns the names of the servers the resource or its closest restricted parent
+// is restricted to.
+func (r *ResourceDefinition) restrictedServers() []string {
+	names := r.Servers
+	for _, p := range r.ParentChain() {
+		if len(names) > 0 {
+			break
+		}
+		names = p.Servers
+	}
+	return names
+}
+
+// hasCyclicParent returns true if the resource ancestors form a cycle.
+func (r *ResourceDefinition) hasCyclicParent() bool {
+	chain := r.ParentChain()
+	return len(chain) > 0 && chain[len(chain)-1].Parent() != nil
+}
+
 // AllOrigins compute all CORS policies for the resource taking into account any API policy.
 // The result is sorted alphabetically by policy origin.
 func (r *ResourceDefinition) AllOrigins() []*CORSDefinition {
@@ -846,7 +1648,8 @@ func (r *ResourceDefinition) AllOrigins() []*CORSDefinition {
 	return cors
 }
 
-// PreflightPaths returns the paths that should handle OPTIONS requests.
+// PreflightPaths returns the paths that should handle OPTIONS requests. The paths are relative to
+// the stripped prefix if the resource uses StripPrefix, see RouteDefinition.MuxPath.
 func (r *ResourceDefinition) PreflightPaths() []string {
 	var paths []string
 	r.IterateActions(func(a *ActionDefinition) error {
@@ -855,7 +1658,7 @@ func (r *ResourceDefinition) PreflightPaths() []string {
 				continue
 			}
 			found := false
-			fp := r.FullPath()
+			fp := r.MuxPath()
 			for _, p := range paths {
 				if fp == p {
 					found = true
@@ -870,7 +1673,7 @@ func (r *ResourceDefinition) PreflightPaths() []string {
 	})
 	r.IterateFileServers(func(fs *FileServerDefinition) error {
 		found := false
-		fp := fs.RequestPath
+		fp := fs.MuxPath()
 		for _, p := range paths {
 			if fp == p {
 				found = true
@@ -894,25 +1697,24 @@ func (r *ResourceDefinition) DSL() func() {
 // parameters, initializes querystring parameters, sets path parameters as non zero attributes
 // and sets the fallbacks for security schemes.
 func (r *ResourceDefinition) Finalize() {
-	meta := r.Metadata["swagger:generate"]
 	r.IterateFileServers(func(f *FileServerDefinition) error {
-		if meta != nil {
-			if _, ok := f.Metadata["swagger:generate"]; !ok {
-				f.Metadata["swagger:generate"] = meta
-			}
-		}
 		f.Finalize()
 		return nil
 	})
 	r.IterateActions(func(a *ActionDefinition) error {
-		if meta != nil {
-			if _, ok := a.Metadata["swagger:generate"]; !ok {
-				a.Metadata["swagger:generate"] = meta
-			}
-		}
 		a.Finalize()
 		return nil
 	})
+	r.IterateWebhooks(func(w *ActionDefinition) error {
+		w.finalizeWebhook()
+		return nil
+	})
+	r.IterateAsyncActions(func(a *ActionDefinition) error {
+		if a.Message != nil {
+			a.Message.Finalize()
+		}
+		return nil
+	})
 }
 
 // UserTypes returns all the user types used by the resource action payloads and parameters.
@@ -929,16 +1731,86 @@ func (r *ResourceDefinition) UserTypes() map[string]*UserTypeDefinition {
 	return types
 }
 
-// byParent makes it possible to sort resources - parents first the children.
-type byParent []*ResourceDefinition
-
-func (p byParent) Len() int      { return len(p) }
-func (p byParent) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
-func (p byParent) Less(i, j int) bool {
-	for k := 0; k < i; k++ {
-		// We need to inspect _all_ previous fields to see if they are a parent. Sort doesn't do this.
-		if p[i].Name == p[k].ParentName {
-			return true
+// MediaTypes returns the sorted list of MIME types of the request bodies and responses of the
+// resource actions, error responses included. The request MIME types of an action with a payload
+// are the content types of its Payloads if any or else the MIME types it consumes, inherited from
+// the resource or API if not set on the action. Parameters are removed from the MIME types.
+func (r *ResourceDefinition) MediaTypes() []string {
+	seen := make(map[string]bool)
+	add := func(identifier string) {
+		if identifier == "" {
+			return
+		}
+		if base, _, err := mime.ParseMediaType(identifier); err == nil {
+			identifier = base
+		}
+		seen[identifier] = true
+	}
+	r.IterateActions(func(a *ActionDefinition) error {
+		if len(a.Payloads) > 0 {
+			for _, p := range a.Payloads {
+				add(p.ContentType)
+			}
+		} else if a.Payload != nil {
+			for _, mt := range a.consumedMIMETypes() {
+				add(mt)
+			}
+		}
+		for _, resp := range a.Responses {
+			add(resp.MediaType)
+		}
+		return nil
+	})
+	mediaTypes := make([]string, 0, len(seen))
+	for mt := range seen {
+		mediaTypes = append(mediaTypes, mt)
+	}
+	sort.Strings(mediaTypes)
+	return mediaTypes
+}
+
+// ConsumedMIMETypes returns the sorted MIME types consumed by the resource actions. Each action
+// consumes the MIME types listed in its own Consumes DSL or inherited from the resource or API so
+// that the list includes the action specific MIME types.
+func (r *ResourceDefinition) ConsumedMIMETypes() []string {
+	return r.actionMIMETypes(func(a *ActionDefinition) []string { return a.Consumes })
+}
+
+// ProducedMIMETypes returns the sorted MIME types produced by the resource actions. Each action
+// produces the MIME types listed in its own Produces DSL or inherited from the resource or API so
+// that the list includes the action specific MIME types.
+func (r *ResourceDefinition) ProducedMIMETypes() []string {
+	return r.actionMIMETypes(func(a *ActionDefinition) []string { return a.Produces })
+}
+
+// actionMIMETypes returns the sorted union of the MIME types returned by mimeTypes for each
+// resource action.
+func (r *ResourceDefinition) actionMIMETypes(mimeTypes func(*ActionDefinition) []string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	r.IterateActions(func(a *ActionDefinition) error {
+		for _, mt := range mimeTypes(a) {
+			if !seen[mt] {
+				seen[mt] = true
+				res = append(res, mt)
+			}
+		}
+		return nil
+	})
+	sort.Strings(res)
+	return res
+}
+
+// byParent makes it possible to sort resources - parents first the children.
+type byParent []*ResourceDefinition
+
+func (p byParent) Len() int      { return len(p) }
+func (p byParent) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byParent) Less(i, j int) bool {
+	for k := 0; k < i; k++ {
+		// We need to inspect _all_ previous fields to see if they are a parent. Sort doesn't do this.
+		if p[i].Name == p[k].ParentName {
+			return true
 		}
 	}
 
@@ -1074,6 +1946,27 @@ func (a *AttributeDefinition) IsInterface(attName string) bool {
 	return att.Type.Kind() == AnyKind
 }
 
+// TimeFormat returns the encoding of the values of a DateTime or Duration attribute set with the
+// "format:time" metadata: one of TimeFormatRFC3339 (the default), TimeFormatUnix or
+// TimeFormatUnixMilli for DateTime attributes and DurationFormatString (the default) or
+// DurationFormatMilli for Duration attributes. TimeFormat returns the empty string for attributes
+// of other types.
+func (a *AttributeDefinition) TimeFormat() string {
+	var def string
+	switch a.Type.Kind() {
+	case DateTimeKind:
+		def = TimeFormatRFC3339
+	case DurationKind:
+		def = DurationFormatString
+	default:
+		return ""
+	}
+	if f, ok := a.Metadata.Last("format:time"); ok {
+		return f
+	}
+	return def
+}
+
 // IsFile returns true if the attribute is of type File or if any its children attributes (if any) is.
 func (a *AttributeDefinition) IsFile(attName string) bool {
 	if !a.Type.IsObject() {
@@ -1164,6 +2057,21 @@ func (a *AttributeDefinition) IsReadOnly() bool {
 	return false
 }
 
+// SetWriteOnly marks the attribute as write-only: it is accepted in request bodies but never
+// rendered in responses.
+func (a *AttributeDefinition) SetWriteOnly() {
+	if a.Metadata == nil {
+		a.Metadata = map[string][]string{}
+	}
+	a.Metadata["swagger:write-only"] = nil
+}
+
+// IsWriteOnly returns true if attribute is write-only (set using SetWriteOnly() method)
+func (a *AttributeDefinition) IsWriteOnly() bool {
+	_, ok := a.Metadata["swagger:write-only"]
+	return ok
+}
+
 func (a *AttributeDefinition) arrayExample(rand *RandomGenerator, seen []string) interface{} {
 	ary := a.Type.ToArray()
 	ln := newExampleGenerator(a, rand).ExampleLength()
@@ -1373,6 +2281,146 @@ func (d *DocsDefinition) Context() string {
 	return fmt.Sprintf("documentation for %s", Design.Name)
 }
 
+// IterateServers calls the given iterator passing in each API server in the order the servers are
+// declared. Generated code relies on this order being stable, e.g. to list client environments.
+// Iteration stops if an iterator returns an error and in this case IterateServers returns that
+// error.
+func (a *APIDefinition) IterateServers(it ServerIterator) error {
+	for _, s := range a.Servers {
+		if err := it(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServerByURL returns the API server with the given URL, nil if there is none. The URLs are
+// compared in their normalized form so that "https://example.com:443" designates the server
+// "https://example.com".
+func (a *APIDefinition) ServerByURL(url string) *ServerDefinition {
+	url = NormalizeServerURL(url)
+	for _, s := range a.Servers {
+		if NormalizeServerURL(s.URL) == url {
+			return s
+		}
+	}
+	return nil
+}
+
+// NamedServers returns the API servers that have a name in the order they are defined.
+func (a *APIDefinition) NamedServers() []*ServerDefinition {
+	var servers []*ServerDefinition
+	for _, s := range a.Servers {
+		if s.Name != "" {
+			servers = append(servers, s)
+		}
+	}
+	return servers
+}
+
+// DefaultServer returns the server generated clients send requests to when no environment is
+// specified: the server marked as default if any, the first server otherwise. It returns nil if the
+// API does not define servers.
+func (a *APIDefinition) DefaultServer() *ServerDefinition {
+	for _, s := range a.Servers {
+		if s.Default {
+			return s
+		}
+	}
+	if len(a.Servers) == 0 {
+		return nil
+	}
+	return a.Servers[0]
+}
+
+// NormalizeServerURL returns the server URL with a lowercase scheme and without the port if it is
+// the default port of the scheme: 443 for "https" and "wss", 80 for "http" and "ws". The URL may
+// contain variables, other ports are kept as is.
+func NormalizeServerURL(u string) string {
+	i := strings.Index(u, "://")
+	if i <= 0 {
+		return u
+	}
+	scheme := strings.ToLower(u[:i])
+	rest := u[i+3:]
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	host := rest[:end]
+	var port string
+	switch scheme {
+	case "https", "wss":
+		port = ":443"
+	case "http", "ws":
+		port = ":80"
+	}
+	if port != "" && strings.HasSuffix(host, port) {
+		host = host[:len(host)-len(port)]
+	}
+	return scheme + "://" + host + rest[end:]
+}
+
+// Context returns the generic definition name used in error messages.
+func (s *ServerDefinition) Context() string {
+	return fmt.Sprintf("server %s", s.URL)
+}
+
+// Host returns the host name of the server URL without the port, empty string if the URL cannot
+// be parsed. The URL variables are replaced with their default values.
+func (s *ServerDefinition) Host() string {
+	u, err := url.Parse(s.DefaultURL())
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// Port returns the port of the server URL with the variables replaced by their default values.
+// The port defaults to 443 for the "https" and "wss" schemes and to 80 otherwise. Port returns 0
+// if the URL cannot be parsed or if its port is invalid.
+func (s *ServerDefinition) Port() int {
+	u, err := url.Parse(s.DefaultURL())
+	if err != nil {
+		return 0
+	}
+	if p := u.Port(); p != "" {
+		port, err := strconv.Atoi(p)
+		if err != nil {
+			return 0
+		}
+		return port
+	}
+	if s.Secure() {
+		return 443
+	}
+	return 80
+}
+
+// Secure returns true if the server URL scheme is "https", "wss" or "grpcs".
+func (s *ServerDefinition) Secure() bool {
+	u, err := url.Parse(s.DefaultURL())
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" || u.Scheme == "wss" || u.Scheme == "grpcs"
+}
+
+// DefaultURL returns the server URL with the variables replaced by their default values.
+// Variables that are not defined or have no default value are left as is.
+func (s *ServerDefinition) DefaultURL() string {
+	return ServerVariableRegex.ReplaceAllStringFunc(s.URL, func(v string) string {
+		if s.Variables == nil {
+			return v
+		}
+		att, ok := s.Variables.Type.ToObject()[v[1:len(v)-1]]
+		if !ok || att.DefaultValue == nil {
+			return v
+		}
+		return fmt.Sprintf("%v", att.DefaultValue)
+	})
+}
+
 // Context returns the generic definition name used in error messages.
 func (t *UserTypeDefinition) Context() string {
 	if t.TypeName != "" {
@@ -1416,6 +2464,110 @@ func (r *ResponseDefinition) Finalize() {
 	r.MediaType = mt.Identifier
 }
 
+// BodyContentType returns the value of the Content-Type header of responses whose Type
+// overrides the media type: ContentType if set, MediaType otherwise. Binary responses with
+// neither use "application/octet-stream".
+func (r *ResponseDefinition) BodyContentType() string {
+	if r.ContentType != "" {
+		return r.ContentType
+	}
+	if r.MediaType == "" && IsBinary(r.Type) {
+		return BinaryMIMEType
+	}
+	return r.MediaType
+}
+
+// Binary returns true if the response body is written as is rather than encoded, that is if the
+// response type is Bytes and its content type is not produced by the API encoders.
+func (r *ResponseDefinition) Binary() bool {
+	if !IsBinary(r.Type) {
+		return false
+	}
+	produces := Design.Produces
+	if len(produces) == 0 {
+		produces = DefaultEncoders
+	}
+	return !hasMIMEType(produces, r.BodyContentType())
+}
+
+// BodyExample returns the example body of the response: the body of the static action response,
+// Example if set or the example defined at the API level for the response status otherwise.
+// Responses with no body have no example.
+func (r *ResponseDefinition) BodyExample() interface{} {
+	if a := r.staticAction(); a != nil {
+		return a.Static.Body
+	}
+	if r.Example != nil {
+		return r.Example
+	}
+	if r.MediaType == "" && r.Type == nil {
+		return nil
+	}
+	if ex, ok := Design.ResponseExamples[r.Status]; ok {
+		return ex.Value
+	}
+	return nil
+}
+
+// staticAction returns the parent action if it is static and r is its static response.
+func (r *ResponseDefinition) staticAction() *ActionDefinition {
+	if a, ok := r.Parent.(*ActionDefinition); ok && a.Static != nil && a.Static.Status == r.Status {
+		return a
+	}
+	return nil
+}
+
+// bodyAttribute returns the attribute describing the response body if any.
+func (r *ResponseDefinition) bodyAttribute() *AttributeDefinition {
+	if r.Type != nil {
+		return &AttributeDefinition{Type: r.Type}
+	}
+	if r.MediaType == "" {
+		return nil
+	}
+	if CanonicalIdentifier(r.MediaType) == CanonicalIdentifier(ErrorMediaIdentifier) {
+		return ErrorMedia.AttributeDefinition
+	}
+	if mt := Design.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+		return mt.AttributeDefinition
+	}
+	return nil
+}
+
+// StaticHeaders returns the values of the response headers that define a default value indexed
+// by name. These headers are constants: the generated code sets them unless the action already
+// did.
+func (r *ResponseDefinition) StaticHeaders() map[string]string {
+	return staticHeaders(r.Headers)
+}
+
+// staticHeaders returns the default values of the given headers indexed by name, array values are
+// joined with commas.
+func staticHeaders(headers *AttributeDefinition) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	values := make(map[string]string)
+	for n, h := range headers.Type.ToObject() {
+		if h.DefaultValue == nil {
+			continue
+		}
+		if vals, ok := h.DefaultValue.([]interface{}); ok {
+			elems := make([]string, len(vals))
+			for i, v := range vals {
+				elems[i] = fmt.Sprint(v)
+			}
+			values[n] = strings.Join(elems, ", ")
+			continue
+		}
+		values[n] = fmt.Sprint(h.DefaultValue)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
 // Dup returns a copy of the response definition.
 func (r *ResponseDefinition) Dup() *ResponseDefinition {
 	res := ResponseDefinition{
@@ -1424,6 +2576,8 @@ func (r *ResponseDefinition) Dup() *ResponseDefinition {
 		Description: r.Description,
 		MediaType:   r.MediaType,
 		ViewName:    r.ViewName,
+		ContentType: r.ContentType,
+		Example:     r.Example,
 	}
 	if r.Headers != nil {
 		res.Headers = DupAtt(r.Headers)
@@ -1449,6 +2603,9 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 		r.MediaType = other.MediaType
 		r.ViewName = other.ViewName
 	}
+	if r.Example == nil {
+		r.Example = other.Example
+	}
 	if other.Headers != nil {
 		otherHeaders := other.Headers.Type.ToObject()
 		if len(otherHeaders) > 0 {
@@ -1465,6 +2622,11 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 	}
 }
 
+// Context returns the generic definition name used in error messages.
+func (e *ResponseExampleDefinition) Context() string {
+	return fmt.Sprintf("response example for status %d", e.Status)
+}
+
 // Context returns the generic definition name used in error messages.
 func (r *ResponseTemplateDefinition) Context() string {
 	if r.Name != "" {
@@ -1476,7 +2638,11 @@ func (r *ResponseTemplateDefinition) Context() string {
 // Context returns the generic definition name used in error messages.
 func (a *ActionDefinition) Context() string {
 	var prefix, suffix string
-	if a.Name != "" {
+	if a.Webhook {
+		suffix = fmt.Sprintf("webhook %#v", a.Name)
+	} else if a.AsyncOperation != "" {
+		suffix = fmt.Sprintf("%s action %#v", a.AsyncOperation, a.Name)
+	} else if a.Name != "" {
 		suffix = fmt.Sprintf("action %#v", a.Name)
 	} else {
 		suffix = "unnamed action"
@@ -1487,6 +2653,16 @@ func (a *ActionDefinition) Context() string {
 	return prefix + suffix
 }
 
+// OpenAPIPath returns the OpenAPI path item key of the action primary route, that is its full
+// path using the OpenAPI syntax for path parameters. It returns the empty string if the action
+// has no route.
+func (a *ActionDefinition) OpenAPIPath() string {
+	if len(a.Routes) == 0 {
+		return ""
+	}
+	return a.Routes[0].OpenAPIPath()
+}
+
 // PathParams returns the path parameters of the action across all its routes.
 func (a *ActionDefinition) PathParams() *AttributeDefinition {
 	obj := make(Object)
@@ -1502,23 +2678,233 @@ func (a *ActionDefinition) PathParams() *AttributeDefinition {
 }
 
 // AllParams returns the path and query string parameters of the action across all its routes.
+// Action-level parameters override the inherited parameters with the same name and the
+// parameters omitted with OmitParam or NoInheritedParams are removed.
 func (a *ActionDefinition) AllParams() *AttributeDefinition {
-	var res *AttributeDefinition
-	if a.Params != nil {
-		res = DupAtt(a.Params)
-	} else {
-		res = &AttributeDefinition{Type: Object{}}
-	}
+	res := copyAttributes(a.Params)
 	if a.HasAbsoluteRoutes() {
 		return res
 	}
-	res = res.Merge(a.Parent.Params)
+	omitted := append(append([]string{}, a.OmittedParams...), a.NoInheritedParams...)
+	mergeInherited(res, a.Parent.Params, omitted)
 	if p := a.Parent.Parent(); p != nil {
-		res = res.Merge(p.CanonicalAction().PathParams())
+		mergeInherited(res, p.CanonicalAction().PathParams(), omitted)
 	} else {
-		res = res.Merge(a.Parent.PathParams())
+		mergeInherited(res, a.Parent.PathParams(), omitted)
+	}
+	mergeInherited(res, Design.Params, omitted)
+	return res
+}
+
+// AllHeaders returns the resource-level and action-level headers of the action. Action-level
+// headers override the resource-level headers with the same name and the headers omitted with
+// OmitHeader are removed.
+func (a *ActionDefinition) AllHeaders() *AttributeDefinition {
+	res := copyAttributes(a.Headers)
+	if a.Parent != nil {
+		mergeInherited(res, a.Parent.Headers, a.OmittedHeaders)
+	}
+	return res
+}
+
+// copyAttributes returns a copy of the given object attribute that can be extended with
+// mergeInherited without modifying att, an empty object attribute if att is nil.
+func copyAttributes(att *AttributeDefinition) *AttributeDefinition {
+	if att == nil {
+		return &AttributeDefinition{Type: Object{}}
+	}
+	res := DupAtt(att)
+	obj := make(Object)
+	for n, at := range att.Type.ToObject() {
+		obj[n] = at
+	}
+	res.Type = obj
+	return res
+}
+
+// mergeInherited adds the attributes of parent to res except the omitted ones and the ones res
+// already defines. The required validations of parent only apply to the added attributes so that
+// the attributes defined by res override both the definition and the validations of the inherited
+// attributes.
+func mergeInherited(res, parent *AttributeDefinition, omitted []string) {
+	if parent == nil {
+		return
+	}
+	obj := res.Type.ToObject()
+	for n, at := range parent.Type.ToObject() {
+		if _, ok := obj[n]; ok || isOmitted(n, omitted) {
+			continue
+		}
+		obj[n] = at
+		if parent.IsRequired(n) {
+			if res.Validation == nil {
+				res.Validation = &dslengine.ValidationDefinition{}
+			}
+			res.Validation.AddRequired([]string{n})
+		}
+	}
+}
+
+// isOmitted returns true if name is one of omitted.
+func isOmitted(name string, omitted []string) bool {
+	for _, o := range omitted {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInheritedParam returns true if the given name is a path parameter of the action resource
+// path that is removed from the action routes with NoInheritedParams.
+func (a *ActionDefinition) IsInheritedParam(name string) bool {
+	return isOmitted(name, a.NoInheritedParams)
+}
+
+// EncodingMIMETypes returns the MIME types of the given encoding definitions.
+func EncodingMIMETypes(defs []*EncodingDefinition) []string {
+	var mimeTypes []string
+	for _, d := range defs {
+		mimeTypes = append(mimeTypes, d.MIMETypes...)
 	}
-	return res.Merge(Design.Params)
+	return mimeTypes
+}
+
+// AudienceMetadataKey is the metadata key used to store the audiences set with the Audience DSL.
+const AudienceMetadataKey = "swagger:audience"
+
+// Audiences returns the names of the audiences declared by the API.
+func (a *APIDefinition) Audiences() []string {
+	return a.Metadata[AudienceMetadataKey]
+}
+
+// Audiences returns the names of the audiences the resource is documented for, nil if the
+// resource is documented for all audiences.
+func (r *ResourceDefinition) Audiences() []string {
+	return r.Metadata[AudienceMetadataKey]
+}
+
+// Audiences returns the names of the audiences the action is documented for, nil if the action
+// is documented for all audiences. The action audiences default to the resource audiences.
+func (a *ActionDefinition) Audiences() []string {
+	if aud, ok := a.Metadata[AudienceMetadataKey]; ok {
+		return aud
+	}
+	if a.Parent != nil {
+		return a.Parent.Audiences()
+	}
+	return nil
+}
+
+// AllMetadata returns the resource metadata merged with the API metadata, the resource values take
+// precedence.
+func (r *ResourceDefinition) AllMetadata() dslengine.MetadataDefinition {
+	if Design == nil {
+		return r.Metadata
+	}
+	return r.Metadata.Merge(Design.Metadata)
+}
+
+// AllMetadata returns the action metadata merged with the metadata of its resource and of the API,
+// the action values take precedence.
+func (a *ActionDefinition) AllMetadata() dslengine.MetadataDefinition {
+	if a.Parent == nil {
+		return a.Metadata
+	}
+	return a.Metadata.Merge(a.Parent.AllMetadata())
+}
+
+// AllMetadata returns the file server metadata merged with the metadata of its resource and of the
+// API, the file server values take precedence.
+func (f *FileServerDefinition) AllMetadata() dslengine.MetadataDefinition {
+	if f.Parent == nil {
+		return f.Metadata
+	}
+	return f.Metadata.Merge(f.Parent.AllMetadata())
+}
+
+// TimeoutMetadataKey is the metadata key used to store the action timeout set with the Timeout
+// DSL. The value is a duration as accepted by time.ParseDuration.
+const TimeoutMetadataKey = "goa:timeout"
+
+// Timeout returns the timeout defined for the action and true if there is one, false otherwise.
+// Invalid or non-positive durations are reported by the action validation and ignored here.
+func (a *ActionDefinition) Timeout() (time.Duration, bool) {
+	val, ok := a.Metadata.Last(TimeoutMetadataKey)
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// SinceMetadataKey is the metadata key used to store the API version that introduced the action
+// set with the Since DSL.
+const SinceMetadataKey = "goa:since"
+
+// Since returns the API version that introduced the action or the empty string if not set.
+func (a *ActionDefinition) Since() string {
+	v, _ := a.Metadata.Last(SinceMetadataKey)
+	return v
+}
+
+// compareVersions compares two versions made of dot separated numbers optionally prefixed with
+// "v", e.g. "v1" or "2.1.0". The missing trailing numbers are 0 so that "2" and "2.0" are equal. It
+// returns -1, 0 or 1 if v1 is lower than, equal to or greater than v2 and false if one of the
+// versions cannot be parsed.
+func compareVersions(v1, v2 string) (int, bool) {
+	parse := func(v string) ([]int, bool) {
+		parts := strings.Split(strings.TrimLeft(v, "vV"), ".")
+		nums := make([]int, len(parts))
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 0 {
+				return nil, false
+			}
+			nums[i] = n
+		}
+		return nums, true
+	}
+	n1, ok1 := parse(v1)
+	n2, ok2 := parse(v2)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	for i := 0; i < len(n1) || i < len(n2); i++ {
+		var a, b int
+		if i < len(n1) {
+			a = n1[i]
+		}
+		if i < len(n2) {
+			b = n2[i]
+		}
+		switch {
+		case a < b:
+			return -1, true
+		case a > b:
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+// SignatureErrorStatus returns the HTTP status code of the response sent when the request
+// signature cannot be verified: 401 if the action defines a response with that status, 403 if it
+// defines a response with status 403 and 0 otherwise.
+func (a *ActionDefinition) SignatureErrorStatus() int {
+	status := 0
+	for _, r := range a.Responses {
+		switch r.Status {
+		case http.StatusUnauthorized:
+			return r.Status
+		case http.StatusForbidden:
+			status = r.Status
+		}
+	}
+	return status
 }
 
 // HasAbsoluteRoutes returns true if all the action routes are absolute.
@@ -1550,17 +2936,21 @@ func (a *ActionDefinition) CanonicalScheme() string {
 }
 
 // EffectiveSchemes return the URL schemes that apply to the action. Looks recursively into action
-// resource, parent resources and API.
+// resource, parent resources, the servers the resource is restricted to and API.
 func (a *ActionDefinition) EffectiveSchemes() []string {
 	// Compute the schemes
 	schemes := a.Schemes
 	if len(schemes) == 0 {
 		res := a.Parent
 		schemes = res.Schemes
-		parent := res.Parent()
-		for len(schemes) == 0 && parent != nil {
+		for _, parent := range res.ParentChain() {
+			if len(schemes) > 0 {
+				break
+			}
 			schemes = parent.Schemes
-			parent = parent.Parent()
+		}
+		if len(schemes) == 0 {
+			schemes = res.ServerSchemes()
 		}
 		if len(schemes) == 0 {
 			schemes = Design.Schemes
@@ -1569,6 +2959,20 @@ func (a *ActionDefinition) EffectiveSchemes() []string {
 	return schemes
 }
 
+// StaticResponse returns the response returned by the static action, nil if the action is not
+// static or does not define a response with the static status.
+func (a *ActionDefinition) StaticResponse() *ResponseDefinition {
+	if a.Static == nil {
+		return nil
+	}
+	for _, r := range a.Responses {
+		if r.Status == a.Static.Status {
+			return r
+		}
+	}
+	return nil
+}
+
 // WebSocket returns true if the action scheme is "ws" or "wss" or both (directly or inherited
 // from the resource or API)
 func (a *ActionDefinition) WebSocket() bool {
@@ -1584,6 +2988,126 @@ func (a *ActionDefinition) WebSocket() bool {
 	return true
 }
 
+// IsIdempotent returns true if sending the same request multiple times has the same effect as
+// sending it once: if the action is marked as idempotent or if all its routes use the GET, HEAD,
+// OPTIONS, PUT or DELETE HTTP verbs.
+func (a *ActionDefinition) IsIdempotent() bool {
+	if a.Idempotent {
+		return true
+	}
+	if len(a.Routes) == 0 {
+		return false
+	}
+	for _, r := range a.Routes {
+		switch r.Verb {
+		case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// FullPaths returns the full paths of the action routes followed by the full paths of the routes
+// that serve the action through the named mounts of its resource in definition order.
+func (a *ActionDefinition) FullPaths() []string {
+	var paths []string
+	for _, r := range a.Routes {
+		paths = append(paths, r.FullPath())
+	}
+	if a.Parent == nil {
+		return paths
+	}
+	for _, m := range a.Parent.Mounts {
+		for _, r := range m.Routes(a) {
+			paths = append(paths, r.FullPath())
+		}
+	}
+	return paths
+}
+
+// CacheableMetadataKey is the metadata key used to opt actions out of client-side response
+// caching, e.g. Metadata("http:cacheable", "false").
+const CacheableMetadataKey = "http:cacheable"
+
+// IsCacheable returns true if the clients may cache the responses of the action: if the action
+// has a GET route and neither the action, its resource nor the API set the "http:cacheable"
+// metadata to false.
+func (a *ActionDefinition) IsCacheable() bool {
+	if cacheable, ok := a.AllMetadata().BoolValue(CacheableMetadataKey); ok && !cacheable {
+		return false
+	}
+	for _, r := range a.Routes {
+		if r.Verb == "GET" {
+			return true
+		}
+	}
+	return false
+}
+
+// NegotiateMetadataKey is the metadata key used to enable the upfront content negotiation of the
+// generated handlers, e.g. Metadata("http:negotiate", "true").
+const NegotiateMetadataKey = "http:negotiate"
+
+// Negotiates returns true if the generated handler of the action checks the request Content-Type
+// and Accept headers against the Consumes and Produces media types before decoding the request:
+// if the action, its resource or the API set the "http:negotiate" metadata to true.
+func (a *ActionDefinition) Negotiates() bool {
+	negotiate, ok := a.AllMetadata().BoolValue(NegotiateMetadataKey)
+	return ok && negotiate
+}
+
+// StreamTransport returns the transport used to stream the action results: WebSocketStream if
+// the action uses the ws or wss scheme, NDJSONStream or SSEStream if the action produces the
+// corresponding MIME type and the empty string otherwise.
+func (a *ActionDefinition) StreamTransport() string {
+	if a.WebSocket() {
+		return WebSocketStream
+	}
+	for _, m := range a.Produces {
+		switch m {
+		case NDJSONMIMEType:
+			return NDJSONStream
+		case SSEMIMEType:
+			return SSEStream
+		}
+	}
+	return ""
+}
+
+// BinaryPayload returns true if the request body is read as is rather than decoded, that is if
+// the payload is Bytes and the action consumes MIME types that the API decoders do not handle,
+// e.g. "application/octet-stream". See BinaryMIMETypes.
+func (a *ActionDefinition) BinaryPayload() bool {
+	return a.Payload != nil && IsBinary(a.Payload) && len(a.BinaryMIMETypes()) > 0
+}
+
+// BinaryMIMETypes returns the MIME types consumed by the action that the API decoders do not
+// handle. These are the content types accepted by actions with binary payloads.
+func (a *ActionDefinition) BinaryMIMETypes() []string {
+	consumes := Design.Consumes
+	if len(consumes) == 0 {
+		consumes = DefaultDecoders
+	}
+	var mimeTypes []string
+	for _, m := range a.Consumes {
+		if !hasMIMEType(consumes, m) {
+			mimeTypes = append(mimeTypes, m)
+		}
+	}
+	return mimeTypes
+}
+
+// hasMIMEType returns true if one of the given encodings handles the given MIME type.
+func hasMIMEType(encs []*EncodingDefinition, mimeType string) bool {
+	for _, m := range EncodingMIMETypes(encs) {
+		if m == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
 // Finalize inherits security scheme and action responses from parent and top level design.
 func (a *ActionDefinition) Finalize() {
 	// Inherit security scheme
@@ -1598,15 +3122,167 @@ func (a *ActionDefinition) Finalize() {
 		a.Security = nil
 	}
 
+	a.inheritMIMETypes()
+
 	if a.Payload != nil {
 		a.Payload.Finalize()
+		a.excludeReadOnlyPayloadAttributes()
+	}
+
+	if a.StreamingResult != nil {
+		a.StreamingResult.Finalize()
+	}
+	if a.Result != nil {
+		a.Result.Finalize()
 	}
 
+	a.Streaming = a.WebSocket() || a.StreamingResult != nil
+
+	a.initDefaultResponse()
 	a.mergeResponses()
+	a.normalizeRoutes()
 	a.initImplicitParams()
+	a.initViewParam()
 	a.initQueryParams()
 }
 
+// excludeReadOnlyPayloadAttributes replaces the action payload with a copy that omits the
+// read-only attributes of the payload object and their required validations if there are any. The copy is named after the
+// action and resource like the payloads defined inline so that the generated request body type
+// does not accept the read-only attributes while the shared type still renders them.
+func (a *ActionDefinition) excludeReadOnlyPayloadAttributes() {
+	obj := a.Payload.Type.ToObject()
+	if obj == nil {
+		return
+	}
+	pobj := make(Object, len(obj))
+	for n, att := range obj {
+		if !att.IsReadOnly() {
+			pobj[n] = att
+		}
+	}
+	if len(pobj) == len(obj) {
+		return
+	}
+	att := DupAtt(a.Payload.AttributeDefinition)
+	att.Type = pobj
+	if att.Validation != nil {
+		var required []string
+		for _, n := range att.Validation.Required {
+			if _, ok := pobj[n]; ok {
+				required = append(required, n)
+			}
+		}
+		att.Validation.Required = required
+	}
+	a.Payload = &UserTypeDefinition{
+		AttributeDefinition: att,
+		TypeName:            fmt.Sprintf("%s%sPayload", titleCase(a.Name), titleCase(a.Parent.Name)),
+	}
+}
+
+// titleCase converts a design name such as "list_all" or "listAll" to "ListAll".
+func titleCase(name string) string {
+	elems := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, e := range elems {
+		elems[i] = strings.ToUpper(e[:1]) + e[1:]
+	}
+	return strings.Join(elems, "")
+}
+
+// normalizeRoutes removes the trailing slash of the action route paths if the API trailing slash
+// policy is TrailingSlashRedirect or TrailingSlashIgnore so that the generated mux, clients and
+// documentation all use the same paths.
+func (a *ActionDefinition) normalizeRoutes() {
+	if a.Webhook || Design.TrailingSlash != TrailingSlashRedirect && Design.TrailingSlash != TrailingSlashIgnore {
+		return
+	}
+	for _, r := range a.Routes {
+		if r.IsAbsolute() && len(r.Path) <= 2 {
+			continue
+		}
+		r.Path = strings.TrimSuffix(r.Path, "/")
+	}
+}
+
+// ViewedResponse returns the response rendered with the view selected by the client when the
+// action defines a ViewParam, that is the success response with the lowest status whose media type
+// defines views and that does not force a view. It returns nil if there is no such response.
+func (a *ActionDefinition) ViewedResponse() (*ResponseDefinition, *MediaTypeDefinition) {
+	responses := make([]*ResponseDefinition, 0, len(a.Responses))
+	for _, r := range a.Responses {
+		responses = append(responses, r)
+	}
+	if a.Parent != nil {
+		for n, r := range a.Parent.Responses {
+			if _, ok := a.Responses[n]; !ok {
+				responses = append(responses, r)
+			}
+		}
+	}
+	var (
+		resp *ResponseDefinition
+		mt   *MediaTypeDefinition
+	)
+	for _, r := range responses {
+		if r.Status < 200 || r.Status > 299 || r.ViewName != "" {
+			continue
+		}
+		if resp != nil && resp.Status <= r.Status {
+			continue
+		}
+		m, ok := r.Type.(*MediaTypeDefinition)
+		if !ok {
+			m = Design.MediaTypeWithIdentifier(r.MediaType)
+		}
+		if m != nil && len(m.Views) > 0 {
+			resp, mt = r, m
+		}
+	}
+	return resp, mt
+}
+
+// ViewNames returns the sorted names of the views of the ViewedResponse media type.
+func (a *ActionDefinition) ViewNames() []string {
+	_, mt := a.ViewedResponse()
+	if mt == nil {
+		return nil
+	}
+	names := make([]string, 0, len(mt.Views))
+	for n := range mt.Views {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// initViewParam adds the query string parameter that selects the view to the action parameters.
+// The parameter value must be one of the view names and defaults to the default view.
+func (a *ActionDefinition) initViewParam() {
+	if a.ViewParam == "" {
+		return
+	}
+	names := a.ViewNames()
+	if len(names) == 0 {
+		return
+	}
+	values := make([]interface{}, len(names))
+	for i, n := range names {
+		values[i] = n
+	}
+	if a.Params == nil {
+		a.Params = &AttributeDefinition{Type: Object{}}
+	}
+	a.Params.Type.ToObject()[a.ViewParam] = &AttributeDefinition{
+		Type:         String,
+		Description:  "View used to render the response",
+		Validation:   &dslengine.ValidationDefinition{Values: values},
+		DefaultValue: DefaultView,
+	}
+}
+
 // UserTypes returns all the user types used by the action payload and parameters.
 func (a *ActionDefinition) UserTypes() map[string]*UserTypeDefinition {
 	types := make(map[string]*UserTypeDefinition)
@@ -1636,14 +3312,8 @@ func (a *ActionDefinition) UserTypes() map[string]*UserTypeDefinition {
 // Iteration stops if an iterator returns an error and in this case IterateHeaders returns that
 // error.
 func (a *ActionDefinition) IterateHeaders(it HeaderIterator) error {
-	mergedHeaders := a.Parent.Headers.Merge(a.Headers)
-
-	isRequired := func(name string) bool {
-		// header required in either the Resource or Action scope?
-		return a.Parent.Headers.IsRequired(name) || a.Headers.IsRequired(name)
-	}
-
-	return iterateHeaders(mergedHeaders, isRequired, it)
+	headers := a.AllHeaders()
+	return iterateHeaders(headers, headers.IsRequired, it)
 }
 
 // IterateResponses calls the given iterator passing in each response sorted in alphabetical order.
@@ -1665,7 +3335,8 @@ func (a *ActionDefinition) IterateResponses(it ResponseIterator) error {
 	return nil
 }
 
-// mergeResponses merges the parent resource and design responses.
+// mergeResponses merges the parent resource and design responses. The action inherits the API
+// error responses unless its parent resource uses NoInheritedErrors.
 func (a *ActionDefinition) mergeResponses() {
 	for name, resp := range a.Parent.Responses {
 		if _, ok := a.Responses[name]; !ok {
@@ -1675,6 +3346,18 @@ func (a *ActionDefinition) mergeResponses() {
 			a.Responses[name] = resp.Dup()
 		}
 	}
+	if a.Parent.InheritsAPIErrors() {
+		for name, resp := range Design.Responses {
+			if _, ok := a.Responses[name]; !ok && resp.Status >= 400 {
+				if a.Responses == nil {
+					a.Responses = make(map[string]*ResponseDefinition)
+				}
+				dup := resp.Dup()
+				dup.Parent = a
+				a.Responses[name] = dup
+			}
+		}
+	}
 	for name, resp := range a.Responses {
 		resp.Finalize()
 		if pr, ok := a.Parent.Responses[name]; ok {
@@ -1689,6 +3372,91 @@ func (a *ActionDefinition) mergeResponses() {
 	}
 }
 
+// finalizeWebhook inherits the webhook MIME types and merges its responses with the default
+// responses. Webhooks do not inherit the resource responses, security or parameters as they
+// describe requests sent by the API.
+func (a *ActionDefinition) finalizeWebhook() {
+	a.inheritMIMETypes()
+	if a.Payload != nil {
+		a.Payload.Finalize()
+	}
+	for name, resp := range a.Responses {
+		resp.Finalize()
+		if dr, ok := Design.DefaultResponses[name]; ok {
+			resp.Merge(dr)
+		}
+	}
+}
+
+// inheritMIMETypes sets the action MIME types to the resource or API MIME types if not set
+// explicitly.
+func (a *ActionDefinition) inheritMIMETypes() {
+	if len(a.Consumes) == 0 {
+		a.Consumes = a.Parent.Consumes
+		if len(a.Consumes) == 0 {
+			a.Consumes = EncodingMIMETypes(Design.Consumes)
+		}
+	}
+	if len(a.Produces) == 0 {
+		a.Produces = a.Parent.Produces
+		if len(a.Produces) == 0 {
+			a.Produces = EncodingMIMETypes(Design.Produces)
+		}
+	}
+}
+
+// consumedMIMETypes returns the MIME types listed in the action Consumes DSL or else the MIME
+// types consumed by the resource or API. Unlike inheritMIMETypes it does not require the action to
+// be finalized.
+func (a *ActionDefinition) consumedMIMETypes() []string {
+	if len(a.Consumes) > 0 {
+		return a.Consumes
+	}
+	if a.Parent != nil && len(a.Parent.Consumes) > 0 {
+		return a.Parent.Consumes
+	}
+	if Design != nil && len(Design.Consumes) > 0 {
+		return EncodingMIMETypes(Design.Consumes)
+	}
+	return EncodingMIMETypes(DefaultDecoders)
+}
+
+// initDefaultResponse adds the success response specified by the parent resource default
+// response statuses if the action does not define any success response.
+func (a *ActionDefinition) initDefaultResponse() {
+	if len(a.Parent.DefaultResponseStatuses) == 0 {
+		return
+	}
+	for _, resps := range []map[string]*ResponseDefinition{a.Responses, a.Parent.Responses} {
+		for _, resp := range resps {
+			if resp.Status >= 200 && resp.Status < 300 {
+				return
+			}
+		}
+	}
+	for _, route := range a.Routes {
+		status, ok := a.Parent.DefaultResponseStatuses[route.Verb]
+		if !ok {
+			continue
+		}
+		for name, dr := range Design.DefaultResponses {
+			if dr.Status != status {
+				continue
+			}
+			resp := &ResponseDefinition{Name: name, Status: status, Parent: a}
+			if status == 200 {
+				resp.MediaType = a.Parent.MediaType
+				resp.ViewName = a.Parent.DefaultViewName
+			}
+			if a.Responses == nil {
+				a.Responses = make(map[string]*ResponseDefinition)
+			}
+			a.Responses[name] = resp
+			return
+		}
+	}
+}
+
 // initImplicitParams creates params for path segments that don't have one.
 func (a *ActionDefinition) initImplicitParams() {
 	for _, ro := range a.Routes {
@@ -1699,7 +3467,7 @@ func (a *ActionDefinition) initImplicitParams() {
 					return
 				}
 				att, ok := params.Type.ToObject()[wc]
-				if ok {
+				if ok && att != nil {
 					if a.Params == nil {
 						a.Params = &AttributeDefinition{Type: Object{}}
 					}
@@ -1713,6 +3481,11 @@ func (a *ActionDefinition) initImplicitParams() {
 				bp := parent.Params
 				parent = parent.Parent()
 				search(bp)
+				if !found && parent != nil {
+					if ca := parent.CanonicalAction(); ca != nil && ca != a {
+						search(ca.PathParams())
+					}
+				}
 			}
 			if found {
 				continue
@@ -1759,6 +3532,52 @@ func (a *ActionDefinition) initQueryParams() {
 	}
 }
 
+// Context returns the generic definition name used in error messages.
+func (s *SignatureDefinition) Context() string {
+	var prefix string
+	if s.Parent != nil {
+		prefix = s.Parent.Context() + " "
+	}
+	return prefix + "signature"
+}
+
+// Context returns the generic definition name used in error messages.
+func (p *PayloadDefinition) Context() string {
+	suffix := fmt.Sprintf("payload %q", p.ContentType)
+	var prefix string
+	if p.Parent != nil {
+		prefix = p.Parent.Context() + " "
+	}
+	return prefix + suffix
+}
+
+// MediaType returns the payload content type stripped of its parameters and lower cased, e.g.
+// "application/json" for "application/JSON; charset=utf-8".
+func (p *PayloadDefinition) MediaType() string {
+	mt, _, err := mime.ParseMediaType(p.ContentType)
+	if err != nil {
+		return strings.ToLower(p.ContentType)
+	}
+	return mt
+}
+
+// AttributeName returns the name of the attribute of the action payload object that holds the
+// request body when it has the payload content type, e.g. "application_json".
+func (p *PayloadDefinition) AttributeName() string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, p.MediaType())
+}
+
+// Raw returns true if the request body is loaded as is rather than decoded, that is if the
+// payload is a string and the content type is textual.
+func (p *PayloadDefinition) Raw() bool {
+	return p.Attribute.Type == String && strings.HasPrefix(p.MediaType(), "text/")
+}
+
 // Context returns the generic definition name used in error messages.
 func (f *FileServerDefinition) Context() string {
 	suffix := fmt.Sprintf("file server %s", f.FilePath)
@@ -1787,11 +3606,138 @@ func (f *FileServerDefinition) Finalize() {
 	}
 }
 
+// Context returns the generic definition name used in error messages.
+func (m *MountDefinition) Context() string {
+	suffix := fmt.Sprintf("mount %#v", m.Name)
+	if m.Parent != nil {
+		return suffix + " of " + m.Parent.Context()
+	}
+	return suffix
+}
+
+// FullPath returns the full path of the route served through the mount: the route full path with
+// the API base path replaced with the mount base path.
+func (m *MountDefinition) FullPath(r *RouteDefinition) string {
+	p := r.FullPath()
+	if base := pathCleaner(Design.BasePath); base != "/" && (p == base || strings.HasPrefix(p, base+"/")) {
+		p = p[len(base):]
+	}
+	joinedPath := path.Join(m.BasePath, p)
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(joinedPath, "/") {
+		joinedPath += "/"
+	}
+	return pathCleaner(joinedPath)
+}
+
+// Route returns the absolute route that serves the action route r through the mount.
+func (m *MountDefinition) Route(r *RouteDefinition) *RouteDefinition {
+	return &RouteDefinition{
+		Verb:     r.Verb,
+		Path:     "/" + m.FullPath(r),
+		Parent:   r.Parent,
+		Metadata: r.Metadata,
+	}
+}
+
+// Routes returns the routes that serve the action a through the mount. The absolute routes of
+// the action are not mounted.
+func (m *MountDefinition) Routes(a *ActionDefinition) []*RouteDefinition {
+	var routes []*RouteDefinition
+	for _, r := range a.Routes {
+		if !r.IsAbsolute() {
+			routes = append(routes, m.Route(r))
+		}
+	}
+	return routes
+}
+
+// ActionSecurity returns the security requirements of the action a when served through the mount:
+// the mount security if set, the action security otherwise.
+func (m *MountDefinition) ActionSecurity(a *ActionDefinition) *SecurityDefinition {
+	if m.Security == nil {
+		return a.Security
+	}
+	if m.Security.Scheme.Kind == NoSecurityKind {
+		return nil
+	}
+	return m.Security
+}
+
+// PreflightPaths returns the paths of the mount routes that must handle CORS preflight requests.
+func (m *MountDefinition) PreflightPaths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	m.Parent.IterateActions(func(a *ActionDefinition) error {
+		for _, r := range m.Routes(a) {
+			if fp := r.FullPath(); r.Verb != "OPTIONS" && !seen[fp] {
+				seen[fp] = true
+				paths = append(paths, fp)
+			}
+		}
+		return nil
+	})
+	return paths
+}
+
+// MuxPath returns the path used to register the file server handler on the service mux, see
+// RouteDefinition.MuxPath.
+func (f *FileServerDefinition) MuxPath() string {
+	return muxPath(f.Parent, f.RequestPath)
+}
+
 // IsDir returns true if the file server serves a directory, false otherwise.
 func (f *FileServerDefinition) IsDir() bool {
 	return WildcardRegex.MatchString(f.RequestPath)
 }
 
+// Context returns the generic definition name used in error messages.
+func (h *HealthCheckDefinition) Context() string {
+	suffix := fmt.Sprintf("health check %s", h.Path)
+	var prefix string
+	if h.Parent != nil {
+		prefix = h.Parent.Context() + " "
+	}
+	return prefix + suffix
+}
+
+// FullPath returns the health check request path computed by concatenating the parent base path
+// with the health check path.
+func (h *HealthCheckDefinition) FullPath() string {
+	var base string
+	switch p := h.Parent.(type) {
+	case *APIDefinition:
+		base = p.BasePath
+	case *ResourceDefinition:
+		base = p.FullPath()
+	}
+	return pathCleaner(path.Join("/", base, h.Path))
+}
+
+// MuxPath returns the path used to register the health check handler on the service mux, see
+// RouteDefinition.MuxPath.
+func (h *HealthCheckDefinition) MuxPath() string {
+	r, _ := h.Parent.(*ResourceDefinition)
+	return muxPath(r, h.FullPath())
+}
+
+// Route returns the GET route used to serve the health check. The route parent is a synthetic
+// action named "health" which belongs to the parent resource if any. The route path is relative to
+// the resource base path like the action routes or built from the API base path for API health
+// checks.
+func (h *HealthCheckDefinition) Route() *RouteDefinition {
+	action := &ActionDefinition{Name: "health"}
+	routePath := h.Path
+	switch p := h.Parent.(type) {
+	case *ResourceDefinition:
+		action.Parent = p
+	case *APIDefinition:
+		routePath = path.Join("/", p.BasePath, h.Path)
+	}
+	route := &RouteDefinition{Verb: "GET", Path: routePath, Parent: action}
+	action.Routes = []*RouteDefinition{route}
+	return route
+}
+
 // ByFilePath makes FileServerDefinition sortable for code generators.
 type ByFilePath []*FileServerDefinition
 
@@ -1831,6 +3777,42 @@ func (l *LinkDefinition) MediaType() *MediaTypeDefinition {
 	return mt
 }
 
+// Context returns the generic definition name used in error messages.
+func (l *ActionLinkDefinition) Context() string {
+	var prefix, suffix string
+	if l.Rel != "" {
+		prefix = fmt.Sprintf("link %#v", l.Rel)
+	} else {
+		prefix = "unnamed link"
+	}
+	if l.Parent != nil {
+		suffix = fmt.Sprintf(" of %s", l.Parent.Context())
+	}
+	return prefix + suffix
+}
+
+// Target returns the action targeted by the link or nil if there isn't one.
+func (l *ActionLinkDefinition) Target() *ActionDefinition {
+	if Design == nil {
+		return nil
+	}
+	r, ok := Design.Resources[l.Resource]
+	if !ok {
+		return nil
+	}
+	return r.Actions[l.Action]
+}
+
+// URITemplate returns the full path of the first route of the action targeted by the link or the
+// empty string if there isn't one.
+func (l *ActionLinkDefinition) URITemplate() string {
+	t := l.Target()
+	if t == nil || len(t.Routes) == 0 {
+		return ""
+	}
+	return t.Routes[0].FullPath()
+}
+
 // Context returns the generic definition name used in error messages.
 func (v *ViewDefinition) Context() string {
 	var prefix, suffix string
@@ -1860,11 +3842,14 @@ func (r *RouteDefinition) Params() []string {
 // with the action specific path.
 func (r *RouteDefinition) FullPath() string {
 	if r.IsAbsolute() {
-		return httppath.Clean(r.Path[1:])
+		return pathCleaner(r.Path[1:])
 	}
 	var base string
 	if r.Parent != nil && r.Parent.Parent != nil {
 		base = r.Parent.Parent.FullPath()
+		if len(r.Parent.NoInheritedParams) > 0 {
+			base = removeWildcards(base, r.Parent.IsInheritedParam)
+		}
 	}
 
 	joinedPath := path.Join(base, r.Path)
@@ -1873,7 +3858,39 @@ func (r *RouteDefinition) FullPath() string {
 		joinedPath += "/"
 	}
 
-	return httppath.Clean(joinedPath)
+	return pathCleaner(joinedPath)
+}
+
+// trimTrailingSlash removes the trailing slash of the path p unless p is the root path.
+func trimTrailingSlash(p string) string {
+	if len(p) > 1 {
+		return strings.TrimSuffix(p, "/")
+	}
+	return p
+}
+
+// MuxPath returns the path used to register the route handler on the service mux: the full path
+// relative to the prefix stripped by the parent resource if it uses StripPrefix, the full path
+// otherwise.
+func (r *RouteDefinition) MuxPath() string {
+	if r.Parent == nil || r.IsAbsolute() {
+		return r.FullPath()
+	}
+	return muxPath(r.Parent.Parent, r.FullPath())
+}
+
+// removeWildcards removes the segments of p that consist of a wildcard whose name is accepted by
+// the given function.
+func removeWildcards(p string, remove func(string) bool) string {
+	segments := strings.Split(p, "/")
+	kept := segments[:0]
+	for _, s := range segments {
+		if len(s) > 1 && (s[0] == ':' || s[0] == '*') && remove(s[1:]) {
+			continue
+		}
+		kept = append(kept, s)
+	}
+	return strings.Join(kept, "/")
 }
 
 // IsAbsolute returns true if the action path should not be concatenated to the resource and API
@@ -1882,6 +3899,23 @@ func (r *RouteDefinition) IsAbsolute() bool {
 	return strings.HasPrefix(r.Path, "//")
 }
 
+// OpenAPIPath returns the route full path using the OpenAPI syntax for path parameters.
+// For example for the route "GET /users/:id" OpenAPIPath returns "/users/{id}".
+func (r *RouteDefinition) OpenAPIPath() string {
+	return OpenAPIPath(r.FullPath())
+}
+
+// OpenAPIPath converts the wildcards of the given path to OpenAPI path parameters. For
+// example "/users/:id" and "/files/*filepath" become "/users/{id}" and "/files/{filepath}".
+// OpenAPIPath returns "/" if the path is empty.
+func OpenAPIPath(p string) string {
+	key := WildcardRegex.ReplaceAllString(p, "/{$1}")
+	if key == "" {
+		key = "/"
+	}
+	return key
+}
+
 func iterateHeaders(headers *AttributeDefinition, isRequired func(name string) bool, it HeaderIterator) error {
 	if headers == nil || !headers.Type.IsObject() {
 		return nil
@@ -2,6 +2,7 @@ package design_test
 
 import (
 	"path"
+	"strings"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
@@ -148,6 +149,69 @@ var _ = Describe("IterateHeaders", func() {
 	})
 
 })
+var _ = Describe("AllHeaders", func() {
+	var resource *design.ResourceDefinition
+	var action *design.ActionDefinition
+
+	BeforeEach(func() {
+		resource = &design.ResourceDefinition{
+			Headers: &design.AttributeDefinition{
+				Type: design.Object{
+					"X-Tenant":  &design.AttributeDefinition{Type: design.String},
+					"X-Request": &design.AttributeDefinition{Type: design.String},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"X-Tenant", "X-Request"}},
+			},
+		}
+		action = &design.ActionDefinition{Parent: resource}
+	})
+
+	It("inherits the resource headers and their validations", func() {
+		headers := action.AllHeaders()
+		Ω(headers.Type.ToObject()).Should(HaveLen(2))
+		Ω(headers.IsRequired("X-Tenant")).Should(BeTrue())
+		Ω(headers.IsRequired("X-Request")).Should(BeTrue())
+	})
+
+	Context("with an action header overriding a resource header", func() {
+		var header *design.AttributeDefinition
+
+		BeforeEach(func() {
+			header = &design.AttributeDefinition{Type: design.Integer}
+			action.Headers = &design.AttributeDefinition{
+				Type: design.Object{"X-Tenant": header},
+			}
+		})
+
+		It("uses the action header definition and validations", func() {
+			headers := action.AllHeaders()
+			Ω(headers.Type.ToObject()).Should(HaveLen(2))
+			Ω(headers.Type.ToObject()["X-Tenant"]).Should(Equal(header))
+			Ω(headers.IsRequired("X-Tenant")).Should(BeFalse())
+			Ω(headers.Validation.Required).Should(Equal([]string{"X-Request"}))
+		})
+
+		It("does not modify the resource headers", func() {
+			action.AllHeaders()
+			Ω(resource.Headers.Type.ToObject()["X-Tenant"].Type).Should(Equal(design.String))
+			Ω(action.Headers.Type.ToObject()).Should(HaveLen(1))
+		})
+	})
+
+	Context("with an omitted header", func() {
+		BeforeEach(func() {
+			action.OmittedHeaders = []string{"X-Tenant"}
+		})
+
+		It("removes the header and its validations", func() {
+			headers := action.AllHeaders()
+			Ω(headers.Type.ToObject()).Should(HaveLen(1))
+			Ω(headers.Type.ToObject()).ShouldNot(HaveKey("X-Tenant"))
+			Ω(headers.Validation.Required).Should(Equal([]string{"X-Request"}))
+		})
+	})
+})
+
 var _ = Describe("Finalize ActionDefinition", func() {
 	Context("with an action with no response", func() {
 		var action *design.ActionDefinition
@@ -169,6 +233,189 @@ var _ = Describe("Finalize ActionDefinition", func() {
 	})
 })
 
+var _ = Describe("HasStreamingActions", func() {
+	var resource *design.ResourceDefinition
+
+	BeforeEach(func() {
+		resource = &design.ResourceDefinition{Name: "bottle"}
+		resource.Actions = map[string]*design.ActionDefinition{
+			"show": &design.ActionDefinition{Name: "show", Parent: resource},
+		}
+	})
+
+	JustBeforeEach(func() {
+		for _, a := range resource.Actions {
+			a.Finalize()
+		}
+	})
+
+	It("returns false when all the actions are unary", func() {
+		Ω(resource.Actions["show"].Streaming).Should(BeFalse())
+		Ω(resource.HasStreamingActions()).Should(BeFalse())
+	})
+
+	Context("with a websocket action", func() {
+		BeforeEach(func() {
+			resource.Actions["watch"] = &design.ActionDefinition{
+				Name:    "watch",
+				Parent:  resource,
+				Schemes: []string{"ws"},
+			}
+		})
+
+		It("returns true", func() {
+			Ω(resource.Actions["watch"].Streaming).Should(BeTrue())
+			Ω(resource.HasStreamingActions()).Should(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("BinaryPayload", func() {
+	var action *design.ActionDefinition
+	var api *design.APIDefinition
+
+	BeforeEach(func() {
+		api = design.Design
+		design.Design = &design.APIDefinition{}
+		action = &design.ActionDefinition{
+			Name: "upload",
+			Payload: &design.UserTypeDefinition{
+				TypeName:            "UploadPayload",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Bytes},
+			},
+			Consumes: []string{"application/json", "image/png"},
+		}
+	})
+
+	AfterEach(func() {
+		design.Design = api
+	})
+
+	It("returns true for Bytes payloads with MIME types not decoded by the API", func() {
+		Ω(action.BinaryPayload()).Should(BeTrue())
+		Ω(action.BinaryMIMETypes()).Should(Equal([]string{"image/png"}))
+	})
+
+	Context("with only decoded MIME types", func() {
+		BeforeEach(func() {
+			action.Consumes = []string{"application/json"}
+		})
+
+		It("returns false", func() {
+			Ω(action.BinaryPayload()).Should(BeFalse())
+		})
+	})
+
+	Context("with a string payload", func() {
+		BeforeEach(func() {
+			action.Payload.Type = design.String
+		})
+
+		It("returns false", func() {
+			Ω(action.BinaryPayload()).Should(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("Binary response", func() {
+	var resp *design.ResponseDefinition
+	var api *design.APIDefinition
+
+	BeforeEach(func() {
+		api = design.Design
+		design.Design = &design.APIDefinition{}
+		resp = &design.ResponseDefinition{Name: "OK", Status: 200, Type: design.Bytes}
+	})
+
+	AfterEach(func() {
+		design.Design = api
+	})
+
+	It("defaults to application/octet-stream", func() {
+		Ω(resp.Binary()).Should(BeTrue())
+		Ω(resp.BodyContentType()).Should(Equal("application/octet-stream"))
+	})
+
+	Context("with a JSON content type", func() {
+		BeforeEach(func() {
+			resp.ContentType = "application/json"
+		})
+
+		It("is encoded", func() {
+			Ω(resp.Binary()).Should(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("MediaTypes", func() {
+	It("returns the sorted media types of the resource requests and responses", func() {
+		resource := &design.ResourceDefinition{Name: "bottle"}
+		show := &design.ActionDefinition{
+			Name:   "show",
+			Parent: resource,
+			Responses: map[string]*design.ResponseDefinition{
+				"OK":         {Name: "OK", Status: 200, MediaType: "application/xml"},
+				"BadRequest": {Name: "BadRequest", Status: 400, MediaType: "application/vnd.goa.error"},
+			},
+		}
+		create := &design.ActionDefinition{
+			Name:     "create",
+			Parent:   resource,
+			Payloads: []*design.PayloadDefinition{{ContentType: "application/json"}},
+			Responses: map[string]*design.ResponseDefinition{
+				"Created":    {Name: "Created", Status: 201, MediaType: "application/json; charset=utf-8"},
+				"NoContent":  {Name: "NoContent", Status: 204},
+				"BadRequest": {Name: "BadRequest", Status: 400, MediaType: "application/vnd.goa.error"},
+			},
+		}
+		resource.Actions = map[string]*design.ActionDefinition{"show": show, "create": create}
+
+		Ω(resource.MediaTypes()).Should(Equal([]string{
+			"application/json",
+			"application/vnd.goa.error",
+			"application/xml",
+		}))
+	})
+
+	Context("with actions with a plain payload", func() {
+		var api *design.APIDefinition
+
+		BeforeEach(func() {
+			api = design.Design
+			design.Design = &design.APIDefinition{
+				Consumes: []*design.EncodingDefinition{{MIMETypes: []string{"application/json"}}},
+			}
+		})
+
+		AfterEach(func() {
+			design.Design = api
+		})
+
+		It("includes the MIME types consumed by the actions", func() {
+			resource := &design.ResourceDefinition{Name: "bottle"}
+			payload := &design.AttributeDefinition{Type: design.Object{}}
+			create := &design.ActionDefinition{
+				Name:     "create",
+				Parent:   resource,
+				Payload:  &design.UserTypeDefinition{AttributeDefinition: payload, TypeName: "CreatePayload"},
+				Consumes: []string{"application/x-www-form-urlencoded; charset=utf-8"},
+			}
+			update := &design.ActionDefinition{
+				Name:    "update",
+				Parent:  resource,
+				Payload: &design.UserTypeDefinition{AttributeDefinition: payload, TypeName: "UpdatePayload"},
+			}
+			show := &design.ActionDefinition{Name: "show", Parent: resource}
+			resource.Actions = map[string]*design.ActionDefinition{"create": create, "update": update, "show": show}
+
+			Ω(resource.MediaTypes()).Should(Equal([]string{
+				"application/json",
+				"application/x-www-form-urlencoded",
+			}))
+		})
+	})
+})
+
 var _ = Describe("FullPath", func() {
 
 	Context("Given a base resource and a resource with an action with a route", func() {
@@ -256,6 +503,82 @@ var _ = Describe("FullPath", func() {
 	})
 })
 
+var _ = Describe("Prepare APIDefinition", func() {
+	var basePath string
+	var resource *design.ResourceDefinition
+
+	BeforeEach(func() {
+		resource = &design.ResourceDefinition{Name: "res", BasePath: "/res"}
+	})
+
+	JustBeforeEach(func() {
+		design.Design.BasePath = basePath
+		design.Design.Prepare()
+	})
+
+	AfterEach(func() {
+		design.Design.BasePath = ""
+	})
+
+	Context("with an empty base path", func() {
+		BeforeEach(func() {
+			basePath = ""
+		})
+
+		It("normalizes the base path to /", func() {
+			Ω(design.Design.BasePath).Should(Equal("/"))
+			Ω(resource.FullPath()).Should(Equal("/res"))
+		})
+	})
+
+	Context("with a base path that does not start with a slash", func() {
+		BeforeEach(func() {
+			basePath = "api"
+		})
+
+		It("prefixes the base path with a slash", func() {
+			Ω(design.Design.BasePath).Should(Equal("/api"))
+			Ω(resource.FullPath()).Should(Equal("/api/res"))
+		})
+	})
+
+	Context("with a base path that starts with a slash", func() {
+		BeforeEach(func() {
+			basePath = "/api"
+		})
+
+		It("keeps the base path", func() {
+			Ω(design.Design.BasePath).Should(Equal("/api"))
+			Ω(resource.FullPath()).Should(Equal("/api/res"))
+		})
+	})
+})
+
+var _ = Describe("NormalizeServerURL", func() {
+	cases := map[string]string{
+		"https://example.com:443":          "https://example.com",
+		"wss://example.com:443/ws":         "wss://example.com/ws",
+		"http://example.com:80?debug=true": "http://example.com?debug=true",
+		"WS://example.com:80/":             "ws://example.com/",
+		"https://{host}:443/{version}":     "https://{host}/{version}",
+		"http://[::1]:80/api":              "http://[::1]/api",
+		"https://example.com:8443":         "https://example.com:8443",
+		"https://example.com:80":           "https://example.com:80",
+		"http://example.com:443":           "http://example.com:443",
+		"http://example.com:8080/api":      "http://example.com:8080/api",
+		"http://example.com/api:80":        "http://example.com/api:80",
+		"grpcs://example.com:443":          "grpcs://example.com:443",
+		"https://example.com":              "https://example.com",
+		"example.com:443":                  "example.com:443",
+	}
+	for u, expected := range cases {
+		u, expected := u, expected
+		It("normalizes "+u, func() {
+			Ω(design.NormalizeServerURL(u)).Should(Equal(expected))
+		})
+	}
+})
+
 var _ = Describe("AllParams", func() {
 	Context("Given a resource with a parent and an action with a route", func() {
 		var (
@@ -537,3 +860,213 @@ var _ = Describe("IterateSets", func() {
 	})
 
 })
+
+var _ = Describe("PruneResources", func() {
+	var names []string
+	var pruneErr error
+
+	BeforeEach(func() {
+		design.Design.Resources = map[string]*design.ResourceDefinition{
+			"parent": {Name: "parent"},
+			"child":  {Name: "child", ParentName: "parent"},
+			"other":  {Name: "other"},
+		}
+		names = nil
+	})
+
+	JustBeforeEach(func() {
+		pruneErr = design.Design.PruneResources(names...)
+	})
+
+	AfterEach(func() {
+		design.Design.Resources = nil
+	})
+
+	Context("with a child resource name", func() {
+		BeforeEach(func() {
+			names = []string{"child"}
+		})
+
+		It("keeps the resource and its parent", func() {
+			Ω(pruneErr).ShouldNot(HaveOccurred())
+			Ω(design.Design.Resources).Should(HaveLen(2))
+			Ω(design.Design.Resources).Should(HaveKey("child"))
+			Ω(design.Design.Resources).Should(HaveKey("parent"))
+		})
+	})
+
+	Context("with an unknown resource name", func() {
+		BeforeEach(func() {
+			names = []string{"unknown"}
+		})
+
+		It("returns an error", func() {
+			Ω(pruneErr).Should(HaveOccurred())
+			Ω(design.Design.Resources).Should(HaveLen(3))
+		})
+	})
+})
+
+var _ = Describe("ParentChain", func() {
+	var root, parent, child *design.ResourceDefinition
+
+	BeforeEach(func() {
+		root = &design.ResourceDefinition{Name: "root"}
+		parent = &design.ResourceDefinition{Name: "parent", ParentName: "root"}
+		child = &design.ResourceDefinition{Name: "child", ParentName: "parent"}
+		design.Design.Resources = map[string]*design.ResourceDefinition{
+			"root":   root,
+			"parent": parent,
+			"child":  child,
+		}
+	})
+
+	AfterEach(func() {
+		design.Design.Resources = nil
+	})
+
+	It("returns the ancestors from the immediate parent to the root", func() {
+		Ω(child.ParentChain()).Should(Equal([]*design.ResourceDefinition{parent, root}))
+		Ω(root.ParentChain()).Should(BeEmpty())
+	})
+
+	Context("with a cyclic definition", func() {
+		BeforeEach(func() {
+			root.ParentName = "child"
+		})
+
+		It("terminates", func() {
+			Ω(child.ParentChain()).Should(Equal([]*design.ResourceDefinition{parent, root}))
+			Ω(root.ParentChain()).Should(Equal([]*design.ResourceDefinition{child, parent}))
+		})
+
+		It("is reported by validation", func() {
+			err := child.Validate()
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(`Parent resource "parent" is part of a cycle`))
+		})
+	})
+})
+
+var _ = Describe("SetPathCleaner", func() {
+	var resource *design.ResourceDefinition
+	var route *design.RouteDefinition
+
+	BeforeEach(func() {
+		resource = &design.ResourceDefinition{Name: "res", BasePath: "/Bottles"}
+		action := &design.ActionDefinition{Name: "show", Parent: resource}
+		route = &design.RouteDefinition{Verb: "GET", Path: "/:ID", Parent: action}
+		action.Routes = []*design.RouteDefinition{route}
+		resource.Actions = map[string]*design.ActionDefinition{"show": action}
+		design.Design.Resources = map[string]*design.ResourceDefinition{"res": resource}
+		design.SetPathCleaner(func(p string) string {
+			return strings.ToLower(path.Clean(p))
+		})
+	})
+
+	AfterEach(func() {
+		design.SetPathCleaner(nil)
+		design.Design.Resources = nil
+	})
+
+	It("uses the custom cleaner to compute full paths", func() {
+		Ω(resource.FullPath()).Should(Equal("/bottles"))
+		Ω(route.FullPath()).Should(Equal("/bottles/:id"))
+	})
+
+	It("restores the default cleaner", func() {
+		design.SetPathCleaner(nil)
+		Ω(route.FullPath()).Should(Equal("/Bottles/:ID"))
+	})
+})
+
+var _ = Describe("OpenAPIPath", func() {
+	var action *design.ActionDefinition
+
+	BeforeEach(func() {
+		resource := &design.ResourceDefinition{Name: "users", BasePath: "/users"}
+		action = &design.ActionDefinition{Name: "show", Parent: resource}
+		resource.Actions = map[string]*design.ActionDefinition{"show": action}
+		design.Design.Resources = map[string]*design.ResourceDefinition{"users": resource}
+	})
+
+	AfterEach(func() {
+		design.Design.Resources = nil
+	})
+
+	It("returns the empty string for actions without routes", func() {
+		Ω(action.OpenAPIPath()).Should(Equal(""))
+	})
+
+	Context("with routes", func() {
+		BeforeEach(func() {
+			action.Routes = []*design.RouteDefinition{
+				{Verb: "GET", Path: "/:id", Parent: action},
+				{Verb: "GET", Path: "//files/*filepath", Parent: action},
+			}
+		})
+
+		It("uses the primary route full path with braced parameters", func() {
+			Ω(action.OpenAPIPath()).Should(Equal("/users/{id}"))
+		})
+
+		It("converts wildcards of the other routes", func() {
+			Ω(action.Routes[1].OpenAPIPath()).Should(Equal("/files/{filepath}"))
+		})
+	})
+
+	It("returns / for empty paths", func() {
+		Ω(design.OpenAPIPath("")).Should(Equal("/"))
+	})
+})
+
+var _ = Describe("RouteTable", func() {
+	var resource *design.ResourceDefinition
+
+	BeforeEach(func() {
+		resource = &design.ResourceDefinition{Name: "bottle", BasePath: "/bottles"}
+		newAction := func(name string, routes ...*design.RouteDefinition) *design.ActionDefinition {
+			a := &design.ActionDefinition{Name: name, Parent: resource, Routes: routes}
+			for _, r := range routes {
+				r.Parent = a
+			}
+			return a
+		}
+		resource.Actions = map[string]*design.ActionDefinition{
+			"update": newAction("update",
+				&design.RouteDefinition{Verb: "PUT", Path: "/:id"},
+				&design.RouteDefinition{Verb: "PATCH", Path: "/:id"}),
+			"show":   newAction("show", &design.RouteDefinition{Verb: "GET", Path: "/:id"}),
+			"list":   newAction("list", &design.RouteDefinition{Verb: "GET", Path: ""}),
+			"create": newAction("create", &design.RouteDefinition{Verb: "POST", Path: ""}),
+		}
+		design.Design.Resources = map[string]*design.ResourceDefinition{"bottle": resource}
+	})
+
+	AfterEach(func() {
+		design.Design.Resources = nil
+	})
+
+	It("maps each route to the action that handles it", func() {
+		var table []string
+		for _, e := range resource.RouteTable() {
+			Ω(e.Action.Name).Should(Equal(e.Method))
+			Ω(e.Action.Routes).Should(ContainElement(e.Route))
+			table = append(table, e.Verb+" "+e.Path+" "+e.Method)
+		}
+		Ω(table).Should(Equal([]string{
+			"GET /bottles list",
+			"POST /bottles create",
+			"GET /bottles/:id show",
+			"PATCH /bottles/:id update",
+			"PUT /bottles/:id update",
+		}))
+	})
+
+	It("is deterministic", func() {
+		first := resource.RouteTable()
+		for i := 0; i < 10; i++ {
+			Ω(resource.RouteTable()).Should(Equal(first))
+		}
+	})
+})
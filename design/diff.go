@@ -0,0 +1,268 @@
+package design
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+type (
+	// APIDiff describes the differences between two versions of an API design, see Diff.
+	APIDiff struct {
+		// AddedResources lists the names of the resources only defined by the new design.
+		AddedResources []string
+		// RemovedResources lists the names of the resources only defined by the old design.
+		RemovedResources []string
+		// Resources lists the differences of the resources defined by both designs sorted
+		// by resource name. Resources that did not change are omitted.
+		Resources []*ResourceDiff
+	}
+
+	// ResourceDiff describes the differences between two versions of a resource.
+	ResourceDiff struct {
+		// Name is the resource name.
+		Name string
+		// AddedActions lists the names of the actions only defined by the new resource.
+		AddedActions []string
+		// RemovedActions lists the names of the actions only defined by the old resource.
+		RemovedActions []string
+		// Actions lists the differences of the actions defined by both resources sorted by
+		// action name. Actions that did not change are omitted.
+		Actions []*ActionDiff
+	}
+
+	// ActionDiff describes the differences between two versions of an action.
+	ActionDiff struct {
+		// Name is the action name.
+		Name string
+		// AddedRoutes lists the routes only defined by the new action, e.g. "GET /bottles/:id".
+		AddedRoutes []string
+		// RemovedRoutes lists the routes only defined by the old action.
+		RemovedRoutes []string
+		// AddedStatuses lists the response status codes only defined by the new action.
+		AddedStatuses []int
+		// RemovedStatuses lists the response status codes only defined by the old action.
+		RemovedStatuses []int
+	}
+)
+
+// Diff compares the resources, actions, routes and response status codes of two versions of an
+// API design. The routes are compared using their HTTP verb and full path, the full paths are
+// computed from the base paths and parent resources of the design that defines them and not from
+// the global Design. Diff returns an empty report if the designs expose the same HTTP interface,
+// see APIDiff.Empty.
+func Diff(old, new *APIDefinition) *APIDiff {
+	diff := &APIDiff{}
+	oldRes, newRes := resourcesByName(old), resourcesByName(new)
+	diff.AddedResources, diff.RemovedResources = diffStrings(resourceNames(oldRes), resourceNames(newRes))
+	names := resourceNames(oldRes)
+	sort.Strings(names)
+	for _, n := range names {
+		nr, ok := newRes[n]
+		if !ok {
+			continue
+		}
+		if rd := diffResource(old, new, oldRes[n], nr); rd != nil {
+			diff.Resources = append(diff.Resources, rd)
+		}
+	}
+	return diff
+}
+
+// Empty returns true if the diff reports no difference.
+func (d *APIDiff) Empty() bool {
+	return len(d.AddedResources) == 0 && len(d.RemovedResources) == 0 && len(d.Resources) == 0
+}
+
+// Breaking returns true if the new design removes resources, actions, routes or response status
+// codes defined by the old design. Clients built against the old design may fail against the new
+// one.
+func (d *APIDiff) Breaking() bool {
+	if len(d.RemovedResources) > 0 {
+		return true
+	}
+	for _, r := range d.Resources {
+		if len(r.RemovedActions) > 0 {
+			return true
+		}
+		for _, a := range r.Actions {
+			if len(a.RemovedRoutes) > 0 || len(a.RemovedStatuses) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// diffResource returns the differences between the two resources of the old and new APIs, nil if
+// there is none.
+func diffResource(oldAPI, newAPI *APIDefinition, old, new *ResourceDefinition) *ResourceDiff {
+	rd := &ResourceDiff{Name: old.Name}
+	rd.AddedActions, rd.RemovedActions = diffStrings(actionNames(old.Actions), actionNames(new.Actions))
+	old.IterateActions(func(oa *ActionDefinition) error {
+		na, ok := new.Actions[oa.Name]
+		if !ok {
+			return nil
+		}
+		if ad := diffAction(oldAPI, newAPI, oa, na); ad != nil {
+			rd.Actions = append(rd.Actions, ad)
+		}
+		return nil
+	})
+	if len(rd.AddedActions) == 0 && len(rd.RemovedActions) == 0 && len(rd.Actions) == 0 {
+		return nil
+	}
+	return rd
+}
+
+// diffAction returns the differences between the two actions of the old and new APIs, nil if
+// there is none.
+func diffAction(oldAPI, newAPI *APIDefinition, old, new *ActionDefinition) *ActionDiff {
+	ad := &ActionDiff{Name: old.Name}
+	ad.AddedRoutes, ad.RemovedRoutes = diffStrings(routeKeys(oldAPI, old), routeKeys(newAPI, new))
+	ad.AddedStatuses, ad.RemovedStatuses = diffInts(statuses(old), statuses(new))
+	if len(ad.AddedRoutes) == 0 && len(ad.RemovedRoutes) == 0 &&
+		len(ad.AddedStatuses) == 0 && len(ad.RemovedStatuses) == 0 {
+		return nil
+	}
+	return ad
+}
+
+// resourcesByName indexes the API resources by name, it handles nil APIs.
+func resourcesByName(api *APIDefinition) map[string]*ResourceDefinition {
+	if api == nil {
+		return nil
+	}
+	return api.Resources
+}
+
+// routeKeys returns the verb and full path of the action routes in the given API.
+func routeKeys(api *APIDefinition, a *ActionDefinition) []string {
+	keys := make([]string, len(a.Routes))
+	for i, r := range a.Routes {
+		keys[i] = r.Verb + " " + routePath(api, r, nil)
+	}
+	return keys
+}
+
+// routePath returns the full path of the route in the given API, see RouteDefinition.FullPath.
+// seen records the resources whose path is being computed to stop on cyclic parents.
+func routePath(api *APIDefinition, r *RouteDefinition, seen map[*ResourceDefinition]bool) string {
+	if r.IsAbsolute() {
+		return pathCleaner(r.Path[1:])
+	}
+	var base string
+	if r.Parent != nil && r.Parent.Parent != nil {
+		base = resourcePath(api, r.Parent.Parent, seen)
+		if len(r.Parent.NoInheritedParams) > 0 {
+			base = removeWildcards(base, r.Parent.IsInheritedParam)
+		}
+	}
+	joined := path.Join(base, r.Path)
+	if strings.HasSuffix(r.Path, "/") {
+		joined += "/"
+	}
+	return pathCleaner(joined)
+}
+
+// resourcePath returns the full path of the resource in the given API, see
+// ResourceDefinition.FullPath.
+func resourcePath(api *APIDefinition, r *ResourceDefinition, seen map[*ResourceDefinition]bool) string {
+	if strings.HasPrefix(r.BasePath, "//") {
+		return pathCleaner(r.BasePath)
+	}
+	if seen == nil {
+		seen = make(map[*ResourceDefinition]bool)
+	}
+	seen[r] = true
+	var basePath string
+	if api != nil {
+		basePath = api.BasePath
+	}
+	if r.ParentName != "" && api != nil {
+		if p := api.Resource(r.ParentName); p != nil && !seen[p] {
+			basePath = ""
+			if ca := p.CanonicalAction(); ca != nil && len(ca.Routes) > 0 {
+				basePath = routePath(api, ca.Routes[0], seen)
+			}
+		}
+	}
+	return pathCleaner(path.Join(basePath, r.BasePath))
+}
+
+// statuses returns the status codes of the action responses.
+func statuses(a *ActionDefinition) []int {
+	codes := make([]int, 0, len(a.Responses))
+	for _, r := range a.Responses {
+		codes = append(codes, r.Status)
+	}
+	return codes
+}
+
+// resourceNames returns the names of the given resources.
+func resourceNames(m map[string]*ResourceDefinition) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	return names
+}
+
+// actionNames returns the names of the given actions.
+func actionNames(m map[string]*ActionDefinition) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	return names
+}
+
+// diffStrings returns the sorted values only present in new and the sorted values only present
+// in old.
+func diffStrings(old, new []string) (added, removed []string) {
+	o := make(map[string]bool, len(old))
+	for _, v := range old {
+		o[v] = true
+	}
+	n := make(map[string]bool, len(new))
+	for _, v := range new {
+		if !o[v] && !n[v] {
+			added = append(added, v)
+		}
+		n[v] = true
+	}
+	for _, v := range old {
+		if !n[v] {
+			removed = append(removed, v)
+			n[v] = true
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return
+}
+
+// diffInts returns the sorted values only present in new and the sorted values only present in
+// old.
+func diffInts(old, new []int) (added, removed []int) {
+	o := make(map[int]bool, len(old))
+	for _, v := range old {
+		o[v] = true
+	}
+	n := make(map[int]bool, len(new))
+	for _, v := range new {
+		if !o[v] && !n[v] {
+			added = append(added, v)
+		}
+		n[v] = true
+	}
+	for _, v := range old {
+		if !n[v] {
+			removed = append(removed, v)
+			n[v] = true
+		}
+	}
+	sort.Ints(added)
+	sort.Ints(removed)
+	return
+}
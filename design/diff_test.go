@@ -0,0 +1,123 @@
+package design_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	var old, new *APIDefinition
+	var diff *APIDiff
+
+	var basePath string
+
+	BeforeEach(func() {
+		basePath = ""
+	})
+
+	AfterEach(func() {
+		dslengine.Reset()
+	})
+
+	// design runs the DSL of a cellar API whose "show" action responds with the given status
+	// and that defines a "delete" action if withDelete is true. It returns a copy of the
+	// resulting API definition as the DSL engine reuses the Design root for the next run.
+	design := func(showStatus func(), withDelete bool) *APIDefinition {
+		dslengine.Reset()
+		API("cellar", func() {
+			if basePath != "" {
+				BasePath(basePath)
+			}
+		})
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			Action("show", func() {
+				Routing(GET("/:id"))
+				showStatus()
+			})
+			if withDelete {
+				Action("delete", func() {
+					Routing(DELETE("/:id"))
+					Response(NoContent)
+				})
+			}
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		api := *Design
+		return &api
+	}
+
+	JustBeforeEach(func() {
+		diff = Diff(old, new)
+	})
+
+	Context("with identical designs", func() {
+		BeforeEach(func() {
+			old = design(func() { Response(OK) }, true)
+			new = design(func() { Response(OK) }, true)
+		})
+
+		It("reports no difference", func() {
+			Ω(diff.Empty()).Should(BeTrue())
+			Ω(diff.Breaking()).Should(BeFalse())
+		})
+	})
+
+	Context("with a removed action and a changed status code", func() {
+		BeforeEach(func() {
+			old = design(func() { Response(OK) }, true)
+			new = design(func() { Response(Accepted) }, false)
+		})
+
+		It("reports the differences", func() {
+			Ω(diff.AddedResources).Should(BeEmpty())
+			Ω(diff.RemovedResources).Should(BeEmpty())
+			Ω(diff.Resources).Should(HaveLen(1))
+			rd := diff.Resources[0]
+			Ω(rd.Name).Should(Equal("bottle"))
+			Ω(rd.AddedActions).Should(BeEmpty())
+			Ω(rd.RemovedActions).Should(Equal([]string{"delete"}))
+			Ω(rd.Actions).Should(HaveLen(1))
+			ad := rd.Actions[0]
+			Ω(ad.Name).Should(Equal("show"))
+			Ω(ad.AddedRoutes).Should(BeEmpty())
+			Ω(ad.RemovedRoutes).Should(BeEmpty())
+			Ω(ad.AddedStatuses).Should(Equal([]int{202}))
+			Ω(ad.RemovedStatuses).Should(Equal([]int{200}))
+			Ω(diff.Breaking()).Should(BeTrue())
+		})
+	})
+
+	Context("with an added action", func() {
+		BeforeEach(func() {
+			old = design(func() { Response(OK) }, false)
+			new = design(func() { Response(OK) }, true)
+		})
+
+		It("reports a non breaking change", func() {
+			Ω(diff.Resources).Should(HaveLen(1))
+			Ω(diff.Resources[0].AddedActions).Should(Equal([]string{"delete"}))
+			Ω(diff.Breaking()).Should(BeFalse())
+		})
+	})
+
+	Context("with a changed base path", func() {
+		BeforeEach(func() {
+			old = design(func() { Response(OK) }, false)
+			basePath = "/v2"
+			new = design(func() { Response(OK) }, false)
+		})
+
+		It("reports the changed routes", func() {
+			Ω(diff.Resources).Should(HaveLen(1))
+			Ω(diff.Resources[0].Actions).Should(HaveLen(1))
+			ad := diff.Resources[0].Actions[0]
+			Ω(ad.AddedRoutes).Should(Equal([]string{"GET /v2/bottles/:id"}))
+			Ω(ad.RemovedRoutes).Should(Equal([]string{"GET /bottles/:id"}))
+			Ω(diff.Breaking()).Should(BeTrue())
+		})
+	})
+})
@@ -44,13 +44,26 @@ func (d *dupper) DupAttribute(att *AttributeDefinition) *AttributeDefinition {
 		Type:              att.Type,
 		Description:       att.Description,
 		Validation:        valDup,
-		Metadata:          att.Metadata,
 		DefaultValue:      att.DefaultValue,
 		NonZeroAttributes: att.NonZeroAttributes,
 		View:              att.View,
 		DSLFunc:           att.DSLFunc,
 		Example:           att.Example,
 	}
+	if att.Metadata != nil {
+		// The metadata of the copy may be modified by the DSL of attributes that use the
+		// original as reference, make sure they don't modify the original.
+		dup.Metadata = make(dslengine.MetadataDefinition, len(att.Metadata))
+		for k, v := range att.Metadata {
+			dup.Metadata[k] = append([]string(nil), v...)
+		}
+	}
+	if len(att.Provenance) > 0 {
+		dup.Provenance = make(map[string]string, len(att.Provenance))
+		for k, v := range att.Provenance {
+			dup.Provenance[k] = v
+		}
+	}
 	return &dup
 }
 
@@ -3,7 +3,12 @@ package design
 import (
 	"fmt"
 	"math"
+	"net/http"
+	"net/url"
+	"reflect"
 	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	regen "github.com/zach-klippenstein/goregen"
@@ -279,3 +284,204 @@ func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
 	}
 	panic("Validation: Min > Max")
 }
+
+// ActionExample describes an example request made to an action together with the response the
+// action is expected to write. The example values are the ones used to render the API
+// documentation.
+type ActionExample struct {
+	// Route is the action route used to make the request.
+	Route *RouteDefinition
+	// Path is the request path, the route wildcards are replaced with example values.
+	Path string
+	// Query contains the example values of the required query string parameters.
+	Query url.Values
+	// Headers contains the example values of the required request headers.
+	Headers http.Header
+	// Payload is the example request payload, nil if the action does not define a payload.
+	Payload interface{}
+	// Response is the success response written by the action.
+	Response *ResponseDefinition
+	// MediaType is the media type of the response.
+	MediaType *MediaTypeDefinition
+	// View is the name of the view used to render the response.
+	View string
+	// Body is the example response body.
+	Body interface{}
+}
+
+// Example returns an example request made to the action and the expected response. The request
+// uses the action first route and sets the required parameters and headers. The response is the
+// success response with the lowest status code that has a media type. Example returns nil if
+// the action has no such response or if example values are not available for the response body,
+// the payload, or the required parameters and headers. Example also returns nil for actions
+// that define payloads per content type.
+func (a *ActionDefinition) Example() *ActionExample {
+	if len(a.Routes) == 0 || len(a.Payloads) > 0 || Design.NoExamples {
+		return nil
+	}
+	rand := Design.RandomGenerator()
+	ex := &ActionExample{Route: a.Routes[0], Query: url.Values{}, Headers: http.Header{}}
+
+	// Pick response
+	var statuses []int
+	byStatus := make(map[int]*ResponseDefinition)
+	for _, r := range a.Responses {
+		if r.Status >= 200 && r.Status < 300 {
+			statuses = append(statuses, r.Status)
+			byStatus[r.Status] = r
+		}
+	}
+	sort.Ints(statuses)
+	for _, s := range statuses {
+		r := byStatus[s]
+		if r.Type != nil {
+			continue
+		}
+		mt := Design.MediaTypeWithIdentifier(r.MediaType)
+		if mt == nil || mt.IsError() {
+			continue
+		}
+		view := r.ViewName
+		if view == "" {
+			view = DefaultView
+		}
+		projected, _, err := mt.Project(view)
+		if err != nil {
+			continue
+		}
+		ex.Response, ex.MediaType, ex.View = r, mt, view
+		ex.Body = exampleValue(projected.GenerateExample(rand, nil))
+		break
+	}
+	if ex.Body == nil {
+		return nil
+	}
+
+	if !a.buildRequestExample(ex, rand) {
+		return nil
+	}
+	return ex
+}
+
+// RequestExample returns an example request made to the action using the given route. The
+// request sets the required parameters and headers and the payload if any. The response fields
+// of the returned value are not set. RequestExample returns nil if example values are not
+// available for the payload or the required parameters and headers, or if the action defines
+// payloads per content type.
+func (a *ActionDefinition) RequestExample(route *RouteDefinition) *ActionExample {
+	if len(a.Payloads) > 0 || Design.NoExamples {
+		return nil
+	}
+	ex := &ActionExample{Route: route, Query: url.Values{}, Headers: http.Header{}}
+	if !a.buildRequestExample(ex, Design.RandomGenerator()) {
+		return nil
+	}
+	return ex
+}
+
+// buildRequestExample initializes the request path, query string, headers and payload of ex using
+// the route ex.Route. It returns false if an example value is not available.
+func (a *ActionDefinition) buildRequestExample(ex *ActionExample, rand *RandomGenerator) bool {
+	if a.Payload != nil {
+		if ex.Payload = exampleValue(a.Payload.GenerateExample(rand, nil)); ex.Payload == nil {
+			return false
+		}
+	}
+	var params Object
+	if a.Params != nil {
+		params = a.Params.Type.ToObject()
+	}
+	path := ex.Route.FullPath()
+	for _, w := range ex.Route.Params() {
+		att, ok := params[w]
+		if !ok {
+			return false
+		}
+		v := exampleValue(att.GenerateExample(rand, nil))
+		if v == nil {
+			return false
+		}
+		path = strings.Replace(path, ":"+w, url.PathEscape(exampleString(v)), 1)
+		path = strings.Replace(path, "*"+w, exampleString(v), 1)
+	}
+	ex.Path = path
+	if a.QueryParams != nil {
+		query := a.QueryParams.Type.ToObject()
+		for _, n := range sortedKeys(query) {
+			if !a.QueryParams.IsRequired(n) {
+				continue
+			}
+			v := exampleValue(query[n].GenerateExample(rand, nil))
+			if v == nil {
+				return false
+			}
+			// Array query string parameters are encoded by repeating the key.
+			if vals, ok := exampleStrings(v); ok {
+				for _, e := range vals {
+					ex.Query.Add(n, e)
+				}
+				continue
+			}
+			ex.Query.Set(n, exampleString(v))
+		}
+	}
+	headers := a.AllHeaders()
+	obj := headers.Type.ToObject()
+	for _, n := range sortedKeys(obj) {
+		if !headers.IsRequired(n) {
+			continue
+		}
+		v := exampleValue(obj[n].GenerateExample(rand, nil))
+		if v == nil {
+			return false
+		}
+		// Array headers are comma separated.
+		if vals, ok := exampleStrings(v); ok {
+			ex.Headers.Set(n, strings.Join(vals, ","))
+			continue
+		}
+		ex.Headers.Set(n, exampleString(v))
+	}
+	return true
+}
+
+// sortedKeys returns the names of the object attributes in alphabetical order.
+func sortedKeys(o Object) []string {
+	keys := make([]string, 0, len(o))
+	for n := range o {
+		keys = append(keys, n)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// exampleValue returns nil if v indicates that no example should be used.
+func exampleValue(v interface{}) interface{} {
+	if s, ok := v.(string); ok && s == "-" {
+		return nil
+	}
+	return v
+}
+
+// exampleString returns the string representation of the primitive example value v used in
+// request paths, query strings and headers.
+func exampleString(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// exampleStrings returns the string representations of the elements of the array example value
+// v. The boolean return value is false if v is not an array. Byte slices are not arrays.
+func exampleStrings(v interface{}) ([]string, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	res := make([]string, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		res[i] = exampleString(rv.Index(i).Interface())
+	}
+	return res, true
+}
@@ -0,0 +1,103 @@
+package design
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TrackProvenance enables the recording of the definitions that contribute the properties of
+// the attributes defined with a reference type, see AttributeDefinition.Provenance. Tracking is
+// disabled by default as only the tools that audit the design need it. It must be enabled before
+// the DSL runs.
+var TrackProvenance bool
+
+// AttributeProperty is a property of the effective definition of an attribute.
+type AttributeProperty struct {
+	// Name is the property name, e.g. "description", "minimum" or "metadata:struct:tag:json".
+	Name string
+	// Value is the property value formatted for display.
+	Value string
+}
+
+// Properties returns the properties of the attribute that are set. The type comes first followed
+// by the description, the default value, the view, the validations and the metadata sorted by
+// key. Examples are not included as they may be generated randomly.
+func (a *AttributeDefinition) Properties() []*AttributeProperty {
+	var props []*AttributeProperty
+	add := func(name, format string, val interface{}) {
+		props = append(props, &AttributeProperty{Name: name, Value: fmt.Sprintf(format, val)})
+	}
+	if a.Type != nil {
+		add("type", "%s", a.Type.Name())
+	}
+	if a.Description != "" {
+		add("description", "%q", a.Description)
+	}
+	if a.DefaultValue != nil {
+		add("default", "%#v", a.DefaultValue)
+	}
+	if a.View != "" {
+		add("view", "%s", a.View)
+	}
+	if v := a.Validation; v != nil {
+		if len(v.Values) > 0 {
+			add("enum", "%#v", v.Values)
+		}
+		if v.Format != "" {
+			add("format", "%s", v.Format)
+		}
+		if v.Pattern != "" {
+			add("pattern", "%q", v.Pattern)
+		}
+		if v.Minimum != nil {
+			add("minimum", "%v", *v.Minimum)
+		}
+		if v.Maximum != nil {
+			add("maximum", "%v", *v.Maximum)
+		}
+		if v.MinLength != nil {
+			add("min length", "%d", *v.MinLength)
+		}
+		if v.MaxLength != nil {
+			add("max length", "%d", *v.MaxLength)
+		}
+		if len(v.Required) > 0 {
+			add("required", "%s", strings.Join(v.Required, ", "))
+		}
+	}
+	for _, k := range a.Metadata.Keys() {
+		add("metadata:"+k, "%q", a.Metadata[k])
+	}
+	return props
+}
+
+// RecordProvenance records source as the origin of the inherited properties that the attribute
+// still has, that is the properties whose value was not overridden by the attribute DSL.
+// inherited is the result of calling Properties on the attribute before its DSL runs. The
+// properties whose origin is already recorded keep it so that the provenance of attributes
+// inherited through multiple references is the definition that originally set them.
+// RecordProvenance does nothing unless TrackProvenance is true.
+func (a *AttributeDefinition) RecordProvenance(inherited []*AttributeProperty, source string) {
+	if !TrackProvenance {
+		return
+	}
+	current := make(map[string]string)
+	for _, p := range a.Properties() {
+		current[p.Name] = p.Value
+	}
+	var prov map[string]string
+	for _, p := range inherited {
+		if val, ok := current[p.Name]; !ok || val != p.Value {
+			continue
+		}
+		if prov == nil {
+			prov = make(map[string]string)
+		}
+		if orig, ok := a.Provenance[p.Name]; ok {
+			prov[p.Name] = orig
+		} else {
+			prov[p.Name] = source
+		}
+	}
+	a.Provenance = prov
+}
@@ -59,6 +59,18 @@ func (r *RandomGenerator) DateTime() time.Time {
 	return time.Unix(unix, 0).UTC()
 }
 
+// Duration produces a random duration of less than a day rounded to the second.
+func (r *RandomGenerator) Duration() time.Duration {
+	return time.Duration(r.rand.Int63n(24*60*60)) * time.Second
+}
+
+// Bytes produces random bytes.
+func (r *RandomGenerator) Bytes() []byte {
+	b := make([]byte, 8+r.rand.Intn(24))
+	r.rand.Read(b)
+	return b
+}
+
 // UUID produces a random UUID.
 func (r *RandomGenerator) UUID() uuid.UUID {
 	return uuid.Must(uuid.NewV4())
@@ -3,6 +3,7 @@ package design
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/goadesign/goa/dslengine"
 )
@@ -31,11 +32,101 @@ type SecurityDefinition struct {
 
 	// Scopes are scopes required for this action
 	Scopes []string `json:"scopes,omitempty"`
+
+	// ScopeExpr is an optional boolean expression of scopes required for this action in
+	// addition to Scopes, see RequiredScopes.
+	ScopeExpr *ScopeExpr `json:"scope_expr,omitempty"`
+}
+
+// ScopeOperator is the operator of a scope expression.
+type ScopeOperator int
+
+const (
+	// ScopeLeaf is the operator of a scope expression that requires a single scope.
+	ScopeLeaf ScopeOperator = iota
+	// ScopeAnd is the operator of a scope expression that requires all its operands.
+	ScopeAnd
+	// ScopeOr is the operator of a scope expression that requires any of its operands.
+	ScopeOr
+)
+
+// ScopeExpr is a boolean expression of authorization scopes, e.g.
+// "admin OR (editor AND org:member)".
+type ScopeExpr struct {
+	// Operator is the expression operator.
+	Operator ScopeOperator `json:"operator"`
+	// Scope is the scope required by a ScopeLeaf expression.
+	Scope string `json:"scope,omitempty"`
+	// Operands lists the operands of a ScopeAnd or ScopeOr expression.
+	Operands []*ScopeExpr `json:"operands,omitempty"`
 }
 
 // Context returns the generic definition name used in error messages.
 func (s *SecurityDefinition) Context() string { return "Security" }
 
+// Scopes returns the scopes referenced by the expression in order of first appearance.
+func (e *ScopeExpr) Scopes() []string {
+	var scopes []string
+	seen := make(map[string]bool)
+	var collect func(*ScopeExpr)
+	collect = func(e *ScopeExpr) {
+		if e.Operator == ScopeLeaf {
+			if !seen[e.Scope] {
+				seen[e.Scope] = true
+				scopes = append(scopes, e.Scope)
+			}
+			return
+		}
+		for _, o := range e.Operands {
+			collect(o)
+		}
+	}
+	collect(e)
+	return scopes
+}
+
+// Eval returns true if the given scopes satisfy the expression.
+func (e *ScopeExpr) Eval(scopes map[string]bool) bool {
+	switch e.Operator {
+	case ScopeAnd:
+		for _, o := range e.Operands {
+			if !o.Eval(scopes) {
+				return false
+			}
+		}
+		return true
+	case ScopeOr:
+		for _, o := range e.Operands {
+			if o.Eval(scopes) {
+				return true
+			}
+		}
+		return false
+	default:
+		return scopes[e.Scope]
+	}
+}
+
+// String returns the expression using the AND and OR operators, nested expressions are
+// surrounded with parenthesis, e.g. "admin OR (editor AND org:member)".
+func (e *ScopeExpr) String() string {
+	if e.Operator == ScopeLeaf {
+		return e.Scope
+	}
+	op := " AND "
+	if e.Operator == ScopeOr {
+		op = " OR "
+	}
+	elems := make([]string, len(e.Operands))
+	for i, o := range e.Operands {
+		elems[i] = o.String()
+		if o.Operator != ScopeLeaf {
+			elems[i] = "(" + elems[i] + ")"
+		}
+	}
+	return strings.Join(elems, op)
+}
+
 // SecuritySchemeDefinition defines a security scheme used to
 // authenticate against the API being designed. See
 // http://swagger.io/specification/#securityDefinitionsObject for more
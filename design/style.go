@@ -0,0 +1,198 @@
+package design
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+const (
+	// KebabStyle is the naming style of the names made of lowercase words separated with
+	// hyphens, e.g. "wine-bottles".
+	KebabStyle = "kebab"
+	// SnakeStyle is the naming style of the names made of lowercase words separated with
+	// underscores, e.g. "vintage_year".
+	SnakeStyle = "snake"
+
+	// PathStyleMetadataKey is the API metadata key that sets the naming style of the HTTP
+	// path segments, e.g. Metadata("style:paths", "kebab").
+	PathStyleMetadataKey = "style:paths"
+	// FieldStyleMetadataKey is the API metadata key that sets the naming style of the query
+	// string parameters and of the body fields, e.g. Metadata("style:fields", "snake").
+	FieldStyleMetadataKey = "style:fields"
+)
+
+var (
+	// styleRegexes maps the naming styles to the regular expressions matching the names that
+	// follow them. Dots are allowed in path segments to accommodate file extensions.
+	styleRegexes = map[string]*regexp.Regexp{
+		KebabStyle: regexp.MustCompile(`^[a-z0-9]+(?:[-.][a-z0-9]+)*$`),
+		SnakeStyle: regexp.MustCompile(`^[a-z0-9]+(?:[_.][a-z0-9]+)*$`),
+	}
+
+	// styleSeparators maps the naming styles to the separator of their words.
+	styleSeparators = map[string]string{KebabStyle: "-", SnakeStyle: "_"}
+)
+
+// styleChecker reports the names that do not follow the naming styles set on the API.
+type styleChecker struct {
+	api    *APIDefinition
+	paths  string
+	fields string
+	verr   *dslengine.ValidationErrors
+	seen   map[*AttributeDefinition]bool
+}
+
+// validateNamingStyle reports the HTTP path segments, query string parameters and body fields
+// whose names do not follow the styles set with the "style:paths" and "style:fields" API
+// metadata. The violations are reported as warnings or as errors in strict mode. The Go names set
+// with the "struct:field:name" metadata and the header names are not checked.
+func (a *APIDefinition) validateNamingStyle(verr *dslengine.ValidationErrors) {
+	c := &styleChecker{api: a, verr: verr, seen: make(map[*AttributeDefinition]bool)}
+	for _, s := range []struct {
+		key   string
+		style *string
+	}{{PathStyleMetadataKey, &c.paths}, {FieldStyleMetadataKey, &c.fields}} {
+		v, ok := a.Metadata.Last(s.key)
+		if !ok {
+			continue
+		}
+		if _, ok := styleRegexes[v]; !ok {
+			verr.Add(a, "invalid %#v metadata value %#v, must be %#v or %#v", s.key, v, KebabStyle, SnakeStyle)
+			continue
+		}
+		*s.style = v
+	}
+	if c.paths == "" && c.fields == "" {
+		return
+	}
+	c.path(a, "base path", a.BasePath)
+	a.IterateResources(func(r *ResourceDefinition) error {
+		c.path(r, "base path", r.BasePath)
+		return r.IterateActions(func(act *ActionDefinition) error {
+			wildcards := make(map[string]bool)
+			for _, ro := range act.Routes {
+				c.path(ro, "path", ro.Path)
+				for _, p := range ro.Params() {
+					wildcards[p] = true
+				}
+			}
+			if act.Params != nil {
+				for _, n := range sortedNames(act.Params.Type.ToObject()) {
+					if !wildcards[n] {
+						c.name(act, c.fields, "query string parameter", n)
+					}
+				}
+			}
+			if act.Payload != nil {
+				c.attribute(act, "payload field", act.Payload.AttributeDefinition)
+			}
+			return nil
+		})
+	})
+	a.IterateUserTypes(func(t *UserTypeDefinition) error {
+		c.attribute(t, "field", t.AttributeDefinition)
+		return nil
+	})
+	a.IterateMediaTypes(func(mt *MediaTypeDefinition) error {
+		c.attribute(mt, "field", mt.AttributeDefinition)
+		return nil
+	})
+}
+
+// path checks the literal segments of the given path, the wildcards and the version placeholder
+// are skipped.
+func (c *styleChecker) path(def dslengine.Definition, what, p string) {
+	if c.paths == "" {
+		return
+	}
+	for _, seg := range strings.Split(p, "/") {
+		if seg == "" || seg == VersionPlaceholder || strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			continue
+		}
+		c.name(def, c.paths, what+" segment", seg)
+	}
+}
+
+// attribute checks the names of the fields of the attribute recursively. The attributes of the
+// API user types and media types are checked once when iterating over the types.
+func (c *styleChecker) attribute(def dslengine.Definition, what string, att *AttributeDefinition) {
+	if c.fields == "" || att == nil || c.seen[att] {
+		return
+	}
+	c.seen[att] = true
+	switch t := att.Type.(type) {
+	case *UserTypeDefinition:
+		if c.api.Types[t.TypeName] == t {
+			return
+		}
+		c.attribute(def, what, t.AttributeDefinition)
+	case *MediaTypeDefinition:
+		if c.api.MediaTypes[CanonicalIdentifier(t.Identifier)] == t {
+			return
+		}
+		c.attribute(def, what, t.AttributeDefinition)
+	case Object:
+		for _, n := range sortedNames(t) {
+			c.name(def, c.fields, what, n)
+			c.attribute(def, what, t[n])
+		}
+	case *Array:
+		c.attribute(def, what, t.ElemType)
+	case *Hash:
+		c.attribute(def, what, t.ElemType)
+	}
+}
+
+// name reports name if it does not follow style.
+func (c *styleChecker) name(def dslengine.Definition, style, what, name string) {
+	if style == "" || styleRegexes[style].MatchString(name) {
+		return
+	}
+	c.verr.Warn(def, "%s %#v is not %s-case, use %#v", what, name, style, styleName(name, style))
+}
+
+// styleName returns the name converted to the given style.
+func styleName(name, style string) string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r == '.':
+			flush()
+			words = append(words, ".")
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(word) > 0 &&
+			(unicode.IsLower(word[len(word)-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+	res := strings.Join(words, styleSeparators[style])
+	sep := styleSeparators[style]
+	return strings.Replace(res, sep+"."+sep, ".", -1)
+}
+
+// sortedNames returns the names of the object attributes in alphabetical order.
+func sortedNames(o Object) []string {
+	names := make([]string, 0, len(o))
+	for n := range o {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
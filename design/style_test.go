@@ -0,0 +1,88 @@
+package design_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("naming style validation", func() {
+	var fieldStyle string
+	var strict bool
+
+	BeforeEach(func() {
+		fieldStyle = SnakeStyle
+		strict = false
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Reset()
+		dslengine.Strict = strict
+		API("test", func() {
+			Metadata("style:paths", KebabStyle)
+			Metadata("style:fields", fieldStyle)
+		})
+		Type("Bottle", func() {
+			Attribute("vintageYear", Integer, func() {
+				Metadata("struct:field:name", "VintageYear")
+			})
+			Attribute("name", String)
+		})
+		Resource("wineBottles", func() {
+			BasePath("/wineBottles")
+			Action("list", func() {
+				Routing(GET("/:cellarID/by_region"))
+				Params(func() {
+					Param("cellarID", String)
+					Param("sortBy", String)
+				})
+			})
+			Action("create", func() {
+				Routing(POST(""))
+				Payload(func() {
+					Member("grapeVariety", String)
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	AfterEach(func() {
+		dslengine.Strict = false
+	})
+
+	It("reports the violations as warnings with suggestions", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(dslengine.Warnings).Should(ConsistOf(
+			`resource "wineBottles": base path segment "wineBottles" is not kebab-case, use "wine-bottles"`,
+			`route GET "/:cellarID/by_region" of resource "wineBottles" action "list": path segment "by_region" is not kebab-case, use "by-region"`,
+			`resource "wineBottles" action "list": query string parameter "sortBy" is not snake-case, use "sort_by"`,
+			`resource "wineBottles" action "create": payload field "grapeVariety" is not snake-case, use "grape_variety"`,
+			`type "Bottle": field "vintageYear" is not snake-case, use "vintage_year"`,
+		))
+	})
+
+	Context("in strict mode", func() {
+		BeforeEach(func() {
+			strict = true
+		})
+
+		It("reports the violations as errors", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`query string parameter "sortBy" is not snake-case, use "sort_by"`))
+		})
+	})
+
+	Context("with an invalid style", func() {
+		BeforeEach(func() {
+			fieldStyle = "camel"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid "style:fields" metadata value "camel", must be "kebab" or "snake"`))
+		})
+	})
+})
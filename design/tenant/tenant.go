@@ -0,0 +1,111 @@
+/*
+Package tenant is an example DSL plugin. It adds a required tenant ID header, a "Forbidden" response
+and the "tenant:header" metadata to all the API actions so that multi-tenant APIs do not need to
+repeat the corresponding DSL in each action definition.
+
+Import the package for its side effects in the design package to enable the plugin:
+
+	import _ "github.com/goadesign/goa/design/tenant"
+
+The plugin only changes the design: the generated contexts reject the requests that are missing
+the tenant header as they do for any required header, but nothing checks that the tenant is
+allowed to make the request. The service must implement that check, for example in a middleware
+or in the controllers, and respond with the "Forbidden" response when it fails.
+*/
+package tenant
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+const (
+	// Header is the name of the header added to all the API actions.
+	Header = "X-Tenant-ID"
+
+	// MetadataKey is the metadata key set on all the API actions, its value is the name of the
+	// tenant header.
+	MetadataKey = "tenant:header"
+)
+
+func init() {
+	dslengine.RegisterPlugin("tenant", AddTenantHeader)
+	dslengine.RegisterInspector("tenant", CheckTenantHeader)
+	dslengine.RegisterMetadataKeys("tenant", "header")
+}
+
+// AddTenantHeader adds the tenant header, the "Forbidden" response and the tenant metadata to
+// all the actions of the API definition found in roots.
+func AddTenantHeader(roots []dslengine.Root) error {
+	api := apiDefinition(roots)
+	if api == nil {
+		return nil
+	}
+	return api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Headers == nil {
+				a.Headers = &design.AttributeDefinition{Type: design.Object{}}
+			}
+			headers := a.Headers.Type.ToObject()
+			if headers == nil {
+				return fmt.Errorf("headers of %s are not an object", a.Context())
+			}
+			if _, ok := headers[Header]; !ok {
+				headers[Header] = &design.AttributeDefinition{
+					Type:        design.String,
+					Description: "ID of tenant making the request",
+				}
+			}
+			if a.Headers.Validation == nil {
+				a.Headers.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Headers.Validation.AddRequired([]string{Header})
+
+			if _, ok := a.Responses[design.Forbidden]; !ok {
+				if def, ok := api.DefaultResponses[design.Forbidden]; ok {
+					if a.Responses == nil {
+						a.Responses = make(map[string]*design.ResponseDefinition)
+					}
+					resp := def.Dup()
+					resp.Parent = a
+					a.Responses[design.Forbidden] = resp
+				}
+			}
+
+			if a.Metadata == nil {
+				a.Metadata = make(dslengine.MetadataDefinition)
+			}
+			a.Metadata[MetadataKey] = []string{Header}
+			return nil
+		})
+	})
+}
+
+// CheckTenantHeader makes sure that all the actions of the finalized API definition require the
+// tenant header.
+func CheckTenantHeader(roots []dslengine.Root) error {
+	api := apiDefinition(roots)
+	if api == nil {
+		return nil
+	}
+	return api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Headers == nil || !a.Headers.IsRequired(Header) {
+				return fmt.Errorf("%s does not require the %s header", a.Context(), Header)
+			}
+			return nil
+		})
+	})
+}
+
+// apiDefinition returns the API definition found in roots if any.
+func apiDefinition(roots []dslengine.Root) *design.APIDefinition {
+	for _, r := range roots {
+		if api, ok := r.(*design.APIDefinition); ok {
+			return api
+		}
+	}
+	return nil
+}
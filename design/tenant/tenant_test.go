@@ -0,0 +1,35 @@
+package tenant_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/design/tenant"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AddTenantHeader", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("foo", func() {})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Params(func() {
+					Param("id", Integer)
+				})
+			})
+		})
+	})
+
+	It("adds the tenant header, response and metadata to all actions", func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		show := Design.Resources["bottle"].Actions["show"]
+		Ω(show.Headers).ShouldNot(BeNil())
+		Ω(show.Headers.Type.ToObject()).Should(HaveKey(tenant.Header))
+		Ω(show.Headers.IsRequired(tenant.Header)).Should(BeTrue())
+		Ω(show.Responses).Should(HaveKey(Forbidden))
+		Ω(show.Metadata).Should(HaveKeyWithValue(tenant.MetadataKey, []string{tenant.Header}))
+	})
+})
@@ -10,6 +10,7 @@
 package design
 
 import (
+	"encoding/base64"
 	"fmt"
 	"mime"
 	"reflect"
@@ -165,6 +166,10 @@ const (
 	MediaTypeKind
 	// FileKind represents a file.
 	FileKind
+	// DurationKind represents a JSON string that is parsed as a Go time.Duration.
+	DurationKind
+	// BytesKind represents a JSON base64 encoded string that is parsed as a Go []byte.
+	BytesKind
 )
 
 const (
@@ -193,6 +198,15 @@ const (
 
 	// File is the type for a file. This type can only be used in a multipart definition.
 	File = Primitive(FileKind)
+
+	// Duration is the type for a JSON string parsed as a Go time.Duration.
+	// Duration expects a value formatted as a Go duration string, e.g. "1h30m".
+	Duration = Primitive(DurationKind)
+
+	// Bytes is the type for binary data, a base64 encoded JSON string parsed as a Go []byte.
+	// Bytes payloads of actions that consume non structured MIME types such as
+	// "application/octet-stream" are read and written as is, see ActionDefinition.BinaryPayload.
+	Bytes = Primitive(BytesKind)
 )
 
 // DataType implementation
@@ -209,7 +223,7 @@ func (p Primitive) Name() string {
 		return "integer"
 	case Number:
 		return "number"
-	case String, DateTime, UUID:
+	case String, DateTime, UUID, Duration, Bytes:
 		return "string"
 	case Any:
 		return "any"
@@ -247,7 +261,7 @@ func (p Primitive) ToHash() *Hash { return nil }
 // CanHaveDefault returns whether the primitive can have a default value.
 func (p Primitive) CanHaveDefault() (ok bool) {
 	switch p {
-	case Boolean, Integer, Number, String, DateTime:
+	case Boolean, Integer, Number, String, DateTime, Duration:
 		ok = true
 	}
 	return
@@ -255,7 +269,7 @@ func (p Primitive) CanHaveDefault() (ok bool) {
 
 // IsCompatible returns true if val is compatible with p.
 func (p Primitive) IsCompatible(val interface{}) bool {
-	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any {
+	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Duration && p != Bytes && p != Any {
 		panic("unknown primitive type") // bug
 	}
 	if p == Any {
@@ -264,6 +278,8 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 	switch val.(type) {
 	case bool:
 		return p == Boolean
+	case []byte:
+		return p == Bytes
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
 		return p == Integer || p == Number
 	case float32, float64:
@@ -280,6 +296,14 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 			_, err := uuid.FromString(val.(string))
 			return err == nil
 		}
+		if p == Duration {
+			_, err := time.ParseDuration(val.(string))
+			return err == nil
+		}
+		if p == Bytes {
+			_, err := base64.StdEncoding.DecodeString(val.(string))
+			return err == nil
+		}
 	}
 	return false
 }
@@ -301,6 +325,10 @@ func (p Primitive) GenerateExample(r *RandomGenerator, seen []string) interface{
 		return r.DateTime()
 	case UUID:
 		return r.UUID().String() // Generate string to can be JSON marshaled
+	case Duration:
+		return r.Duration().String() // Generate string to can be JSON marshaled
+	case Bytes:
+		return r.Bytes()
 	case Any:
 		// to not make it too complicated, pick one of the primitive types
 		return anyPrimitive[r.Int()%len(anyPrimitive)].GenerateExample(r, seen)
@@ -600,6 +628,37 @@ func UserTypes(dt DataType) map[string]*UserTypeDefinition {
 	}
 }
 
+// IsText returns true if values of the given type may be written as is in plain text HTTP
+// bodies, that is if the type is a boolean, an integer, a number or a string.
+func IsText(dt DataType) bool {
+	if dt == nil {
+		return false
+	}
+	switch actual := dt.(type) {
+	case *UserTypeDefinition:
+		return IsText(actual.Type)
+	case *MediaTypeDefinition:
+		return IsText(actual.Type)
+	}
+	switch dt.Kind() {
+	case BooleanKind, IntegerKind, NumberKind, StringKind:
+		return true
+	}
+	return false
+}
+
+// IsBinary returns true if the given type is Bytes or a user type whose underlying type is
+// Bytes.
+func IsBinary(dt DataType) bool {
+	switch actual := dt.(type) {
+	case *UserTypeDefinition:
+		return actual != nil && IsBinary(actual.Type)
+	case *MediaTypeDefinition:
+		return actual != nil && IsBinary(actual.Type)
+	}
+	return dt != nil && dt.Kind() == BytesKind
+}
+
 // HasFile returns true if the underlying type has any file attributes.
 func HasFile(dt DataType) bool {
 	return hasFile(dt, nil)
@@ -838,7 +897,9 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 		var required []string
 		for _, n := range names {
 			if _, ok := viewObj[n]; ok {
-				required = append(required, n)
+				if at := m.Type.ToObject()[n]; at == nil || !at.IsWriteOnly() {
+					required = append(required, n)
+				}
 			}
 		}
 		val = m.Validation.Dup()
@@ -902,6 +963,9 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 			}
 			projectedObj[n] = &AttributeDefinition{Type: links, Description: "Links to related resources"}
 			ProjectedMediaTypes[canonical+"; links"] = &MediaTypeDefinition{UserTypeDefinition: links}
+		} else if at := mtObj[n]; at != nil && at.IsWriteOnly() {
+			delete(projectedObj, n)
+			delete(p.Views["default"].Type.ToObject(), n)
 		} else {
 			if at := mtObj[n]; at != nil {
 				at = DupAtt(at)
@@ -1084,6 +1148,10 @@ func toReflectType(dtype DataType) reflect.Type {
 		return reflect.TypeOf("")
 	case DateTimeKind:
 		return reflect.TypeOf(time.Time{})
+	case DurationKind:
+		return reflect.TypeOf("")
+	case BytesKind:
+		return reflect.TypeOf([]byte{})
 	case ObjectKind, UserTypeKind, MediaTypeKind:
 		return reflect.TypeOf(map[string]interface{}{})
 	case ArrayKind:
@@ -6,14 +6,58 @@ import (
 	"mime"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/dslengine"
 )
 
+// ResourceValidator is a custom validation rule run against each resource of the design, see
+// RegisterResourceValidator. It returns nil if the resource is valid.
+type ResourceValidator func(*ResourceDefinition) *dslengine.ValidationErrors
+
+var (
+	resourceValidators   []ResourceValidator
+	resourceValidatorsMu sync.RWMutex
+)
+
+// RegisterResourceValidator registers a custom validation rule, e.g. to enforce naming
+// conventions or security policies. The validator runs when the resource definitions are
+// validated, after the built-in validations, and the errors it returns are merged with theirs.
+// The returned function unregisters the validator.
+func RegisterResourceValidator(v ResourceValidator) (unregister func()) {
+	resourceValidatorsMu.Lock()
+	defer resourceValidatorsMu.Unlock()
+	resourceValidators = append(resourceValidators, v)
+	i := len(resourceValidators) - 1
+	return func() {
+		resourceValidatorsMu.Lock()
+		defer resourceValidatorsMu.Unlock()
+		resourceValidators[i] = nil
+	}
+}
+
+// runResourceValidators runs the registered custom validators against r.
+func runResourceValidators(r *ResourceDefinition, verr *dslengine.ValidationErrors) {
+	resourceValidatorsMu.RLock()
+	validators := make([]ResourceValidator, len(resourceValidators))
+	copy(validators, resourceValidators)
+	resourceValidatorsMu.RUnlock()
+	for _, v := range validators {
+		if v != nil {
+			verr.Merge(v(r))
+		}
+	}
+}
+
 type routeInfo struct {
 	Key       string
 	Resource  *ResourceDefinition
@@ -81,43 +125,76 @@ func (a *APIDefinition) Validate() error {
 		verr.Merge(a.Params.Validate("base parameters", a))
 	}
 
+	validateSchemes(a, a.Schemes, verr)
+	validateSecurity(a, a.Security, verr)
+	if strings.Contains(a.BasePath, VersionPlaceholder) {
+		verr.Add(a, "base path %#v uses %s but the API does not define a version", a.BasePath, VersionPlaceholder)
+	} else if strings.ContainsAny(a.BasePath, "{}?# ") {
+		verr.Add(a, "invalid base path %#v, must be a valid URL path", a.BasePath)
+	}
 	a.validateContact(verr)
 	a.validateLicense(verr)
 	a.validateDocs(verr)
+	a.validateServers(verr)
 	a.validateOrigins(verr)
-
-	var allRoutes []*routeInfo
-	a.IterateResources(func(r *ResourceDefinition) error {
+	a.validateAudiences(verr)
+	a.validateMIMETypes(verr)
+	a.validateResponseExamples(verr)
+	a.validateNamingStyle(verr)
+	for _, r := range a.Responses {
 		verr.Merge(r.Validate())
-		r.IterateActions(func(ac *ActionDefinition) error {
-			if ac.Docs != nil && ac.Docs.URL != "" {
-				if _, err := url.ParseRequestURI(ac.Docs.URL); err != nil {
-					verr.Add(ac, "invalid action docs URL value: %s", err)
-				}
+		if r.Status != 0 && r.Status < 400 {
+			verr.Add(r, "API responses must be error responses, status %d is not a 4xx or 5xx status code", r.Status)
+		}
+	}
+	validateResponseHeaders(a, a.ResponseHeaders, verr)
+	validateMetadata(a, a.Metadata, verr)
+	if s := a.ValidationErrorStatus; s != 0 && (s < 400 || s > 499) {
+		verr.Add(a, "invalid validation error status %d, must be a 4xx status code", s)
+	}
+	if h := a.RequestIDHeader; h != "" && !isToken(h) {
+		verr.Add(a, "invalid request ID header name %q, must be a valid HTTP header name", h)
+	}
+	switch a.TrailingSlash {
+	case "", TrailingSlashRedirect, TrailingSlashStrict, TrailingSlashIgnore:
+	default:
+		verr.Add(a, "invalid trailing slash policy %q, must be one of %q, %q or %q", a.TrailingSlash,
+			TrailingSlashRedirect, TrailingSlashStrict, TrailingSlashIgnore)
+	}
+
+	Walk(a, WalkFunc(func(def dslengine.Definition, _ string) error {
+		switch d := def.(type) {
+		case *ResourceDefinition:
+			verr.Merge(d.Validate())
+		case *ActionDefinition:
+			if d.Webhook || d.AsyncOperation != "" {
+				return SkipChildren
 			}
-			for _, ro := range ac.Routes {
-				if ro.IsAbsolute() {
-					continue
-				}
-				info := newRouteInfo(r, ac, ro)
-				allRoutes = append(allRoutes, info)
-				rwcs := ExtractWildcards(ac.Parent.FullPath())
-				wcs := ExtractWildcards(ro.Path)
-				for _, rwc := range rwcs {
-					for _, wc := range wcs {
-						if rwc == wc {
-							verr.Add(ac, `duplicate wildcard "%s" in resource base path "%s" and action route "%s"`,
-								wc, ac.Parent.FullPath(), ro.Path)
-						}
+			if err := validateDocsURL(d.Docs); err != nil {
+				verr.Add(d, "invalid action docs URL value: %s", err)
+			}
+		case *RouteDefinition:
+			if d.IsAbsolute() {
+				return nil
+			}
+			ac := d.Parent
+			rwcs := ExtractWildcards(ac.Parent.FullPath())
+			wcs := ExtractWildcards(d.Path)
+			for _, rwc := range rwcs {
+				for _, wc := range wcs {
+					if rwc == wc {
+						verr.Add(ac, `duplicate wildcard "%s" in resource base path "%s" and action route "%s"`,
+							wc, ac.Parent.FullPath(), d.Path)
 					}
 				}
 			}
-			return nil
-		})
+		case *AttributeDefinition, *ResponseDefinition:
+			return SkipChildren
+		}
 		return nil
-	})
+	}))
 
-	a.validateRoutes(verr, allRoutes)
+	verr.Merge(a.ValidateResources())
 
 	a.IterateMediaTypes(func(mt *MediaTypeDefinition) error {
 		verr.Merge(mt.Validate())
@@ -146,6 +223,41 @@ func (a *APIDefinition) Validate() error {
 	return err
 }
 
+// ValidateResources runs the checks that span multiple resources and thus cannot be done by
+// ResourceDefinition.Validate: route collisions, route wildcard conflicts, resource and webhook
+// name conflicts and health check path collisions. Validate calls it once all the resources have
+// been validated.
+func (a *APIDefinition) ValidateResources() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	var routes []*routeInfo
+	Walk(a, WalkFunc(func(def dslengine.Definition, _ string) error {
+		switch d := def.(type) {
+		case *ActionDefinition:
+			if d.Webhook || d.AsyncOperation != "" {
+				return SkipChildren
+			}
+		case *RouteDefinition:
+			if !d.IsAbsolute() {
+				routes = append(routes, newRouteInfo(d.Parent.Parent, d.Parent, d))
+			}
+		case *AttributeDefinition, *ResponseDefinition:
+			return SkipChildren
+		}
+		return nil
+	}))
+	a.validateRoutes(verr, routes)
+	a.validateTrailingSlashes(verr, routes)
+	a.validateResourceNames(verr)
+	a.validateResourcePaths(verr)
+	a.validateMounts(verr)
+	a.validateWebhookNames(verr)
+	a.validateHealthChecks(verr)
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
 func (a *APIDefinition) validateRoutes(verr *dslengine.ValidationErrors, routes []*routeInfo) {
 	for _, route := range routes {
 		for _, other := range routes {
@@ -155,6 +267,11 @@ func (a *APIDefinition) validateRoutes(verr *dslengine.ValidationErrors, routes
 			if route.Route.Verb != other.Route.Verb {
 				continue
 			}
+			if route.Key == other.Key && route.Action != other.Action && len(route.DifferentWildcards(other)) == 0 {
+				verr.Add(route.Action, "route %s %#v is ambiguous with route %s %#v of %s",
+					route.Route.Verb, route.Route.FullPath(), other.Route.Verb, other.Route.FullPath(), other.Action.Context())
+				continue
+			}
 			if strings.HasPrefix(route.Key, other.Key) {
 				diffs := route.DifferentWildcards(other)
 				if len(diffs) > 0 {
@@ -178,6 +295,204 @@ func (a *APIDefinition) validateRoutes(verr *dslengine.ValidationErrors, routes
 	}
 }
 
+// validateTrailingSlashes makes sure that no two routes only differ by a trailing slash when the
+// API defines a trailing slash policy: such routes are ambiguous with the strict policy and
+// collide once normalized with the other policies.
+func (a *APIDefinition) validateTrailingSlashes(verr *dslengine.ValidationErrors, routes []*routeInfo) {
+	if a.TrailingSlash == "" {
+		return
+	}
+	for i, route := range routes {
+		for _, other := range routes[:i] {
+			if route.Route.Verb != other.Route.Verb {
+				continue
+			}
+			p, op := route.Route.FullPath(), other.Route.FullPath()
+			if p == op || trimTrailingSlash(p) != trimTrailingSlash(op) {
+				continue
+			}
+			if a.TrailingSlash == TrailingSlashStrict {
+				verr.Add(route.Action, "route %s %#v only differs from route %s %#v of %s by a trailing slash",
+					route.Route.Verb, p, other.Route.Verb, op, other.Action.Context())
+			} else {
+				verr.Add(route.Action, "route %s %#v collides with route %s %#v of %s once trailing slashes are removed",
+					route.Route.Verb, p, other.Route.Verb, op, other.Action.Context())
+			}
+		}
+	}
+}
+
+// validateResourceNames makes sure that no two resources have names that only differ by case or
+// separators. Such resources would produce the same identifiers in the generated code and make
+// lookups by name ambiguous. It also makes sure that resource aliases are unique and do not
+// collide with resource names.
+func (a *APIDefinition) validateResourceNames(verr *dslengine.ValidationErrors) {
+	names := make(map[string]string)
+	a.IterateResources(func(r *ResourceDefinition) error {
+		key := identifierName(r.Name)
+		if other, ok := names[key]; ok {
+			verr.Add(r, "resource name %#v conflicts with resource %#v", r.Name, other)
+			return nil
+		}
+		names[key] = r.Name
+		return nil
+	})
+	aliases := make(map[string]string)
+	a.IterateResources(func(r *ResourceDefinition) error {
+		for _, alias := range r.Aliases {
+			if _, ok := a.Resources[alias]; ok {
+				verr.Add(r, "alias %#v conflicts with resource %#v", alias, alias)
+				continue
+			}
+			if other, ok := aliases[alias]; ok {
+				verr.Add(r, "alias %#v is already an alias of resource %#v", alias, other)
+				continue
+			}
+			aliases[alias] = r.Name
+		}
+		return nil
+	})
+}
+
+// validateResourcePaths makes sure that no two resources that define a base path resolve to the
+// same full path, possibly through different parents, as the routes of the two subtrees would be
+// ambiguous. Paths that only differ by the names of their wildcards are considered identical.
+func (a *APIDefinition) validateResourcePaths(verr *dslengine.ValidationErrors) {
+	paths := make(map[string]*ResourceDefinition)
+	a.IterateResources(func(r *ResourceDefinition) error {
+		if r.BasePath == "" || r.hasCyclicParent() {
+			return nil
+		}
+		key := WildcardRegex.ReplaceAllLiteralString(r.FullPath(), "/*")
+		if other, ok := paths[key]; ok {
+			verr.Add(r, "base path %#v resolves to %#v which is also the full path of %s", r.BasePath, r.FullPath(), other.Context())
+			return nil
+		}
+		paths[key] = r
+		return nil
+	})
+}
+
+// validateMounts makes sure that the routes served through the named mounts of a resource do not
+// conflict with each other nor with the routes of the default mount.
+func (a *APIDefinition) validateMounts(verr *dslengine.ValidationErrors) {
+	type mountedRoute struct {
+		mount *MountDefinition
+		route *RouteDefinition
+	}
+	a.IterateResources(func(r *ResourceDefinition) error {
+		if len(r.Mounts) == 0 {
+			return nil
+		}
+		routes := make(map[string]mountedRoute)
+		add := func(m *MountDefinition, ro *RouteDefinition) {
+			key := ro.Verb + " " + WildcardRegex.ReplaceAllLiteralString(ro.FullPath(), "/*")
+			other, ok := routes[key]
+			if !ok {
+				routes[key] = mountedRoute{m, ro}
+				return
+			}
+			if other.mount == m {
+				return // reported by validateRoutes
+			}
+			owner := "the default mount"
+			if other.mount != nil {
+				owner = fmt.Sprintf("mount %#v", other.mount.Name)
+			}
+			verr.Add(m, "route %s %#v of action %#v conflicts with route %s %#v of action %#v served by %s",
+				ro.Verb, ro.FullPath(), ro.Parent.Name, other.route.Verb, other.route.FullPath(), other.route.Parent.Name, owner)
+		}
+		r.IterateActions(func(act *ActionDefinition) error {
+			for _, ro := range act.Routes {
+				add(nil, ro)
+			}
+			return nil
+		})
+		for _, m := range r.Mounts {
+			r.IterateActions(func(act *ActionDefinition) error {
+				for _, ro := range m.Routes(act) {
+					add(m, ro)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+// validateWebhookNames makes sure that no two resources define webhooks with the same name.
+func (a *APIDefinition) validateWebhookNames(verr *dslengine.ValidationErrors) {
+	names := make(map[string]*ResourceDefinition)
+	a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateWebhooks(func(w *ActionDefinition) error {
+			if other, ok := names[w.Name]; ok {
+				verr.Add(w, "webhook name %#v is already used by %s", w.Name, other.Context())
+				return nil
+			}
+			names[w.Name] = r
+			return nil
+		})
+	})
+}
+
+// identifierName returns the name case folded with the separators removed so that names that
+// produce the same or clashing Go identifiers map to the same value, e.g. "useraccounts" for
+// "user_accounts", "UserAccounts" or "userAccounts".
+func identifierName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.ToLower(strings.Join(words, ""))
+}
+
+func (a *APIDefinition) validateHealthChecks(verr *dslengine.ValidationErrors) {
+	var checks []*HealthCheckDefinition
+	if a.HealthCheck != nil {
+		checks = append(checks, a.HealthCheck)
+	}
+	a.IterateResources(func(r *ResourceDefinition) error {
+		if r.HealthCheck != nil {
+			checks = append(checks, r.HealthCheck)
+		}
+		return nil
+	})
+	for i, h := range checks {
+		if h.Path == "" {
+			verr.Add(h, "health check must have a non empty path")
+			continue
+		}
+		full := h.FullPath()
+		key := routeKey(full)
+		for _, other := range checks[:i] {
+			if routeKey(other.FullPath()) == key {
+				verr.Add(h, "health check path %#v collides with %s", full, other.Context())
+			}
+		}
+		a.IterateResources(func(r *ResourceDefinition) error {
+			r.IterateActions(func(ac *ActionDefinition) error {
+				for _, ro := range ac.Routes {
+					if ro.Verb == "GET" && routeKey(ro.FullPath()) == key {
+						verr.Add(h, "health check path %#v collides with %s", full, ro.Context())
+					}
+				}
+				return nil
+			})
+			for _, fs := range r.FileServers {
+				if routeKey(fs.RequestPath) == key {
+					verr.Add(h, "health check path %#v collides with %s", full, fs.Context())
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// routeKey returns the path p without trailing slash and with the wildcards replaced with a
+// placeholder so that paths that only differ by these compare equal.
+func routeKey(p string) string {
+	return trimTrailingSlash(WildcardRegex.ReplaceAllLiteralString(p, "/*"))
+}
+
 func (a *APIDefinition) validateContact(verr *dslengine.ValidationErrors) {
 	if a.Contact != nil && a.Contact.URL != "" {
 		if _, err := url.ParseRequestURI(a.Contact.URL); err != nil {
@@ -195,19 +510,198 @@ func (a *APIDefinition) validateLicense(verr *dslengine.ValidationErrors) {
 }
 
 func (a *APIDefinition) validateDocs(verr *dslengine.ValidationErrors) {
-	if a.Docs != nil && a.Docs.URL != "" {
-		if _, err := url.ParseRequestURI(a.Docs.URL); err != nil {
-			verr.Add(a, "invalid docs URL value: %s", err)
+	if err := validateDocsURL(a.Docs); err != nil {
+		verr.Add(a, "invalid docs URL value: %s", err)
+	}
+}
+
+// validateDocsURL returns an error if the docs URL is set and is not an absolute URL with a
+// scheme and a host.
+func validateDocsURL(docs *DocsDefinition) error {
+	if docs == nil || docs.URL == "" {
+		return nil
+	}
+	u, err := url.Parse(docs.URL)
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("%#v is not an absolute URL", docs.URL)
+	}
+	return nil
+}
+
+func (a *APIDefinition) validateServers(verr *dslengine.ValidationErrors) {
+	names := make(map[string]string)
+	var def *ServerDefinition
+	for _, s := range a.Servers {
+		if s.Default {
+			if def != nil {
+				verr.Add(s, "only one server can be the default, server %s is already the default", def.URL)
+			} else {
+				def = s
+			}
+		}
+		resolved := true
+		for _, m := range ServerVariableRegex.FindAllStringSubmatch(s.URL, -1) {
+			var att *AttributeDefinition
+			if s.Variables != nil {
+				att = s.Variables.Type.ToObject()[m[1]]
+			}
+			if att == nil {
+				verr.Add(s, "server URL variable %#v is not defined", m[1])
+				resolved = false
+			} else if att.DefaultValue == nil {
+				verr.Add(s, "server URL variable %#v must have a default value", m[1])
+				resolved = false
+			}
+		}
+		if resolved {
+			if u, err := url.Parse(s.DefaultURL()); err != nil || !u.IsAbs() || u.Host == "" {
+				verr.Add(s, "invalid server URL %#v, URL must be absolute and include a host", s.URL)
+			} else if !IsSupportedScheme(u.Scheme) {
+				verr.Add(s, "unsupported server URL scheme %#v, must be one of %s", u.Scheme, strings.Join(SupportedSchemes, ", "))
+			}
 		}
+		if s.Name == "" {
+			continue
+		}
+		if other, ok := names[s.Name]; ok {
+			verr.Add(s, "server name %#v is already used by server %s", s.Name, other)
+			continue
+		}
+		names[s.Name] = s.URL
 	}
 }
 
+// validateAudiences warns about resource and action audiences that are not declared by the API.
+func (a *APIDefinition) validateAudiences(verr *dslengine.ValidationErrors) {
+	declared := make(map[string]bool)
+	for _, aud := range a.Audiences() {
+		declared[aud] = true
+	}
+	check := func(def dslengine.Definition, audiences []string) {
+		for _, aud := range audiences {
+			if !declared[aud] {
+				verr.Warn(def, "audience %#v is not declared in the API definition", aud)
+			}
+		}
+	}
+	a.IterateResources(func(r *ResourceDefinition) error {
+		check(r, r.Metadata[AudienceMetadataKey])
+		return r.IterateActions(func(ac *ActionDefinition) error {
+			check(ac, ac.Metadata[AudienceMetadataKey])
+			return nil
+		})
+	})
+}
+
+// validateMIMETypes warns about resource and action MIME types that the API does not consume or
+// produce.
+func (a *APIDefinition) validateMIMETypes(verr *dslengine.ValidationErrors) {
+	consumes, produces := a.Consumes, a.Produces
+	if len(consumes) == 0 {
+		consumes = DefaultDecoders
+	}
+	if len(produces) == 0 {
+		produces = DefaultEncoders
+	}
+	check := func(def dslengine.Definition, mimeTypes []string, encodings []*EncodingDefinition, verb string) {
+		known := make(map[string]bool)
+		for _, mt := range EncodingMIMETypes(encodings) {
+			known[mt] = true
+		}
+		for _, mt := range mimeTypes {
+			if !known[mt] {
+				verr.Warn(def, "MIME type %#v is not %s by the API", mt, verb)
+			}
+		}
+	}
+	a.IterateResources(func(r *ResourceDefinition) error {
+		check(r, r.Consumes, consumes, "consumed")
+		check(r, r.Produces, produces, "produced")
+		return r.IterateActions(func(ac *ActionDefinition) error {
+			if ac.Payload == nil || !IsBinary(ac.Payload) {
+				// Binary payloads are read as is and do not require a decoder.
+				check(ac, ac.Consumes, consumes, "consumed")
+			}
+			check(ac, ac.Produces, produces, "produced")
+			return nil
+		})
+	})
+}
+
 func (a *APIDefinition) validateOrigins(verr *dslengine.ValidationErrors) {
 	for _, origin := range a.Origins {
 		verr.Merge(origin.Validate())
 	}
 }
 
+// IsSupportedScheme returns true if the given URL scheme is one of SupportedSchemes.
+func IsSupportedScheme(scheme string) bool {
+	for _, s := range SupportedSchemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// validateResponseHeaders makes sure the headers listed with ResponseHeaders are constants, i.e.
+// primitives or arrays of primitives that define a default value.
+func validateResponseHeaders(def dslengine.Definition, headers *AttributeDefinition, verr *dslengine.ValidationErrors) {
+	if headers == nil {
+		return
+	}
+	headers.Type.ToObject().IterateAttributes(func(n string, h *AttributeDefinition) error {
+		t := h.Type
+		if t.IsArray() {
+			t = t.ToArray().ElemType.Type
+		}
+		if !t.IsPrimitive() {
+			verr.Add(def, "response header %#v must be a primitive or an array of primitives", n)
+			return nil
+		}
+		if h.DefaultValue == nil {
+			verr.Add(def, "response header %#v must define its value with Default", n)
+		}
+		return nil
+	})
+}
+
+// validateSecurity checks that the scopes referenced by the security required scopes expression
+// are declared on the security scheme.
+func validateSecurity(def dslengine.Definition, sec *SecurityDefinition, verr *dslengine.ValidationErrors) {
+	if sec == nil || sec.ScopeExpr == nil || sec.Scheme == nil {
+		return
+	}
+	for _, s := range sec.ScopeExpr.Scopes() {
+		if _, ok := sec.Scheme.Scopes[s]; !ok {
+			verr.Add(def, "scope %#v used in required scopes %#v is not declared by security scheme %#v",
+				s, sec.ScopeExpr.String(), sec.Scheme.SchemeName)
+		}
+	}
+}
+
+// validateSchemes records an error for each scheme that is not one of SupportedSchemes.
+func validateSchemes(def dslengine.Definition, schemes []string, verr *dslengine.ValidationErrors) {
+	for _, s := range schemes {
+		if !IsSupportedScheme(s) {
+			verr.Add(def, "unsupported scheme %#v, must be one of %s", s, strings.Join(SupportedSchemes, ", "))
+		}
+	}
+}
+
+// IsSupportedCompression returns true if the given algorithm is one of SupportedCompressions.
+func IsSupportedCompression(algorithm string) bool {
+	for _, c := range SupportedCompressions {
+		if c == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate tests whether the resource definition is consistent: action names are valid and each action is
 // valid.
 func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
@@ -215,7 +709,63 @@ func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
 	if r.Name == "" {
 		verr.Add(r, "Resource name cannot be empty")
 	}
+	validateSchemes(r, r.Schemes, verr)
+	validateSecurity(r, r.Security, verr)
+	validateMetadata(r, r.Metadata, verr)
+	for _, c := range r.Compression {
+		if !IsSupportedCompression(c) {
+			verr.Add(r, "unsupported compression algorithm %#v, must be one of %s", c, strings.Join(SupportedCompressions, ", "))
+		}
+	}
+	if len(r.Actions) == 0 && len(r.FileServers) == 0 && r.HealthCheck == nil && len(r.Webhooks) == 0 && len(r.AsyncActions) == 0 {
+		verr.Warn(r, "resource defines no action and no file server, no code is generated for it")
+	}
+	for verb, status := range r.DefaultResponseStatuses {
+		valid := false
+		for _, v := range HTTPVerbs {
+			if v == verb {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			verr.Add(r, "invalid HTTP verb %#v in default response status, must be one of %s", verb, strings.Join(HTTPVerbs, ", "))
+		}
+		if status < 200 || status > 299 {
+			verr.Add(r, "invalid default response status %d for %s, must be a 2xx status code", status, verb)
+		}
+	}
+	validateResponseHeaders(r, r.ResponseHeaders, verr)
+	for _, n := range r.Servers {
+		found := false
+		for _, s := range Design.Servers {
+			if s.Name == n {
+				found = true
+				break
+			}
+		}
+		if !found {
+			verr.Add(r, "server %#v is not defined, use the Name DSL to name the API servers", n)
+		}
+	}
 	r.validateActions(verr)
+	for _, m := range r.Mounts {
+		verr.Merge(m.Validate())
+	}
+	if Design.RequireDescriptions {
+		for _, a := range r.UndocumentedActions() {
+			verr.Add(a, "missing description, the API requires all actions to be documented")
+		}
+	}
+	r.IterateWebhooks(func(w *ActionDefinition) error {
+		verr.Merge(w.Validate())
+		w.validateWebhook(verr)
+		return nil
+	})
+	r.IterateAsyncActions(func(a *ActionDefinition) error {
+		a.validateAsync(verr)
+		return nil
+	})
 	if r.ParentName != "" {
 		r.validateParent(verr)
 	}
@@ -225,12 +775,53 @@ func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
 	if r.Params != nil {
 		verr.Merge(r.Params.Validate("resource parameters", r))
 	}
+	validateHeaderTypes(r, r.Headers, verr)
 	for _, origin := range r.Origins {
 		verr.Merge(origin.Validate())
 	}
+	runResourceValidators(r, verr)
 	return verr.AsError()
 }
 
+// validateCanonicalVerbs checks that the canonical actions set for specific HTTP verbs exist and
+// define a route with the verb.
+func (r *ResourceDefinition) validateCanonicalVerbs(verr *dslengine.ValidationErrors) {
+	verbs := make([]string, 0, len(r.CanonicalActionNames))
+	for v := range r.CanonicalActionNames {
+		verbs = append(verbs, v)
+	}
+	sort.Strings(verbs)
+	for _, v := range verbs {
+		name := r.CanonicalActionNames[v]
+		valid := false
+		for _, verb := range HTTPVerbs {
+			if verb == v {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			verr.Add(r, `invalid HTTP verb %#v for canonical action "%s"`, v, name)
+			continue
+		}
+		a, ok := r.Actions[name]
+		if !ok {
+			verr.Add(r, `unknown canonical action "%s" for %s`, name, v)
+			continue
+		}
+		found := false
+		for _, route := range a.Routes {
+			if route.Verb == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			verr.Add(r, `canonical action "%s" for %s has no %s route`, name, v, v)
+		}
+	}
+}
+
 func (r *ResourceDefinition) validateActions(verr *dslengine.ValidationErrors) {
 	found := false
 	for _, a := range r.Actions {
@@ -242,28 +833,227 @@ func (r *ResourceDefinition) validateActions(verr *dslengine.ValidationErrors) {
 	for _, f := range r.FileServers {
 		verr.Merge(f.Validate())
 	}
+	r.validateFileServers(verr)
 	if r.CanonicalActionName != "" && !found {
 		verr.Add(r, `unknown canonical action "%s"`, r.CanonicalActionName)
 	}
+	r.validateCanonicalVerbs(verr)
+	if r.NotFoundActionName != "" {
+		r.validateNotFound(verr)
+	}
+	if r.StripPrefix {
+		r.validateStripPrefix(verr)
+	}
+	r.validateResponseNames(verr)
+}
+
+// validateResponseNames makes sure that no two responses of the resource or of one of its actions
+// have names that only differ by case or separators: such responses would produce the same
+// identifiers in the generated code and one would shadow the other. It also makes sure that the
+// responses do not change the status of the API response with the same name.
+func (r *ResourceDefinition) validateResponseNames(verr *dslengine.ValidationErrors) {
+	validateResponseNameConflicts(r, r.Responses, nil, verr)
+	r.IterateActions(func(a *ActionDefinition) error {
+		validateResponseNameConflicts(a, a.Responses, r.Responses, verr)
+		return nil
+	})
+}
+
+// validateResponseNameConflicts validates the names of the given responses against each other,
+// against the inherited responses and against the API responses.
+func validateResponseNameConflicts(def dslengine.Definition, responses, inherited map[string]*ResponseDefinition, verr *dslengine.ValidationErrors) {
+	names := make([]string, 0, len(responses))
+	for n := range responses {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	seen := make(map[string]string)
+	for n := range inherited {
+		seen[identifierName(n)] = n
+	}
+	for _, n := range names {
+		key := identifierName(n)
+		if other, ok := seen[key]; ok && other != n {
+			verr.Add(def, "response name %#v conflicts with response %#v", n, other)
+		} else if !ok {
+			seen[key] = n
+		}
+		resp := responses[n]
+		if ar, ok := Design.Responses[n]; ok && resp.Status != 0 && ar.Status != 0 && resp.Status != ar.Status {
+			verr.Add(def, "response %#v has status %d but the API response with the same name has status %d", n, resp.Status, ar.Status)
+		}
+	}
+}
+
+// validateStripPrefix makes sure the base path stripped by the resource is a non-root literal
+// path and that all the resource routes are under it.
+func (r *ResourceDefinition) validateStripPrefix(verr *dslengine.ValidationErrors) {
+	prefix := r.StrippedPrefix()
+	if prefix == "" {
+		verr.Add(r, "StripPrefix requires a base path")
+		return
+	}
+	if wcs := ExtractWildcards(prefix); len(wcs) > 0 {
+		verr.Add(r, "cannot strip base path %#v, it defines wildcards", prefix)
+		return
+	}
+	r.IterateActions(func(a *ActionDefinition) error {
+		for _, ro := range a.Routes {
+			if ro.IsAbsolute() {
+				verr.Add(a, "absolute route %s %#v is not under the prefix %#v stripped by the resource", ro.Verb, ro.FullPath(), prefix)
+			}
+		}
+		return nil
+	})
+}
+
+// validateFileServers makes sure the file servers of the resource do not serve the same request
+// paths from different files.
+func (r *ResourceDefinition) validateFileServers(verr *dslengine.ValidationErrors) {
+	for i, f := range r.FileServers {
+		for _, other := range r.FileServers[:i] {
+			if p, ok := overlappingFileServers(other, f); ok {
+				verr.Add(f, "request path %#v is also served by %s from a different file", p, other.Context())
+			}
+		}
+	}
+}
+
+// overlappingFileServers returns the request path of the file server whose requests are also
+// matched by the other and true if the two file servers would serve different files for these
+// requests.
+func overlappingFileServers(f, other *FileServerDefinition) (string, bool) {
+	fp, fdir := fileServerPrefix(f)
+	op, odir := fileServerPrefix(other)
+	if !fdir && !odir {
+		return fp, fp == op && path.Clean(f.FilePath) != path.Clean(other.FilePath)
+	}
+	if !fdir || (odir && len(op) < len(fp)) {
+		f, other = other, f
+		fp, op = op, fp
+		odir = fdir
+	}
+	// f is a directory, check whether it also matches the requests sent to other.
+	if !strings.HasPrefix(op, fp) {
+		return "", false
+	}
+	rel := strings.TrimPrefix(op, fp)
+	if rel != "" && !strings.HasPrefix(rel, "/") {
+		return "", false
+	}
+	reqPath := op
+	if odir {
+		reqPath += "/*"
+	}
+	// The file server of a directory serves the index.html file of the sub-directories.
+	expected, file := path.Join(f.FilePath, rel), path.Clean(other.FilePath)
+	return reqPath, file != expected && (odir || file != path.Join(expected, "index.html"))
+}
+
+// fileServerPrefix returns the request path of the file server without its trailing wildcard
+// if any and true if there was one.
+func fileServerPrefix(f *FileServerDefinition) (string, bool) {
+	p := f.RequestPath
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	if !f.IsDir() {
+		return p, false
+	}
+	return strings.TrimSuffix(p[:strings.LastIndex(p, "/*")], "/"), true
+}
+
+// validateNotFound makes sure the not found action can handle any request made to the resource
+// base path and that its catch-all routes do not collide with routes defined in the design.
+func (r *ResourceDefinition) validateNotFound(verr *dslengine.ValidationErrors) {
+	a := r.NotFoundAction()
+	if a == nil {
+		verr.Add(r, `unknown not found action "%s"`, r.NotFoundActionName)
+		return
+	}
+	if a.Payload != nil && !a.PayloadOptional {
+		verr.Add(a, "payload of not found action must be optional")
+	}
+	if a.WebSocket() {
+		verr.Add(a, "not found action cannot be a websocket action")
+	}
+	if a.Params != nil && a.Params.Validation != nil {
+		base := ExtractWildcards(r.FullPath())
+		for _, req := range a.Params.Validation.Required {
+			found := false
+			for _, b := range base {
+				if b == req {
+					found = true
+					break
+				}
+			}
+			if !found {
+				verr.Add(a, "not found action cannot require parameter %#v, only the resource base path parameters are available", req)
+			}
+		}
+	}
+	catchAll := WildcardRegex.ReplaceAllLiteralString(r.NotFoundRoutes()[0].FullPath(), "/*")
+	for _, other := range r.Actions {
+		for _, ro := range other.Routes {
+			if strings.Contains(path.Base(ro.FullPath()), "*") &&
+				WildcardRegex.ReplaceAllLiteralString(ro.FullPath(), "/*") == catchAll {
+				verr.Add(a, "not found routes collide with %s", ro.Context())
+			}
+		}
+	}
+	for _, f := range r.FileServers {
+		if WildcardRegex.ReplaceAllLiteralString(f.RequestPath, "/*") == catchAll {
+			verr.Add(a, "not found routes collide with %s", f.Context())
+		}
+	}
 }
 
 func (r *ResourceDefinition) validateParent(verr *dslengine.ValidationErrors) {
-	p, ok := Design.Resources[r.ParentName]
-	if !ok {
+	p := Design.Resource(r.ParentName)
+	if p == nil {
 		verr.Add(r, "Parent resource named %#v not found", r.ParentName)
 	} else {
-		if p.CanonicalAction() == nil {
+		if ca := p.CanonicalAction(); ca == nil {
 			verr.Add(r, "Parent resource %#v has no canonical action", r.ParentName)
+		} else if len(ca.Routes) == 0 {
+			verr.Add(r, "canonical action %#v of parent resource %#v has no route, the resource paths cannot be nested under it", ca.Name, r.ParentName)
+		} else if fp := ca.Routes[0].FullPath(); fp == "" || fp == "/" {
+			verr.Add(r, "canonical action %#v of parent resource %#v resolves to the empty path %#v, the resource paths cannot be nested under it", ca.Name, r.ParentName, fp)
 		}
+		if r.hasCyclicParent() {
+			verr.Add(r, "Parent resource %#v is part of a cycle", r.ParentName)
+		}
+		r.validateParentPaths(verr)
 	}
 }
 
+// validateParentPaths warns about absolute paths defined on a resource with a parent: such paths
+// ignore the parent resource path which usually indicates a mistake.
+func (r *ResourceDefinition) validateParentPaths(verr *dslengine.ValidationErrors) {
+	if strings.HasPrefix(r.BasePath, "//") {
+		verr.Warn(r, "base path %#v is absolute and ignores the path of parent resource %#v", r.BasePath, r.ParentName)
+	}
+	r.IterateActions(func(a *ActionDefinition) error {
+		for _, route := range a.Routes {
+			if route.IsAbsolute() {
+				verr.Warn(a, "route %s %#v is absolute and ignores the path of parent resource %#v", route.Verb, route.Path, r.ParentName)
+			}
+		}
+		return nil
+	})
+}
+
 // Validate makes sure the CORS definition origin is valid.
 func (cors *CORSDefinition) Validate() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
 	if !cors.Regexp && strings.Count(cors.Origin, "*") > 1 {
 		verr.Add(cors, "invalid origin, can only contain one wildcard character")
 	}
+	if !cors.Regexp && cors.Origin != "*" {
+		if err := validateOrigin(cors.Origin); err != nil {
+			verr.Add(cors, "invalid origin, %s", err)
+		}
+	}
 	if cors.Regexp {
 		_, err := regexp.Compile(cors.Origin)
 		if err != nil {
@@ -273,6 +1063,31 @@ func (cors *CORSDefinition) Validate() *dslengine.ValidationErrors {
 	return verr
 }
 
+// validateOrigin checks that a non regular expression origin is a host optionally prefixed with
+// an HTTP scheme and followed by a port, e.g. "https://*.goa.design:8080".
+func validateOrigin(origin string) error {
+	if origin == "" {
+		return fmt.Errorf("origin cannot be empty")
+	}
+	host := origin
+	if i := strings.Index(origin, "://"); i >= 0 {
+		if scheme := origin[:i]; scheme != "http" && scheme != "https" {
+			return fmt.Errorf("scheme must be http or https, got %#v", scheme)
+		}
+		host = origin[i+3:]
+	}
+	// Replace the wildcard so that the origin parses, a wildcard port matches any port.
+	host = strings.TrimSuffix(host, ":*")
+	u, err := url.Parse("http://" + strings.Replace(host, "*", "wildcard", 1))
+	if err != nil {
+		return err
+	}
+	if u.Host == "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("%#v must consist of a scheme, a host and a port only", origin)
+	}
+	return nil
+}
+
 // Validate validates the encoding MIME type and Go package path if set.
 func (enc *EncodingDefinition) Validate() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
@@ -329,6 +1144,9 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	if len(a.Routes) == 0 {
 		verr.Add(a, "No route defined for action")
 	}
+	validateSchemes(a, a.Schemes, verr)
+	validateSecurity(a, a.Security, verr)
+	validateMetadata(a, a.Metadata, verr)
 	for i, r := range a.Responses {
 		for j, r2 := range a.Responses {
 			if i != j && r.Status == r2.Status {
@@ -343,10 +1161,23 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	verr.Merge(a.ValidateParams())
 	if a.Payload != nil {
 		verr.Merge(a.Payload.Validate("action payload", a))
+		if obj := a.Payload.Type.ToObject(); obj != nil {
+			for _, n := range a.Payload.AllRequired() {
+				if att, ok := obj[n]; ok && att.IsReadOnly() {
+					verr.Add(a, "payload %s requires the read-only attribute %#v, read-only attributes are not accepted in request bodies", a.Payload.TypeName, n)
+				}
+			}
+		}
 		if HasFile(a.Payload.Type) && a.PayloadMultipart != true {
 			verr.Add(a, "Payload %s contains an invalid type, action payloads cannot contain a file", a.Payload.TypeName)
 		}
 	}
+	if len(a.Payloads) > 0 {
+		a.validatePayloads(verr)
+	}
+	if a.Static != nil {
+		a.validateStatic(verr)
+	}
 	if a.Parent == nil {
 		verr.Add(a, "missing parent resource")
 	}
@@ -356,6 +1187,9 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 				if HasFile(p.Type) {
 					verr.Add(a, "Param %s has an invalid type, action params cannot be a file", n)
 				}
+				if IsBinary(p.Type) {
+					verr.Add(a, "Param %s has an invalid type, action params cannot be bytes", n)
+				}
 				continue
 			}
 			if p.Type.IsArray() {
@@ -369,7 +1203,336 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 			verr.Add(a, "Param %s has an invalid type, action params must be primitives or arrays of primitives", n)
 		}
 	}
+	validateHeaderTypes(a, a.Headers, verr)
+	a.validateNoInheritedParams(verr)
+	a.validateOmitted(verr)
+	a.validateTimeout(verr)
+	a.validateSince(verr)
+	a.validateSignature(verr)
+	a.validateStreamingResult(verr)
+	a.validateViewParam(verr)
+	a.validateLinks(verr)
+
+	return verr.AsError()
+}
+
+// validateLinks makes sure the actions targeted by the action links exist and have a route.
+func (a *ActionDefinition) validateLinks(verr *dslengine.ValidationErrors) {
+	for _, l := range a.Links {
+		if l.Rel == "" {
+			verr.Add(l, "link relation type cannot be empty")
+		}
+		target := l.Target()
+		if target == nil {
+			verr.Add(l, "link targets unknown action %#v of resource %#v", l.Action, l.Resource)
+			continue
+		}
+		if len(target.Routes) == 0 {
+			verr.Add(l, "link targets %s which has no route", target.Context())
+		}
+	}
+}
+
+// validateMetadata warns about the metadata keys that belong to a known namespace but are not known
+// themselves, such keys are most likely misspelled.
+func validateMetadata(def dslengine.Definition, m dslengine.MetadataDefinition, verr *dslengine.ValidationErrors) {
+	for _, k := range m.UnknownKeys() {
+		verr.Warn(def, "unknown metadata key %#v", k)
+	}
+}
+
+// validateViewParam makes sure actions that let clients select the response view define a response
+// with views and that the view parameter does not override another parameter.
+func (a *ActionDefinition) validateViewParam(verr *dslengine.ValidationErrors) {
+	if a.ViewParam == "" {
+		return
+	}
+	if resp, _ := a.ViewedResponse(); resp == nil {
+		verr.Add(a, "ViewParam %q requires a success response whose media type defines views", a.ViewParam)
+	}
+	if a.Params != nil {
+		if _, ok := a.Params.Type.ToObject()[a.ViewParam]; ok {
+			verr.Add(a, "ViewParam %q conflicts with the parameter with the same name", a.ViewParam)
+		}
+	}
+}
+
+// validateStreamingResult makes sure actions that stream results use a transport that supports
+// streaming and, if they also send a final result, a transport that can tag the final frame.
+func (a *ActionDefinition) validateStreamingResult(verr *dslengine.ValidationErrors) {
+	if a.StreamingResult == nil {
+		if a.Result != nil {
+			verr.Add(a, "Result can only be used together with StreamingResult")
+		}
+		return
+	}
+	verr.Merge(a.StreamingResult.Validate("streaming result", a))
+	switch a.StreamTransport() {
+	case "":
+		verr.Add(a, "actions that stream results must use the ws or wss scheme or produce %s or %s", NDJSONMIMEType, SSEMIMEType)
+	case SSEStream:
+		if a.Result != nil {
+			verr.Add(a, "final result cannot be sent with server-sent events, use a websocket or %s", NDJSONMIMEType)
+		}
+	}
+	if a.Result != nil {
+		verr.Merge(a.Result.Validate("result", a))
+	}
+}
+
+// validateSignature makes sure the request signature, if any, uses a header and a supported
+// algorithm, that the action does not stream its requests and that it defines the response sent
+// when the signature cannot be verified.
+func (a *ActionDefinition) validateSignature(verr *dslengine.ValidationErrors) {
+	s := a.Signature
+	if s == nil {
+		return
+	}
+	if s.Header == "" {
+		verr.Add(s, "signature header is missing")
+	}
+	supported := false
+	for _, alg := range SupportedSignatureAlgorithms {
+		if s.Algorithm == alg {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		verr.Add(s, "unsupported signature algorithm %#v, algorithm must be one of %s",
+			s.Algorithm, strings.Join(SupportedSignatureAlgorithms, ", "))
+	}
+	if a.WebSocket() {
+		verr.Add(s, "websocket actions cannot be signed, request bodies are streamed")
+	}
+	if !a.Webhook && a.SignatureErrorStatus() == 0 {
+		verr.Add(s, "action must define an Unauthorized or Forbidden response to report signature verification failures")
+	}
+}
+
+// validateWebhook makes sure the webhook sends a payload using a single route with no path
+// parameter and that its responses are acknowledgments.
+func (a *ActionDefinition) validateWebhook(verr *dslengine.ValidationErrors) {
+	if len(a.Routes) > 1 {
+		verr.Add(a, "webhook must define a single route")
+	}
+	for _, route := range a.Routes {
+		switch route.Verb {
+		case "POST", "PUT", "PATCH":
+		default:
+			verr.Add(a, "webhook route must use POST, PUT or PATCH, got %s", route.Verb)
+		}
+		if wcs := ExtractWildcards(route.Path); len(wcs) > 0 {
+			verr.Add(a, "webhook route %#v cannot define path parameters, got %s", route.Path, strings.Join(wcs, ", "))
+		}
+	}
+	if a.Payload == nil {
+		verr.Add(a, "webhook must define a payload")
+	}
+	for _, resp := range a.Responses {
+		if resp.Status < 200 || resp.Status > 299 || resp.MediaType != "" || resp.Type != nil {
+			verr.Add(a, "webhook response %s must be a 2xx acknowledgment with no body", resp.Name)
+		}
+	}
+}
+
+// validateAsync makes sure the async action defines a channel and a message and does not use
+// HTTP specific DSLs.
+func (a *ActionDefinition) validateAsync(verr *dslengine.ValidationErrors) {
+	if a.Channel == "" {
+		verr.Add(a, "async action must define a channel")
+	}
+	if a.Message == nil {
+		verr.Add(a, "async action must define a message")
+	} else {
+		verr.Merge(a.Message.Validate("message", a))
+	}
+	var invalid []string
+	if len(a.Routes) > 0 {
+		invalid = append(invalid, "Routing")
+	}
+	if a.Params != nil {
+		invalid = append(invalid, "Params")
+	}
+	if a.Headers != nil {
+		invalid = append(invalid, "Headers")
+	}
+	if a.Payload != nil {
+		invalid = append(invalid, "Payload")
+	}
+	if len(a.Responses) > 0 {
+		invalid = append(invalid, "Response")
+	}
+	if len(a.Schemes) > 0 {
+		invalid = append(invalid, "Scheme")
+	}
+	if a.Security != nil {
+		invalid = append(invalid, "Security")
+	}
+	if a.Signature != nil {
+		invalid = append(invalid, "Signature")
+	}
+	if _, ok := a.Metadata[TimeoutMetadataKey]; ok {
+		invalid = append(invalid, "Timeout")
+	}
+	for _, dsl := range invalid {
+		verr.Add(a, "%s cannot be used in async actions, they have no HTTP mapping", dsl)
+	}
+}
+
+// validateTimeout makes sure the action timeout, if any, is a valid positive duration.
+func (a *ActionDefinition) validateTimeout(verr *dslengine.ValidationErrors) {
+	vals, ok := a.Metadata[TimeoutMetadataKey]
+	if !ok {
+		return
+	}
+	if len(vals) != 1 {
+		verr.Add(a, "timeout must be a single duration, got %v", vals)
+		return
+	}
+	d, err := time.ParseDuration(vals[0])
+	if err != nil {
+		verr.Add(a, "invalid timeout %#v: %s", vals[0], err)
+		return
+	}
+	if d <= 0 {
+		verr.Add(a, "invalid timeout %#v: timeout must be positive", vals[0])
+	}
+}
+
+// validateSince makes sure the version that introduced the action is a valid version that is not
+// greater than the API version.
+func (a *ActionDefinition) validateSince(verr *dslengine.ValidationErrors) {
+	since := a.Since()
+	if since == "" {
+		return
+	}
+	if Design == nil || Design.Version == "" {
+		verr.Add(a, "action is introduced in version %#v but the API does not define a version", since)
+		return
+	}
+	if _, ok := compareVersions(since, since); !ok {
+		verr.Add(a, "invalid version %#v, must be made of dot separated numbers optionally prefixed with \"v\"", since)
+		return
+	}
+	// API versions that are not made of numbers cannot be compared.
+	if cmp, ok := compareVersions(since, Design.Version); ok && cmp > 0 {
+		verr.Add(a, "action is introduced in version %#v which is greater than the API version %#v", since, Design.Version)
+	}
+}
+
+// validateNoInheritedParams makes sure the path parameters removed from the action routes are
+// inherited from the resource path and that they are still bound to a query string parameter or
+// a header. APIDefinition.ValidateResources checks that the resulting routes do not collide with
+// the routes of other actions.
+func (a *ActionDefinition) validateNoInheritedParams(verr *dslengine.ValidationErrors) {
+	if len(a.NoInheritedParams) == 0 || a.Parent == nil {
+		return
+	}
+	inherited := make(map[string]bool)
+	for _, wc := range ExtractWildcards(a.Parent.FullPath()) {
+		inherited[wc] = true
+	}
+	for _, n := range a.NoInheritedParams {
+		if !inherited[n] {
+			verr.Add(a, "%#v is not a path parameter of the resource path %#v", n, a.Parent.FullPath())
+			continue
+		}
+		if !a.bindsParam(n) {
+			verr.Add(a, "path parameter %#v is removed from the action routes but is not bound to a query string parameter or a header", n)
+		}
+	}
+}
 
+// validateOmitted makes sure the parameters and headers omitted by the action are defined by its
+// resource and are not also defined by the action or used by its routes.
+func (a *ActionDefinition) validateOmitted(verr *dslengine.ValidationErrors) {
+	if a.Parent == nil {
+		return
+	}
+	for _, n := range a.OmittedParams {
+		if a.Parent.Params == nil || a.Parent.Params.Type.ToObject()[n] == nil {
+			verr.Add(a, "omitted param %#v is not a param of %s", n, a.Parent.Context())
+			continue
+		}
+		if a.Params != nil && a.Params.Type.ToObject()[n] != nil {
+			verr.Add(a, "param %#v is both omitted and defined by the action", n)
+		}
+		for _, ro := range a.Routes {
+			if isOmitted(n, ro.Params()) {
+				verr.Add(a, "omitted param %#v is used by route %s %#v", n, ro.Verb, ro.FullPath())
+			}
+		}
+	}
+	for _, n := range a.OmittedHeaders {
+		if a.Parent.Headers == nil || a.Parent.Headers.Type.ToObject()[n] == nil {
+			verr.Add(a, "omitted header %#v is not a header of %s", n, a.Parent.Context())
+			continue
+		}
+		if a.Headers != nil && a.Headers.Type.ToObject()[n] != nil {
+			verr.Add(a, "header %#v is both omitted and defined by the action", n)
+		}
+	}
+}
+
+// bindsParam returns true if the action defines a query string parameter or a header that binds
+// the path parameter with the given name. A header binds a parameter if its name with dashes
+// replaced by underscores and the "X-" prefix removed is the parameter name ignoring case, e.g.
+// the header "X-Account-ID" binds the parameter "account_id".
+func (a *ActionDefinition) bindsParam(name string) bool {
+	if a.Params != nil {
+		if _, ok := a.Params.Type.ToObject()[name]; ok {
+			return true
+		}
+	}
+	for _, headers := range []*AttributeDefinition{a.Headers, a.Parent.Headers} {
+		if headers == nil {
+			continue
+		}
+		for h := range headers.Type.ToObject() {
+			n := strings.TrimPrefix(strings.Replace(strings.ToLower(h), "-", "_", -1), "x_")
+			if n == strings.ToLower(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateHeaderTypes makes sure the request headers are primitives or arrays of primitives, the
+// only types that can be decoded from header values.
+func validateHeaderTypes(def dslengine.Definition, headers *AttributeDefinition, verr *dslengine.ValidationErrors) {
+	if headers == nil {
+		return
+	}
+	for n, h := range headers.Type.ToObject() {
+		if h.Type.IsPrimitive() && !HasFile(h.Type) {
+			continue
+		}
+		if h.Type.IsArray() {
+			elem := h.Type.ToArray().ElemType.Type
+			if elem.IsPrimitive() && !HasFile(elem) {
+				continue
+			}
+		}
+		verr.Add(def, "Header %s has an invalid type, headers must be primitives or arrays of primitives", n)
+	}
+}
+
+// Validate checks the mount is properly initialized.
+func (m *MountDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if m.Name == "" {
+		verr.Add(m, "mount name cannot be empty")
+	}
+	switch {
+	case m.BasePath == "":
+		verr.Add(m, "missing base path, use BasePath to set the path the resource is mounted under")
+	case !strings.HasPrefix(m.BasePath, "/") || strings.HasPrefix(m.BasePath, "//"):
+		verr.Add(m, "invalid base path %#v, must start with a single slash", m.BasePath)
+	}
+	validateMetadata(m, m.Metadata, verr)
+	validateSecurity(m, m.Security, verr)
 	return verr.AsError()
 }
 
@@ -379,12 +1542,17 @@ func (f *FileServerDefinition) Validate() *dslengine.ValidationErrors {
 	if f.FilePath == "" {
 		verr.Add(f, "File server must have a non empty file path")
 	}
+	validateMetadata(f, f.Metadata, verr)
+	validateSecurity(f, f.Security, verr)
 	if f.RequestPath == "" {
 		verr.Add(f, "File server must have a non empty route path")
 	}
 	if f.Parent == nil {
 		verr.Add(f, "missing parent resource")
 	}
+	if err := validateDocsURL(f.Docs); err != nil {
+		verr.Add(f, "invalid file server docs URL value: %s", err)
+	}
 	matches := WildcardRegex.FindAllString(f.RequestPath, -1)
 	if len(matches) == 1 {
 		if !strings.HasSuffix(f.RequestPath, matches[0]) {
@@ -394,10 +1562,41 @@ func (f *FileServerDefinition) Validate() *dslengine.ValidationErrors {
 	if len(matches) > 2 {
 		verr.Add(f, "invalid request path, may only contain one wildcard")
 	}
+	if f.MaxAge != nil && *f.MaxAge < 0 {
+		verr.Add(f, "invalid max age %d, must be positive or zero", *f.MaxAge)
+	}
 
 	return verr.AsError()
 }
 
+// validatePayloads makes sure the content types of the action payloads are valid and do not
+// overlap so that the content type of a request identifies a single payload.
+func (a *ActionDefinition) validatePayloads(verr *dslengine.ValidationErrors) {
+	if a.PayloadMultipart {
+		verr.Add(a, "multipart form actions cannot define payloads per content type")
+	}
+	seen := make(map[string]string)
+	for _, p := range a.Payloads {
+		mt, _, err := mime.ParseMediaType(p.ContentType)
+		if err != nil {
+			verr.Add(p, "invalid content type: %s", err)
+			continue
+		}
+		if strings.Contains(mt, "*") {
+			verr.Add(p, "content type cannot contain wildcards")
+			continue
+		}
+		if other, ok := seen[p.AttributeName()]; ok {
+			verr.Add(p, "content type overlaps with %q", other)
+			continue
+		}
+		seen[p.AttributeName()] = p.ContentType
+		if HasFile(p.Attribute.Type) {
+			verr.Add(p, "payload cannot contain a file")
+		}
+	}
+}
+
 // ValidateParams checks the action parameters (make sure they have names, members and types).
 func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
@@ -446,6 +1645,33 @@ func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 	return verr.AsError()
 }
 
+// validateStatic checks that the static action does not define a request body and that its
+// response body is compatible with the type of the response with the static status.
+func (a *ActionDefinition) validateStatic(verr *dslengine.ValidationErrors) {
+	if a.Payload != nil || len(a.Payloads) > 0 {
+		verr.Add(a, "static actions cannot define a payload")
+	}
+	if a.StreamingResult != nil {
+		verr.Add(a, "static actions cannot stream results")
+	}
+	resp := a.StaticResponse()
+	if resp == nil {
+		verr.Add(a, "static action must define a response with status %d", a.Static.Status)
+		return
+	}
+	if a.Static.Body == nil {
+		return
+	}
+	att := resp.bodyAttribute()
+	if att == nil || goa.BodylessStatus(resp.Status) {
+		verr.Add(a, "static response body is defined but %s has no body", resp.Context())
+		return
+	}
+	if err := validateExample("body", att, a.Static.Body); err != nil {
+		verr.Add(a, "invalid static response body: %s", err)
+	}
+}
+
 // validated keeps track of validated attributes to handle cyclical definitions.
 var validated = make(map[*AttributeDefinition]bool)
 
@@ -466,6 +1692,28 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 	if ctx != "" {
 		ctx += " - "
 	}
+	for _, k := range a.Metadata.UnknownKeys() {
+		verr.Warn(parent, "%sunknown metadata key %#v", ctx, k)
+	}
+	if f, ok := a.Metadata.Last("format:time"); ok {
+		switch a.Type.Kind() {
+		case DateTimeKind:
+			if f != TimeFormatRFC3339 && f != TimeFormatUnix && f != TimeFormatUnixMilli {
+				verr.Add(parent, "%sinvalid time format %#v, must be one of %#v, %#v or %#v", ctx, f,
+					TimeFormatRFC3339, TimeFormatUnix, TimeFormatUnixMilli)
+			}
+		case DurationKind:
+			if f != DurationFormatString && f != DurationFormatMilli {
+				verr.Add(parent, "%sinvalid duration format %#v, must be %#v or %#v", ctx, f,
+					DurationFormatString, DurationFormatMilli)
+			}
+		default:
+			verr.Add(parent, "%stime format metadata can only be used on DateTime and Duration attributes", ctx)
+		}
+	}
+	if a.IsReadOnly() && a.IsWriteOnly() {
+		verr.Add(parent, "%sattribute cannot be both read-only and write-only", ctx)
+	}
 	// If both Default and Enum are given, make sure the Default value is one of Enum values.
 	// TODO: We only do the default value and enum check just for primitive types.
 	// Issue 388 (https://github.com/goadesign/goa/issues/388) will address this for other types.
@@ -495,6 +1743,15 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 				verr.Add(parent, `%srequired field "%s" does not exist`, ctx, n)
 			}
 		}
+		if a.Validation != nil {
+			for _, g := range a.Validation.Together {
+				for _, n := range g {
+					if _, ok := o[n]; !ok {
+						verr.Add(parent, `%sfield "%s" required together with %s does not exist`, ctx, n, strings.Join(g, ", "))
+					}
+				}
+			}
+		}
 		for n, att := range o {
 			ctx = fmt.Sprintf("field %s", n)
 			verr.Merge(att.Validate(ctx, parent))
@@ -519,15 +1776,101 @@ func (r *ResponseDefinition) Validate() *dslengine.ValidationErrors {
 	if r.Status == 0 {
 		verr.Add(r, "response status not defined")
 	}
+	validateMetadata(r, r.Metadata, verr)
+	if goa.BodylessStatus(r.Status) && (r.MediaType != "" || r.Type != nil) {
+		verr.Add(r, "responses with status %d cannot have a body, remove the media type or type and use headers instead", r.Status)
+	}
+	if ex := r.BodyExample(); ex != nil && r.staticAction() == nil {
+		if att := r.bodyAttribute(); att == nil {
+			if r.Example != nil {
+				verr.Add(r, "response example is defined but the response has no body")
+			}
+		} else if err := validateExample("body", att, ex); err != nil {
+			verr.Add(r, "invalid response example: %s", err)
+		}
+	}
 	return verr.AsError()
 }
 
+// validateResponseExamples checks that the API response examples use valid status codes.
+func (a *APIDefinition) validateResponseExamples(verr *dslengine.ValidationErrors) {
+	for status, ex := range a.ResponseExamples {
+		if status < 100 || status > 599 {
+			verr.Add(ex, "invalid status code %d", status)
+		} else if goa.BodylessStatus(status) {
+			verr.Add(ex, "responses with status %d cannot have a body", status)
+		}
+		if ex.Value == nil {
+			verr.Add(ex, "missing example value")
+		}
+	}
+}
+
+// validateExample checks that the example value ex is compatible with the type of att. Objects
+// must define all the required attributes and only attributes of the type.
+func validateExample(ctx string, att *AttributeDefinition, ex interface{}) error {
+	if ex == nil || att.Type == nil {
+		return nil
+	}
+	if !att.Type.IsCompatible(ex) {
+		return fmt.Errorf("%s: value %#v is incompatible with type %s", ctx, ex, att.Type.Name())
+	}
+	switch {
+	case att.Type.IsObject():
+		m, ok := ex.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		obj := att.Type.ToObject()
+		if v := att.Validation; v != nil {
+			for _, n := range v.Required {
+				if _, ok := m[n]; !ok {
+					return fmt.Errorf("%s: missing required attribute %q", ctx, n)
+				}
+			}
+		}
+		if ut, ok := att.Type.(*UserTypeDefinition); ok {
+			return validateExample(ctx, ut.AttributeDefinition, ex)
+		}
+		if mt, ok := att.Type.(*MediaTypeDefinition); ok {
+			return validateExample(ctx, mt.AttributeDefinition, ex)
+		}
+		names := make([]string, 0, len(m))
+		for n := range m {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			child, ok := obj[n]
+			if !ok {
+				return fmt.Errorf("%s: unknown attribute %q", ctx, n)
+			}
+			if err := validateExample(ctx+"."+n, child, m[n]); err != nil {
+				return err
+			}
+		}
+	case att.Type.IsArray():
+		elem := att.Type.ToArray().ElemType
+		if elem == nil {
+			return nil
+		}
+		v := reflect.ValueOf(ex)
+		for i := 0; i < v.Len(); i++ {
+			if err := validateExample(fmt.Sprintf("%s[%d]", ctx, i), elem, v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Validate checks that the route definition is consistent: it has a parent.
 func (r *RouteDefinition) Validate() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
 	if r.Parent == nil {
 		verr.Add(r, "missing route parent action")
 	}
+	validateMetadata(r, r.Metadata, verr)
 	return verr.AsError()
 }
 
@@ -646,3 +1989,20 @@ func (v *ViewDefinition) Validate() *dslengine.ValidationErrors {
 	verr.Merge(v.AttributeDefinition.Validate("", v))
 	return verr.AsError()
 }
+
+// isToken returns true if s is a valid HTTP token as defined in RFC 7230 section 3.2.6, header
+// names must be tokens.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -67,6 +67,50 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("with a time format", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, DateTime, func() {
+						Metadata("format:time", "unix-ms")
+					})
+				}
+			})
+
+			It("records the format", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(att.TimeFormat()).Should(Equal(TimeFormatUnixMilli))
+			})
+		})
+
+		Context("with a duration format", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, Duration, func() {
+						Metadata("format:time", "unix")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid duration format"))
+			})
+		})
+
+		Context("with a time format on a string attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("format:time", "unix")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
 		Context("with a default value that doesn't exist in enum", func() {
 			BeforeEach(func() {
 				dsl = func() {
@@ -312,6 +356,600 @@ var _ = Describe("Validation", func() {
 		})
 	})
 
+	Context("with schemes", func() {
+		var schemes []string
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("one", func() {
+				Action("first", func() {
+					Routing(GET("/first"))
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Design.Resources["one"].Actions["first"].Schemes = schemes
+		})
+
+		Context("that are supported", func() {
+			BeforeEach(func() {
+				schemes = []string{"https", "grpcs"}
+			})
+
+			It("validates", func() {
+				Ω(Design.Validate()).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("that are not supported", func() {
+			BeforeEach(func() {
+				schemes = []string{"ftp"}
+			})
+
+			It("produces an error referencing the action", func() {
+				err := Design.Validate()
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring(`resource "one" action "first"`))
+				Ω(err.Error()).Should(ContainSubstring(`unsupported scheme "ftp"`))
+			})
+		})
+	})
+
+	Context("with docs", func() {
+		var docsURL string
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("one", func() {
+				Action("first", func() {
+					Routing(GET("/first"))
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Design.Resources["one"].Actions["first"].Docs = &DocsDefinition{URL: docsURL}
+		})
+
+		Context("with an absolute URL", func() {
+			BeforeEach(func() {
+				docsURL = "https://goa.design/docs"
+			})
+
+			It("validates", func() {
+				Ω(Design.Validate()).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with a relative path", func() {
+			BeforeEach(func() {
+				docsURL = "/docs"
+			})
+
+			It("produces an error", func() {
+				err := Design.Validate()
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring(`invalid action docs URL value: "/docs" is not an absolute URL`))
+			})
+		})
+
+		Context("with an empty URL", func() {
+			BeforeEach(func() {
+				docsURL = ""
+			})
+
+			It("validates", func() {
+				Ω(Design.Validate()).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a resource", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("empty", dsl)
+			dslengine.Run()
+		})
+
+		Context("with no action and no file server", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					BasePath("/empty")
+				}
+			})
+
+			It("records a warning", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(dslengine.Warnings).Should(Equal([]string{
+					`resource "empty": resource defines no action and no file server, no code is generated for it`,
+				}))
+			})
+		})
+
+		Context("with an action", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Action("show", func() {
+						Routing(GET(""))
+					})
+				}
+			})
+
+			It("validates without warnings", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(dslengine.Warnings).Should(BeEmpty())
+			})
+		})
+
+		Context("with an audience not declared by the API", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Action("show", func() {
+						Audience("partners")
+						Routing(GET(""))
+					})
+				}
+			})
+
+			It("records a warning", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(dslengine.Warnings).Should(Equal([]string{
+					`resource "empty" action "show": audience "partners" is not declared in the API definition`,
+				}))
+			})
+		})
+
+		Context("with a file server without file path", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Files("/index.html", "")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("File server must have a non empty file path"))
+			})
+		})
+
+		Context("with consistent file servers", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Files("/*filepath", "public")
+					Files("/", "public/index.html")
+					Files("/js/*filepath", "public/js")
+					Files("/css/site.css", "public/css/site.css")
+				}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with overlapping file servers", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Files("/public/*filepath", "public")
+					Files("/public/js/*filepath", "assets/js")
+					Files("/index.html", "public/index.html")
+					Files("index.html", "www/index.html")
+				}
+			})
+
+			It("produces an error for each conflict", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+					`resource "empty" file server assets/js: request path "/public/js/*" is also served by resource "empty" file server public from a different file`))
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+					`resource "empty" file server www/index.html: request path "/index.html" is also served by resource "empty" file server public/index.html from a different file`))
+			})
+		})
+	})
+
+	Context("with an action removing inherited path params", func() {
+		var dsl, parentDSL func()
+		var names []string
+
+		BeforeEach(func() {
+			names = []string{"accountID"}
+			parentDSL = func() {}
+			dsl = func() {}
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("account", func() {
+				BasePath("/accounts")
+				Action("show", func() {
+					Routing(GET("/:accountID"))
+				})
+				parentDSL()
+			})
+			Resource("bottle", func() {
+				Parent("account")
+				BasePath("/bottles")
+				Action("search", func() {
+					Routing(GET("/search"))
+					NoInheritedParams(names...)
+					dsl()
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("bound to a header", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Headers(func() {
+						Header("X-AccountID")
+					})
+				}
+			})
+
+			It("removes the params from the action routes", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				a := Design.Resources["bottle"].Actions["search"]
+				Ω(a.Routes[0].FullPath()).Should(Equal("/accounts/bottles/search"))
+				Ω(a.AllParams().Type.ToObject()).ShouldNot(HaveKey("accountID"))
+				Ω(a.QueryParams.Type.ToObject()).ShouldNot(HaveKey("accountID"))
+			})
+		})
+
+		Context("bound to a query string param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Params(func() {
+						Param("accountID", Integer)
+					})
+				}
+			})
+
+			It("makes the params query string params", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				a := Design.Resources["bottle"].Actions["search"]
+				Ω(a.Routes[0].FullPath()).Should(Equal("/accounts/bottles/search"))
+				Ω(a.QueryParams.Type.ToObject()).Should(HaveKey("accountID"))
+			})
+		})
+
+		Context("not bound", func() {
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`path parameter "accountID" is removed from the action routes but is not bound`))
+			})
+		})
+
+		Context("that are not inherited", func() {
+			BeforeEach(func() {
+				names = []string{"id"}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"id" is not a path parameter of the resource path "/accounts/:accountID/bottles"`))
+			})
+		})
+
+		Context("resulting in an ambiguous route", func() {
+			BeforeEach(func() {
+				parentDSL = func() {
+					Action("searchBottles", func() {
+						Routing(GET("/bottles/search"))
+					})
+				}
+				dsl = func() {
+					Headers(func() {
+						Header("X-AccountID")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`route GET "/accounts/bottles/search" is ambiguous with route GET "/accounts/bottles/search" of resource "account" action "searchBottles"`))
+			})
+		})
+	})
+
+	Context("with an action omitting resource params and headers", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("bottle", func() {
+				BasePath("/bottles/:tenantID")
+				Params(func() {
+					Param("tenantID")
+					Param("tenant")
+					Param("region")
+					Required("tenant", "region")
+				})
+				Headers(func() {
+					Header("X-Tenant")
+					Required("X-Tenant")
+				})
+				Action("health", func() {
+					Routing(GET("/health"))
+					dsl()
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("that the resource defines", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					OmitParam("tenant")
+					OmitHeader("X-Tenant")
+					Params(func() {
+						Param("region", Integer)
+					})
+				}
+			})
+
+			It("removes them from the action", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				a := Design.Resources["bottle"].Actions["health"]
+				params := a.AllParams()
+				Ω(params.Type.ToObject()).ShouldNot(HaveKey("tenant"))
+				Ω(params.Type.ToObject()["region"].Type).Should(Equal(Integer))
+				Ω(params.IsRequired("region")).Should(BeFalse())
+				Ω(a.QueryParams.Type.ToObject()).ShouldNot(HaveKey("tenant"))
+				Ω(a.AllHeaders().Type.ToObject()).Should(BeEmpty())
+			})
+		})
+
+		Context("that the resource does not define", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					OmitParam("unknown")
+					OmitHeader("X-Unknown")
+				}
+			})
+
+			It("produces errors", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`omitted param "unknown" is not a param of resource "bottle"`))
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`omitted header "X-Unknown" is not a header of resource "bottle"`))
+			})
+		})
+
+		Context("used by the action routes", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					OmitParam("tenantID")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`omitted param "tenantID" is used by route GET "/bottles/:tenantID/health"`))
+			})
+		})
+	})
+
+	Context("with a parent resource whose canonical action has an empty route", func() {
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("parent", func() {
+				Action("show", func() {
+					Routing(GET(""))
+				})
+			})
+			Resource("child", func() {
+				Parent("parent")
+				BasePath("/children")
+				Action("list", func() {
+					Routing(GET(""))
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("reports the empty parent path", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`resource "child": canonical action "show" of parent resource "parent" resolves to the empty path "/", the resource paths cannot be nested under it`))
+		})
+	})
+
+	Context("with checks spanning multiple resources", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Action("list", func() {
+					Routing(GET(""))
+				})
+			})
+			dsl()
+			dslengine.Run()
+		})
+
+		Context("with colliding routes", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Resource("wine", func() {
+						Action("list", func() {
+							Routing(GET("/bottles"))
+						})
+					})
+				}
+			})
+
+			It("reports the collision on both actions", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				verr := Design.ValidateResources()
+				Ω(verr).ShouldNot(BeNil())
+				Ω(verr.Errors).Should(HaveLen(2))
+				Ω(verr.Error()).Should(ContainSubstring(`resource "bottle" action "list": route GET "/bottles" is ambiguous with route GET "/bottles" of resource "wine" action "list"`))
+				Ω(verr.Error()).Should(ContainSubstring(`resource "wine" action "list": route GET "/bottles" is ambiguous with route GET "/bottles" of resource "bottle" action "list"`))
+			})
+		})
+
+		Context("with duplicate names", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Resource("Bottle", func() {
+						BasePath("/other")
+						Action("show", func() {
+							Routing(GET("/:id"))
+						})
+						Webhook("shipped", func() {
+							Routing(POST("/"))
+							Payload(func() {
+								Member("id", String)
+							})
+							Response(NoContent)
+						})
+					})
+					Resource("order", func() {
+						Action("show", func() {
+							Routing(GET("/orders/:id"))
+						})
+						Webhook("shipped", func() {
+							Routing(POST("/"))
+							Payload(func() {
+								Member("id", String)
+							})
+							Response(NoContent)
+						})
+					})
+				}
+			})
+
+			It("reports the conflicting resource and webhook names", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				verr := Design.ValidateResources()
+				Ω(verr).ShouldNot(BeNil())
+				Ω(verr.Error()).Should(ContainSubstring(`resource name "bottle" conflicts with resource "Bottle"`))
+				Ω(verr.Error()).Should(ContainSubstring(`webhook name "shipped" is already used by resource "Bottle"`))
+			})
+		})
+
+		Context("with identical resolved base paths", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Resource("account", func() {
+						BasePath("/accounts")
+						Action("show", func() {
+							Routing(GET("/:accountID"))
+						})
+					})
+					Resource("cellar", func() {
+						Parent("account")
+						BasePath("/bottles")
+						Action("search", func() {
+							Routing(GET("/search"))
+						})
+					})
+					Resource("rack", func() {
+						BasePath("/accounts/:accountID/bottles")
+						Action("count", func() {
+							Routing(GET("/count"))
+						})
+					})
+				}
+			})
+
+			It("reports the duplicate base path", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				verr := Design.ValidateResources()
+				Ω(verr).ShouldNot(BeNil())
+				Ω(verr.Errors).Should(HaveLen(1))
+				Ω(verr.Error()).Should(ContainSubstring(`resource "rack": base path "/accounts/:accountID/bottles" resolves to "/accounts/:accountID/bottles" which is also the full path of resource "cellar"`))
+			})
+		})
+
+		Context("with no conflict", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Resource("wine", func() {
+						BasePath("/wines")
+						Action("list", func() {
+							Routing(GET(""))
+						})
+					})
+				}
+			})
+
+			It("returns nil", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.ValidateResources()).Should(BeNil())
+			})
+		})
+	})
+
+	Context("with a child resource", func() {
+		var path string
+		var strict bool
+
+		BeforeEach(func() {
+			strict = false
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			dslengine.Strict = strict
+			Resource("parent", func() {
+				BasePath("/parents")
+				Action("show", func() {
+					Routing(GET("/:id"))
+				})
+			})
+			Resource("child", func() {
+				Parent("parent")
+				Action("list", func() {
+					Routing(GET(path))
+				})
+			})
+			dslengine.Run()
+		})
+
+		AfterEach(func() {
+			dslengine.Strict = false
+		})
+
+		Context("with a relative path", func() {
+			BeforeEach(func() {
+				path = "/children"
+			})
+
+			It("validates without warnings", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(dslengine.Warnings).Should(BeEmpty())
+			})
+		})
+
+		Context("with an absolute path", func() {
+			BeforeEach(func() {
+				path = "//children"
+			})
+
+			It("records a warning", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(dslengine.Warnings).Should(Equal([]string{
+					`resource "child" action "list": route GET "//children" is absolute and ignores the path of parent resource "parent"`,
+				}))
+			})
+
+			Context("in strict mode", func() {
+				BeforeEach(func() {
+					strict = true
+				})
+
+				It("produces an error", func() {
+					Ω(dslengine.Errors).Should(HaveOccurred())
+					Ω(dslengine.Errors.Error()).Should(ContainSubstring(`route GET "//children" is absolute`))
+					Ω(dslengine.Warnings).Should(BeEmpty())
+				})
+			})
+		})
+	})
+
 	Context("with an action", func() {
 		var dsl func()
 
@@ -417,6 +1055,61 @@ var _ = Describe("Validation", func() {
 				))
 			})
 		})
+
+		Context("which has a no content response with a body", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Response(NoContent, func() {
+						Media("application/json")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+					"responses with status 204 cannot have a body, remove the media type or type and use headers instead",
+				))
+			})
+		})
+
+		Context("which has a no content response with headers", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Response(NoContent, func() {
+						Headers(func() {
+							Header("Location")
+						})
+					})
+				}
+			})
+
+			It("produces no error", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("which has misspelled metadata", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Metadata("swagger:generat", "false")
+					Metadata("swagger:extension:x-foo", "{}")
+					Metadata("custom:anything", "value")
+					Params(func() {
+						Param("id", func() {
+							Metadata("struct:field:nam", "ID")
+						})
+					})
+				}
+			})
+
+			It("warns about the unknown keys of known namespaces", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(dslengine.Warnings).Should(ContainElement(`resource "foo" action "bar": unknown metadata key "swagger:generat"`))
+				Ω(dslengine.Warnings).Should(ContainElement(HaveSuffix(`id - unknown metadata key "struct:field:nam"`)))
+				Ω(dslengine.Warnings).Should(HaveLen(2))
+			})
+		})
 	})
 
 	Describe("EncoderDefinition", func() {
@@ -0,0 +1,245 @@
+package design
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+type (
+	// Visitor is the interface implemented by the values given to Walk.
+	Visitor interface {
+		// Enter is called when the walk reaches def, before the children of def are
+		// walked. Returning SkipChildren skips the children of def, returning any
+		// other error stops the walk.
+		Enter(def dslengine.Definition, path string) error
+		// Exit is called once the children of def have been walked or skipped.
+		// Returning an error stops the walk.
+		Exit(def dslengine.Definition, path string) error
+	}
+
+	// WalkFunc is a Visitor that only implements Enter.
+	WalkFunc func(def dslengine.Definition, path string) error
+
+	// walker holds the state of a walk.
+	walker struct {
+		v Visitor
+		// types lists the user types whose attributes are being walked, it guards
+		// against infinite recursion with recursive types.
+		types map[*UserTypeDefinition]bool
+	}
+)
+
+// SkipChildren is returned by Visitor.Enter to skip the children of a definition.
+var SkipChildren = errors.New("skip children")
+
+// Enter calls f.
+func (f WalkFunc) Enter(def dslengine.Definition, path string) error { return f(def, path) }
+
+// Exit does nothing.
+func (f WalkFunc) Exit(def dslengine.Definition, path string) error { return nil }
+
+// Walk traverses the API definition tree depth-first, calling v.Enter on each definition before
+// walking its children and v.Exit after. The path identifies the definition in the tree, e.g.
+// "resources.bottle.actions.show.payload.name". Walk stops and returns the first error returned
+// by the visitor other than SkipChildren.
+//
+// The children are walked in the following order, skipping nil definitions:
+//
+//	*APIDefinition:         "params" attribute, "responses.<name>", "health" and
+//	                        "resources.<name>" in IterateResources order
+//	*ResourceDefinition:    "params" and "headers" attributes, "responses.<name>",
+//	                        "files[<index>]", "health", "actions.<name>", "webhooks.<name>"
+//	                        and "async.<name>"
+//	*ActionDefinition:      "routes[<index>]", "params" and "headers" attributes, "payload"
+//	                        and "message" attributes and "responses.<name>"
+//	*ResponseDefinition:    "headers" attribute
+//	*AttributeDefinition:   object attributes "<path>.<name>", array element "<path>[]", hash
+//	                        key "<path>{key}" and hash element "<path>{}"
+//
+// Maps are walked in alphabetical order of their keys, slices in order. The other definitions
+// (*FileServerDefinition, *HealthCheckDefinition and *RouteDefinition) have no children. The
+// attributes of recursive user types are walked once per branch. Walk may visit new kinds of
+// definitions in the future so visitors should ignore the definitions they do not handle.
+func Walk(api *APIDefinition, v Visitor) error {
+	w := &walker{v: v, types: make(map[*UserTypeDefinition]bool)}
+	return w.walk(api, "", func() error {
+		if err := w.attribute(api.Params, "params"); err != nil {
+			return err
+		}
+		if err := w.responses(api.Responses, "responses"); err != nil {
+			return err
+		}
+		if err := w.leaf(api.HealthCheck, "health"); err != nil {
+			return err
+		}
+		return api.IterateResources(func(r *ResourceDefinition) error {
+			return w.resource(r, "resources."+r.Name)
+		})
+	})
+}
+
+// walk calls Enter, the children function unless skipped and Exit.
+func (w *walker) walk(def dslengine.Definition, path string, children func() error) error {
+	err := w.v.Enter(def, path)
+	if err == nil && children != nil {
+		err = children()
+	}
+	if err != nil && err != SkipChildren {
+		return err
+	}
+	return w.v.Exit(def, path)
+}
+
+// leaf walks a definition that has no children.
+func (w *walker) leaf(def dslengine.Definition, path string) error {
+	switch d := def.(type) {
+	case *HealthCheckDefinition:
+		if d == nil {
+			return nil
+		}
+	}
+	return w.walk(def, path, nil)
+}
+
+func (w *walker) resource(r *ResourceDefinition, path string) error {
+	return w.walk(r, path, func() error {
+		if err := w.attribute(r.Params, path+".params"); err != nil {
+			return err
+		}
+		if err := w.attribute(r.Headers, path+".headers"); err != nil {
+			return err
+		}
+		if err := w.responses(r.Responses, path+".responses"); err != nil {
+			return err
+		}
+		for i, fs := range r.FileServers {
+			if err := w.leaf(fs, fmt.Sprintf("%s.files[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		if err := w.leaf(r.HealthCheck, path+".health"); err != nil {
+			return err
+		}
+		err := r.IterateActions(func(a *ActionDefinition) error {
+			return w.action(a, path+".actions."+a.Name)
+		})
+		if err != nil {
+			return err
+		}
+		err = r.IterateWebhooks(func(a *ActionDefinition) error {
+			return w.action(a, path+".webhooks."+a.Name)
+		})
+		if err != nil {
+			return err
+		}
+		return r.IterateAsyncActions(func(a *ActionDefinition) error {
+			return w.action(a, path+".async."+a.Name)
+		})
+	})
+}
+
+func (w *walker) action(a *ActionDefinition, path string) error {
+	return w.walk(a, path, func() error {
+		for i, r := range a.Routes {
+			if err := w.leaf(r, fmt.Sprintf("%s.routes[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		if err := w.attribute(a.Params, path+".params"); err != nil {
+			return err
+		}
+		if err := w.attribute(a.Headers, path+".headers"); err != nil {
+			return err
+		}
+		if err := w.userType(a.Payload, path+".payload"); err != nil {
+			return err
+		}
+		if err := w.userType(a.Message, path+".message"); err != nil {
+			return err
+		}
+		return a.IterateResponses(func(r *ResponseDefinition) error {
+			return w.response(r, path+".responses."+r.Name)
+		})
+	})
+}
+
+// responses walks the given responses in alphabetical order of their names.
+func (w *walker) responses(responses map[string]*ResponseDefinition, path string) error {
+	names := make([]string, 0, len(responses))
+	for n := range responses {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := w.response(responses[n], path+"."+n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) response(r *ResponseDefinition, path string) error {
+	return w.walk(r, path, func() error {
+		return w.attribute(r.Headers, path+".headers")
+	})
+}
+
+// userType walks the attribute of a user type.
+func (w *walker) userType(ut *UserTypeDefinition, path string) error {
+	if ut == nil || w.types[ut] {
+		return nil
+	}
+	w.types[ut] = true
+	defer delete(w.types, ut)
+	return w.attribute(ut.AttributeDefinition, path)
+}
+
+func (w *walker) attribute(att *AttributeDefinition, path string) error {
+	if att == nil {
+		return nil
+	}
+	return w.walk(att, path, func() error {
+		if att.Type == nil {
+			return nil
+		}
+		var ut *UserTypeDefinition
+		switch t := att.Type.(type) {
+		case *UserTypeDefinition:
+			ut = t
+		case *MediaTypeDefinition:
+			ut = t.UserTypeDefinition
+		}
+		if ut != nil {
+			if w.types[ut] {
+				return nil
+			}
+			w.types[ut] = true
+			defer delete(w.types, ut)
+		}
+		if obj := att.Type.ToObject(); obj != nil {
+			names := make([]string, 0, len(obj))
+			for n := range obj {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			for _, n := range names {
+				if err := w.attribute(obj[n], path+"."+n); err != nil {
+					return err
+				}
+			}
+		}
+		if arr := att.Type.ToArray(); arr != nil {
+			return w.attribute(arr.ElemType, path+"[]")
+		}
+		if h := att.Type.ToHash(); h != nil {
+			if err := w.attribute(h.KeyType, path+"{key}"); err != nil {
+				return err
+			}
+			return w.attribute(h.ElemType, path+"{}")
+		}
+		return nil
+	})
+}
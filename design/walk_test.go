@@ -0,0 +1,142 @@
+package design_test
+
+import (
+	"errors"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// recorder records the paths of the definitions entered and exited.
+type recorder struct {
+	entered, exited []string
+	enter           func(def dslengine.Definition) error
+}
+
+func (r *recorder) Enter(def dslengine.Definition, path string) error {
+	r.entered = append(r.entered, path)
+	if r.enter != nil {
+		return r.enter(def)
+	}
+	return nil
+}
+
+func (r *recorder) Exit(def dslengine.Definition, path string) error {
+	r.exited = append(r.exited, path)
+	return nil
+}
+
+var _ = Describe("Walk", func() {
+	var rec *recorder
+	var err error
+
+	indexOf := func(path string) int {
+		for i, p := range rec.entered {
+			if p == path {
+				return i
+			}
+		}
+		return -1
+	}
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		rec = &recorder{}
+		API("test", func() {})
+		node := Type("node", func() {
+			Attribute("name", String)
+			Attribute("next", "node")
+			Attribute("tags", ArrayOf(String))
+		})
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			Files("/index.html", "index.html")
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Params(func() {
+					Param("id", Integer)
+				})
+				Payload(node)
+				Response(OK)
+				Response(NotFound)
+			})
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		err = Walk(Design, rec)
+	})
+
+	It("walks the definitions in order", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rec.entered[0]).Should(Equal(""))
+		order := []string{
+			"resources.bottle",
+			"resources.bottle.files[0]",
+			"resources.bottle.actions.show",
+			"resources.bottle.actions.show.routes[0]",
+			"resources.bottle.actions.show.params",
+			"resources.bottle.actions.show.params.id",
+			"resources.bottle.actions.show.payload",
+			"resources.bottle.actions.show.payload.name",
+			"resources.bottle.actions.show.payload.next",
+			"resources.bottle.actions.show.payload.tags",
+			"resources.bottle.actions.show.payload.tags[]",
+			"resources.bottle.actions.show.responses.NotFound",
+			"resources.bottle.actions.show.responses.OK",
+		}
+		prev := -1
+		for _, p := range order {
+			i := indexOf(p)
+			Ω(i).Should(BeNumerically(">", prev), p)
+			prev = i
+		}
+	})
+
+	It("walks recursive types once per branch", func() {
+		Ω(indexOf("resources.bottle.actions.show.payload.next.name")).Should(Equal(-1))
+	})
+
+	It("exits each definition after its children", func() {
+		Ω(rec.exited).Should(HaveLen(len(rec.entered)))
+		Ω(rec.exited[len(rec.exited)-1]).Should(Equal(""))
+	})
+
+	Context("with a visitor that skips actions", func() {
+		BeforeEach(func() {
+			rec.enter = func(def dslengine.Definition) error {
+				if _, ok := def.(*ActionDefinition); ok {
+					return SkipChildren
+				}
+				return nil
+			}
+		})
+
+		It("skips the action children", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(indexOf("resources.bottle.actions.show")).ShouldNot(Equal(-1))
+			Ω(indexOf("resources.bottle.actions.show.routes[0]")).Should(Equal(-1))
+			Ω(rec.exited).Should(ContainElement("resources.bottle.actions.show"))
+		})
+	})
+
+	Context("with a visitor that returns an error", func() {
+		BeforeEach(func() {
+			rec.enter = func(def dslengine.Definition) error {
+				if _, ok := def.(*RouteDefinition); ok {
+					return errors.New("boom")
+				}
+				return nil
+			}
+		})
+
+		It("stops the walk", func() {
+			Ω(err).Should(MatchError("boom"))
+			Ω(rec.entered[len(rec.entered)-1]).Should(Equal("resources.bottle.actions.show.routes[0]"))
+		})
+	})
+})
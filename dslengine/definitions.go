@@ -1,6 +1,12 @@
 package dslengine
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
 
 type (
 
@@ -52,6 +58,15 @@ type (
 		DSL() func()
 	}
 
+	// Prepare is the interface implemented by definitions that need to normalize their values
+	// once the DSL has executed and before they are validated.
+	Prepare interface {
+		Definition
+		// Prepare is run by the DSL runner once the definition DSL has executed and before
+		// the definition is validated.
+		Prepare()
+	}
+
 	// Finalize is the interface implemented by definitions that require an additional pass
 	// after the DSL has executed (e.g. to merge generated definitions or initialize default
 	// values)
@@ -103,9 +118,51 @@ type (
 		// Required list the required fields of object attributes as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor61.
 		Required []string
+		// Together lists groups of fields of object attributes that must be either all
+		// present or all absent.
+		Together [][]string
 	}
 )
 
+// Keys returns the metadata keys sorted alphabetically. Code that emits metadata iterates over the
+// keys returned by Keys so that the generated output is identical across runs.
+func (m MetadataDefinition) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MarshalJSON encodes the metadata as a JSON object whose keys are sorted alphabetically and
+// whose values are listed in the order they were defined.
+func (m MetadataDefinition) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.Keys() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(m[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
 // Context returns the generic definition name used in error messages.
 func (t *TraitDefinition) Context() string {
 	if t.Name != "" {
@@ -148,6 +205,7 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 		v.MaxLength = other.MaxLength
 	}
 	v.AddRequired(other.Required)
+	v.AddTogether(other.Together...)
 }
 
 // AddRequired merges the required fields from other into v
@@ -166,6 +224,22 @@ func (v *ValidationDefinition) AddRequired(required []string) {
 	}
 }
 
+// AddTogether merges the given groups of fields that must be present together into v.
+func (v *ValidationDefinition) AddTogether(groups ...[]string) {
+	for _, g := range groups {
+		found := false
+		for _, gg := range v.Together {
+			if strings.Join(g, ",") == strings.Join(gg, ",") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.Together = append(v.Together, g)
+		}
+	}
+}
+
 // HasRequiredOnly returns true if the validation only has the Required field with a non-zero value.
 func (v *ValidationDefinition) HasRequiredOnly() bool {
 	if len(v.Values) > 0 {
@@ -177,6 +251,9 @@ func (v *ValidationDefinition) HasRequiredOnly() bool {
 	if (v.Minimum != nil) || (v.Maximum != nil) || (v.MaxLength != nil) {
 		return false
 	}
+	if len(v.Together) > 0 {
+		return false
+	}
 	return true
 }
 
@@ -191,5 +268,6 @@ func (v *ValidationDefinition) Dup() *ValidationDefinition {
 		MinLength: v.MinLength,
 		MaxLength: v.MaxLength,
 		Required:  v.Required,
+		Together:  v.Together,
 	}
 }
@@ -0,0 +1,83 @@
+package dslengine_test
+
+import (
+	"encoding/json"
+
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MetadataDefinition", func() {
+	var meta dslengine.MetadataDefinition
+
+	BeforeEach(func() {
+		meta = dslengine.MetadataDefinition{
+			"swagger:extension:x-b": {"2"},
+			"struct:tag:json":       {"name", "omitempty"},
+			"swagger:tag:pets":      {"Pets"},
+			"a":                     {"z", "y"},
+		}
+	})
+
+	It("returns the keys sorted alphabetically", func() {
+		Ω(meta.Keys()).Should(Equal([]string{"a", "struct:tag:json", "swagger:extension:x-b", "swagger:tag:pets"}))
+	})
+
+	It("serializes identically across runs", func() {
+		expected := `{"a":["z","y"],"struct:tag:json":["name","omitempty"],"swagger:extension:x-b":["2"],"swagger:tag:pets":["Pets"]}`
+		for i := 0; i < 20; i++ {
+			b, err := json.Marshal(meta)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(expected))
+		}
+	})
+
+	It("serializes nil metadata as null", func() {
+		var empty dslengine.MetadataDefinition
+		b, err := json.Marshal(empty)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal("null"))
+	})
+
+	It("returns the last value of a key", func() {
+		val, ok := meta.Last("a")
+		Ω(ok).Should(BeTrue())
+		Ω(val).Should(Equal("y"))
+		_, ok = meta.Last("missing")
+		Ω(ok).Should(BeFalse())
+	})
+
+	It("parses boolean values", func() {
+		meta["swagger:generate"] = []string{"true", "false"}
+		generate, ok := meta.BoolValue("swagger:generate")
+		Ω(ok).Should(BeTrue())
+		Ω(generate).Should(BeFalse())
+		_, ok = meta.BoolValue("a")
+		Ω(ok).Should(BeFalse())
+		_, ok = meta.BoolValue("missing")
+		Ω(ok).Should(BeFalse())
+	})
+
+	It("merges the parent metadata giving precedence to its own values", func() {
+		parent := dslengine.MetadataDefinition{"a": {"parent"}, "b": {"parent"}}
+		merged := meta.Merge(parent)
+		Ω(merged["a"]).Should(Equal([]string{"z", "y"}))
+		Ω(merged["b"]).Should(Equal([]string{"parent"}))
+		Ω(meta).ShouldNot(HaveKey("b"))
+		Ω(parent["a"]).Should(Equal([]string{"parent"}))
+	})
+
+	It("lists the unknown keys of known namespaces", func() {
+		dslengine.RegisterMetadataKeys("test", "known", "prefix:")
+		meta = dslengine.MetadataDefinition{
+			"test:known":        nil,
+			"test:prefix:any":   nil,
+			"test:unknown":      nil,
+			"other:unknown":     nil,
+			"test":              nil,
+			"test:known:suffix": nil,
+		}
+		Ω(meta.UnknownKeys()).Should(Equal([]string{"test:known:suffix", "test:unknown"}))
+	})
+})
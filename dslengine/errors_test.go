@@ -0,0 +1,74 @@
+package dslengine_test
+
+import (
+	"fmt"
+
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DSL error reporting", func() {
+	var maxErrors int
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		maxErrors = dslengine.MaxErrors
+	})
+
+	AfterEach(func() {
+		dslengine.MaxErrors = maxErrors
+	})
+
+	Context("with nested invalid DSL", func() {
+		BeforeEach(func() {
+			API("foo", func() {})
+			Resource("bar", func() {
+				Action("baz", func() {
+					Attributes(func() {})
+					Routing(GET(""))
+				})
+				Attributes(func() {})
+			})
+			dslengine.Run()
+		})
+
+		It("reports all the errors annotated with the DSL function and stack", func() {
+			Ω(dslengine.Errors).Should(HaveLen(2))
+			Ω(dslengine.Errors[0].DSL).Should(Equal("Attributes"))
+			Ω(dslengine.Errors[0].Stack).Should(Equal([]string{`resource "bar"`, `action "baz"`}))
+			Ω(dslengine.Errors[0].Error()).Should(ContainSubstring(`in resource "bar" > action "baz"`))
+			Ω(dslengine.Errors[1].Stack).Should(Equal([]string{`resource "bar"`}))
+		})
+	})
+
+	Context("with more errors than the limit", func() {
+		BeforeEach(func() {
+			dslengine.MaxErrors = 2
+			for i := 0; i < 5; i++ {
+				dslengine.ReportError("err%d", i)
+			}
+		})
+
+		It("records the errors up to the limit", func() {
+			Ω(dslengine.Errors).Should(HaveLen(2))
+			Ω(dslengine.Errors.Error()).Should(HaveSuffix("too many errors, 3 more not reported"))
+		})
+	})
+
+	Context("with errors in multiple files", func() {
+		BeforeEach(func() {
+			dslengine.Errors = dslengine.MultiError{
+				{GoError: fmt.Errorf("a1"), File: "a.go", Line: 1},
+				{GoError: fmt.Errorf("b1"), File: "b.go", Line: 1},
+				{GoError: fmt.Errorf("v")},
+				{GoError: fmt.Errorf("a2"), File: "a.go", Line: 2},
+			}
+		})
+
+		It("groups the errors by file", func() {
+			Ω(dslengine.Errors.Error()).Should(Equal("[a.go:1] a1\n[a.go:2] a2\n[b.go:1] b1\nv"))
+		})
+	})
+})
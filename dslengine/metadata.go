@@ -0,0 +1,113 @@
+package dslengine
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// knownMetadata lists the registered metadata keys indexed by namespace.
+	knownMetadata = make(map[string]map[string]bool)
+	// knownMetadataMu protects knownMetadata.
+	knownMetadataMu sync.RWMutex
+)
+
+// RegisterMetadataKeys records the keys of the given metadata namespace, the namespace is the part
+// of the metadata key that precedes the first colon. For example the "swagger:generate" key is
+// registered with RegisterMetadataKeys("swagger", "generate"). A key that ends with a colon
+// registers all the keys with that prefix, e.g. "tag:" registers "swagger:tag:Backend". See
+// MetadataDefinition.UnknownKeys.
+func RegisterMetadataKeys(namespace string, keys ...string) {
+	knownMetadataMu.Lock()
+	defer knownMetadataMu.Unlock()
+	known, ok := knownMetadata[namespace]
+	if !ok {
+		known = make(map[string]bool)
+		knownMetadata[namespace] = known
+	}
+	for _, k := range keys {
+		known[k] = true
+	}
+}
+
+// Last returns the last value of the given key and true if the metadata defines at least one
+// value for the key, the empty string and false otherwise.
+func (m MetadataDefinition) Last(key string) (string, bool) {
+	vals := m[key]
+	if len(vals) == 0 {
+		return "", false
+	}
+	return vals[len(vals)-1], true
+}
+
+// BoolValue returns the boolean value of the given key, that is the last value of the key parsed
+// with strconv.ParseBool. The second value is false if the key has no value or if the value is not
+// a boolean.
+func (m MetadataDefinition) BoolValue(key string) (bool, bool) {
+	val, ok := m.Last(key)
+	if !ok {
+		return false, false
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, false
+	}
+	return b, true
+}
+
+// Merge returns the metadata that contains the keys of m and the keys of parent that m does not
+// define. Definitions inherit the metadata of their parent by merging it so that the values set on
+// the most specific definition take precedence, e.g. the metadata of an action is merged with the
+// metadata of its resource which is merged with the metadata of the API. Merge does not modify m
+// or parent.
+func (m MetadataDefinition) Merge(parent MetadataDefinition) MetadataDefinition {
+	if len(parent) == 0 {
+		return m
+	}
+	if len(m) == 0 {
+		return parent
+	}
+	merged := make(MetadataDefinition, len(m)+len(parent))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range m {
+		merged[k] = v
+	}
+	return merged
+}
+
+// UnknownKeys returns the sorted keys of m that belong to a namespace registered with
+// RegisterMetadataKeys but that are not registered themselves. Keys of unknown namespaces are
+// free-form and never returned.
+func (m MetadataDefinition) UnknownKeys() []string {
+	knownMetadataMu.RLock()
+	defer knownMetadataMu.RUnlock()
+	var unknown []string
+	for _, key := range m.Keys() {
+		i := strings.Index(key, ":")
+		if i == -1 {
+			continue
+		}
+		known, ok := knownMetadata[key[:i]]
+		if !ok || isKnownMetadataKey(known, key[i+1:]) {
+			continue
+		}
+		unknown = append(unknown, key)
+	}
+	return unknown
+}
+
+// isKnownMetadataKey returns true if key or one of its prefixes ending with a colon is in known.
+func isKnownMetadataKey(known map[string]bool, key string) bool {
+	if known[key] {
+		return true
+	}
+	for i, c := range key {
+		if c == ':' && known[key[:i+1]] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package dslengine
+
+import "fmt"
+
+type (
+	// PluginFunc is the signature of the functions registered with RegisterPlugin and
+	// RegisterInspector. The functions are given the DSL roots in execution order.
+	PluginFunc func(roots []Root) error
+
+	// plugin holds the functions registered under a plugin name.
+	plugin struct {
+		name    string
+		mutate  PluginFunc
+		inspect PluginFunc
+	}
+)
+
+// Registered plugins in registration order
+var plugins []*plugin
+
+// RegisterPlugin registers a function that Run invokes once all the DSL has been executed and
+// validated but before the definitions are finalized. The function may modify the definitions,
+// for example to add attributes, headers, responses or metadata to existing definitions. The
+// definitions are validated again after all the plugin functions have run. Errors returned by
+// the function are reported by Run prefixed with the plugin name. RegisterPlugin panics if a
+// function is already registered under the same name.
+func RegisterPlugin(name string, fn PluginFunc) {
+	p := pluginNamed(name)
+	if p.mutate != nil {
+		panic(fmt.Sprintf("dslengine: duplicate plugin %s", name))
+	}
+	p.mutate = fn
+}
+
+// RegisterInspector registers a function that Run invokes once the definitions have been
+// finalized. The function may inspect the definitions but must not modify them. Errors returned
+// by the function are reported by Run prefixed with the plugin name. RegisterInspector panics if
+// an inspector is already registered under the same name.
+func RegisterInspector(name string, fn PluginFunc) {
+	p := pluginNamed(name)
+	if p.inspect != nil {
+		panic(fmt.Sprintf("dslengine: duplicate plugin inspector %s", name))
+	}
+	p.inspect = fn
+}
+
+// pluginNamed returns the plugin with the given name, it creates it if needed.
+func pluginNamed(name string) *plugin {
+	for _, p := range plugins {
+		if p.name == name {
+			return p
+		}
+	}
+	p := &plugin{name: name}
+	plugins = append(plugins, p)
+	return p
+}
+
+// runPlugins calls the mutating plugin functions if mutate is true, the inspecting functions
+// otherwise. It records the errors returned by the functions in Errors and returns true if
+// any function was called.
+func runPlugins(roots []Root, mutate bool) bool {
+	ran := false
+	for _, p := range plugins {
+		fn := p.inspect
+		if mutate {
+			fn = p.mutate
+		}
+		if fn == nil {
+			continue
+		}
+		ran = true
+		if err := fn(roots); err != nil {
+			Errors = append(Errors, &Error{GoError: fmt.Errorf("plugin %s: %s", p.name, err)})
+		}
+	}
+	return ran
+}
@@ -0,0 +1,79 @@
+package dslengine_test
+
+import (
+	"errors"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Functions called by the test plugin, nil functions are skipped.
+var mutate, inspect dslengine.PluginFunc
+
+func init() {
+	dslengine.RegisterPlugin("test", func(roots []dslengine.Root) error {
+		if mutate == nil {
+			return nil
+		}
+		return mutate(roots)
+	})
+	dslengine.RegisterInspector("test", func(roots []dslengine.Root) error {
+		if inspect == nil {
+			return nil
+		}
+		return inspect(roots)
+	})
+}
+
+var _ = Describe("Plugins", func() {
+	var consumesBefore, consumesAfter int
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("foo", func() {})
+		consumesBefore, consumesAfter = -1, -1
+		mutate = func(roots []dslengine.Root) error {
+			consumesBefore = len(Design.Consumes)
+			Design.Metadata = dslengine.MetadataDefinition{"plugin": {"test"}}
+			return nil
+		}
+		inspect = func(roots []dslengine.Root) error {
+			consumesAfter = len(Design.Consumes)
+			return nil
+		}
+	})
+
+	AfterEach(func() {
+		mutate, inspect = nil, nil
+	})
+
+	It("runs the plugins before and after finalization", func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		Ω(Design.Metadata).Should(HaveKeyWithValue("plugin", []string{"test"}))
+		Ω(consumesBefore).Should(Equal(0))
+		Ω(consumesAfter).ShouldNot(BeZero())
+	})
+
+	Context("with a plugin returning an error", func() {
+		BeforeEach(func() {
+			mutate = func(roots []dslengine.Root) error {
+				return errors.New("boom")
+			}
+		})
+
+		It("reports the error with the plugin name", func() {
+			Ω(dslengine.Run()).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(Equal("plugin test: boom"))
+			Ω(consumesAfter).Should(Equal(-1))
+		})
+	})
+
+	It("panics when registering a plugin twice", func() {
+		noop := func(roots []dslengine.Root) error { return nil }
+		Ω(func() { dslengine.RegisterPlugin("test", noop) }).Should(Panic())
+		Ω(func() { dslengine.RegisterInspector("test", noop) }).Should(Panic())
+	})
+})
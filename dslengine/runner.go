@@ -13,6 +13,13 @@ var (
 	// Errors contains the DSL execution errors if any.
 	Errors MultiError
 
+	// MaxErrors is the maximum number of DSL execution errors recorded in Errors, the errors
+	// reported past the limit are counted but not recorded. Zero means no limit.
+	MaxErrors = 100
+
+	// Number of DSL execution errors reported past MaxErrors
+	droppedErrors int
+
 	// Global DSL evaluation stack
 	ctxStack contextStack
 
@@ -31,6 +38,12 @@ type (
 		GoError error
 		File    string
 		Line    int
+		// DSL is the name of the DSL function that reported the error if known, e.g.
+		// "Attribute".
+		DSL string
+		// Stack lists the contexts of the definitions being evaluated when the error
+		// was reported, outermost first, e.g. [`resource "bottle"`, `action "show"`].
+		Stack []string
 	}
 
 	// MultiError collects all DSL errors. It implements error.
@@ -72,12 +85,15 @@ func Reset() {
 		r.Reset()
 	}
 	Errors = nil
+	Warnings = nil
+	droppedErrors = 0
 }
 
 // Run runs the given root definitions. It iterates over the definition sets
-// multiple times to first execute the DSL, the validate the resulting
+// multiple times to first execute the DSL, then prepare and validate the resulting
 // definitions and finally finalize them. The executed DSL may register new
-// roots to have them be executed (last) in the same run.
+// roots to have them be executed (last) in the same run. The registered
+// plugins run after validation and after finalization, see RegisterPlugin.
 func Run() error {
 	if len(roots) == 0 {
 		return nil
@@ -87,6 +103,7 @@ func Run() error {
 		return err
 	}
 	Errors = nil
+	droppedErrors = 0
 	executed := 0
 	recursed := 0
 	for executed < len(roots) {
@@ -104,15 +121,34 @@ func Run() error {
 	if Errors != nil {
 		return Errors
 	}
+	for _, root := range roots {
+		root.IterateSets(prepareSet)
+	}
 	for _, root := range roots {
 		root.IterateSets(validateSet)
 	}
 	if Errors != nil {
 		return Errors
 	}
+	if runPlugins(roots, true) {
+		if Errors != nil {
+			return Errors
+		}
+		// Make sure the changes made by the plugins are consistent
+		for _, root := range roots {
+			root.IterateSets(validateSet)
+		}
+		if Errors != nil {
+			return Errors
+		}
+	}
 	for _, root := range roots {
 		root.IterateSets(finalizeSet)
 	}
+	runPlugins(roots, false)
+	if Errors != nil {
+		return Errors
+	}
 
 	return nil
 }
@@ -127,11 +163,11 @@ func Execute(dsl func(), def Definition) bool {
 	if dsl == nil {
 		return true
 	}
-	initCount := len(Errors)
+	initCount := len(Errors) + droppedErrors
 	ctxStack = append(ctxStack, def)
 	dsl()
 	ctxStack = ctxStack[:len(ctxStack)-1]
-	return len(Errors) <= initCount
+	return len(Errors)+droppedErrors <= initCount
 }
 
 // CurrentDefinition returns the definition whose initialization DSL is currently being executed.
@@ -159,22 +195,29 @@ type TopLevelDefinition struct{}
 // errors.
 func (t *TopLevelDefinition) Context() string { return "top-level" }
 
-// ReportError records a DSL error for reporting post DSL execution.
+// ReportError records a DSL error for reporting post DSL execution. The DSL execution carries on
+// so that a single run reports all the errors, up to MaxErrors. The error message ends with the
+// stack of definitions being evaluated, e.g. `in resource "bottle" > action "show"`.
 func ReportError(fm string, vals ...interface{}) {
+	if MaxErrors > 0 && len(Errors) >= MaxErrors {
+		droppedErrors++
+		return
+	}
 	var suffix string
-	if cur := ctxStack.Current(); cur != nil {
-		if ctx := cur.Context(); ctx != "" {
-			suffix = fmt.Sprintf(" in %s", ctx)
-		}
-	} else {
+	stack := ctxStack.Contexts()
+	if len(stack) > 0 {
+		suffix = fmt.Sprintf(" in %s", strings.Join(stack, " > "))
+	} else if len(ctxStack) == 0 {
 		suffix = " (top level)"
 	}
 	err := fmt.Errorf(fm+suffix, vals...)
-	file, line := computeErrorLocation()
+	file, line, dsl := computeErrorLocation()
 	Errors = append(Errors, &Error{
 		GoError: err,
 		File:    file,
 		Line:    line,
+		DSL:     dsl,
+		Stack:   stack,
 	})
 }
 
@@ -216,11 +259,35 @@ func InvalidArgError(expected string, actual interface{}) {
 		actual, reflect.TypeOf(actual), expected)
 }
 
-// Error returns the error message.
+// Error returns the error messages grouped by design file, the files are listed in the order of
+// their first error. The errors with no location, e.g. validation errors, are listed last.
 func (m MultiError) Error() string {
-	msgs := make([]string, len(m))
-	for i, de := range m {
-		msgs[i] = de.Error()
+	var (
+		files  []string
+		byFile = make(map[string][]*Error)
+		msgs   []string
+	)
+	for _, de := range m {
+		if de.File == "" {
+			continue
+		}
+		if _, ok := byFile[de.File]; !ok {
+			files = append(files, de.File)
+		}
+		byFile[de.File] = append(byFile[de.File], de)
+	}
+	for _, f := range files {
+		for _, de := range byFile[f] {
+			msgs = append(msgs, de.Error())
+		}
+	}
+	for _, de := range m {
+		if de.File == "" {
+			msgs = append(msgs, de.Error())
+		}
+	}
+	if droppedErrors > 0 && len(m) > 0 {
+		msgs = append(msgs, fmt.Sprintf("too many errors, %d more not reported", droppedErrors))
 	}
 	return strings.Join(msgs, "\n")
 }
@@ -228,10 +295,14 @@ func (m MultiError) Error() string {
 // Error returns the underlying error message.
 func (de *Error) Error() string {
 	if err := de.GoError; err != nil {
+		msg := err.Error()
+		if de.DSL != "" {
+			msg = de.DSL + ": " + msg
+		}
 		if de.File == "" {
-			return err.Error()
+			return msg
 		}
-		return fmt.Sprintf("[%s:%d] %s", de.File, de.Line, err.Error())
+		return fmt.Sprintf("[%s:%d] %s", de.File, de.Line, msg)
 	}
 	return ""
 }
@@ -244,29 +315,58 @@ func (s contextStack) Current() Definition {
 	return s[len(s)-1]
 }
 
+// Contexts returns the non-empty contexts of the stack definitions, outermost first. The
+// contexts that mention their parent context, e.g. `resource "bar" action "baz"`, are
+// shortened so that each element only describes its own definition.
+func (s contextStack) Contexts() []string {
+	var ctxs []string
+	var parent string
+	for _, def := range s {
+		ctx := def.Context()
+		if ctx == "" {
+			continue
+		}
+		full := ctx
+		if parent != "" {
+			ctx = strings.TrimPrefix(ctx, parent+" ")
+			ctx = strings.TrimSuffix(ctx, " of "+parent)
+		}
+		parent = full
+		ctxs = append(ctxs, ctx)
+	}
+	return ctxs
+}
+
 // computeErrorLocation implements a heuristic to find the location in the user
-// code where the error occurred. It walks back the callstack until the file
-// doesn't match "/goa/design/*.go" or one of the DSL package paths.
-// When successful it returns the file name and line number, empty string and
-// 0 otherwise.
-func computeErrorLocation() (file string, line int) {
-	skipFunc := func(file string) bool {
+// code where the error occurred. It walks back the callstack until neither the
+// file nor the function match one of the DSL package paths, matching the
+// function makes it possible to find the location when the sources are not in
+// a GOPATH. When successful it returns the file name and line number, empty
+// string and 0 otherwise. The last value is the name of the DSL function called
+// by the user code if any.
+func computeErrorLocation() (file string, line int, dsl string) {
+	skipFunc := func(pc uintptr, file string) bool {
 		if strings.HasSuffix(file, "_test.go") { // Be nice with tests
 			return false
 		}
 		file = filepath.ToSlash(file)
+		var name string
+		if f := runtime.FuncForPC(pc); f != nil {
+			name = f.Name()
+		}
 		for pkg := range dslPackages {
-			if strings.Contains(file, pkg) {
+			if strings.Contains(file, pkg) || strings.HasPrefix(name, pkg) {
 				return true
 			}
 		}
 		return false
 	}
 	depth := 2
-	_, file, line, _ = runtime.Caller(depth)
-	for skipFunc(file) {
+	pc, file, line, _ := runtime.Caller(depth)
+	for skipFunc(pc, file) {
+		dsl = funcName(pc)
 		depth++
-		_, file, line, _ = runtime.Caller(depth)
+		pc, file, line, _ = runtime.Caller(depth)
 	}
 	wd, err := os.Getwd()
 	if err != nil {
@@ -306,6 +406,16 @@ func runSet(set DefinitionSet) error {
 	return nil
 }
 
+// prepareSet runs the preparation on all the set definitions that define one.
+func prepareSet(set DefinitionSet) error {
+	for _, def := range set {
+		if prepare, ok := def.(Prepare); ok {
+			prepare.Prepare()
+		}
+	}
+	return nil
+}
+
 // validateSet runs the validation on all the set definitions that define one.
 func validateSet(set DefinitionSet) error {
 	errors := &ValidationErrors{}
@@ -415,6 +525,28 @@ func sortDependenciesR(root Root, seen map[string]bool, sorted *[]Root, depFunc
 	*sorted = append(*sorted, root)
 }
 
+// funcName returns the name of the function containing pc without the package path and the
+// suffixes of anonymous functions, e.g. "Action" for "github.com/goadesign/goa/design/apidsl.Action.func1".
+func funcName(pc uintptr) string {
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return ""
+	}
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	elems := strings.Split(name, ".")
+	if len(elems) < 2 {
+		return name
+	}
+	if strings.HasPrefix(elems[1], "(") && len(elems) > 2 {
+		// Method, e.g. "design.(*APIDefinition).Example"
+		return elems[2]
+	}
+	return elems[1]
+}
+
 // caller returns the name of calling function.
 func caller() string {
 	pc, file, _, ok := runtime.Caller(2)
@@ -5,6 +5,15 @@ import (
 	"strings"
 )
 
+var (
+	// Strict causes the warnings recorded with ValidationErrors.Warn to be reported as
+	// validation errors.
+	Strict bool
+
+	// Warnings lists the warnings recorded since the last Reset, see ValidationErrors.Warn.
+	Warnings []string
+)
+
 // ValidationErrors records the errors encountered when running Validate.
 type ValidationErrors struct {
 	Errors      []error
@@ -47,6 +56,24 @@ func (verr *ValidationErrors) AddError(def Definition, err error) {
 	verr.Definitions = append(verr.Definitions, def)
 }
 
+// Warn records a warning about a definition that is valid but most likely incorrect. The warning
+// is added to the target as a validation error if Strict is true, it is appended to Warnings
+// otherwise.
+func (verr *ValidationErrors) Warn(def Definition, format string, vals ...interface{}) {
+	if Strict {
+		verr.Add(def, format, vals...)
+		return
+	}
+	w := fmt.Sprintf("%s: %s", def.Context(), fmt.Sprintf(format, vals...))
+	for _, existing := range Warnings {
+		if existing == w {
+			// Definitions may be validated more than once.
+			return
+		}
+	}
+	Warnings = append(Warnings, w)
+}
+
 // AsError returns an error if there are validation errors, nil otherwise.
 func (verr *ValidationErrors) AsError() *ValidationErrors {
 	if len(verr.Errors) > 0 {
@@ -30,10 +30,13 @@ middleware) also generate an internal error response.
 package goa
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"io"
+	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -58,6 +61,14 @@ var (
 	// MaxRequestBodyLength bytes.
 	ErrRequestBodyTooLarge = NewErrorClass("request_too_large", 413)
 
+	// ErrUnsupportedMediaType is the error produced when the content type of a request body is
+	// not one of the content types accepted by the action.
+	ErrUnsupportedMediaType = NewErrorClass("unsupported_media_type", 415)
+
+	// ErrNotAcceptable is the error produced when the request Accept header accepts none of
+	// the media types produced by the action.
+	ErrNotAcceptable = NewErrorClass("not_acceptable", 406)
+
 	// ErrNoAuthMiddleware is the error produced when no auth middleware is mounted for a
 	// security scheme defined in the design.
 	ErrNoAuthMiddleware = NewErrorClass("no_auth_middleware", 500)
@@ -120,8 +131,34 @@ type (
 		Status int `json:"status" yaml:"status" xml:"status" form:"status"`
 		// Detail describes the specific error occurrence.
 		Detail string `json:"detail" yaml:"detail" xml:"detail" form:"detail"`
+		// MessageKey identifies the detail message independently of its parameters, the
+		// parameters are the Meta values. See MessageCatalog.
+		MessageKey string `json:"message_key,omitempty" yaml:"message_key,omitempty" xml:"message_key,omitempty" form:"message_key,omitempty"`
 		// Meta contains additional key/value pairs useful to clients.
 		Meta map[string]interface{} `json:"meta,omitempty" yaml:"meta,omitempty" xml:"meta,omitempty" form:"meta,omitempty"`
+
+		// response describes the response the error was decoded from by a client.
+		response *errorHTTPResponse
+		// merged lists the errors merged into the error by MergeErrors in order.
+		merged []*ErrorResponse
+	}
+
+	// Violation describes a value of a request body that does not satisfy the validations
+	// defined in the design.
+	Violation struct {
+		// Pointer is the JSON Pointer (RFC 6901) to the invalid value relative to the
+		// validated value, e.g. "/origin/country". Array elements and hash values are
+		// identified with "*".
+		Pointer string `json:"pointer" yaml:"pointer" xml:"pointer" form:"pointer"`
+		// Detail describes the violation.
+		Detail string `json:"detail" yaml:"detail" xml:"detail" form:"detail"`
+	}
+
+	// errorHTTPResponse records the HTTP response an ErrorResponse was decoded from.
+	errorHTTPResponse struct {
+		status int
+		header http.Header
+		body   []byte
 	}
 )
 
@@ -159,40 +196,47 @@ func NewErrorClass(code string, status int) ErrorClass {
 
 // MissingPayloadError is the error produced when a request is missing a required payload.
 func MissingPayloadError() error {
-	return ErrInvalidRequest("missing required payload")
+	return withMessageKey(ErrInvalidRequest("missing required payload"), "missing_payload")
 }
 
 // InvalidParamTypeError is the error produced when the type of a parameter does not match the type
 // defined in the design.
 func InvalidParamTypeError(name string, val interface{}, expected string) error {
 	msg := fmt.Sprintf("invalid value %#v for parameter %#v, must be a %s", val, name, expected)
-	return ErrInvalidRequest(msg, "param", name, "value", val, "expected", expected)
+	return withMessageKey(ErrInvalidRequest(msg, "param", name, "value", val, "expected", expected), "invalid_param_type")
 }
 
 // MissingParamError is the error produced for requests that are missing path or querystring
 // parameters.
 func MissingParamError(name string) error {
 	msg := fmt.Sprintf("missing required parameter %#v", name)
-	return ErrInvalidRequest(msg, "name", name)
+	return withMessageKey(ErrInvalidRequest(msg, "name", name), "missing_param")
 }
 
 // InvalidAttributeTypeError is the error produced when the type of payload field does not match
 // the type defined in the design.
 func InvalidAttributeTypeError(ctx string, val interface{}, expected string) error {
 	msg := fmt.Sprintf("type of %s must be %s but got value %#v", ctx, expected, val)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", val, "expected", expected)
+	return withMessageKey(ErrInvalidRequest(msg, "attribute", ctx, "value", val, "expected", expected), "invalid_attribute_type")
 }
 
 // MissingAttributeError is the error produced when a request payload is missing a required field.
 func MissingAttributeError(ctx, name string) error {
 	msg := fmt.Sprintf("attribute %#v of %s is missing and required", name, ctx)
-	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx)
+	return withMessageKey(ErrInvalidRequest(msg, "attribute", name, "parent", ctx), "missing_attribute")
+}
+
+// IncompleteGroupError is the error produced when a request sets some but not all of the
+// parameters or fields of a group that must be set together (see the RequiredTogether DSL).
+func IncompleteGroupError(ctx, present, missing string) error {
+	msg := fmt.Sprintf("%#v is missing in %s, it is required when %#v is set", missing, ctx, present)
+	return withMessageKey(ErrInvalidRequest(msg, "missing", missing, "present", present, "parent", ctx), "incomplete_group")
 }
 
 // MissingHeaderError is the error produced when a request is missing a required header.
 func MissingHeaderError(name string) error {
 	msg := fmt.Sprintf("missing required HTTP header %#v", name)
-	return ErrInvalidRequest(msg, "name", name)
+	return withMessageKey(ErrInvalidRequest(msg, "name", name), "missing_header")
 }
 
 // InvalidEnumValueError is the error produced when the value of a parameter or payload field does
@@ -203,50 +247,53 @@ func InvalidEnumValueError(ctx string, val interface{}, allowed []interface{}) e
 		elems[i] = fmt.Sprintf("%#v", a)
 	}
 	msg := fmt.Sprintf("value of %s must be one of %s but got value %#v", ctx, strings.Join(elems, ", "), val)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", val, "expected", strings.Join(elems, ", "))
+	err := ErrInvalidRequest(msg, "attribute", ctx, "value", val, "expected", strings.Join(elems, ", "))
+	return withMessageKey(err, "invalid_enum_value")
 }
 
 // InvalidFormatError is the error produced when the value of a parameter or payload field does not
 // match the format validation defined in the design.
 func InvalidFormatError(ctx, target string, format Format, formatError error) error {
 	msg := fmt.Sprintf("%s must be formatted as a %s but got value %#v, %s", ctx, format, target, formatError.Error())
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "expected", format, "error", formatError.Error())
+	err := ErrInvalidRequest(msg, "attribute", ctx, "value", target, "expected", format, "error", formatError.Error())
+	return withMessageKey(err, "invalid_format")
 }
 
 // InvalidPatternError is the error produced when the value of a parameter or payload field does
 // not match the pattern validation defined in the design.
 func InvalidPatternError(ctx, target string, pattern string) error {
 	msg := fmt.Sprintf("%s must match the regexp %#v but got value %#v", ctx, pattern, target)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "regexp", pattern)
+	return withMessageKey(ErrInvalidRequest(msg, "attribute", ctx, "value", target, "regexp", pattern), "invalid_pattern")
 }
 
 // InvalidRangeError is the error produced when the value of a parameter or payload field does
 // not match the range validation defined in the design. value may be a int or a float64.
 func InvalidRangeError(ctx string, target interface{}, value interface{}, min bool) error {
-	comp := "greater than or equal to"
+	comp, key := "greater than or equal to", "invalid_range_min"
 	if !min {
-		comp = "less than or equal to"
+		comp, key = "less than or equal to", "invalid_range_max"
 	}
 	msg := fmt.Sprintf("%s must be %s %v but got value %#v", ctx, comp, value, target)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "comp", comp, "expected", value)
+	return withMessageKey(ErrInvalidRequest(msg, "attribute", ctx, "value", target, "comp", comp, "expected", value), key)
 }
 
 // InvalidLengthError is the error produced when the value of a parameter or payload field does
 // not match the length validation defined in the design.
 func InvalidLengthError(ctx string, target interface{}, ln, value int, min bool) error {
-	comp := "greater than or equal to"
+	comp, key := "greater than or equal to", "invalid_length_min"
 	if !min {
-		comp = "less than or equal to"
+		comp, key = "less than or equal to", "invalid_length_max"
 	}
 	msg := fmt.Sprintf("length of %s must be %s %d but got value %#v (len=%d)", ctx, comp, value, target, ln)
-	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "len", ln, "comp", comp, "expected", value)
+	err := ErrInvalidRequest(msg, "attribute", ctx, "value", target, "len", ln, "comp", comp, "expected", value)
+	return withMessageKey(err, key)
 }
 
 // NoAuthMiddleware is the error produced when goa is unable to lookup a auth middleware for a
 // security scheme defined in the design.
 func NoAuthMiddleware(schemeName string) error {
 	msg := fmt.Sprintf("Auth middleware for security scheme %s is not mounted", schemeName)
-	return ErrNoAuthMiddleware(msg, "scheme", schemeName)
+	return withMessageKey(ErrNoAuthMiddleware(msg, "scheme", schemeName), "no_auth_middleware")
 }
 
 // MethodNotAllowedError is the error produced to requests that match the path of a registered
@@ -257,7 +304,14 @@ func MethodNotAllowedError(method string, allowed []string) error {
 		plural = " one of"
 	}
 	msg := fmt.Sprintf("Method %s must be%s %s", method, plural, strings.Join(allowed, ", "))
-	return ErrMethodNotAllowed(msg, "method", method, "allowed", strings.Join(allowed, ", "))
+	err := ErrMethodNotAllowed(msg, "method", method, "allowed", strings.Join(allowed, ", "))
+	return withMessageKey(err, "method_not_allowed")
+}
+
+// withMessageKey sets the message key of err which must be a *ErrorResponse and returns it.
+func withMessageKey(err error, key string) error {
+	err.(*ErrorResponse).MessageKey = key
+	return err
 }
 
 // Error returns the error occurrence details.
@@ -275,6 +329,38 @@ func (e *ErrorResponse) ResponseStatus() int { return e.Status }
 // Token is the unique error occurrence identifier.
 func (e *ErrorResponse) Token() string { return e.ID }
 
+// SetResponse records the HTTP response the error was decoded from. Clients call SetResponse
+// after decoding so that StatusCode, Header and Body describe the actual response.
+func (e *ErrorResponse) SetResponse(status int, header http.Header, body []byte) {
+	e.response = &errorHTTPResponse{status: status, header: header, body: body}
+}
+
+// StatusCode returns the status code of the response the error was decoded from, Status if the
+// error was not decoded from a response.
+func (e *ErrorResponse) StatusCode() int {
+	if e.response == nil {
+		return e.Status
+	}
+	return e.response.status
+}
+
+// Header returns the headers of the response the error was decoded from if any.
+func (e *ErrorResponse) Header() http.Header {
+	if e.response == nil {
+		return nil
+	}
+	return e.response.header
+}
+
+// Body returns the raw body of the response the error was decoded from if any. Clients may
+// truncate the body.
+func (e *ErrorResponse) Body() []byte {
+	if e.response == nil {
+		return nil
+	}
+	return e.response.body
+}
+
 // MergeErrors updates an error by merging another into it. It first converts other into a
 // ServiceError if not already one - producing an internal error in that case. The merge algorithm
 // is:
@@ -312,6 +398,7 @@ func MergeErrors(err, other error) error {
 
 	e := asErrorResponse(err)
 	o := asErrorResponse(other)
+	merged := append(e.mergedErrors(), o.mergedErrors()...)
 	switch {
 	case e.Status == 500 || o.Status == 500:
 		if e.Status != 500 {
@@ -323,6 +410,9 @@ func MergeErrors(err, other error) error {
 		e.Code = "bad_request"
 	}
 	e.Detail = e.Detail + "; " + o.Detail
+	if e.MessageKey != o.MessageKey {
+		e.MessageKey = ""
+	}
 
 	if e.Meta == nil && len(o.Meta) > 0 {
 		e.Meta = make(map[string]interface{})
@@ -330,9 +420,95 @@ func MergeErrors(err, other error) error {
 	for k, v := range o.Meta {
 		e.Meta[k] = v
 	}
+	e.merged = merged
 	return e
 }
 
+// mergedErrors returns the errors merged into e or a copy of e if e is not the result of a merge.
+func (e *ErrorResponse) mergedErrors() []*ErrorResponse {
+	if len(e.merged) > 0 {
+		return append([]*ErrorResponse(nil), e.merged...)
+	}
+	c := *e
+	if e.Meta != nil {
+		c.Meta = make(map[string]interface{}, len(e.Meta))
+		for k, v := range e.Meta {
+			c.Meta[k] = v
+		}
+	}
+	return []*ErrorResponse{&c}
+}
+
+// Violations returns the violations described by the invalid request errors contained in err.
+// err is typically returned by the Validate method of a generated payload type, the generated
+// code merges the errors of each invalid attribute into a single error.
+func Violations(err error) []*Violation {
+	e, ok := err.(*ErrorResponse)
+	if !ok {
+		return nil
+	}
+	var violations []*Violation
+	for _, m := range e.mergedErrors() {
+		if m.Code != "invalid_request" {
+			continue
+		}
+		att, ok := m.Meta["attribute"].(string)
+		if !ok {
+			continue
+		}
+		pointer := attributePointer(att)
+		if parent, ok := m.Meta["parent"].(string); ok {
+			// Missing attribute, the attribute is the name of the field.
+			pointer = attributePointer(parent) + "/" + escapePointerToken(att)
+		}
+		violations = append(violations, &Violation{Pointer: pointer, Detail: m.Detail})
+	}
+	return violations
+}
+
+// attributePointer converts the context of an attribute used in validation errors into a JSON
+// Pointer, e.g. "raw.origin.country" into "/origin/country". The first element of the context
+// identifies the validated value and is omitted.
+func attributePointer(ctx string) string {
+	i := strings.IndexAny(ctx, ".[")
+	if i == -1 {
+		return ""
+	}
+	ctx = ctx[i:]
+	var pointer bytes.Buffer
+	for len(ctx) > 0 {
+		var token string
+		switch ctx[0] {
+		case '.':
+			end := strings.IndexAny(ctx[1:], ".[")
+			if end == -1 {
+				end = len(ctx) - 1
+			}
+			token, ctx = ctx[1:end+1], ctx[end+1:]
+		case '[':
+			end := strings.Index(ctx, "]")
+			if end == -1 {
+				end = len(ctx)
+				ctx += "]"
+			}
+			token, ctx = ctx[1:end], ctx[end+1:]
+			if unquoted, err := strconv.Unquote(token); err == nil {
+				token = unquoted
+			}
+		default:
+			return pointer.String()
+		}
+		pointer.WriteString("/" + escapePointerToken(token))
+	}
+	return pointer.String()
+}
+
+// escapePointerToken escapes the characters of token that have a special meaning in JSON
+// Pointers.
+func escapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
 func asServiceError(err error) ServiceError {
 	e, ok := err.(ServiceError)
 	if !ok {
@@ -601,3 +601,56 @@ var _ = Describe("Merge", func() {
 	})
 
 })
+
+var _ = Describe("Violations", func() {
+	var err error
+	var violations []*Violation
+
+	JustBeforeEach(func() {
+		violations = Violations(err)
+	})
+
+	Context("with a single invalid attribute error", func() {
+		BeforeEach(func() {
+			err = InvalidRangeError(`raw.origin.count`, 0, 1, true)
+		})
+
+		It("returns the attribute violation", func() {
+			Ω(violations).Should(HaveLen(1))
+			Ω(violations[0].Pointer).Should(Equal("/origin/count"))
+			Ω(violations[0].Detail).Should(Equal(err.(*ErrorResponse).Detail))
+		})
+	})
+
+	Context("with merged errors", func() {
+		BeforeEach(func() {
+			err = MergeErrors(nil, MissingAttributeError(`raw`, "name"))
+			err = MergeErrors(err, InvalidEnumValueError(`raw.origin.country`, "ZZ", []interface{}{"FR", "US"}))
+			err = MergeErrors(err, InvalidLengthError(`raw.tags[*]`, "", 0, 1, true))
+			err = MergeErrors(err, MissingAttributeError(`raw.labels["a/b"]`, "value"))
+		})
+
+		It("returns a violation per error", func() {
+			Ω(violations).Should(HaveLen(4))
+			Ω(violations[0].Pointer).Should(Equal("/name"))
+			Ω(violations[1].Pointer).Should(Equal("/origin/country"))
+			Ω(violations[2].Pointer).Should(Equal("/tags/*"))
+			Ω(violations[3].Pointer).Should(Equal("/labels/a~1b/value"))
+		})
+
+		It("keeps the details of each error", func() {
+			Ω(violations[0].Detail).Should(ContainSubstring(`"name"`))
+			Ω(violations[1].Detail).Should(ContainSubstring("raw.origin.country"))
+		})
+	})
+
+	Context("with an error that is not an invalid request error", func() {
+		BeforeEach(func() {
+			err = ErrNotFound("not found")
+		})
+
+		It("returns no violation", func() {
+			Ω(violations).Should(BeEmpty())
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"text/template"
+	"time"
 
 	"github.com/goadesign/goa/design"
 )
@@ -70,6 +71,7 @@ func (f *Finalizer) recurse(root, att *design.AttributeDefinition, target string
 					"catt":       catt,
 					"depth":      depth,
 					"isDatetime": catt.Type == design.DateTime,
+					"timeType":   GoTimeType(catt),
 					"defaultVal": PrintVal(catt.Type, catt.DefaultValue),
 				}
 				if !first {
@@ -123,6 +125,9 @@ func PrintVal(t design.DataType, val interface{}) string {
 			s = fmt.Sprintf("%f", v)
 		case design.DateTime:
 			s = fmt.Sprintf("time.Parse(time.RFC3339, %s)", s)
+		case design.Duration:
+			d, _ := time.ParseDuration(val.(string))
+			s = DurationCode(d)
 		}
 		return s
 	case t.IsHash():
@@ -161,11 +166,31 @@ func PrintVal(t design.DataType, val interface{}) string {
 	}
 }
 
+// DurationCode returns the Go code for the given duration using the largest unit that divides it.
+func DurationCode(d time.Duration) string {
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.d == 0 {
+			return fmt.Sprintf("%d * %s", d/u.d, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
+
 const (
 	assignmentTmpl = `{{ if .catt.Type.IsPrimitive }}{{ $defaultName := (print "default" (goify .field true)) }}{{/*
 */}}{{ tabs .depth }}var {{ $defaultName }}{{if .isDatetime}}, _{{end}} = {{ .defaultVal }}
 {{ tabs .depth }}if {{ .target }}.{{ goify .field true }} == nil {
-{{ tabs .depth }}	{{ .target }}.{{ goify .field true }} = &{{ $defaultName }}
+{{ tabs .depth }}	{{ .target }}.{{ goify .field true }} = &{{ with .timeType }}{{ . }}{ {{ $defaultName }} }{{ else }}{{ $defaultName }}{{ end }}
 }{{ else }}{{ tabs .depth }}if {{ .target }}.{{ goify .field true }} == nil {
 {{ tabs .depth }}	{{ .target }}.{{ goify .field true }} = {{ .defaultVal }}
 }{{ end }}`
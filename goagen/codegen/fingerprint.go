@@ -0,0 +1,303 @@
+package codegen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Fingerprints records the content hash and modification time of files indexed by absolute
+	// path. Fingerprints taken prior to running a generator make it possible to detect which
+	// generated files actually changed.
+	Fingerprints map[string]*Fingerprint
+
+	// Fingerprint is the content hash and modification time of a single file.
+	Fingerprint struct {
+		// Hash is the hex encoded SHA-256 hash of the file content.
+		Hash string
+		// ModTime is the file modification time.
+		ModTime time.Time
+	}
+
+	// GenerationSummary lists the files written, skipped and deleted by a generator run.
+	GenerationSummary struct {
+		// Written lists the files that were created or whose content changed.
+		Written []string
+		// Skipped lists the files whose content did not change.
+		Skipped []string
+		// Deleted lists the files that existed prior to the run and were removed by it.
+		Deleted []string
+	}
+)
+
+// SkipUnchanged causes WriteFile and SourceFile to leave the files whose content did not change
+// untouched instead of writing them again. goagen sets it when running with --incremental so that
+// build caches and editors do not see a change.
+var SkipUnchanged bool
+
+// generated records the directories prepared with CleanDir and the files generated since when
+// SkipUnchanged is set.
+var generated = struct {
+	sync.Mutex
+	dirs  map[string]bool
+	files map[string]bool
+}{dirs: make(map[string]bool), files: make(map[string]bool)}
+
+// CleanDir prepares the generator output directory dir. CleanDir deletes dir and its content
+// unless SkipUnchanged is set in which case the existing files are kept so that the ones whose
+// content does not change are not written again. RemoveStaleFiles deletes the files that the
+// generator did not write.
+func CleanDir(dir string) error {
+	if !SkipUnchanged {
+		return os.RemoveAll(dir)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	generated.Lock()
+	defer generated.Unlock()
+	generated.dirs[abs] = true
+	for f := range generated.files {
+		if isUnder(f, abs) {
+			delete(generated.files, f)
+		}
+	}
+	return nil
+}
+
+// RemoveStaleFiles deletes the files that live in the directories prepared with CleanDir but that
+// were not generated again. It returns the paths of the deleted files.
+func RemoveStaleFiles() ([]string, error) {
+	generated.Lock()
+	defer generated.Unlock()
+	var deleted []string
+	for dir := range generated.dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !info.Mode().IsRegular() || generated.files[path] {
+				return nil
+			}
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			deleted = append(deleted, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Remove the directories left empty, deepest first.
+	sort.Sort(sort.Reverse(sort.StringSlice(deleted)))
+	for _, f := range deleted {
+		for d := filepath.Dir(f); !generated.dirs[d] && isUnderAny(d, generated.dirs); d = filepath.Dir(d) {
+			if os.Remove(d) != nil {
+				break
+			}
+		}
+	}
+	generated.dirs = make(map[string]bool)
+	generated.files = make(map[string]bool)
+	sort.Strings(deleted)
+	return deleted, nil
+}
+
+// claimFile records that the file at path is generated by the current run. It returns true if
+// the file lives in a directory prepared with CleanDir and was not generated before in which case
+// its existing content must be discarded.
+func claimFile(path string) bool {
+	generated.Lock()
+	defer generated.Unlock()
+	if generated.files[path] {
+		return false
+	}
+	generated.files[path] = true
+	return isUnderAny(path, generated.dirs)
+}
+
+// isUnder returns true if path is dir or lives under dir.
+func isUnder(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// isUnderAny returns true if path lives under any of the given directories.
+func isUnderAny(path string, dirs map[string]bool) bool {
+	for d := range dirs {
+		if isUnder(path, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// TakeFingerprints walks the directory tree rooted at root and computes the fingerprint of each
+// regular file. Hidden directories and vendor directories are skipped.
+func TakeFingerprints(root string) (Fingerprints, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	fps := make(Fingerprints)
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		hash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		fps[path] = &Fingerprint{Hash: hash, ModTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fps, nil
+}
+
+// Apply compares the fingerprints of the given generated paths with the fingerprints taken prior
+// to running the generator. Generated files whose fingerprint did not change are reported as
+// skipped, the generators do not write them when SkipUnchanged is set. Paths may refer to
+// directories in which case all the files they contain are considered generated. Fingerprinted
+// files that used to live under a generated path but no longer exist are reported as deleted.
+func (fps Fingerprints) Apply(paths []string) (*GenerationSummary, error) {
+	var files, roots []string
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		roots = append(roots, abs)
+		if !info.IsDir() {
+			files = append(files, abs)
+			continue
+		}
+		err = filepath.Walk(abs, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	summary := &GenerationSummary{}
+	seen := make(map[string]bool)
+	for _, f := range files {
+		if seen[f] {
+			continue
+		}
+		seen[f] = true
+		old, ok := fps[f]
+		if !ok {
+			summary.Written = append(summary.Written, f)
+			continue
+		}
+		hash, err := hashFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if hash != old.Hash {
+			summary.Written = append(summary.Written, f)
+			continue
+		}
+		summary.Skipped = append(summary.Skipped, f)
+	}
+	for f := range fps {
+		if seen[f] {
+			continue
+		}
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			continue
+		}
+		for _, r := range roots {
+			if isUnder(f, r) {
+				summary.Deleted = append(summary.Deleted, f)
+				break
+			}
+		}
+	}
+	sort.Strings(summary.Written)
+	sort.Strings(summary.Skipped)
+	sort.Strings(summary.Deleted)
+	return summary, nil
+}
+
+// String returns a one line description of the summary.
+func (s *GenerationSummary) String() string {
+	return fmt.Sprintf("%d written, %d skipped, %d deleted", len(s.Written), len(s.Skipped), len(s.Deleted))
+}
+
+// WriteFile writes content to the file at the given path, creating it if necessary. WriteFile
+// does not write the file if SkipUnchanged is set and the fingerprint of its current content
+// matches the fingerprint of content.
+func WriteFile(path string, content []byte) error {
+	if SkipUnchanged {
+		if abs, err := filepath.Abs(path); err == nil {
+			claimFile(abs)
+		}
+		if hash, err := hashFile(path); err == nil && hash == hashContent(content) {
+			return nil
+		}
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// hashContent returns the hex encoded SHA-256 hash of content.
+func hashContent(content []byte) string {
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:])
+}
+
+// hashFile returns the hex encoded SHA-256 hash of the content of the file at the given path.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
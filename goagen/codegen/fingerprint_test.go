@@ -0,0 +1,112 @@
+package codegen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fingerprints", func() {
+	var dir, unchanged, changed, removed, added string
+	var past time.Time
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "fingerprint")
+		Ω(err).ShouldNot(HaveOccurred())
+		unchanged = filepath.Join(dir, "unchanged.go")
+		changed = filepath.Join(dir, "changed.go")
+		removed = filepath.Join(dir, "removed.go")
+		added = filepath.Join(dir, "added.go")
+		past = time.Now().Add(-time.Hour).Truncate(time.Second)
+		for _, f := range []string{unchanged, changed, removed} {
+			Ω(ioutil.WriteFile(f, []byte("package foo\n"), 0644)).Should(Succeed())
+			Ω(os.Chtimes(f, past, past)).Should(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		codegen.SkipUnchanged = false
+		os.RemoveAll(dir)
+	})
+
+	It("computes the summary of a generation run", func() {
+		fps, err := codegen.TakeFingerprints(dir)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(fps).Should(HaveLen(3))
+
+		codegen.SkipUnchanged = true
+		Ω(codegen.WriteFile(unchanged, []byte("package foo\n"))).Should(Succeed())
+		Ω(codegen.WriteFile(changed, []byte("package bar\n"))).Should(Succeed())
+		Ω(codegen.WriteFile(added, []byte("package foo\n"))).Should(Succeed())
+		Ω(os.Remove(removed)).Should(Succeed())
+
+		summary, err := fps.Apply([]string{dir})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(summary.Written).Should(Equal([]string{added, changed}))
+		Ω(summary.Skipped).Should(Equal([]string{unchanged}))
+		Ω(summary.Deleted).Should(Equal([]string{removed}))
+		Ω(summary.String()).Should(Equal("2 written, 1 skipped, 1 deleted"))
+
+		info, err := os.Stat(unchanged)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(info.ModTime().Equal(past)).Should(BeTrue())
+		info, err = os.Stat(changed)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(info.ModTime().Equal(past)).Should(BeFalse())
+	})
+
+	It("does not write source files whose content did not change", func() {
+		p := &codegen.Package{Workspace: &codegen.Workspace{Path: dir}, Path: "foo"}
+		source := filepath.Join(p.Abs(), "foo.go")
+		Ω(os.MkdirAll(p.Abs(), 0755)).Should(Succeed())
+		Ω(ioutil.WriteFile(source, []byte("package foo\n"), 0644)).Should(Succeed())
+		Ω(os.Chtimes(source, past, past)).Should(Succeed())
+
+		codegen.SkipUnchanged = true
+		file, err := p.CreateSourceFile("foo.go")
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = file.Write([]byte("package   foo\n"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(file.FormatCode()).Should(Succeed())
+		file.Close()
+
+		content, err := ioutil.ReadFile(source)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(Equal("package foo\n"))
+		info, err := os.Stat(source)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(info.ModTime().Equal(past)).Should(BeTrue())
+	})
+
+	It("deletes the stale files of the directories it cleans", func() {
+		stale := filepath.Join(dir, "sub", "stale.go")
+		Ω(os.MkdirAll(filepath.Dir(stale), 0755)).Should(Succeed())
+		Ω(ioutil.WriteFile(stale, []byte("package sub\n"), 0644)).Should(Succeed())
+
+		codegen.SkipUnchanged = true
+		Ω(codegen.CleanDir(dir)).Should(Succeed())
+		Ω(codegen.WriteFile(unchanged, []byte("package foo\n"))).Should(Succeed())
+		Ω(codegen.WriteFile(changed, []byte("package bar\n"))).Should(Succeed())
+		deleted, err := codegen.RemoveStaleFiles()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(deleted).Should(Equal([]string{removed, stale}))
+		_, err = os.Stat(filepath.Dir(stale))
+		Ω(os.IsNotExist(err)).Should(BeTrue())
+		info, err := os.Stat(unchanged)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(info.ModTime().Equal(past)).Should(BeTrue())
+	})
+
+	It("removes the directories it cleans when SkipUnchanged is not set", func() {
+		Ω(codegen.CleanDir(dir)).Should(Succeed())
+		_, err := os.Stat(dir)
+		Ω(os.IsNotExist(err)).Should(BeTrue())
+	})
+})
@@ -130,6 +130,25 @@ func CanonicalParams(r *design.ResourceDefinition) []string {
 	return params
 }
 
+// LinkTemplate returns the URI template of the action targeted by the link as a format string
+// suitable for use in the fmt.Printf function family.
+func LinkTemplate(l *design.ActionLinkDefinition) string {
+	return design.WildcardRegex.ReplaceAllLiteralString(l.URITemplate(), "/%v")
+}
+
+// LinkParams returns the list of parameter names needed to build the href of the link. It returns
+// nil if the link does not target an action with a route.
+func LinkParams(l *design.ActionLinkDefinition) []string {
+	var params []string
+	if t := l.Target(); t != nil && len(t.Routes) > 0 {
+		params = t.Routes[0].Params()
+		for i, p := range params {
+			params[i] = Goify(p, false)
+		}
+	}
+	return params
+}
+
 // Casing exceptions
 var toLower = map[string]string{"OAuth": "oauth"}
 
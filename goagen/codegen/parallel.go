@@ -0,0 +1,52 @@
+package codegen
+
+import "sync"
+
+// Jobs is the maximum number of goroutines used by generators that produce independent files
+// concurrently, for example one controller file per resource. Values lower than 2 cause the
+// generators to run sequentially.
+var Jobs = 1
+
+// Parallel calls fn for each index in [0, n) using at most jobs concurrent goroutines and waits
+// for all the calls to complete. fn must only write to state owned by the index it is given so
+// that the result does not depend on scheduling. Parallel returns the error returned by the call
+// with the lowest index if any, the same error a sequential loop stopping at the first failure
+// would return.
+func Parallel(jobs, n int, fn func(i int) error) error {
+	if jobs < 2 || n < 2 {
+		for i := 0; i < n; i++ {
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if jobs > n {
+		jobs = n
+	}
+	var (
+		errs = make([]error, n)
+		idx  = make(chan int)
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
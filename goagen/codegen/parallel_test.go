@@ -0,0 +1,33 @@
+package codegen_test
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parallel", func() {
+	It("calls the function for each index", func() {
+		res := make([]int, 100)
+		err := codegen.Parallel(4, len(res), func(i int) error {
+			res[i] = i * 2
+			return nil
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+		for i, r := range res {
+			Ω(r).Should(Equal(i * 2))
+		}
+	})
+
+	It("returns the error with the lowest index", func() {
+		err := codegen.Parallel(4, 10, func(i int) error {
+			if i == 3 || i == 7 {
+				return fmt.Errorf("failed %d", i)
+			}
+			return nil
+		})
+		Ω(err).Should(MatchError("failed 3"))
+	})
+})
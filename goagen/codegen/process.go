@@ -0,0 +1,158 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type (
+	// GeneratedFile is a file produced by a generator and given to the file processors.
+	GeneratedFile struct {
+		// Path is the absolute path to the file.
+		Path string
+		// Content is the file content.
+		Content []byte
+		// Generator is the name of the generator that produced the file, it is empty for
+		// files added by file processors.
+		Generator string
+	}
+
+	// FileProcessor is the signature of the functions registered with RegisterFileProcessor.
+	// A file processor may modify the content of the given files, remove files from the list
+	// or add new files to it. It returns the resulting list of files.
+	FileProcessor func(files []*GeneratedFile) ([]*GeneratedFile, error)
+
+	// fileProcessor is a registered file processor.
+	fileProcessor struct {
+		name     string
+		priority int
+		fn       FileProcessor
+	}
+)
+
+// Registered file processors sorted by priority
+var fileProcessors []*fileProcessor
+
+// RegisterFileProcessor registers a function that post-processes the files produced by the
+// generators, for example to add license headers or build tags. File processors run in ascending
+// priority order, processors with identical priorities run in registration order. File processors
+// are typically registered by packages imported by the design package.
+func RegisterFileProcessor(name string, priority int, fn FileProcessor) {
+	fileProcessors = append(fileProcessors, &fileProcessor{name: name, priority: priority, fn: fn})
+	sort.SliceStable(fileProcessors, func(i, j int) bool {
+		return fileProcessors[i].priority < fileProcessors[j].priority
+	})
+}
+
+// ProcessFiles runs the registered file processors on the files produced by the given generator.
+// paths is the list returned by the generator, directories are walked so that each file they
+// contain is processed. ProcessFiles then writes the files whose content changed, creates the
+// files added by the processors and deletes the files removed by the processors. It returns the
+// updated list of paths.
+//
+// A processor may not remove a Go file if other Go files produced by the same generator remain in
+// the same package directory as they most likely depend on it.
+func ProcessFiles(generator string, paths []string) ([]string, error) {
+	if len(fileProcessors) == 0 {
+		return paths, nil
+	}
+	var files []*GeneratedFile
+	for _, p := range paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			files = append(files, &GeneratedFile{Path: path, Content: content, Generator: generator})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	original := make(map[string][]byte, len(files))
+	for _, f := range files {
+		original[f.Path] = f.Content
+	}
+
+	for _, p := range fileProcessors {
+		processed, err := p.fn(files)
+		if err != nil {
+			return nil, fmt.Errorf("file processor %s: %s", p.name, err)
+		}
+		if err := checkRemovals(p.name, files, processed); err != nil {
+			return nil, err
+		}
+		files = processed
+	}
+
+	kept := make(map[string]bool, len(files))
+	for _, f := range files {
+		kept[f.Path] = true
+		if content, ok := original[f.Path]; ok && bytes.Equal(content, f.Content) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(f.Path, f.Content, 0644); err != nil {
+			return nil, err
+		}
+	}
+	for path := range original {
+		if !kept[path] {
+			if err := os.Remove(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var res []string
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			res = append(res, p)
+		}
+	}
+	for _, f := range files {
+		if _, ok := original[f.Path]; !ok {
+			res = append(res, f.Path)
+		}
+	}
+	return res, nil
+}
+
+// checkRemovals returns an error if the processor with the given name removed a Go file that
+// other Go files produced by the same generator depend on.
+func checkRemovals(name string, before, after []*GeneratedFile) error {
+	kept := make(map[string]bool, len(after))
+	for _, f := range after {
+		kept[f.Path] = true
+	}
+	for _, removed := range before {
+		if kept[removed.Path] || removed.Generator == "" || filepath.Ext(removed.Path) != ".go" {
+			continue
+		}
+		for _, f := range after {
+			if f.Generator != removed.Generator || filepath.Ext(f.Path) != ".go" {
+				continue
+			}
+			if filepath.Dir(f.Path) != filepath.Dir(removed.Path) || strings.HasSuffix(f.Path, "_test.go") {
+				continue
+			}
+			return fmt.Errorf("file processor %s removed %s which %s generated by %s depends on",
+				name, removed.Path, f.Path, removed.Generator)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package codegen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Names of the test file processors in the order they ran, nil when not recording.
+var processed *[]string
+
+func init() {
+	record := func(name string) codegen.FileProcessor {
+		return func(files []*codegen.GeneratedFile) ([]*codegen.GeneratedFile, error) {
+			if processed == nil {
+				return files, nil
+			}
+			*processed = append(*processed, name)
+			for _, f := range files {
+				f.Content = append(f.Content, "// "+name+"\n"...)
+			}
+			return files, nil
+		}
+	}
+	codegen.RegisterFileProcessor("second", 10, record("second"))
+	codegen.RegisterFileProcessor("first", 1, record("first"))
+	codegen.RegisterFileProcessor("third", 10, record("third"))
+}
+
+var _ = Describe("ProcessFiles", func() {
+	var dir, file string
+	var order []string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "process")
+		Ω(err).ShouldNot(HaveOccurred())
+		file = filepath.Join(dir, "foo.go")
+		Ω(ioutil.WriteFile(file, []byte("package foo\n"), 0644)).Should(Succeed())
+		order = nil
+		processed = &order
+	})
+
+	AfterEach(func() {
+		processed = nil
+		os.RemoveAll(dir)
+	})
+
+	It("runs the file processors in priority order", func() {
+		files, err := codegen.ProcessFiles("gen", []string{dir})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(files).Should(Equal([]string{dir}))
+		Ω(order).Should(Equal([]string{"first", "second", "third"}))
+		content, err := ioutil.ReadFile(file)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(Equal("package foo\n// first\n// second\n// third\n"))
+	})
+})
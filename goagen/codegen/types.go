@@ -29,6 +29,7 @@ var (
 
 // Initialize all templates
 func init() {
+	dslengine.RegisterMetadataKeys("transform", "key")
 	var err error
 	fn := template.FuncMap{
 		"tabs":               Tabs,
@@ -73,6 +74,9 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 	t := def.Type
 	switch actual := t.(type) {
 	case design.Primitive:
+		if tname := GoTimeType(def); tname != "" {
+			return tname
+		}
 		return GoTypeName(t, nil, tabs, private)
 	case *design.Array:
 		d := GoTypeDef(actual.ElemType, tabs, jsonTags, private)
@@ -139,14 +143,7 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 // attributeTags computes the struct field tags.
 func attributeTags(parent, att *design.AttributeDefinition, name string, private bool) string {
 	var elems []string
-	keys := make([]string, len(att.Metadata))
-	i := 0
-	for k := range att.Metadata {
-		keys[i] = k
-		i++
-	}
-	sort.Strings(keys)
-	for _, key := range keys {
+	for _, key := range att.Metadata.Keys() {
 		val := att.Metadata[key]
 		if strings.HasPrefix(key, "struct:tag:") {
 			name := key[11:]
@@ -244,6 +241,10 @@ func GoNativeType(t design.DataType) string {
 			return "interface{}"
 		case design.FileKind:
 			return "multipart.FileHeader"
+		case design.DurationKind:
+			return "time.Duration"
+		case design.BytesKind:
+			return "[]byte"
 		default:
 			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
 		}
@@ -262,6 +263,24 @@ func GoNativeType(t design.DataType) string {
 	}
 }
 
+// GoTimeType returns the name of the goa type that encodes the values of the given DateTime or
+// Duration attribute in request and response bodies according to its time format. It returns the
+// empty string if the attribute is not a Duration attribute and uses the default time format,
+// time.Time encodes values as RFC 3339 strings already.
+func GoTimeType(att *design.AttributeDefinition) string {
+	switch att.TimeFormat() {
+	case design.TimeFormatUnix:
+		return "goa.UnixTime"
+	case design.TimeFormatUnixMilli:
+		return "goa.UnixMilliTime"
+	case design.DurationFormatString:
+		return "goa.Duration"
+	case design.DurationFormatMilli:
+		return "goa.MilliDuration"
+	}
+	return ""
+}
+
 // GoTypeDesc returns the description of a type.  If no description is defined
 // for the type, one will be generated.
 func GoTypeDesc(t design.DataType, upper bool) string {
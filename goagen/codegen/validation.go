@@ -424,9 +424,35 @@ func validationsCode(att *design.AttributeDefinition, data map[string]interface{
 		}
 		res = append(res, val)
 	}
+	for _, group := range validation.Together {
+		res = append(res, togetherCode(att, group, data["target"].(string), data["context"].(string),
+			data["depth"].(int), data["private"].(bool)))
+	}
 	return
 }
 
+// togetherCode produces code that checks that the fields of target with the given names are
+// either all set or all unset. The fields that cannot be nil are always set.
+func togetherCode(att *design.AttributeDefinition, names []string, target, context string, depth int, private bool) string {
+	obj := att.Type.ToObject()
+	quoted := make([]string, len(names))
+	set := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+		catt := obj[n]
+		switch {
+		case catt == nil:
+			set[i] = "false"
+		case !private && catt.Type.IsPrimitive() && catt.Type.Kind() != design.AnyKind && !att.IsPrimitivePointer(n):
+			set[i] = "true"
+		default:
+			set[i] = fmt.Sprintf("%s.%s != nil", target, GoifyAtt(catt, n, true))
+		}
+	}
+	return fmt.Sprintf("%serr = goa.MergeErrors(err, goa.ValidateTogether(`%s`, []string{%s}, []bool{%s}))",
+		Tabs(depth), context, strings.Join(quoted, ", "), strings.Join(set, ", "))
+}
+
 // renderInteger renders a max or min value properly, taking into account
 // overflows due to casting from a float value.
 func renderInteger(f float64) string {
@@ -217,6 +217,22 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of fields required together", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"lat": &design.AttributeDefinition{Type: design.Number},
+						"lng": &design.AttributeDefinition{Type: design.Number},
+					}
+					validation = &dslengine.ValidationDefinition{
+						Together: [][]string{{"lat", "lng"}},
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(togetherValCode))
+				})
+			})
+
 			Context("of embedded object", func() {
 				var catt, ccatt *design.AttributeDefinition
 
@@ -423,6 +439,8 @@ const (
 		}
 	}`
 
+	togetherValCode = "\terr = goa.MergeErrors(err, goa.ValidateTogether(`context`, []string{\"lat\", \"lng\"}, []bool{val.Lat != nil, val.Lng != nil}))"
+
 	embeddedValCode = `	if val.Foo != nil {
 		if val.Foo.Bar != nil {
 			if !(*val.Foo.Bar == 1 || *val.Foo.Bar == 2 || *val.Foo.Bar == 3) {
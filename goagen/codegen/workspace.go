@@ -47,6 +47,13 @@ type (
 		Package *Package
 		// osFile is the underlying OS file.
 		osFile *os.File
+		// buf holds the file content until the file is closed when SkipUnchanged is set.
+		buf *bytes.Buffer
+		// closed is true once buf has been written to disk.
+		closed bool
+		// tempCount holds the value appended to the temporary variable names generated by
+		// the file templates.
+		tempCount int
 	}
 )
 
@@ -179,19 +186,42 @@ func (p *Package) Abs() string {
 // CreateSourceFile creates a Go source file in the given package. If the file
 // already exists it is overwritten.
 func (p *Package) CreateSourceFile(name string) (*SourceFile, error) {
+	if SkipUnchanged {
+		// Keep the existing file, Close only overwrites it if the content changed.
+		f, err := p.OpenSourceFile(name)
+		if err == nil {
+			f.buf.Reset()
+		}
+		return f, err
+	}
 	os.RemoveAll(filepath.Join(p.Abs(), name))
 	return p.OpenSourceFile(name)
 }
 
 // OpenSourceFile opens an existing file to append to it. If the file does not
-// exist OpenSourceFile creates it.
+// exist OpenSourceFile creates it. When SkipUnchanged is set the content is kept
+// in memory and written to disk by Close.
 func (p *Package) OpenSourceFile(name string) (*SourceFile, error) {
 	f := &SourceFile{Name: name, Package: p}
 	file, err := os.OpenFile(f.Abs(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, err
 	}
-	f.osFile = file
+	if !SkipUnchanged {
+		f.osFile = file
+		return f, nil
+	}
+	file.Close()
+	if claimFile(f.Abs()) {
+		// The file was left over by CleanDir, start from scratch.
+		f.buf = new(bytes.Buffer)
+		return f, nil
+	}
+	content, err := ioutil.ReadFile(f.Abs())
+	if err != nil {
+		return nil, err
+	}
+	f.buf = bytes.NewBuffer(content)
 	return f, nil
 }
 
@@ -249,23 +279,47 @@ func (f *SourceFile) WriteHeader(title, pack string, imports []*ImportSpec) erro
 // Write implements io.Writer so that variables of type *SourceFile can be
 // used in template.Execute.
 func (f *SourceFile) Write(b []byte) (int, error) {
+	if f.buf != nil {
+		return f.buf.Write(b)
+	}
 	return f.osFile.Write(b)
 }
 
-// Close closes the underlying OS file.
+// Close closes the underlying OS file or writes the buffered content to disk.
 func (f *SourceFile) Close() {
-	if err := f.osFile.Close(); err != nil {
+	if f.buf == nil {
+		if err := f.osFile.Close(); err != nil {
+			panic(err) // bug
+		}
+		return
+	}
+	if f.closed {
+		return
+	}
+	f.closed = true
+	if err := WriteFile(f.Abs(), f.buf.Bytes()); err != nil {
 		panic(err) // bug
 	}
 }
 
-// FormatCode performs the equivalent of "goimports -w" on the source file.
+// FormatCode performs the equivalent of "goimports -w" on the source file. FormatCode should be
+// called prior to Close so that files whose content did not change are not written when
+// SkipUnchanged is set.
 func (f *SourceFile) FormatCode() error {
+	buffered := f.buf != nil && !f.closed
+	var content []byte
+	if buffered {
+		content = f.buf.Bytes()
+	} else {
+		var err error
+		if content, err = ioutil.ReadFile(f.Abs()); err != nil {
+			return err
+		}
+	}
 	// Parse file into AST
 	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, f.Abs(), nil, parser.ParseComments)
+	file, err := parser.ParseFile(fset, f.Abs(), content, parser.ParseComments)
 	if err != nil {
-		content, _ := ioutil.ReadFile(f.Abs())
 		var buf bytes.Buffer
 		scanner.PrintError(&buf, err)
 		return fmt.Errorf("%s\n========\nContent:\n%s", buf.String(), content)
@@ -285,14 +339,16 @@ func (f *SourceFile) FormatCode() error {
 		}
 	}
 	ast.SortImports(fset, file)
-	// Open file to be written
-	w, err := os.OpenFile(f.Abs(), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
-	if err != nil {
+	// Write formatted code without unused imports
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
 		return err
 	}
-	defer w.Close()
-	// Write formatted code without unused imports
-	return format.Node(w, fset, file)
+	if buffered {
+		f.buf = &buf
+		return nil
+	}
+	return WriteFile(f.Abs(), buf.Bytes())
 }
 
 // Abs returne the source file absolute filename
@@ -301,14 +357,27 @@ func (f *SourceFile) Abs() string {
 }
 
 // ExecuteTemplate executes the template and writes the output to the file.
+// The "tempvar" template function generates variable names that are unique to the file unless
+// overridden in funcMap, this keeps the generated code independent of the order in which files
+// are written when generators run concurrently.
 func (f *SourceFile) ExecuteTemplate(name, source string, funcMap template.FuncMap, data interface{}) error {
-	tmpl, err := template.New(name).Funcs(DefaultFuncMap).Funcs(funcMap).Parse(source)
+	tmpl, err := template.New(name).
+		Funcs(DefaultFuncMap).
+		Funcs(template.FuncMap{"tempvar": f.tempvar}).
+		Funcs(funcMap).
+		Parse(source)
 	if err != nil {
 		panic(err) // bug
 	}
 	return tmpl.Execute(f, data)
 }
 
+// tempvar generates a variable name unique to the file.
+func (f *SourceFile) tempvar() string {
+	f.tempCount++
+	return fmt.Sprintf("tmp%d", f.tempCount)
+}
+
 // PackagePath returns the Go package path for the directory that lives under the given absolute
 // file path.
 func PackagePath(path string) (string, error) {
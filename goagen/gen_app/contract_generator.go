@@ -0,0 +1,247 @@
+package genapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// ContractTestData is the data used to render the contract test of a resource.
+type ContractTestData struct {
+	Name      string            // Name of resource
+	Resource  string            // Go name of resource
+	MountName string            // Name of generated mount function
+	Actions   []*ContractAction // Actions sorted by name
+}
+
+// ContractAction describes the example request made to an action by the contract test and the
+// response the test expects.
+type ContractAction struct {
+	Name        string            // Name of action
+	MethodName  string            // Name of controller method
+	ContextType string            // Type of action context
+	Skip        string            // Reason the action is skipped if not tested
+	Verb        string            // HTTP method of example request
+	Path        string            // Path of example request
+	Query       string            // Encoded query string of example request
+	Headers     []*ContractHeader // Headers of example request
+	PayloadJSON string            // JSON encoded example payload if any
+	Status      int               // Expected response status code
+	RespName    string            // Name of context method used to write the response
+	BodyType    string            // Type of response body
+	BodyPointer bool              // Whether the response method accepts a pointer
+	BodyJSON    string            // JSON encoded example response body
+}
+
+// ContractHeader is a header set on the example request made by the contract test.
+type ContractHeader struct {
+	Name  string
+	Value string
+}
+
+// generateContractTest generates the contract test of the given resource. The test mounts a
+// controller that writes the example responses on an in-process server and checks that making
+// the example requests produces the example response bodies rendered in the API documentation.
+// Actions that do not define examples are skipped.
+func (g *Generator) generateContractTest(res *design.ResourceDefinition, outDir string, imports []*codegen.ImportSpec) (err error) {
+	filename := filepath.Join(outDir, codegen.SnakeCase(res.Name)+"_contract_test.go")
+	var file *codegen.SourceFile
+	file, err = codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = file.FormatCode()
+		}
+		file.Close()
+	}()
+	title := fmt.Sprintf("%s: %s Contract Tests", g.API.Context(), res.Name)
+	imports = append(imports,
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("reflect"),
+		codegen.SimpleImport("testing"),
+	)
+	if err = file.WriteHeader(title, "test", imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+
+	resName := codegen.Goify(res.Name, true)
+	data := &ContractTestData{
+		Name:      res.Name,
+		Resource:  resName,
+		MountName: fmt.Sprintf("%s.Mount%sController", g.Target, resName),
+	}
+	err = res.IterateActions(func(a *design.ActionDefinition) error {
+		if a.Static != nil {
+			return nil
+		}
+		ca, err := g.contractAction(a)
+		if err != nil {
+			return err
+		}
+		data.Actions = append(data.Actions, ca)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return file.ExecuteTemplate("contract", contractT, nil, data)
+}
+
+// contractAction computes the contract test data of the given action.
+func (g *Generator) contractAction(a *design.ActionDefinition) (*ContractAction, error) {
+	name := codegen.Goify(a.Name, true)
+	ca := &ContractAction{
+		Name:        a.Name,
+		MethodName:  name,
+		ContextType: fmt.Sprintf("%s.%s%sContext", g.Target, name, codegen.Goify(a.Parent.Name, true)),
+	}
+	if a.WebSocket() {
+		ca.Skip = fmt.Sprintf("%s is a websocket action", a.Name)
+		return ca, nil
+	}
+	ex := a.Example()
+	if ex == nil {
+		ca.Skip = fmt.Sprintf("%s does not define examples", a.Name)
+		return ca, nil
+	}
+	projected, _, err := ex.MediaType.Project(ex.View)
+	if err != nil {
+		return nil, err
+	}
+	body, err := exampleJSON(ex.Body)
+	if err != nil {
+		return nil, err
+	}
+	ca.Verb = ex.Route.Verb
+	ca.Path = ex.Path
+	ca.Query = ex.Query.Encode()
+	ca.Status = ex.Response.Status
+	ca.RespName = codegen.Goify(ex.Response.Name, true)
+	if ex.View != design.DefaultView {
+		ca.RespName = codegen.Goify(ex.Response.Name+strings.Title(ex.View), true)
+	}
+	ca.BodyType = fmt.Sprintf("%s.%s", g.Target, codegen.GoTypeName(projected, nil, 0, false))
+	ca.BodyPointer = projected.IsObject()
+	ca.BodyJSON = body
+	if ex.Payload != nil {
+		if ca.PayloadJSON, err = exampleJSON(ex.Payload); err != nil {
+			return nil, err
+		}
+	}
+	names := make([]string, 0, len(ex.Headers))
+	for n := range ex.Headers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		ca.Headers = append(ca.Headers, &ContractHeader{Name: n, Value: ex.Headers.Get(n)})
+	}
+	return ca, nil
+}
+
+// exampleJSON returns the JSON representation of the example value v.
+func exampleJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(jsonExample(v))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// jsonExample converts the hash examples so that they may be marshaled to JSON.
+func jsonExample(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[fmt.Sprintf("%v", k)] = jsonExample(e)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[k] = jsonExample(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(actual))
+		for i, e := range actual {
+			res[i] = jsonExample(e)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+const contractT = `
+// contract{{ .Resource }}Controller implements the {{ .Name }} resource by writing the example
+// responses.
+type contract{{ .Resource }}Controller struct {
+	*goa.Controller
+}
+{{ range .Actions }}
+// {{ .MethodName }} runs the {{ .Name }} action.
+func (c *contract{{ $.Resource }}Controller) {{ .MethodName }}(ctx *{{ .ContextType }}) error {
+{{ if .Skip }}	return nil
+{{ else }}	var res {{ .BodyType }}
+	if err := json.Unmarshal([]byte({{ printf "%q" .BodyJSON }}), &res); err != nil {
+		return err
+	}
+	return ctx.{{ .RespName }}({{ if .BodyPointer }}&{{ end }}res)
+{{ end }}}
+{{ end }}
+// Test{{ .Resource }}Contract makes the example requests of the {{ .Name }} resource actions to a
+// controller that writes the example responses and checks that the response bodies match the
+// examples used in the API documentation. This makes sure that the examples, the validations and
+// the encoders stay in sync.
+func Test{{ .Resource }}Contract(t *testing.T) {
+	service := goa.New("contract")
+	service.WithLogger(goa.NewLogger(log.New(ioutil.Discard, "", 0)))
+	ctrl := &contract{{ .Resource }}Controller{Controller: service.NewController("{{ .Resource }}Controller")}
+	{{ .MountName }}(service, ctrl)
+	srv := httptest.NewServer(service.Mux)
+	defer srv.Close()
+{{ range .Actions }}
+	t.Run({{ printf "%q" .Name }}, func(t *testing.T) {
+{{ if .Skip }}		t.Skip({{ printf "%q" .Skip }})
+{{ else }}		req, err := http.NewRequest({{ printf "%q" .Verb }}, srv.URL+{{ printf "%q" .Path }}{{ if .Query }}+"?"+{{ printf "%q" .Query }}{{ end }}, strings.NewReader({{ printf "%q" .PayloadJSON }}))
+		if err != nil {
+			t.Fatalf("invalid example request: %s", err)
+		}
+{{ range .Headers }}		req.Header.Set({{ printf "%q" .Name }}, {{ printf "%q" .Value }})
+{{ end }}{{ if .PayloadJSON }}		req.Header.Set("Content-Type", "application/json")
+{{ end }}		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("example request failed: %s", err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read response body: %s", err)
+		}
+		if resp.StatusCode != {{ .Status }} {
+			t.Fatalf("invalid response status code: got %d, expected {{ .Status }}, body: %s", resp.StatusCode, body)
+		}
+		var actual, expected interface{}
+		if err := json.Unmarshal(body, &actual); err != nil {
+			t.Fatalf("invalid response body: %s", err)
+		}
+		if err := json.Unmarshal([]byte({{ printf "%q" .BodyJSON }}), &expected); err != nil {
+			panic(err) // bug
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("response body does not match the example:\ngot:      %s\nexpected: %s", body, {{ printf "%q" .BodyJSON }})
+		}
+{{ end }}	})
+{{ end }}}
+`
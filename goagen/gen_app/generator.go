@@ -1,8 +1,10 @@
 package genapp
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
@@ -80,35 +82,43 @@ func (g *Generator) Generate() (_ []string, err error) {
 
 	codegen.Reserved[g.Target] = true
 
-	os.RemoveAll(g.OutDir)
+	codegen.CleanDir(g.OutDir)
 
 	if err := os.MkdirAll(g.OutDir, 0755); err != nil {
 		return nil, err
 	}
 	g.genfiles = []string{g.OutDir}
-	if err := g.generateContexts(); err != nil {
-		return nil, err
-	}
-	if err := g.generateControllers(); err != nil {
-		return nil, err
-	}
-	if err := g.generateSecurity(); err != nil {
-		return nil, err
-	}
-	if err := g.generateHrefs(); err != nil {
-		return nil, err
-	}
-	if err := g.generateMediaTypes(); err != nil {
-		return nil, err
-	}
-	if err := g.generateUserTypes(); err != nil {
-		return nil, err
+	steps := []func(*Generator) error{
+		(*Generator).generateContexts,
+		(*Generator).generateControllers,
+		(*Generator).generateSecurity,
+		(*Generator).generateHrefs,
+		(*Generator).generateMediaTypes,
+		(*Generator).generateUserTypes,
+		(*Generator).generateAsync,
+		(*Generator).generateStreams,
 	}
 	if !g.NoTest {
-		if err := g.generateResourceTest(); err != nil {
-			return nil, err
+		steps = append(steps, (*Generator).generateResourceTest)
+	}
+	// Each step writes its own files using a copy of the generator so that the steps may run
+	// concurrently, the generated files are then recorded in the order of the steps.
+	gens := make([]*Generator, len(steps))
+	err = codegen.Parallel(codegen.Jobs, len(steps), func(i int) error {
+		sg := *g
+		sg.genfiles = nil
+		sg.validator = codegen.NewValidator()
+		gens[i] = &sg
+		return steps[i](&sg)
+	})
+	for _, sg := range gens {
+		if sg != nil {
+			g.genfiles = append(g.genfiles, sg.genfiles...)
 		}
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	return g.genfiles, nil
 }
@@ -137,13 +147,14 @@ func (g *Generator) generateContexts() (err error) {
 		}
 	}
 	defer func() {
-		ctxWr.Close()
 		if err == nil {
 			err = ctxWr.FormatCode()
 		}
+		ctxWr.Close()
 	}()
 	title := fmt.Sprintf("%s: Application Contexts", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("strconv"),
@@ -169,19 +180,12 @@ func (g *Generator) generateContexts() (err error) {
 	}
 	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
 		return r.IterateActions(func(a *design.ActionDefinition) error {
-			ctxName := codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true) + "Context"
-			headers := &design.AttributeDefinition{
-				Type: design.Object{},
-			}
-			if r.Headers != nil {
-				headers.Merge(r.Headers)
-				headers.Validation = r.Headers.Validation
+			if a.Static != nil {
+				return nil // served by the generated code, see staticActionData
 			}
-			if a.Headers != nil {
-				headers.Merge(a.Headers)
-				headers.Validation = a.Headers.Validation
-			}
-			if headers != nil && len(headers.Type.ToObject()) == 0 {
+			ctxName := codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true) + "Context"
+			headers := a.AllHeaders()
+			if len(headers.Type.ToObject()) == 0 {
 				headers = nil // So that {{if .Headers}} returns false in templates
 			}
 			params := a.AllParams()
@@ -208,6 +212,10 @@ func (g *Generator) generateContexts() (err error) {
 				DefaultPkg:   g.Target,
 				Security:     a.Security,
 			}
+			if resp, _ := a.ViewedResponse(); resp != nil && a.ViewParam != "" {
+				ctxData.ViewParam = a.ViewParam
+				ctxData.ViewedResponse = resp.Name
+			}
 			return ctxWr.Execute(&ctxData)
 		})
 	})
@@ -229,10 +237,10 @@ func (g *Generator) generateControllers() (err error) {
 		}
 	}
 	defer func() {
-		ctlWr.Close()
 		if err == nil {
 			err = ctlWr.FormatCode()
 		}
+		ctlWr.Close()
 	}()
 	title := fmt.Sprintf("%s: Application Controllers", g.API.Context())
 	imports := []*codegen.ImportSpec{
@@ -241,8 +249,15 @@ func (g *Generator) generateControllers() (err error) {
 		codegen.SimpleImport("context"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("github.com/goadesign/goa/cors"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/compress"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("io"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("mime"),
 		codegen.SimpleImport("regexp"),
 		codegen.SimpleImport("strconv"),
+		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
@@ -280,7 +295,7 @@ func (g *Generator) generateControllers() (err error) {
 
 	g.genfiles = append(g.genfiles, ctlFile)
 	var controllersData []*ControllerTemplateData
-	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
 		// Create file servers for all directory file servers that serve index.html.
 		fileServers := r.FileServers
 		for _, fs := range r.FileServers {
@@ -295,6 +310,8 @@ func (g *Generator) generateControllers() (err error) {
 					RequestPath: rpath,
 					Metadata:    fs.Metadata,
 					Security:    fs.Security,
+					ETag:        fs.ETag,
+					MaxAge:      fs.MaxAge,
 				})
 			}
 		}
@@ -303,8 +320,19 @@ func (g *Generator) generateControllers() (err error) {
 			Resource:       codegen.Goify(r.Name, true),
 			PreflightPaths: r.PreflightPaths(),
 			FileServers:    fileServers,
+			HealthCheck:    r.HealthCheck,
+			Compression:    r.Compression,
+			StripPrefix:    r.StrippedPrefix(),
 		}
-		r.IterateActions(func(a *design.ActionDefinition) error {
+		err := r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Static != nil {
+				static, err := staticActionData(a)
+				if err != nil {
+					return err
+				}
+				data.StaticActions = append(data.StaticActions, static)
+				return nil
+			}
 			context := fmt.Sprintf("%s%sContext", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
 			unmarshal := fmt.Sprintf("unmarshal%s%sPayload", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
 			action := map[string]interface{}{
@@ -316,23 +344,137 @@ func (g *Generator) generateControllers() (err error) {
 				"Payload":          a.Payload,
 				"PayloadOptional":  a.PayloadOptional,
 				"PayloadMultipart": a.PayloadMultipart,
+				"Payloads":         a.Payloads,
 				"Security":         a.Security,
+				"WebSocket":        a.WebSocket(),
+			}
+			if a.BinaryPayload() {
+				action["BinaryMIMETypes"] = a.BinaryMIMETypes()
+				if val := a.Payload.Validation; val != nil && val.MaxLength != nil {
+					action["MaxBodyLength"] = *val.MaxLength
+				}
+			}
+			if a.Signature != nil {
+				action["Signature"] = a.Signature
+				action["SignatureErrorStatus"] = a.SignatureErrorStatus()
+			}
+			if d, ok := a.Timeout(); ok {
+				action["Timeout"] = codegen.DurationCode(d)
+			}
+			if a.Negotiates() {
+				if a.Payload != nil && !a.WebSocket() {
+					action["Consumes"] = a.Consumes
+				}
+				action["Produces"] = a.Produces
+			}
+			if a.Name == r.NotFoundActionName {
+				action["NotFoundRoutes"] = r.NotFoundRoutes()
 			}
 			data.Actions = append(data.Actions, action)
 			return nil
 		})
-		if len(data.Actions) > 0 || len(data.FileServers) > 0 {
+		if err != nil {
+			return err
+		}
+		if len(data.Actions) > 0 || len(data.StaticActions) > 0 || len(data.FileServers) > 0 || data.HealthCheck != nil {
 			data.Encoders = encoders
 			data.Decoders = decoders
 			data.Origins = r.AllOrigins()
+			data.ResponseHeaders = r.StaticResponseHeaders()
+			if status := g.API.InvalidRequestStatus(); status != http.StatusBadRequest {
+				data.InvalidRequestStatus = status
+			}
+			data.Mounts = mountsData(r, data)
 			controllersData = append(controllersData, data)
 		}
 		return nil
 	})
-	err = ctlWr.Execute(controllersData)
+	if err != nil {
+		return
+	}
+	if err = ctlWr.Execute(controllersData); err != nil {
+		return
+	}
+	if g.API.HealthCheck != nil {
+		err = ctlWr.WriteHealthCheck(g.API.HealthCheck)
+	}
 	return
 }
 
+// mountsData returns the template data used to generate the Mount functions of the named mounts of
+// the resource r from the data of its default mount. The mount routes replace the action routes
+// and the mount security overrides the action security.
+func mountsData(r *design.ResourceDefinition, data *ControllerTemplateData) []*ControllerTemplateData {
+	mountActions := func(m *design.MountDefinition, actions []map[string]interface{}) []map[string]interface{} {
+		var res []map[string]interface{}
+		for _, action := range actions {
+			a := r.Actions[action["DesignName"].(string)]
+			routes := m.Routes(a)
+			if len(routes) == 0 {
+				continue
+			}
+			ma := make(map[string]interface{}, len(action))
+			for k, v := range action {
+				ma[k] = v
+			}
+			ma["Routes"] = routes
+			ma["Security"] = m.ActionSecurity(a)
+			delete(ma, "NotFoundRoutes")
+			res = append(res, ma)
+		}
+		return res
+	}
+	var mounts []*ControllerTemplateData
+	for _, m := range r.Mounts {
+		md := *data
+		md.Mount = m.Name
+		md.Mounts = nil
+		md.FileServers = nil
+		md.HealthCheck = nil
+		md.StripPrefix = ""
+		md.PreflightPaths = m.PreflightPaths()
+		md.Actions = mountActions(m, data.Actions)
+		md.StaticActions = mountActions(m, data.StaticActions)
+		mounts = append(mounts, &md)
+	}
+	return mounts
+}
+
+// staticActionData returns the template data used to mount the handler of the static action a.
+// The handler writes the response body computed at generation time: string bodies of String
+// responses are written as is, other bodies are encoded in JSON.
+func staticActionData(a *design.ActionDefinition) (map[string]interface{}, error) {
+	resp := a.StaticResponse()
+	if resp == nil {
+		return nil, fmt.Errorf("static action %s of %s has no response with status %d", a.Name, a.Parent.Name, a.Static.Status)
+	}
+	var body string
+	if a.Static.Body != nil {
+		if s, ok := a.Static.Body.(string); ok && resp.Type != nil && resp.Type.Kind() == design.StringKind {
+			body = s
+		} else {
+			b, err := json.Marshal(a.Static.Body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode static response body of action %s of %s: %s", a.Name, a.Parent.Name, err)
+			}
+			body = string(b)
+		}
+	}
+	contentType := resp.BodyContentType()
+	if contentType == "" && body != "" {
+		contentType = "application/json"
+	}
+	return map[string]interface{}{
+		"Name":        codegen.Goify(a.Name, true),
+		"DesignName":  a.Name,
+		"Routes":      a.Routes,
+		"Status":      a.Static.Status,
+		"ContentType": contentType,
+		"Body":        body,
+		"Security":    a.Security,
+	}, nil
+}
+
 // generateControllers iterates through the API resources and generates the low level
 // controllers.
 func (g *Generator) generateSecurity() (err error) {
@@ -352,10 +494,10 @@ func (g *Generator) generateSecurity() (err error) {
 		}
 	}
 	defer func() {
-		secWr.Close()
 		if err == nil {
 			err = secWr.FormatCode()
 		}
+		secWr.Close()
 	}()
 	title := fmt.Sprintf("%s: Application Security", g.API.Context())
 	imports := []*codegen.ImportSpec{
@@ -387,10 +529,10 @@ func (g *Generator) generateHrefs() (err error) {
 		}
 	}
 	defer func() {
-		resWr.Close()
 		if err == nil {
 			err = resWr.FormatCode()
 		}
+		resWr.Close()
 	}()
 	title := fmt.Sprintf("%s: Application Resource Href Factories", g.API.Context())
 	imports := []*codegen.ImportSpec{
@@ -417,6 +559,19 @@ func (g *Generator) generateHrefs() (err error) {
 			CanonicalTemplate: codegen.CanonicalTemplate(r),
 			CanonicalParams:   codegen.CanonicalParams(r),
 		}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			for _, l := range a.Links {
+				data.Links = append(data.Links, &LinkHrefData{
+					Name:     codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + codegen.Goify(l.Rel, true),
+					Rel:      l.Rel,
+					Action:   a.Name,
+					Target:   l.Target().Context(),
+					Template: codegen.LinkTemplate(l),
+					Params:   codegen.LinkParams(l),
+				})
+			}
+			return nil
+		})
 		return resWr.Execute(&data)
 	})
 	return
@@ -437,10 +592,10 @@ func (g *Generator) generateMediaTypes() (err error) {
 		}
 	}
 	defer func() {
-		mtWr.Close()
 		if err == nil {
 			err = mtWr.FormatCode()
 		}
+		mtWr.Close()
 	}()
 	title := fmt.Sprintf("%s: Application Media Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
@@ -484,10 +639,10 @@ func (g *Generator) generateUserTypes() (err error) {
 		}
 	}
 	defer func() {
-		utWr.Close()
 		if err == nil {
 			err = utWr.FormatCode()
 		}
+		utWr.Close()
 	}()
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
@@ -510,3 +665,130 @@ func (g *Generator) generateUserTypes() (err error) {
 	})
 	return
 }
+
+// generateAsync generates the message types of the async actions that define them inline and the
+// functions that publish and consume the messages. Messages that use a type or media type defined
+// in the design reuse the corresponding generated data structure.
+// generateStreams iterates through the actions that stream results and generates the stream
+// types used by the controllers to send the results.
+func (g *Generator) generateStreams() (err error) {
+	var streams []*StreamTemplateData
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.StreamingResult == nil {
+				return nil
+			}
+			streams = append(streams, &StreamTemplateData{
+				Name:            codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true),
+				ActionName:      a.Name,
+				ResourceName:    r.Name,
+				Transport:       a.StreamTransport(),
+				StreamingResult: a.StreamingResult,
+				Result:          a.Result,
+			})
+			return nil
+		})
+	})
+	if len(streams) == 0 {
+		return nil
+	}
+
+	var (
+		streamsFile string
+		streamsWr   *StreamsWriter
+	)
+	{
+		streamsFile = filepath.Join(g.OutDir, "streams.go")
+		streamsWr, err = NewStreamsWriter(streamsFile)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		if err == nil {
+			err = streamsWr.FormatCode()
+		}
+		streamsWr.Close()
+	}()
+	title := fmt.Sprintf("%s: Application Streams", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("golang.org/x/net/websocket"),
+	}
+	if err = streamsWr.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, streamsFile)
+	return streamsWr.Execute(streams)
+}
+
+func (g *Generator) generateAsync() (err error) {
+	if !g.API.HasAsyncActions() {
+		return nil
+	}
+
+	var (
+		asyncFile string
+		asyncWr   *AsyncWriter
+	)
+	{
+		asyncFile = filepath.Join(g.OutDir, "async.go")
+		asyncWr, err = NewAsyncWriter(asyncFile)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		if err == nil {
+			err = asyncWr.FormatCode()
+		}
+		asyncWr.Close()
+	}()
+	title := fmt.Sprintf("%s: Application Async Actions", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+	var actions []*AsyncActionTemplateData
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateAsyncActions(func(a *design.ActionDefinition) error {
+			if a.Message == nil {
+				return nil
+			}
+			shared := g.API.Types[a.Message.TypeName] == a.Message
+			if !shared {
+				for _, mt := range g.API.MediaTypes {
+					if mt.UserTypeDefinition == a.Message {
+						shared = true
+						break
+					}
+				}
+			}
+			if !shared {
+				imports = codegen.AttributeImports(a.Message.AttributeDefinition, imports, nil)
+			}
+			actions = append(actions, &AsyncActionTemplateData{
+				Name:         codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true),
+				DesignName:   a.Name,
+				ResourceName: r.Name,
+				Description:  a.Description,
+				Operation:    a.AsyncOperation,
+				Channel:      a.Channel,
+				Message:      a.Message,
+				Inline:       !shared,
+			})
+			return nil
+		})
+	})
+	if err = asyncWr.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, asyncFile)
+	return asyncWr.Execute(actions)
+}
@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_app"
@@ -17,6 +19,22 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// process is the function called by the test file processor if not nil.
+var process codegen.FileProcessor
+
+// dslRoot is the API definition registered with the DSL engine, the specs that build their
+// design by hand replace design.Design.
+var dslRoot = design.Design
+
+func init() {
+	codegen.RegisterFileProcessor("test", 0, func(files []*codegen.GeneratedFile) ([]*codegen.GeneratedFile, error) {
+		if process == nil {
+			return files, nil
+		}
+		return process(files)
+	})
+}
+
 var _ = Describe("Generate", func() {
 	var workspace *codegen.Workspace
 	var outDir string
@@ -68,6 +86,257 @@ var _ = Describe("Generate", func() {
 			isEmptySource("hrefs.go")
 			isEmptySource("media_types.go")
 		})
+
+		Context("with a file processor", func() {
+			const section = "\n// Health is an extra section added by a file processor.\nfunc Health() {}\n"
+
+			AfterEach(func() {
+				process = nil
+			})
+
+			It("appends a section to the controllers file", func() {
+				process = func(files []*codegen.GeneratedFile) ([]*codegen.GeneratedFile, error) {
+					for _, f := range files {
+						if filepath.Base(f.Path) == "controllers.go" {
+							f.Content = append(f.Content, section...)
+						}
+					}
+					return files, nil
+				}
+				Ω(genErr).Should(BeNil())
+				processed, err := codegen.ProcessFiles("genapp", files)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(processed).Should(Equal(files))
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(HaveSuffix(section))
+			})
+
+			It("reports the removal of a file other files depend on", func() {
+				process = func(files []*codegen.GeneratedFile) ([]*codegen.GeneratedFile, error) {
+					var res []*codegen.GeneratedFile
+					for _, f := range files {
+						if filepath.Base(f.Path) != "contexts.go" {
+							res = append(res, f)
+						}
+					}
+					return res, nil
+				}
+				Ω(genErr).Should(BeNil())
+				_, err := codegen.ProcessFiles("genapp", files)
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring("file processor test removed " + filepath.Join(outDir, "app", "contexts.go")))
+				Ω(err.Error()).Should(ContainSubstring("generated by genapp depends on"))
+			})
+		})
+	})
+
+	Context("with async actions", func() {
+		BeforeEach(func() {
+			event := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"id": &design.AttributeDefinition{Type: design.String}},
+				},
+				TypeName: "OrderEvent",
+			}
+			paid := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:       design.Object{"order_id": &design.AttributeDefinition{Type: design.String}},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"order_id"}},
+				},
+				TypeName: "PaidOrderMessage",
+			}
+			res := &design.ResourceDefinition{Name: "order"}
+			res.AsyncActions = map[string]*design.ActionDefinition{
+				"created": {
+					Name:           "created",
+					Parent:         res,
+					AsyncOperation: design.PublishOperation,
+					Channel:        "orders.created",
+					Message:        event,
+				},
+				"paid": {
+					Name:           "paid",
+					Parent:         res,
+					AsyncOperation: design.SubscribeOperation,
+					Channel:        "payments.completed",
+					Message:        paid,
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Resources: map[string]*design.ResourceDefinition{"order": res},
+				Types:     map[string]*design.UserTypeDefinition{"OrderEvent": event},
+			}
+		})
+
+		It("generates the message types and the publish and subscribe functions", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "async.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			code := string(content)
+			Ω(code).Should(ContainSubstring("type Publisher interface {"))
+			Ω(code).Should(ContainSubstring("type Subscriber interface {"))
+			Ω(code).Should(ContainSubstring("func PublishCreatedOrder(ctx context.Context, p Publisher, msg *OrderEvent) error {"))
+			Ω(code).Should(ContainSubstring(`return p.Publish(ctx, "orders.created", body)`))
+			Ω(code).Should(ContainSubstring("type PaidOrderMessage struct {"))
+			Ω(code).Should(ContainSubstring("func SubscribePaidOrder(ctx context.Context, s Subscriber, handler func(context.Context, *PaidOrderMessage) error) error {"))
+			Ω(code).Should(ContainSubstring("if err := msg.Validate(); err != nil {"))
+			Ω(code).ShouldNot(ContainSubstring("type OrderEvent struct"))
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "app", "user_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("type OrderEvent struct"))
+		})
+	})
+
+	Context("with an action streaming results", func() {
+		BeforeEach(func() {
+			progress := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"done": &design.AttributeDefinition{Type: design.Integer}},
+				},
+				TypeName: "Progress",
+			}
+			summary := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"imported": &design.AttributeDefinition{Type: design.Integer}},
+				},
+				TypeName: "Summary",
+			}
+			res := &design.ResourceDefinition{Name: "task"}
+			route := &design.RouteDefinition{Verb: "POST", Path: "/import"}
+			action := &design.ActionDefinition{
+				Name:            "import",
+				Parent:          res,
+				Routes:          []*design.RouteDefinition{route},
+				Produces:        []string{design.NDJSONMIMEType},
+				StreamingResult: progress,
+				Result:          summary,
+			}
+			route.Parent = action
+			res.Actions = map[string]*design.ActionDefinition{"import": action}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Resources: map[string]*design.ResourceDefinition{"task": res},
+				Types:     map[string]*design.UserTypeDefinition{"Progress": progress, "Summary": summary},
+			}
+		})
+
+		It("generates the stream sending the results and the final result", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "streams.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			code := string(content)
+			Ω(code).Should(ContainSubstring("type ImportTaskStream struct {"))
+			Ω(code).Should(ContainSubstring("func NewImportTaskStream(rw http.ResponseWriter) *ImportTaskStream {"))
+			Ω(code).Should(ContainSubstring("goa.NewNDJSONResultStream(rw)"))
+			Ω(code).Should(ContainSubstring("func (s *ImportTaskStream) Send(v *Progress) error {"))
+			Ω(code).Should(ContainSubstring("func (s *ImportTaskStream) CloseAndSend(v *Summary) error {"))
+		})
+	})
+
+	Context("with a websocket action", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{Name: "task"}
+			route := &design.RouteDefinition{Verb: "GET", Path: "/watch"}
+			action := &design.ActionDefinition{
+				Name:    "watch",
+				Parent:  res,
+				Routes:  []*design.RouteDefinition{route},
+				Schemes: []string{"ws"},
+			}
+			route.Parent = action
+			res.Actions = map[string]*design.ActionDefinition{"watch": action}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Resources: map[string]*design.ResourceDefinition{"task": res},
+			}
+		})
+
+		It("closes the websocket when the action panics", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			code := string(content)
+			Ω(code).Should(ContainSubstring("var PanicHandler goa.PanicHandler = goa.DefaultPanicHandler"))
+			Ω(code).Should(ContainSubstring("h = goa.HandleWebSocketPanics(PanicHandler)(h)"))
+			Ω(code).ShouldNot(ContainSubstring("goa.HandlePanics"))
+		})
+	})
+
+	Context("with an action that lets clients select the response view", func() {
+		BeforeEach(func() {
+			design.Design = dslRoot
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("name", design.String)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+				})
+				apidsl.View("tiny", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/bottles/:id"))
+					apidsl.ViewParam("view")
+					apidsl.Response(design.OK, bottle)
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("binds the view from the query string or the View header and renders it", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			code := string(content)
+			Ω(code).Should(ContainSubstring(`if view := req.Header.Get("View"); view != "" {`))
+			Ω(code).Should(ContainSubstring("goa.InvalidEnumValueError(`view`, rctx.View, []interface{}{\"default\", \"tiny\"})"))
+			Ω(code).Should(ContainSubstring("func (ctx *ShowBottleContext) OKView(r interface{}) error {"))
+			Ω(code).Should(ContainSubstring(`case "tiny":
+		if v, ok := r.(*BottleTiny); ok {`))
+		})
+	})
+
+	Context("with the validation error status", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{Name: "task"}
+			route := &design.RouteDefinition{Verb: "GET", Path: "/tasks"}
+			action := &design.ActionDefinition{Name: "list", Parent: res, Routes: []*design.RouteDefinition{route}}
+			route.Parent = action
+			res.Actions = map[string]*design.ActionDefinition{"list": action}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Resources: map[string]*design.ResourceDefinition{"task": res},
+			}
+		})
+
+		It("uses the default status by default", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).ShouldNot(ContainSubstring("goa.InvalidRequestStatus"))
+		})
+
+		Context("set to 422", func() {
+			BeforeEach(func() {
+				design.Design.ValidationErrorStatus = 422
+			})
+
+			It("overrides the status of the invalid request errors", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("h = goa.InvalidRequestStatus(422)(h)"))
+			})
+		})
 	})
 
 	Context("with a simple API", func() {
@@ -191,7 +460,7 @@ var _ = Describe("Generate", func() {
 
 			It("generates the corresponding code", func() {
 				Ω(genErr).Should(BeNil())
-				Ω(files).Should(HaveLen(8))
+				Ω(files).Should(HaveLen(9))
 
 				isSource("contexts.go", contextsCode)
 				isSource("controllers.go", controllersCode)
@@ -200,6 +469,59 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("with an API CORS policy", func() {
+			BeforeEach(func() {
+				design.Design.Origins = map[string]*design.CORSDefinition{
+					"https://goa.design": {
+						Parent:  design.Design,
+						Origin:  "https://goa.design",
+						Methods: []string{"GET"},
+					},
+				}
+			})
+
+			It("generates the CORS handler and the preflight routes for the resource", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				code := string(content)
+				Ω(code).Should(ContainSubstring(`service.Mux.Handle("OPTIONS", "/:id", ctrl.MuxHandler("preflight", handleWidgetOrigin(cors.HandlePreflight()), nil))`))
+				Ω(code).Should(ContainSubstring("func handleWidgetOrigin(h goa.Handler) goa.Handler {"))
+				Ω(code).Should(ContainSubstring(`cors.MatchOrigin(origin, "https://goa.design")`))
+			})
+		})
+
+		Context("with constant response headers", func() {
+			BeforeEach(func() {
+				design.Design.ResponseHeaders = &design.AttributeDefinition{
+					Type: design.Object{
+						"X-Content-Type-Options": &design.AttributeDefinition{Type: design.String, DefaultValue: "nosniff"},
+					},
+				}
+				design.Design.Resources["Widget"].Actions["get"].Responses["ok"].Headers = &design.AttributeDefinition{
+					Type: design.Object{
+						"Cache-Control": &design.AttributeDefinition{Type: design.String, DefaultValue: "max-age=60"},
+						"ETag":          &design.AttributeDefinition{Type: design.String},
+					},
+				}
+			})
+
+			It("sets the headers in the handlers and the response helpers", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				code := string(content)
+				Ω(code).Should(ContainSubstring("h = handleWidgetResponseHeaders(h)"))
+				Ω(code).Should(ContainSubstring("func handleWidgetResponseHeaders(h goa.Handler) goa.Handler {"))
+				Ω(code).Should(ContainSubstring(`rw.Header().Set("X-Content-Type-Options", "nosniff")`))
+				content, err = ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				code = string(content)
+				Ω(code).Should(ContainSubstring(`ctx.ResponseData.Header().Set("Cache-Control", "max-age=60")`))
+				Ω(code).ShouldNot(ContainSubstring(`"ETag"`))
+			})
+		})
+
 		Context("with a slice payload", func() {
 			BeforeEach(func() {
 				elemType := &design.AttributeDefinition{Type: design.Integer}
@@ -274,6 +596,134 @@ var _ = Describe("Generate", func() {
 	})
 })
 
+var _ = Describe("Generate concurrently", func() {
+	var workspace *codegen.Workspace
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		design.Design = dslRoot
+		dslengine.Reset()
+		apidsl.API("test api", nil)
+		bottle := apidsl.MediaType("application/vnd.bottle", func() {
+			apidsl.Attributes(func() {
+				apidsl.Attribute("id", design.Integer)
+				apidsl.Attribute("name", design.String, func() {
+					apidsl.MinLength(1)
+				})
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("id")
+				apidsl.Attribute("name")
+			})
+		})
+		for _, name := range []string{"bottle", "cellar", "winery"} {
+			name := name
+			apidsl.Resource(name, func() {
+				apidsl.BasePath("/" + name + "s")
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+						apidsl.Param("full", design.Boolean)
+						apidsl.Param("ratings", apidsl.ArrayOf(design.Integer))
+					})
+					apidsl.Response(design.OK, bottle)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Member("name", design.String)
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.Created)
+				})
+				apidsl.Action("upload", func() {
+					apidsl.Routing(apidsl.POST("/:id/upload"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+					})
+					apidsl.Payload(func() {
+						apidsl.Member("vintage", design.Integer)
+						apidsl.Member("price", design.Number)
+						apidsl.Required("vintage")
+					})
+					apidsl.MultipartForm()
+					apidsl.Response(design.NoContent)
+				})
+			})
+		}
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		codegen.Jobs = 1
+		codegen.SkipUnchanged = false
+		workspace.Delete()
+		delete(codegen.Reserved, "app")
+	})
+
+	// generate runs the generator with the given number of jobs and returns the generated files
+	// content indexed by path relative to the output directory.
+	generate := func(jobs int) ([]string, map[string]string) {
+		outDir := filepath.Join(workspace.Path, "app")
+		codegen.Jobs = jobs
+		g := genapp.NewGenerator(genapp.API(design.Design), genapp.OutDir(outDir), genapp.Target("app"))
+		files, err := g.Generate()
+		Ω(err).ShouldNot(HaveOccurred())
+		rels := make([]string, len(files))
+		content := make(map[string]string, len(files))
+		for i, f := range files {
+			rels[i], err = filepath.Rel(outDir, f)
+			Ω(err).ShouldNot(HaveOccurred())
+			if info, err := os.Stat(f); err == nil && info.IsDir() {
+				continue
+			}
+			b, err := ioutil.ReadFile(f)
+			Ω(err).ShouldNot(HaveOccurred())
+			content[rels[i]] = string(b)
+		}
+		return rels, content
+	}
+
+	It("produces the same files as sequential generation", func() {
+		seqFiles, seqContent := generate(1)
+		Ω(seqContent["contexts.go"]).Should(ContainSubstring("tmp1"))
+		Ω(seqContent["controllers.go"]).Should(ContainSubstring("tmp1"))
+		for i := 0; i < 5; i++ {
+			parFiles, parContent := generate(8)
+			Ω(parFiles).Should(Equal(seqFiles))
+			Ω(parContent).Should(Equal(seqContent))
+		}
+	})
+
+	It("leaves the unchanged files untouched and deletes the stale ones when incremental", func() {
+		outDir := filepath.Join(workspace.Path, "app")
+		files, content := generate(1)
+		past := time.Now().Add(-time.Hour).Truncate(time.Second)
+		for f := range content {
+			Ω(os.Chtimes(filepath.Join(outDir, f), past, past)).Should(Succeed())
+		}
+		stale := filepath.Join(outDir, "test", "stale.go")
+		Ω(ioutil.WriteFile(stale, []byte("package test\n"), 0644)).Should(Succeed())
+
+		codegen.SkipUnchanged = true
+		incFiles, incContent := generate(4)
+		deleted, err := codegen.RemoveStaleFiles()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(incFiles).Should(Equal(files))
+		Ω(incContent).Should(Equal(content))
+		Ω(deleted).Should(Equal([]string{stale}))
+		for f := range content {
+			info, err := os.Stat(filepath.Join(outDir, f))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(info.ModTime().Equal(past)).Should(BeTrue(), f)
+		}
+	})
+})
+
 var _ = Describe("NewGenerator", func() {
 	var generator *genapp.Generator
 
@@ -382,6 +832,11 @@ import (
 	"net/http"
 )
 
+// PanicHandler handles the panics raised by the controller actions, it logs the stack trace and
+// returns an internal error by default. Override it prior to mounting the controllers to report
+// panics to an error tracking service.
+var PanicHandler goa.PanicHandler = goa.DefaultPanicHandler
+
 // initService sets up the service encoders, decoders and mux.
 func initService(service *goa.Service) {
 	// Setup encoders and decoders
@@ -412,6 +867,7 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
+	h = goa.HandlePanics(PanicHandler)(h)
 	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("get", h, nil))
 	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
 }
@@ -478,6 +934,7 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
+	h = goa.HandlePanics(PanicHandler)(h)
 	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("get", h, unmarshalGetWidgetPayload))
 	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
 }
@@ -515,6 +972,7 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
+	h = goa.HandlePanics(PanicHandler)(h)
 	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("get", h, unmarshalGetWidgetPayload))
 	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
 }
@@ -554,6 +1012,7 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
+	h = goa.HandlePanics(PanicHandler)(h)
 	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("get", h, unmarshalGetWidgetPayload))
 	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
 }
@@ -16,7 +16,7 @@ import (
 
 func makeTestDir(g *Generator, apiName string) (outDir string, err error) {
 	outDir = filepath.Join(g.OutDir, "test")
-	if err = os.RemoveAll(outDir); err != nil {
+	if err = codegen.CleanDir(outDir); err != nil {
 		return
 	}
 	if err = os.MkdirAll(outDir, 0755); err != nil {
@@ -56,6 +56,21 @@ func (t *TestMethod) Escape(s string) string {
 	return s
 }
 
+// RoundTripMethod is the data used to render the helper that runs an action through an
+// in-process HTTP server.
+type RoundTripMethod struct {
+	*TestMethod
+	MountName string
+	WebSocket bool
+	Responses []*RoundTripResponse
+}
+
+// RoundTripResponse describes how the round trip helper decodes the body of a response.
+type RoundTripResponse struct {
+	Status int
+	Type   *ObjectType
+}
+
 // ObjectType structure
 type ObjectType struct {
 	Label       string
@@ -63,6 +78,8 @@ type ObjectType struct {
 	Type        string
 	Pointer     string
 	Validatable bool
+	// TimeFormat is the "format:time" of DateTime and Duration parameters.
+	TimeFormat string
 }
 
 func (g *Generator) generateResourceTest() error {
@@ -73,6 +90,7 @@ func (g *Generator) generateResourceTest() error {
 		"isSlice": isSlice,
 	}
 	testTmpl := template.Must(template.New("test").Funcs(funcs).Parse(testTmpl))
+	roundTripTmpl := template.Must(template.New("roundTrip").Funcs(funcs).Parse(roundTripTmpl))
 	outDir, err := makeTestDir(g, g.API.Name)
 	if err != nil {
 		return err
@@ -97,6 +115,7 @@ func (g *Generator) generateResourceTest() error {
 		codegen.SimpleImport("github.com/goadesign/goa/goatest"),
 		codegen.SimpleImport("context"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+		codegen.SimpleImport("golang.org/x/net/websocket"),
 	}
 
 	return g.API.IterateResources(func(res *design.ResourceDefinition) (err error) {
@@ -107,19 +126,28 @@ func (g *Generator) generateResourceTest() error {
 			return err
 		}
 		defer func() {
-			file.Close()
 			if err == nil {
 				err = file.FormatCode()
 			}
+			file.Close()
 		}()
 		title := fmt.Sprintf("%s: %s TestHelpers", g.API.Context(), res.Name)
 		if err = file.WriteHeader(title, "test", imports); err != nil {
 			return err
 		}
 
-		var methods []*TestMethod
+		var (
+			methods    []*TestMethod
+			roundTrips []*RoundTripMethod
+		)
 
 		if err = res.IterateActions(func(action *design.ActionDefinition) error {
+			if action.Static != nil {
+				return nil
+			}
+			if len(action.Routes) > 0 {
+				roundTrips = append(roundTrips, g.createRoundTripMethod(res, action))
+			}
 			if err := action.IterateResponses(func(response *design.ResponseDefinition) error {
 				if response.Status == 101 { // SwitchingProtocols, Don't currently handle WebSocket endpoints
 					return nil
@@ -146,11 +174,90 @@ func (g *Generator) generateResourceTest() error {
 			return err
 		}
 		g.genfiles = append(g.genfiles, filename)
-		err = testTmpl.Execute(file, methods)
+		if err = testTmpl.Execute(file, methods); err != nil {
+			return
+		}
+		if err = roundTripTmpl.Execute(file, roundTrips); err != nil {
+			return
+		}
+		err = g.generateContractTest(res, outDir, imports)
 		return
 	})
 }
 
+// createRoundTripMethod builds the data used to render the round trip helper of the given action.
+// The helper uses the action primary route.
+func (g *Generator) createRoundTripMethod(resource *design.ResourceDefinition, action *design.ActionDefinition) *RoundTripMethod {
+	var (
+		actionName = codegen.Goify(action.Name, true)
+		ctrlName   = codegen.Goify(resource.Name, true)
+		route      = action.Routes[0]
+		path       = pathParams(action, route)
+		query      = queryParams(action)
+		header     = headers(action)
+		payload    *ObjectType
+		responses  []*RoundTripResponse
+	)
+	if action.Payload != nil && !action.WebSocket() {
+		payload = &ObjectType{
+			Name: "payload",
+			Type: fmt.Sprintf("%s.%s", g.Target, codegen.Goify(action.Payload.TypeName, true)),
+		}
+		if !action.Payload.IsPrimitive() && !action.Payload.IsArray() && !action.Payload.IsHash() {
+			payload.Pointer = "*"
+		}
+	}
+	action.IterateResponses(func(response *design.ResponseDefinition) error {
+		mediaType := design.Design.MediaTypeWithIdentifier(response.MediaType)
+		if mediaType == nil {
+			return nil
+		}
+		typ := &ObjectType{Type: "goa.ErrorResponse", Pointer: "*"}
+		if !mediaType.IsError() {
+			view := response.ViewName
+			if view == "" {
+				view = design.DefaultView
+			}
+			p, _, err := mediaType.Project(view)
+			if err != nil {
+				return nil
+			}
+			typ.Type = fmt.Sprintf("%s.%s", g.Target, codegen.GoTypeName(p, nil, 0, false))
+			typ.Pointer = ""
+			if p.IsObject() {
+				typ.Pointer = "*"
+			}
+			validate := g.validator.Code(p.AttributeDefinition, false, false, false, "payload", "raw", 1, false)
+			typ.Validatable = validate != ""
+		}
+		responses = append(responses, &RoundTripResponse{Status: response.Status, Type: typ})
+		return nil
+	})
+	name := fmt.Sprintf("%s%sRoundTrip", actionName, ctrlName)
+	if action.WebSocket() {
+		name = fmt.Sprintf("%s%sWebSocket", actionName, ctrlName)
+	}
+
+	return &RoundTripMethod{
+		TestMethod: &TestMethod{
+			Name:           name,
+			ActionName:     actionName,
+			ResourceName:   ctrlName,
+			ControllerName: fmt.Sprintf("%s.%sController", g.Target, ctrlName),
+			RouteVerb:      route.Verb,
+			FullPath:       goPathFormat(route.FullPath()),
+			Params:         path,
+			QueryParams:    query,
+			Headers:        header,
+			Payload:        payload,
+			reservedNames:  reservedNames(path, query, header, payload, nil),
+		},
+		MountName: fmt.Sprintf("%s.Mount%sController", g.Target, ctrlName),
+		WebSocket: action.WebSocket(),
+		Responses: responses,
+	}
+}
+
 func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action *design.ActionDefinition,
 	response *design.ResponseDefinition, route *design.RouteDefinition, routeIndex int,
 	mediaType *design.MediaTypeDefinition, view *design.ViewDefinition) *TestMethod {
@@ -206,7 +313,7 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 
 	path = pathParams(action, route)
 	query = queryParams(action)
-	header = headers(action, resource.Headers)
+	header = headers(action)
 
 	if action.Payload != nil {
 		payload = &ObjectType{}
@@ -250,22 +357,8 @@ func pathParams(action *design.ActionDefinition, route *design.RouteDefinition)
 
 // headers builds the template data structure needed to proprely render the code
 // for setting the headers for the given action.
-func headers(action *design.ActionDefinition, headers *design.AttributeDefinition) []*ObjectType {
-	hds := &design.AttributeDefinition{
-		Type: design.Object{},
-	}
-	if headers != nil {
-		hds.Merge(headers)
-		hds.Validation = headers.Validation
-	}
-	if action.Headers != nil {
-		hds.Merge(action.Headers)
-		hds.Validation = action.Headers.Validation
-	}
-
-	if hds == nil {
-		return nil
-	}
+func headers(action *design.ActionDefinition) []*ObjectType {
+	hds := action.AllHeaders()
 	var headrs []string
 	for header := range hds.Type.ToObject() {
 		headrs = append(headrs, header)
@@ -292,9 +385,10 @@ func queryParams(action *design.ActionDefinition) []*ObjectType {
 }
 
 func paramFromNames(action *design.ActionDefinition, names []string) (params []*ObjectType) {
-	obj := action.Params.Type.ToObject()
+	all := action.AllParams()
+	obj := all.Type.ToObject()
 	for _, name := range names {
-		params = append(params, attToObject(name, action.Params, obj[name]))
+		params = append(params, attToObject(name, all, obj[name]))
 	}
 	return
 }
@@ -324,6 +418,7 @@ func attToObject(name string, parent, att *design.AttributeDefinition) *ObjectTy
 	obj.Label = name
 	obj.Name = codegen.Goify(name, false)
 	obj.Type = codegen.GoTypeRef(att.Type, nil, 0, false)
+	obj.TimeFormat = att.TimeFormat()
 	if att.Type.IsPrimitive() && parent.IsPrimitivePointer(name) {
 		obj.Pointer = "*"
 	}
@@ -352,7 +447,13 @@ var convertParamTmpl = `{{ if eq .Type "string" }}		sliceVal := []string{ {{ if
 		for i, v := range {{ .Name }} {
 			sliceVal[i] = fmt.Sprintf("%v", v)
 		}{{/*
-*/}}{{ else if eq .Type "time.Time" }}		sliceVal := []string{ {{ if .Pointer }}(*{{ end }}{{ .Name }}{{ if .Pointer }}){{ end }}.Format(time.RFC3339)}{{/*
+*/}}{{ else if eq .Type "time.Time" }}{{ $v := .Name }}{{ if .Pointer }}{{ $v = printf "(*%s)" .Name }}{{ end }}{{/*
+*/}}{{ if eq .TimeFormat "unix" }}		sliceVal := []string{strconv.FormatInt({{ $v }}.Unix(), 10)}{{/*
+*/}}{{ else if eq .TimeFormat "unix-ms" }}		sliceVal := []string{strconv.FormatInt({{ $v }}.UnixNano()/int64(time.Millisecond), 10)}{{/*
+*/}}{{ else }}		sliceVal := []string{ {{ $v }}.Format(time.RFC3339)}{{ end }}{{/*
+*/}}{{ else if eq .Type "time.Duration" }}{{ $v := .Name }}{{ if .Pointer }}{{ $v = printf "(*%s)" .Name }}{{ end }}{{/*
+*/}}{{ if eq .TimeFormat "ms" }}		sliceVal := []string{strconv.FormatInt(int64({{ $v }}/time.Millisecond), 10)}{{/*
+*/}}{{ else }}		sliceVal := []string{ {{ $v }}.String()}{{ end }}{{/*
 */}}{{ else }}		sliceVal := []string{fmt.Sprintf("%v", {{ if .Pointer }}*{{ end }}{{ .Name }})}{{ end }}`
 
 var testTmpl = `{{ define "convertParam" }}` + convertParamTmpl + `{{ end }}` + `
@@ -461,3 +562,109 @@ func {{ $test.Name }}(t goatest.TInterface, ctx context.Context, service *goa.Se
 	return {{ $rw }}{{ if $test.ReturnType }}, mt{{ end }}
 }
 {{ end }}`
+
+var roundTripTmpl = `{{ define "convertParam" }}` + convertParamTmpl + `{{ end }}` + `
+{{ range $test := . }}{{/*
+*/}}{{ $logBuf := $test.Escape "logBuf" }}{{ $srv := $test.Escape "srv" }}{{ $query := $test.Escape "query" }}{{/*
+*/}}{{ $u := $test.Escape "u" }}{{ $body := $test.Escape "body" }}{{ $req := $test.Escape "req" }}{{/*
+*/}}{{ $resp := $test.Escape "resp" }}{{ $res := $test.Escape "res" }}{{ $mt := $test.Escape "mt" }}{{/*
+*/}}{{ $err := $test.Escape "err" }}{{ $ws := $test.Escape "ws" }}{{ $cfg := $test.Escape "cfg" }}
+{{ if $test.WebSocket }}// {{ $test.Name }} mounts the given controller on an in-process HTTP server and opens a
+// websocket connection to the {{ $test.ActionName }} action. The request goes through the service mux
+// and the generated parameter decoding and validation code before reaching the controller.
+// The returned function closes the connection and shuts down the server.
+// If service is nil then a default service is created, otherwise ctrl is mounted on it.
+{{ else }}// {{ $test.Name }} mounts the given controller on an in-process HTTP server and runs the
+// {{ $test.ActionName }} action by sending it an HTTP request. The payload is encoded with the service
+// encoder and the request goes through the service mux and the generated decoding and validation
+// code before reaching the controller.
+// It returns the HTTP response and its decoded body: a media type for success responses or a
+// *goa.ErrorResponse for error responses, nil if the response has no body.
+// If ctx is nil then context.Background() is used.
+// If service is nil then a default service is created, otherwise ctrl is mounted on it.
+{{ end }}func {{ $test.Name }}(t goatest.TInterface, {{ if not $test.WebSocket }}ctx context.Context, {{ end }}service *goa.Service, ctrl {{ $test.ControllerName}}{{/*
+*/}}{{ range $param := $test.Params }}, {{ $param.Name }} {{ $param.Pointer }}{{ $param.Type }}{{ end }}{{/*
+*/}}{{ range $param := $test.QueryParams }}, {{ $param.Name }} {{ $param.Pointer }}{{ $param.Type }}{{ end }}{{/*
+*/}}{{ range $header := $test.Headers }}, {{ $header.Name }} {{ $header.Pointer }}{{ $header.Type }}{{ end }}{{/*
+*/}}{{ if $test.Payload }}, {{ $test.Payload.Name }} {{ $test.Payload.Pointer }}{{ $test.Payload.Type }}{{ end }}){{/*
+*/}} {{ if $test.WebSocket }}(*websocket.Conn, func()){{ else }}(*http.Response, interface{}){{ end }} {
+	// Setup service
+	var {{ $logBuf }} bytes.Buffer
+	if service == nil {
+		service = goa.New("test")
+	}
+	service.WithLogger(goa.NewLogger(log.New(&{{ $logBuf }}, "", log.Ltime)))
+{{ if not $test.WebSocket }}	if ctx == nil {
+		ctx = context.Background()
+	}
+{{ end }}	{{ $test.MountName }}(service, ctrl)
+	{{ $srv }} := httptest.NewServer(service.Mux)
+
+	// Setup request
+{{ if $test.QueryParams}}	{{ $query }} := url.Values{}
+{{ range $param := $test.QueryParams }}{{ if $param.Pointer }}	if {{ $param.Name }} != nil {{ end }}{
+{{ template "convertParam" $param }}
+		{{ $query }}[{{ printf "%q" $param.Label }}] = sliceVal
+	}
+{{ end }}{{ end }}	{{ $u }} := &url.URL{
+		Path: fmt.Sprintf({{ printf "%q" $test.FullPath }}{{ range $param := $test.Params }}, {{ $param.Name }}{{ end }}),
+{{ if $test.QueryParams }}		RawQuery: {{ $query }}.Encode(),
+{{ end }}	}
+{{ if $test.WebSocket }}	{{ $cfg }}, {{ $err }} := websocket.NewConfig("ws"+strings.TrimPrefix({{ $srv }}.URL, "http")+{{ $u }}.String(), {{ $srv }}.URL)
+	if {{ $err }} != nil {
+		panic("invalid test " + {{ $err }}.Error()) // bug
+	}
+{{ range $header := $test.Headers }}{{ if $header.Pointer }}	if {{ $header.Name }} != nil {{ end }}{
+{{ template "convertParam" $header }}
+		{{ $cfg }}.Header[{{ printf "%q" $header.Label }}] = sliceVal
+	}
+{{ end }}
+	// Open connection
+	{{ $ws }}, {{ $err }} := websocket.DialConfig({{ $cfg }})
+	if {{ $err }} != nil {
+		{{ $srv }}.Close()
+		t.Fatalf("websocket connection failed: %s, logs:\n%s", {{ $err }}, {{ $logBuf }}.String())
+	}
+	return {{ $ws }}, func() {
+		{{ $ws }}.Close()
+		{{ $srv }}.Close()
+	}
+{{ else }}	defer {{ $srv }}.Close()
+	var {{ $body }} bytes.Buffer
+{{ if $test.Payload }}	if {{ $err }} := service.Encoder.Encode({{ $test.Payload.Name }}, &{{ $body }}, "*/*"); {{ $err }} != nil {
+		t.Fatalf("failed to encode payload: %s", {{ $err }})
+	}
+{{ end }}	{{ $req }}, {{ $err }} := http.NewRequest("{{ $test.RouteVerb }}", {{ $srv }}.URL+{{ $u }}.String(), &{{ $body }})
+	if {{ $err }} != nil {
+		panic("invalid test " + {{ $err }}.Error()) // bug
+	}
+{{ range $header := $test.Headers }}{{ if $header.Pointer }}	if {{ $header.Name }} != nil {{ end }}{
+{{ template "convertParam" $header }}
+		{{ $req }}.Header[{{ printf "%q" $header.Label }}] = sliceVal
+	}
+{{ end }}
+	// Perform request
+	{{ $resp }}, {{ $err }} := http.DefaultClient.Do({{ $req }}.WithContext(ctx))
+	if {{ $err }} != nil {
+		t.Fatalf("request failed: %s, logs:\n%s", {{ $err }}, {{ $logBuf }}.String())
+	}
+	defer {{ $resp }}.Body.Close()
+
+	// Decode response
+	var {{ $res }} interface{}
+{{ if $test.Responses }}	switch {{ $resp }}.StatusCode {
+{{ range $r := $test.Responses }}	case {{ $r.Status }}:
+		{{ if $r.Type.Pointer }}{{ $mt }} := new({{ $r.Type.Type }}){{ else }}var {{ $mt }} {{ $r.Type.Type }}{{ end }}
+		if {{ $err }} := service.Decoder.Decode({{ if not $r.Type.Pointer }}&{{ end }}{{ $mt }}, {{ $resp }}.Body, {{ $resp }}.Header.Get("Content-Type")); {{ $err }} != nil {
+			t.Fatalf("failed to decode response body: %s", {{ $err }})
+		}
+{{ if $r.Type.Validatable }}		if {{ $err }} := {{ $mt }}.Validate(); {{ $err }} != nil {
+			t.Errorf("invalid response media type: %s", {{ $err }})
+		}
+{{ end }}		{{ $res }} = {{ $mt }}
+{{ end }}	}
+{{ end }}
+	// Return results
+	return {{ $resp }}, {{ $res }}
+{{ end }}}
+{{ end }}`
@@ -185,7 +185,7 @@ var _ = Describe("Generate", func() {
 
 		It("does not call Validate on the resulting media type when it does not exist", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(8))
+			Ω(files).Should(HaveLen(9))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 
@@ -194,7 +194,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates the ActionRouteResponse test methods ", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(8))
+			Ω(files).Should(HaveLen(9))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 
@@ -257,6 +257,28 @@ var _ = Describe("Generate", func() {
 			Ω(content).Should(ContainSubstring(", payload app.CustomName)"))
 		})
 
+		It("generates round trip helpers", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(content).Should(ContainSubstring("func ShowFooRoundTrip(t goatest.TInterface, ctx context.Context, service *goa.Service, ctrl app.FooController, "))
+			Ω(content).Should(ContainSubstring("func GetFooRoundTrip(t goatest.TInterface, ctx context.Context, service *goa.Service, ctrl app.FooController, optionalResourceHeader *int, requiredResourceHeader string, payload app.CustomName) (*http.Response, interface{})"))
+			Ω(content).Should(ContainSubstring("app.MountFooController(service, ctrl)"))
+			Ω(content).Should(ContainSubstring(`service.Encoder.Encode(payload, &body, "*/*")`))
+			Ω(content).Should(ContainSubstring("mt := new(app.IntContainer)"))
+			Ω(content).Should(ContainSubstring("mt := new(goa.ErrorResponse)"))
+		})
+
+		It("generates contract tests", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_contract_test.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(content).Should(ContainSubstring("func TestFooContract(t *testing.T)"))
+			Ω(content).Should(ContainSubstring("type contractFooController struct"))
+			Ω(content).Should(ContainSubstring("app.MountFooController(service, ctrl)"))
+			Ω(content).Should(ContainSubstring(`t.Run("show"`))
+		})
+
 		It("generates header compliant with https://github.com/golang/go/issues/13560", func() {
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
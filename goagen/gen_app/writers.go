@@ -71,6 +71,17 @@ type (
 		Validator    *codegen.Validator
 	}
 
+	// AsyncWriter generate code for the messages published and consumed by the async actions.
+	AsyncWriter struct {
+		*codegen.SourceFile
+		Validator *codegen.Validator
+	}
+
+	// StreamsWriter generate code for the streams used by the actions that stream results.
+	StreamsWriter struct {
+		*codegen.SourceFile
+	}
+
 	// ContextTemplateData contains all the information used by the template to render the context
 	// code for an action.
 	ContextTemplateData struct {
@@ -85,18 +96,34 @@ type (
 		API          *design.APIDefinition
 		DefaultPkg   string
 		Security     *design.SecurityDefinition
+		// ViewParam is the name of the parameter that selects the view of the response
+		// named ViewedResponse if any.
+		ViewParam      string
+		ViewedResponse string
 	}
 
 	// ControllerTemplateData contains the information required to generate an action handler.
 	ControllerTemplateData struct {
-		API            *design.APIDefinition          // API definition
-		Resource       string                         // Lower case plural resource name, e.g. "bottles"
-		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "DesignName", "Routes", "Context" and "Unmarshal"
-		FileServers    []*design.FileServerDefinition // File servers
-		Encoders       []*EncoderTemplateData         // Encoder data
-		Decoders       []*EncoderTemplateData         // Decoder data
-		Origins        []*design.CORSDefinition       // CORS policies
-		PreflightPaths []string
+		API             *design.APIDefinition          // API definition
+		Resource        string                         // Lower case plural resource name, e.g. "bottles"
+		Actions         []map[string]interface{}       // Array of actions, each action has keys "Name", "DesignName", "Routes", "Context" and "Unmarshal", "NotFoundRoutes" if it handles unmatched paths and "Consumes" and "Produces" if it negotiates its media types
+		FileServers     []*design.FileServerDefinition // File servers
+		HealthCheck     *design.HealthCheckDefinition  // Health check endpoint if any
+		StaticActions   []map[string]interface{}       // Static actions, each action has keys "Name", "DesignName", "Routes", "Status", "ContentType", "Body" and "Security"
+		Encoders        []*EncoderTemplateData         // Encoder data
+		Decoders        []*EncoderTemplateData         // Decoder data
+		Origins         []*design.CORSDefinition       // CORS policies
+		PreflightPaths  []string
+		Compression     []string          // Compression algorithms enabled on the resource if any
+		ResponseHeaders map[string]string // Constant response headers indexed by name if any
+		StripPrefix     string            // Prefix stripped from the request paths if any
+		// InvalidRequestStatus is the status of the responses sent for invalid requests if not 400
+		InvalidRequestStatus int
+		// Mount is the name of the named mount whose routes the Mount function registers, empty
+		// for the default mount.
+		Mount string
+		// Mounts contains the data used to generate the Mount functions of the named mounts.
+		Mounts []*ControllerTemplateData
 	}
 
 	// ResourceData contains the information required to generate the resource GoGenerator
@@ -107,6 +134,42 @@ type (
 		Type              *design.MediaTypeDefinition // Type of resource media type
 		CanonicalTemplate string                      // CanonicalFormat represents the resource canonical path in the form of a fmt.Sprintf format.
 		CanonicalParams   []string                    // CanonicalParams is the list of parameter names that appear in the resource canonical path in order.
+		Links             []*LinkHrefData             // Links lists the links defined by the resource actions.
+	}
+
+	// LinkHrefData contains the information required to generate the href builder of an action
+	// link.
+	LinkHrefData struct {
+		Name     string   // Name of the href builder function suffix, e.g. "ShowBottleOwner"
+		Rel      string   // Rel is the link relation type
+		Action   string   // Action is the name of the action that defines the link
+		Target   string   // Target is the context of the action targeted by the link
+		Template string   // Template is the target path in the form of a fmt.Sprintf format.
+		Params   []string // Params is the list of parameter names that appear in the target path in order.
+	}
+
+	// AsyncActionTemplateData contains the information required to generate the functions that
+	// publish or consume the messages of an async action.
+	AsyncActionTemplateData struct {
+		Name         string                     // Name of the generated functions suffix, e.g. "CreatedOrder"
+		DesignName   string                     // Name of the async action, e.g. "created"
+		ResourceName string                     // Name of the resource, e.g. "order"
+		Description  string                     // Description of the async action
+		Operation    string                     // design.PublishOperation or design.SubscribeOperation
+		Channel      string                     // Name of the message broker channel
+		Message      *design.UserTypeDefinition // Message type
+		Inline       bool                       // Whether the message type is defined by the async action
+	}
+
+	// StreamTemplateData contains the information required to generate the stream used by an
+	// action that streams results.
+	StreamTemplateData struct {
+		Name            string                     // Name of the generated stream type prefix, e.g. "ImportTask"
+		ActionName      string                     // Name of the action, e.g. "import"
+		ResourceName    string                     // Name of the resource, e.g. "task"
+		Transport       string                     // design.WebSocketStream, design.NDJSONStream or design.SSEStream
+		StreamingResult *design.UserTypeDefinition // Type of the streamed results
+		Result          *design.UserTypeDefinition // Type of the final result if any
 	}
 
 	// EncoderTemplateData contains the data needed to render the registration code for a single
@@ -210,13 +273,15 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 		return err
 	}
 	fn := template.FuncMap{
-		"newCoerceData":      newCoerceData,
-		"arrayAttribute":     arrayAttribute,
-		"printVal":           codegen.PrintVal,
-		"canonicalHeaderKey": http.CanonicalHeaderKey,
-		"isPathParam":        data.IsPathParam,
-		"valueTypeOf":        valueTypeOf,
-		"fromString":         fromString,
+		"newCoerceData":       newCoerceData,
+		"newHeaderCoerceData": newHeaderCoerceData,
+		"arrayAttribute":      arrayAttribute,
+		"printVal":            codegen.PrintVal,
+		"canonicalHeaderKey":  http.CanonicalHeaderKey,
+		"isPathParam":         data.IsPathParam,
+		"valueTypeOf":         valueTypeOf,
+		"fromString":          fromString,
+		"viewHeader":          func() string { return design.ViewHeader },
 	}
 	if err := w.ExecuteTemplate("new", ctxNewT, fn, data); err != nil {
 		return err
@@ -241,15 +306,17 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 	}
 	return data.IterateResponses(func(resp *design.ResponseDefinition) error {
 		respData := map[string]interface{}{
-			"Context":  data,
-			"Response": resp,
+			"Context":       data,
+			"Response":      resp,
+			"StaticHeaders": resp.StaticHeaders(),
 		}
 		var mt *design.MediaTypeDefinition
 		if resp.Type != nil {
 			var ok bool
 			if mt, ok = resp.Type.(*design.MediaTypeDefinition); !ok {
 				respData["Type"] = resp.Type
-				respData["ContentType"] = resp.MediaType
+				respData["ContentType"] = resp.BodyContentType()
+				respData["Binary"] = resp.Binary()
 				return w.ExecuteTemplate("response", ctxTRespT, nil, respData)
 			}
 		} else {
@@ -268,6 +335,8 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 				}
 				sort.Strings(views)
 			}
+			viewed := data.ViewParam != "" && resp.Name == data.ViewedResponse
+			var viewsData []map[string]interface{}
 			for _, view := range views {
 				projected, _, err := mt.Project(view)
 				if err != nil {
@@ -286,6 +355,23 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 				if err := w.ExecuteTemplate("response", ctxMTRespT, fn, respData); err != nil {
 					return err
 				}
+				if viewed {
+					viewsData = append(viewsData, map[string]interface{}{
+						"Name":      view,
+						"RespName":  respData["RespName"],
+						"Projected": projected,
+					})
+				}
+			}
+			if viewed {
+				viewData := map[string]interface{}{
+					"Context":  data,
+					"Response": resp,
+					"RespName": codegen.Goify(resp.Name, true),
+					"Field":    codegen.GoifyAtt(data.Params.Type.ToObject()[data.ViewParam], data.ViewParam, true),
+					"Views":    viewsData,
+				}
+				return w.ExecuteTemplate("viewResponse", ctxViewRespT, nil, viewData)
 			}
 			return nil
 		}
@@ -314,6 +400,14 @@ func (w *ControllersWriter) WriteInitService(encoders, decoders []*EncoderTempla
 		"Encoders": encoders,
 		"Decoders": decoders,
 	}
+	switch design.Design.TrailingSlash {
+	case design.TrailingSlashRedirect:
+		ctx["TrailingSlashPolicy"] = "goa.TrailingSlashRedirect"
+	case design.TrailingSlashStrict:
+		ctx["TrailingSlashPolicy"] = "goa.TrailingSlashStrict"
+	case design.TrailingSlashIgnore:
+		ctx["TrailingSlashPolicy"] = "goa.TrailingSlashIgnore"
+	}
 	return w.ExecuteTemplate("service", serviceT, nil, ctx)
 }
 
@@ -326,21 +420,38 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 		if err := w.ExecuteTemplate("controller", ctrlT, nil, d); err != nil {
 			return err
 		}
-		if err := w.ExecuteTemplate("mount", mountT, nil, d); err != nil {
+		if err := w.ExecuteTemplate("mount", mountT, template.FuncMap{"scopeExprCode": scopeExprCode}, d); err != nil {
 			return err
 		}
+		for _, m := range d.Mounts {
+			if err := w.ExecuteTemplate("mount", mountT, template.FuncMap{"scopeExprCode": scopeExprCode}, m); err != nil {
+				return err
+			}
+		}
+		if d.StripPrefix != "" {
+			if err := w.ExecuteTemplate("stripPrefix", stripPrefixT, nil, d); err != nil {
+				return err
+			}
+		}
 		if len(d.Origins) > 0 {
 			if err := w.ExecuteTemplate("handleCORS", handleCORST, nil, d); err != nil {
 				return err
 			}
 		}
+		if len(d.ResponseHeaders) > 0 {
+			if err := w.ExecuteTemplate("handleResponseHeaders", handleResponseHeadersT, nil, d); err != nil {
+				return err
+			}
+		}
 		fn := template.FuncMap{
-			"newCoerceData":  newCoerceData,
-			"finalizeCode":   w.Finalizer.Code,
-			"arrayAttribute": arrayAttribute,
-			"validationCode": w.Validator.Code,
-			"valueTypeOf":    valueTypeOf,
-			"fromString":     fromString,
+			"newCoerceData":        newCoerceData,
+			"newPayloadCoerceData": newPayloadCoerceData,
+			"finalizeCode":         w.Finalizer.Code,
+			"arrayAttribute":       arrayAttribute,
+			"validationCode":       w.Validator.Code,
+			"valueTypeOf":          valueTypeOf,
+			"fromString":           fromString,
+			"isText":               design.IsText,
 		}
 		if err := w.ExecuteTemplate("unmarshal", unmarshalT, fn, d); err != nil {
 			return err
@@ -349,6 +460,12 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 	return nil
 }
 
+// WriteHealthCheck writes the MountHealthCheck function which mounts the API level health check
+// endpoint.
+func (w *ControllersWriter) WriteHealthCheck(hc *design.HealthCheckDefinition) error {
+	return w.ExecuteTemplate("healthCheck", healthCheckT, nil, hc)
+}
+
 // NewSecurityWriter returns a security functionality code writer.
 // Those functionalities are there to support action-middleware related to security.
 func NewSecurityWriter(filename string) (*SecurityWriter, error) {
@@ -364,6 +481,55 @@ func (w *SecurityWriter) Execute(schemes []*design.SecuritySchemeDefinition) err
 	return w.ExecuteTemplate("security_schemes", securitySchemesT, nil, schemes)
 }
 
+// NewAsyncWriter returns an async actions code writer.
+func NewAsyncWriter(filename string) (*AsyncWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &AsyncWriter{SourceFile: file, Validator: codegen.NewValidator()}, nil
+}
+
+// Execute writes the message types defined inline by the async actions, the Publisher and
+// Subscriber interfaces and the functions that publish or consume the messages.
+func (w *AsyncWriter) Execute(actions []*AsyncActionTemplateData) error {
+	var hasPublish, hasSubscribe bool
+	for _, a := range actions {
+		if a.Operation == design.PublishOperation {
+			hasPublish = true
+		} else {
+			hasSubscribe = true
+		}
+	}
+	validate := func(ut *design.UserTypeDefinition) bool {
+		return w.Validator.Code(ut.AttributeDefinition, false, false, false, "ut", "message", 1, false) != ""
+	}
+	fn := template.FuncMap{
+		"validationCode": w.Validator.Code,
+		"validate":       validate,
+	}
+	data := map[string]interface{}{
+		"Actions":      actions,
+		"HasPublish":   hasPublish,
+		"HasSubscribe": hasSubscribe,
+	}
+	return w.ExecuteTemplate("async", asyncT, fn, data)
+}
+
+// NewStreamsWriter returns a streams code writer.
+func NewStreamsWriter(filename string) (*StreamsWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamsWriter{SourceFile: file}, nil
+}
+
+// Execute writes the stream types used by the actions to send their results.
+func (w *StreamsWriter) Execute(streams []*StreamTemplateData) error {
+	return w.ExecuteTemplate("streams", streamsT, nil, streams)
+}
+
 // NewResourcesWriter returns a contexts code writer.
 // Resources provide the glue between the underlying request data and the user controller.
 func NewResourcesWriter(filename string) (*ResourcesWriter, error) {
@@ -448,7 +614,43 @@ func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, p
 		"Attribute": att,
 		"Pkg":       pkg,
 		"Depth":     depth,
+		"Parse":     parseTimeCode(att, "raw"+codegen.Goify(name, true)),
+	}
+}
+
+// parseTimeCode returns the Go code that parses the DateTime or Duration value held by the string
+// variable varName according to the time format of att. It returns the empty string for
+// attributes of other types.
+func parseTimeCode(att *design.AttributeDefinition, varName string) string {
+	switch att.TimeFormat() {
+	case design.TimeFormatRFC3339:
+		return "time.Parse(time.RFC3339, " + varName + ")"
+	case design.TimeFormatUnix:
+		return "goa.ParseUnixTime(" + varName + ")"
+	case design.TimeFormatUnixMilli:
+		return "goa.ParseUnixMilliTime(" + varName + ")"
+	case design.DurationFormatString:
+		return "time.ParseDuration(" + varName + ")"
+	case design.DurationFormatMilli:
+		return "goa.ParseMilliDuration(" + varName + ")"
 	}
+	return ""
+}
+
+// newHeaderCoerceData is newCoerceData for header values, header date times may also use the
+// HTTP-date format (RFC 7231 section 7.1.1.1).
+func newHeaderCoerceData(name string, att *design.AttributeDefinition, pointer bool, pkg string, depth int) map[string]interface{} {
+	data := newCoerceData(name, att, pointer, pkg, depth)
+	data["Header"] = true
+	return data
+}
+
+// newPayloadCoerceData is newCoerceData for the fields of payload structs, the fields of the
+// DateTime and Duration attributes may use the goa types that encode their values.
+func newPayloadCoerceData(name string, att *design.AttributeDefinition, pointer bool, pkg string, depth int) map[string]interface{} {
+	data := newCoerceData(name, att, pointer, pkg, depth)
+	data["TimeType"] = codegen.GoTimeType(att)
+	return data
 }
 
 // arrayAttribute returns the array element attribute definition.
@@ -499,6 +701,26 @@ func fromString(att *design.AttributeDefinition, varName string) string {
 	return "(" + valueTypeOf("", att) + ")(nil), (error)(nil)"
 }
 
+// scopeExprCode returns the Go boolean expression that evaluates the scope expression against the
+// scopes map, e.g. `scopes["admin"] || (scopes["editor"] && scopes["org:member"])`.
+func scopeExprCode(e *design.ScopeExpr) string {
+	if e.Operator == design.ScopeLeaf {
+		return fmt.Sprintf("scopes[%q]", e.Scope)
+	}
+	op := " && "
+	if e.Operator == design.ScopeOr {
+		op = " || "
+	}
+	elems := make([]string, len(e.Operands))
+	for i, o := range e.Operands {
+		elems[i] = scopeExprCode(o)
+		if o.Operator != design.ScopeLeaf {
+			elems[i] = "(" + elems[i] + ")"
+		}
+	}
+	return strings.Join(elems, op)
+}
+
 const (
 	// ctxT generates the code for the context data type.
 	// template input: *ContextTemplateData
@@ -557,9 +779,14 @@ type {{ .Name }} struct {
 
 */}}{{/* DateTimeType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
-*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := time.Parse(time.RFC3339, raw{{ goify .Name true }}); err2 == nil {
-{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
-{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+*/}}{{ if and .Header (eq .Attribute.TimeFormat "rfc3339") }}{{ tabs .Depth }}{{ .VarName }}, err2 := {{ .Parse }}
+{{ tabs .Depth }}if err2 != nil {
+{{ tabs .Depth }}	{{ .VarName }}, err2 = http.ParseTime(raw{{ goify .Name true }})
+{{ tabs .Depth }}}
+{{ tabs .Depth }}if err2 == nil {
+{{ else }}{{ tabs .Depth }}if {{ .VarName }}, err2 := {{ .Parse }}; err2 == nil {
+{{ end }}{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ with .TimeType }}{{ . }}{ {{ $.VarName }} }{{ else }}{{ .VarName }}{{ end }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ if and .TimeType (not .Pointer) }}{{ .TimeType }}{ {{ $varName }} }{{ else }}{{ $varName }}{{ end }}
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "datetime"))
 {{ tabs .Depth }}}
@@ -603,6 +830,16 @@ type {{ .Name }} struct {
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", "{{ .Name }}", "file"))
 {{ tabs .Depth }}}
+{{ else if eq .Attribute.Type.Kind 14 }}{{/*
+
+*/}}{{/* DurationType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := {{ .Parse }}; err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ with .TimeType }}{{ . }}{ {{ $.VarName }} }{{ else }}{{ .VarName }}{{ end }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ if and .TimeType (not .Pointer) }}{{ .TimeType }}{ {{ $varName }} }{{ else }}{{ $varName }}{{ end }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "duration"))
+{{ tabs .Depth }}}
 {{ end }}`
 
 	// ctxNewT generates the code for the context factory method.
@@ -623,22 +860,33 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 		err = goa.MergeErrors(err, goa.MissingHeaderError("{{ $name }}"))
 	} else {
 {{ else }}	if len(header{{ goify $name true }}) > 0 {
-{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}		req.Params["{{ $name }}"] = header{{ goify $name true }}
-{{ if eq (arrayAttribute $att).Type.Kind 4 }}		headers := header{{ goify $name true }}
-{{ else }}		headers := make({{ gotypedef $att 2 true false }}, len(header{{ goify $name true }}))
-		for i, raw{{ goify $name true}} := range header{{ goify $name true}} {
-{{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Headers.IsPrimitivePointer $name) "headers[i]" 3) }}{{/*
+{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}		var values{{ goify $name true }} []string
+		for _, h := range header{{ goify $name true }} {
+			for _, v := range strings.Split(h, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					values{{ goify $name true }} = append(values{{ goify $name true }}, v)
+				}
+			}
+		}
+		req.Params["{{ $name }}"] = values{{ goify $name true }}
+{{ if eq (arrayAttribute $att).Type.Kind 4 }}		headers := values{{ goify $name true }}
+{{ else }}		headers := make({{ gotyperef $att.Type nil 2 false }}, len(values{{ goify $name true }}))
+		for i, raw{{ goify $name true}} := range values{{ goify $name true}} {
+{{ template "Coerce" (newHeaderCoerceData $name (arrayAttribute $att) ($.Headers.IsPrimitivePointer $name) "headers[i]" 3) }}{{/*
 */}}		}
 {{ end }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = headers
 {{ else }}		raw{{ goify $name true}} := header{{ goify $name true}}[0]
 		req.Params["{{ $name }}"] = []string{raw{{ goify $name true }}}
-{{ template "Coerce" (newCoerceData $name $att ($.Headers.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
+{{ template "Coerce" (newHeaderCoerceData $name $att ($.Headers.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
 */}}{{ $validation := validationChecker $att ($.Headers.IsNonZero $name) ($.Headers.IsRequired $name) ($.Headers.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}
 {{ end }}	}
-{{ end }}{{ end }}{{/* if .Headers }}{{/*
-
-*/}}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
+{{ end }}{{ end }}{{/* if .Headers */}}{{ with .ViewParam }}	if len(req.Params[{{ printf "%q" . }}]) == 0 {
+		if view := req.Header.Get({{ printf "%q" viewHeader }}); view != "" {
+			req.Params[{{ printf "%q" . }}] = []string{view}
+		}
+	}
+{{ end }}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
 */}}	param{{ goify $name true }} := req.Params["{{ $name }}"]
 {{ $mustValidate := $.MustValidate $name }}{{ if $mustValidate }}	if len(param{{ goify $name true }}) == 0 {
 		{{ if $.Params.HasDefaultValue $name }}{{printf "rctx.%s" (goifyatt $att $name true) }} = {{ printVal $att.Type $att.DefaultValue }}{{else}}{{/*
@@ -649,7 +897,7 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 	} else {
 {{ else }}	if len(param{{ goify $name true }}) > 0 {
 {{ end }}{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ if eq (arrayAttribute $att).Type.Kind 4 }}		params := param{{ goify $name true }}
-{{ else }}		params := make({{ gotypedef $att 2 true false }}, len(param{{ goify $name true }}))
+{{ else }}		params := make({{ gotyperef $att.Type nil 2 false }}, len(param{{ goify $name true }}))
 		for i, raw{{ goify $name true}} := range param{{ goify $name true}} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Params.IsPrimitivePointer $name) "params[i]" 3) }}{{/*
 */}}		}
@@ -662,7 +910,11 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 	}{{ end }}{{/*
 */}}{{ else }}{{ $validation := validationChecker $att ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}{{ end }}{{ end }}	}
-{{ end }}{{ end }}{{/* if .Params */}}	return &rctx, err
+{{ end }}{{ end }}{{/* if .Params */}}{{/*
+*/}}{{ with .Headers }}{{ with .Validation }}{{ range .Together }}	err = goa.MergeErrors(err, goa.ValidateTogether("request headers", []string{ {{- range $i, $n := . }}{{ if $i }}, {{ end }}{{ printf "%q" $n }}{{ end }}}, []bool{ {{- range $i, $n := . }}{{ if $i }}, {{ end }}len(header{{ goify $n true }}) > 0{{ end }}}))
+{{ end }}{{ end }}{{ end }}{{/*
+*/}}{{ with .Params }}{{ with .Validation }}{{ range .Together }}	err = goa.MergeErrors(err, goa.ValidateTogether("request parameters", []string{ {{- range $i, $n := . }}{{ if $i }}, {{ end }}{{ printf "%q" $n }}{{ end }}}, []bool{ {{- range $i, $n := . }}{{ if $i }}, {{ end }}len(param{{ goify $n true }}) > 0{{ end }}}))
+{{ end }}{{ end }}{{ end }}	return &rctx, err
 }
 `
 
@@ -673,11 +925,30 @@ func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}(r {{ gotyperef .Proje
 	if ctx.ResponseData.Header().Get("Content-Type") == "" {
 		ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
 	}
-{{ if .Projected.Type.IsArray }}	if r == nil {
+{{ range $name, $value := .StaticHeaders }}	if ctx.ResponseData.Header().Get({{ printf "%q" $name }}) == "" {
+		ctx.ResponseData.Header().Set({{ printf "%q" $name }}, {{ printf "%q" $value }})
+	}
+{{ end }}{{ if .Projected.Type.IsArray }}	if r == nil {
 		r = {{ gotyperef .Projected .Projected.AllRequired 0 false }}{}
 	}
 {{ end }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
+`
+
+	// ctxViewRespT generates the response helper that renders the response with the view
+	// selected by the client.
+	// template input: map[string]interface{}
+	ctxViewRespT = `// {{ .RespName }}View sends a HTTP response with status code {{ .Response.Status }} rendered with the view selected
+// by the client, r must be the value returned by the response helper of that view.
+func (ctx *{{ .Context.Name }}) {{ .RespName }}View(r interface{}) error {
+	switch ctx.{{ .Field }} {
+{{ range .Views }}	case {{ printf "%q" .Name }}:
+		if v, ok := r.({{ gotyperef .Projected .Projected.AllRequired 0 false }}); ok {
+			return ctx.{{ .RespName }}(v)
+		}
+{{ end }}	}
+	return fmt.Errorf("cannot render %T with view %q", r, ctx.{{ .Field }})
+}
 `
 
 	// ctxTRespT generates the response helpers for responses with overridden types.
@@ -687,8 +958,17 @@ func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}(r {{ gotyperef .
 	if ctx.ResponseData.Header().Get("Content-Type") == "" {
 		ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
 	}
-	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
-}
+{{ range $name, $value := .StaticHeaders }}	if ctx.ResponseData.Header().Get({{ printf "%q" $name }}) == "" {
+		ctx.ResponseData.Header().Set({{ printf "%q" $name }}, {{ printf "%q" $value }})
+	}
+{{ end }}{{ if .Binary }}	ctx.ResponseData.WriteHeader({{ .Response.Status }})
+	if !goa.ResponseHasBody(ctx.RequestData, {{ .Response.Status }}) {
+		return nil
+	}
+	_, err := ctx.ResponseData.Write(r)
+	return err
+{{ else }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ end }}}
 `
 
 	// ctxNoMTRespT generates the response helpers for responses with no known media type.
@@ -699,6 +979,9 @@ func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}({{ if .Response.
 {{ if .Response.MediaType }}	if ctx.ResponseData.Header().Get("Content-Type") == "" {
 		ctx.ResponseData.Header().Set("Content-Type", "{{ .Response.MediaType }}")
 	}
+{{ end }}{{ range $name, $value := .StaticHeaders }}	if ctx.ResponseData.Header().Get({{ printf "%q" $name }}) == "" {
+		ctx.ResponseData.Header().Set({{ printf "%q" $name }}, {{ printf "%q" $value }})
+	}
 {{ end }}	ctx.ResponseData.WriteHeader({{ .Response.Status }}){{ if .Response.MediaType }}
 	_, err := ctx.ResponseData.Write(resp)
 	return err{{ else }}
@@ -728,6 +1011,19 @@ func (payload {{ gotyperef .Payload .Payload.AllRequired 0 true }}) Publicize()
 	var pub {{ $typeName }}
 	{{ recursivePublicizer .Payload.AttributeDefinition "payload" "pub" 1 }}
 	return &pub
+}
+
+// Validate{{ $typeName }} validates the JSON encoded {{ .ResourceName }} {{ .ActionName }} action
+// payload against the design, it lets code outside of the service such as queue consumers validate
+// the same messages. Use goa.Violations to list the violations described by the returned error.
+func Validate{{ $typeName }}(body []byte) error {
+	payload := &{{ $privateTypeName }}{}
+	if err := json.Unmarshal(body, payload); err != nil {
+		return goa.ErrBadRequest(err)
+	}{{ if $assignment }}
+	payload.Finalize(){{ end }}{{ if $validation }}
+	return payload.Validate(){{ else }}
+	return nil{{ end }}
 }{{ end }}
 
 // {{ gotypename .Payload nil 0 false }} is the {{ .ResourceName }} {{ .ActionName }} action payload.
@@ -752,6 +1048,11 @@ type {{ .Resource }}Controller interface {
 	// serviceT generates the service initialization code.
 	// template input: *ControllerTemplateData
 	serviceT = `
+// PanicHandler handles the panics raised by the controller actions, it logs the stack trace and
+// returns an internal error by default. Override it prior to mounting the controllers to report
+// panics to an error tracking service.
+var PanicHandler goa.PanicHandler = goa.DefaultPanicHandler
+
 // initService sets up the service encoders, decoders and mux.
 func initService(service *goa.Service) {
 	// Setup encoders and decoders
@@ -766,14 +1067,19 @@ func initService(service *goa.Service) {
 */}}	service.Encoder.Register({{ .PackageName }}.{{ .Function }}, "*/*")
 {{ end }}{{ end }}{{ range .Decoders }}{{ if .Default }}{{/*
 */}}	service.Decoder.Register({{ .PackageName }}.{{ .Function }}, "*/*")
-{{ end }}{{ end }}}
+{{ end }}{{ end }}{{ with .TrailingSlashPolicy }}
+	// Setup trailing slash policy
+	goa.SetTrailingSlashPolicy(service.Mux, {{ . }})
+{{ end }}}
 `
 
 	// mountT generates the code for a resource "Mount" function.
 	// template input: *ControllerTemplateData
 	mountT = `
-// Mount{{ .Resource }}Controller "mounts" a {{ .Resource }} resource controller on the given service.
-func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Controller) {
+{{ $mount := goify .Mount true }}{{/*
+*/}}// Mount{{ .Resource }}{{ $mount }}Controller "mounts" a {{ .Resource }} resource controller on the given service{{ with .Mount }}
+// under its {{ printf "%q" . }} mount{{ end }}.
+func Mount{{ .Resource }}{{ $mount }}Controller(service *goa.Service, ctrl {{ .Resource }}Controller) {
 	initService(service)
 	var h goa.Handler
 {{ $res := .Resource }}{{ if .Origins }}{{ range .PreflightPaths }}{{/*
@@ -797,17 +1103,82 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 {{ end }}		}
 {{ end }}		return ctrl.{{ .Name }}(rctx)
 	}
-{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
-{{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
-{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.DesignName }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
+	h = goa.{{ if .WebSocket }}HandleWebSocketPanics{{ else }}HandlePanics{{ end }}(PanicHandler)(h)
+{{ with $.InvalidRequestStatus }}	h = goa.InvalidRequestStatus({{ . }})(h)
+{{ end }}{{ with .Timeout }}	h = middleware.Timeout({{ . }})(h)
+{{ end }}{{ with .Produces }}	h = goa.Negotiate({{ range $i, $m := . }}{{ if $i }}, {{ end }}{{ printf "%q" $m }}{{ end }})(h)
+{{ end }}{{ if $.Compression }}	h = compress.Middleware({{ range $i, $a := $.Compression }}{{ if $i }}, {{ end }}{{ printf "%q" $a }}{{ end }})(h)
+{{ end }}{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
+{{ with .Security.ScopeExpr }}	h = handleScopeExpr(h, func(scopes map[string]bool) bool { return {{ scopeExprCode . }} })
+{{ end }}{{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
+{{ end }}{{ if $.ResponseHeaders }}	h = handle{{ $res }}ResponseHeaders(h)
+{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .MuxPath }}, {{ with $action.Signature }}goa.VerifySignature(service, {{ printf "%q" .Header }}, {{ printf "%q" .Algorithm }}, {{ $action.SignatureErrorStatus }}, {{ end }}ctrl.MuxHandler({{ printf "%q" $action.DesignName }}, h, {{ if $action.Payload }}{{ if $action.Consumes }}goa.NegotiateContentType({{ end }}{{ if $.Compression }}compress.Decode({{ $action.Unmarshal }}{{ range $.Compression }}, {{ printf "%q" . }}{{ end }}){{ else }}{{ $action.Unmarshal }}{{ end }}{{ range $action.Consumes }}, {{ printf "%q" . }}{{ end }}{{ if $action.Consumes }}){{ end }}{{ else }}nil{{ end }}){{ if $action.Signature }}){{ end }})
 	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ with .NotFoundRoutes }}{{ range . }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .MuxPath }}, {{ with $action.Signature }}goa.VerifySignature(service, {{ printf "%q" .Header }}, {{ printf "%q" .Algorithm }}, {{ $action.SignatureErrorStatus }}, {{ end }}ctrl.MuxHandler({{ printf "%q" $action.DesignName }}, h, {{ if $action.Payload }}{{ if $action.Consumes }}goa.NegotiateContentType({{ end }}{{ if $.Compression }}compress.Decode({{ $action.Unmarshal }}{{ range $.Compression }}, {{ printf "%q" . }}{{ end }}){{ else }}{{ $action.Unmarshal }}{{ end }}{{ range $action.Consumes }}, {{ printf "%q" . }}{{ end }}{{ if $action.Consumes }}){{ end }}{{ else }}nil{{ end }}){{ if $action.Signature }}){{ end }})
+{{ end }}	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "* %s" (index . 0).FullPath) }}, "fallback", true)
 {{ end }}{{ end }}{{ range .FileServers }}
-	h = ctrl.FileHandler({{ printf "%q" .RequestPath }}, {{ printf "%q" .FilePath }})
-{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
-{{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
-{{ end }}	service.Mux.Handle("GET", "{{ .RequestPath }}", ctrl.MuxHandler("serve", h, nil))
+	h = ctrl.FileHandler({{ printf "%q" .MuxPath }}, {{ printf "%q" .FilePath }})
+{{ if or .ETag .MaxAge }}	h = goa.FileCaching({{ .ETag }}, {{ with .MaxAge }}{{ . }}{{ else }}-1{{ end }})(h)
+{{ end }}{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
+{{ with .Security.ScopeExpr }}	h = handleScopeExpr(h, func(scopes map[string]bool) bool { return {{ scopeExprCode . }} })
+{{ end }}{{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
+{{ end }}{{ if $.ResponseHeaders }}	h = handle{{ $res }}ResponseHeaders(h)
+{{ end }}	service.Mux.Handle("GET", "{{ .MuxPath }}", ctrl.MuxHandler("serve", h, nil))
 	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "files", {{ printf "%q" .FilePath }}, "route", {{ printf "%q" (printf "GET %s" .RequestPath) }}{{ with .Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
-{{ end }}}
+{{ end }}{{ with .HealthCheck }}
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		rw.WriteHeader(http.StatusOK)
+		_, err := rw.Write([]byte({{ printf "%q" .Body }}))
+		return err
+	}
+{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
+{{ end }}{{ if $.ResponseHeaders }}	h = handle{{ $res }}ResponseHeaders(h)
+{{ end }}	service.Mux.Handle("GET", {{ printf "%q" .MuxPath }}, ctrl.MuxHandler("health", h, nil))
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", "Health", "route", {{ printf "%q" (printf "GET %s" .FullPath) }})
+{{ end }}{{ range .StaticActions }}{{ $action := . }}
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+{{ if .Body }}{{ with .ContentType }}		rw.Header().Set("Content-Type", {{ printf "%q" . }})
+{{ end }}{{ end }}		rw.WriteHeader({{ .Status }})
+{{ if .Body }}		if !goa.ResponseHasBody(goa.ContextRequest(ctx), {{ .Status }}) {
+			return nil
+		}
+		_, err := rw.Write([]byte({{ printf "%q" .Body }}))
+		return err
+{{ else }}		return nil
+{{ end }}	}
+{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
+{{ with .Security.ScopeExpr }}	h = handleScopeExpr(h, func(scopes map[string]bool) bool { return {{ scopeExprCode . }} })
+{{ end }}{{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
+{{ end }}{{ if $.ResponseHeaders }}	h = handle{{ $res }}ResponseHeaders(h)
+{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .MuxPath }}, ctrl.MuxHandler({{ printf "%q" $action.DesignName }}, h, nil))
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}, "static", true)
+{{ end }}{{ end }}}
+`
+
+	// stripPrefixT generates the handler of the resources that strip their base path.
+	// template input: *ControllerTemplateData
+	stripPrefixT = `
+// {{ .Resource }}Handler returns the handler used by external routers to dispatch the requests
+// made to {{ .StripPrefix }} to the service. It strips the prefix from the request paths.
+func {{ .Resource }}Handler(service *goa.Service) http.Handler {
+	return http.StripPrefix({{ printf "%q" .StripPrefix }}, service.Mux)
+}
+`
+
+	// healthCheckT generates the code for the API health check "Mount" function.
+	// template input: *design.HealthCheckDefinition
+	healthCheckT = `
+// MountHealthCheck "mounts" the API health check endpoint on the given service.
+func MountHealthCheck(service *goa.Service) {
+	ctrl := service.NewController("HealthCheck")
+	h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		rw.WriteHeader(http.StatusOK)
+		_, err := rw.Write([]byte({{ printf "%q" .Body }}))
+		return err
+	}
+	service.Mux.Handle("GET", {{ printf "%q" .FullPath }}, ctrl.MuxHandler("health", h, nil))
+	service.LogInfo("mount", "ctrl", "HealthCheck", "route", {{ printf "%q" (printf "GET %s" .FullPath) }})
+}
 `
 
 	// handleCORST generates the code that checks whether a CORS request is authorized
@@ -840,6 +1211,17 @@ func handle{{ .Resource }}Origin(h goa.Handler) goa.Handler {
 		return h(ctx, rw, req)
 	}
 }
+`
+
+	// handleResponseHeadersT generates the code that sets the constant response headers.
+	// template input: *ControllerTemplateData
+	handleResponseHeadersT = `// handle{{ .Resource }}ResponseHeaders sets the constant response headers defined in the design.
+func handle{{ .Resource }}ResponseHeaders(h goa.Handler) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+{{ range $name, $value := .ResponseHeaders }}		rw.Header().Set({{ printf "%q" $name }}, {{ printf "%q" $value }})
+{{ end }}		return h(ctx, rw, req)
+	}
+}
 `
 
 	// unmarshalT generates the code for an action payload unmarshal function.
@@ -853,17 +1235,65 @@ func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Reque
 	{{ if eq $att.Type.Kind 13 }}	_, raw{{ goify $name true }}, err2 := req.FormFile("{{ $name }}"){{ else if eq $att.Type.Kind 8 }}{{/*
 */}}	raw{{ goify $name true }} := req.Form["{{ $name }}[]"]{{ else }}{{/*
 */}}	raw{{ goify $name true }} := req.FormValue("{{ $name }}"){{ end }}
-{{ template "Coerce" (newCoerceData $name $att true (printf "payload.%s" (goifyatt $att $name true)) 1) }}{{ end }}{{/*
+{{ template "Coerce" (newPayloadCoerceData $name $att true (printf "payload.%s" (goifyatt $att $name true)) 1) }}{{ end }}{{/*
 */}}	if err != nil {
 		return err
-	}{{ else if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
+	}{{ else if .Payloads }}payload := &{{ gotypename .Payload nil 1 true }}{}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch mediaType {
+{{ range .Payloads }}	case {{ printf "%q" .MediaType }}:
+{{ if .Raw }}		defer req.Body.Close()
+		raw, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		body := string(raw)
+		payload.{{ goify .AttributeName true }} = &body
+{{ else }}		if err := service.DecodeRequest(req, &payload.{{ goify .AttributeName true }}); err != nil {
+			return err
+		}
+{{ end }}{{ end }}	default:
+		return goa.ErrUnsupportedMediaType(fmt.Sprintf("unsupported content type %q", mediaType))
+	}{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
+	payload.Finalize(){{ end }}{{ else if .BinaryMIMETypes }}var payload {{ gotypename .Payload nil 1 false }}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch mediaType {
+	case {{ range $i, $m := .BinaryMIMETypes }}{{ if $i }}, {{ end }}{{ printf "%q" $m }}{{ end }}:
+	default:
+		return goa.ErrUnsupportedMediaType(fmt.Sprintf("unsupported content type %q", mediaType))
+	}
+	defer req.Body.Close()
+{{ if .MaxBodyLength }}	raw, err := ioutil.ReadAll(io.LimitReader(req.Body, {{ .MaxBodyLength }}+1))
+	if err != nil {
+		return err
+	}
+	if len(raw) > {{ .MaxBodyLength }} {
+		return goa.ErrRequestBodyTooLarge("request body length exceeds {{ .MaxBodyLength }} bytes")
+	}
+{{ else }}	raw, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+{{ end }}	payload = {{ gotypename .Payload nil 1 false }}(raw){{ else if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
 	if err := service.DecodeRequest(req, payload); err != nil {
 		return err
 	}{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
 	payload.Finalize(){{ end }}{{ else }}var payload {{ gotypename .Payload nil 1 false }}
-	if err := service.DecodeRequest(req, &payload); err != nil {
+{{ if isText .Payload }}	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); strings.HasPrefix(mediaType, "text/") {
+		defer req.Body.Close()
+		raw, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+{{ if eq .Payload.Type.Kind 4 }}		payload = {{ gotypename .Payload nil 1 false }}(raw)
+{{ else }}		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+{{ end }}	} else if err := service.DecodeRequest(req, &payload); err != nil {
 		return err
-	}{{ end }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 true }}{{ if $validation }}
+	}{{ else }}	if err := service.DecodeRequest(req, &payload); err != nil {
+		return err
+	}{{ end }}{{ end }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 true }}{{ if $validation }}
 	if err := payload.Validate(); err != nil {
 		// Initialize payload with private data structure so it can be logged
 		goa.ContextRequest(ctx).Payload = payload
@@ -883,6 +1313,14 @@ func {{ .Name }}Href({{ if .CanonicalParams }}{{ join .CanonicalParams ", " }} i
 {{ end }}{{ if .CanonicalParams }}	return fmt.Sprintf("{{ .CanonicalTemplate }}", param{{ join .CanonicalParams ", param" }})
 {{ else }}	return "{{ .CanonicalTemplate }}"
 {{ end }}}
+{{ end }}{{ range .Links }}
+// {{ .Name }}Href returns the href of the {{ printf "%q" .Rel }} link of the {{ .Action }} action, the link
+// targets {{ .Target }}.
+func {{ .Name }}Href({{ if .Params }}{{ join .Params ", " }} interface{}{{ end }}) string {
+{{ range $param := .Params }}	param{{$param}} := strings.TrimLeftFunc(fmt.Sprintf("%v", {{$param}}), func(r rune) bool { return r == '/' })
+{{ end }}{{ if .Params }}	return fmt.Sprintf("{{ .Template }}", param{{ join .Params ", param" }})
+{{ else }}	return "{{ .Template }}"
+{{ end }}}
 {{ end }}`
 
 	// mediaTypeT generates the code for a media type.
@@ -941,6 +1379,100 @@ func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 }{{ end }}
 `
 
+	// asyncT generates the code for the async actions.
+	// template input: map[string]interface{}
+	asyncT = `{{ range .Actions }}{{ if .Inline }}{{ $typeName := gotypename .Message .Message.AllRequired 0 false }}{{/*
+*/}}// {{ gotypedesc .Message true }}
+type {{ $typeName }} {{ gotypedef .Message 0 true false }}
+{{ $validation := validationCode .Message.AttributeDefinition false false false "ut" "message" 1 false }}{{ if $validation }}// Validate validates the {{ $typeName }} type instance.
+func (ut {{ gotyperef .Message .Message.AllRequired 0 false }}) Validate() (err error) {
+{{ $validation }}
+	return
+}
+{{ end }}
+{{ end }}{{ end }}{{ if .HasPublish }}// Publisher publishes encoded messages to a message broker channel, implementations wire the
+// generated Publish functions to a broker such as Kafka or NATS.
+type Publisher interface {
+	// Publish sends body to the given channel.
+	Publish(ctx context.Context, channel string, body []byte) error
+}
+
+{{ end }}{{ if .HasSubscribe }}// Subscriber consumes encoded messages from a message broker channel, implementations wire the
+// generated Subscribe functions to a broker such as Kafka or NATS.
+type Subscriber interface {
+	// Subscribe calls handler with the body of each message received on the given channel.
+	Subscribe(ctx context.Context, channel string, handler func(context.Context, []byte) error) error
+}
+
+{{ end }}{{ range .Actions }}{{ $typeRef := gotyperef .Message .Message.AllRequired 0 false }}{{/*
+*/}}{{ if eq .Operation "publish" }}// Publish{{ .Name }} {{ if validate .Message }}validates and {{ end }}encodes msg and publishes it to the {{ printf "%q" .Channel }} channel.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+func Publish{{ .Name }}(ctx context.Context, p Publisher, msg {{ $typeRef }}) error {
+{{ if validate .Message }}	if err := msg.Validate(); err != nil {
+		return err
+	}
+{{ end }}	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return p.Publish(ctx, {{ printf "%q" .Channel }}, body)
+}
+{{ else }}// Subscribe{{ .Name }} calls handler with the messages received on the {{ printf "%q" .Channel }} channel.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+func Subscribe{{ .Name }}(ctx context.Context, s Subscriber, handler func(context.Context, {{ $typeRef }}) error) error {
+	return s.Subscribe(ctx, {{ printf "%q" .Channel }}, func(ctx context.Context, body []byte) error {
+		var msg {{ gotypename .Message .Message.AllRequired 0 false }}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return err
+		}
+{{ if validate .Message }}		if err := msg.Validate(); err != nil {
+			return err
+		}
+{{ end }}		return handler(ctx, {{ if .Message.IsObject }}&{{ end }}msg)
+	})
+}
+{{ end }}
+{{ end }}`
+
+	// streamsT generates the stream types used by the actions that stream results.
+	// template input: []*StreamTemplateData
+	streamsT = `{{ range . }}{{ $stream := printf "%sStream" .Name }}// {{ $stream }} sends the results streamed by the {{ .ActionName }} action of the {{ .ResourceName }} resource{{ if .Result }}
+// followed by its final result{{ end }}.
+type {{ $stream }} struct {
+	stream *goa.ResultStream
+}
+
+{{ if eq .Transport "websocket" }}// New{{ $stream }} returns a stream that sends the results as websocket frames.
+func New{{ $stream }}(ws *websocket.Conn) *{{ $stream }} {
+	send := func(f *goa.StreamFrame) error { return websocket.JSON.Send(ws, f) }
+	return &{{ $stream }}{stream: goa.NewResultStream(send, ws.Close, true)}
+}
+{{ else if eq .Transport "ndjson" }}// New{{ $stream }} returns a stream that writes the results as newline delimited JSON.
+func New{{ $stream }}(rw http.ResponseWriter) *{{ $stream }} {
+	return &{{ $stream }}{stream: goa.NewNDJSONResultStream(rw)}
+}
+{{ else }}// New{{ $stream }} returns a stream that writes the results as server-sent events.
+func New{{ $stream }}(rw http.ResponseWriter) *{{ $stream }} {
+	return &{{ $stream }}{stream: goa.NewSSEResultStream(rw)}
+}
+{{ end }}
+// Send sends a streamed result.
+func (s *{{ $stream }}) Send(v {{ gotyperef .StreamingResult .StreamingResult.AllRequired 0 false }}) error {
+	return s.stream.Send(v)
+}
+{{ if .Result }}
+// CloseAndSend sends the final result and closes the stream.
+func (s *{{ $stream }}) CloseAndSend(v {{ gotyperef .Result .Result.AllRequired 0 false }}) error {
+	return s.stream.CloseAndSend(v)
+}
+{{ end }}
+// Close closes the stream.
+func (s *{{ $stream }}) Close() error {
+	return s.stream.Close()
+}
+
+{{ end }}`
+
 	// securitySchemesT generates the code for the security module.
 	// template input: []*design.SecuritySchemeDefinition
 	securitySchemesT = `
@@ -996,5 +1528,13 @@ func handleSecurity(schemeName string, h goa.Handler, scopes ...string) goa.Hand
 		return am(h)(ctx, rw, req)
 	}
 }
+
+// handleScopeExpr creates a handler that stores the required scope expression evaluator in the
+// context so that the auth middleware can run it.
+func handleScopeExpr(h goa.Handler, expr goa.ScopeExprFunc) goa.Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		return h(goa.WithRequiredScopeExpr(ctx, expr), rw, req)
+	}
+}
 `
 )
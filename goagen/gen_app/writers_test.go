@@ -739,6 +739,30 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with params required together", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"lat": &design.AttributeDefinition{Type: design.Number},
+							"lng": &design.AttributeDefinition{Type: design.Number},
+						},
+						Validation: &dslengine.ValidationDefinition{
+							Together: [][]string{{"lat", "lng"}},
+						},
+					}
+				})
+
+				It("validates that the params are all present or all absent", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`	err = goa.MergeErrors(err, goa.ValidateTogether("request parameters", []string{"lat", "lng"}, []bool{len(paramLat) > 0, len(paramLng) > 0}))
+	return &rctx, err`))
+				})
+			})
+
 			Context("with a custom name param", func() {
 				BeforeEach(func() {
 					intParam := &design.AttributeDefinition{
@@ -816,6 +840,89 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with an integer header", func() {
+				BeforeEach(func() {
+					headers = &design.AttributeDefinition{
+						Type: design.Object{
+							"Retry-After": &design.AttributeDefinition{Type: design.Integer},
+						},
+					}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(intHeaderContextFactory))
+				})
+			})
+
+			Context("with a datetime header", func() {
+				BeforeEach(func() {
+					headers = &design.AttributeDefinition{
+						Type: design.Object{
+							"If-Modified-Since": &design.AttributeDefinition{Type: design.DateTime},
+						},
+					}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(dateTimeHeaderContextFactory))
+				})
+			})
+
+			Context("with a unix time header", func() {
+				BeforeEach(func() {
+					headers = &design.AttributeDefinition{
+						Type: design.Object{
+							"X-Since": &design.AttributeDefinition{
+								Type:     design.DateTime,
+								Metadata: dslengine.MetadataDefinition{"format:time": {"unix-ms"}},
+							},
+						},
+					}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(unixTimeHeaderContextFactory))
+				})
+			})
+
+			Context("with an integer array header", func() {
+				BeforeEach(func() {
+					headers = &design.AttributeDefinition{
+						Type: design.Object{
+							"Counts": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}}},
+						},
+					}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(intArrayHeaderContextFactory))
+				})
+			})
+
 			Context("with a simple payload", func() {
 				BeforeEach(func() {
 					design.Design = new(design.APIDefinition)
@@ -866,6 +973,7 @@ var _ = Describe("ContextsWriter", func() {
 					written := string(b)
 					Ω(written).ShouldNot(BeEmpty())
 					Ω(written).Should(ContainSubstring(payloadObjContext))
+					Ω(written).Should(ContainSubstring(payloadObjValidator))
 				})
 
 				var _ = Describe("IterateResponses", func() {
@@ -932,12 +1040,16 @@ var _ = Describe("ControllersWriter", func() {
 			filePath := "swagger/swagger.json"
 			var origins []*design.CORSDefinition
 			var preflightPaths []string
+			var etag bool
+			var maxAge *int
 
 			var data []*genapp.ControllerTemplateData
 
 			BeforeEach(func() {
 				origins = nil
 				preflightPaths = nil
+				etag = false
+				maxAge = nil
 			})
 
 			JustBeforeEach(func() {
@@ -945,6 +1057,8 @@ var _ = Describe("ControllersWriter", func() {
 				fileServer := &design.FileServerDefinition{
 					FilePath:    filePath,
 					RequestPath: requestPath,
+					ETag:        etag,
+					MaxAge:      maxAge,
 				}
 				d := &genapp.ControllerTemplateData{
 					API:            &design.APIDefinition{},
@@ -964,6 +1078,37 @@ var _ = Describe("ControllersWriter", func() {
 				written := string(b)
 				Ω(written).ShouldNot(BeEmpty())
 				Ω(written).Should(ContainSubstring(simpleFileServer))
+				Ω(written).ShouldNot(ContainSubstring("goa.FileCaching"))
+			})
+
+			Context("with caching", func() {
+				BeforeEach(func() {
+					etag = true
+					age := 3600
+					maxAge = &age
+				})
+
+				It("sets the caching headers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(string(b)).Should(ContainSubstring(fileServerCaching))
+				})
+			})
+
+			Context("with an ETag only", func() {
+				BeforeEach(func() {
+					etag = true
+				})
+
+				It("does not set the Cache-Control header", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(string(b)).Should(ContainSubstring("h = goa.FileCaching(true, -1)(h)"))
+				})
 			})
 
 			Context("with CORS", func() {
@@ -993,12 +1138,168 @@ var _ = Describe("ControllersWriter", func() {
 			})
 		})
 
+		Context("with a health check", func() {
+			var data []*genapp.ControllerTemplateData
+
+			JustBeforeEach(func() {
+				d := &genapp.ControllerTemplateData{
+					API:      &design.APIDefinition{},
+					Resource: "Public",
+					HealthCheck: &design.HealthCheckDefinition{
+						Parent: &design.APIDefinition{BasePath: "/api"},
+						Path:   "/health",
+						Body:   "OK",
+					},
+				}
+				data = []*genapp.ControllerTemplateData{d}
+			})
+
+			It("mounts the health check handler", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring(`_, err := rw.Write([]byte("OK"))`))
+				Ω(written).Should(ContainSubstring(`service.Mux.Handle("GET", "/api/health", ctrl.MuxHandler("health", h, nil))`))
+			})
+		})
+
+		Context("with a resource stripping its base path", func() {
+			var data []*genapp.ControllerTemplateData
+
+			JustBeforeEach(func() {
+				design.Design = &design.APIDefinition{BasePath: "/api"}
+				res := &design.ResourceDefinition{Name: "bottle", BasePath: "/bottles", StripPrefix: true}
+				show := &design.ActionDefinition{Name: "show", Parent: res}
+				show.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "/:id", Parent: show}}
+				res.Actions = map[string]*design.ActionDefinition{"show": show}
+				d := &genapp.ControllerTemplateData{
+					API:      design.Design,
+					Resource: "Bottle",
+					Actions: []map[string]interface{}{
+						{
+							"Name":       "Show",
+							"DesignName": "show",
+							"Routes":     show.Routes,
+							"Context":    "ShowBottleContext",
+						},
+					},
+					StripPrefix: res.StrippedPrefix(),
+				}
+				data = []*genapp.ControllerTemplateData{d}
+			})
+
+			It("mounts the handlers relative to the prefix", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring(`service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("show", h, nil))`))
+				Ω(written).Should(ContainSubstring(`"route", "GET /api/bottles/:id"`))
+				Ω(written).Should(ContainSubstring(stripPrefixHandler))
+			})
+		})
+
+		Context("with a static action", func() {
+			var data []*genapp.ControllerTemplateData
+
+			JustBeforeEach(func() {
+				design.Design = new(design.APIDefinition)
+				res := &design.ResourceDefinition{Name: "meta", BasePath: "/meta"}
+				version := &design.ActionDefinition{Name: "version", Parent: res}
+				version.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "/version", Parent: version}}
+				res.Actions = map[string]*design.ActionDefinition{"version": version}
+				d := &genapp.ControllerTemplateData{
+					API:      design.Design,
+					Resource: "Meta",
+					StaticActions: []map[string]interface{}{
+						{
+							"Name":        "Version",
+							"DesignName":  "version",
+							"Routes":      version.Routes,
+							"Status":      200,
+							"ContentType": "application/json",
+							"Body":        `{"version":"1.2.0"}`,
+						},
+					},
+				}
+				data = []*genapp.ControllerTemplateData{d}
+			})
+
+			It("mounts a handler writing the static response", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring(staticActionMount))
+				Ω(written).ShouldNot(ContainSubstring("ctrl.Version("))
+			})
+		})
+
+		Context("with a not found action", func() {
+			var data []*genapp.ControllerTemplateData
+
+			JustBeforeEach(func() {
+				design.Design = new(design.APIDefinition)
+				res := &design.ResourceDefinition{Name: "bottle", BasePath: "/bottles", NotFoundActionName: "fallback"}
+				fallback := &design.ActionDefinition{Name: "fallback", Parent: res}
+				fallback.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "/fallback", Parent: fallback}}
+				show := &design.ActionDefinition{Name: "show", Parent: res}
+				show.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "/:id", Parent: show}}
+				res.Actions = map[string]*design.ActionDefinition{"fallback": fallback, "show": show}
+				d := &genapp.ControllerTemplateData{
+					API:      design.Design,
+					Resource: "Bottle",
+					Actions: []map[string]interface{}{
+						{
+							"Name":           "Fallback",
+							"DesignName":     "fallback",
+							"Routes":         fallback.Routes,
+							"Context":        "FallbackBottleContext",
+							"Unmarshal":      "unmarshalFallbackBottlePayload",
+							"NotFoundRoutes": res.NotFoundRoutes(),
+						},
+						{
+							"Name":       "Show",
+							"DesignName": "show",
+							"Routes":     show.Routes,
+							"Context":    "ShowBottleContext",
+							"Unmarshal":  "unmarshalShowBottlePayload",
+						},
+					},
+				}
+				data = []*genapp.ControllerTemplateData{d}
+			})
+
+			It("mounts the action on catch-all routes", func() {
+				err := writer.Execute(data)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring(`service.Mux.Handle("GET", "/bottles/fallback", ctrl.MuxHandler("fallback", h, nil))`))
+				for _, verb := range design.NotFoundMethods {
+					Ω(written).Should(ContainSubstring(`service.Mux.Handle("` + verb + `", "/bottles/*goaNotFoundPath", ctrl.MuxHandler("fallback", h, nil))`))
+				}
+				Ω(written).Should(ContainSubstring(`service.Mux.Handle("GET", "/bottles/:id", ctrl.MuxHandler("show", h, nil))`))
+			})
+		})
+
 		Context("with data", func() {
 			var multipart bool
 			var actions, verbs, paths, contexts, unmarshals []string
 			var payloads []*design.UserTypeDefinition
 			var encoders, decoders []*genapp.EncoderTemplateData
 			var origins []*design.CORSDefinition
+			var compression []string
+			var timeout string
+			var signature *design.SignatureDefinition
+			var binaryMIMETypes []string
+			var maxBodyLength int
+			var consumes, produces []string
 
 			var data []*genapp.ControllerTemplateData
 
@@ -1013,14 +1314,22 @@ var _ = Describe("ControllersWriter", func() {
 				encoders = nil
 				decoders = nil
 				origins = nil
+				compression = nil
+				timeout = ""
+				signature = nil
+				binaryMIMETypes = nil
+				maxBodyLength = 0
+				consumes = nil
+				produces = nil
 			})
 
 			JustBeforeEach(func() {
 				codegen.TempCount = 0
 				api := &design.APIDefinition{}
 				d := &genapp.ControllerTemplateData{
-					Resource: "Bottles",
-					Origins:  origins,
+					Resource:    "Bottles",
+					Origins:     origins,
+					Compression: compression,
 				}
 				as := make([]map[string]interface{}, len(actions))
 				for i, a := range actions {
@@ -1045,6 +1354,25 @@ var _ = Describe("ControllersWriter", func() {
 						"Payload":          payload,
 						"PayloadMultipart": multipart,
 					}
+					if timeout != "" {
+						as[i]["Timeout"] = timeout
+					}
+					if signature != nil {
+						as[i]["Signature"] = signature
+						as[i]["SignatureErrorStatus"] = 401
+					}
+					if binaryMIMETypes != nil {
+						as[i]["BinaryMIMETypes"] = binaryMIMETypes
+					}
+					if maxBodyLength > 0 {
+						as[i]["MaxBodyLength"] = maxBodyLength
+					}
+					if consumes != nil {
+						as[i]["Consumes"] = consumes
+					}
+					if produces != nil {
+						as[i]["Produces"] = produces
+					}
 				}
 				if len(as) > 0 {
 					d.API = api
@@ -1088,6 +1416,99 @@ var _ = Describe("ControllersWriter", func() {
 				})
 			})
 
+			Context("with a signed action", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					signature = &design.SignatureDefinition{Header: "X-Signature", Algorithm: "hmac-sha256"}
+				})
+
+				It("verifies the signature before calling the mux handler", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`service.Mux.Handle("GET", "/accounts/:accountID/bottles", goa.VerifySignature(service, "X-Signature", "hmac-sha256", 401, ctrl.MuxHandler("list", h, nil)))`))
+				})
+			})
+
+			Context("with an action with a timeout", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					timeout = "30 * time.Second"
+				})
+
+				It("wraps the action handler with the timeout middleware", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(timeoutMount))
+				})
+			})
+
+			Context("with a named mount", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("writes one Mount function per mount", func() {
+					mounted := *data[0]
+					mounted.Mount = "partner"
+					mounted.Actions = []map[string]interface{}{{
+						"Name":       "List",
+						"DesignName": "list",
+						"Routes":     []*design.RouteDefinition{{Verb: "GET", Path: "//partner/v1/accounts/:accountID/bottles"}},
+						"Context":    "ListBottleContext",
+					}}
+					data[0].Mounts = []*genapp.ControllerTemplateData{&mounted}
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(simpleMount))
+					Ω(written).Should(ContainSubstring(partnerMount))
+				})
+			})
+
+			Context("with an action that negotiates its media types", func() {
+				BeforeEach(func() {
+					actions = []string{"create"}
+					verbs = []string{"POST"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"CreateBottleContext"}
+					unmarshals = []string{"unmarshalCreateBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName:            "CreateBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+						},
+					}
+					consumes = []string{"application/json", "multipart/form-data"}
+					produces = []string{"application/json", "application/xml"}
+				})
+
+				It("checks the Content-Type and Accept headers before decoding the request", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(negotiateMount))
+				})
+			})
+
 			Context("with actions that take a payload", func() {
 				BeforeEach(func() {
 					actions = []string{"list"}
@@ -1118,6 +1539,183 @@ var _ = Describe("ControllersWriter", func() {
 					Ω(written).Should(ContainSubstring(payloadNoValidationsObjUnmarshal))
 				})
 			})
+
+			Context("with actions that take a primitive payload", func() {
+				var payloadType design.DataType
+
+				BeforeEach(func() {
+					payloadType = design.Integer
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					unmarshals = []string{"unmarshalListBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName:            "ListBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{},
+						},
+					}
+				})
+
+				JustBeforeEach(func() {
+					payloads[0].Type = payloadType
+				})
+
+				It("reads text bodies as is", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadPrimitiveUnmarshal))
+				})
+
+				Context("of type string", func() {
+					BeforeEach(func() {
+						payloadType = design.String
+					})
+
+					It("converts the raw body", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring("payload = ListBottlePayload(raw)"))
+					})
+				})
+
+				Context("of type array", func() {
+					BeforeEach(func() {
+						payloadType = &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}
+					})
+
+					It("decodes the body", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(ContainSubstring("ioutil.ReadAll"))
+						Ω(written).Should(ContainSubstring("if err := service.DecodeRequest(req, &payload); err != nil {"))
+					})
+				})
+			})
+
+			Context("with actions that take a binary payload", func() {
+				BeforeEach(func() {
+					actions = []string{"upload"}
+					verbs = []string{"PUT"}
+					paths = []string{"/bottles/:id/label"}
+					contexts = []string{"UploadBottleContext"}
+					unmarshals = []string{"unmarshalUploadBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName:            "UploadBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{Type: design.Bytes},
+						},
+					}
+					binaryMIMETypes = []string{"image/png", "image/jpeg"}
+				})
+
+				It("reads the raw body", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadBinaryUnmarshal))
+					Ω(written).ShouldNot(ContainSubstring("DecodeRequest"))
+				})
+
+				Context("with a maximum length", func() {
+					BeforeEach(func() {
+						maxBodyLength = 1024
+					})
+
+					It("limits the body size", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring("raw, err := ioutil.ReadAll(io.LimitReader(req.Body, 1024+1))"))
+						Ω(written).Should(ContainSubstring(`return goa.ErrRequestBodyTooLarge("request body length exceeds 1024 bytes")`))
+					})
+				})
+			})
+
+			Context("with actions that take payloads per content type", func() {
+				BeforeEach(func() {
+					actions = []string{"receive"}
+					verbs = []string{"POST"}
+					paths = []string{"/hooks"}
+					contexts = []string{"ReceiveBottleContext"}
+					unmarshals = []string{"unmarshalReceiveBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "ReceiveBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"application_json": &design.AttributeDefinition{
+										Type: design.Object{"id": &design.AttributeDefinition{Type: design.String}},
+									},
+									"text_plain": &design.AttributeDefinition{Type: design.String},
+								},
+							},
+						},
+					}
+				})
+
+				It("switches on the request content type", func() {
+					p := payloads[0].Type.ToObject()
+					data[0].Actions[0]["Payloads"] = []*design.PayloadDefinition{
+						{ContentType: "application/json", Attribute: p["application_json"]},
+						{ContentType: "text/plain; charset=utf-8", Attribute: p["text_plain"]},
+					}
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadsUnmarshal))
+				})
+			})
+
+			Context("with compression", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					unmarshals = []string{"unmarshalListBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "ListBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"id": &design.AttributeDefinition{
+										Type: design.String,
+									},
+								},
+							},
+						},
+					}
+					compression = []string{"br", "gzip"}
+				})
+
+				It("compresses responses and decompresses requests", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`h = compress.Middleware("br", "gzip")(h)`))
+					Ω(written).Should(ContainSubstring(`ctrl.MuxHandler("list", h, compress.Decode(unmarshalListBottlePayload, "br", "gzip"))`))
+				})
+			})
+
 			Context("with actions that take a payload with a required validation", func() {
 				BeforeEach(func() {
 					actions = []string{"list"}
@@ -1513,6 +2111,31 @@ var _ = Describe("HrefWriter", func() {
 						Ω(written).Should(ContainSubstring(noParamHref))
 					})
 				})
+
+				Context("and an action link", func() {
+					BeforeEach(func() {
+						canoTemplate = "/bottles/%v"
+						canoParams = []string{"id"}
+					})
+
+					It("writes the link href method", func() {
+						data.Links = []*genapp.LinkHrefData{{
+							Name:     "ShowBottleOwner",
+							Rel:      "owner",
+							Action:   "show",
+							Target:   `resource "account" action "show"`,
+							Template: "/accounts/%v",
+							Params:   []string{"accountID"},
+						}}
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(simpleResourceHref))
+						Ω(written).Should(ContainSubstring(linkHref))
+					})
+				})
 			})
 		})
 	})
@@ -1877,6 +2500,104 @@ func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Ser
 	}
 	return &rctx, err
 }
+`
+
+	intHeaderContextFactory = `
+func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	req.Request = r
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	headerRetryAfter := req.Header["Retry-After"]
+	if len(headerRetryAfter) > 0 {
+		rawRetryAfter := headerRetryAfter[0]
+		req.Params["Retry-After"] = []string{rawRetryAfter}
+		if retryAfter, err2 := strconv.Atoi(rawRetryAfter); err2 == nil {
+			tmp2 := retryAfter
+			tmp1 := &tmp2
+			rctx.RetryAfter = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("Retry-After", rawRetryAfter, "integer"))
+		}
+	}
+	return &rctx, err
+}
+`
+
+	dateTimeHeaderContextFactory = `
+func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	req.Request = r
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	headerIfModifiedSince := req.Header["If-Modified-Since"]
+	if len(headerIfModifiedSince) > 0 {
+		rawIfModifiedSince := headerIfModifiedSince[0]
+		req.Params["If-Modified-Since"] = []string{rawIfModifiedSince}
+		ifModifiedSince, err2 := time.Parse(time.RFC3339, rawIfModifiedSince)
+		if err2 != nil {
+			ifModifiedSince, err2 = http.ParseTime(rawIfModifiedSince)
+		}
+		if err2 == nil {
+			tmp1 := &ifModifiedSince
+			rctx.IfModifiedSince = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("If-Modified-Since", rawIfModifiedSince, "datetime"))
+		}
+	}
+	return &rctx, err
+}
+`
+
+	unixTimeHeaderContextFactory = `
+	headerXSince := req.Header["X-Since"]
+	if len(headerXSince) > 0 {
+		rawXSince := headerXSince[0]
+		req.Params["X-Since"] = []string{rawXSince}
+		if xSince, err2 := goa.ParseUnixMilliTime(rawXSince); err2 == nil {
+			tmp1 := &xSince
+			rctx.XSince = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("X-Since", rawXSince, "datetime"))
+		}
+	}
+`
+
+	intArrayHeaderContextFactory = `
+func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	req.Request = r
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	headerCounts := req.Header["Counts"]
+	if len(headerCounts) > 0 {
+		var valuesCounts []string
+		for _, h := range headerCounts {
+			for _, v := range strings.Split(h, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					valuesCounts = append(valuesCounts, v)
+				}
+			}
+		}
+		req.Params["Counts"] = valuesCounts
+		headers := make([]int, len(valuesCounts))
+		for i, rawCounts := range valuesCounts {
+			if counts, err2 := strconv.Atoi(rawCounts); err2 == nil {
+				headers[i] = counts
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("Counts", rawCounts, "integer"))
+			}
+		}
+		rctx.Counts = headers
+	}
+	return &rctx, err
+}
 `
 
 	strHeaderParamContextFactory = `
@@ -2356,6 +3077,16 @@ func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Ser
 	return &rctx, err
 }
 `
+	payloadObjValidator = `
+func ValidateListBottlePayload(body []byte) error {
+	payload := &listBottlePayload{}
+	if err := json.Unmarshal(body, payload); err != nil {
+		return goa.ErrBadRequest(err)
+	}
+	return payload.Validate()
+}
+`
+
 	payloadObjContext = `
 type ListBottleContext struct {
 	context.Context
@@ -2363,6 +3094,72 @@ type ListBottleContext struct {
 	*goa.RequestData
 	Payload *ListBottlePayload
 }
+`
+
+	payloadBinaryUnmarshal = `
+func unmarshalUploadBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	var payload UploadBottlePayload
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch mediaType {
+	case "image/png", "image/jpeg":
+	default:
+		return goa.ErrUnsupportedMediaType(fmt.Sprintf("unsupported content type %q", mediaType))
+	}
+	defer req.Body.Close()
+	raw, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	payload = UploadBottlePayload(raw)
+	goa.ContextRequest(ctx).Payload = payload
+	return nil
+}
+`
+
+	payloadPrimitiveUnmarshal = `
+func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	var payload ListBottlePayload
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); strings.HasPrefix(mediaType, "text/") {
+		defer req.Body.Close()
+		raw, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+	} else if err := service.DecodeRequest(req, &payload); err != nil {
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload
+	return nil
+}
+`
+
+	payloadsUnmarshal = `
+// unmarshalReceiveBottlePayload unmarshals the request body into the context request data Payload field.
+func unmarshalReceiveBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	payload := &receiveBottlePayload{}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		if err := service.DecodeRequest(req, &payload.ApplicationJSON); err != nil {
+			return err
+		}
+	case "text/plain":
+		defer req.Body.Close()
+		raw, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		body := string(raw)
+		payload.TextPlain = &body
+	default:
+		return goa.ErrUnsupportedMediaType(fmt.Sprintf("unsupported content type %q", mediaType))
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
 `
 
 	payloadObjUnmarshal = `
@@ -2495,6 +3292,10 @@ type PublicController interface {
 }
 `
 
+	fileServerCaching = `	h = ctrl.FileHandler("/swagger.json", "swagger/swagger.json")
+	h = goa.FileCaching(true, 3600)(h)
+	service.Mux.Handle("GET", "/swagger.json", ctrl.MuxHandler("serve", h, nil))`
+
 	fileServerOptionsHandler = `service.Mux.Handle("OPTIONS", "/public/star\\*star/*filepath", ctrl.MuxHandler("preflight", handlePublicOrigin(cors.HandlePreflight()), nil))`
 
 	simpleController = `// BottlesController is the controller interface for the Bottles actions.
@@ -2505,6 +3306,7 @@ type BottlesController interface {
 `
 
 	originsIntegration = `}
+	h = goa.HandlePanics(PanicHandler)(h)
 	h = handleBottlesOrigin(h)
 	service.Mux.Handle`
 
@@ -2607,6 +3409,7 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 		}
 		return ctrl.List(rctx)
 	}
+	h = goa.HandlePanics(PanicHandler)(h)
 	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
 	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
 }
@@ -2628,11 +3431,50 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 		}
 		return ctrl.List(rctx)
 	}
+	h = goa.HandlePanics(PanicHandler)(h)
 	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
 	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
 }
 `
 
+	timeoutMount = `		return ctrl.List(rctx)
+	}
+	h = goa.HandlePanics(PanicHandler)(h)
+	h = middleware.Timeout(30 * time.Second)(h)
+	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
+`
+
+	partnerMount = `// MountBottlesPartnerController "mounts" a Bottles resource controller on the given service
+// under its "partner" mount.
+func MountBottlesPartnerController(service *goa.Service, ctrl BottlesController) {
+	initService(service)
+	var h goa.Handler
+
+	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+		// Build the context
+		rctx, err := NewListBottleContext(ctx, req, service)
+		if err != nil {
+			return err
+		}
+		return ctrl.List(rctx)
+	}
+	h = goa.HandlePanics(PanicHandler)(h)
+	service.Mux.Handle("GET", "/partner/v1/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
+	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /partner/v1/accounts/:accountID/bottles")
+}
+`
+
+	negotiateMount = `		return ctrl.Create(rctx)
+	}
+	h = goa.HandlePanics(PanicHandler)(h)
+	h = goa.Negotiate("application/json", "application/xml")(h)
+	service.Mux.Handle("POST", "/accounts/:accountID/bottles", ctrl.MuxHandler("create", h, goa.NegotiateContentType(unmarshalCreateBottlePayload, "application/json", "multipart/form-data")))
+`
+
 	multiController = `// BottlesController is the controller interface for the Bottles actions.
 type BottlesController interface {
 	goa.Muxer
@@ -2657,6 +3499,7 @@ type BottlesController interface {
 		}
 		return ctrl.List(rctx)
 	}
+	h = goa.HandlePanics(PanicHandler)(h)
 	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
 	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
 
@@ -2672,6 +3515,7 @@ type BottlesController interface {
 		}
 		return ctrl.Show(rctx)
 	}
+	h = goa.HandlePanics(PanicHandler)(h)
 	service.Mux.Handle("GET", "/accounts/:accountID/bottles/:id", ctrl.MuxHandler("show", h, nil))
 	service.LogInfo("mount", "ctrl", "Bottles", "action", "Show", "route", "GET /accounts/:accountID/bottles/:id")
 }
@@ -2685,6 +3529,14 @@ type BottlesController interface {
 	noParamHref = `func BottleHref() string {
 	return "/bottles"
 }
+`
+
+	linkHref = `// ShowBottleOwnerHref returns the href of the "owner" link of the show action, the link
+// targets resource "account" action "show".
+func ShowBottleOwnerHref(accountID interface{}) string {
+	paramaccountID := strings.TrimLeftFunc(fmt.Sprintf("%v", accountID), func(r rune) bool { return r == '/' })
+	return fmt.Sprintf("/accounts/%v", paramaccountID)
+}
 `
 
 	simpleUserType = `// simplePayload user type.
@@ -2744,3 +3596,21 @@ type ComplexPayload struct {
 }
 `
 )
+
+const staticActionMount = `	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		if !goa.ResponseHasBody(goa.ContextRequest(ctx), 200) {
+			return nil
+		}
+		_, err := rw.Write([]byte("{\"version\":\"1.2.0\"}"))
+		return err
+	}
+	service.Mux.Handle("GET", "/meta/version", ctrl.MuxHandler("version", h, nil))
+	service.LogInfo("mount", "ctrl", "Meta", "action", "Version", "route", "GET /meta/version", "static", true)
+`
+
+const stripPrefixHandler = `func BottleHandler(service *goa.Service) http.Handler {
+	return http.StripPrefix("/api/bottles", service.Mux)
+}
+`
@@ -0,0 +1,184 @@
+package genasyncapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_schema"
+)
+
+// Version is the version of the AsyncAPI specification implemented by the generated documents.
+const Version = "2.6.0"
+
+type (
+	// AsyncAPI is the data structure that describes the channels of an API.
+	AsyncAPI struct {
+		AsyncAPI           string              `json:"asyncapi"`
+		Info               *Info               `json:"info"`
+		DefaultContentType string              `json:"defaultContentType,omitempty"`
+		Channels           map[string]*Channel `json:"channels"`
+		Components         *Components         `json:"components,omitempty"`
+	}
+
+	// Info provides metadata about the API.
+	Info struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// Channel describes the operations available on a single channel.
+	Channel struct {
+		// Subscribe describes the messages published by the API to the channel.
+		Subscribe *Operation `json:"subscribe,omitempty"`
+		// Publish describes the messages consumed by the API from the channel.
+		Publish *Operation `json:"publish,omitempty"`
+	}
+
+	// Operation describes a publish or subscribe operation.
+	Operation struct {
+		OperationID string   `json:"operationId,omitempty"`
+		Summary     string   `json:"summary,omitempty"`
+		Description string   `json:"description,omitempty"`
+		Tags        []*Tag   `json:"tags,omitempty"`
+		Message     *Message `json:"message"`
+	}
+
+	// Tag allows adding metadata to an operation.
+	Tag struct {
+		Name string `json:"name"`
+	}
+
+	// Message describes a message, operations reference the messages listed in the
+	// components.
+	Message struct {
+		Ref         string                `json:"$ref,omitempty"`
+		Name        string                `json:"name,omitempty"`
+		Title       string                `json:"title,omitempty"`
+		ContentType string                `json:"contentType,omitempty"`
+		Payload     *genschema.JSONSchema `json:"payload,omitempty"`
+	}
+
+	// Components holds the schemas and messages referenced by the operations.
+	Components struct {
+		Schemas  map[string]*genschema.JSONSchema `json:"schemas,omitempty"`
+		Messages map[string]*Message              `json:"messages,omitempty"`
+	}
+)
+
+// New creates the AsyncAPI document describing the async actions of the given API. The message
+// payloads describe the message types inline and refer to the components schemas for the user
+// types they use. The schemas include the types referenced by the messages only when the
+// genschema definitions are reset prior to calling New.
+func New(api *design.APIDefinition) (*AsyncAPI, error) {
+	if api == nil {
+		return nil, nil
+	}
+	title := api.Title
+	if title == "" {
+		title = api.Name
+	}
+	version := api.Version
+	if version == "" {
+		version = "1.0"
+	}
+	doc := &AsyncAPI{
+		AsyncAPI: Version,
+		Info: &Info{
+			Title:       title,
+			Version:     version,
+			Description: api.Description,
+		},
+		DefaultContentType: "application/json",
+		Channels:           make(map[string]*Channel),
+	}
+	messages := make(map[string]*Message)
+	err := api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateAsyncActions(func(a *design.ActionDefinition) error {
+			if a.Message == nil {
+				return nil
+			}
+			name := a.Message.TypeName
+			if _, ok := messages[name]; !ok {
+				messages[name] = &Message{
+					Name:        name,
+					Title:       a.Message.Description,
+					ContentType: "application/json",
+					Payload:     componentSchema(genschema.TypeSchema(api, a.Message.Type)),
+				}
+			}
+			op := &Operation{
+				OperationID: fmt.Sprintf("%s#%s", r.Name, a.Name),
+				Summary:     a.Name + " " + r.Name,
+				Description: a.Description,
+				Tags:        []*Tag{{Name: r.Name}},
+				Message:     &Message{Ref: "#/components/messages/" + name},
+			}
+			ch, ok := doc.Channels[a.Channel]
+			if !ok {
+				ch = &Channel{}
+				doc.Channels[a.Channel] = ch
+			}
+			// AsyncAPI 2 describes the operations from the point of view of the clients.
+			if a.AsyncOperation == design.PublishOperation {
+				if ch.Subscribe != nil {
+					return fmt.Errorf("channel %#v: %s and %s both publish messages", a.Channel, ch.Subscribe.OperationID, op.OperationID)
+				}
+				ch.Subscribe = op
+			} else {
+				if ch.Publish != nil {
+					return fmt.Errorf("channel %#v: %s and %s both consume messages", a.Channel, ch.Publish.OperationID, op.OperationID)
+				}
+				ch.Publish = op
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) > 0 {
+		doc.Components = &Components{Messages: messages}
+		if len(genschema.Definitions) > 0 {
+			doc.Components.Schemas = make(map[string]*genschema.JSONSchema)
+			for n, d := range genschema.Definitions {
+				s := componentSchema(d)
+				s.Media = nil
+				s.Links = nil
+				doc.Components.Schemas[n] = s
+			}
+		}
+	}
+	return doc, nil
+}
+
+// componentSchema returns a copy of the given schema where references to the JSON schema
+// definitions are replaced with references to the AsyncAPI components schemas.
+func componentSchema(s *genschema.JSONSchema) *genschema.JSONSchema {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Ref = strings.Replace(s.Ref, "#/definitions/", "#/components/schemas/", 1)
+	c.Items = componentSchema(s.Items)
+	if s.Properties != nil {
+		c.Properties = make(map[string]*genschema.JSONSchema, len(s.Properties))
+		for n, p := range s.Properties {
+			c.Properties[n] = componentSchema(p)
+		}
+	}
+	if s.Definitions != nil {
+		c.Definitions = make(map[string]*genschema.JSONSchema, len(s.Definitions))
+		for n, d := range s.Definitions {
+			c.Definitions[n] = componentSchema(d)
+		}
+	}
+	if s.AnyOf != nil {
+		c.AnyOf = make([]*genschema.JSONSchema, len(s.AnyOf))
+		for i, a := range s.AnyOf {
+			c.AnyOf[i] = componentSchema(a)
+		}
+	}
+	return &c
+}
@@ -0,0 +1,109 @@
+package genasyncapi_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_asyncapi"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var doc *genasyncapi.AsyncAPI
+	var newErr error
+
+	BeforeEach(func() {
+		doc = nil
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		doc, newErr = genasyncapi.New(Design)
+	})
+
+	Context("with publish and subscribe actions", func() {
+		BeforeEach(func() {
+			event := Type("OrderEvent", func() {
+				Attribute("id", String)
+			})
+			API("test", func() {
+				Title("orders")
+				Version("2.0")
+			})
+			Resource("order", func() {
+				Publish("created", func() {
+					Description("Order created")
+					Channel("orders.created")
+					Message(event)
+				})
+				Subscribe("paid", func() {
+					Channel("payments.completed")
+					Message(func() {
+						Attribute("order", event)
+						Required("order")
+					})
+				})
+			})
+		})
+
+		It("describes the channels", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(doc).ShouldNot(BeNil())
+			Ω(doc.AsyncAPI).Should(Equal(genasyncapi.Version))
+			Ω(doc.Info.Title).Should(Equal("orders"))
+			Ω(doc.Info.Version).Should(Equal("2.0"))
+			Ω(doc.Channels).Should(HaveLen(2))
+
+			created := doc.Channels["orders.created"]
+			Ω(created).ShouldNot(BeNil())
+			Ω(created.Publish).Should(BeNil())
+			Ω(created.Subscribe).ShouldNot(BeNil())
+			Ω(created.Subscribe.OperationID).Should(Equal("order#created"))
+			Ω(created.Subscribe.Description).Should(Equal("Order created"))
+			Ω(created.Subscribe.Message.Ref).Should(Equal("#/components/messages/OrderEvent"))
+
+			paid := doc.Channels["payments.completed"]
+			Ω(paid).ShouldNot(BeNil())
+			Ω(paid.Subscribe).Should(BeNil())
+			Ω(paid.Publish).ShouldNot(BeNil())
+			Ω(paid.Publish.Message.Ref).Should(Equal("#/components/messages/PaidOrderMessage"))
+		})
+
+		It("shares the message schemas through the components", func() {
+			Ω(doc.Components).ShouldNot(BeNil())
+			Ω(doc.Components.Messages).Should(HaveKey("OrderEvent"))
+			Ω(doc.Components.Messages).Should(HaveKey("PaidOrderMessage"))
+			Ω(doc.Components.Schemas).Should(HaveKey("OrderEvent"))
+			payload := doc.Components.Messages["PaidOrderMessage"].Payload
+			Ω(payload).ShouldNot(BeNil())
+			Ω(payload.Properties).Should(HaveKey("order"))
+			Ω(payload.Properties["order"].Ref).Should(Equal("#/components/schemas/OrderEvent"))
+		})
+	})
+
+	Context("with two actions publishing on the same channel", func() {
+		BeforeEach(func() {
+			API("test", nil)
+			Resource("order", func() {
+				Publish("created", func() {
+					Channel("orders")
+					Message(String)
+				})
+				Publish("updated", func() {
+					Channel("orders")
+					Message(String)
+				})
+			})
+		})
+
+		It("returns an error", func() {
+			Ω(newErr).Should(HaveOccurred())
+			Ω(newErr.Error()).Should(ContainSubstring(`channel "orders"`))
+		})
+	})
+})
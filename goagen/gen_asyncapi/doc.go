@@ -0,0 +1,10 @@
+/*
+Package genasyncapi provides a generator for the AsyncAPI document describing the messages
+published and consumed by the API async actions, see the Publish and Subscribe DSLs.
+
+The generator produces the asyncapi/asyncapi.json and asyncapi/asyncapi.yaml files. The document
+follows the AsyncAPI 2.x specification which describes the operations from the point of view of
+the clients: the messages published by the API are listed under the channel "subscribe"
+operation and the messages consumed by the API under the channel "publish" operation.
+*/
+package genasyncapi
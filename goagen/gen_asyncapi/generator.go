@@ -0,0 +1,114 @@
+package genasyncapi
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// NewGenerator returns an initialized instance of an AsyncAPI generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the AsyncAPI generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("asyncapi", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the AsyncAPI document in JSON and YAML.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	doc, err := New(g.API)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(g.OutDir, "asyncapi")
+	codegen.CleanDir(dir)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, dir)
+
+	// JSON
+	rawJSON, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, "asyncapi.json")
+	if err = codegen.WriteFile(file, rawJSON); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, file)
+
+	// YAML
+	var yamlSource interface{}
+	if err = json.Unmarshal(rawJSON, &yamlSource); err != nil {
+		return nil, err
+	}
+	rawYAML, err := yaml.Marshal(yamlSource)
+	if err != nil {
+		return nil, err
+	}
+	file = filepath.Join(dir, "asyncapi.yaml")
+	if err = codegen.WriteFile(file, rawYAML); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, file)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invocation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
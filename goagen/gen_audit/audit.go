@@ -0,0 +1,65 @@
+package genaudit
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+)
+
+// Audit returns the report that lists the effective definition of the attributes of each user
+// type and media type of the API. Each property inherited from a reference type is followed by
+// the name of the type that contributed it, e.g.:
+//
+//	type "Bottle"
+//		name
+//			type: string
+//			description: "The bottle name" (from BaseBottle)
+//			min length: 1 (from BaseBottle)
+//
+// The attributes of inline objects are listed using their dotted path. The provenance is only
+// available if design.TrackProvenance was enabled while the DSL ran.
+func Audit(api *design.APIDefinition) string {
+	var buf bytes.Buffer
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		fmt.Fprintf(&buf, "type %q\n", ut.TypeName)
+		auditAttributes(&buf, "", ut.AttributeDefinition)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		fmt.Fprintf(&buf, "media type %q\n", mt.Identifier)
+		auditAttributes(&buf, "", mt.AttributeDefinition)
+		return nil
+	})
+	return buf.String()
+}
+
+// auditAttributes writes the properties of the child attributes of att if att is an inline
+// object.
+func auditAttributes(buf *bytes.Buffer, prefix string, att *design.AttributeDefinition) {
+	if att == nil {
+		return
+	}
+	obj, ok := att.Type.(design.Object)
+	if !ok {
+		return
+	}
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		child := obj[n]
+		fmt.Fprintf(buf, "\t%s%s\n", prefix, n)
+		for _, p := range child.Properties() {
+			fmt.Fprintf(buf, "\t\t%s: %s", p.Name, p.Value)
+			if source, ok := child.Provenance[p.Name]; ok {
+				fmt.Fprintf(buf, " (from %s)", source)
+			}
+			buf.WriteString("\n")
+		}
+		auditAttributes(buf, prefix+n+".", child)
+	}
+}
@@ -0,0 +1,62 @@
+package genaudit_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_audit"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audit", func() {
+	var report string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		var BaseBottle = Type("BaseBottle", func() {
+			Attribute("name", String, "The bottle name", func() {
+				MinLength(1)
+				Metadata("struct:tag:json", "name")
+			})
+			Attribute("vintage", Integer, func() {
+				Minimum(1900)
+			})
+		})
+		Type("Bottle", func() {
+			Reference(BaseBottle)
+			Attribute("name", func() {
+				MaxLength(10)
+			})
+			Attribute("vintage", func() {
+				Description("The bottle vintage")
+			})
+			Attribute("origin", func() {
+				Attribute("country", String)
+			})
+		})
+		API("test", func() {})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		report = genaudit.Audit(Design)
+	})
+
+	It("annotates the properties inherited from the reference", func() {
+		Ω(report).Should(ContainSubstring("type \"Bottle\"\n\tname\n\t\ttype: string (from BaseBottle)\n" +
+			"\t\tdescription: \"The bottle name\" (from BaseBottle)\n"))
+		Ω(report).Should(ContainSubstring("\t\tmin length: 1 (from BaseBottle)\n\t\tmax length: 10\n" +
+			"\t\tmetadata:struct:tag:json: [\"name\"] (from BaseBottle)\n"))
+		Ω(report).Should(ContainSubstring("\tvintage\n\t\ttype: integer (from BaseBottle)\n" +
+			"\t\tdescription: \"The bottle vintage\"\n"))
+		Ω(report).Should(ContainSubstring("\t\tminimum: 1900 (from BaseBottle)\n"))
+	})
+
+	It("lists the attributes of inline objects", func() {
+		Ω(report).Should(ContainSubstring("\torigin.country\n\t\ttype: string\n"))
+	})
+
+	It("does not annotate the properties of types without reference", func() {
+		Ω(report).Should(ContainSubstring("type \"BaseBottle\"\n\tname\n\t\ttype: string\n" +
+			"\t\tdescription: \"The bottle name\"\n"))
+	})
+})
@@ -0,0 +1,12 @@
+/*
+Package genaudit provides a generator that reports where the properties of the design attributes
+come from. Attributes of types and media types that use Reference inherit their description,
+default value, validations and metadata from the referenced type, the generated report lists
+the effective definition of each attribute and annotates the inherited properties with the name
+of the type that contributed them.
+
+The generator produces the audit/audit.txt file. Loading the generator package enables
+design.TrackProvenance so that the provenance is recorded while the DSL runs, other generators
+are not affected.
+*/
+package genaudit
@@ -0,0 +1,13 @@
+package genaudit_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenAudit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenAudit Suite")
+}
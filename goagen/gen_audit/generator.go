@@ -0,0 +1,88 @@
+package genaudit
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+func init() {
+	// The generated main runs the DSL after the generator package is initialized.
+	design.TrackProvenance = true
+}
+
+// NewGenerator returns an initialized instance of an attribute provenance audit generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the attribute provenance audit generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("audit", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the attribute provenance report.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	g.OutDir = filepath.Join(g.OutDir, "audit")
+	codegen.CleanDir(g.OutDir)
+	os.MkdirAll(g.OutDir, 0755)
+	g.genfiles = append(g.genfiles, g.OutDir)
+	auditFile := filepath.Join(g.OutDir, "audit.txt")
+	if err = codegen.WriteFile(auditFile, []byte(Audit(g.API))); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, auditFile)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invocation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
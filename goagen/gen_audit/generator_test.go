@@ -0,0 +1,58 @@
+package genaudit_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_audit"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("audittest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genaudit.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a type", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {})
+			apidsl.Type("Bottle", func() {
+				apidsl.Attribute("name")
+			})
+			dslengine.Run()
+		})
+
+		It("generates the audit report", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(2))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "audit", "audit.txt"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(Equal("type \"Bottle\"\n\tname\n\t\ttype: string\n"))
+		})
+	})
+})
@@ -21,14 +21,15 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		return err
 	}
 	defer func() {
-		file.Close()
 		if err == nil {
 			err = file.FormatCode()
 		}
+		file.Close()
 	}()
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io"),
 		codegen.SimpleImport("io/ioutil"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("os"),
@@ -101,12 +102,19 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 		return err
 	}
 	defer func() {
-		file.Close()
 		if err == nil {
 			err = file.FormatCode()
 		}
+		file.Close()
 	}()
 
+	// Copy the shared func map so that the command helpers, in particular cmdFieldType which
+	// stores complex flag values as strings, do not leak into the client package templates.
+	cmdFuncs := make(template.FuncMap, len(funcs))
+	for n, f := range funcs {
+		cmdFuncs[n] = f
+	}
+	funcs = cmdFuncs
 	funcs["defaultRouteParams"] = defaultRouteParams
 	funcs["defaultRouteTemplate"] = defaultRouteTemplate
 	funcs["joinNames"] = joinNames
@@ -118,6 +126,8 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	funcs["formatExample"] = formatExample
 	funcs["shouldAddExample"] = shouldAddExample
 	funcs["kebabCase"] = codegen.KebabCase
+	funcs["streamName"] = streamName
+	funcs["resultMethod"] = resultMethod
 
 	commandTypesTmpl := template.Must(template.New("commandTypes").Funcs(funcs).Parse(commandTypesTmpl))
 	commandsTmpl := template.Must(template.New("commands").Funcs(funcs).Parse(commandsTmpl))
@@ -128,6 +138,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io/ioutil"),
 		codegen.SimpleImport("log"),
 		codegen.SimpleImport("net/url"),
 		codegen.SimpleImport("os"),
@@ -239,7 +250,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 				"Action":          action,
 				"Resource":        action.Parent,
 				"Package":         g.Target,
-				"HasMultiContent": len(g.API.Consumes) > 1,
+				"HasMultiContent": len(g.API.Consumes) > 1 && len(action.Payloads) == 0,
 			}
 			var err error
 			if action.WebSocket() {
@@ -258,6 +269,18 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	return
 }
 
+// streamName returns the name of the generated client type that reads the results streamed by
+// the given action.
+func streamName(a *design.ActionDefinition) string {
+	return codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true) + "Stream"
+}
+
+// resultMethod returns the name of the generated client stream method that returns the final
+// result of the given action.
+func resultMethod(a *design.ActionDefinition) string {
+	return codegen.GoTypeName(a.Result, a.Result.AllRequired(), 0, false)
+}
+
 // defaultRouteParams returns the parameters needed to build the first route of the given action.
 func defaultRouteParams(a *design.ActionDefinition) *design.AttributeDefinition {
 	r := a.Routes[0]
@@ -441,6 +464,8 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 					typeHandler = "uuidVal"
 				case design.DateTime:
 					typeHandler = "timeVal"
+				case design.Duration:
+					typeHandler = "durationVal"
 				case design.Any:
 					typeHandler = "jsonVal"
 				}
@@ -456,6 +481,8 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 					typeHandler = "uuidArray"
 				case design.DateTime:
 					typeHandler = "timeArray"
+				case design.Duration:
+					typeHandler = "durationArray"
 				case design.Any:
 					typeHandler = "jsonArray"
 				}
@@ -567,6 +594,8 @@ func flagType(att *design.AttributeDefinition) string {
 		return "String"
 	case design.DateTimeKind:
 		return "String"
+	case design.DurationKind:
+		return "String"
 	case design.UUIDKind:
 		return "String"
 	case design.AnyKind:
@@ -624,11 +653,21 @@ func main() {
 	c := {{ .Package }}.New(goaclient.HTTPClientDoer(httpClient))
 
 	// Register global flags
-	app.PersistentFlags().StringVarP(&c.Scheme, "scheme", "s", "", "Set the requests scheme")
+{{ if .API.Servers }}	app.PersistentFlags().StringVarP(&c.Scheme, "scheme", "s", c.Scheme, "Set the requests scheme")
+	app.PersistentFlags().StringVarP(&c.Host, "host", "H", c.Host, "API hostname")
+{{ else }}	app.PersistentFlags().StringVarP(&c.Scheme, "scheme", "s", "", "Set the requests scheme")
 	app.PersistentFlags().StringVarP(&c.Host, "host", "H", "{{ .API.Host }}", "API hostname")
-	app.PersistentFlags().DurationVarP(&httpClient.Timeout, "timeout", "t", time.Duration(20) * time.Second, "Set the request timeout")
+{{ end }}	app.PersistentFlags().DurationVarP(&httpClient.Timeout, "timeout", "t", time.Duration(20) * time.Second, "Set the request timeout")
 	app.PersistentFlags().BoolVar(&c.Dump, "dump", false, "Dump HTTP request and response.")
-
+{{ with .API.NamedServers }}	var server string
+	app.PersistentFlags().StringVar(&server, "server", "", "Name of the server requests are sent to, overrides --scheme and --host ({{ range $i, $s := . }}{{ if $i }}, {{ end }}{{ $s.Name }}{{ end }})")
+	app.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if server == "" {
+			return nil
+		}
+		return c.UseServer(server)
+	}
+{{ end }}
 {{ if .HasSigners }}	// Register signer flags
 {{ if .HasBasicAuthSigners }} var user, pass string
 	app.PersistentFlags().StringVar(&user, "user", "", "Username used for authentication")
@@ -700,6 +739,8 @@ const commandTypesTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Name (titl
 	{{ $cmdName }} struct {
 {{ if .Payload }}		Payload string
 		ContentType string
+{{ end }}{{ if .BinaryPayload }}		DataFile string
+{{ end }}{{ if and .WebSocket (not .StreamingResult) }}		StreamFile string
 {{ end }}{{ $params := defaultRouteParams . }}{{ if $params }}{{ range $name, $att := $params.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false }}
 {{ end }}{{ end }}{{ $params := .QueryParams }}{{ if $params }}{{ range $name, $att := $params.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
@@ -737,12 +778,29 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 		goa.LogError(ctx, "failed", "err", err)
 		return err
 	}
-	go goaclient.WSWrite(ws)
-	goaclient.WSRead(ws)
-
-	return nil
+{{ if .Action.StreamingResult }}	stream := {{ .Package }}.New{{ streamName .Action }}(ws)
+{{ template "printStream" . }}{{ else }}	in := io.Reader(os.Stdin)
+	if cmd.StreamFile != "" {
+		f, err := os.Open(cmd.StreamFile)
+		if err != nil {
+			return fmt.Errorf("failed to open stream file: %s", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	if err := goaclient.WSStream(ws, in, os.Stdout); err != nil {
+		goaclient.HandleStreamError(err)
+	}
+{{ end }}	return nil
 }
-`
+` + printStreamTmpl
+
+const printStreamTmpl = `{{ define "printStream" }}	recv := func() (interface{}, error) { return stream.Recv() }
+{{ if .Action.Result }}	result := func() (interface{}, error) { return stream.{{ resultMethod .Action }}() }
+{{ end }}	if err := goaclient.PrintStream(os.Stdout, recv, {{ if .Action.Result }}result{{ else }}nil{{ end }}, stream.Close); err != nil {
+		goaclient.HandleStreamError(err)
+	}
+{{ end }}`
 
 const downloadCommandTmpl = `
 // Run downloads files with given paths.
@@ -797,6 +855,8 @@ const registerTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Action.Name (t
 func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *{{ .Package }}.Client) {
 {{ if .Action.Payload }}	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
 	cc.Flags().StringVar(&cmd.ContentType, "content", "", "Request content type override, e.g. 'application/x-www-form-urlencoded'")
+{{ end }}{{ if .Action.BinaryPayload }}	cc.Flags().StringVar(&cmd.DataFile, "data-file", "", "Path to the file containing the request body")
+{{ end }}{{ if and .Action.WebSocket (not .Action.StreamingResult) }}	cc.Flags().StringVar(&cmd.StreamFile, "stream-file", "", "Path to the file containing the newline delimited JSON messages to send, defaults to STDIN")
 {{ end }}{{ $pparams := defaultRouteParams .Action }}{{ if $pparams }}{{ range $pname, $pparam := $pparams.Type.ToObject }}{{ $tmp := goify $pname false }}{{/*
 */}}{{ if not $pparam.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $pparam.Type false }}
 {{ end }}	cc.Flags().{{ flagType $pparam }}Var(&cmd.{{ goify $pname true }}, "{{ $pname }}", {{/*
@@ -820,7 +880,17 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 {{ $default := defaultPath .Action }}{{ if $default }}	path = "{{ $default }}"
 {{ else }}{{ $pparams := defaultRouteParams .Action }}	path = fmt.Sprintf({{ printf "%q" (defaultRouteTemplate .Action) }}, {{ joinRouteParams .Action $pparams }})
 {{ end }}	}
-{{ if .Action.Payload }}var payload {{ gotyperefext .Action.Payload 2 .Package }}
+{{ if .Action.BinaryPayload }}var payload {{ gotyperefext .Action.Payload 2 .Package }}
+	if cmd.DataFile != "" {
+		data, err := ioutil.ReadFile(cmd.DataFile)
+		if err != nil {
+			return fmt.Errorf("failed to read data file: %s", err)
+		}
+		payload = {{ gotyperefext .Action.Payload 2 .Package }}(data)
+	} else if cmd.Payload != "" {
+		payload = {{ gotyperefext .Action.Payload 2 .Package }}(cmd.Payload)
+	}
+{{ else if .Action.Payload }}var payload {{ gotyperefext .Action.Payload 2 .Package }}
 	if cmd.Payload != "" {
 		err := json.Unmarshal([]byte(cmd.Payload), &payload)
 		if err != nil {
@@ -831,7 +901,7 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 {{ end }}	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
 	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
 	resp, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{ if .Action.Payload }}, {{/*
-	*/}}{{ if or .Action.Payload.Type.IsObject .Action.Payload.IsPrimitive }}&{{ end }}payload{{ else }}{{ end }}{{/*
+	*/}}{{ if and (not .Action.BinaryPayload) (or .Action.Payload.Type.IsObject .Action.Payload.IsPrimitive) }}&{{ end }}payload{{ else }}{{ end }}{{/*
 	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }}{{/*
 	*/}}{{ if and .Action.Payload .HasMultiContent }}, cmd.ContentType{{ end }})
 	if err != nil {
@@ -839,10 +909,14 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 		return err
 	}
 
-	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
-	return nil
+{{ if .Action.StreamingResult }}	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+	}
+	stream := {{ .Package }}.New{{ streamName .Action }}(resp)
+{{ template "printStream" . }}{{ else }}	goaclient.HandleResponse(c.Client, resp, cmd.PrettyPrint)
+{{ end }}	return nil
 }
-`
+` + printStreamTmpl
 
 // Takes map[string][]*design.ActionDefinition as input
 const registerCmdsT = `// RegisterCommands registers the resource action CLI commands.
@@ -965,6 +1039,29 @@ func timeArray(ins []string) ([]time.Time, error) {
 	return vals, nil
 }
 
+func durationVal(val string) (*time.Duration, error) {
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func durationArray(ins []string) ([]time.Duration, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []time.Duration
+	for _, id := range ins {
+		val, err := durationVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
 func uuidVal(val string) (*uuid.UUID, error) {
 	t, err := uuid.FromString(val)
 	if err != nil {
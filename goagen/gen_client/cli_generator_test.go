@@ -352,6 +352,19 @@ var _ = Describe("Generate", func() {
 
 		})
 
+		It("generates query options using the native parameter types", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			content := string(c)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("func ShowFooWithBool(bool bool) goaclient.QueryOption {"))
+			Ω(content).Should(ContainSubstring("func ShowFooWithNumber(number float64) goaclient.QueryOption {"))
+			Ω(content).Should(ContainSubstring("func ShowFooWithTime(time_ time.Time) goaclient.QueryOption {"))
+			Ω(content).Should(ContainSubstring("func ShowFooWithUUID(uuid uuid.UUID) goaclient.QueryOption {"))
+			_, err = gexec.Build(filepath.Join(testgenPackagePath, "client"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
 		Context("with an action with a multiline description", func() {
 			const multiline = "multi\nline"
 
@@ -434,6 +447,31 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with named servers", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+				Consumes:    design.DefaultEncoders,
+				Servers: []*design.ServerDefinition{
+					{URL: "https://api.example.com", Name: "production"},
+					{URL: "https://staging.example.com", Name: "staging"},
+				},
+			}
+		})
+
+		It("registers the server flag listing the server names", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "testapi-cli", "main.go"))
+			content := string(c)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`app.PersistentFlags().StringVarP(&c.Host, "host", "H", c.Host, "API hostname")`))
+			Ω(content).Should(ContainSubstring(`app.PersistentFlags().StringVar(&server, "server", "", "Name of the server requests are sent to, overrides --scheme and --host (production, staging)")`))
+			Ω(content).Should(ContainSubstring("return c.UseServer(server)"))
+		})
+	})
+
 	Context("with an action with security configured", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
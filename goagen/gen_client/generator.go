@@ -3,6 +3,7 @@ package genclient
 import (
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -119,7 +120,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 			}
 
 			cliDir = filepath.Join(g.OutDir, g.ToolDirName, "cli")
-			if err = os.RemoveAll(cliDir); err != nil {
+			if err = codegen.CleanDir(cliDir); err != nil {
 				return
 			}
 			if err = os.MkdirAll(cliDir, 0755); err != nil {
@@ -128,7 +129,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 		}
 
 		pkgDir = filepath.Join(g.OutDir, g.Target)
-		if err = os.RemoveAll(pkgDir); err != nil {
+		if err = codegen.CleanDir(pkgDir); err != nil {
 			return
 		}
 		if err = os.MkdirAll(pkgDir, 0755); err != nil {
@@ -233,10 +234,10 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 		}
 	}
 	defer func() {
-		file.Close()
 		if err == nil {
 			err = file.FormatCode()
 		}
+		file.Close()
 	}()
 	clientTmpl := template.Must(template.New("client").Funcs(funcs).Parse(clientTmpl))
 
@@ -266,7 +267,10 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 
 	// Setup codegen
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
@@ -281,14 +285,35 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 	g.genfiles = append(g.genfiles, clientFile)
 
 	// Generate
+	hasSignatures := false
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		signed := func(a *design.ActionDefinition) error {
+			if a.Signature != nil {
+				hasSignatures = true
+			}
+			return nil
+		}
+		r.IterateActions(signed)
+		return r.IterateWebhooks(signed)
+	})
+	var defaultServer *url.URL
+	if s := g.API.DefaultServer(); s != nil {
+		defaultServer, _ = url.Parse(s.DefaultURL())
+	}
 	data := struct {
-		API      *design.APIDefinition
-		Encoders []*genapp.EncoderTemplateData
-		Decoders []*genapp.EncoderTemplateData
+		API           *design.APIDefinition
+		Encoders      []*genapp.EncoderTemplateData
+		Decoders      []*genapp.EncoderTemplateData
+		Servers       []*design.ServerDefinition
+		DefaultServer *url.URL
+		HasSignatures bool
 	}{
-		API:      g.API,
-		Encoders: encoders,
-		Decoders: decoders,
+		API:           g.API,
+		Encoders:      encoders,
+		Decoders:      decoders,
+		Servers:       g.API.NamedServers(),
+		DefaultServer: defaultServer,
+		HasSignatures: hasSignatures,
 	}
 	err = clientTmpl.Execute(file, data)
 	return
@@ -311,6 +336,7 @@ func (g *Generator) generateClientResources(pkgDir, clientPkg string, funcs temp
 func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDefinition, funcs template.FuncMap) (err error) {
 	payloadTmpl := template.Must(template.New("payload").Funcs(funcs).Parse(payloadTmpl))
 	pathTmpl := template.Must(template.New("pathTemplate").Funcs(funcs).Parse(pathTmpl))
+	queryOptionsTmpl := template.Must(template.New("queryOptions").Funcs(funcs).Parse(queryOptionsTmpl))
 
 	resFilename := codegen.SnakeCase(res.Name)
 	if resFilename == typesFileName {
@@ -325,10 +351,10 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		return err
 	}
 	defer func() {
-		file.Close()
 		if err == nil {
 			err = file.FormatCode()
 		}
+		file.Close()
 	}()
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("bytes"),
@@ -347,6 +373,8 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	title := fmt.Sprintf("%s: %s Resource Client", g.API.Context(), res.Name)
@@ -378,6 +406,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 				}
 			}
 		}
+		queryOptions := queryOptionParams(action)
 		for i, r := range action.Routes {
 			routeParams := r.Params()
 			var pd []*paramData
@@ -385,33 +414,126 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 			for _, p := range routeParams {
 				requiredParams, _ := initParams(&design.AttributeDefinition{
 					Type: &design.Object{
-						p: action.Params.Type.ToObject()[p],
+						p: action.AllParams().Type.ToObject()[p],
 					},
 					Validation: &dslengine.ValidationDefinition{
 						Required: routeParams,
 					},
 				})
+				for _, rp := range requiredParams {
+					rp.CatchAll = strings.Contains(r.FullPath(), "/*"+p)
+				}
 				pd = append(pd, requiredParams...)
 			}
 
 			data := struct {
-				Route  *design.RouteDefinition
-				Index  int
-				Params []*paramData
+				Route    *design.RouteDefinition
+				Index    int
+				Params   []*paramData
+				HasQuery bool
 			}{
-				Route:  r,
-				Index:  i,
-				Params: pd,
+				Route:    r,
+				Index:    i,
+				Params:   pd,
+				HasQuery: len(queryOptions) > 0,
 			}
 			if err := pathTmpl.Execute(file, data); err != nil {
 				return err
 			}
 		}
-		return g.generateActionClient(action, file, funcs)
+		if len(queryOptions) > 0 {
+			data := struct {
+				Name         string
+				ResourceName string
+				Params       []*paramData
+			}{
+				Name:         action.Name,
+				ResourceName: res.Name,
+				Params:       queryOptions,
+			}
+			if err := queryOptionsTmpl.Execute(file, data); err != nil {
+				return err
+			}
+		}
+		if err := g.generateActionClient(action, file, funcs); err != nil {
+			return err
+		}
+		if action.StreamingResult != nil {
+			return g.generateStreamClient(action, file, funcs)
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	err = res.IterateWebhooks(func(webhook *design.ActionDefinition) error {
+		if webhook.Payload != nil {
+			if _, ok := design.Design.Types[webhook.Payload.TypeName]; !ok {
+				if err := payloadTmpl.Execute(file, webhook); err != nil {
+					return err
+				}
+			}
+		}
+		return g.generateWebhookClient(webhook, file, funcs)
 	})
 	return
 }
 
+// generateWebhookClient generates the client method that sends the given webhook request to a
+// URL registered by a client.
+func (g *Generator) generateWebhookClient(webhook *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
+	webhookTmpl := template.Must(template.New("webhook").Funcs(funcs).Parse(webhookTmpl))
+	var payload string
+	if webhook.Payload != nil {
+		payload = codegen.GoTypeRef(webhook.Payload, webhook.Payload.AllRequired(), 1, false)
+	}
+	contentType := design.Design.Consumes[0].MIMETypes[0]
+	if len(webhook.Consumes) > 0 {
+		contentType = webhook.Consumes[0]
+	}
+	data := struct {
+		Name         string
+		ResourceName string
+		Description  string
+		Route        *design.RouteDefinition
+		Payload      string
+		ContentType  string
+		Signature    *design.SignatureDefinition
+	}{
+		Name:         webhook.Name,
+		ResourceName: webhook.Parent.Name,
+		Description:  webhook.Description,
+		Route:        webhook.Routes[0],
+		Payload:      payload,
+		ContentType:  contentType,
+		Signature:    webhook.Signature,
+	}
+	return webhookTmpl.Execute(file, data)
+}
+
+// generateStreamClient generates the type used to read the results streamed by the given action
+// and its final result if any.
+func (g *Generator) generateStreamClient(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
+	streamTmpl := template.Must(template.New("stream").Funcs(funcs).Parse(streamTmpl))
+	data := struct {
+		Name            string
+		ActionName      string
+		ResourceName    string
+		Transport       string
+		StreamingResult *design.UserTypeDefinition
+		Result          *design.UserTypeDefinition
+	}{
+		Name:            codegen.Goify(action.Name, true) + codegen.Goify(action.Parent.Name, true),
+		ActionName:      action.Name,
+		ResourceName:    action.Parent.Name,
+		Transport:       action.StreamTransport(),
+		StreamingResult: action.StreamingResult,
+		Result:          action.Result,
+	}
+	return streamTmpl.Execute(file, data)
+}
+
 func (g *Generator) generateFileServer(file *codegen.SourceFile, fs *design.FileServerDefinition, funcs template.FuncMap) error {
 	var (
 		dir string
@@ -493,6 +615,16 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 	if action.Security != nil {
 		signer = codegen.Goify(action.Security.Scheme.SchemeName, true)
 	}
+	defaultContentType := design.Design.Consumes[0].MIMETypes[0]
+	if len(action.Consumes) > 0 {
+		defaultContentType = action.Consumes[0]
+	}
+	hasMultiContent := len(design.Design.Consumes) > 1 && len(action.Payloads) == 0
+	if action.BinaryPayload() {
+		binary := action.BinaryMIMETypes()
+		defaultContentType = binary[0]
+		hasMultiContent = len(binary) > 1
+	}
 	data := struct {
 		Name               string
 		ResourceName       string
@@ -500,15 +632,22 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Routes             []*design.RouteDefinition
 		Payload            *design.UserTypeDefinition
 		PayloadMultipart   bool
+		Payloads           []*design.PayloadDefinition
 		HasPayload         bool
 		HasMultiContent    bool
+		TextPayload        bool
+		TextContentType    bool
+		BinaryPayload      bool
 		DefaultContentType string
 		Params             string
 		ParamNames         string
 		CanonicalScheme    string
 		Signer             string
+		Signature          *design.SignatureDefinition
 		QueryParams        []*paramData
 		Headers            []*paramData
+		Idempotent         bool
+		Cacheable          bool
 	}{
 		Name:               action.Name,
 		ResourceName:       action.Parent.Name,
@@ -516,15 +655,22 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Routes:             action.Routes,
 		Payload:            action.Payload,
 		PayloadMultipart:   action.PayloadMultipart,
+		Payloads:           action.Payloads,
 		HasPayload:         action.Payload != nil,
-		HasMultiContent:    len(design.Design.Consumes) > 1,
-		DefaultContentType: design.Design.Consumes[0].MIMETypes[0],
+		HasMultiContent:    hasMultiContent,
+		TextPayload:        action.Payload != nil && design.IsText(action.Payload),
+		TextContentType:    strings.HasPrefix(defaultContentType, "text/"),
+		BinaryPayload:      action.BinaryPayload(),
+		DefaultContentType: defaultContentType,
 		Params:             strings.Join(params, ", "),
 		ParamNames:         strings.Join(names, ", "),
 		CanonicalScheme:    action.CanonicalScheme(),
 		Signer:             signer,
+		Signature:          action.Signature,
 		QueryParams:        queryParams,
 		Headers:            headers,
+		Idempotent:         action.IsIdempotent() && action.StreamingResult == nil,
+		Cacheable:          action.IsCacheable() && action.StreamingResult == nil,
 	}
 	if action.WebSocket() {
 		return clientsWSTmpl.Execute(file, data)
@@ -583,10 +729,10 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) (e
 		}
 	}
 	defer func() {
-		mtWr.Close()
 		if err == nil {
 			err = mtWr.FormatCode()
 		}
+		mtWr.Close()
 	}()
 	title := fmt.Sprintf("%s: Application Media Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
@@ -595,6 +741,7 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) (e
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
+		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	for _, v := range g.API.MediaTypes {
@@ -637,10 +784,10 @@ func (g *Generator) generateUserTypes(pkgDir string) (err error) {
 		}
 	}
 	defer func() {
-		utWr.Close()
 		if err == nil {
 			err = utWr.FormatCode()
 		}
+		utWr.Close()
 	}()
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
@@ -761,9 +908,9 @@ func cmdFieldTypeString(t design.DataType, point bool) string {
 	if point && !t.IsArray() {
 		pointer = "*"
 	}
-	if t.Kind() == design.UUIDKind || t.Kind() == design.DateTimeKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind {
+	if t.Kind() == design.UUIDKind || t.Kind() == design.DateTimeKind || t.Kind() == design.DurationKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind {
 		suffix = "string"
-	} else if isArrayOfType(t, design.UUIDKind, design.DateTimeKind, design.AnyKind, design.NumberKind, design.BooleanKind) {
+	} else if isArrayOfType(t, design.UUIDKind, design.DateTimeKind, design.DurationKind, design.AnyKind, design.NumberKind, design.BooleanKind) {
 		suffix = "[]string"
 	} else {
 		suffix = codegen.GoNativeType(t)
@@ -802,7 +949,19 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 		case design.StringKind:
 			return fmt.Sprintf("%s := %s", target, name)
 		case design.DateTimeKind:
-			return fmt.Sprintf("%s := %s.Format(time.RFC3339)", target, strings.Replace(name, "*", "", -1)) // remove pointer if present
+			name = strings.Replace(name, "*", "", -1) // remove pointer if present
+			switch att.TimeFormat() {
+			case design.TimeFormatUnix:
+				return fmt.Sprintf("%s := strconv.FormatInt(%s.Unix(), 10)", target, name)
+			case design.TimeFormatUnixMilli:
+				return fmt.Sprintf("%s := strconv.FormatInt(%s.UnixNano()/int64(time.Millisecond), 10)", target, name)
+			}
+			return fmt.Sprintf("%s := %s.Format(time.RFC3339)", target, name)
+		case design.DurationKind:
+			if att.TimeFormat() == design.DurationFormatMilli {
+				return fmt.Sprintf("%s := strconv.FormatInt(int64(%s/time.Millisecond), 10)", target, name)
+			}
+			return fmt.Sprintf("%s := %s.String()", target, name)
 		case design.UUIDKind:
 			return fmt.Sprintf("%s := %s.String()", target, strings.Replace(name, "*", "", -1)) // remove pointer if present
 		case design.AnyKind:
@@ -928,6 +1087,39 @@ func initParams(att *design.AttributeDefinition) ([]*paramData, []*paramData) {
 	return reqParamData, optParamData
 }
 
+// queryOptionParams returns the data needed to generate the query options of the path functions
+// of the action sorted by name, one per query string parameter.
+func queryOptionParams(action *design.ActionDefinition) []*paramData {
+	if action.QueryParams == nil {
+		return nil
+	}
+	obj := action.QueryParams.Type.ToObject()
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	params := make([]*paramData, len(names))
+	for i, n := range names {
+		att := obj[n]
+		varName := codegen.Goify(n, false)
+		param := &paramData{
+			Name:         n,
+			VarName:      varName,
+			ValueName:    varName,
+			Attribute:    att,
+			MustToString: att.Type.Kind() != design.StringKind,
+		}
+		if att.Type.IsArray() {
+			param.IsArray = true
+			param.ElemAttribute = att.Type.ToArray().ElemType
+			param.MustToString = param.ElemAttribute.Type.Kind() != design.StringKind
+		}
+		params[i] = param
+	}
+	return params
+}
+
 // paramData is the data structure holding the information needed to generate query params and
 // headers handling code.
 type paramData struct {
@@ -939,6 +1131,7 @@ type paramData struct {
 	MustToString  bool
 	IsArray       bool
 	CheckNil      bool
+	CatchAll      bool
 }
 
 type byParamName []*paramData
@@ -960,23 +1153,43 @@ type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
 `
 
 	typeDecodeTmpl = `{{ $typeName := typeName . }}{{ $funcName := printf "Decode%s" $typeName }}// {{ $funcName }} decodes the {{ $typeName }} instance encoded in resp body.
+// The returned error is a *goaclient.DecodeError that includes the beginning of the body if
+// decoding fails.
 func (c *Client) {{ $funcName }}(resp *http.Response) ({{ decodegotyperef . .AllRequired 0 false }}, error) {
 	var decoded {{ decodegotypename . .AllRequired 0 false }}
-	err := c.Decoder.Decode(&decoded, resp.Body, resp.Header.Get("Content-Type"))
+	err := goaclient.DecodeResponse(c.Decoder, &decoded, resp)
 	return {{ if .IsObject }}&{{ end }}decoded, err
 }
 `
 
 	pathTmpl = `{{ $funcName := printf "%sPath%s" (goify (printf "%s%s" .Route.Parent.Name (title .Route.Parent.Parent.Name)) true) ((or (and .Index (add .Index 1)) "") | printf "%v") }}{{/*
-*/}}// {{ $funcName }} computes a request path to the {{ .Route.Parent.Name }} action of {{ .Route.Parent.Parent.Name }}.
-func {{ $funcName }}({{ pathParams .Route }}) string {
+*/}}// {{ $funcName }} computes a request path to the {{ .Route.Parent.Name }} action of {{ .Route.Parent.Parent.Name }}.{{ if .HasQuery }}
+// The query options set the query string parameters of the path.{{ end }}
+func {{ $funcName }}({{ pathParams .Route }}{{ if .HasQuery }}{{ if .Params }}, {{ end }}opts ...goaclient.QueryOption{{ end }}) string {
 	{{ range $i, $param := .Params }}{{/*
 */}}{{ toString $param.VarName (printf "param%d" $i) $param.Attribute }}
 	{{ end }}
-	return fmt.Sprintf({{ printf "%q" (pathTemplate .Route) }}{{ range $i, $param := .Params }}, {{ printf "param%d" $i }}{{ end }})
+	return {{ if .HasQuery }}goaclient.AppendQuery({{ end }}fmt.Sprintf({{ printf "%q" (pathTemplate .Route) }}{{ range $i, $param := .Params }}, {{ if $param.CatchAll }}goaclient.EscapeCatchAll{{ else }}url.PathEscape{{ end }}({{ printf "param%d" $i }}){{ end }}){{ if .HasQuery }}, opts...){{ end }}
 }
 `
 
+	queryOptionsTmpl = `{{ $prefix := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ range .Params }}{{/*
+*/}}{{ $funcName := printf "%sWith%s" $prefix (goify .Name true) }}
+// {{ $funcName }} sets the {{ printf "%q" .Name }} query string parameter of the paths to the {{ $.Name }} action of {{ $.ResourceName }}.
+func {{ $funcName }}({{ .VarName }} {{ cmdFieldType .Attribute.Type false }}) goaclient.QueryOption {
+	return func(values url.Values) {
+{{ if .IsArray }}		for _, p := range {{ .VarName }} {
+{{ if .MustToString }}			{{ toString "p" "s" .ElemAttribute }}
+			values.Add("{{ .Name }}", s)
+{{ else }}			values.Add("{{ .Name }}", p)
+{{ end }}		}
+{{ else if .MustToString }}		{{ toString .ValueName "s" .Attribute }}
+		values.Set("{{ .Name }}", s)
+{{ else }}		values.Set("{{ .Name }}", {{ .ValueName }})
+{{ end }}	}
+}
+{{ end }}`
+
 	clientsTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ $desc := .Description }}{{/*
 */}}{{ if $desc }}{{ multiComment $desc }}{{ else }}{{/*
 */}}// {{ $funcName }} makes a request to the {{ .Name }} action endpoint of the {{ .ResourceName }} resource{{ end }}
@@ -985,7 +1198,7 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	if err != nil {
 		return nil, err
 	}
-	return c.Client.Do(ctx, req)
+	return c.Client.{{ if .Cacheable }}DoCacheable{{ else if .Idempotent }}DoIdempotent{{ else }}Do{{ end }}(ctx, req)
 }
 `
 
@@ -996,7 +1209,8 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	if scheme == "" {
 		scheme = "{{ .CanonicalScheme }}"
 	}
-	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	u := url.URL{Host: c.Host, Scheme: scheme}
+	goaclient.SetURLPath(&u, path)
 {{ if .QueryParams }}	values := u.Query()
 {{ range .QueryParams }}{{ if .CheckNil }}	if {{ .VarName }} != nil {
 	{{ end }}{{/*
@@ -1111,18 +1325,40 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 {{ end }}{{ end }}	if err := w.Close(); err != nil {
 		return nil, err
 	}
+{{ else if .Payloads }}	var contentType string
+	switch {
+{{ range .Payloads }}	case payload.{{ goify .AttributeName true }} != nil:
+		contentType = {{ printf "%q" .ContentType }}
+{{ if .Raw }}		body.WriteString(*payload.{{ goify .AttributeName true }})
+{{ else }}		if err := c.Encoder.Encode(payload.{{ goify .AttributeName true }}, &body, contentType); err != nil {
+			return nil, fmt.Errorf("failed to encode body: %s", err)
+		}
+{{ end }}{{ end }}	default:
+		return nil, fmt.Errorf("payload is empty")
+	}
+{{ else if .BinaryPayload }}{{ if .HasMultiContent }}	if contentType == "" {
+		contentType = "{{ .DefaultContentType }}"
+	}
+{{ end }}	body.Write(payload)
 {{ else }}{{ if .HasMultiContent }}	if contentType == "" {
 		contentType = "*/*" // Use default encoder
 	}
-{{ end }}	err := c.Encoder.Encode(payload, &body, {{ if .HasMultiContent }}contentType{{ else }}"*/*"{{ end }})
+{{ end }}{{ if and .TextPayload .HasMultiContent }}	if strings.HasPrefix(contentType, "text/") {
+		fmt.Fprint(&body, payload)
+	} else if err := c.Encoder.Encode(payload, &body, contentType); err != nil {
+		return nil, fmt.Errorf("failed to encode body: %s", err)
+	}
+{{ else if and .TextPayload .TextContentType }}	fmt.Fprint(&body, payload)
+{{ else }}	err := c.Encoder.Encode(payload, &body, {{ if .HasMultiContent }}contentType{{ else }}"*/*"{{ end }})
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode body: %s", err)
 	}
-{{ end }}{{ end }}	scheme := c.Scheme
+{{ end }}{{ end }}{{ end }}	scheme := c.Scheme
 	if scheme == "" {
 		scheme = "{{ .CanonicalScheme }}"
 	}
-	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+	u := url.URL{Host: c.Host, Scheme: scheme}
+	goaclient.SetURLPath(&u, path)
 {{ if .QueryParams }}	values := u.Query()
 {{ range .QueryParams }}{{/*
 
@@ -1153,6 +1389,8 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	}
 {{ if or .HasPayload .Headers }}	header := req.Header
 {{ if .PayloadMultipart }}	header.Set("Content-Type", w.FormDataContentType())
+{{ else if .Payloads }}	header.Set("Content-Type", contentType)
+{{ else if and .BinaryPayload .HasMultiContent }}	header.Set("Content-Type", contentType)
 {{ else }}{{ if .HasPayload }}{{ if .HasMultiContent }}	if contentType == "*/*" {
 		header.Set("Content-Type", "{{ .DefaultContentType }}")
 	} else {
@@ -1169,8 +1407,83 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 			return nil, err
 		}
 	}
+{{ end }}{{ with .Signature }}	if err := c.signRequest(req, {{ printf "%q" .Header }}, {{ printf "%q" .Algorithm }}, {{ if $.HasPayload }}body.Bytes(){{ else }}nil{{ end }}); err != nil {
+		return nil, err
+	}
 {{ end }}	return req, nil
 }
+`
+
+	streamTmpl = `
+{{ $stream := printf "%sStream" .Name }}{{ $res := .StreamingResult }}// {{ $stream }} reads the results streamed by the {{ .ActionName }} action of the {{ .ResourceName }} resource{{ if .Result }}
+// followed by its final result{{ end }}.
+type {{ $stream }} struct {
+	reader *goaclient.ResultStreamReader
+}
+
+{{ if eq .Transport "websocket" }}// New{{ $stream }} returns a stream that reads the results from the given websocket connection.
+func New{{ $stream }}(ws *websocket.Conn) *{{ $stream }} {
+	recv := func(f *goa.StreamFrame) error { return websocket.JSON.Receive(ws, f) }
+	return &{{ $stream }}{reader: goaclient.NewResultStreamReader(recv, ws.Close)}
+}
+{{ else if eq .Transport "ndjson" }}// New{{ $stream }} returns a stream that reads the newline delimited JSON results from the
+// response body.
+func New{{ $stream }}(resp *http.Response) *{{ $stream }} {
+	return &{{ $stream }}{reader: goaclient.NewNDJSONResultStreamReader(resp.Body)}
+}
+{{ else }}// New{{ $stream }} returns a stream that reads the server-sent events from the response body.
+func New{{ $stream }}(resp *http.Response) *{{ $stream }} {
+	return &{{ $stream }}{reader: goaclient.NewSSEResultStreamReader(resp.Body)}
+}
+{{ end }}
+// Recv returns the next streamed result, io.EOF once the stream ends.
+func (s *{{ $stream }}) Recv() ({{ gotyperef $res $res.AllRequired 0 false }}, error) {
+	var v {{ gotypename $res $res.AllRequired 0 false }}
+	if err := s.reader.Recv(&v); err != nil {
+		return {{ if $res.IsObject }}nil{{ else }}v{{ end }}, err
+	}
+	return {{ if $res.IsObject }}&{{ end }}v, nil
+}
+{{ with .Result }}{{ $name := gotypename . .AllRequired 0 false }}
+// {{ $name }} returns the final result once Recv has returned io.EOF, nil if the stream ended
+// without a final result.
+func (s *{{ $stream }}) {{ $name }}() ({{ gotyperef . .AllRequired 0 false }}, error) {
+	var v {{ $name }}
+	if ok, err := s.reader.Result(&v); !ok || err != nil {
+		return {{ if .IsObject }}nil{{ else }}v{{ end }}, err
+	}
+	return {{ if .IsObject }}&{{ end }}v, nil
+}
+{{ end }}
+// Close closes the stream.
+func (s *{{ $stream }}) Close() error {
+	return s.reader.Close()
+}
+`
+
+	webhookTmpl = `{{ $funcName := goify (printf "Send%s%sWebhook" (title .Name) (title .ResourceName)) true }}{{/*
+*/}}{{ if .Description }}{{ multiComment .Description }}{{ else }}{{/*
+*/}}// {{ $funcName }} sends the {{ .Name }} webhook request of the {{ .ResourceName }} resource to webhookURL.{{ end }}
+func (c *Client) {{ $funcName }}(ctx context.Context, webhookURL string{{ if .Payload }}, payload {{ .Payload }}{{ end }}) (*http.Response, error) {
+{{ if .Payload }}	var body bytes.Buffer
+	if err := c.Encoder.Encode(payload, &body, {{ printf "%q" .ContentType }}); err != nil {
+		return nil, fmt.Errorf("failed to encode body: %s", err)
+	}
+{{ end }}	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+{{ if and .Route.Path (ne .Route.Path "/") }}	u.Path = path.Join(u.Path, {{ printf "%q" .Route.Path }})
+{{ end }}	req, err := http.NewRequest({{ printf "%q" .Route.Verb }}, u.String(), {{ if .Payload }}&body{{ else }}nil{{ end }})
+	if err != nil {
+		return nil, err
+	}
+{{ if .Payload }}	req.Header.Set("Content-Type", {{ printf "%q" .ContentType }})
+{{ end }}{{ with .Signature }}	if err := c.signRequest(req, {{ printf "%q" .Header }}, {{ printf "%q" .Algorithm }}, {{ if $.Payload }}body.Bytes(){{ else }}nil{{ end }}); err != nil {
+		return nil, err
+	}
+{{ end }}	return c.Client.Do(ctx, req)
+}
 `
 
 	clientTmpl = `// Client is the {{ .API.Name }} service client.
@@ -1178,17 +1491,25 @@ type Client struct {
 	*goaclient.Client{{range $security := .API.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}
 	{{ goify $security.SchemeName true }}Signer goaclient.Signer{{ end }}{{ end }}
 	Encoder *goa.HTTPEncoder
-	Decoder *goa.HTTPDecoder
+	Decoder *goa.HTTPDecoder{{ if .HasSignatures }}
+	// SignatureKeyProvider returns the key used to sign the requests of signed actions.
+	SignatureKeyProvider goa.SignatureKeyProvider{{ end }}
 }
 
-// New instantiates the client.
-func New(c goaclient.Doer) *Client {
+// Option configures the client created by New.
+type Option func(*Client)
+
+// New instantiates the client.{{ with .DefaultServer }} The client sends requests to {{ .String }}
+// unless configured otherwise.{{ end }}
+func New(c goaclient.Doer, opts ...Option) *Client {
 	client := &Client{
 		Client: goaclient.New(c),
 		Encoder: goa.NewHTTPEncoder(),
 		Decoder: goa.NewHTTPDecoder(),
 	}
-
+{{ with .DefaultServer }}	client.Scheme = {{ printf "%q" .Scheme }}
+	client.Host = {{ printf "%q" .Host }}
+{{ end }}
 {{ if .Encoders }}	// Setup encoders and decoders
 {{ range .Encoders }}{{/*
 */}}	client.Encoder.Register({{ .PackageName }}.{{ .Function }}, "{{ joinStrings .MIMETypes "\", \"" }}")
@@ -1202,10 +1523,82 @@ func New(c goaclient.Doer) *Client {
 {{ end }}{{ end }}{{ range .Decoders }}{{ if .Default }}{{/*
 */}}	client.Decoder.Register({{ .PackageName }}.{{ .Function }}, "*/*")
 {{ end }}{{ end }}
-{{ end }}	return client
+{{ end }}	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// WithSingleflight configures the client to coalesce concurrent identical requests made to
+// idempotent actions into a single HTTP call. Each caller gets its own copy of the response. The
+// shared call is not canceled when the context of one of the callers is canceled and its error is
+// returned to all the callers.
+func WithSingleflight() Option {
+	return func(c *Client) {
+		c.Singleflight = goaclient.NewSingleflight()
+	}
+}
+
+// WithCache configures the client to cache the responses to the GET requests made to cacheable
+// actions in store. Fresh responses are served from the cache, stale responses that have an ETag
+// are revalidated with conditional requests. Use goaclient.NewLRUCache for an in-memory store.
+func WithCache(store goaclient.CacheStore) Option {
+	return func(c *Client) {
+		c.Cache = store
+	}
+}
+{{ if .Servers }}
+// ServerNames lists the names of the {{ .API.Name }} servers, see WithServer.
+var ServerNames = []string{ {{ range $i, $s := .Servers }}{{ if $i }}, {{ end }}{{ printf "%q" $s.Name }}{{ end }} }
+
+// ServerURLs maps the names of the {{ .API.Name }} servers to their URLs.
+var ServerURLs = map[string]string{
+{{ range .Servers }}	{{ printf "%q" .Name }}: {{ printf "%q" .DefaultURL }},
+{{ end }}}
+
+// WithServer configures the client to send requests to the server with the given name. The
+// client keeps using the default server if there is no server with that name, see UseServer.
+func WithServer(name string) Option {
+	return func(c *Client) {
+		c.UseServer(name)
+	}
+}
+
+// UseServer configures the client to send requests to the server with the given name. It returns
+// an error if there is no server with that name.
+func (c *Client) UseServer(name string) error {
+	u, ok := ServerURLs[name]
+	if !ok {
+		return fmt.Errorf("unknown server %q, server must be one of %s", name, strings.Join(ServerNames, ", "))
+	}
+	su, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+	c.Scheme = su.Scheme
+	c.Host = su.Host
+	return nil
+}
+{{ end }}
+
+{{ if .HasSignatures }}// signRequest sets the given request header to the signature of the request body.
+func (c *Client) signRequest(req *http.Request, header, algorithm string, body []byte) error {
+	if c.SignatureKeyProvider == nil {
+		return fmt.Errorf("cannot sign request, client has no signature key provider")
+	}
+	key, err := c.SignatureKeyProvider(req)
+	if err != nil {
+		return err
+	}
+	sig, err := goa.Sign(algorithm, key, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(header, sig)
+	return nil
 }
 
-{{range $security := .API.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}{{/*
+{{ end }}{{range $security := .API.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}{{/*
 */}}{{ $name := printf "%sSigner" (goify $security.SchemeName true) }}{{/*
 */}}// Set{{ $name }} sets the request signer for the {{ $security.SchemeName }} security scheme.
 func (c *Client) Set{{ $name }}(signer goaclient.Signer) {
@@ -226,7 +226,66 @@ var _ = Describe("Generate", func() {
 	param1 := strings.Join(tmp2, ",")`))
 			Ω(content).Should(ContainSubstring(`param2 := baz.Format(time.RFC3339)`))
 			Ω(content).Should(ContainSubstring(`param3 := bat.String()`))
-			Ω(content).Should(ContainSubstring(`fmt.Sprintf("/foo/%s/bar/%s/baz/%s/bat/%s", param0, param1, param2, param3)`))
+			Ω(content).Should(ContainSubstring(`fmt.Sprintf("/foo/%s/bar/%s/baz/%s/bat/%s", url.PathEscape(param0), url.PathEscape(param1), url.PathEscape(param2), url.PathEscape(param3))`))
+		})
+
+		It("caches the responses to cacheable actions", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(c)).Should(ContainSubstring("c.Client.DoCacheable(ctx, req)"))
+		})
+
+		Context("with caching disabled", func() {
+			BeforeEach(func() {
+				showAct := design.Design.Resources["foo"].Actions["show"]
+				showAct.Metadata = dslengine.MetadataDefinition{design.CacheableMetadataKey: {"false"}}
+			})
+
+			It("coalesces the requests made to idempotent actions", func() {
+				Ω(genErr).Should(BeNil())
+				c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(c)).Should(ContainSubstring("c.Client.DoIdempotent(ctx, req)"))
+			})
+		})
+	})
+
+	Context("with a catch-all path param", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			o := design.Object{
+				"path": &design.AttributeDefinition{Type: design.String},
+			}
+			design.Design = &design.APIDefinition{
+				Name:     "testapi",
+				Consumes: design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name:   "show",
+								Params: &design.AttributeDefinition{Type: o},
+								Routes: []*design.RouteDefinition{
+									{Verb: "GET", Path: "/files/*path"},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("does not escape the slashes of the param value", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(c)).Should(ContainSubstring(`fmt.Sprintf("/files/%s", goaclient.EscapeCatchAll(param0))`))
 		})
 	})
 
@@ -288,6 +347,23 @@ var _ = Describe("Generate", func() {
 		values.Set("fields[bat]", tmp4)`))
 		})
 
+		It("generates typed query options for the path functions", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(c)
+			Ω(content).Should(ContainSubstring("func ShowFooPath(opts ...goaclient.QueryOption) string {"))
+			Ω(content).Should(ContainSubstring(`return goaclient.AppendQuery(fmt.Sprintf("/"), opts...)`))
+			Ω(content).Should(ContainSubstring(`func ShowFooWithFieldsBaz(fieldsBaz []int) goaclient.QueryOption {
+	return func(values url.Values) {
+		for _, p := range fieldsBaz {
+			s := strconv.Itoa(p)
+			values.Add("fields[baz]", s)
+		}
+	}
+}`))
+		})
+
 		Context("with --notool", func() {
 			BeforeEach(func() {
 				os.Args = append(os.Args, "--notool")
@@ -566,6 +642,342 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with an action with a primitive payload", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+				Consumes:    design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"update": {
+								Name: "update",
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "PUT",
+										Path: "",
+									},
+								},
+								Payload: &design.UserTypeDefinition{
+									AttributeDefinition: &design.AttributeDefinition{Type: design.Integer},
+									TypeName:            "UpdateFooPayload",
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			updateAct := fooRes.Actions["update"]
+			updateAct.Parent = fooRes
+			updateAct.Routes[0].Parent = updateAct
+		})
+
+		It("writes text bodies as is", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`if strings.HasPrefix(contentType, "text/") {`))
+			Ω(content).Should(ContainSubstring("fmt.Fprint(&body, payload)"))
+			Ω(content).Should(ContainSubstring("} else if err := c.Encoder.Encode(payload, &body, contentType); err != nil {"))
+		})
+	})
+
+	Context("with an action with an array header", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+				Consumes:    design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "GET",
+										Path: "",
+									},
+								},
+								Headers: &design.AttributeDefinition{
+									Type: design.Object{
+										"X-Ids": &design.AttributeDefinition{
+											Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("encodes the header values as a comma separated list", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("strconv.Itoa(e)"))
+			Ω(content).Should(ContainSubstring(`strings.Join(tmp`))
+			Ω(content).Should(ContainSubstring(`header.Set("X-Ids", tmp`))
+		})
+	})
+
+	Context("with a signed action", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+				Consumes:    design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"deliver": {
+								Name: "deliver",
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "POST",
+										Path: "",
+									},
+								},
+								Payload: &design.UserTypeDefinition{
+									AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+									TypeName:            "Delivery",
+								},
+								Signature: &design.SignatureDefinition{
+									Header:    "X-Signature",
+									Algorithm: "hmac-sha256",
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			deliverAct := fooRes.Actions["deliver"]
+			deliverAct.Parent = fooRes
+			deliverAct.Routes[0].Parent = deliverAct
+		})
+
+		It("signs the request body after encoding it", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`if err := c.signRequest(req, "X-Signature", "hmac-sha256", body.Bytes()); err != nil {`))
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("SignatureKeyProvider goa.SignatureKeyProvider"))
+			Ω(content).Should(ContainSubstring("sig, err := goa.Sign(algorithm, key, body)"))
+		})
+	})
+
+	Context("with a webhook", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+				Consumes:    design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"order": {
+						Name: "order",
+						Webhooks: map[string]*design.ActionDefinition{
+							"order.shipped": {
+								Name:    "order.shipped",
+								Webhook: true,
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "POST",
+										Path: "/shipped",
+									},
+								},
+								Payload: &design.UserTypeDefinition{
+									AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+									TypeName:            "ShipmentEvent",
+								},
+								Signature: &design.SignatureDefinition{
+									Header:    "X-Signature",
+									Algorithm: "hmac-sha256",
+								},
+							},
+						},
+					},
+				},
+			}
+			orderRes := design.Design.Resources["order"]
+			shipped := orderRes.Webhooks["order.shipped"]
+			shipped.Parent = orderRes
+			shipped.Routes[0].Parent = shipped
+		})
+
+		It("generates a typed sender posting the signed payload to the given URL", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "order.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("func (c *Client) SendOrderShippedOrderWebhook(ctx context.Context, webhookURL string, payload ShipmentEvent) (*http.Response, error) {"))
+			Ω(content).Should(ContainSubstring(`u.Path = path.Join(u.Path, "/shipped")`))
+			Ω(content).Should(ContainSubstring(`http.NewRequest("POST", u.String(), &body)`))
+			Ω(content).Should(ContainSubstring(`if err := c.signRequest(req, "X-Signature", "hmac-sha256", body.Bytes()); err != nil {`))
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("SignatureKeyProvider goa.SignatureKeyProvider"))
+		})
+	})
+
+	Context("with servers", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+				Servers: []*design.ServerDefinition{
+					{URL: "https://api.example.com", Name: "production"},
+					{URL: "https://eu.example.com"},
+					{URL: "http://staging.example.com:8080", Name: "staging"},
+				},
+			}
+		})
+
+		It("defaults to the first server and generates the server selection option", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("func New(c goaclient.Doer, opts ...Option) *Client {"))
+			Ω(content).Should(ContainSubstring(`client.Scheme = "https"`))
+			Ω(content).Should(ContainSubstring(`client.Host = "api.example.com"`))
+			Ω(content).Should(ContainSubstring(`var ServerNames = []string{"production", "staging"}`))
+			Ω(content).Should(ContainSubstring(`"staging":    "http://staging.example.com:8080",`))
+			Ω(content).Should(ContainSubstring("func WithServer(name string) Option {"))
+			Ω(content).Should(ContainSubstring("func (c *Client) UseServer(name string) error {"))
+			Ω(content).ShouldNot(ContainSubstring("eu.example.com"))
+		})
+
+		Context("and a default server", func() {
+			BeforeEach(func() {
+				design.Design.Servers[2].Default = true
+			})
+
+			It("defaults to the default server", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(content).Should(ContainSubstring(`client.Scheme = "http"`))
+				Ω(content).Should(ContainSubstring(`client.Host = "staging.example.com:8080"`))
+			})
+		})
+	})
+
+	Context("with an action streaming results", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			progress := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"done": &design.AttributeDefinition{Type: design.Integer}},
+				},
+				TypeName: "Progress",
+			}
+			summary := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"imported": &design.AttributeDefinition{Type: design.Integer}},
+				},
+				TypeName: "Summary",
+			}
+			res := &design.ResourceDefinition{Name: "task"}
+			route := &design.RouteDefinition{Verb: "GET", Path: "/import"}
+			action := &design.ActionDefinition{
+				Name:            "import",
+				Parent:          res,
+				Routes:          []*design.RouteDefinition{route},
+				Schemes:         []string{"ws"},
+				StreamingResult: progress,
+				Result:          summary,
+			}
+			route.Parent = action
+			res.Actions = map[string]*design.ActionDefinition{"import": action}
+			design.Design = &design.APIDefinition{
+				Name:      "testapi",
+				Consumes:  design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{"task": res},
+				Types:     map[string]*design.UserTypeDefinition{"Progress": progress, "Summary": summary},
+			}
+		})
+
+		It("generates the stream reading the results and the final result", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "task.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("func NewImportTaskStream(ws *websocket.Conn) *ImportTaskStream {"))
+			Ω(content).Should(ContainSubstring("func (s *ImportTaskStream) Recv() (*Progress, error) {"))
+			Ω(content).Should(ContainSubstring("func (s *ImportTaskStream) Summary() (*Summary, error) {"))
+		})
+
+		It("generates the CLI command printing the streamed results", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("stream := client.NewImportTaskStream(ws)"))
+			Ω(content).Should(ContainSubstring("result := func() (interface{}, error) { return stream.Summary() }"))
+			Ω(content).Should(ContainSubstring("if err := goaclient.PrintStream(os.Stdout, recv, result, stream.Close); err != nil {"))
+			Ω(content).Should(ContainSubstring("goaclient.HandleStreamError(err)"))
+			Ω(content).ShouldNot(ContainSubstring("stream-file"))
+		})
+
+		Context("with no streaming result", func() {
+			BeforeEach(func() {
+				action := design.Design.Resources["task"].Actions["import"]
+				action.StreamingResult = nil
+				action.Result = nil
+			})
+
+			It("generates the CLI command streaming messages both ways", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(content).Should(ContainSubstring(`cc.Flags().StringVar(&cmd.StreamFile, "stream-file", ""`))
+				Ω(content).Should(ContainSubstring("if err := goaclient.WSStream(ws, in, os.Stdout); err != nil {"))
+			})
+		})
+	})
+
+	Context("with an error media type", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				MediaTypes: map[string]*design.MediaTypeDefinition{
+					design.ErrorMedia.Identifier: design.ErrorMedia,
+				},
+			}
+		})
+
+		It("decodes the error recording the response", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "media_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("func (c *Client) DecodeErrorResponse(resp *http.Response) (*goa.ErrorResponse, error) {"))
+			Ω(content).Should(ContainSubstring("err := goaclient.DecodeResponse(c.Decoder, &decoded, resp)"))
+			Ω(content).Should(ContainSubstring(`goaclient "github.com/goadesign/goa/client"`))
+		})
+	})
+
 	Context("with a multipartform action with a user type payload", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -33,6 +33,7 @@ type Generator struct {
 	AppPkg    string                // Name of generated "app" package
 	Force     bool                  // Whether to override existing files
 	Regen     bool                  // Whether to regenerate scaffolding in place, retaining controller impls
+	Update    bool                  // Whether to add missing actions to existing controllers
 	Pkg       string                // Name of the generated package
 	Resource  string                // Name of the generated file
 	genfiles  []string              // Generated files
@@ -42,7 +43,7 @@ type Generator struct {
 func Generate() (files []string, err error) {
 	var (
 		outDir, designPkg, appPkg, ver, res, pkg string
-		force, regen                             bool
+		force, regen, update                     bool
 	)
 
 	set := flag.NewFlagSet("controller", flag.PanicOnError)
@@ -54,6 +55,7 @@ func Generate() (files []string, err error) {
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&force, "force", false, "")
 	set.BoolVar(&regen, "regen", false, "")
+	set.BoolVar(&update, "update", false, "")
 	set.Bool("notest", false, "")
 	set.Parse(os.Args[1:])
 
@@ -61,7 +63,7 @@ func Generate() (files []string, err error) {
 		return nil, err
 	}
 
-	g := &Generator{OutDir: outDir, DesignPkg: designPkg, AppPkg: appPkg, Force: force, Regen: regen, API: design.Design, Pkg: pkg, Resource: res}
+	g := &Generator{OutDir: outDir, DesignPkg: designPkg, AppPkg: appPkg, Force: force, Regen: regen, Update: update, API: design.Design, Pkg: pkg, Resource: res}
 
 	return g.Generate()
 }
@@ -88,22 +90,28 @@ func (g *Generator) Generate() (_ []string, err error) {
 	pkgName := elems[len(elems)-1]
 	codegen.Reserved[pkgName] = true
 
-	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
-		var (
-			filename string
-			err      error
-		)
-		if g.Resource == "" || g.Resource == r.Name {
-			filename, err = genmain.GenerateController(g.Force, g.Regen, g.AppPkg, g.OutDir, g.Pkg, r.Name, r)
-		}
-
-		if err != nil {
-			return err
-		}
-		g.genfiles = append(g.genfiles, filename)
-
+	var resources []*design.ResourceDefinition
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		resources = append(resources, r)
 		return nil
 	})
+	filenames := make([]string, len(resources))
+	err = codegen.Parallel(codegen.Jobs, len(resources), func(i int) error {
+		r := resources[i]
+		if g.Resource != "" && g.Resource != r.Name {
+			return nil
+		}
+		filename, err := genmain.GenerateController(g.Force, g.Regen, g.Update, g.AppPkg, g.OutDir, g.Pkg, r.Name, r)
+		filenames[i] = filename
+		return err
+	})
+	// Record all the files, including the ones generated after a failure, so that they
+	// get cleaned up.
+	for _, filename := range filenames {
+		if err == nil || filename != "" {
+			g.genfiles = append(g.genfiles, filename)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
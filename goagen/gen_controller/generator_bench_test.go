@@ -0,0 +1,61 @@
+package gencontroller_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_controller"
+)
+
+// largeDesign returns an API definition with n resources, each with a handful of actions. It
+// also sets design.Design as the generators look up the API level settings there.
+func largeDesign(n int) *design.APIDefinition {
+	api := &design.APIDefinition{
+		Name:      "large",
+		Resources: make(map[string]*design.ResourceDefinition, n),
+	}
+	for i := 0; i < n; i++ {
+		res := &design.ResourceDefinition{
+			Name:    fmt.Sprintf("resource%d", i),
+			Actions: make(map[string]*design.ActionDefinition),
+		}
+		for _, name := range []string{"list", "show", "create", "update", "delete"} {
+			act := &design.ActionDefinition{Name: name, Parent: res}
+			act.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "/" + name, Parent: act}}
+			res.Actions[name] = act
+		}
+		api.Resources[res.Name] = res
+	}
+	design.Design = api
+	return api
+}
+
+func benchmarkGenerate(b *testing.B, jobs int) {
+	workspace, err := codegen.NewWorkspace("bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer workspace.Delete()
+	api := largeDesign(50)
+	prev := codegen.Jobs
+	codegen.Jobs = jobs
+	defer func() { codegen.Jobs = prev }()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		outDir, err := ioutil.TempDir(workspace.Path, "")
+		if err != nil {
+			b.Fatal(err)
+		}
+		g := gencontroller.NewGenerator(gencontroller.API(api), gencontroller.OutDir(outDir), gencontroller.Pkg("controllers"))
+		if _, err := g.Generate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenerateSequential(b *testing.B) { benchmarkGenerate(b, 1) }
+func BenchmarkGenerateParallel(b *testing.B)   { benchmarkGenerate(b, 8) }
@@ -136,6 +136,51 @@ var _ = Describe("Generate", func() {
 	})
 })
 
+var _ = Describe("Generate concurrently", func() {
+	var workspace *codegen.Workspace
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		codegen.Jobs = 1
+		workspace.Delete()
+	})
+
+	// generate runs the generator with the given number of jobs and returns the generated files
+	// content indexed by path relative to the output directory. The output directory is the
+	// same for all runs as the generated code imports packages relative to it.
+	generate := func(jobs int) ([]string, map[string]string) {
+		outDir := filepath.Join(workspace.Path, "controllers")
+		Ω(os.RemoveAll(outDir)).Should(Succeed())
+		Ω(os.MkdirAll(outDir, 0755)).Should(Succeed())
+		codegen.Jobs = jobs
+		g := gencontroller.NewGenerator(gencontroller.API(largeDesign(50)), gencontroller.OutDir(outDir), gencontroller.Pkg("controllers"))
+		files, err := g.Generate()
+		Ω(err).ShouldNot(HaveOccurred())
+		rels := make([]string, len(files))
+		content := make(map[string]string, len(files))
+		for i, f := range files {
+			rels[i], err = filepath.Rel(outDir, f)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(f)
+			Ω(err).ShouldNot(HaveOccurred())
+			content[rels[i]] = string(b)
+		}
+		return rels, content
+	}
+
+	It("produces the same files as sequential generation", func() {
+		seqFiles, seqContent := generate(1)
+		parFiles, parContent := generate(8)
+		Ω(parFiles).Should(Equal(seqFiles))
+		Ω(parContent).Should(Equal(seqContent))
+	})
+})
+
 var _ = Describe("NewGenerator", func() {
 	var generator *gencontroller.Generator
 
@@ -146,6 +191,7 @@ var _ = Describe("NewGenerator", func() {
 		appPkg    string
 		force     bool
 		regen     bool
+		update    bool
 		pkg       string
 		resource  string
 		noExample bool
@@ -159,6 +205,7 @@ var _ = Describe("NewGenerator", func() {
 		pkg:       "controller",
 		resource:  "controller",
 		force:     false,
+		update:    true,
 	}
 
 	Context("with options all options set", func() {
@@ -173,6 +220,7 @@ var _ = Describe("NewGenerator", func() {
 				gencontroller.Resource(args.resource),
 				gencontroller.Force(args.force),
 				gencontroller.Regen(args.regen),
+				gencontroller.Update(args.update),
 			)
 		})
 
@@ -186,6 +234,7 @@ var _ = Describe("NewGenerator", func() {
 			Ω(generator.Resource).Should(Equal(args.resource))
 			Ω(generator.Force).Should(Equal(args.force))
 			Ω(generator.Regen).Should(Equal(args.regen))
+			Ω(generator.Update).Should(Equal(args.update))
 		})
 
 	})
@@ -60,3 +60,10 @@ func Resource(res string) Option {
 		g.Resource = res
 	}
 }
+
+//Update Whether to add stubs for missing actions to existing controllers, reporting removed or changed ones
+func Update(update bool) Option {
+	return func(g *Generator) {
+		g.Update = update
+	}
+}
@@ -0,0 +1,276 @@
+package gendeploy
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// Deployment describes the deployment of the service that serves a resource.
+	Deployment struct {
+		// Name is the Kubernetes name of the Service and Deployment.
+		Name string
+		// Resource is the resource served by the deployment.
+		Resource *design.ResourceDefinition
+		// Image is the container image, see the "deploy:image" metadata.
+		Image string
+		// Replicas is the number of replicas, see the "deploy:replicas" metadata.
+		Replicas int
+		// Ports lists the ports exposed by the Service sorted by port number.
+		Ports []*Port
+		// ContainerPort is the port the service listens on.
+		ContainerPort int
+		// TLS is true if the service listens for HTTPS requests.
+		TLS bool
+		// HealthPath is the path used by the readiness and liveness probes, empty if the
+		// resource and the API define no health check.
+		HealthPath string
+	}
+
+	// Port describes a port exposed by a Kubernetes Service.
+	Port struct {
+		// Name is the port name.
+		Name string
+		// Port is the port number.
+		Port int
+		// TLS is true if the servers using the port use a secure scheme.
+		TLS bool
+	}
+)
+
+// invalidNameChars matches the characters that may not appear in Kubernetes names.
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Plan returns the deployments of the API resources that set the "deploy:image" metadata sorted
+// by resource name. Plan returns an error if the metadata values are invalid or if the port
+// allocations conflict.
+func Plan(api *design.APIDefinition) ([]*Deployment, error) {
+	containerPort := 8080
+	if _, port, err := net.SplitHostPort(api.Host); err == nil {
+		if p, err := strconv.Atoi(port); err == nil {
+			containerPort = p
+		}
+	}
+	tls := false
+	for _, scheme := range api.Schemes {
+		if scheme == "https" {
+			tls = true
+		}
+	}
+
+	var deployments []*Deployment
+	names := make(map[string]*design.ResourceDefinition)
+	endpoints := make(map[string]*design.ResourceDefinition)
+	err := api.IterateResources(func(r *design.ResourceDefinition) error {
+		image, ok := r.Metadata.Last("deploy:image")
+		if !ok {
+			return nil
+		}
+		if image == "" {
+			return fmt.Errorf("resource %q: deploy:image must not be empty", r.Name)
+		}
+		replicas, err := replicas(api, r)
+		if err != nil {
+			return err
+		}
+		name := strings.Trim(invalidNameChars.ReplaceAllString(codegen.KebabCase(r.Name), "-"), "-")
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("resources %q and %q both map to the Kubernetes name %q", other.Name, r.Name, name)
+		}
+		names[name] = r
+		ports, hosts, err := servicePorts(api, r)
+		if err != nil {
+			return err
+		}
+		for _, h := range hosts {
+			if other, ok := endpoints[h]; ok {
+				return fmt.Errorf("resources %q and %q are both exposed on %s by different deployments", other.Name, r.Name, h)
+			}
+			endpoints[h] = r
+		}
+		d := &Deployment{
+			Name:          name,
+			Resource:      r,
+			Image:         image,
+			Replicas:      replicas,
+			Ports:         ports,
+			ContainerPort: containerPort,
+			TLS:           tls,
+		}
+		if r.HealthCheck != nil {
+			d.HealthPath = r.HealthCheck.FullPath()
+		} else if api.HealthCheck != nil {
+			d.HealthPath = api.HealthCheck.FullPath()
+		}
+		deployments = append(deployments, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// replicas returns the number of replicas set with the "deploy:replicas" metadata of the resource
+// or of the API, 1 if not set.
+func replicas(api *design.APIDefinition, r *design.ResourceDefinition) (int, error) {
+	val, ok := r.Metadata.Last("deploy:replicas")
+	if !ok {
+		if val, ok = api.Metadata.Last("deploy:replicas"); !ok {
+			return 1, nil
+		}
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("resource %q: invalid deploy:replicas value %q, must be a positive integer", r.Name, val)
+	}
+	return n, nil
+}
+
+// servicePorts returns the distinct ports of the URLs of the servers exposing the resource sorted
+// by number together with the host and port pairs of the URLs. The API host and first scheme are
+// used if the API defines no server.
+func servicePorts(api *design.APIDefinition, r *design.ResourceDefinition) ([]*Port, []string, error) {
+	servers := r.EffectiveServers()
+	if len(servers) == 0 && api.Host != "" {
+		scheme := "http"
+		if len(api.Schemes) > 0 {
+			scheme = api.Schemes[0]
+		}
+		servers = []*design.ServerDefinition{{URL: scheme + "://" + api.Host}}
+	}
+	if len(servers) == 0 {
+		return []*Port{{Name: "http-80", Port: 80}}, nil, nil
+	}
+
+	byNumber := make(map[int]*Port)
+	sources := make(map[int]string)
+	seen := make(map[string]bool)
+	var ports []*Port
+	var hosts []string
+	for _, s := range servers {
+		u := s.DefaultURL()
+		number := s.Port()
+		if number == 0 || s.Host() == "" {
+			return nil, nil, fmt.Errorf("resource %q: invalid server URL %q", r.Name, u)
+		}
+		secure := s.Secure()
+		host := net.JoinHostPort(s.Host(), strconv.Itoa(number))
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+		if p, ok := byNumber[number]; ok {
+			if p.TLS != secure {
+				return nil, nil, fmt.Errorf("resource %q: port %d is used by servers %q and %q with and without TLS",
+					r.Name, number, sources[number], u)
+			}
+			continue
+		}
+		scheme := strings.SplitN(u, ":", 2)[0]
+		p := &Port{Name: fmt.Sprintf("%s-%d", scheme, number), Port: number, TLS: secure}
+		byNumber[number] = p
+		sources[number] = u
+		ports = append(ports, p)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Port < ports[j].Port })
+	return ports, hosts, nil
+}
+
+// Dockerfile returns the content of the Dockerfile that builds the service.
+func (d *Deployment) Dockerfile() (string, error) {
+	return render(dockerfileTmpl, d)
+}
+
+// Manifest returns the content of the Kubernetes manifest that describes the Service and
+// Deployment.
+func (d *Deployment) Manifest() (string, error) {
+	return render(manifestTmpl, d)
+}
+
+// render executes the given template with the deployment data.
+func render(tmpl *template.Template, d *Deployment) (string, error) {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, d); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+var (
+	dockerfileTmpl = template.Must(template.New("dockerfile").Parse(dockerfileT))
+	manifestTmpl   = template.Must(template.New("manifest").Parse(manifestT))
+)
+
+const dockerfileT = `# Code generated by goagen, DO NOT EDIT.
+FROM golang:1 AS build
+WORKDIR /src
+COPY . .
+RUN CGO_ENABLED=0 go build -o /bin/service .
+
+FROM scratch
+COPY --from=build /bin/service /service
+EXPOSE {{ .ContainerPort }}
+ENTRYPOINT ["/service"]
+`
+
+const manifestT = `# Code generated by goagen, DO NOT EDIT.
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Name }}
+  labels:
+    app: {{ .Name }}
+spec:
+  selector:
+    app: {{ .Name }}
+  ports:
+{{- range .Ports }}
+  - name: {{ .Name }}
+    port: {{ .Port }}
+    targetPort: {{ $.ContainerPort }}
+{{- end }}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Name }}
+  labels:
+    app: {{ .Name }}
+spec:
+  replicas: {{ .Replicas }}
+  selector:
+    matchLabels:
+      app: {{ .Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .Name }}
+    spec:
+      containers:
+      - name: {{ .Name }}
+        image: {{ printf "%q" .Image }}
+        ports:
+        - containerPort: {{ .ContainerPort }}
+{{- if .HealthPath }}
+        readinessProbe:
+          httpGet:
+            path: {{ printf "%q" .HealthPath }}
+            port: {{ .ContainerPort }}
+            scheme: {{ if .TLS }}HTTPS{{ else }}HTTP{{ end }}
+        livenessProbe:
+          httpGet:
+            path: {{ printf "%q" .HealthPath }}
+            port: {{ .ContainerPort }}
+            scheme: {{ if .TLS }}HTTPS{{ else }}HTTP{{ end }}
+{{- end }}
+`
@@ -0,0 +1,110 @@
+package gendeploy_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_deploy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plan", func() {
+	var deployments []*gendeploy.Deployment
+	var planErr error
+
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	JustBeforeEach(func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		deployments, planErr = gendeploy.Plan(Design)
+	})
+
+	Context("with resources exposed by distinct servers", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Server("https://bottles.example.com", func() { Name("bottles") })
+				Server("http://accounts.example.com:9090", func() { Name("accounts") })
+			})
+			Resource("bottle", func() {
+				Servers("bottles")
+				HealthCheck("/ping")
+				Metadata("deploy:image", "bottle:1")
+				Metadata("deploy:replicas", "3")
+			})
+			Resource("account", func() {
+				Servers("accounts")
+				Metadata("deploy:image", "account:1")
+			})
+		})
+
+		It("allocates the ports of each deployment", func() {
+			Ω(planErr).ShouldNot(HaveOccurred())
+			Ω(deployments).Should(HaveLen(2))
+			account, bottle := deployments[0], deployments[1]
+			Ω(account.Name).Should(Equal("account"))
+			Ω(account.Replicas).Should(Equal(1))
+			Ω(account.ContainerPort).Should(Equal(8080))
+			Ω(account.HealthPath).Should(BeEmpty())
+			Ω(account.Ports).Should(Equal([]*gendeploy.Port{{Name: "http-9090", Port: 9090}}))
+			Ω(bottle.Name).Should(Equal("bottle"))
+			Ω(bottle.Replicas).Should(Equal(3))
+			Ω(bottle.HealthPath).Should(Equal("/ping"))
+			Ω(bottle.Ports).Should(Equal([]*gendeploy.Port{{Name: "https-443", Port: 443, TLS: true}}))
+		})
+	})
+
+	Context("with a port used with and without TLS", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Server("https://api.example.com:8443")
+				Server("http://internal.example.com:8443")
+			})
+			Resource("bottle", func() {
+				Metadata("deploy:image", "bottle:1")
+			})
+		})
+
+		It("returns an error", func() {
+			Ω(planErr).Should(HaveOccurred())
+			Ω(planErr.Error()).Should(ContainSubstring("port 8443 is used by servers"))
+		})
+	})
+
+	Context("with two deployments exposed by the same server", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Server("https://api.example.com")
+			})
+			Resource("bottle", func() {
+				Metadata("deploy:image", "bottle:1")
+			})
+			Resource("account", func() {
+				Metadata("deploy:image", "account:1")
+			})
+		})
+
+		It("returns an error", func() {
+			Ω(planErr).Should(HaveOccurred())
+			Ω(planErr.Error()).Should(ContainSubstring(`resources "account" and "bottle" are both exposed on api.example.com:443`))
+		})
+	})
+
+	Context("with an invalid number of replicas", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Metadata("deploy:replicas", "0")
+			})
+			Resource("bottle", func() {
+				Metadata("deploy:image", "bottle:1")
+			})
+		})
+
+		It("returns an error", func() {
+			Ω(planErr).Should(HaveOccurred())
+			Ω(planErr.Error()).Should(ContainSubstring("invalid deploy:replicas value"))
+		})
+	})
+})
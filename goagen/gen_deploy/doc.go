@@ -0,0 +1,27 @@
+/*
+Package gendeploy provides a generator that produces deployment descriptors for the services built
+from the design. The generator is opt-in: it only considers the resources that set the
+"deploy:image" metadata to the name of the container image that serves them, for example:
+
+	Resource("bottle", func() {
+		Metadata("deploy:image", "registry.example.com/cellar:1.0")
+		Metadata("deploy:replicas", "3")
+	})
+
+For each such resource the generator produces the deploy/<name>/Dockerfile file that builds the
+service and the deploy/<name>/kubernetes.yaml file that contains a Kubernetes Service and
+Deployment. The "deploy:replicas" metadata sets the number of replicas, it may be set on the API
+to apply to all resources and defaults to 1.
+
+The Service exposes one port per distinct port of the URLs of the servers that expose the resource
+(see Server), the ports default to 80 and 443 depending on the URL scheme. The API host and
+schemes are used if the API defines no server. All the ports target the port the generated main
+listens on, that is the port of the API host or 8080. The Deployment uses the path of the resource
+health check, or of the API health check, for its readiness and liveness probes.
+
+The generator fails if a port is used by servers with and without TLS, if two resources are
+exposed on the same server port or if two resources map to the same Kubernetes name. The
+generated files only depend on the design so that running the generator again produces the same
+files.
+*/
+package gendeploy
@@ -0,0 +1,13 @@
+package gendeploy_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenDeploy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenDeploy Suite")
+}
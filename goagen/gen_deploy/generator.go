@@ -0,0 +1,108 @@
+package gendeploy
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// NewGenerator returns an initialized instance of a deployment descriptors generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the deployment descriptors generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("deploy", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Dockerfile and Kubernetes manifest of each deployment.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	deployments, err := Plan(g.API)
+	if err != nil {
+		return
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "deploy")
+	codegen.CleanDir(g.OutDir)
+	if len(deployments) == 0 {
+		return nil, nil
+	}
+	os.MkdirAll(g.OutDir, 0755)
+	g.genfiles = append(g.genfiles, g.OutDir)
+	for _, d := range deployments {
+		dir := filepath.Join(g.OutDir, d.Name)
+		os.MkdirAll(dir, 0755)
+		g.genfiles = append(g.genfiles, dir)
+		var dockerfile, manifest string
+		if dockerfile, err = d.Dockerfile(); err != nil {
+			return
+		}
+		if manifest, err = d.Manifest(); err != nil {
+			return
+		}
+		dockerPath := filepath.Join(dir, "Dockerfile")
+		if err = codegen.WriteFile(dockerPath, []byte(dockerfile)); err != nil {
+			return
+		}
+		g.genfiles = append(g.genfiles, dockerPath)
+		manifestPath := filepath.Join(dir, "kubernetes.yaml")
+		if err = codegen.WriteFile(manifestPath, []byte(manifest)); err != nil {
+			return
+		}
+		g.genfiles = append(g.genfiles, manifestPath)
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invocation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
@@ -0,0 +1,98 @@
+package gendeploy_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_deploy"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("deploytest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gendeploy.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with no resource setting deploy:image", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {})
+			apidsl.Resource("bottle", func() {})
+			dslengine.Run()
+		})
+
+		It("does not generate any file", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(BeEmpty())
+			_, err := os.Stat(filepath.Join(testPkg.Abs(), "deploy"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+	})
+
+	Context("with a deployed resource", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Host("localhost:8081")
+				apidsl.Scheme("https")
+				apidsl.Server("https://api.example.com")
+				apidsl.Server("http://internal.example.com:8080")
+				apidsl.HealthCheck("/health")
+				apidsl.Metadata("deploy:replicas", "2")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Metadata("deploy:image", "registry.example.com/cellar:1.0")
+			})
+			apidsl.Resource("account", func() {})
+			dslengine.Run()
+		})
+
+		It("generates the Dockerfile and the Kubernetes manifest", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(4))
+			for _, name := range []string{"Dockerfile", "kubernetes.yaml"} {
+				golden, err := ioutil.ReadFile(filepath.Join("testdata", name))
+				Ω(err).ShouldNot(HaveOccurred())
+				content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "deploy", "bottle", name))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(Equal(string(golden)))
+			}
+		})
+
+		It("produces the same files when run again", func() {
+			Ω(genErr).Should(BeNil())
+			first, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "deploy", "bottle", "kubernetes.yaml"))
+			Ω(err).ShouldNot(HaveOccurred())
+			again, err := gendeploy.Generate()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(again).Should(Equal(files))
+			second, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "deploy", "bottle", "kubernetes.yaml"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(second).Should(Equal(first))
+		})
+	})
+})
@@ -0,0 +1,487 @@
+package gendescribe
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// FormatVersion is the version of the JSON format produced by Describe.
+const FormatVersion = "1"
+
+type (
+	// Description is the JSON representation of a design.
+	Description struct {
+		// FormatVersion is the version of the format, see FormatVersion.
+		FormatVersion string `json:"format_version"`
+		// API describes the API.
+		API *APIDescription `json:"api"`
+		// Resources lists the API resources sorted by name.
+		Resources []*ResourceDescription `json:"resources,omitempty"`
+		// Types lists the user types and media types used by the API sorted by name.
+		Types []*TypeDescription `json:"types,omitempty"`
+		// SecuritySchemes lists the security schemes defined by the API.
+		SecuritySchemes []*SecuritySchemeDescription `json:"security_schemes,omitempty"`
+	}
+
+	// APIDescription describes the API.
+	APIDescription struct {
+		Name        string                       `json:"name"`
+		Title       string                       `json:"title,omitempty"`
+		Description string                       `json:"description,omitempty"`
+		Version     string                       `json:"version,omitempty"`
+		Host        string                       `json:"host,omitempty"`
+		Schemes     []string                     `json:"schemes,omitempty"`
+		BasePath    string                       `json:"base_path,omitempty"`
+		Servers     []*ServerDescription         `json:"servers,omitempty"`
+		Params      *AttributeDescription        `json:"params,omitempty"`
+		Consumes    []string                     `json:"consumes,omitempty"`
+		Produces    []string                     `json:"produces,omitempty"`
+		Security    *SecurityDescription         `json:"security,omitempty"`
+		Metadata    dslengine.MetadataDefinition `json:"metadata,omitempty"`
+	}
+
+	// ServerDescription describes a server hosting the API.
+	ServerDescription struct {
+		URL         string `json:"url"`
+		Label       string `json:"label,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// ResourceDescription describes a resource.
+	ResourceDescription struct {
+		Name            string                       `json:"name"`
+		Description     string                       `json:"description,omitempty"`
+		Parent          string                       `json:"parent,omitempty"`
+		BasePath        string                       `json:"base_path,omitempty"`
+		FullPath        string                       `json:"full_path"`
+		Schemes         []string                     `json:"schemes,omitempty"`
+		MediaType       string                       `json:"media_type,omitempty"`
+		DefaultView     string                       `json:"default_view,omitempty"`
+		CanonicalAction string                       `json:"canonical_action,omitempty"`
+		Params          *AttributeDescription        `json:"params,omitempty"`
+		Headers         *AttributeDescription        `json:"headers,omitempty"`
+		Actions         []*ActionDescription         `json:"actions,omitempty"`
+		FileServers     []*FileServerDescription     `json:"file_servers,omitempty"`
+		Responses       []*ResponseDescription       `json:"responses,omitempty"`
+		Security        *SecurityDescription         `json:"security,omitempty"`
+		Metadata        dslengine.MetadataDefinition `json:"metadata,omitempty"`
+	}
+
+	// ActionDescription describes a resource action.
+	ActionDescription struct {
+		Name            string                       `json:"name"`
+		Description     string                       `json:"description,omitempty"`
+		Schemes         []string                     `json:"schemes,omitempty"`
+		Routes          []*RouteDescription          `json:"routes"`
+		Params          *AttributeDescription        `json:"params,omitempty"`
+		QueryParams     *AttributeDescription        `json:"query_params,omitempty"`
+		Headers         *AttributeDescription        `json:"headers,omitempty"`
+		Payload         *AttributeDescription        `json:"payload,omitempty"`
+		PayloadOptional bool                         `json:"payload_optional,omitempty"`
+		Responses       []*ResponseDescription       `json:"responses,omitempty"`
+		Security        *SecurityDescription         `json:"security,omitempty"`
+		Metadata        dslengine.MetadataDefinition `json:"metadata,omitempty"`
+	}
+
+	// RouteDescription describes an action route.
+	RouteDescription struct {
+		Verb     string `json:"verb"`
+		Path     string `json:"path"`
+		FullPath string `json:"full_path"`
+	}
+
+	// FileServerDescription describes a file server.
+	FileServerDescription struct {
+		Description string `json:"description,omitempty"`
+		FilePath    string `json:"file_path"`
+		RequestPath string `json:"request_path"`
+	}
+
+	// ResponseDescription describes a response.
+	ResponseDescription struct {
+		Name        string                `json:"name"`
+		Status      int                   `json:"status"`
+		Description string                `json:"description,omitempty"`
+		MediaType   string                `json:"media_type,omitempty"`
+		View        string                `json:"view,omitempty"`
+		Headers     *AttributeDescription `json:"headers,omitempty"`
+	}
+
+	// TypeDescription describes a user type or a media type.
+	TypeDescription struct {
+		// Name is the type name as used in AttributeDescription Ref fields.
+		Name string `json:"name"`
+		// Identifier is the media type identifier, empty for user types.
+		Identifier string `json:"identifier,omitempty"`
+		// Attribute describes the type.
+		Attribute *AttributeDescription `json:"attribute"`
+		// Views lists the names of the attributes rendered by each view of a media type.
+		Views map[string][]string `json:"views,omitempty"`
+	}
+
+	// AttributeDescription describes an attribute.
+	AttributeDescription struct {
+		// Type is one of "boolean", "integer", "number", "string", "datetime", "uuid",
+		// "file", "any", "array", "hash", "object" or "ref".
+		Type string `json:"type"`
+		// Ref is the name of the referenced type when Type is "ref".
+		Ref string `json:"ref,omitempty"`
+		// Description is the attribute description.
+		Description string `json:"description,omitempty"`
+		// Elem describes the array elements or the hash values.
+		Elem *AttributeDescription `json:"elem,omitempty"`
+		// Key describes the hash keys.
+		Key *AttributeDescription `json:"key,omitempty"`
+		// Attributes describes the object attributes indexed by name.
+		Attributes map[string]*AttributeDescription `json:"attributes,omitempty"`
+		// Validation lists the attribute validations.
+		Validation *ValidationDescription `json:"validation,omitempty"`
+		// Default is the attribute default value.
+		Default interface{} `json:"default,omitempty"`
+		// Example is the attribute example value.
+		Example interface{} `json:"example,omitempty"`
+		// Metadata is the attribute metadata.
+		Metadata dslengine.MetadataDefinition `json:"metadata,omitempty"`
+	}
+
+	// ValidationDescription describes the validations of an attribute.
+	ValidationDescription struct {
+		Values    []interface{} `json:"values,omitempty"`
+		Format    string        `json:"format,omitempty"`
+		Pattern   string        `json:"pattern,omitempty"`
+		Minimum   *float64      `json:"minimum,omitempty"`
+		Maximum   *float64      `json:"maximum,omitempty"`
+		MinLength *int          `json:"min_length,omitempty"`
+		MaxLength *int          `json:"max_length,omitempty"`
+		Required  []string      `json:"required,omitempty"`
+	}
+
+	// SecuritySchemeDescription describes a security scheme.
+	SecuritySchemeDescription struct {
+		Name             string                       `json:"name"`
+		Type             string                       `json:"type"`
+		Description      string                       `json:"description,omitempty"`
+		In               string                       `json:"in,omitempty"`
+		ParamName        string                       `json:"param_name,omitempty"`
+		Scopes           map[string]string            `json:"scopes,omitempty"`
+		Flow             string                       `json:"flow,omitempty"`
+		TokenURL         string                       `json:"token_url,omitempty"`
+		AuthorizationURL string                       `json:"authorization_url,omitempty"`
+		Metadata         dslengine.MetadataDefinition `json:"metadata,omitempty"`
+	}
+
+	// SecurityDescription describes the security requirements of an API, resource or action.
+	SecurityDescription struct {
+		Scheme string   `json:"scheme"`
+		Scopes []string `json:"scopes,omitempty"`
+	}
+
+	// describer builds a description keeping track of the types it encountered.
+	describer struct {
+		types map[string]*TypeDescription
+	}
+)
+
+// Describe returns the description of the given API.
+func Describe(api *design.APIDefinition) *Description {
+	d := &describer{types: make(map[string]*TypeDescription)}
+	desc := &Description{FormatVersion: FormatVersion, API: d.api(api)}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		desc.Resources = append(desc.Resources, d.resource(r))
+		return nil
+	})
+	api.IterateUserTypes(func(u *design.UserTypeDefinition) error {
+		d.userType(u)
+		return nil
+	})
+	api.IterateMediaTypes(func(m *design.MediaTypeDefinition) error {
+		d.userType(m.UserTypeDefinition)
+		d.mediaType(m)
+		return nil
+	})
+	names := make([]string, 0, len(d.types))
+	for n := range d.types {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		desc.Types = append(desc.Types, d.types[n])
+	}
+	for _, s := range api.SecuritySchemes {
+		desc.SecuritySchemes = append(desc.SecuritySchemes, &SecuritySchemeDescription{
+			Name:             s.SchemeName,
+			Type:             s.Type,
+			Description:      s.Description,
+			In:               s.In,
+			ParamName:        s.Name,
+			Scopes:           s.Scopes,
+			Flow:             s.Flow,
+			TokenURL:         s.TokenURL,
+			AuthorizationURL: s.AuthorizationURL,
+			Metadata:         s.Metadata,
+		})
+	}
+	return desc
+}
+
+// JSON returns the indented JSON representation of the description.
+func (d *Description) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func (d *describer) api(api *design.APIDefinition) *APIDescription {
+	var servers []*ServerDescription
+	for _, s := range api.Servers {
+		servers = append(servers, &ServerDescription{
+			URL:         s.URL,
+			Label:       s.Label,
+			Description: s.Description,
+		})
+	}
+	return &APIDescription{
+		Name:        api.Name,
+		Title:       api.Title,
+		Description: api.Description,
+		Version:     api.Version,
+		Host:        api.Host,
+		Schemes:     api.Schemes,
+		BasePath:    api.BasePath,
+		Servers:     servers,
+		Params:      d.attribute(api.Params),
+		Consumes:    mimeTypes(api.Consumes),
+		Produces:    mimeTypes(api.Produces),
+		Security:    security(api.Security),
+		Metadata:    api.Metadata,
+	}
+}
+
+func (d *describer) resource(r *design.ResourceDefinition) *ResourceDescription {
+	res := &ResourceDescription{
+		Name:            r.Name,
+		Description:     r.Description,
+		Parent:          r.ParentName,
+		BasePath:        r.BasePath,
+		FullPath:        r.FullPath(),
+		Schemes:         r.Schemes,
+		MediaType:       r.MediaType,
+		DefaultView:     r.DefaultViewName,
+		CanonicalAction: r.CanonicalActionName,
+		Params:          d.attribute(r.Params),
+		Headers:         d.attribute(r.Headers),
+		Responses:       d.responses(r.Responses),
+		Security:        security(r.Security),
+		Metadata:        r.Metadata,
+	}
+	r.IterateActions(func(a *design.ActionDefinition) error {
+		res.Actions = append(res.Actions, d.action(a))
+		return nil
+	})
+	r.IterateFileServers(func(fs *design.FileServerDefinition) error {
+		res.FileServers = append(res.FileServers, &FileServerDescription{
+			Description: fs.Description,
+			FilePath:    fs.FilePath,
+			RequestPath: fs.RequestPath,
+		})
+		return nil
+	})
+	return res
+}
+
+func (d *describer) action(a *design.ActionDefinition) *ActionDescription {
+	res := &ActionDescription{
+		Name:            a.Name,
+		Description:     a.Description,
+		Schemes:         a.Schemes,
+		Routes:          make([]*RouteDescription, len(a.Routes)),
+		Params:          d.attribute(a.Params),
+		QueryParams:     d.attribute(a.QueryParams),
+		Headers:         d.attribute(a.Headers),
+		PayloadOptional: a.PayloadOptional,
+		Responses:       d.responses(a.Responses),
+		Security:        security(a.Security),
+		Metadata:        a.Metadata,
+	}
+	for i, r := range a.Routes {
+		res.Routes[i] = &RouteDescription{Verb: r.Verb, Path: r.Path, FullPath: r.FullPath()}
+	}
+	if a.Payload != nil {
+		res.Payload = d.attribute(&design.AttributeDefinition{Type: a.Payload})
+	}
+	return res
+}
+
+func (d *describer) responses(responses map[string]*design.ResponseDefinition) []*ResponseDescription {
+	names := make([]string, 0, len(responses))
+	for n := range responses {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var res []*ResponseDescription
+	for _, n := range names {
+		r := responses[n]
+		res = append(res, &ResponseDescription{
+			Name:        r.Name,
+			Status:      r.Status,
+			Description: r.Description,
+			MediaType:   r.MediaType,
+			View:        r.ViewName,
+			Headers:     d.attribute(r.Headers),
+		})
+	}
+	return res
+}
+
+// userType records the description of the given user type if not already done.
+func (d *describer) userType(u *design.UserTypeDefinition) {
+	if _, ok := d.types[u.TypeName]; ok {
+		return
+	}
+	td := &TypeDescription{Name: u.TypeName}
+	// Record the type before describing its attribute so that recursive references end.
+	d.types[u.TypeName] = td
+	td.Attribute = d.attribute(u.AttributeDefinition)
+}
+
+// mediaType records the identifier and views of the given media type.
+func (d *describer) mediaType(m *design.MediaTypeDefinition) {
+	td := d.types[m.TypeName]
+	td.Identifier = m.Identifier
+	m.IterateViews(func(v *design.ViewDefinition) error {
+		var names []string
+		if o := v.Type.ToObject(); o != nil {
+			for n := range o {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+		}
+		if td.Views == nil {
+			td.Views = make(map[string][]string)
+		}
+		td.Views[v.Name] = names
+		return nil
+	})
+}
+
+// attribute returns the description of the given attribute, user types and media types are
+// described by reference.
+func (d *describer) attribute(att *design.AttributeDefinition) *AttributeDescription {
+	if att == nil || att.Type == nil {
+		return nil
+	}
+	res := &AttributeDescription{
+		Description: att.Description,
+		Default:     jsonValue(att.DefaultValue),
+		Example:     jsonValue(att.Example),
+		Metadata:    att.Metadata,
+	}
+	if v := att.Validation; v != nil {
+		values := make([]interface{}, len(v.Values))
+		for i, val := range v.Values {
+			values[i] = jsonValue(val)
+		}
+		if len(values) == 0 {
+			values = nil
+		}
+		res.Validation = &ValidationDescription{
+			Values:    values,
+			Format:    v.Format,
+			Pattern:   v.Pattern,
+			Minimum:   v.Minimum,
+			Maximum:   v.Maximum,
+			MinLength: v.MinLength,
+			MaxLength: v.MaxLength,
+			Required:  v.Required,
+		}
+	}
+	switch t := att.Type.(type) {
+	case *design.MediaTypeDefinition:
+		d.userType(t.UserTypeDefinition)
+		d.mediaType(t)
+		res.Type = "ref"
+		res.Ref = t.TypeName
+	case *design.UserTypeDefinition:
+		d.userType(t)
+		res.Type = "ref"
+		res.Ref = t.TypeName
+	case *design.Array:
+		res.Type = "array"
+		res.Elem = d.attribute(t.ElemType)
+	case *design.Hash:
+		res.Type = "hash"
+		res.Key = d.attribute(t.KeyType)
+		res.Elem = d.attribute(t.ElemType)
+	case design.Object:
+		res.Type = "object"
+		res.Attributes = make(map[string]*AttributeDescription, len(t))
+		for n, child := range t {
+			res.Attributes[n] = d.attribute(child)
+		}
+	default:
+		res.Type = primitiveName(att.Type)
+	}
+	return res
+}
+
+// primitiveName returns the name used to describe the given primitive type.
+func primitiveName(t design.DataType) string {
+	switch t.Kind() {
+	case design.DateTimeKind:
+		return "datetime"
+	case design.DurationKind:
+		return "duration"
+	case design.BytesKind:
+		return "bytes"
+	case design.UUIDKind:
+		return "uuid"
+	case design.FileKind:
+		return "file"
+	default:
+		return t.Name()
+	}
+}
+
+// security returns the description of the given security requirements.
+func security(s *design.SecurityDefinition) *SecurityDescription {
+	if s == nil || s.Scheme == nil {
+		return nil
+	}
+	return &SecurityDescription{Scheme: s.Scheme.SchemeName, Scopes: s.Scopes}
+}
+
+// mimeTypes returns the MIME types of the given encodings.
+func mimeTypes(encs []*design.EncodingDefinition) []string {
+	var res []string
+	for _, e := range encs {
+		res = append(res, e.MIMETypes...)
+	}
+	return res
+}
+
+// jsonValue converts the given default or example value so that it can be serialized to JSON.
+// Hash values use interface{} keys which are converted to strings.
+func jsonValue(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case design.HashVal:
+		return jsonValue(actual.ToMap())
+	case design.ArrayVal:
+		return jsonValue(actual.ToSlice())
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, val := range actual {
+			res[fmt.Sprintf("%v", k)] = jsonValue(val)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(actual))
+		for i, val := range actual {
+			res[i] = jsonValue(val)
+		}
+		return res
+	default:
+		return v
+	}
+}
@@ -0,0 +1,155 @@
+package gendescribe_test
+
+import (
+	"encoding/json"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_describe"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Describe", func() {
+	var desc *gendescribe.Description
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		var Node *UserTypeDefinition
+		Node = Type("Node", func() {
+			Attribute("name", String, func() {
+				MinLength(1)
+				Metadata("struct:tag:json", "name")
+			})
+			Attribute("children", ArrayOf(Node))
+			Required("name")
+		})
+		var Tree = MediaType("application/vnd.tree+json", func() {
+			TypeName("Tree")
+			Attributes(func() {
+				Attribute("root", Node)
+				Attribute("size", Integer, func() {
+					Minimum(0)
+					Default(0)
+				})
+			})
+			View("default", func() {
+				Attribute("root")
+				Attribute("size")
+			})
+		})
+		API("test", func() {
+			Host("example.com")
+			Scheme("https")
+			BasePath("/api")
+			Server("https://staging.example.com:8443", func() {
+				Label("staging")
+			})
+			Server("https://example.com")
+			BasicAuthSecurity("basic")
+		})
+		Resource("tree", func() {
+			BasePath("/trees")
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Params(func() {
+					Param("id", Integer)
+				})
+				Security("basic")
+				Response(OK, Tree)
+				Response(NotFound)
+			})
+			Action("create", func() {
+				Routing(POST(""))
+				Payload(Node)
+				Response(Created)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		desc = gendescribe.Describe(Design)
+	})
+
+	It("describes the API", func() {
+		Ω(desc.FormatVersion).Should(Equal(gendescribe.FormatVersion))
+		Ω(desc.API.Host).Should(Equal("example.com"))
+		Ω(desc.API.Schemes).Should(Equal([]string{"https"}))
+		Ω(desc.API.Servers).Should(Equal([]*gendescribe.ServerDescription{
+			{URL: "https://staging.example.com:8443", Label: "staging"},
+			{URL: "https://example.com", Label: "example.com"},
+		}))
+		Ω(desc.SecuritySchemes).Should(HaveLen(1))
+		Ω(desc.SecuritySchemes[0].Name).Should(Equal("basic"))
+		Ω(desc.SecuritySchemes[0].Type).Should(Equal("basic"))
+	})
+
+	It("describes the resources, routes and responses", func() {
+		Ω(desc.Resources).Should(HaveLen(1))
+		r := desc.Resources[0]
+		Ω(r.Name).Should(Equal("tree"))
+		Ω(r.FullPath).Should(Equal("/api/trees"))
+		Ω(r.Actions).Should(HaveLen(2))
+		show := r.Actions[1]
+		Ω(show.Name).Should(Equal("show"))
+		Ω(show.Routes).Should(HaveLen(1))
+		Ω(*show.Routes[0]).Should(Equal(gendescribe.RouteDescription{Verb: "GET", Path: "/:id", FullPath: "/api/trees/:id"}))
+		Ω(show.Params.Attributes).Should(HaveKey("id"))
+		Ω(show.Security).Should(Equal(&gendescribe.SecurityDescription{Scheme: "basic"}))
+		Ω(show.Responses).Should(HaveLen(2))
+		Ω(show.Responses[0].Name).Should(Equal("NotFound"))
+		Ω(show.Responses[0].Status).Should(Equal(404))
+		Ω(show.Responses[1].Name).Should(Equal("OK"))
+		Ω(show.Responses[1].Status).Should(Equal(200))
+		Ω(show.Responses[1].MediaType).Should(Equal("application/vnd.tree+json"))
+	})
+
+	It("describes user types by reference", func() {
+		create := desc.Resources[0].Actions[0]
+		Ω(create.Payload.Type).Should(Equal("ref"))
+		Ω(create.Payload.Ref).Should(Equal("Node"))
+	})
+
+	It("describes recursive types", func() {
+		var node, tree *gendescribe.TypeDescription
+		for _, t := range desc.Types {
+			switch t.Name {
+			case "Node":
+				node = t
+			case "Tree":
+				tree = t
+			}
+		}
+		Ω(node).ShouldNot(BeNil())
+		children := node.Attribute.Attributes["children"]
+		Ω(children.Type).Should(Equal("array"))
+		Ω(children.Elem.Type).Should(Equal("ref"))
+		Ω(children.Elem.Ref).Should(Equal("Node"))
+		name := node.Attribute.Attributes["name"]
+		Ω(*name.Validation.MinLength).Should(Equal(1))
+		Ω(name.Metadata).Should(HaveKeyWithValue("struct:tag:json", []string{"name"}))
+		Ω(node.Attribute.Validation.Required).Should(Equal([]string{"name"}))
+
+		Ω(tree).ShouldNot(BeNil())
+		Ω(tree.Identifier).Should(Equal("application/vnd.tree+json"))
+		Ω(tree.Views).Should(HaveKeyWithValue("default", []string{"root", "size"}))
+		Ω(tree.Attribute.Attributes["root"].Ref).Should(Equal("Node"))
+		Ω(tree.Attribute.Attributes["size"].Default).Should(Equal(0))
+	})
+
+	It("round trips", func() {
+		js, err := desc.JSON()
+		Ω(err).ShouldNot(HaveOccurred())
+		var decoded gendescribe.Description
+		Ω(json.Unmarshal(js, &decoded)).ShouldNot(HaveOccurred())
+		js2, err := decoded.JSON()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(js2)).Should(Equal(string(js)))
+	})
+
+	It("produces a valid JSON schema", func() {
+		var schema map[string]interface{}
+		Ω(json.Unmarshal([]byte(gendescribe.Schema), &schema)).ShouldNot(HaveOccurred())
+		Ω(schema["properties"]).Should(HaveKey("format_version"))
+	})
+})
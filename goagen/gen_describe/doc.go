@@ -0,0 +1,16 @@
+/*
+Package gendescribe provides a generator that exports the evaluated design as a JSON document.
+The document lists the API, its resources, actions, routes, responses, security requirements,
+user types and media types so that external tools such as linters, documentation generators or
+API catalogs can consume the design without running Go code.
+
+The generator produces two files: describe/design.json contains the design description and
+describe/schema.json contains the JSON schema of the description. The document includes a
+"format_version" field whose value is FormatVersion, the value is bumped whenever the format
+changes in an incompatible way.
+
+Attributes whose type is a user type or a media type reference the type by name, the type
+itself is described once in the "types" list. This makes it possible to describe recursive
+types.
+*/
+package gendescribe
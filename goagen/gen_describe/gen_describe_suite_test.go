@@ -0,0 +1,13 @@
+package gendescribe_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenDescribe(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenDescribe Suite")
+}
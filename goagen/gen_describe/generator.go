@@ -0,0 +1,93 @@
+package gendescribe
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// NewGenerator returns an initialized instance of a design description generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the design description generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("describe", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the design description and its JSON schema.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	js, err := Describe(g.API).JSON()
+	if err != nil {
+		return
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "describe")
+	codegen.CleanDir(g.OutDir)
+	os.MkdirAll(g.OutDir, 0755)
+	g.genfiles = append(g.genfiles, g.OutDir)
+	designFile := filepath.Join(g.OutDir, "design.json")
+	if err = codegen.WriteFile(designFile, js); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, designFile)
+	schemaFile := filepath.Join(g.OutDir, "schema.json")
+	if err = codegen.WriteFile(schemaFile, []byte(Schema)); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, schemaFile)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invocation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
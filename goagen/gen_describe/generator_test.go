@@ -0,0 +1,64 @@
+package gendescribe_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_describe"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("describetest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gendescribe.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a dummy API", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API with no resource")
+			})
+			dslengine.Run()
+		})
+
+		It("generates the design description and its schema", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(3))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "describe", "design.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			var d gendescribe.Description
+			Ω(json.Unmarshal(content, &d)).ShouldNot(HaveOccurred())
+			Ω(d.FormatVersion).Should(Equal(gendescribe.FormatVersion))
+			Ω(d.API.Name).Should(Equal("test api"))
+			content, err = ioutil.ReadFile(filepath.Join(testPkg.Abs(), "describe", "schema.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(Equal(gendescribe.Schema))
+		})
+	})
+})
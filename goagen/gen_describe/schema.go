@@ -0,0 +1,178 @@
+package gendescribe
+
+// Schema is the JSON schema (draft 4) of the documents produced by Describe.
+const Schema = `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "title": "goa design description",
+  "type": "object",
+  "required": ["format_version", "api"],
+  "properties": {
+    "format_version": {"type": "string", "enum": ["1"]},
+    "api": {"$ref": "#/definitions/api"},
+    "resources": {"type": "array", "items": {"$ref": "#/definitions/resource"}},
+    "types": {"type": "array", "items": {"$ref": "#/definitions/type"}},
+    "security_schemes": {"type": "array", "items": {"$ref": "#/definitions/security_scheme"}}
+  },
+  "definitions": {
+    "metadata": {
+      "type": "object",
+      "additionalProperties": {"type": "array", "items": {"type": "string"}}
+    },
+    "strings": {"type": "array", "items": {"type": "string"}},
+    "security": {
+      "type": "object",
+      "required": ["scheme"],
+      "properties": {
+        "scheme": {"type": "string"},
+        "scopes": {"$ref": "#/definitions/strings"}
+      }
+    },
+    "api": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "title": {"type": "string"},
+        "description": {"type": "string"},
+        "version": {"type": "string"},
+        "host": {"type": "string"},
+        "schemes": {"$ref": "#/definitions/strings"},
+        "base_path": {"type": "string"},
+        "params": {"$ref": "#/definitions/attribute"},
+        "consumes": {"$ref": "#/definitions/strings"},
+        "produces": {"$ref": "#/definitions/strings"},
+        "security": {"$ref": "#/definitions/security"},
+        "metadata": {"$ref": "#/definitions/metadata"}
+      }
+    },
+    "resource": {
+      "type": "object",
+      "required": ["name", "full_path"],
+      "properties": {
+        "name": {"type": "string"},
+        "description": {"type": "string"},
+        "parent": {"type": "string"},
+        "base_path": {"type": "string"},
+        "full_path": {"type": "string"},
+        "schemes": {"$ref": "#/definitions/strings"},
+        "media_type": {"type": "string"},
+        "default_view": {"type": "string"},
+        "canonical_action": {"type": "string"},
+        "params": {"$ref": "#/definitions/attribute"},
+        "headers": {"$ref": "#/definitions/attribute"},
+        "actions": {"type": "array", "items": {"$ref": "#/definitions/action"}},
+        "file_servers": {"type": "array", "items": {"$ref": "#/definitions/file_server"}},
+        "responses": {"type": "array", "items": {"$ref": "#/definitions/response"}},
+        "security": {"$ref": "#/definitions/security"},
+        "metadata": {"$ref": "#/definitions/metadata"}
+      }
+    },
+    "action": {
+      "type": "object",
+      "required": ["name", "routes"],
+      "properties": {
+        "name": {"type": "string"},
+        "description": {"type": "string"},
+        "schemes": {"$ref": "#/definitions/strings"},
+        "routes": {"type": "array", "items": {"$ref": "#/definitions/route"}},
+        "params": {"$ref": "#/definitions/attribute"},
+        "query_params": {"$ref": "#/definitions/attribute"},
+        "headers": {"$ref": "#/definitions/attribute"},
+        "payload": {"$ref": "#/definitions/attribute"},
+        "payload_optional": {"type": "boolean"},
+        "responses": {"type": "array", "items": {"$ref": "#/definitions/response"}},
+        "security": {"$ref": "#/definitions/security"},
+        "metadata": {"$ref": "#/definitions/metadata"}
+      }
+    },
+    "route": {
+      "type": "object",
+      "required": ["verb", "path", "full_path"],
+      "properties": {
+        "verb": {"type": "string"},
+        "path": {"type": "string"},
+        "full_path": {"type": "string"}
+      }
+    },
+    "file_server": {
+      "type": "object",
+      "required": ["file_path", "request_path"],
+      "properties": {
+        "description": {"type": "string"},
+        "file_path": {"type": "string"},
+        "request_path": {"type": "string"}
+      }
+    },
+    "response": {
+      "type": "object",
+      "required": ["name", "status"],
+      "properties": {
+        "name": {"type": "string"},
+        "status": {"type": "integer"},
+        "description": {"type": "string"},
+        "media_type": {"type": "string"},
+        "view": {"type": "string"},
+        "headers": {"$ref": "#/definitions/attribute"}
+      }
+    },
+    "type": {
+      "type": "object",
+      "required": ["name", "attribute"],
+      "properties": {
+        "name": {"type": "string"},
+        "identifier": {"type": "string"},
+        "attribute": {"$ref": "#/definitions/attribute"},
+        "views": {"type": "object", "additionalProperties": {"$ref": "#/definitions/strings"}}
+      }
+    },
+    "attribute": {
+      "type": "object",
+      "required": ["type"],
+      "properties": {
+        "type": {
+          "type": "string",
+          "enum": ["boolean", "integer", "number", "string", "datetime", "uuid", "file", "any", "array", "hash", "object", "ref"]
+        },
+        "ref": {"type": "string"},
+        "description": {"type": "string"},
+        "elem": {"$ref": "#/definitions/attribute"},
+        "key": {"$ref": "#/definitions/attribute"},
+        "attributes": {"type": "object", "additionalProperties": {"$ref": "#/definitions/attribute"}},
+        "validation": {"$ref": "#/definitions/validation"},
+        "default": {},
+        "example": {},
+        "metadata": {"$ref": "#/definitions/metadata"}
+      }
+    },
+    "validation": {
+      "type": "object",
+      "properties": {
+        "values": {"type": "array"},
+        "format": {"type": "string"},
+        "pattern": {"type": "string"},
+        "minimum": {"type": "number"},
+        "maximum": {"type": "number"},
+        "min_length": {"type": "integer"},
+        "max_length": {"type": "integer"},
+        "required": {"$ref": "#/definitions/strings"}
+      }
+    },
+    "security_scheme": {
+      "type": "object",
+      "required": ["name", "type"],
+      "properties": {
+        "name": {"type": "string"},
+        "type": {"type": "string"},
+        "description": {"type": "string"},
+        "in": {"type": "string"},
+        "param_name": {"type": "string"},
+        "scopes": {"type": "object", "additionalProperties": {"type": "string"}},
+        "flow": {"type": "string"},
+        "token_url": {"type": "string"},
+        "authorization_url": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/metadata"}
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,582 @@
+package gendiff
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_describe"
+)
+
+type (
+	// Change describes a difference between two designs.
+	Change struct {
+		// Breaking is true if the change may break existing clients.
+		Breaking bool
+		// Location identifies the changed element, for example
+		// `resource "bottle" action "show" payload.name`.
+		Location string
+		// Description describes the change.
+		Description string
+	}
+
+	// Report lists the changes between two designs.
+	Report struct {
+		// Changes lists the changes in the order they were found.
+		Changes []*Change
+	}
+
+	// differ compares two design descriptions.
+	differ struct {
+		report   *Report
+		oldTypes map[string]*gendescribe.TypeDescription
+		newTypes map[string]*gendescribe.TypeDescription
+		// visited records the pairs of type names being compared to stop recursion.
+		visited map[visit]bool
+		// response is true while comparing response bodies.
+		response bool
+	}
+
+	// visit identifies a comparison of two types.
+	visit struct {
+		old, new string
+		response bool
+	}
+)
+
+// Metadata key used to override the name of attributes on the wire.
+const wireNameKey = "struct:tag:json"
+
+// DiffAPIs returns the changes between the old and the new API definitions.
+func DiffAPIs(old, new *design.APIDefinition) *Report {
+	return Diff(gendescribe.Describe(old), gendescribe.Describe(new))
+}
+
+// Diff returns the changes between the old and the new design descriptions. Removed resources,
+// actions, routes, responses, status codes and attributes, changed types, new required
+// attributes, narrowed validations and renamed wire fields are breaking changes. New resources,
+// actions, responses, status codes and optional attributes, widened validations and
+// documentation changes are not. The resources, actions, routes and status codes are compared
+// with design.Diff.
+func Diff(old, new *gendescribe.Description) *Report {
+	d := &differ{
+		report:   &Report{},
+		oldTypes: typesByName(old),
+		newTypes: typesByName(new),
+		visited:  make(map[visit]bool),
+	}
+	d.api(old.API, new.API)
+	d.structure(design.Diff(apiDefinition(old), apiDefinition(new)))
+	newRes := make(map[string]*gendescribe.ResourceDescription, len(new.Resources))
+	for _, r := range new.Resources {
+		newRes[r.Name] = r
+	}
+	for _, r := range old.Resources {
+		if n, ok := newRes[r.Name]; ok {
+			d.resource(fmt.Sprintf("resource %#v", r.Name), r, n)
+		}
+	}
+	return d.report
+}
+
+// apiDefinition builds the API definition made of the resources, actions, routes and response
+// status codes of the description for design.Diff. The routes are absolute so that their full
+// paths are the full paths of the description.
+func apiDefinition(desc *gendescribe.Description) *design.APIDefinition {
+	api := &design.APIDefinition{Resources: make(map[string]*design.ResourceDefinition, len(desc.Resources))}
+	for _, r := range desc.Resources {
+		res := &design.ResourceDefinition{Name: r.Name, Actions: make(map[string]*design.ActionDefinition, len(r.Actions))}
+		for _, a := range r.Actions {
+			act := &design.ActionDefinition{
+				Name:      a.Name,
+				Parent:    res,
+				Responses: make(map[string]*design.ResponseDefinition, len(a.Responses)),
+			}
+			for _, ro := range a.Routes {
+				act.Routes = append(act.Routes, &design.RouteDefinition{Verb: ro.Verb, Path: "/" + ro.FullPath, Parent: act})
+			}
+			for _, resp := range a.Responses {
+				act.Responses[resp.Name] = &design.ResponseDefinition{Name: resp.Name, Status: resp.Status, Parent: act}
+			}
+			res.Actions[a.Name] = act
+		}
+		api.Resources[r.Name] = res
+	}
+	return api
+}
+
+// structure records the resources, actions, routes and status codes added or removed.
+func (d *differ) structure(diff *design.APIDiff) {
+	for _, n := range diff.RemovedResources {
+		d.add(true, fmt.Sprintf("resource %#v", n), "resource removed")
+	}
+	for _, n := range diff.AddedResources {
+		d.add(false, fmt.Sprintf("resource %#v", n), "resource added")
+	}
+	for _, r := range diff.Resources {
+		loc := fmt.Sprintf("resource %#v", r.Name)
+		for _, n := range r.RemovedActions {
+			d.add(true, fmt.Sprintf("%s action %#v", loc, n), "action removed")
+		}
+		for _, n := range r.AddedActions {
+			d.add(false, fmt.Sprintf("%s action %#v", loc, n), "action added")
+		}
+		for _, a := range r.Actions {
+			aloc := fmt.Sprintf("%s action %#v", loc, a.Name)
+			for _, ro := range a.RemovedRoutes {
+				d.add(true, aloc, "route %s removed", ro)
+			}
+			for _, ro := range a.AddedRoutes {
+				d.add(false, aloc, "route %s added", ro)
+			}
+			for _, s := range a.RemovedStatuses {
+				d.add(true, aloc, "status code %d removed", s)
+			}
+			for _, s := range a.AddedStatuses {
+				d.add(false, aloc, "status code %d added", s)
+			}
+		}
+	}
+}
+
+// HasBreaking returns true if the report contains breaking changes.
+func (r *Report) HasBreaking() bool {
+	return len(r.Breaking()) > 0
+}
+
+// Breaking returns the breaking changes.
+func (r *Report) Breaking() []*Change {
+	var res []*Change
+	for _, c := range r.Changes {
+		if c.Breaking {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// String returns a human readable report listing the breaking changes first.
+func (r *Report) String() string {
+	if len(r.Changes) == 0 {
+		return "no changes\n"
+	}
+	var buf bytes.Buffer
+	breaking := r.Breaking()
+	fmt.Fprintf(&buf, "%d change(s), %d breaking\n", len(r.Changes), len(breaking))
+	if len(breaking) > 0 {
+		buf.WriteString("\nBreaking changes:\n")
+		for _, c := range breaking {
+			fmt.Fprintf(&buf, "  %s: %s\n", c.Location, c.Description)
+		}
+	}
+	if len(breaking) < len(r.Changes) {
+		buf.WriteString("\nNon-breaking changes:\n")
+		for _, c := range r.Changes {
+			if !c.Breaking {
+				fmt.Fprintf(&buf, "  %s: %s\n", c.Location, c.Description)
+			}
+		}
+	}
+	return buf.String()
+}
+
+func (d *differ) add(breaking bool, loc, format string, args ...interface{}) {
+	d.report.Changes = append(d.report.Changes, &Change{
+		Breaking:    breaking,
+		Location:    loc,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) api(old, new *gendescribe.APIDescription) {
+	loc := fmt.Sprintf("API %#v", new.Name)
+	if old.BasePath != new.BasePath {
+		d.add(true, loc, "base path changed from %#v to %#v", old.BasePath, new.BasePath)
+	}
+	d.removedStrings(loc, "scheme", old.Schemes, new.Schemes)
+	d.security(loc, old.Security, new.Security)
+	d.request(loc+" params", old.Params, new.Params)
+	if old.Description != new.Description || old.Title != new.Title {
+		d.add(false, loc, "documentation changed")
+	}
+}
+
+func (d *differ) resource(loc string, old, new *gendescribe.ResourceDescription) {
+	if old.FullPath != new.FullPath {
+		d.add(true, loc, "path changed from %#v to %#v", old.FullPath, new.FullPath)
+	}
+	d.removedStrings(loc, "scheme", old.Schemes, new.Schemes)
+	d.security(loc, old.Security, new.Security)
+	if old.Description != new.Description {
+		d.add(false, loc, "documentation changed")
+	}
+	newActs := make(map[string]*gendescribe.ActionDescription, len(new.Actions))
+	for _, a := range new.Actions {
+		newActs[a.Name] = a
+	}
+	for _, a := range old.Actions {
+		if n, ok := newActs[a.Name]; ok {
+			d.action(fmt.Sprintf("%s action %#v", loc, a.Name), a, n)
+		}
+	}
+}
+
+func (d *differ) action(loc string, old, new *gendescribe.ActionDescription) {
+	d.removedStrings(loc, "scheme", old.Schemes, new.Schemes)
+	d.security(loc, old.Security, new.Security)
+	d.request(loc+" params", old.Params, new.Params)
+	d.request(loc+" query params", old.QueryParams, new.QueryParams)
+	d.request(loc+" headers", old.Headers, new.Headers)
+	switch {
+	case old.Payload == nil && new.Payload != nil:
+		d.add(!new.PayloadOptional, loc, "payload added")
+	case old.Payload != nil && new.Payload == nil:
+		d.add(true, loc, "payload removed")
+	case old.Payload != nil:
+		if old.PayloadOptional && !new.PayloadOptional {
+			d.add(true, loc, "payload is now required")
+		}
+		d.attribute(loc+" payload", old.Payload, new.Payload)
+	}
+	d.responses(loc, old.Responses, new.Responses)
+	if old.Description != new.Description {
+		d.add(false, loc, "documentation changed")
+	}
+}
+
+func (d *differ) responses(loc string, old, new []*gendescribe.ResponseDescription) {
+	newResps := make(map[string]*gendescribe.ResponseDescription, len(new))
+	for _, r := range new {
+		newResps[r.Name] = r
+	}
+	oldResps := make(map[string]*gendescribe.ResponseDescription, len(old))
+	for _, r := range old {
+		oldResps[r.Name] = r
+		rloc := fmt.Sprintf("%s response %#v", loc, r.Name)
+		n, ok := newResps[r.Name]
+		if !ok {
+			d.add(true, rloc, "response removed")
+			continue
+		}
+		if r.MediaType != n.MediaType {
+			d.add(true, rloc, "media type changed from %#v to %#v", r.MediaType, n.MediaType)
+		}
+		if r.View != n.View {
+			d.add(true, rloc, "view changed from %#v to %#v", r.View, n.View)
+		}
+		d.attribute(rloc+" headers", r.Headers, n.Headers)
+		if r.MediaType == n.MediaType {
+			d.result(rloc, r.MediaType, r.View, n.View)
+		}
+		if r.Description != n.Description {
+			d.add(false, rloc, "documentation changed")
+		}
+	}
+	for _, r := range new {
+		if _, ok := oldResps[r.Name]; !ok {
+			d.add(false, fmt.Sprintf("%s response %#v", loc, r.Name), "response added")
+		}
+	}
+}
+
+// result compares the attributes rendered by the response media type views.
+func (d *differ) result(loc, identifier, oldView, newView string) {
+	old, new := typeWithIdentifier(d.oldTypes, identifier), typeWithIdentifier(d.newTypes, identifier)
+	if old == nil || new == nil {
+		return
+	}
+	if oldView == "" {
+		oldView = "default"
+	}
+	if newView == "" {
+		newView = "default"
+	}
+	if _, ok := old.Views[oldView]; !ok {
+		return
+	}
+	rendered := make(map[string]bool)
+	for _, n := range new.Views[newView] {
+		rendered[n] = true
+	}
+	for _, n := range old.Views[oldView] {
+		if !rendered[n] {
+			d.add(true, loc, "attribute %#v no longer rendered", n)
+		}
+	}
+	d.response = true
+	d.typeRef(loc+" body", old.Name, new.Name)
+	d.response = false
+}
+
+// request compares request attributes: params, query params and headers.
+func (d *differ) request(loc string, old, new *gendescribe.AttributeDescription) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		old = &gendescribe.AttributeDescription{Type: "object"}
+	case new == nil:
+		new = &gendescribe.AttributeDescription{Type: "object"}
+	}
+	d.attribute(loc, old, new)
+}
+
+// attribute compares two attributes recursively.
+func (d *differ) attribute(loc string, old, new *gendescribe.AttributeDescription) {
+	if old == nil || new == nil {
+		if old != nil {
+			d.add(true, loc, "removed")
+		} else if new != nil {
+			d.add(false, loc, "added")
+		}
+		return
+	}
+	if old.Type == "ref" && new.Type == "ref" {
+		d.validation(loc, old.Validation, new.Validation)
+		d.typeRef(loc, old.Ref, new.Ref)
+		return
+	}
+	if old.Type == "ref" {
+		if t, ok := d.oldTypes[old.Ref]; ok {
+			old = t.Attribute
+		}
+	}
+	if new.Type == "ref" {
+		if t, ok := d.newTypes[new.Ref]; ok {
+			new = t.Attribute
+		}
+	}
+	if old.Type != new.Type {
+		d.add(true, loc, "type changed from %s to %s", old.Type, new.Type)
+		return
+	}
+	if oldName, newName := wireName(old), wireName(new); oldName != newName {
+		d.add(true, loc, "wire name changed from %#v to %#v", oldName, newName)
+	}
+	d.validation(loc, old.Validation, new.Validation)
+	switch old.Type {
+	case "array":
+		d.attribute(loc+"[]", old.Elem, new.Elem)
+	case "hash":
+		d.attribute(loc+"{key}", old.Key, new.Key)
+		d.attribute(loc+"{}", old.Elem, new.Elem)
+	case "object":
+		d.object(loc, old, new)
+	}
+	if old.Description != new.Description {
+		d.add(false, loc, "documentation changed")
+	}
+}
+
+// object compares the child attributes of two objects.
+func (d *differ) object(loc string, old, new *gendescribe.AttributeDescription) {
+	oldReq, newReq := required(old), required(new)
+	for _, n := range sortedKeys(old.Attributes) {
+		aloc := loc + "." + n
+		att, ok := new.Attributes[n]
+		if !ok {
+			d.add(true, aloc, "attribute removed")
+			continue
+		}
+		if !oldReq[n] && newReq[n] {
+			d.add(!d.response, aloc, "attribute is now required")
+		} else if oldReq[n] && !newReq[n] {
+			d.add(d.response, aloc, "attribute is now optional")
+		}
+		d.attribute(aloc, old.Attributes[n], att)
+	}
+	for _, n := range sortedKeys(new.Attributes) {
+		if _, ok := old.Attributes[n]; ok {
+			continue
+		}
+		if newReq[n] {
+			d.add(!d.response, loc+"."+n, "required attribute added")
+		} else {
+			d.add(false, loc+"."+n, "optional attribute added")
+		}
+	}
+}
+
+// typeRef compares the user types or media types with the given names.
+func (d *differ) typeRef(loc, oldName, newName string) {
+	key := visit{oldName, newName, d.response}
+	if d.visited[key] {
+		return
+	}
+	d.visited[key] = true
+	defer delete(d.visited, key)
+	old, new := d.oldTypes[oldName], d.newTypes[newName]
+	if old == nil || new == nil {
+		return
+	}
+	d.attribute(loc, old.Attribute, new.Attribute)
+}
+
+// validation compares two sets of validations. Narrowed request validations and widened
+// response validations are breaking changes.
+func (d *differ) validation(loc string, old, new *gendescribe.ValidationDescription) {
+	if old == nil {
+		old = &gendescribe.ValidationDescription{}
+	}
+	if new == nil {
+		new = &gendescribe.ValidationDescription{}
+	}
+	switch {
+	case len(old.Values) == 0 && len(new.Values) > 0:
+		d.narrowed(true, loc, "enum validation added")
+	case len(old.Values) > 0 && len(new.Values) == 0:
+		d.narrowed(false, loc, "enum validation removed")
+	default:
+		for _, v := range old.Values {
+			if !containsValue(new.Values, v) {
+				d.narrowed(true, loc, "enum value %v removed", v)
+			}
+		}
+		for _, v := range new.Values {
+			if !containsValue(old.Values, v) {
+				d.narrowed(false, loc, "enum value %v added", v)
+			}
+		}
+	}
+	if old.Format != new.Format {
+		d.narrowed(new.Format != "", loc, "format changed from %#v to %#v", old.Format, new.Format)
+	}
+	if old.Pattern != new.Pattern {
+		d.narrowed(new.Pattern != "", loc, "pattern changed from %#v to %#v", old.Pattern, new.Pattern)
+	}
+	d.bound(loc, "minimum", old.Minimum, new.Minimum, true)
+	d.bound(loc, "maximum", old.Maximum, new.Maximum, false)
+	d.bound(loc, "min length", intBound(old.MinLength), intBound(new.MinLength), true)
+	d.bound(loc, "max length", intBound(old.MaxLength), intBound(new.MaxLength), false)
+}
+
+// bound compares two bounds, lower indicates whether the bound is a lower bound.
+func (d *differ) bound(loc, name string, old, new *float64, lower bool) {
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		d.narrowed(true, loc, "%s validation added", name)
+	case new == nil:
+		d.narrowed(false, loc, "%s validation removed", name)
+	case *old != *new:
+		narrowed := *new > *old
+		if !lower {
+			narrowed = *new < *old
+		}
+		d.narrowed(narrowed, loc, "%s changed from %v to %v", name, *old, *new)
+	}
+}
+
+// narrowed records a validation change, narrowing is breaking for requests while widening is
+// breaking for responses.
+func (d *differ) narrowed(narrowed bool, loc, format string, args ...interface{}) {
+	d.add(narrowed != d.response, loc, format, args...)
+}
+
+func (d *differ) security(loc string, old, new *gendescribe.SecurityDescription) {
+	switch {
+	case old == nil && new != nil:
+		d.add(true, loc, "security scheme %#v required", new.Scheme)
+	case old != nil && new == nil:
+		d.add(false, loc, "security scheme %#v no longer required", old.Scheme)
+	case old != nil && old.Scheme != new.Scheme:
+		d.add(true, loc, "security scheme changed from %#v to %#v", old.Scheme, new.Scheme)
+	case old != nil:
+		scopes := make(map[string]bool, len(old.Scopes))
+		for _, s := range old.Scopes {
+			scopes[s] = true
+		}
+		for _, s := range new.Scopes {
+			if !scopes[s] {
+				d.add(true, loc, "scope %#v required", s)
+			}
+		}
+	}
+}
+
+// removedStrings records a breaking change for each value of old missing in new. An empty new
+// list imposes no restriction.
+func (d *differ) removedStrings(loc, name string, old, new []string) {
+	if len(new) == 0 {
+		return
+	}
+	present := make(map[string]bool, len(new))
+	for _, s := range new {
+		present[s] = true
+	}
+	for _, s := range old {
+		if !present[s] {
+			d.add(true, loc, "%s %#v removed", name, s)
+		}
+	}
+}
+
+// typesByName indexes the types of the given description by name.
+func typesByName(desc *gendescribe.Description) map[string]*gendescribe.TypeDescription {
+	res := make(map[string]*gendescribe.TypeDescription, len(desc.Types))
+	for _, t := range desc.Types {
+		res[t.Name] = t
+	}
+	return res
+}
+
+// typeWithIdentifier returns the media type with the given identifier if any.
+func typeWithIdentifier(types map[string]*gendescribe.TypeDescription, identifier string) *gendescribe.TypeDescription {
+	if identifier == "" {
+		return nil
+	}
+	for _, t := range types {
+		if t.Identifier == identifier {
+			return t
+		}
+	}
+	return nil
+}
+
+// wireName returns the name of the attribute on the wire if overridden via metadata.
+func wireName(att *gendescribe.AttributeDescription) string {
+	if tags, ok := att.Metadata[wireNameKey]; ok && len(tags) > 0 {
+		return tags[0]
+	}
+	return ""
+}
+
+// required returns the names of the required child attributes.
+func required(att *gendescribe.AttributeDescription) map[string]bool {
+	res := make(map[string]bool)
+	if att.Validation != nil {
+		for _, n := range att.Validation.Required {
+			res[n] = true
+		}
+	}
+	return res
+}
+
+func sortedKeys(m map[string]*gendescribe.AttributeDescription) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// containsValue compares the values using their string representation so that values decoded
+// from JSON (where all numbers are float64) match the values read from the design.
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, val := range values {
+		if fmt.Sprintf("%v", val) == fmt.Sprintf("%v", v) {
+			return true
+		}
+	}
+	return false
+}
+
+func intBound(i *int) *float64 {
+	if i == nil {
+		return nil
+	}
+	f := float64(*i)
+	return &f
+}
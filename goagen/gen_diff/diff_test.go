@@ -0,0 +1,203 @@
+package gendiff_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_describe"
+	"github.com/goadesign/goa/goagen/gen_diff"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// describe runs the given DSL and returns the description of the resulting design.
+func describe(dsl func()) *gendescribe.Description {
+	dslengine.Reset()
+	dsl()
+	dslengine.Run()
+	Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+	return gendescribe.Describe(Design)
+}
+
+// bottles returns a DSL defining a bottle resource, typ customizes the bottle type and show
+// defines the show action responses, show may be nil in which case the action defines a OK
+// response.
+func bottles(typ, show func()) func() {
+	return func() {
+		var Bottle *UserTypeDefinition
+		Bottle = Type("Bottle", func() {
+			Attribute("name", String)
+			Attribute("related", ArrayOf(Bottle))
+			typ()
+		})
+		API("test", func() {})
+		Resource("bottle", func() {
+			Action("create", func() {
+				Routing(POST("/"))
+				Payload(Bottle)
+				Response(Created)
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+				if show == nil {
+					Response(OK)
+				} else {
+					show()
+				}
+			})
+		})
+	}
+}
+
+func noop() {}
+
+var _ = Describe("Diff", func() {
+	var oldDSL, newDSL func()
+	var report *gendiff.Report
+
+	BeforeEach(func() {
+		oldDSL = bottles(noop, nil)
+		newDSL = oldDSL
+	})
+
+	JustBeforeEach(func() {
+		old := describe(oldDSL)
+		report = gendiff.Diff(old, describe(newDSL))
+	})
+
+	changes := func(breaking bool) []string {
+		var res []string
+		for _, c := range report.Changes {
+			if c.Breaking == breaking {
+				res = append(res, c.Location+": "+c.Description)
+			}
+		}
+		return res
+	}
+
+	Context("with identical designs", func() {
+		It("reports no change", func() {
+			Ω(report.Changes).Should(BeEmpty())
+			Ω(report.String()).Should(Equal("no changes\n"))
+		})
+	})
+
+	Context("with a removed action", func() {
+		BeforeEach(func() {
+			newDSL = func() {
+				API("test", func() {})
+				Resource("bottle", func() {
+					Action("create", func() {
+						Routing(POST("/"))
+						Payload(func() {
+							Attribute("name", String)
+						})
+						Response(Created)
+					})
+				})
+			}
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes(true)).Should(ContainElement(`resource "bottle" action "show": action removed`))
+			Ω(report.HasBreaking()).Should(BeTrue())
+		})
+	})
+
+	Context("with a new optional attribute", func() {
+		BeforeEach(func() {
+			newDSL = bottles(func() { Attribute("vintage", Integer) }, nil)
+		})
+
+		It("reports a non-breaking change", func() {
+			Ω(changes(true)).Should(BeEmpty())
+			Ω(changes(false)).Should(Equal([]string{`resource "bottle" action "create" payload.vintage: optional attribute added`}))
+		})
+	})
+
+	Context("with a new required attribute", func() {
+		BeforeEach(func() {
+			newDSL = bottles(func() {
+				Attribute("vintage", Integer)
+				Required("vintage")
+			}, nil)
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes(true)).Should(Equal([]string{`resource "bottle" action "create" payload.vintage: required attribute added`}))
+		})
+	})
+
+	Context("with a narrowed validation", func() {
+		BeforeEach(func() {
+			newDSL = bottles(func() {
+				Attribute("name", String, func() { MaxLength(10) })
+			}, nil)
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes(true)).Should(Equal([]string{`resource "bottle" action "create" payload.name: max length validation added`}))
+		})
+	})
+
+	Context("with a renamed wire field", func() {
+		BeforeEach(func() {
+			newDSL = bottles(func() {
+				Attribute("name", String, func() { Metadata("struct:tag:json", "title") })
+			}, nil)
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes(true)).Should(Equal([]string{`resource "bottle" action "create" payload.name: wire name changed from "" to "title"`}))
+		})
+	})
+
+	Context("with a changed status code", func() {
+		BeforeEach(func() {
+			newDSL = bottles(noop, func() {
+				Response(OK, func() {
+					Status(203)
+					Media("text/plain")
+				})
+			})
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes(true)).Should(Equal([]string{`resource "bottle" action "show": status code 200 removed`}))
+			Ω(changes(false)).Should(ContainElement(`resource "bottle" action "show": status code 203 added`))
+		})
+	})
+
+	Context("with a documentation change", func() {
+		BeforeEach(func() {
+			newDSL = bottles(noop, func() {
+				Description("Show a bottle")
+				Response(OK)
+			})
+		})
+
+		It("reports a non-breaking change", func() {
+			Ω(changes(true)).Should(BeEmpty())
+			Ω(changes(false)).Should(Equal([]string{`resource "bottle" action "show": documentation changed`}))
+		})
+	})
+
+	Context("with a new resource", func() {
+		BeforeEach(func() {
+			newDSL = func() {
+				bottles(noop, nil)()
+				Resource("cellar", func() {
+					Action("list", func() {
+						Routing(GET("/cellars"))
+						Response(OK)
+					})
+				})
+			}
+		})
+
+		It("reports a non-breaking change", func() {
+			Ω(changes(true)).Should(BeEmpty())
+			Ω(changes(false)).Should(Equal([]string{`resource "cellar": resource added`}))
+		})
+	})
+})
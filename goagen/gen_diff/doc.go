@@ -0,0 +1,11 @@
+/*
+Package gendiff compares two designs and classifies the differences as breaking or non-breaking
+changes for existing clients.
+
+The comparison operates on the descriptions produced by the gendescribe package so that the old
+design can be loaded from a "design.json" file previously produced by "goagen describe". The
+package can be used directly via the Diff and DiffAPIs functions or via "goagen diff" which
+compares the current design with a description file and exits with a non-zero status if any
+breaking change is found, making it suitable for gating changes in continuous integration.
+*/
+package gendiff
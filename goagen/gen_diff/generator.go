@@ -0,0 +1,108 @@
+package gendiff
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_describe"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// NewGenerator returns an initialized instance of a design diff generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the design diff generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Old      string                // Path to the old design description
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, old, ver string
+	set := flag.NewFlagSet("diff", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&old, "old", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Old: old, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate compares the API definition with the old design description and writes the report
+// to the file "diff.txt". Generate returns an error containing the report if the API definition
+// introduces breaking changes.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+	if g.Old == "" {
+		return nil, fmt.Errorf("missing path to old design description, use goagen describe to produce it")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	content, err := ioutil.ReadFile(g.Old)
+	if err != nil {
+		return
+	}
+	var old gendescribe.Description
+	if err = json.Unmarshal(content, &old); err != nil {
+		return nil, fmt.Errorf("invalid design description %s: %s", g.Old, err)
+	}
+	if old.FormatVersion != gendescribe.FormatVersion {
+		return nil, fmt.Errorf("unsupported design description format version %#v in %s, expected %#v",
+			old.FormatVersion, g.Old, gendescribe.FormatVersion)
+	}
+
+	report := Diff(&old, gendescribe.Describe(g.API))
+	if report.HasBreaking() {
+		return nil, fmt.Errorf("design introduces breaking changes\n%s", report)
+	}
+
+	os.MkdirAll(g.OutDir, 0755)
+	reportFile := filepath.Join(g.OutDir, "diff.txt")
+	if err = codegen.WriteFile(reportFile, []byte(report.String())); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, reportFile)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invocation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
@@ -0,0 +1,71 @@
+package gendiff_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_diff"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+	var newDSL func()
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("difftest")
+		Ω(err).ShouldNot(HaveOccurred())
+		js, err := describe(bottles(noop, nil)).JSON()
+		Ω(err).ShouldNot(HaveOccurred())
+		old := filepath.Join(testPkg.Abs(), "design.json")
+		Ω(ioutil.WriteFile(old, js, 0644)).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--old=" + old, "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		describe(newDSL)
+		files, genErr = gendiff.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with non-breaking changes", func() {
+		BeforeEach(func() {
+			newDSL = bottles(func() { Attribute("vintage", Integer) }, nil)
+		})
+
+		It("writes the report", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(files).Should(HaveLen(1))
+			content, err := ioutil.ReadFile(files[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("optional attribute added"))
+		})
+	})
+
+	Context("with breaking changes", func() {
+		BeforeEach(func() {
+			newDSL = bottles(func() { Required("name") }, nil)
+		})
+
+		It("fails with the report", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(genErr.Error()).Should(ContainSubstring(`payload.name: attribute is now required`))
+			Ω(files).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,27 @@
+package gendiff
+
+import "github.com/goadesign/goa/design"
+
+// Option a generator option definition
+type Option func(*Generator)
+
+// API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+// OutDir Path to output directory
+func OutDir(outDir string) Option {
+	return func(g *Generator) {
+		g.OutDir = outDir
+	}
+}
+
+// Old Path to the description of the old design produced by goagen describe
+func Old(old string) Option {
+	return func(g *Generator) {
+		g.Old = old
+	}
+}
@@ -3,7 +3,6 @@ package genjs
 import (
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
@@ -99,7 +98,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 	}
 
 	g.OutDir = filepath.Join(g.OutDir, "js")
-	if err := os.RemoveAll(g.OutDir); err != nil {
+	if err := codegen.CleanDir(g.OutDir); err != nil {
 		return nil, err
 	}
 	if err := os.MkdirAll(g.OutDir, 0755); err != nil {
@@ -237,7 +236,7 @@ func (g *Generator) generateIndexHTML(htmlFile string, exampleAction *design.Act
 
 func (g *Generator) generateAxiosJS() error {
 	filePath := filepath.Join(g.OutDir, "axios.min.js")
-	if err := ioutil.WriteFile(filePath, []byte(axios), 0644); err != nil {
+	if err := codegen.WriteFile(filePath, []byte(axios)); err != nil {
 		return err
 	}
 	g.genfiles = append(g.genfiles, filePath)
@@ -252,10 +251,10 @@ func (g *Generator) generateExample() error {
 		return err
 	}
 	defer func() {
-		file.Close()
 		if err == nil {
 			err = file.FormatCode()
 		}
+		file.Close()
 	}()
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("net/http"),
@@ -4,6 +4,17 @@ This generator generates the code for a basic "main" package and is mainly inten
 bootstrap new applications.
 The generator creates a main.go file and one file per resource listed in the API metadata.
 If a file already exists it skips its creation unless the flag --force is provided on the command
-line in which case it overrides the content of existing files.
+line in which case it overrides the content of existing files. The flag --update makes the generator
+append stubs for the actions missing from existing controller files instead, the methods that
+implement removed actions or whose signature no longer matches the design are reported but left
+untouched.
+
+The generated main starts a single listener on the port of the API host by default. If the API
+defines servers (see Server) it starts one listener per distinct server port instead and mounts
+on each listener the controllers of the resources exposed by its servers. The HTTPS listeners use
+the certificate and key given with the --cert and --key flags, or a self-signed certificate with
+--self-signed, and the --h2c flag enables cleartext HTTP/2 on the HTTP listeners. The routes of
+each listener are logged on startup. Servers whose URL scheme is not http, https, ws or wss are
+not served.
 */
 package genmain
@@ -39,6 +39,7 @@ type Generator struct {
 	Target    string                // Name of generated "app" package
 	Force     bool                  // Whether to override existing files
 	Regen     bool                  // Whether to regenerate scaffolding in place, maintaining controller implementation
+	Update    bool                  // Whether to add missing actions to existing controllers
 	genfiles  []string              // Generated files
 }
 
@@ -46,7 +47,7 @@ type Generator struct {
 func Generate() (files []string, err error) {
 	var (
 		outDir, toolDir, designPkg, target, ver string
-		force, notool, regen                    bool
+		force, notool, regen, update            bool
 	)
 
 	set := flag.NewFlagSet("main", flag.PanicOnError)
@@ -58,6 +59,7 @@ func Generate() (files []string, err error) {
 	set.BoolVar(&notool, "notool", false, "")
 	set.BoolVar(&force, "force", false, "")
 	set.BoolVar(&regen, "regen", false, "")
+	set.BoolVar(&update, "update", false, "")
 	set.Bool("notest", false, "")
 	set.Parse(os.Args[1:])
 
@@ -66,7 +68,7 @@ func Generate() (files []string, err error) {
 	}
 
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, DesignPkg: designPkg, Target: target, Force: force, Regen: regen, API: design.Design}
+	g := &Generator{OutDir: outDir, DesignPkg: designPkg, Target: target, Force: force, Regen: regen, Update: update, API: design.Design}
 
 	return g.Generate()
 }
@@ -117,13 +119,23 @@ func extractControllerBody(filename string) (map[string]string, []*ast.ImportSpe
 }
 
 // GenerateController generates the controller corresponding to the given
-// resource and returns the generated filename.
-func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *design.ResourceDefinition) (filename string, err error) {
+// resource and returns the generated filename. If update is true and the
+// controller file already exists then stubs are appended for the missing
+// actions and methods that no longer match the design are reported on stderr,
+// the returned filename is empty in this case.
+func GenerateController(force, regen, update bool, appPkg, outDir, pkg, name string, r *design.ResourceDefinition) (filename string, err error) {
 	filename = filepath.Join(outDir, codegen.SnakeCase(name)+".go")
 	var (
 		actionImpls      map[string]string
 		extractedImports []*ast.ImportSpec
 	)
+	elems := strings.Split(appPkg, "/")
+	pkgName := elems[len(elems)-1]
+	if update && !force && !regen {
+		if _, e := os.Stat(filename); e == nil {
+			return "", updateController(filename, appPkg, pkgName, outDir, r)
+		}
+	}
 	if regen {
 		actionImpls, extractedImports, err = extractControllerBody(filename)
 		if err != nil {
@@ -147,23 +159,15 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 		return "", err
 	}
 	defer func() {
-		file.Close()
 		if err == nil {
 			err = file.FormatCode()
 		}
+		file.Close()
 	}()
 
-	elems := strings.Split(appPkg, "/")
-	pkgName := elems[len(elems)-1]
-	var imp string
-	if _, err := codegen.PackageSourcePath(appPkg); err == nil {
-		imp = appPkg
-	} else {
-		imp, err = codegen.PackagePath(outDir)
-		if err != nil {
-			return "", err
-		}
-		imp = path.Join(filepath.ToSlash(imp), appPkg)
+	imp, err := appImport(appPkg, outDir)
+	if err != nil {
+		return "", err
 	}
 
 	imports := []*codegen.ImportSpec{
@@ -193,6 +197,9 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 		return "", err
 	}
 	err = r.IterateActions(func(a *design.ActionDefinition) error {
+		if a.Static != nil {
+			return nil
+		}
 		if a.WebSocket() {
 			return file.ExecuteTemplate("actionWS", actionWST, funcs, a)
 		}
@@ -204,6 +211,38 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 	return
 }
 
+// updateController appends the missing action stubs to the existing controller
+// file and reports the methods that could not be reconciled with the design.
+func updateController(filename, appPkg, pkgName, outDir string, r *design.ResourceDefinition) error {
+	imp, err := appImport(appPkg, outDir)
+	if err != nil {
+		return err
+	}
+	u, err := UpdateController(filename, pkgName, imp, r)
+	if err != nil {
+		return err
+	}
+	if len(u.Added) > 0 {
+		fmt.Fprintf(os.Stderr, "%s: added %s\n", filename, strings.Join(u.Added, ", "))
+	}
+	for _, w := range u.Warnings(filename, pkgName, r) {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	return nil
+}
+
+// appImport returns the import path of the generated "app" package.
+func appImport(appPkg, outDir string) (string, error) {
+	if _, err := codegen.PackageSourcePath(appPkg); err == nil {
+		return appPkg, nil
+	}
+	imp, err := codegen.PackagePath(outDir)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(filepath.ToSlash(imp), appPkg), nil
+}
+
 // Generate produces the skeleton main.
 func (g *Generator) Generate() (_ []string, err error) {
 	if g.API == nil {
@@ -239,15 +278,25 @@ func (g *Generator) Generate() (_ []string, err error) {
 		}
 	}
 
-	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
-		filename, err := GenerateController(g.Force, g.Regen, g.Target, g.OutDir, "main", r.Name, r)
-		if err != nil {
-			return err
-		}
-
-		g.genfiles = append(g.genfiles, filename)
+	var resources []*design.ResourceDefinition
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		resources = append(resources, r)
 		return nil
 	})
+	filenames := make([]string, len(resources))
+	err = codegen.Parallel(codegen.Jobs, len(resources), func(i int) error {
+		r := resources[i]
+		filename, err := GenerateController(g.Force, g.Regen, g.Update, g.Target, g.OutDir, "main", r.Name, r)
+		filenames[i] = filename
+		return err
+	})
+	// Record all the files, including the ones generated after a failure, so that they
+	// get cleaned up.
+	for _, filename := range filenames {
+		if err == nil || filename != "" {
+			g.genfiles = append(g.genfiles, filename)
+		}
+	}
 	if err != nil {
 		return
 	}
@@ -270,10 +319,10 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) (err
 		return err
 	}
 	defer func() {
-		file.Close()
 		if err == nil {
 			err = file.FormatCode()
 		}
+		file.Close()
 	}()
 	g.genfiles = append(g.genfiles, mainFile)
 	funcs["getPort"] = func(hostport string) string {
@@ -294,10 +343,40 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) (err
 		codegen.SimpleImport("github.com/goadesign/goa/middleware"),
 		codegen.SimpleImport(appPkg),
 	}
+	listeners, err := listeners(g.API)
+	if err != nil {
+		return err
+	}
+	if len(listeners) > 0 {
+		imports = append(imports,
+			codegen.SimpleImport("crypto/tls"),
+			codegen.SimpleImport("flag"),
+			codegen.SimpleImport("golang.org/x/net/http2"),
+			codegen.SimpleImport("golang.org/x/net/http2/h2c"),
+		)
+	}
 	file.Write([]byte("//go:generate goagen bootstrap -d " + g.DesignPkg + "\n\n"))
 	if err = file.WriteHeader("", "main", imports); err != nil {
 		return err
 	}
+	if len(listeners) > 0 {
+		var hasTLS, hasPlain bool
+		for _, l := range listeners {
+			if l.TLS {
+				hasTLS = true
+			} else {
+				hasPlain = true
+			}
+		}
+		data := map[string]interface{}{
+			"Name":      g.API.Name,
+			"API":       g.API,
+			"Listeners": listeners,
+			"HasTLS":    hasTLS,
+			"HasPlain":  hasPlain,
+		}
+		return file.ExecuteTemplate("main", serversMainT, funcs, data)
+	}
 	tls := false
 	for _, scheme := range g.API.Schemes {
 		if scheme == "https" {
@@ -313,6 +392,87 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) (err
 	return
 }
 
+// serversMainT is the template of the main function used when the API defines servers. The main
+// starts one listener per distinct server port and mounts the controllers of the resources exposed
+// by the servers of each listener.
+const serversMainT = `
+func main() {
+	// Parse command line flags
+	var (
+{{- if .HasTLS }}
+		certFile   = flag.String("cert", "cert.pem", "path to the TLS certificate of the HTTPS listeners")
+		keyFile    = flag.String("key", "key.pem", "path to the TLS private key of the HTTPS listeners")
+		selfSigned = flag.Bool("self-signed", false, "serve HTTPS with a generated self-signed certificate, for development only")
+{{- end }}
+{{- if .HasPlain }}
+		enableH2C = flag.Bool("h2c", false, "enable cleartext HTTP/2 on the HTTP listeners")
+{{- end }}
+	)
+	flag.Parse()
+
+	errc := make(chan error)
+{{ range $i, $l := .Listeners }}
+	// Listen on {{ $l.Addr }} for {{ join $l.URLs ", " }}
+	{{ if $i }}service = {{ else }}service := {{ end }}newService()
+{{- range $l.Resources }}{{ $name := goify .Name true }}{{ $tmp := tempvar }}
+	{{ $tmp }} := New{{ $name }}Controller(service)
+	{{ targetPkg }}.Mount{{ $name }}Controller(service, {{ $tmp }})
+{{- range .Mounts }}
+	{{ targetPkg }}.Mount{{ $name }}{{ goify .Name true }}Controller(service, {{ $tmp }})
+{{- end }}
+{{- end }}
+{{- if $.API.HealthCheck }}
+	{{ targetPkg }}.MountHealthCheck(service)
+{{- end }}
+	go func(service *goa.Service) {
+		errc <- {{ if $l.TLS }}serveTLS(service, {{ printf "%q" $l.Addr }}, *certFile, *keyFile, *selfSigned{{ range $l.Hosts }}, {{ printf "%q" . }}{{ end }}){{ else }}serve(service, {{ printf "%q" $l.Addr }}, *enableH2C){{ end }}
+	}(service)
+{{ end }}
+	if err := <-errc; err != nil {
+		service.LogError("startup", "err", err)
+	}
+}
+
+// newService creates a service and mounts the middleware.
+func newService() *goa.Service {
+	service := goa.New({{ printf "%q" .Name }})
+	service.Use(middleware.RequestIDWithEcho({{ printf "%q" .API.RequestIDHeaderName }}))
+	service.Use(middleware.LogRequest(true))
+	service.Use(middleware.ErrorHandler(service, true))
+	service.Use(middleware.Recover())
+	return service
+}
+{{ if .HasPlain }}
+// serve logs the service routes and serves HTTP requests on addr. enableH2C enables cleartext
+// HTTP/2 connections.
+func serve(service *goa.Service, addr string, enableH2C bool) error {
+	service.Server.Addr = addr
+	service.LogRoutes()
+	if enableH2C {
+		service.Server.Handler = h2c.NewHandler(service.Server.Handler, &http2.Server{})
+	}
+	service.LogInfo("listen", "transport", "http", "addr", addr)
+	return service.Server.ListenAndServe()
+}
+{{ end }}{{ if .HasTLS }}
+// serveTLS logs the service routes and serves HTTPS requests on addr using the given certificate
+// and key files, or a self-signed certificate valid for hosts if selfSigned is true.
+func serveTLS(service *goa.Service, addr, certFile, keyFile string, selfSigned bool, hosts ...string) error {
+	service.Server.Addr = addr
+	service.LogRoutes()
+	if selfSigned {
+		cert, err := goa.SelfSignedCertificate(hosts...)
+		if err != nil {
+			return err
+		}
+		service.Server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		certFile, keyFile = "", ""
+	}
+	service.LogInfo("listen", "transport", "https", "addr", addr)
+	return service.Server.ListenAndServeTLS(certFile, keyFile)
+}
+{{ end }}`
+
 // tempCount is the counter used to create unique temporary variable names.
 var tempCount int
 
@@ -456,7 +616,7 @@ func main() {
 	service := goa.New({{ printf "%q" .Name }})
 
 	// Mount middleware
-	service.Use(middleware.RequestID())
+	service.Use(middleware.RequestIDWithEcho({{ printf "%q" .API.RequestIDHeaderName }}))
 	service.Use(middleware.LogRequest(true))
 	service.Use(middleware.ErrorHandler(service, true))
 	service.Use(middleware.Recover())
@@ -464,8 +624,11 @@ func main() {
 {{ range $name, $res := $api.Resources }}{{ $name := goify $res.Name true }} // Mount "{{$res.Name}}" controller
 	{{ $tmp := tempvar }}{{ $tmp }} := New{{ $name }}Controller(service)
 	{{ targetPkg }}.Mount{{ $name }}Controller(service, {{ $tmp }})
+{{ range $res.Mounts }}	{{ targetPkg }}.Mount{{ $name }}{{ goify .Name true }}Controller(service, {{ $tmp }})
+{{ end }}{{ end }}
+{{ if $api.HealthCheck }} // Mount health check
+	{{ targetPkg }}.MountHealthCheck(service)
 {{ end }}
-
 {{ if .TLS }}
 	// Start service
 	if err := service.ListenAndServeTLS(":{{ getPort .API.Host }}", "cert.pem", "key.pem"); err != nil {
@@ -75,6 +75,103 @@ var _ = Describe("Generate", func() {
 			})
 
 		})
+
+		It("mounts the request ID middleware with the default header", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`service.Use(middleware.RequestIDWithEcho("X-Request-ID"))`))
+		})
+
+		Context("with a custom request ID header", func() {
+			BeforeEach(func() {
+				design.Design.RequestIDHeader = "X-Correlation-ID"
+			})
+
+			It("mounts the request ID middleware with the custom header", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`service.Use(middleware.RequestIDWithEcho("X-Correlation-ID"))`))
+			})
+		})
+
+		Context("with servers", func() {
+			BeforeEach(func() {
+				design.Design.Servers = []*design.ServerDefinition{
+					{URL: "https://api.example.com"},
+					{URL: "http://localhost:8080"},
+					{URL: "http://internal.example.com:8080"},
+				}
+			})
+
+			It("starts one listener per server port", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`flag.Bool("h2c", false`))
+				Ω(string(content)).Should(ContainSubstring(`flag.Bool("self-signed", false`))
+				Ω(string(content)).Should(ContainSubstring(`// Listen on :8080 for http://localhost:8080, http://internal.example.com:8080`))
+				Ω(string(content)).Should(ContainSubstring(`errc <- serve(service, ":8080", *enableH2C)`))
+				Ω(string(content)).Should(ContainSubstring(`errc <- serveTLS(service, ":443", *certFile, *keyFile, *selfSigned, "api.example.com")`))
+				Ω(string(content)).ShouldNot(ContainSubstring(listenAndServeCode))
+				_, err = gexec.Build(testgenPackagePath)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			Context("using the same port with and without TLS", func() {
+				BeforeEach(func() {
+					design.Design.Servers = []*design.ServerDefinition{
+						{URL: "https://localhost:8080"},
+						{URL: "http://localhost:8080"},
+					}
+				})
+
+				It("returns an error", func() {
+					Ω(genErr).Should(HaveOccurred())
+					Ω(genErr.Error()).Should(ContainSubstring("port 8080 is used by servers"))
+				})
+			})
+
+			Context("including a gRPC server", func() {
+				BeforeEach(func() {
+					design.Design.Servers = append(design.Design.Servers,
+						&design.ServerDefinition{URL: "grpc://localhost:8090"})
+				})
+
+				It("returns an error", func() {
+					Ω(genErr).Should(HaveOccurred())
+					Ω(genErr.Error()).Should(ContainSubstring(`server "grpc://localhost:8090" uses scheme "grpc"`))
+				})
+			})
+		})
+	})
+
+	Context("with resources restricted to servers", func() {
+		BeforeEach(func() {
+			public := &design.ResourceDefinition{Name: "public", Servers: []string{"public"}}
+			internal := &design.ResourceDefinition{Name: "internal", Servers: []string{"internal"}}
+			design.Design = &design.APIDefinition{
+				Name: "test api",
+				Servers: []*design.ServerDefinition{
+					{URL: "https://api.example.com", Name: "public"},
+					{URL: "http://localhost:8081", Name: "internal"},
+				},
+				Resources: map[string]*design.ResourceDefinition{
+					"public":   public,
+					"internal": internal,
+				},
+			}
+		})
+
+		It("mounts the controllers on the listeners of their servers", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(MatchRegexp(`(?s)Listen on :443 .*MountPublicController.*serveTLS.*Listen on :8081 .*MountInternalController.*serve\(service, ":8081"`))
+			Ω(strings.Count(string(content), "MountPublicController")).Should(Equal(1))
+			Ω(strings.Count(string(content), "MountInternalController")).Should(Equal(1))
+		})
 	})
 
 	Context("with resources", func() {
@@ -198,6 +295,7 @@ var _ = Describe("NewGenerator", func() {
 		target    string
 		force     bool
 		regen     bool
+		update    bool
 		noExample bool
 	}{
 		api: &design.APIDefinition{
@@ -208,6 +306,7 @@ var _ = Describe("NewGenerator", func() {
 		target:    "app",
 		force:     false,
 		regen:     false,
+		update:    true,
 	}
 
 	Context("with options all options set", func() {
@@ -220,6 +319,7 @@ var _ = Describe("NewGenerator", func() {
 				genmain.Target(args.target),
 				genmain.Force(args.force),
 				genmain.Regen(args.regen),
+				genmain.Update(args.update),
 			)
 		})
 
@@ -231,6 +331,7 @@ var _ = Describe("NewGenerator", func() {
 			Ω(generator.Target).Should(Equal(args.target))
 			Ω(generator.Force).Should(Equal(args.force))
 			Ω(generator.Regen).Should(Equal(args.regen))
+			Ω(generator.Update).Should(Equal(args.update))
 		})
 
 	})
@@ -0,0 +1,90 @@
+package genmain
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+)
+
+// listener describes a listener started by the generated main when the API defines servers.
+type listener struct {
+	// Addr is the listen address, e.g. ":443".
+	Addr string
+	// TLS is true if the listener serves HTTPS.
+	TLS bool
+	// URLs lists the URLs of the servers served by the listener.
+	URLs []string
+	// Hosts lists the distinct host names of the servers, the self-signed certificate of
+	// HTTPS listeners is valid for these hosts.
+	Hosts []string
+	// Resources lists the resources mounted on the listener sorted by name.
+	Resources []*design.ResourceDefinition
+
+	servers []*design.ServerDefinition
+}
+
+// listeners groups the API servers by port and returns one listener per distinct port sorted by
+// port number. Each listener serves the resources exposed by at least one of its servers.
+// listeners returns an error if a port is used by servers with and without TLS or if a server uses
+// a scheme other than "http", "https", "ws" or "wss" as the generated main cannot serve it.
+func listeners(api *design.APIDefinition) ([]*listener, error) {
+	byPort := make(map[int]*listener)
+	var ports []int
+	for _, s := range api.Servers {
+		u, err := url.Parse(s.DefaultURL())
+		if err != nil {
+			return nil, fmt.Errorf("invalid server URL %#v: %s", s.URL, err)
+		}
+		switch u.Scheme {
+		case "http", "https", "ws", "wss":
+		default:
+			return nil, fmt.Errorf("server %#v uses scheme %#v, the generated main only serves HTTP and websocket servers",
+				s.DefaultURL(), u.Scheme)
+		}
+		port := s.Port()
+		l, ok := byPort[port]
+		if !ok {
+			l = &listener{Addr: fmt.Sprintf(":%d", port), TLS: s.Secure()}
+			byPort[port] = l
+			ports = append(ports, port)
+		} else if l.TLS != s.Secure() {
+			return nil, fmt.Errorf("port %d is used by servers %#v and %#v with and without TLS",
+				port, l.URLs[0], s.DefaultURL())
+		}
+		l.URLs = append(l.URLs, s.DefaultURL())
+		if h := s.Host(); !contains(l.Hosts, h) {
+			l.Hosts = append(l.Hosts, h)
+		}
+		l.servers = append(l.servers, s)
+	}
+	sort.Ints(ports)
+	res := make([]*listener, len(ports))
+	for i, p := range ports {
+		l := byPort[p]
+		api.IterateResources(func(r *design.ResourceDefinition) error {
+			for _, s := range r.EffectiveServers() {
+				for _, ls := range l.servers {
+					if s == ls {
+						l.Resources = append(l.Resources, r)
+						return nil
+					}
+				}
+			}
+			return nil
+		})
+		res[i] = l
+	}
+	return res, nil
+}
+
+// contains returns true if vals contains val.
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
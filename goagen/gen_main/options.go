@@ -46,3 +46,10 @@ func Regen(regen bool) Option {
 		g.Regen = regen
 	}
 }
+
+//Update Whether to add stubs for missing actions to existing controllers, reporting removed or changed ones
+func Update(update bool) Option {
+	return func(g *Generator) {
+		g.Update = update
+	}
+}
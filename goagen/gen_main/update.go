@@ -0,0 +1,190 @@
+package genmain
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// ControllerUpdate describes the outcome of updating an existing controller file with
+// UpdateController.
+type ControllerUpdate struct {
+	// Added lists the names of the action methods whose stubs were appended to the file.
+	Added []string
+	// Removed lists the names of the methods that implement actions no longer present in
+	// the design. These methods are left untouched.
+	Removed []string
+	// Changed lists the names of the action methods whose signature does not match the
+	// design. These methods are left untouched.
+	Changed []string
+}
+
+// UpdateController updates the existing controller file with the given name so that it
+// implements all the actions of resource r. The file is parsed and stubs are appended for
+// the actions that do not have a corresponding method, the rest of the file is preserved
+// as is. appPkg is the name of the generated "app" package and imp its import path.
+func UpdateController(filename, appPkg, imp string, r *design.ResourceDefinition) (*ControllerUpdate, error) {
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	resName := codegen.Goify(r.Name, true)
+	ctrlName := resName + "Controller"
+	methods := make(map[string]*ast.FuncDecl)
+	var names []string
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || receiverName(fn) != ctrlName {
+			continue
+		}
+		methods[fn.Name.Name] = fn
+		names = append(names, fn.Name.Name)
+	}
+
+	var (
+		update  ControllerUpdate
+		stubs   bytes.Buffer
+		ws      bool
+		actions = make(map[string]bool)
+		funcs   = funcMap(appPkg, nil)
+	)
+	err = r.IterateActions(func(a *design.ActionDefinition) error {
+		if a.Static != nil {
+			return nil
+		}
+		name := codegen.Goify(a.Name, true)
+		actions[name] = true
+		fn, ok := methods[name]
+		if ok {
+			if !isActionMethod(fn, name+resName+"Context") {
+				update.Changed = append(update.Changed, name)
+			}
+			return nil
+		}
+		update.Added = append(update.Added, name)
+		stubs.WriteString("\n")
+		if a.WebSocket() {
+			ws = true
+			return executeTemplate(&stubs, actionWST, funcs, a)
+		}
+		return executeTemplate(&stubs, actionT, funcs, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		action := strings.TrimSuffix(name, "WSHandler")
+		if actions[action] || !takesContext(methods[name], action+resName+"Context") {
+			continue
+		}
+		update.Removed = append(update.Removed, name)
+	}
+	if len(update.Added) == 0 {
+		return &update, nil
+	}
+
+	// Append the stubs and fix up the imports they need.
+	src = append(src, stubs.Bytes()...)
+	fset = token.NewFileSet()
+	if f, err = parser.ParseFile(fset, filename, src, parser.ParseComments); err != nil {
+		return nil, err
+	}
+	astutil.AddImport(fset, f, imp)
+	if ws {
+		astutil.AddImport(fset, f, "io")
+		astutil.AddImport(fset, f, "golang.org/x/net/websocket")
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, err
+	}
+	if err := codegen.WriteFile(filename, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// Warnings returns the messages describing the methods of the controller file with the
+// given name that could not be reconciled with the design of resource r.
+func (u *ControllerUpdate) Warnings(filename, appPkg string, r *design.ResourceDefinition) []string {
+	var warnings []string
+	for _, name := range u.Removed {
+		warnings = append(warnings, fmt.Sprintf("%s: method %s does not implement any action of resource %q",
+			filename, name, r.Name))
+	}
+	for _, name := range u.Changed {
+		resName := codegen.Goify(r.Name, true)
+		warnings = append(warnings, fmt.Sprintf("%s: signature of method %s does not match action, expected func (c *%sController) %s(ctx *%s.%s%sContext) error",
+			filename, name, resName, name, appPkg, name, resName))
+	}
+	return warnings
+}
+
+// executeTemplate renders the controller template source into w.
+func executeTemplate(w *bytes.Buffer, source string, funcs template.FuncMap, data interface{}) error {
+	tmpl, err := template.New("update").Funcs(codegen.DefaultFuncMap).Funcs(funcs).Parse(source)
+	if err != nil {
+		panic(err) // bug
+	}
+	return tmpl.Execute(w, data)
+}
+
+// receiverName returns the name of the type of the receiver of fn, "" if fn is not a method.
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// takesContext returns true if fn accepts a single pointer to the context type with the
+// given name.
+func takesContext(fn *ast.FuncDecl, ctxName string) bool {
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) > 1 {
+		return false
+	}
+	star, ok := params[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == ctxName
+}
+
+// isActionMethod returns true if fn has the signature of an action method accepting the
+// context type with the given name.
+func isActionMethod(fn *ast.FuncDecl, ctxName string) bool {
+	if !takesContext(fn, ctxName) {
+		return false
+	}
+	results := fn.Type.Results
+	if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+		return false
+	}
+	ident, ok := results.List[0].Type.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
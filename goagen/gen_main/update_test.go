@@ -0,0 +1,123 @@
+package genmain_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_main"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpdateController", func() {
+	var resource *design.ResourceDefinition
+	var existing string
+	var filename string
+
+	var update *genmain.ControllerUpdate
+	var content string
+	var updateErr error
+
+	BeforeEach(func() {
+		resource = &design.ResourceDefinition{
+			Name:    "first",
+			Actions: map[string]*design.ActionDefinition{},
+		}
+		for _, name := range []string{"alpha", "beta"} {
+			resource.Actions[name] = &design.ActionDefinition{Parent: resource, Name: name}
+		}
+		design.Design = &design.APIDefinition{
+			Name:      "test api",
+			Resources: map[string]*design.ResourceDefinition{"first": resource},
+		}
+		existing = `package main
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/app"
+)
+
+// FirstController implements the first resource.
+type FirstController struct {
+	*goa.Controller
+}
+
+// Alpha runs the alpha action.
+func (c *FirstController) Alpha(ctx *app.AlphaFirstContext) error {
+	// Custom implementation
+	fmt.Println("alpha")
+	return nil
+}
+`
+	})
+
+	JustBeforeEach(func() {
+		dir, err := ioutil.TempDir("", "update")
+		Ω(err).ShouldNot(HaveOccurred())
+		filename = filepath.Join(dir, "first.go")
+		Ω(ioutil.WriteFile(filename, []byte(existing), 0644)).Should(Succeed())
+		update, updateErr = genmain.UpdateController(filename, "app", "github.com/goadesign/goa/app", resource)
+		c, err := ioutil.ReadFile(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		content = string(c)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(filename))
+	})
+
+	It("appends stubs for the missing actions", func() {
+		Ω(updateErr).ShouldNot(HaveOccurred())
+		Ω(update.Added).Should(Equal([]string{"Beta"}))
+		Ω(update.Removed).Should(BeEmpty())
+		Ω(update.Changed).Should(BeEmpty())
+		Ω(content).Should(ContainSubstring("func (c *FirstController) Beta(ctx *app.BetaFirstContext) error {"))
+		Ω(content).Should(ContainSubstring("// FirstController_Beta: start_implement"))
+	})
+
+	It("preserves the existing code", func() {
+		Ω(updateErr).ShouldNot(HaveOccurred())
+		Ω(content).Should(ContainSubstring(`"fmt"`))
+		Ω(content).Should(MatchRegexp(`// Custom implementation\s*fmt.Println\("alpha"\)\s*return nil`))
+	})
+
+	Context("with a method implementing a removed action", func() {
+		BeforeEach(func() {
+			existing += `
+func (c *FirstController) Omega(ctx *app.OmegaFirstContext) error {
+	return nil
+}
+
+func (c *FirstController) helper(ctx *app.AlphaFirstContext) {}
+`
+		})
+
+		It("reports the method without removing it", func() {
+			Ω(updateErr).ShouldNot(HaveOccurred())
+			Ω(update.Removed).Should(Equal([]string{"Omega"}))
+			Ω(content).Should(ContainSubstring("func (c *FirstController) Omega("))
+			warnings := update.Warnings("first.go", "app", resource)
+			Ω(warnings).Should(ConsistOf(`first.go: method Omega does not implement any action of resource "first"`))
+		})
+	})
+
+	Context("with a method whose signature changed", func() {
+		BeforeEach(func() {
+			existing += `
+func (c *FirstController) Beta(ctx *app.BetaFirstContext) {
+	fmt.Println("beta")
+}
+`
+		})
+
+		It("reports the method without rewriting it", func() {
+			Ω(updateErr).ShouldNot(HaveOccurred())
+			Ω(update.Added).Should(BeEmpty())
+			Ω(update.Changed).Should(Equal([]string{"Beta"}))
+			Ω(content).Should(Equal(existing))
+		})
+	})
+})
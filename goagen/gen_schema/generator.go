@@ -3,7 +3,6 @@ package genschema
 import (
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -69,11 +68,11 @@ func (g *Generator) Generate() (_ []string, err error) {
 	}
 
 	g.OutDir = filepath.Join(g.OutDir, "schema")
-	os.RemoveAll(g.OutDir)
+	codegen.CleanDir(g.OutDir)
 	os.MkdirAll(g.OutDir, 0755)
 	g.genfiles = append(g.genfiles, g.OutDir)
 	schemaFile := filepath.Join(g.OutDir, "schema.json")
-	if err = ioutil.WriteFile(schemaFile, js, 0644); err != nil {
+	if err = codegen.WriteFile(schemaFile, js); err != nil {
 		return
 	}
 	g.genfiles = append(g.genfiles, schemaFile)
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/goadesign/goa/design"
 )
@@ -30,6 +31,7 @@ type (
 		// Hyper schema
 		Media     *JSONMedia  `json:"media,omitempty"`
 		ReadOnly  bool        `json:"readOnly,omitempty"`
+		WriteOnly bool        `json:"writeOnly,omitempty"`
 		PathStart string      `json:"pathStart,omitempty"`
 		Links     []*JSONLink `json:"links,omitempty"`
 		Ref       string      `json:"$ref,omitempty"`
@@ -49,6 +51,7 @@ type (
 
 		// Union
 		AnyOf []*JSONSchema `json:"anyOf,omitempty"`
+		OneOf []*JSONSchema `json:"oneOf,omitempty"`
 	}
 
 	// JSONType is the JSON type enum.
@@ -292,6 +295,10 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 			s.Format = "uuid"
 		case design.DateTimeKind:
 			s.Format = "date-time"
+		case design.DurationKind:
+			s.Format = "duration"
+		case design.BytesKind:
+			s.Format = "byte"
 		case design.NumberKind:
 			s.Format = "double"
 		case design.IntegerKind:
@@ -339,6 +346,7 @@ func (s *JSONSchema) createMergeItems(other *JSONSchema) mergeItems {
 		{&s.Title, other.Title, s.Title == ""},
 		{&s.Media, other.Media, s.Media == nil},
 		{&s.ReadOnly, other.ReadOnly, s.ReadOnly == false},
+		{&s.WriteOnly, other.WriteOnly, s.WriteOnly == false},
 		{&s.PathStart, other.PathStart, s.PathStart == ""},
 		{&s.Enum, other.Enum, s.Enum == nil},
 		{&s.Format, other.Format, s.Format == ""},
@@ -415,6 +423,7 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		Title:                s.Title,
 		Media:                s.Media,
 		ReadOnly:             s.ReadOnly,
+		WriteOnly:            s.WriteOnly,
 		PathStart:            s.PathStart,
 		Links:                s.Links,
 		Ref:                  s.Ref,
@@ -459,12 +468,21 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	s.Description = at.Description
 	s.Example = at.GenerateExample(api.RandomGenerator(), nil)
 	s.ReadOnly = at.IsReadOnly()
+	s.WriteOnly = at.IsWriteOnly()
+	if t, format, ok := TimeFormatSchema(at); ok {
+		s.Type = t
+		s.Format = format
+		s.DefaultValue = TimeFormatValue(at, s.DefaultValue)
+		s.Example = TimeFormatValue(at, s.Example)
+	}
 	val := at.Validation
 	if val == nil {
 		return s
 	}
 	s.Enum = val.Values
-	s.Format = val.Format
+	if val.Format != "" {
+		s.Format = val.Format
+	}
 	s.Pattern = val.Pattern
 	if val.Minimum != nil {
 		s.Minimum = val.Minimum
@@ -492,6 +510,59 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	return s
 }
 
+// TimeFormatSchema returns the JSON type and format of the values of the given DateTime or
+// Duration attribute as encoded according to its "format:time" metadata. ok is false if the
+// attribute is neither a DateTime nor a Duration.
+func TimeFormatSchema(at *design.AttributeDefinition) (t JSONType, format string, ok bool) {
+	switch at.TimeFormat() {
+	case design.TimeFormatRFC3339:
+		return JSONString, "date-time", true
+	case design.TimeFormatUnix:
+		return JSONInteger, "unix-time", true
+	case design.TimeFormatUnixMilli:
+		return JSONInteger, "unix-time-ms", true
+	case design.DurationFormatString:
+		return JSONString, "duration", true
+	case design.DurationFormatMilli:
+		return JSONInteger, "duration-ms", true
+	}
+	return "", "", false
+}
+
+// TimeFormatValue converts the given design value of a DateTime or Duration attribute, either an
+// RFC3339 or Go duration string or a time.Time, to its encoding according to the attribute
+// "format:time" metadata. The value is returned as is if it cannot be converted.
+func TimeFormatValue(at *design.AttributeDefinition, val interface{}) interface{} {
+	format := at.TimeFormat()
+	switch format {
+	case design.TimeFormatUnix, design.TimeFormatUnixMilli:
+		t, ok := val.(time.Time)
+		if s, isString := val.(string); isString {
+			var err error
+			t, err = time.Parse(time.RFC3339, s)
+			ok = err == nil
+		}
+		if !ok {
+			return val
+		}
+		if format == design.TimeFormatUnix {
+			return t.Unix()
+		}
+		return t.UnixNano() / int64(time.Millisecond)
+	case design.DurationFormatMilli:
+		s, ok := val.(string)
+		if !ok {
+			return val
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return val
+		}
+		return int64(d / time.Millisecond)
+	}
+	return val
+}
+
 // toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
 func toStringMap(val interface{}) interface{} {
 	switch actual := val.(type) {
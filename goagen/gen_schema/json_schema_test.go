@@ -46,6 +46,38 @@ var _ = Describe("TypeSchema", func() {
 		})
 	})
 
+	Context("with time and duration attributes", func() {
+		BeforeEach(func() {
+			Type("event", func() {
+				Attribute("at", design.DateTime)
+				Attribute("at_ms", design.DateTime, func() {
+					Metadata("format:time", "unix-ms")
+					Default("2016-05-01T10:30:00Z")
+				})
+				Attribute("timeout", design.Duration)
+				Attribute("interval", design.Duration, func() {
+					Metadata("format:time", "ms")
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["event"].Type
+		})
+
+		It("describes the encoded values", func() {
+			Ω(s.Properties["at"].Type).Should(Equal(genschema.JSONType(genschema.JSONString)))
+			Ω(s.Properties["at"].Format).Should(Equal("date-time"))
+			Ω(s.Properties["at_ms"].Type).Should(Equal(genschema.JSONType(genschema.JSONInteger)))
+			Ω(s.Properties["at_ms"].Format).Should(Equal("unix-time-ms"))
+			Ω(s.Properties["at_ms"].DefaultValue).Should(Equal(int64(1462098600000)))
+			Ω(s.Properties["at_ms"].Example).Should(BeAssignableToTypeOf(int64(0)))
+			Ω(s.Properties["timeout"].Type).Should(Equal(genschema.JSONType(genschema.JSONString)))
+			Ω(s.Properties["timeout"].Format).Should(Equal("duration"))
+			Ω(s.Properties["interval"].Type).Should(Equal(genschema.JSONType(genschema.JSONInteger)))
+			Ω(s.Properties["interval"].Format).Should(Equal("duration-ms"))
+		})
+	})
+
 	Context("with a media type with self-referencing attributes", func() {
 		BeforeEach(func() {
 			MediaType("application/vnd.menu+json", func() {
@@ -0,0 +1,203 @@
+package genswagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa/design"
+)
+
+// curlEnabled returns true if the operations describing the action should include a curl example
+// request, that is if the action or the API sets the "swagger:curl" metadata to true.
+func curlEnabled(api *design.APIDefinition, action *design.ActionDefinition) bool {
+	if enabled, ok := action.Metadata.BoolValue("swagger:curl"); ok {
+		return enabled
+	}
+	enabled, _ := api.Metadata.BoolValue("swagger:curl")
+	return enabled
+}
+
+// applyCurlExample appends a markdown code block containing a curl command that makes an example
+// request to the given route to the operation description. The operation is left untouched if the
+// request cannot be built from the design examples.
+func applyCurlExample(operation *Operation, api *design.APIDefinition, route *design.RouteDefinition) {
+	action := route.Parent
+	if !curlEnabled(api, action) {
+		return
+	}
+	cmd := curlCommand(action, route)
+	if cmd == "" {
+		return
+	}
+	if operation.Description != "" {
+		operation.Description += "\n\n"
+	}
+	operation.Description += fmt.Sprintf("Example request:\n\n```sh\n%s\n```", cmd)
+}
+
+// curlCommand returns a curl command making an example request to the given route of the action.
+// The command uses the URL of the first server exposing the resource, the example values of the
+// path and required query string parameters and headers and the example payload encoded with the
+// first content type consumed by the action that the command knows how to encode. Credentials are
+// replaced with placeholders. curlCommand returns an empty string for websocket actions and if the
+// example request cannot be built.
+func curlCommand(action *design.ActionDefinition, route *design.RouteDefinition) string {
+	if action.WebSocket() {
+		return ""
+	}
+	ex := action.RequestExample(route)
+	if ex == nil {
+		return ""
+	}
+
+	var opts []string
+	headers := make(map[string]string, len(ex.Headers))
+	for n := range ex.Headers {
+		headers[n] = ex.Headers.Get(n)
+	}
+	query := ex.Query.Encode()
+	if sec := action.Security; sec != nil {
+		switch scheme := sec.Scheme; scheme.Kind {
+		case design.BasicAuthSecurityKind:
+			opts = append(opts, "-u "+shellQuote("<username>:<password>"))
+		case design.OAuth2SecurityKind:
+			headers["Authorization"] = "Bearer <token>"
+		case design.APIKeySecurityKind, design.JWTSecurityKind:
+			placeholder := "<api-key>"
+			if scheme.Kind == design.JWTSecurityKind {
+				placeholder = "<token>"
+				if strings.EqualFold(scheme.Name, "Authorization") {
+					placeholder = "Bearer <token>"
+				}
+			}
+			if scheme.In == "query" {
+				if query != "" {
+					query += "&"
+				}
+				query += url.QueryEscape(scheme.Name) + "=" + placeholder
+			} else {
+				headers[scheme.Name] = placeholder
+			}
+		}
+	}
+
+	if action.Payload != nil {
+		body, contentType, ok := curlBody(action, ex.Payload)
+		if !ok {
+			return ""
+		}
+		if contentType != "" {
+			headers["Content-Type"] = contentType
+		}
+		opts = append(opts, body...)
+	}
+
+	names := make([]string, 0, len(headers))
+	for n := range headers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	hopts := make([]string, len(names))
+	for i, n := range names {
+		hopts[i] = "-H " + shellQuote(n+": "+headers[n])
+	}
+	opts = append(hopts, opts...)
+
+	base := action.Parent.ExampleServerURL()
+	if base == "" {
+		base = "http://localhost"
+	}
+	u := base + ex.Path
+	if query != "" {
+		u += "?" + query
+	}
+	cmd := "curl"
+	if route.Verb != "GET" {
+		cmd += " -X " + route.Verb
+	}
+	cmd += " " + shellQuote(u)
+	for _, o := range opts {
+		cmd += " \\\n  " + o
+	}
+	return cmd
+}
+
+// curlBody returns the curl options that send the example payload and the request content type.
+// Multipart payloads are sent as form fields and binary payloads are read from a file. Other
+// payloads are encoded using the first JSON or form content type consumed by the action. The
+// last return value is false if the action consumes no such content type.
+func curlBody(action *design.ActionDefinition, payload interface{}) ([]string, string, bool) {
+	if action.PayloadMultipart {
+		obj, ok := toStringMap(payload).(map[string]interface{})
+		if !ok {
+			return nil, "", false
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		opts := make([]string, len(keys))
+		for i, k := range keys {
+			v, err := curlValue(obj[k])
+			if err != nil {
+				return nil, "", false
+			}
+			opts[i] = "-F " + shellQuote(k+"="+v)
+		}
+		// curl sets the multipart content type and boundary.
+		return opts, "", true
+	}
+	if action.BinaryPayload() {
+		return []string{"--data-binary @" + shellQuote("<file>")}, action.BinaryMIMETypes()[0], true
+	}
+	for _, ct := range action.Consumes {
+		mediaType := strings.Split(ct, ";")[0]
+		switch {
+		case mediaType == "application/x-www-form-urlencoded":
+			obj, ok := toStringMap(payload).(map[string]interface{})
+			if !ok {
+				return nil, "", false
+			}
+			vals := url.Values{}
+			for k, e := range obj {
+				v, err := curlValue(e)
+				if err != nil {
+					return nil, "", false
+				}
+				vals.Set(k, v)
+			}
+			return []string{"--data " + shellQuote(vals.Encode())}, ct, true
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			b, err := json.Marshal(toStringMap(payload))
+			if err != nil {
+				return nil, "", false
+			}
+			return []string{"--data " + shellQuote(string(b))}, ct, true
+		}
+	}
+	return nil, "", false
+}
+
+// curlValue returns the string representation of a form field example value: primitive values
+// are formatted as is and other values are encoded to JSON.
+func curlValue(v interface{}) (string, error) {
+	switch actual := v.(type) {
+	case string, bool, int, float64:
+		return fmt.Sprintf("%v", actual), nil
+	case time.Time:
+		return actual.Format(time.RFC3339), nil
+	default:
+		b, err := json.Marshal(actual)
+		return string(b), err
+	}
+}
+
+// shellQuote returns s quoted with single quotes so that it is passed as is to the command.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -12,6 +11,7 @@ import (
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_schema"
 	"github.com/goadesign/goa/goagen/utils"
 )
 
@@ -81,40 +81,75 @@ func (g *Generator) Generate() (_ []string, err error) {
 	}
 
 	swaggerDir := filepath.Join(g.OutDir, "swagger")
-	os.RemoveAll(swaggerDir)
+	codegen.CleanDir(swaggerDir)
 	if err = os.MkdirAll(swaggerDir, 0755); err != nil {
 		return nil, err
 	}
 	g.genfiles = append(g.genfiles, swaggerDir)
+	specs := []*Swagger{s}
+	names := []string{"swagger"}
+
+	// One spec per audience, reset the schema definitions so that each spec only defines the
+	// types used by the actions it documents. The specs are built sequentially as they share
+	// the schema definitions and written concurrently.
+	for _, audience := range g.API.Audiences() {
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+		s, err := NewForAudience(g.API, audience)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, s)
+		names = append(names, "swagger_"+audience)
+	}
+	files := make([][]string, len(specs))
+	err = codegen.Parallel(codegen.Jobs, len(specs), func(i int) error {
+		var err error
+		files[i], err = writeSpec(specs[i], swaggerDir, names[i])
+		return err
+	})
+	for _, f := range files {
+		g.genfiles = append(g.genfiles, f...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// writeSpec writes the JSON and YAML representations of the given spec to the files with the
+// given base name in dir. It returns the paths to the files written.
+func writeSpec(s *Swagger, dir, name string) ([]string, error) {
+	var files []string
 
 	// JSON
 	rawJSON, err := json.Marshal(s)
 	if err != nil {
-		return nil, err
+		return files, err
 	}
-	swaggerFile := filepath.Join(swaggerDir, "swagger.json")
-	if err := ioutil.WriteFile(swaggerFile, rawJSON, 0644); err != nil {
-		return nil, err
+	swaggerFile := filepath.Join(dir, name+".json")
+	if err := codegen.WriteFile(swaggerFile, rawJSON); err != nil {
+		return files, err
 	}
-	g.genfiles = append(g.genfiles, swaggerFile)
+	files = append(files, swaggerFile)
 
 	// YAML
 	var yamlSource interface{}
 	if err = json.Unmarshal(rawJSON, &yamlSource); err != nil {
-		return nil, err
+		return files, err
 	}
 
 	rawYAML, err := yaml.Marshal(yamlSource)
 	if err != nil {
-		return nil, err
+		return files, err
 	}
-	swaggerFile = filepath.Join(swaggerDir, "swagger.yaml")
-	if err := ioutil.WriteFile(swaggerFile, rawYAML, 0644); err != nil {
-		return nil, err
+	swaggerFile = filepath.Join(dir, name+".yaml")
+	if err := codegen.WriteFile(swaggerFile, rawYAML); err != nil {
+		return files, err
 	}
-	g.genfiles = append(g.genfiles, swaggerFile)
+	files = append(files, swaggerFile)
 
-	return g.genfiles, nil
+	return files, nil
 }
 
 // Cleanup removes all the files generated by this generator during the last invokation of Generate.
@@ -30,6 +30,9 @@ type (
 		SecurityDefinitions map[string]*SecurityDefinition   `json:"securityDefinitions,omitempty"`
 		Tags                []*Tag                           `json:"tags,omitempty"`
 		ExternalDocs        *ExternalDocs                    `json:"externalDocs,omitempty"`
+		// Webhooks describes the requests sent by the API to URLs registered by clients
+		// indexed by webhook name, see the Webhook DSL.
+		Webhooks map[string]*Path `json:"x-webhooks,omitempty"`
 	}
 
 	// Info provides metadata about the API. The metadata can be used by the clients if needed,
@@ -165,6 +168,8 @@ type (
 		Schema *genschema.JSONSchema `json:"schema,omitempty"`
 		// Headers is a list of headers that are sent with the response.
 		Headers map[string]*Header `json:"headers,omitempty"`
+		// Examples lists the response examples indexed by MIME type.
+		Examples map[string]interface{} `json:"examples,omitempty"`
 		// Ref references a global API response.
 		// This field is exclusive with the other fields of Response.
 		Ref string `json:"$ref,omitempty"`
@@ -360,6 +365,14 @@ func (t Tag) MarshalJSON() ([]byte, error) {
 
 // New creates a Swagger spec from an API definition.
 func New(api *design.APIDefinition) (*Swagger, error) {
+	return NewForAudience(api, "")
+}
+
+// NewForAudience creates a Swagger spec that documents the API actions intended for the given
+// audience, see the Audience DSL. All actions are documented if audience is empty. The
+// definitions include the types referenced by the documented actions only when the genschema
+// definitions are reset prior to calling NewForAudience.
+func NewForAudience(api *design.APIDefinition, audience string) (*Swagger, error) {
 	if api == nil {
 		return nil, nil
 	}
@@ -424,31 +437,47 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = api.IterateResources(func(res *design.ResourceDefinition) error {
-		for k, v := range extensionsFromDefinition(res.Metadata) {
-			s.Paths[k] = v
-		}
-		err := res.IterateFileServers(func(fs *design.FileServerDefinition) error {
-			if !mustGenerate(fs.Metadata) {
-				return nil
+	err = design.Walk(api, design.WalkFunc(func(def dslengine.Definition, _ string) error {
+		switch d := def.(type) {
+		case *design.ResourceDefinition:
+			for k, v := range extensionsFromDefinition(d.Metadata) {
+				s.Paths[k] = v
 			}
-			return buildPathFromFileServer(s, api, fs)
-		})
-		if err != nil {
-			return err
-		}
-		return res.IterateActions(func(a *design.ActionDefinition) error {
-			if !mustGenerate(a.Metadata) {
+			for _, m := range d.Mounts {
+				addMountTag(s, m)
+			}
+		case *design.FileServerDefinition:
+			if !mustGenerate(d.AllMetadata()) || !inAudience(d.Parent.Audiences(), audience) {
 				return nil
 			}
-			for _, route := range a.Routes {
-				if err := buildPathFromDefinition(s, api, route, basePath); err != nil {
+			return buildPathFromFileServer(s, api, d)
+		case *design.ActionDefinition:
+			if d.AsyncOperation != "" || !mustGenerate(d.AllMetadata()) || !inAudience(d.Audiences(), audience) {
+				return design.SkipChildren
+			}
+			if d.Webhook {
+				if err := buildWebhookFromDefinition(s, api, d); err != nil {
 					return err
 				}
+				return design.SkipChildren
 			}
-			return nil
-		})
-	})
+			for _, m := range d.Parent.Mounts {
+				for _, ro := range d.Routes {
+					if ro.IsAbsolute() {
+						continue
+					}
+					if err := buildPathFromDefinition(s, api, ro, basePath, m); err != nil {
+						return err
+					}
+				}
+			}
+		case *design.RouteDefinition:
+			return buildPathFromDefinition(s, api, d, basePath, nil)
+		case *design.AttributeDefinition, *design.ResponseDefinition:
+			return design.SkipChildren
+		}
+		return nil
+	}))
 	if err != nil {
 		return nil, err
 	}
@@ -467,29 +496,58 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 // mustGenerate returns true if the metadata indicates that a Swagger specification should be
 // generated, false otherwise.
 func mustGenerate(meta dslengine.MetadataDefinition) bool {
-	if m, ok := meta["swagger:generate"]; ok {
-		if len(m) > 0 && m[0] == "false" {
+	if generate, ok := meta.BoolValue("swagger:generate"); ok {
+		return generate
+	}
+	return true
+}
+
+// equalStrings returns true if a and b contain the same strings in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if b[i] != v {
 			return false
 		}
 	}
 	return true
 }
 
+// inAudience returns true if a definition with the given audiences must be documented in the
+// specification of the given audience.
+func inAudience(audiences []string, audience string) bool {
+	if audience == "" || len(audiences) == 0 {
+		return true
+	}
+	for _, a := range audiences {
+		if a == audience {
+			return true
+		}
+	}
+	return false
+}
+
 // hasAbsoluteRoutes returns true if any action exposed by the API uses an absolute route of if the
-// API has file servers. This is needed as Swagger does not support exceptions to the base path so
-// if the API has any absolute route the base path must be "/" and all routes must be absolutes.
+// API has file servers or mounted resources. This is needed as Swagger does not support exceptions
+// to the base path so if the API has any absolute route the base path must be "/" and all routes
+// must be absolutes.
 func hasAbsoluteRoutes(api *design.APIDefinition) bool {
 	hasAbsoluteRoutes := false
 	for _, res := range api.Resources {
+		if len(res.Mounts) > 0 {
+			return true
+		}
 		for _, fs := range res.FileServers {
-			if !mustGenerate(fs.Metadata) {
+			if !mustGenerate(fs.AllMetadata()) {
 				continue
 			}
 			hasAbsoluteRoutes = true
 			break
 		}
 		for _, a := range res.Actions {
-			if !mustGenerate(a.Metadata) {
+			if !mustGenerate(a.AllMetadata()) {
 				continue
 			}
 			for _, ro := range a.Routes {
@@ -557,12 +615,7 @@ func scopesMapList(scopes map[string]string) string {
 }
 
 func tagsFromDefinition(mdata dslengine.MetadataDefinition) (tags []*Tag) {
-	var keys []string
-	for k := range mdata {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	for _, key := range keys {
+	for _, key := range mdata.Keys() {
 		chunks := strings.Split(key, ":")
 		if len(chunks) != 3 {
 			continue
@@ -616,17 +669,16 @@ func tagNamesFromDefinitions(mdatas ...dslengine.MetadataDefinition) (tagNames [
 }
 
 func summaryFromDefinition(name string, metadata dslengine.MetadataDefinition) string {
-	for n, mdata := range metadata {
-		if n == "swagger:summary" && len(mdata) > 0 {
-			return mdata[0]
-		}
+	if summary, ok := metadata.Last("swagger:summary"); ok {
+		return summary
 	}
 	return name
 }
 
 func extensionsFromDefinition(mdata dslengine.MetadataDefinition) map[string]interface{} {
 	extensions := make(map[string]interface{})
-	for key, value := range mdata {
+	for _, key := range mdata.Keys() {
+		value := mdata[key]
 		chunks := strings.Split(key, ":")
 		if len(chunks) != 3 {
 			continue
@@ -684,6 +736,9 @@ func paramsFromHeaders(action *design.ActionDefinition) []*Parameter {
 	params := []*Parameter{}
 	action.IterateHeaders(func(name string, required bool, header *design.AttributeDefinition) error {
 		p := paramFor(header, name, "header", required)
+		if header.Type.IsArray() {
+			p.CollectionFormat = "csv" // array headers are comma separated
+		}
 		params = append(params, p)
 		return nil
 	})
@@ -738,6 +793,12 @@ func toStringMap(val interface{}) interface{} {
 			m[toString(k)] = toStringMap(v)
 		}
 		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, v := range actual {
+			m[k] = toStringMap(v)
+		}
+		return m
 	case []interface{}:
 		mapSlice := make([]interface{}, len(actual))
 		for i, e := range actual {
@@ -786,14 +847,30 @@ func responseSpecFromDefinition(s *Swagger, api *design.APIDefinition, r *design
 			schema.Ref = genschema.MediaTypeRef(api, mt, view)
 		}
 	}
+	if schema == nil && r.Type != nil {
+		if r.Binary() {
+			schema = binarySchema()
+		} else {
+			schema = genschema.TypeSchema(api, r.Type)
+		}
+	}
 	headers, err := headersFromDefinition(r.Headers)
 	if err != nil {
 		return nil, err
 	}
+	var examples map[string]interface{}
+	if ex := r.BodyExample(); ex != nil && !r.Binary() {
+		mime := r.BodyContentType()
+		if mime == "" {
+			mime = "application/json"
+		}
+		examples = map[string]interface{}{mime: ex}
+	}
 	return &Response{
 		Description: r.Description,
 		Schema:      schema,
 		Headers:     headers,
+		Examples:    examples,
 		Extensions:  extensionsFromDefinition(r.Metadata),
 	}, nil
 }
@@ -822,6 +899,38 @@ func responseFromDefinition(s *Swagger, api *design.APIDefinition, r *design.Res
 	return response, nil
 }
 
+// viewsSchema returns the schema of the responses rendered with the view selected by the client:
+// one of the schemas of the given views of the media type.
+func viewsSchema(api *design.APIDefinition, mt *design.MediaTypeDefinition, views []string) *genschema.JSONSchema {
+	schema := genschema.NewJSONSchema()
+	for _, view := range views {
+		ref := genschema.NewJSONSchema()
+		ref.Ref = genschema.MediaTypeRef(api, mt, view)
+		schema.OneOf = append(schema.OneOf, ref)
+	}
+	return schema
+}
+
+// addResponseHeaders documents the constant headers sent with all the responses of the resource.
+// The headers defined by the responses themselves take precedence.
+func addResponseHeaders(responses map[string]*Response, r *design.ResourceDefinition) error {
+	headers, err := headersFromDefinition(r.AllResponseHeaders())
+	if err != nil {
+		return err
+	}
+	for _, resp := range responses {
+		for n, h := range headers {
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]*Header, len(headers))
+			}
+			if _, ok := resp.Headers[n]; !ok {
+				resp.Headers[n] = h
+			}
+		}
+	}
+	return nil
+}
+
 func headersFromDefinition(headers *design.AttributeDefinition) (map[string]*Header, error) {
 	if headers == nil {
 		return nil, nil
@@ -867,9 +976,15 @@ func buildPathFromFileServer(s *Swagger, api *design.APIDefinition, fs *design.F
 		schema := genschema.TypeSchema(api, design.ErrorMedia)
 		responses["404"] = &Response{Description: "File not found", Schema: schema}
 	}
+	if err := addResponseHeaders(responses, fs.Parent); err != nil {
+		return err
+	}
 
 	operationID := fmt.Sprintf("%s#%s", fs.Parent.Name, fs.RequestPath)
-	schemes := api.Schemes
+	schemes := fs.Parent.ServerSchemes()
+	if len(schemes) == 0 {
+		schemes = api.Schemes
+	}
 
 	operation := &Operation{
 		Description:  fs.Description,
@@ -883,15 +998,7 @@ func buildPathFromFileServer(s *Swagger, api *design.APIDefinition, fs *design.F
 
 	applySecurity(operation, fs.Security)
 
-	key := design.WildcardRegex.ReplaceAllStringFunc(
-		fs.RequestPath,
-		func(w string) string {
-			return fmt.Sprintf("/{%s}", w[2:])
-		},
-	)
-	if key == "" {
-		key = "/"
-	}
+	key := design.OpenAPIPath(fs.RequestPath)
 	var path interface{}
 	var ok bool
 	if path, ok = s.Paths[key]; !ok {
@@ -905,15 +1012,35 @@ func buildPathFromFileServer(s *Swagger, api *design.APIDefinition, fs *design.F
 	return nil
 }
 
-func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *design.RouteDefinition, basePath string) error {
+// addMountTag adds the tag used to group the operations of the mount to the spec tags unless the API
+// metadata already defines it.
+func addMountTag(s *Swagger, m *design.MountDefinition) {
+	for _, t := range s.Tags {
+		if t.Name == m.Name {
+			return
+		}
+	}
+	s.Tags = append(s.Tags, &Tag{Name: m.Name, Description: m.Description})
+}
+
+// buildPathFromDefinition adds the operation corresponding to the route to the spec. The operation
+// describes the route served through the given mount if not nil: it is tagged with the mount name
+// and uses the mount path and security.
+func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *design.RouteDefinition, basePath string, mount *design.MountDefinition) error {
 	action := route.Parent
+	fullRoute := route
+	if mount != nil {
+		fullRoute = mount.Route(route)
+	}
 
 	tagNames := tagNamesFromDefinitions(action.Parent.Metadata, action.Metadata)
-	if len(tagNames) == 0 {
+	if mount != nil {
+		tagNames = []string{mount.Name}
+	} else if len(tagNames) == 0 {
 		// By default tag with resource name
 		tagNames = []string{route.Parent.Parent.Name}
 	}
-	params, err := paramsFromDefinition(action.AllParams(), route.FullPath())
+	params, err := paramsFromDefinition(action.AllParams(), fullRoute.FullPath())
 	if err != nil {
 		return err
 	}
@@ -928,9 +1055,34 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		}
 		responses[strconv.Itoa(r.Status)] = resp
 	}
+	if err := addResponseHeaders(responses, action.Parent); err != nil {
+		return err
+	}
+	if resp, mt := action.ViewedResponse(); resp != nil && action.ViewParam != "" {
+		if r, ok := responses[strconv.Itoa(resp.Status)]; ok {
+			r.Schema = viewsSchema(api, mt, action.ViewNames())
+		}
+	}
 
+	// The payload of websocket actions is described by the x-websocket extension.
+	ws := action.WebSocket()
 	consumesMultipart := false
-	if action.Payload != nil {
+	var consumes []string
+	if len(action.Payloads) > 0 && !ws {
+		payloadSchema := genschema.NewJSONSchema()
+		for _, p := range action.Payloads {
+			payloadSchema.AnyOf = append(payloadSchema.AnyOf, genschema.TypeSchema(api, p.Attribute.Type))
+			consumes = append(consumes, p.ContentType)
+		}
+		pp := &Parameter{
+			Name:        "payload",
+			In:          "body",
+			Description: action.Payload.Description,
+			Required:    true,
+			Schema:      payloadSchema,
+		}
+		params = append(params, pp)
+	} else if action.Payload != nil && !ws {
 		if action.PayloadMultipart {
 			p, err := paramsFromPayload(action.Payload)
 			if err != nil {
@@ -940,6 +1092,10 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 			consumesMultipart = true
 		} else {
 			payloadSchema := genschema.TypeSchema(api, action.Payload)
+			if action.BinaryPayload() {
+				payloadSchema = binarySchema()
+				consumes = action.BinaryMIMETypes()
+			}
 			pp := &Parameter{
 				Name:        "payload",
 				In:          "body",
@@ -959,11 +1115,17 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 			break
 		}
 	}
+	if mount != nil {
+		operationID = fmt.Sprintf("%s#%s", operationID, mount.Name)
+	}
 	if index > 0 {
 		operationID = fmt.Sprintf("%s#%d", operationID, index)
 	}
 
 	schemes := action.Schemes
+	if len(schemes) == 0 {
+		schemes = action.Parent.ServerSchemes()
+	}
 	if len(schemes) == 0 {
 		schemes = api.Schemes
 	}
@@ -980,27 +1142,73 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		Deprecated:   false,
 		Extensions:   extensionsFromDefinition(route.Metadata),
 	}
+	if mount != nil {
+		for k, v := range extensionsFromDefinition(mount.Metadata) {
+			if operation.Extensions == nil {
+				operation.Extensions = make(map[string]interface{})
+			}
+			operation.Extensions[k] = v
+		}
+	}
 
 	if consumesMultipart {
 		operation.Consumes = append(operation.Consumes, "multipart/form-data")
 	}
+	if len(consumes) == 0 && !consumesMultipart && !equalStrings(action.Consumes, s.Consumes) {
+		consumes = action.Consumes
+	}
+	operation.Consumes = append(operation.Consumes, consumes...)
 
 	computeProduces(operation, s, action)
-	applySecurity(operation, action.Security)
+	if since := action.Since(); since != "" {
+		if operation.Description != "" {
+			operation.Description += "\n\n"
+		}
+		operation.Description += fmt.Sprintf("Available since version %s.", since)
+	}
+	security := action.Security
+	if mount != nil {
+		security = mount.ActionSecurity(action)
+	}
+	applySecurity(operation, security)
+	applyCurlExample(operation, api, fullRoute)
 
-	computePaths(operation, s, route, basePath)
+	verb := route.Verb
+	if ws {
+		// Websocket connections are always established with a GET request.
+		verb = "GET"
+	}
+	applyStreaming(operation, api, action)
+	applyLinks(operation, action)
+
+	computePaths(operation, s, fullRoute, verb, basePath)
 	return nil
 }
 
+// binarySchema returns the schema of the request and response bodies read and written as is.
+func binarySchema() *genschema.JSONSchema {
+	s := genschema.NewJSONSchema()
+	s.Type = genschema.JSONString
+	s.Format = "binary"
+	return s
+}
+
 func computeProduces(operation *Operation, s *Swagger, action *design.ActionDefinition) {
 	produces := make(map[string]struct{})
 	action.IterateResponses(func(resp *design.ResponseDefinition) error {
-		if resp.MediaType != "" {
-			produces[resp.MediaType] = struct{}{}
+		if ct := resp.BodyContentType(); ct != "" {
+			produces[ct] = struct{}{}
 		}
 		return nil
 	})
+	// Actions that override the API produced MIME types list them explicitly.
 	subset := true
+	if len(action.Produces) > 0 && !equalStrings(action.Produces, s.Produces) {
+		for _, p := range action.Produces {
+			produces[p] = struct{}{}
+		}
+		subset = false
+	}
 	for p := range produces {
 		found := false
 		for _, p2 := range s.Produces {
@@ -1025,19 +1233,106 @@ func computeProduces(operation *Operation, s *Swagger, action *design.ActionDefi
 	}
 }
 
-func computePaths(operation *Operation, s *Swagger, route *design.RouteDefinition, basePath string) {
-	key := design.WildcardRegex.ReplaceAllStringFunc(
-		route.FullPath(),
-		func(w string) string {
-			return fmt.Sprintf("/{%s}", w[2:])
-		},
-	)
-	bp := design.WildcardRegex.ReplaceAllStringFunc(
-		basePath,
-		func(w string) string {
-			return fmt.Sprintf("/{%s}", w[2:])
-		},
-	)
+// applyLinks describes the action links in the x-links extension of the operation success
+// responses using the OpenAPI 3 link object format. The parameters of the target action route are
+// read from the response body properties with the same names.
+func applyLinks(operation *Operation, action *design.ActionDefinition) {
+	if len(action.Links) == 0 {
+		return
+	}
+	links := make(map[string]interface{}, len(action.Links))
+	for _, l := range action.Links {
+		t := l.Target()
+		if t == nil {
+			continue
+		}
+		link := map[string]interface{}{"operationId": fmt.Sprintf("%s#%s", t.Parent.Name, t.Name)}
+		if len(t.Routes) > 0 {
+			if params := t.Routes[0].Params(); len(params) > 0 {
+				lp := make(map[string]string, len(params))
+				for _, p := range params {
+					lp[p] = "$response.body#/" + p
+				}
+				link["parameters"] = lp
+			}
+		}
+		links[l.Rel] = link
+	}
+	for code, resp := range operation.Responses {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if resp.Extensions == nil {
+			resp.Extensions = make(map[string]interface{})
+		}
+		resp.Extensions["x-links"] = links
+	}
+}
+
+// applyStreaming describes the results streamed by the action. Websocket actions list the schemas
+// of the messages exchanged on the connection in the x-websocket extension. The other actions
+// describe each streamed result with the schema of the 200 response.
+func applyStreaming(operation *Operation, api *design.APIDefinition, action *design.ActionDefinition) {
+	transport := action.StreamTransport()
+	if transport == "" || (transport != design.WebSocketStream && action.StreamingResult == nil) {
+		return
+	}
+	if transport == design.WebSocketStream {
+		ext := make(map[string]interface{})
+		if action.Payload != nil {
+			ext["payload"] = genschema.TypeSchema(api, action.Payload)
+		}
+		if action.StreamingResult != nil {
+			ext["message"] = frameSchema(api, action)
+		}
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		operation.Extensions["x-websocket"] = ext
+		if _, ok := operation.Responses["101"]; !ok {
+			operation.Responses["101"] = &Response{Description: "Switching Protocols"}
+		}
+		return
+	}
+	resp, ok := operation.Responses["200"]
+	if !ok {
+		resp = &Response{Description: "OK"}
+		operation.Responses["200"] = resp
+	}
+	if transport == design.SSEStream {
+		// Each event carries a single streamed result.
+		operation.Produces = []string{design.SSEMIMEType}
+		resp.Schema = genschema.TypeSchema(api, action.StreamingResult)
+		return
+	}
+	operation.Produces = []string{design.NDJSONMIMEType}
+	resp.Schema = frameSchema(api, action)
+}
+
+// frameSchema returns the schema of the goa.StreamFrame envelope sent by websocket and
+// application/x-ndjson streams. The frame data is one of the streamed results or the final result
+// when terminal is true.
+func frameSchema(api *design.APIDefinition, action *design.ActionDefinition) *genschema.JSONSchema {
+	s := genschema.NewJSONSchema()
+	s.Type = genschema.JSONObject
+	terminal := genschema.NewJSONSchema()
+	terminal.Type = genschema.JSONBoolean
+	s.Properties["terminal"] = terminal
+	data := genschema.TypeSchema(api, action.StreamingResult)
+	if action.Result != nil {
+		data = genschema.NewJSONSchema()
+		data.AnyOf = []*genschema.JSONSchema{
+			genschema.TypeSchema(api, action.StreamingResult),
+			genschema.TypeSchema(api, action.Result),
+		}
+	}
+	s.Properties["data"] = data
+	return s
+}
+
+func computePaths(operation *Operation, s *Swagger, route *design.RouteDefinition, verb, basePath string) {
+	key := route.OpenAPIPath()
+	bp := design.OpenAPIPath(basePath)
 	if bp != "/" {
 		key = strings.TrimPrefix(key, bp)
 	}
@@ -1051,7 +1346,12 @@ func computePaths(operation *Operation, s *Swagger, route *design.RouteDefinitio
 		s.Paths[key] = path
 	}
 	p := path.(*Path)
-	switch route.Verb {
+	setOperation(p, verb, operation)
+	p.Extensions = extensionsFromDefinition(route.Parent.Metadata)
+}
+
+func setOperation(p *Path, verb string, operation *Operation) {
+	switch verb {
 	case "GET":
 		p.Get = operation
 	case "PUT":
@@ -1067,7 +1367,66 @@ func computePaths(operation *Operation, s *Swagger, route *design.RouteDefinitio
 	case "PATCH":
 		p.Patch = operation
 	}
-	p.Extensions = extensionsFromDefinition(route.Parent.Metadata)
+}
+
+// buildWebhookFromDefinition describes the request sent by the given webhook. The operation path
+// is relative to the URL registered by the client.
+func buildWebhookFromDefinition(s *Swagger, api *design.APIDefinition, w *design.ActionDefinition) error {
+	if len(w.Routes) == 0 {
+		return nil
+	}
+	route := w.Routes[0]
+	params := paramsFromHeaders(w)
+	if w.Signature != nil {
+		params = append(params, &Parameter{
+			Name:        w.Signature.Header,
+			In:          "header",
+			Description: fmt.Sprintf("%s signature of the request body", w.Signature.Algorithm),
+			Required:    true,
+			Type:        "string",
+		})
+	}
+	if w.Payload != nil {
+		params = append(params, &Parameter{
+			Name:        "payload",
+			In:          "body",
+			Description: w.Payload.Description,
+			Required:    !w.PayloadOptional,
+			Schema:      genschema.TypeSchema(api, w.Payload),
+		})
+	}
+	responses := make(map[string]*Response, len(w.Responses))
+	for _, r := range w.Responses {
+		resp, err := responseFromDefinition(s, api, r)
+		if err != nil {
+			return err
+		}
+		responses[strconv.Itoa(r.Status)] = resp
+	}
+	tagNames := tagNamesFromDefinitions(w.Parent.Metadata, w.Metadata)
+	if len(tagNames) == 0 {
+		tagNames = []string{w.Parent.Name}
+	}
+	operation := &Operation{
+		Tags:         tagNames,
+		Description:  w.Description,
+		Summary:      summaryFromDefinition(w.Name+" "+w.Parent.Name, w.Metadata),
+		ExternalDocs: docsFromDefinition(w.Docs),
+		OperationID:  fmt.Sprintf("%s#webhook:%s", w.Parent.Name, w.Name),
+		Parameters:   params,
+		Responses:    responses,
+		Extensions:   extensionsFromDefinition(route.Metadata),
+	}
+	if !equalStrings(w.Consumes, s.Consumes) {
+		operation.Consumes = w.Consumes
+	}
+	if s.Webhooks == nil {
+		s.Webhooks = make(map[string]*Path)
+	}
+	p := &Path{Extensions: extensionsFromDefinition(w.Metadata)}
+	setOperation(p, route.Verb, operation)
+	s.Webhooks[w.Name] = p
+	return nil
 }
 
 func applySecurity(operation *Operation, security *design.SecurityDefinition) {
@@ -1078,6 +1437,12 @@ func applySecurity(operation *Operation, security *design.SecurityDefinition) {
 			}
 			operation.Description += fmt.Sprintf("Required security scopes:\n%s", scopesList(security.Scopes))
 		}
+		if security.ScopeExpr != nil {
+			if operation.Description != "" {
+				operation.Description += "\n\n"
+			}
+			operation.Description += fmt.Sprintf("Required security scopes expression: `%s`", security.ScopeExpr)
+		}
 		scopes := security.Scopes
 		if scopes == nil {
 			scopes = make([]string, 0)
@@ -1198,13 +1563,33 @@ func initMaxLengthValidation(def interface{}, isArray bool, max *int) {
 	}
 }
 
+func initTimeFormat(attr *design.AttributeDefinition, def interface{}) {
+	t, format, ok := genschema.TimeFormatSchema(attr)
+	if !ok {
+		return
+	}
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.Type, actual.Format = string(t), format
+		actual.Default = genschema.TimeFormatValue(attr, actual.Default)
+	case *Header:
+		actual.Type, actual.Format = string(t), format
+		actual.Default = genschema.TimeFormatValue(attr, actual.Default)
+	case *Items:
+		actual.Type, actual.Format = string(t), format
+	}
+}
+
 func initValidations(attr *design.AttributeDefinition, def interface{}) {
+	initTimeFormat(attr, def)
 	val := attr.Validation
 	if val == nil {
 		return
 	}
 	initEnumValidation(def, val.Values)
-	initFormatValidation(def, val.Format)
+	if val.Format != "" {
+		initFormatValidation(def, val.Format)
+	}
 	initPatternValidation(def, val.Pattern)
 	if val.Minimum != nil {
 		initMinimumValidation(def, val.Minimum)
@@ -3,6 +3,8 @@ package genswagger_test
 import (
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/go-openapi/loads"
 	_ "github.com/goadesign/goa-cellar/design"
@@ -330,6 +332,43 @@ var _ = Describe("New", func() {
 			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
 		})
 
+		Context("with payloads per content type", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							POST("/"),
+						)
+						ContentTypePayload("application/json", func() {
+							Member("id", Integer)
+						})
+						ContentTypePayload("text/plain", String)
+					})
+				})
+			})
+
+			It("adds an Action level consumes listing the content types", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				a := swagger.Paths["/"].(*genswagger.Path)
+				Ω(a.Post).ShouldNot(BeNil())
+				Ω(a.Post.Consumes).Should(Equal([]string{"application/json", "text/plain"}))
+			})
+
+			It("lists the schemas of all the payloads", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				a := swagger.Paths["/"].(*genswagger.Path)
+				ps := a.Post.Parameters
+				Ω(ps).Should(HaveLen(1))
+				Ω(ps[0].In).Should(Equal("body"))
+				Ω(ps[0].Required).Should(BeTrue())
+				Ω(ps[0].Schema.AnyOf).Should(HaveLen(2))
+				Ω(ps[0].Schema.AnyOf[0].Properties).Should(HaveKey("id"))
+				Ω(ps[0].Schema.AnyOf[1].Type).Should(Equal(genschema.JSONType(genschema.JSONString)))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
 		Context("with recursive payload", func() {
 			BeforeEach(func() {
 				p := Type("RecursivePayload", func() {
@@ -695,7 +734,7 @@ var _ = Describe("New", func() {
 				Ω(ps).Should(HaveLen(14))
 				// check Headers in detail
 				Ω(ps[3]).Should(Equal(&genswagger.Parameter{In: "header", Name: "Authorization", Type: "string", Required: true}))
-				Ω(ps[4]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OptionalArray", Type: "array", CollectionFormat: "multi",
+				Ω(ps[4]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OptionalArray", Type: "array", CollectionFormat: "csv",
 					Items: &genswagger.Items{Type: "string"}, MinItems: &minItems1, MaxItems: &maxItems5}))
 				Ω(ps[5]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OptionalBoolWithDefault", Type: "boolean",
 					Description: "defaults true", Default: true}))
@@ -705,7 +744,7 @@ var _ = Describe("New", func() {
 				Ω(ps[8]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OptionalResourceHeaderWithEnum", Type: "string",
 					Enum: []interface{}{"a", "b"}}))
 				Ω(ps[9]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OverrideOptionalHeader", Type: "string", Required: true}))
-				Ω(ps[10]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OverrideRequiredHeader", Type: "string", Required: true}))
+				Ω(ps[10]).Should(Equal(&genswagger.Parameter{In: "header", Name: "OverrideRequiredHeader", Type: "string"}))
 				Ω(ps[11]).Should(Equal(&genswagger.Parameter{In: "header", Name: "X-Account", Type: "integer", Required: true}))
 				Ω(ps[12]).Should(Equal(&genswagger.Parameter{In: "header", Name: "header", Type: "string", Required: true}))
 				Ω(swagger.Paths["/base/bottles/{id}"]).ShouldNot(BeNil())
@@ -824,4 +863,500 @@ var _ = Describe("New", func() {
 
 		})
 	})
+
+	Context("with a webhook", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			Resource("order", func() {
+				Action("show", func() {
+					Routing(GET("/orders/:id"))
+				})
+				Webhook("order.shipped", func() {
+					Description("order shipped")
+					Routing(POST("/"))
+					Payload(func() {
+						Member("id", String)
+					})
+					Signature(func() {
+						Header("X-Signature")
+						Algorithm("hmac-sha256")
+					})
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("describes the webhook request under x-webhooks", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(swagger.Paths).Should(HaveLen(1))
+			Ω(swagger.Webhooks).Should(HaveKey("order.shipped"))
+			op := swagger.Webhooks["order.shipped"].Post
+			Ω(op).ShouldNot(BeNil())
+			Ω(op.OperationID).Should(Equal("order#webhook:order.shipped"))
+			Ω(op.Description).Should(Equal("order shipped"))
+			Ω(op.Parameters).Should(HaveLen(2))
+			Ω(op.Parameters[0].Name).Should(Equal("X-Signature"))
+			Ω(op.Parameters[0].In).Should(Equal("header"))
+			Ω(op.Parameters[1].In).Should(Equal("body"))
+			Ω(op.Responses).Should(HaveKey("204"))
+		})
+	})
+
+	Context("with constant response headers", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				ResponseHeaders(func() {
+					Header("X-Content-Type-Options", String, func() {
+						Default("nosniff")
+					})
+				})
+			})
+			Resource("bottle", func() {
+				ResponseHeaders(func() {
+					Header("Cache-Control", String, func() {
+						Default("no-store")
+					})
+				})
+				Files("/public/*filepath", "public/")
+				Action("show", func() {
+					Routing(GET("/bottles/:id"))
+					Response(OK, func() {
+						Headers(func() {
+							Header("Cache-Control", String, func() {
+								Default("max-age=60")
+							})
+						})
+					})
+					Response(NotFound)
+				})
+			})
+		})
+
+		It("documents the headers in all the responses", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			op := swagger.Paths["/bottles/{id}"].(*genswagger.Path).Get
+			Ω(op.Responses["200"].Headers).Should(HaveKey("X-Content-Type-Options"))
+			Ω(op.Responses["200"].Headers["Cache-Control"].Default).Should(Equal("max-age=60"))
+			Ω(op.Responses["404"].Headers["X-Content-Type-Options"].Default).Should(Equal("nosniff"))
+			Ω(op.Responses["404"].Headers["Cache-Control"].Default).Should(Equal("no-store"))
+			fs := swagger.Paths["/public/{filepath}"].(*genswagger.Path).Get
+			Ω(fs.Responses["200"].Headers).Should(HaveKey("X-Content-Type-Options"))
+			Ω(fs.Responses["404"].Headers).Should(HaveKey("Cache-Control"))
+		})
+	})
+
+	Context("with a trailing slash policy", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				TrailingSlash(TrailingSlashRedirect)
+			})
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Action("list", func() {
+					Routing(GET("/"))
+				})
+			})
+		})
+
+		It("documents the normalized paths", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(swagger.Paths).Should(HaveKey("/bottles"))
+			Ω(swagger.Paths).ShouldNot(HaveKey("/bottles/"))
+		})
+	})
+
+	Context("with API response examples", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				ResponseExample(404, func() {
+					Example(map[string]interface{}{"code": "not_found"})
+				})
+			})
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/bottles/:id"))
+					Response(NotFound, ErrorMedia)
+				})
+			})
+		})
+
+		It("documents the examples of the action responses", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			op := swagger.Paths["/bottles/{id}"].(*genswagger.Path).Get
+			Ω(op.Responses["404"].Examples).Should(Equal(map[string]interface{}{
+				"application/vnd.goa.error": map[string]interface{}{"code": "not_found"},
+			}))
+		})
+	})
+
+	Context("with actions streaming results", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			filter := Type("Filter", func() {
+				Attribute("status", String)
+			})
+			progress := Type("Progress", func() {
+				Attribute("done", Integer)
+			})
+			summary := Type("Summary", func() {
+				Attribute("imported", Integer)
+			})
+			Resource("task", func() {
+				Action("watch", func() {
+					Routing(GET("/watch"))
+					Scheme("ws")
+					Payload(filter)
+					StreamingResult(progress)
+				})
+				Action("import", func() {
+					Routing(POST("/import"))
+					Produces("application/x-ndjson")
+					StreamingResult(progress)
+					Result(summary)
+				})
+				Action("events", func() {
+					Routing(GET("/events"))
+					Produces("text/event-stream")
+					StreamingResult(progress)
+				})
+			})
+		})
+
+		It("describes the streamed results", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			golden, err := ioutil.ReadFile(filepath.Join("testdata", "streaming.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := json.Marshal(swagger.Paths)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(b).Should(MatchJSON(golden))
+			Ω(swagger.Definitions).Should(HaveLen(3))
+			Ω(swagger.Definitions).Should(HaveKey("Filter"))
+			Ω(swagger.Definitions).Should(HaveKey("Progress"))
+			Ω(swagger.Definitions).Should(HaveKey("Summary"))
+			validateSwagger(swagger)
+		})
+	})
+
+	Context("with curl examples", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				BasePath("/v1")
+				Server("https://api.example.com")
+				Metadata("swagger:curl", "true")
+			})
+			jwt := JWTSecurity("jwt", func() {
+				Header("Authorization")
+			})
+			key := APIKeySecurity("key", func() {
+				Query("api_key")
+			})
+			basic := BasicAuthSecurity("basic")
+			bottle := Type("BottlePayload", func() {
+				Attribute("name", String, func() { Example("Chateau") })
+				Attribute("vintage", Integer, func() { Example(2010) })
+			})
+			id := func() {
+				Param("id", Integer, func() { Example(42) })
+			}
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Security(jwt)
+				Action("show", func() {
+					Description("Show a bottle")
+					Routing(GET("/:id"))
+					Params(id)
+					Response(NoContent)
+				})
+				Action("list", func() {
+					Routing(GET(""))
+					Security(key)
+					Params(func() {
+						Param("color", ArrayOf(String), func() { Example([]string{"red", "white"}) })
+						Param("limit", Integer)
+						Required("color")
+					})
+					Headers(func() {
+						Header("X-Request-Id", String, func() { Example("abc") })
+						Required("X-Request-Id")
+					})
+					Response(NoContent)
+				})
+				Action("update", func() {
+					Routing(PUT("/:id"))
+					Security(basic)
+					Params(id)
+					Payload(bottle)
+					Response(NoContent)
+				})
+				Action("upload", func() {
+					Routing(POST("/:id/label"))
+					NoSecurity()
+					Params(id)
+					Consumes("application/octet-stream")
+					Payload(Bytes)
+					Response(NoContent)
+				})
+				Action("rate", func() {
+					Description("Rate a bottle")
+					Metadata("swagger:curl", "false")
+					Routing(POST("/:id/rate"))
+					Params(id)
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("appends curl commands to the operation descriptions", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			descs := make(map[string]string)
+			for _, p := range swagger.Paths {
+				path := p.(*genswagger.Path)
+				for _, op := range []*genswagger.Operation{path.Get, path.Put, path.Post} {
+					if op != nil {
+						descs[op.OperationID] = op.Description
+					}
+				}
+			}
+			golden, err := ioutil.ReadFile(filepath.Join("testdata", "curl.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := json.Marshal(descs)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(b).Should(MatchJSON(golden))
+			validateSwagger(swagger)
+		})
+	})
+
+	Context("with a required scopes expression", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			jwt := JWTSecurity("jwt", func() {
+				Header("Authorization")
+				Scope("admin")
+				Scope("editor")
+				Scope("org:member")
+			})
+			Resource("bottle", func() {
+				Action("delete", func() {
+					Description("Delete a bottle")
+					Routing(DELETE("/bottles/:id"))
+					Security(jwt, func() {
+						RequiredScopes(AnyScope("admin", AllScopes("editor", "org:member")))
+					})
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("renders the expression in the operation description", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			op := swagger.Paths["/bottles/{id}"].(*genswagger.Path).Delete
+			Ω(op.Description).Should(Equal("Delete a bottle\n\nRequired security scopes expression: `admin OR (editor AND org:member)`"))
+		})
+	})
+
+	Context("with an action introduced in a later version", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Version("1.3")
+			})
+			Resource("bottle", func() {
+				Action("export", func() {
+					Description("Export the bottles")
+					Routing(GET("/export"))
+					Since("1.2")
+					Response(OK)
+				})
+			})
+		})
+
+		It("mentions the version in the operation description", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			op := swagger.Paths["/export"].(*genswagger.Path).Get
+			Ω(op.Description).Should(Equal("Export the bottles\n\nAvailable since version 1.2."))
+		})
+	})
+
+	Context("with a mounted resource", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				BasePath("/api/v1")
+				APIKeySecurity("partner_key", func() {
+					Query("key")
+				})
+			})
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Mount("partner", func() {
+					Description("Partner access")
+					BasePath("/partner/v1")
+					Security("partner_key")
+					Metadata("swagger:extension:x-partner", "true")
+				})
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response(OK)
+				})
+			})
+		})
+
+		It("describes the operations of each mount", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(swagger.BasePath).Should(BeEmpty())
+			def := swagger.Paths["/api/v1/bottles/{id}"].(*genswagger.Path).Get
+			Ω(def.OperationID).Should(Equal("bottle#show"))
+			Ω(def.Tags).Should(Equal([]string{"bottle"}))
+			Ω(def.Security).Should(BeNil())
+			op := swagger.Paths["/partner/v1/bottles/{id}"].(*genswagger.Path).Get
+			Ω(op.OperationID).Should(Equal("bottle#show#partner"))
+			Ω(op.Tags).Should(Equal([]string{"partner"}))
+			Ω(op.Security).Should(Equal([]map[string][]string{{"partner_key": {}}}))
+			Ω(op.Extensions).Should(HaveKeyWithValue("x-partner", true))
+			Ω(op.Parameters).Should(HaveLen(1))
+			Ω(op.Parameters[0].Name).Should(Equal("id"))
+			Ω(swagger.Tags).Should(ContainElement(&genswagger.Tag{Name: "partner", Description: "Partner access"}))
+			validateSwagger(swagger)
+		})
+	})
+
+	Context("with action links", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			Resource("account", func() {
+				Action("show", func() {
+					Routing(GET("/accounts/:accountID"))
+					Response(OK)
+				})
+			})
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/bottles/:bottleID"))
+					Link("owner", "account", "show")
+					Response(OK)
+					Response(NotFound)
+				})
+			})
+		})
+
+		It("describes the links in the success responses", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			op := swagger.Paths["/bottles/{bottleID}"].(*genswagger.Path).Get
+			Ω(op.Responses["200"].Extensions).Should(HaveKeyWithValue("x-links", map[string]interface{}{
+				"owner": map[string]interface{}{
+					"operationId": "account#show",
+					"parameters":  map[string]string{"accountID": "$response.body#/accountID"},
+				},
+			}))
+			Ω(op.Responses["404"].Extensions).ShouldNot(HaveKey("x-links"))
+		})
+	})
+
+	Context("with an action that lets clients select the response view", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			bottle := MediaType("application/vnd.bottle", func() {
+				Attributes(func() {
+					Attribute("id", Integer)
+					Attribute("name", String)
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("name")
+				})
+				View("tiny", func() {
+					Attribute("id")
+				})
+			})
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/bottles/:id"))
+					ViewParam("view")
+					Response(OK, bottle)
+				})
+			})
+		})
+
+		It("enumerates the views and documents the schema of each view", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			p := swagger.Paths["/bottles/{id}"].(*genswagger.Path)
+			Ω(p.Get).ShouldNot(BeNil())
+			var view *genswagger.Parameter
+			for _, param := range p.Get.Parameters {
+				if param.Name == "view" {
+					view = param
+				}
+			}
+			Ω(view).ShouldNot(BeNil())
+			Ω(view.In).Should(Equal("query"))
+			Ω(view.Enum).Should(Equal([]interface{}{"default", "tiny"}))
+			Ω(view.Default).Should(Equal("default"))
+			schema := p.Get.Responses["200"].Schema
+			Ω(schema.OneOf).Should(HaveLen(2))
+			Ω(schema.OneOf[0].Ref).Should(Equal("#/definitions/Bottle"))
+			Ω(schema.OneOf[1].Ref).Should(Equal("#/definitions/BottleTiny"))
+			validateSwagger(swagger)
+		})
+	})
+})
+
+var _ = Describe("NewForAudience", func() {
+	var audience string
+	var swagger *genswagger.Swagger
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+		API("test", func() {
+			Audience("public", "internal")
+		})
+		public := Type("PublicPayload", func() {
+			Member("name", String)
+		})
+		internal := Type("InternalPayload", func() {
+			Member("secret", String)
+		})
+		Resource("res", func() {
+			Action("show", func() {
+				Routing(PUT("/public"))
+				Payload(public)
+			})
+			Action("audit", func() {
+				Audience("internal")
+				Routing(PUT("/internal"))
+				Payload(internal)
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		var newErr error
+		swagger, newErr = genswagger.NewForAudience(Design, audience)
+		Ω(newErr).ShouldNot(HaveOccurred())
+	})
+
+	Context("with the public audience", func() {
+		BeforeEach(func() {
+			audience = "public"
+		})
+
+		It("excludes the actions and types of other audiences", func() {
+			Ω(swagger.Paths).Should(HaveKey("/public"))
+			Ω(swagger.Paths).ShouldNot(HaveKey("/internal"))
+			Ω(swagger.Definitions).Should(HaveKey("PublicPayload"))
+			Ω(swagger.Definitions).ShouldNot(HaveKey("InternalPayload"))
+		})
+	})
+
+	Context("with the internal audience", func() {
+		BeforeEach(func() {
+			audience = "internal"
+		})
+
+		It("includes the actions with no audience", func() {
+			Ω(swagger.Paths).Should(HaveKey("/public"))
+			Ω(swagger.Paths).Should(HaveKey("/internal"))
+			Ω(swagger.Definitions).Should(HaveKey("InternalPayload"))
+		})
+	})
 })
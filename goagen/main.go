@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
@@ -41,13 +42,21 @@ controllers supporting code and main skeleton code (if not already present) as w
 package and tool and the Swagger specification for the API.
 `}
 	var (
-		designPkg string
-		debug     bool
+		designPkg   string
+		debug       bool
+		incremental bool
+		resources   string
+		jobs        int
+		strict      bool
 	)
 
 	rootCmd.PersistentFlags().StringP("out", "o", ".", "output directory")
 	rootCmd.PersistentFlags().StringVarP(&designPkg, "design", "d", "", "design package import path")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode, does not cleanup temporary files.")
+	rootCmd.PersistentFlags().BoolVar(&incremental, "incremental", false, "do not write unchanged files, delete stale generated files and print a summary of the changes")
+	rootCmd.PersistentFlags().IntVarP(&jobs, "jobs", "j", runtime.NumCPU(), "maximum number of files generated concurrently")
+	rootCmd.PersistentFlags().BoolVar(&strict, "strict", false, "report design warnings as errors")
+	rootCmd.PersistentFlags().StringVar(&resources, "resources", "", "comma separated list of the `names` of the resources to generate code for, generate all if not specified")
 
 	// versionCmd implements the "version" command
 	versionCmd := &cobra.Command{
@@ -75,7 +84,7 @@ package and tool and the Swagger specification for the API.
 
 	// mainCmd implements the "main" command.
 	var (
-		force, regen bool
+		force, regen, update bool
 	)
 	mainCmd := &cobra.Command{
 		Use:   "main",
@@ -84,6 +93,7 @@ package and tool and the Swagger specification for the API.
 	}
 	mainCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
 	mainCmd.Flags().BoolVar(&regen, "regen", false, "regenerate scaffolding, maintaining controller implementations")
+	mainCmd.Flags().BoolVar(&update, "update", false, "add missing actions to existing controllers, report removed or changed ones")
 	rootCmd.AddCommand(mainCmd)
 
 	// clientCmd implements the "client" command.
@@ -110,6 +120,14 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(swaggerCmd)
 
+	// asyncapiCmd implements the "asyncapi" command.
+	asyncapiCmd := &cobra.Command{
+		Use:   "asyncapi",
+		Short: "Generate AsyncAPI document describing the publish and subscribe actions",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genasyncapi", c) },
+	}
+	rootCmd.AddCommand(asyncapiCmd)
+
 	// jsCmd implements the "js" command.
 	var (
 		timeout      = time.Duration(20) * time.Second
@@ -135,6 +153,40 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(schemaCmd)
 
+	// describeCmd implements the "describe" command.
+	describeCmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Generate JSON description of the design for external tools",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gendescribe", c) },
+	}
+	rootCmd.AddCommand(describeCmd)
+
+	// auditCmd implements the "audit" command.
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Report where the properties of the design attributes come from",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genaudit", c) },
+	}
+	rootCmd.AddCommand(auditCmd)
+
+	// deployCmd implements the "deploy" command.
+	deployCmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Generate Dockerfiles and Kubernetes manifests for the resources that set deploy:image",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gendeploy", c) },
+	}
+	rootCmd.AddCommand(deployCmd)
+
+	// diffCmd implements the "diff" command.
+	var old string
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Report changes between the design and a previous description, fail on breaking changes",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gendiff", c) },
+	}
+	diffCmd.Flags().StringVar(&old, "old", "", `path to the "design.json" file produced by the describe command for the previous design`)
+	rootCmd.AddCommand(diffCmd)
+
 	// genCmd implements the "gen" command.
 	var (
 		pkgPath string
@@ -194,6 +246,7 @@ package and tool and the Swagger specification for the API.
 	}
 	controllerCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
 	controllerCmd.Flags().BoolVar(&regen, "regen", false, "regenerate scaffolding, maintaining controller implementations")
+	controllerCmd.Flags().BoolVar(&update, "update", false, "add missing actions to existing controllers, report removed or changed ones")
 	controllerCmd.Flags().StringVar(&res, "res", "", "name of the `resource` to generate the controller for, generate all if not specified")
 	controllerCmd.Flags().StringVar(&pkg, "pkg", "main", "name of the generated controller `package`")
 	controllerCmd.Flags().StringVar(&appPkg, "app-pkg", "app", "`import path` of Go package generated with 'goagen app', may be relative to output")
@@ -281,6 +334,9 @@ func generate(pkgName, pkgPath string, c *cobra.Command, args []string) ([]strin
 	if _, ok := m["out"]; !ok {
 		m["out"] = c.Flag("out").DefValue
 	}
+	if _, ok := m["jobs"]; !ok {
+		m["jobs"] = c.Flag("jobs").DefValue
+	}
 	// turn "out" into an absolute path
 	var err error
 	m["out"], err = filepath.Abs(m["out"])
@@ -297,7 +353,14 @@ func generate(pkgName, pkgPath string, c *cobra.Command, args []string) ([]strin
 	if err != nil {
 		return nil, err
 	}
-	return gen.Generate()
+	files, err := gen.Generate()
+	if err != nil {
+		return nil, err
+	}
+	if gen.Summary != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", pkgName, gen.Summary)
+	}
+	return files, nil
 }
 
 type (
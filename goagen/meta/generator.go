@@ -1,6 +1,7 @@
 package meta
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -43,6 +44,25 @@ type Generator struct {
 	// DesignPkgPath is the Go import path to the design package.
 	DesignPkgPath string
 
+	// Incremental causes the generator to leave the generated files whose content did not
+	// change untouched and to record a summary of the run in Summary.
+	Incremental bool
+
+	// Resources lists the names of the resources to generate code for. The design is pruned
+	// prior to running the generator so that it only contains these resources and their
+	// parents. All resources are kept if empty.
+	Resources []string
+
+	// Summary lists the files written, skipped and deleted by the last incremental run.
+	Summary *codegen.GenerationSummary
+
+	// Jobs is the maximum number of files generated concurrently by the final generator.
+	// Generation is sequential if Jobs is lower than 2.
+	Jobs int
+
+	// Strict causes design warnings to be reported as errors.
+	Strict bool
+
 	debug bool
 }
 
@@ -51,7 +71,10 @@ type Generator struct {
 func NewGenerator(genfunc string, imports []*codegen.ImportSpec, flags map[string]string, customflags []string) (*Generator, error) {
 	var (
 		outDir, designPkgPath string
-		debug                 bool
+		debug, incremental    bool
+		strict                bool
+		resources             []string
+		jobs                  int
 	)
 
 	if o, ok := flags["out"]; ok {
@@ -67,6 +90,40 @@ func NewGenerator(genfunc string, imports []*codegen.ImportSpec, flags map[strin
 			return nil, fmt.Errorf("failed to parse debug flag: %s", err)
 		}
 	}
+	// The incremental, resources, jobs and strict flags are handled by the meta generator, remove them so
+	// that the final generator does not have to know about them.
+	if i, ok := flags["incremental"]; ok {
+		var err error
+		incremental, err = strconv.ParseBool(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse incremental flag: %s", err)
+		}
+		delete(flags, "incremental")
+	}
+	if r, ok := flags["resources"]; ok {
+		for _, n := range strings.Split(r, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				resources = append(resources, n)
+			}
+		}
+		delete(flags, "resources")
+	}
+	if j, ok := flags["jobs"]; ok {
+		var err error
+		jobs, err = strconv.Atoi(j)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jobs flag: %s", err)
+		}
+		delete(flags, "jobs")
+	}
+	if s, ok := flags["strict"]; ok {
+		var err error
+		strict, err = strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse strict flag: %s", err)
+		}
+		delete(flags, "strict")
+	}
 
 	return &Generator{
 		Genfunc:       genfunc,
@@ -75,6 +132,10 @@ func NewGenerator(genfunc string, imports []*codegen.ImportSpec, flags map[strin
 		CustomFlags:   customflags,
 		OutDir:        outDir,
 		DesignPkgPath: designPkgPath,
+		Incremental:   incremental,
+		Resources:     resources,
+		Jobs:          jobs,
+		Strict:        strict,
 		debug:         debug,
 	}, nil
 }
@@ -143,7 +204,24 @@ func (m *Generator) Generate() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	return m.spawn(genbin)
+	if !m.Incremental {
+		return m.spawn(genbin)
+	}
+
+	// Record the state of the output directory prior to running the generator so that the
+	// files it did not write can be reported.
+	fps, err := codegen.TakeFingerprints(m.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := m.spawn(genbin)
+	if err != nil {
+		return nil, err
+	}
+	if m.Summary, err = fps.Apply(files); err != nil {
+		return nil, err
+	}
+	return files, nil
 }
 
 func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
@@ -154,10 +232,19 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 	defer file.Close()
 	imports := append(m.Imports,
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("os"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("github.com/goadesign/goa/dslengine"),
+		codegen.SimpleImport("github.com/goadesign/goa/goagen/codegen"),
 		codegen.NewImport("_", filepath.ToSlash(m.DesignPkgPath)),
 	)
+	if len(m.Resources) > 0 {
+		imports = append(imports, codegen.SimpleImport("github.com/goadesign/goa/design"))
+	}
+	var jobs string
+	if m.Jobs > 1 {
+		jobs = strconv.Itoa(m.Jobs)
+	}
 	file.WriteHeader("Code Generator", "main", imports)
 	tmpl, err := template.New("generator").Parse(mainTmpl)
 	if err != nil {
@@ -167,10 +254,27 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 	if err != nil {
 		panic(err)
 	}
+	resources := make([]string, len(m.Resources))
+	for i, r := range m.Resources {
+		resources[i] = strconv.Quote(r)
+	}
+	var strict string
+	if m.Strict {
+		strict = "true"
+	}
+	var incremental string
+	if m.Incremental {
+		incremental = "true"
+	}
 	context := map[string]string{
 		"Genfunc":       m.Genfunc,
+		"Generator":     strings.TrimSuffix(m.Genfunc, ".Generate"),
 		"DesignPackage": m.DesignPkgPath,
 		"PkgName":       pkgName,
+		"Resources":     strings.Join(resources, ", "),
+		"Jobs":          jobs,
+		"Strict":        strict,
+		"Incremental":   incremental,
 	}
 	if err := tmpl.Execute(file, context); err != nil {
 		panic(err) // bug
@@ -191,11 +295,15 @@ func (m *Generator) spawn(genbin string) ([]string, error) {
 	args = append(args, "--version="+version.String())
 	args = append(args, m.CustomFlags...)
 	cmd := exec.Command(genbin, args...)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("%s\n%s", err, string(out))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s\n%s%s", err, stdout.String(), stderr.String())
 	}
-	res := strings.Split(string(out), "\n")
+	// The generator prints the generated filenames on stdout and warnings on stderr.
+	os.Stderr.Write(stderr.Bytes())
+	res := strings.Split(stdout.String(), "\n")
 	for (len(res) > 0) && (res[len(res)-1] == "") {
 		res = res[:len(res)-1]
 	}
@@ -207,11 +315,34 @@ func main() {
 	// Check if there were errors while running the first DSL pass
 	dslengine.FailOnError(dslengine.Errors)
 
-	// Now run the secondary DSLs
-	dslengine.FailOnError(dslengine.Run())
+{{if .Strict}}	// Report design warnings as errors
+	dslengine.Strict = true
 
+{{end}}	// Now run the secondary DSLs
+	dslengine.FailOnError(dslengine.Run())
+	for _, w := range dslengine.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+{{if .Resources}}
+	// Only keep the requested resources
+	dslengine.FailOnError(design.Design.PruneResources({{.Resources}}))
+{{end}}{{if .Jobs}}
+	// Generate independent files concurrently
+	codegen.Jobs = {{.Jobs}}
+{{end}}{{if .Incremental}}
+	// Do not write the files whose content did not change
+	codegen.SkipUnchanged = true
+{{end}}
 	files, err := {{.Genfunc}}()
 	dslengine.FailOnError(err)
+{{if .Incremental}}
+	// Delete the files left over by previous runs that were not generated again
+	_, err = codegen.RemoveStaleFiles()
+	dslengine.FailOnError(err)
+{{end}}
+	// Post-process the generated files
+	files, err = codegen.ProcessFiles({{printf "%q" .Generator}}, files)
+	dslengine.FailOnError(err)
 
 	// We're done
 	fmt.Println(strings.Join(files, "\n"))
@@ -0,0 +1,140 @@
+package goa
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is the language of the error messages built by goa.
+const DefaultLanguage = "en"
+
+// MessageCatalog contains the translations of the error messages identified by the MessageKey
+// field of ErrorResponse. A translation is a template where "{name}" is replaced with the value
+// of the error metadata with key name and "{detail}" with the original message, for example:
+//
+//	catalog.Register("fr", map[string]string{
+//		"missing_attribute": "l'attribut {attribute} de {parent} est obligatoire",
+//		"invalid_range_min": "{attribute} doit être supérieur ou égal à {expected}",
+//	})
+//
+// See the error constructors of this package for the keys and parameters of the messages
+// produced by the generated validation code.
+type MessageCatalog struct {
+	lock     sync.RWMutex
+	messages map[string]map[string]string
+}
+
+// NewMessageCatalog returns an empty message catalog.
+func NewMessageCatalog() *MessageCatalog {
+	return &MessageCatalog{messages: make(map[string]map[string]string)}
+}
+
+// Register adds the translations of the messages indexed by key for the given language tag, e.g.
+// "fr" or "fr-CA". Translations registered for a tag that already has translations override them.
+func (c *MessageCatalog) Register(lang string, messages map[string]string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	lang = strings.ToLower(lang)
+	if c.messages[lang] == nil {
+		c.messages[lang] = make(map[string]string, len(messages))
+	}
+	for k, m := range messages {
+		c.messages[lang][k] = m
+	}
+}
+
+// Negotiate returns the language tag of the catalog that best matches the given Accept-Language
+// header value. A tag matches a range that is either identical or its primary subtag so that
+// "fr" is used for "fr-CA" if there is no "fr-ca" translation. Negotiate returns DefaultLanguage
+// if no tag matches.
+func (c *MessageCatalog) Negotiate(acceptLanguage string) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, r := range parseAcceptLanguage(acceptLanguage) {
+		if _, ok := c.messages[r]; ok {
+			return r
+		}
+		if i := strings.Index(r, "-"); i > 0 {
+			if _, ok := c.messages[r[:i]]; ok {
+				return r[:i]
+			}
+		}
+	}
+	return DefaultLanguage
+}
+
+// Localize returns a copy of err whose detail is translated in the given language. The
+// translations of DefaultLanguage are used for the messages that are not translated in lang and
+// the original message is kept if there is no such translation either. Each error merged into err
+// is translated separately.
+func (c *MessageCatalog) Localize(err *ErrorResponse, lang string) *ErrorResponse {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	lang = strings.ToLower(lang)
+	localized := *err
+	parts := err.mergedErrors()
+	details := make([]string, len(parts))
+	for i, p := range parts {
+		details[i] = c.translate(p, lang)
+	}
+	localized.Detail = strings.Join(details, "; ")
+	return &localized
+}
+
+// translate returns the translated detail of err.
+func (c *MessageCatalog) translate(err *ErrorResponse, lang string) string {
+	if err.MessageKey == "" {
+		return err.Detail
+	}
+	msg, ok := c.messages[lang][err.MessageKey]
+	if !ok {
+		if msg, ok = c.messages[DefaultLanguage][err.MessageKey]; !ok {
+			return err.Detail
+		}
+	}
+	params := []string{"{detail}", err.Detail}
+	for k, v := range err.Meta {
+		params = append(params, "{"+k+"}", fmt.Sprintf("%v", v))
+	}
+	return strings.NewReplacer(params...).Replace(msg)
+}
+
+// parseAcceptLanguage returns the lowercase language ranges listed in the given Accept-Language
+// header value sorted by decreasing quality. Ranges with a quality of 0 and the wildcard range
+// are omitted.
+func parseAcceptLanguage(header string) []string {
+	type langRange struct {
+		tag string
+		q   float64
+	}
+	var ranges []langRange
+	for _, elem := range strings.Split(header, ",") {
+		parts := strings.Split(elem, ";")
+		tag := strings.ToLower(strings.TrimSpace(parts[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, p := range parts[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if v, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		ranges = append(ranges, langRange{tag: tag, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	tags := make([]string, len(ranges))
+	for i, r := range ranges {
+		tags[i] = r.tag
+	}
+	return tags
+}
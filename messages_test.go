@@ -0,0 +1,170 @@
+package goa_test
+
+import (
+	"encoding/json"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MessageCatalog", func() {
+	var catalog *goa.MessageCatalog
+
+	BeforeEach(func() {
+		catalog = goa.NewMessageCatalog()
+		catalog.Register("fr", map[string]string{
+			"missing_attribute": "l'attribut {attribute} de {parent} est obligatoire",
+			"invalid_range_min": "{attribute} doit être supérieur ou égal à {expected}",
+		})
+		catalog.Register("pt-BR", map[string]string{
+			"missing_attribute": "o atributo {attribute} de {parent} é obrigatório",
+		})
+	})
+
+	Describe("Negotiate", func() {
+		var acceptLanguage string
+		var lang string
+
+		JustBeforeEach(func() {
+			lang = catalog.Negotiate(acceptLanguage)
+		})
+
+		Context("with a translated language", func() {
+			BeforeEach(func() {
+				acceptLanguage = "FR"
+			})
+
+			It("returns the language", func() {
+				Ω(lang).Should(Equal("fr"))
+			})
+		})
+
+		Context("with a region of a translated language", func() {
+			BeforeEach(func() {
+				acceptLanguage = "fr-CH"
+			})
+
+			It("returns the primary language", func() {
+				Ω(lang).Should(Equal("fr"))
+			})
+		})
+
+		Context("with a translated region", func() {
+			BeforeEach(func() {
+				acceptLanguage = "pt-BR"
+			})
+
+			It("returns the region", func() {
+				Ω(lang).Should(Equal("pt-br"))
+			})
+		})
+
+		Context("with quality values", func() {
+			BeforeEach(func() {
+				acceptLanguage = "fr;q=0.3, de, pt-BR;q=0.8"
+			})
+
+			It("returns the translated language with the highest quality", func() {
+				Ω(lang).Should(Equal("pt-br"))
+			})
+		})
+
+		Context("with a language explicitly not accepted", func() {
+			BeforeEach(func() {
+				acceptLanguage = "fr;q=0, *"
+			})
+
+			It("returns the default language", func() {
+				Ω(lang).Should(Equal(goa.DefaultLanguage))
+			})
+		})
+
+		Context("with no header", func() {
+			BeforeEach(func() {
+				acceptLanguage = ""
+			})
+
+			It("returns the default language", func() {
+				Ω(lang).Should(Equal(goa.DefaultLanguage))
+			})
+		})
+	})
+
+	Describe("Localize", func() {
+		var err error
+		var lang string
+		var localized *goa.ErrorResponse
+
+		BeforeEach(func() {
+			lang = "fr"
+		})
+
+		JustBeforeEach(func() {
+			localized = catalog.Localize(err.(*goa.ErrorResponse), lang)
+		})
+
+		Context("with a translated message", func() {
+			BeforeEach(func() {
+				err = goa.MissingAttributeError("raw.origin", "country")
+			})
+
+			It("translates the detail using the error metadata", func() {
+				Ω(localized.Detail).Should(Equal("l'attribut country de raw.origin est obligatoire"))
+			})
+
+			It("does not modify the error", func() {
+				Ω(err.(*goa.ErrorResponse).Detail).ShouldNot(Equal(localized.Detail))
+				Ω(localized.MessageKey).Should(Equal("missing_attribute"))
+			})
+		})
+
+		Context("with a missing translation", func() {
+			BeforeEach(func() {
+				err = goa.InvalidPatternError("raw.name", "x", "^a")
+			})
+
+			It("keeps the original message", func() {
+				Ω(localized.Detail).Should(Equal(err.(*goa.ErrorResponse).Detail))
+			})
+
+			Context("with a default language translation", func() {
+				BeforeEach(func() {
+					catalog.Register(goa.DefaultLanguage, map[string]string{
+						"invalid_pattern": "{attribute} must match {regexp}",
+					})
+				})
+
+				It("uses the default language translation", func() {
+					Ω(localized.Detail).Should(Equal("raw.name must match ^a"))
+				})
+			})
+		})
+
+		Context("with merged errors", func() {
+			BeforeEach(func() {
+				err = goa.MergeErrors(nil, goa.MissingAttributeError("raw", "name"))
+				err = goa.MergeErrors(err, goa.InvalidRangeError("raw.count", 0, 1, true))
+				err = goa.MergeErrors(err, goa.InvalidPatternError("raw.code", "x", "^a"))
+			})
+
+			It("translates each message", func() {
+				Ω(localized.Detail).Should(Equal("l'attribut name de raw est obligatoire; " +
+					"raw.count doit être supérieur ou égal à 1; " +
+					goa.InvalidPatternError("raw.code", "x", "^a").(*goa.ErrorResponse).Detail))
+			})
+
+			It("has no message key", func() {
+				Ω(localized.MessageKey).Should(BeEmpty())
+			})
+		})
+	})
+})
+
+var _ = Describe("ErrorResponse", func() {
+	It("encodes the message key", func() {
+		b, err := json.Marshal(goa.MissingHeaderError("X-Request-Id"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(ContainSubstring(`"message_key":"missing_header"`))
+	})
+})
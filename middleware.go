@@ -78,3 +78,40 @@ func httpHandlerToMiddleware(m http.HandlerFunc) Middleware {
 		}
 	}
 }
+
+// InvalidRequestStatus returns a middleware that sets the response status of the invalid request
+// errors returned by the handler to status. The generated code uses it when the design overrides
+// the status of the responses sent for requests that fail validation.
+func InvalidRequestStatus(status int) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			err := h(ctx, rw, req)
+			if e, ok := err.(*ErrorResponse); ok && e.Code == "invalid_request" {
+				e.Status = status
+			}
+			return err
+		}
+	}
+}
+
+// ReportViolations returns a middleware that lists the violations of the requests that fail the
+// design validations in the "violations" field of the error metadata. Each violation consists of a
+// JSON Pointer to the invalid value of the request body and a detail message, see Violations.
+// The middleware must be mounted after the error handler middleware so that it can process the
+// errors first.
+func ReportViolations() Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			err := h(ctx, rw, req)
+			if e, ok := err.(*ErrorResponse); ok {
+				if violations := Violations(e); len(violations) > 0 {
+					if e.Meta == nil {
+						e.Meta = make(map[string]interface{})
+					}
+					e.Meta["violations"] = violations
+				}
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goadesign/goa"
+)
+
+const (
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	headerContentLength   = "Content-Length"
+	headerVary            = "Vary"
+	headerSecWebSocketKey = "Sec-WebSocket-Key"
+)
+
+type (
+	// EncoderFunc creates a writer that compresses the data written to it into w.
+	EncoderFunc func(w io.Writer) (io.WriteCloser, error)
+
+	// DecoderFunc creates a reader that decompresses the data read from r.
+	DecoderFunc func(r io.Reader) (io.ReadCloser, error)
+
+	// algorithm is a registered compression algorithm.
+	algorithm struct {
+		encoder EncoderFunc
+		decoder DecoderFunc
+	}
+
+	// compressWriter compresses the response body.
+	compressWriter struct {
+		http.ResponseWriter
+		encoding    string
+		encoder     EncoderFunc
+		w           io.WriteCloser
+		wroteHeader bool
+		err         error
+	}
+)
+
+var (
+	algorithmsMu sync.RWMutex
+	algorithms   = map[string]*algorithm{
+		"gzip": {
+			encoder: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+			decoder: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		},
+		"deflate": {
+			encoder: func(w io.Writer) (io.WriteCloser, error) { return flate.NewWriter(w, flate.DefaultCompression) },
+			decoder: func(r io.Reader) (io.ReadCloser, error) { return flate.NewReader(r), nil },
+		},
+	}
+)
+
+// Register registers the encoder and decoder used for the algorithm with the given name,
+// overriding any previous registration.
+func Register(name string, encoder EncoderFunc, decoder DecoderFunc) {
+	algorithmsMu.Lock()
+	defer algorithmsMu.Unlock()
+	algorithms[name] = &algorithm{encoder: encoder, decoder: decoder}
+}
+
+// lookup returns the registered algorithm with the given name or nil.
+func lookup(name string) *algorithm {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	return algorithms[name]
+}
+
+// Middleware compresses response bodies using the first of the given algorithms accepted by the
+// client as indicated by the request Accept-Encoding header. The middleware does not compress
+// responses that already have a Content-Encoding header, responses with no body and WebSocket
+// requests.
+func Middleware(algorithms ...string) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			rw.Header().Add(headerVary, headerAcceptEncoding)
+			encoding := Negotiate(req.Header.Get(headerAcceptEncoding), algorithms...)
+			if encoding == "" || req.Header.Get(headerSecWebSocketKey) != "" {
+				return h(ctx, rw, req)
+			}
+			resp := goa.ContextResponse(ctx)
+			cw := &compressWriter{
+				ResponseWriter: resp.SwitchWriter(nil),
+				encoding:       encoding,
+				encoder:        lookup(encoding).encoder,
+			}
+			resp.SwitchWriter(cw)
+			err := h(ctx, rw, req)
+			if cerr := cw.Close(); err == nil {
+				err = cerr
+			}
+			return err
+		}
+	}
+}
+
+// Decode wraps the given unmarshaler so that request bodies compressed with one of the given
+// algorithms are decompressed before being unmarshaled. Decode returns an error if the request
+// Content-Encoding header is set to any other algorithm.
+func Decode(unm goa.Unmarshaler, algorithms ...string) goa.Unmarshaler {
+	return func(ctx context.Context, service *goa.Service, req *http.Request) error {
+		encoding := strings.ToLower(strings.TrimSpace(req.Header.Get(headerContentEncoding)))
+		if encoding == "" || encoding == "identity" {
+			return unm(ctx, service, req)
+		}
+		var alg *algorithm
+		for _, a := range algorithms {
+			if a == encoding {
+				alg = lookup(a)
+				break
+			}
+		}
+		if alg == nil || alg.decoder == nil {
+			return fmt.Errorf("unsupported content encoding %#v", encoding)
+		}
+		body, err := alg.decoder(req.Body)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		req.Body = body
+		req.Header.Del(headerContentEncoding)
+		req.Header.Del(headerContentLength)
+		return unm(ctx, service, req)
+	}
+}
+
+// Negotiate returns the first of the given algorithms that is registered and accepted by the
+// given Accept-Encoding header value. It returns an empty string if no algorithm is acceptable.
+func Negotiate(acceptEncoding string, algorithms ...string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+	accepted := make(map[string]bool)
+	wildcard := false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		ok := true
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				ok = err == nil && q > 0
+			}
+		}
+		if name == "*" {
+			wildcard = ok
+			continue
+		}
+		accepted[name] = ok
+	}
+	for _, a := range algorithms {
+		ok, listed := accepted[a]
+		if !listed {
+			ok = wildcard
+		}
+		if alg := lookup(a); ok && alg != nil && alg.encoder != nil {
+			return a
+		}
+	}
+	return ""
+}
+
+// WriteHeader sets the Content-Encoding header unless the response has no body or is already
+// encoded.
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	h := cw.Header()
+	if status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified &&
+		h.Get(headerContentEncoding) == "" {
+		if cw.w, cw.err = cw.encoder(cw.ResponseWriter); cw.err == nil {
+			h.Set(headerContentEncoding, cw.encoding)
+			h.Del(headerContentLength)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+// Write compresses the given bytes if the response is compressed.
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	if cw.w == nil {
+		return cw.ResponseWriter.Write(b)
+	}
+	return cw.w.Write(b)
+}
+
+// Close flushes the compressed data.
+func (cw *compressWriter) Close() error {
+	if cw.w == nil {
+		return nil
+	}
+	return cw.w.Close()
+}
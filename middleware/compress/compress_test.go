@@ -0,0 +1,139 @@
+package compress_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/compress"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Negotiate", func() {
+	It("picks the first accepted algorithm in server order", func() {
+		Ω(compress.Negotiate("gzip, deflate", "deflate", "gzip")).Should(Equal("deflate"))
+	})
+
+	It("ignores algorithms with a zero quality", func() {
+		Ω(compress.Negotiate("deflate;q=0, gzip;q=0.5", "deflate", "gzip")).Should(Equal("gzip"))
+	})
+
+	It("honors wildcards", func() {
+		Ω(compress.Negotiate("*", "gzip")).Should(Equal("gzip"))
+		Ω(compress.Negotiate("gzip;q=0, *", "gzip", "deflate")).Should(Equal("deflate"))
+	})
+
+	It("ignores algorithms that are not registered", func() {
+		Ω(compress.Negotiate("br, gzip", "br", "gzip")).Should(Equal("gzip"))
+	})
+
+	It("returns an empty string if nothing is acceptable", func() {
+		Ω(compress.Negotiate("", "gzip")).Should(Equal(""))
+		Ω(compress.Negotiate("identity", "gzip")).Should(Equal(""))
+	})
+})
+
+var _ = Describe("Middleware", func() {
+	var ctx context.Context
+	var req *http.Request
+	var rw *httptest.ResponseRecorder
+	var handler goa.Handler
+
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest("GET", "/foo", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		req.Header.Set("Accept-Encoding", "gzip")
+		rw = httptest.NewRecorder()
+		ctx = goa.NewContext(nil, rw, req, nil)
+		handler = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			resp := goa.ContextResponse(ctx)
+			resp.Header().Set("Content-Type", "application/json")
+			resp.WriteHeader(http.StatusOK)
+			resp.Write([]byte(`{"compress":"me"}`))
+			return nil
+		}
+	})
+
+	It("compresses the response", func() {
+		err := compress.Middleware("gzip")(handler)(ctx, goa.ContextResponse(ctx), req)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Header().Get("Content-Encoding")).Should(Equal("gzip"))
+		Ω(rw.Header().Get("Content-Type")).Should(Equal("application/json"))
+		Ω(rw.Header().Get("Vary")).Should(Equal("Accept-Encoding"))
+		gzr, err := gzip.NewReader(rw.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		body, err := ioutil.ReadAll(gzr)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(body)).Should(Equal(`{"compress":"me"}`))
+	})
+
+	It("does not compress if the client does not accept the algorithms", func() {
+		req.Header.Set("Accept-Encoding", "br")
+		err := compress.Middleware("gzip")(handler)(ctx, goa.ContextResponse(ctx), req)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Header().Get("Content-Encoding")).Should(BeEmpty())
+		Ω(rw.Body.String()).Should(Equal(`{"compress":"me"}`))
+	})
+
+	It("does not compress responses with no body", func() {
+		handler = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			goa.ContextResponse(ctx).WriteHeader(http.StatusNoContent)
+			return nil
+		}
+		err := compress.Middleware("gzip")(handler)(ctx, goa.ContextResponse(ctx), req)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(http.StatusNoContent))
+		Ω(rw.Header().Get("Content-Encoding")).Should(BeEmpty())
+		Ω(rw.Body.Len()).Should(Equal(0))
+	})
+})
+
+var _ = Describe("Decode", func() {
+	var req *http.Request
+	var decoded string
+	var unm goa.Unmarshaler
+
+	BeforeEach(func() {
+		var buf bytes.Buffer
+		gzw := gzip.NewWriter(&buf)
+		gzw.Write([]byte(`{"payload":42}`))
+		gzw.Close()
+		var err error
+		req, err = http.NewRequest("POST", "/foo", &buf)
+		Ω(err).ShouldNot(HaveOccurred())
+		req.Header.Set("Content-Encoding", "gzip")
+		decoded = ""
+		unm = func(ctx context.Context, service *goa.Service, req *http.Request) error {
+			b, err := ioutil.ReadAll(req.Body)
+			decoded = string(b)
+			return err
+		}
+	})
+
+	It("decompresses the request body", func() {
+		err := compress.Decode(unm, "gzip")(context.Background(), nil, req)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(decoded).Should(Equal(`{"payload":42}`))
+		Ω(req.Header.Get("Content-Encoding")).Should(BeEmpty())
+	})
+
+	It("rejects algorithms that are not enabled", func() {
+		err := compress.Decode(unm, "deflate")(context.Background(), nil, req)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(`unsupported content encoding "gzip"`))
+	})
+
+	It("passes uncompressed bodies through", func() {
+		req, _ = http.NewRequest("POST", "/foo", strings.NewReader(`{}`))
+		err := compress.Decode(unm, "gzip")(context.Background(), nil, req)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(decoded).Should(Equal(`{}`))
+	})
+})
@@ -0,0 +1,16 @@
+/*
+Package compress provides a middleware that compresses response bodies and a wrapper that
+decompresses request bodies using the algorithms enabled on a resource with the Compress DSL.
+
+The gzip and deflate algorithms are supported out of the box. Other algorithms such as "br" must be
+registered with Register before they can be used, for example:
+
+	compress.Register("br",
+		func(w io.Writer) (io.WriteCloser, error) { return brotli.NewWriter(w), nil },
+		func(r io.Reader) (io.ReadCloser, error) { return ioutil.NopCloser(brotli.NewReader(r)), nil },
+	)
+
+Algorithms that are not registered are ignored when negotiating the response encoding and requests
+using them are rejected.
+*/
+package compress
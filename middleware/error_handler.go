@@ -15,6 +15,8 @@ import (
 // them, it turns other Go error types into a 500 internal error response.
 // If verbose is false the details of internal errors is not included in HTTP responses.
 // If you use github.com/pkg/errors then wrapping the error will allow a trace to be printed to the logs
+// ErrorHandler translates the error messages using the service message catalog if any, the
+// language is negotiated using the request Accept-Language header.
 func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 	return func(h goa.Handler) goa.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
@@ -28,6 +30,12 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 			if err, ok := cause.(goa.ServiceError); ok {
 				status = err.ResponseStatus()
 				respBody = err
+				if e, ok := err.(*goa.ErrorResponse); ok && service.Messages != nil {
+					lang := service.Messages.Negotiate(req.Header.Get("Accept-Language"))
+					respBody = service.Messages.Localize(e, lang)
+					rw.Header().Set("Content-Language", lang)
+					rw.Header().Add("Vary", "Accept-Language")
+				}
 				goa.ContextResponse(ctx).ErrorCode = err.Token()
 				rw.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
 			} else {
@@ -45,6 +45,7 @@ var _ = Describe("ErrorHandler", func() {
 	var service *goa.Service
 	var h goa.Handler
 	var verbose bool
+	var acceptLanguage string
 
 	var rw *testResponseWriter
 
@@ -52,6 +53,7 @@ var _ = Describe("ErrorHandler", func() {
 		service = nil
 		h = nil
 		verbose = true
+		acceptLanguage = ""
 		rw = nil
 	})
 
@@ -60,6 +62,9 @@ var _ = Describe("ErrorHandler", func() {
 		eh := middleware.ErrorHandler(service, verbose)(h)
 		req, err := http.NewRequest("GET", "/foo", nil)
 		Ω(err).ShouldNot(HaveOccurred())
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
 		ctx := newContext(service, rw, req, nil)
 		err = eh(ctx, rw, req)
 		Ω(err).ShouldNot(HaveOccurred())
@@ -163,6 +168,48 @@ var _ = Describe("ErrorHandler", func() {
 		})
 	})
 
+	Context("with a message catalog", func() {
+		BeforeEach(func() {
+			service = newService(nil)
+			service.Messages = goa.NewMessageCatalog()
+			service.Messages.Register("fr", map[string]string{
+				"missing_attribute": "l'attribut {attribute} de {parent} est obligatoire",
+			})
+			h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				return goa.MissingAttributeError("raw", "name")
+			}
+		})
+
+		Context("and a request accepting a translated language", func() {
+			BeforeEach(func() {
+				acceptLanguage = "de;q=0.5, fr-CA"
+			})
+
+			It("translates the error message", func() {
+				var decoded errorResponse
+				Ω(rw.Status).Should(Equal(400))
+				Ω(rw.ParentHeader.Get("Content-Language")).Should(Equal("fr"))
+				err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(decoded.Detail).Should(Equal("l'attribut name de raw est obligatoire"))
+			})
+		})
+
+		Context("and a request accepting other languages", func() {
+			BeforeEach(func() {
+				acceptLanguage = "de"
+			})
+
+			It("sends the message in English", func() {
+				var decoded errorResponse
+				Ω(rw.ParentHeader.Get("Content-Language")).Should(Equal("en"))
+				err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(decoded.Detail).Should(Equal(goa.MissingAttributeError("raw", "name").(*goa.ErrorResponse).Detail))
+			})
+		})
+	})
+
 	Context("with a handler returning a pkg errors wrapped error", func() {
 		var wrappedError error
 		var logger *testLogger
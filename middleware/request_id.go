@@ -65,6 +65,18 @@ func RequestIDWithHeaderAndLengthLimit(requestIDHeader string, lengthLimit int)
 	}
 }
 
+// RequestIDWithEcho behaves like RequestIDWithHeader but it also sets the request ID header of the
+// response to the request ID so that clients can correlate responses with the server logs.
+func RequestIDWithEcho(requestIDHeader string) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		echo := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			rw.Header().Set(requestIDHeader, ContextRequestID(ctx))
+			return h(ctx, rw, req)
+		}
+		return RequestIDWithHeader(requestIDHeader)(echo)
+	}
+}
+
 // RequestID is a middleware that injects a request ID into the context of each request.
 // Retrieve it using ctx.Value(ReqIDKey). If the incoming request has a RequestIDHeader header then
 // that value is used else a random value is generated.
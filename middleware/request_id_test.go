@@ -83,6 +83,35 @@ var _ = Describe("RequestID", func() {
 		Ω(middleware.ContextRequestID(newCtx)).Should(Equal(string(original)))
 	})
 
+	It("echoes the request ID in the response", func() {
+		var newCtx context.Context
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			newCtx = ctx
+			return nil
+		}
+		trw := newTestResponseWriter()
+		req.Header.Del(middleware.RequestIDHeader)
+		req.Header.Set("X-Correlation-ID", reqID)
+		rg := middleware.RequestIDWithEcho("X-Correlation-ID")(h)
+		Ω(rg(ctx, trw, req)).ShouldNot(HaveOccurred())
+		Ω(middleware.ContextRequestID(newCtx)).Should(Equal(reqID))
+		Ω(trw.Header().Get("X-Correlation-ID")).Should(Equal(reqID))
+	})
+
+	It("echoes the generated request ID in the response", func() {
+		var newCtx context.Context
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			newCtx = ctx
+			return nil
+		}
+		trw := newTestResponseWriter()
+		req.Header.Del(middleware.RequestIDHeader)
+		rg := middleware.RequestIDWithEcho(middleware.RequestIDHeader)(h)
+		Ω(rg(ctx, trw, req)).ShouldNot(HaveOccurred())
+		Ω(middleware.ContextRequestID(newCtx)).ShouldNot(BeEmpty())
+		Ω(trw.Header().Get(middleware.RequestIDHeader)).Should(Equal(middleware.ContextRequestID(newCtx)))
+	})
+
 })
 
 func makeRequestID(length int) string {
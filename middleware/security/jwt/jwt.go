@@ -24,6 +24,8 @@ import (
 //     3. If scopes are defined in the design for the action, validate them
 //        against the scopes presented by the JWT in the claim "scope", or if
 //        that's not defined, "scopes".
+//     4. If a required scopes expression is defined in the design for the
+//        action, evaluate it against the same scopes.
 //
 // The `exp` (expiration) and `nbf` (not before) date checks are validated by the JWT library.
 //
@@ -127,6 +129,10 @@ func New(resolver KeyResolver, validationFunc goa.Middleware, scheme *goa.JWTSec
 					return ErrJWTError(msg, "required", requiredScopes, "scopes", scopesInClaimList)
 				}
 			}
+			if expr := goa.ContextRequiredScopeExpr(ctx); expr != nil && !expr(scopesInClaim) {
+				msg := "authorization failed: required scopes expression not satisfied by JWT claim"
+				return ErrJWTError(msg, "scopes", scopesInClaimList)
+			}
 
 			ctx = WithJWT(ctx, token)
 			if validationFunc != nil {
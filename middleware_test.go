@@ -152,3 +152,65 @@ var _ = Describe("NewMiddleware", func() {
 
 	})
 })
+
+var _ = Describe("InvalidRequestStatus", func() {
+	var err error
+
+	JustBeforeEach(func() {
+		h := func(context.Context, http.ResponseWriter, *http.Request) error { return err }
+		err = goa.InvalidRequestStatus(422)(h)(context.Background(), nil, nil)
+	})
+
+	Context("with an invalid request error", func() {
+		BeforeEach(func() {
+			err = goa.MissingParamError("id")
+		})
+
+		It("overrides the response status", func() {
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(422))
+		})
+	})
+
+	Context("with another error", func() {
+		BeforeEach(func() {
+			err = goa.ErrNotFound("not found")
+		})
+
+		It("keeps the response status", func() {
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(404))
+		})
+	})
+})
+
+var _ = Describe("ReportViolations", func() {
+	var err error
+
+	JustBeforeEach(func() {
+		h := func(context.Context, http.ResponseWriter, *http.Request) error { return err }
+		err = goa.ReportViolations()(h)(context.Background(), nil, nil)
+	})
+
+	Context("with an invalid request error", func() {
+		BeforeEach(func() {
+			err = goa.MergeErrors(nil, goa.MissingAttributeError("raw.origin", "country"))
+		})
+
+		It("lists the violations in the error metadata", func() {
+			meta := err.(*goa.ErrorResponse).Meta
+			Ω(meta).Should(HaveKey("violations"))
+			violations := meta["violations"].([]*goa.Violation)
+			Ω(violations).Should(HaveLen(1))
+			Ω(violations[0].Pointer).Should(Equal("/origin/country"))
+		})
+	})
+
+	Context("with another error", func() {
+		BeforeEach(func() {
+			err = goa.ErrNotFound("not found")
+		})
+
+		It("leaves the error metadata untouched", func() {
+			Ω(err.(*goa.ErrorResponse).Meta).ShouldNot(HaveKey("violations"))
+		})
+	})
+})
@@ -3,6 +3,8 @@ package goa
 import (
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/dimfeld/httptreemux"
 )
@@ -42,6 +44,10 @@ type (
 		MuxHandler(string, Handler, Unmarshaler) MuxHandler
 	}
 
+	// TrailingSlashPolicy defines how the default mux handles requests whose path differs
+	// from a route path only by a trailing slash or by duplicate slashes.
+	TrailingSlashPolicy int
+
 	// mux is the default ServeMux implementation.
 	mux struct {
 		router  *httptreemux.TreeMux
@@ -49,6 +55,15 @@ type (
 	}
 )
 
+const (
+	// TrailingSlashRedirect redirects the requests to the route path with a 308 status.
+	TrailingSlashRedirect TrailingSlashPolicy = iota + 1
+	// TrailingSlashStrict handles the requests as not found.
+	TrailingSlashStrict
+	// TrailingSlashIgnore handles the requests with the route handler.
+	TrailingSlashIgnore
+)
+
 // NewMux returns a Mux.
 func NewMux() ServeMux {
 	r := httptreemux.New()
@@ -59,6 +74,61 @@ func NewMux() ServeMux {
 	}
 }
 
+// SetTrailingSlashPolicy sets the policy used by the default mux returned by NewMux to handle the
+// requests whose path differs from a route path only by a trailing slash or by duplicate slashes.
+// The mux redirects these requests with a 301 status by default. SetTrailingSlashPolicy returns
+// false if m is not the default mux.
+func SetTrailingSlashPolicy(m ServeMux, policy TrailingSlashPolicy) bool {
+	dm, ok := m.(*mux)
+	if !ok {
+		return false
+	}
+	switch policy {
+	case TrailingSlashRedirect:
+		dm.router.RedirectTrailingSlash = true
+		dm.router.RedirectCleanPath = true
+		dm.router.RedirectBehavior = httptreemux.Redirect308
+	case TrailingSlashStrict:
+		dm.router.RedirectTrailingSlash = false
+		dm.router.RedirectCleanPath = false
+	case TrailingSlashIgnore:
+		dm.router.RedirectTrailingSlash = true
+		dm.router.RedirectCleanPath = true
+		dm.router.RedirectBehavior = httptreemux.UseHandler
+	}
+	return true
+}
+
+// Routes returns the routes registered with the default mux returned by NewMux sorted by path and
+// method, each route is the HTTP method followed by a space and the path, e.g. "GET /bottles/:id".
+// Routes returns nil if m is not the default mux.
+func Routes(m ServeMux) []string {
+	dm, ok := m.(*mux)
+	if !ok {
+		return nil
+	}
+	type route struct{ method, path string }
+	routes := make([]route, 0, len(dm.handles))
+	for k := range dm.handles {
+		i := strings.Index(k, "/")
+		if i < 0 {
+			continue
+		}
+		routes = append(routes, route{k[:i], k[i:]})
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].path == routes[j].path {
+			return routes[i].method < routes[j].method
+		}
+		return routes[i].path < routes[j].path
+	})
+	res := make([]string, len(routes))
+	for i, r := range routes {
+		res[i] = r.method + " " + r.path
+	}
+	return res
+}
+
 // Handle sets the handler for the given verb and path.
 func (m *mux) Handle(method, path string, handle MuxHandler) {
 	hthandle := func(rw http.ResponseWriter, req *http.Request, htparams map[string]string) {
@@ -83,4 +83,123 @@ var _ = Describe("Mux", func() {
 		})
 	})
 
+	Context("with a catch-all not found handler", func() {
+		var handled, fallback string
+
+		BeforeEach(func() {
+			handled, fallback = "", ""
+			mux.Handle("GET", "/bottles/:id", func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+				handled = vals.Get("id")
+			})
+			mux.Handle("GET", "/bottles/:id/rate", func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+				handled = "rate " + vals.Get("id")
+			})
+			mux.Handle("GET", "/bottles/*goaNotFoundPath", func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+				fallback = vals.Get("goaNotFoundPath")
+			})
+		})
+
+		Context("and a request matching a route", func() {
+			BeforeEach(func() {
+				var err error
+				req, err = http.NewRequest("GET", "/bottles/42", nil)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("does not use the not found handler", func() {
+				Ω(handled).Should(Equal("42"))
+				Ω(fallback).Should(BeEmpty())
+			})
+		})
+
+		Context("and a request matching a nested route", func() {
+			BeforeEach(func() {
+				var err error
+				req, err = http.NewRequest("GET", "/bottles/42/rate", nil)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("does not use the not found handler", func() {
+				Ω(handled).Should(Equal("rate 42"))
+				Ω(fallback).Should(BeEmpty())
+			})
+		})
+
+		Context("and a request to an unmatched path", func() {
+			BeforeEach(func() {
+				var err error
+				req, err = http.NewRequest("GET", "/bottles/42/unknown", nil)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("uses the not found handler", func() {
+				Ω(handled).Should(BeEmpty())
+				Ω(fallback).Should(Equal("42/unknown"))
+			})
+		})
+	})
+
+	Context("with a request path that has a trailing slash", func() {
+		var handled bool
+
+		BeforeEach(func() {
+			handled = false
+			mux.Handle("GET", "/users", func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+				handled = true
+			})
+			var err error
+			req, err = http.NewRequest("GET", "/users/", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("redirects with a 301 status by default", func() {
+			Ω(rw.Status).Should(Equal(301))
+			Ω(handled).Should(BeFalse())
+		})
+
+		Context("and the redirect policy", func() {
+			BeforeEach(func() {
+				Ω(goa.SetTrailingSlashPolicy(mux, goa.TrailingSlashRedirect)).Should(BeTrue())
+			})
+
+			It("redirects with a 308 status", func() {
+				Ω(rw.Status).Should(Equal(308))
+				Ω(rw.ParentHeader.Get("Location")).Should(Equal("/users"))
+				Ω(handled).Should(BeFalse())
+			})
+		})
+
+		Context("and the strict policy", func() {
+			BeforeEach(func() {
+				Ω(goa.SetTrailingSlashPolicy(mux, goa.TrailingSlashStrict)).Should(BeTrue())
+			})
+
+			It("returns 404", func() {
+				Ω(rw.Status).Should(Equal(404))
+				Ω(handled).Should(BeFalse())
+			})
+		})
+
+		Context("and the ignore policy", func() {
+			BeforeEach(func() {
+				Ω(goa.SetTrailingSlashPolicy(mux, goa.TrailingSlashIgnore)).Should(BeTrue())
+			})
+
+			It("handles the request", func() {
+				Ω(handled).Should(BeTrue())
+			})
+		})
+	})
+
+})
+
+var _ = Describe("Routes", func() {
+	It("returns the routes of the default mux sorted by path and method", func() {
+		mux := goa.NewMux()
+		noop := func(http.ResponseWriter, *http.Request, url.Values) {}
+		mux.Handle("POST", "/users", noop)
+		mux.Handle("GET", "/users/:id", noop)
+		mux.Handle("GET", "/users", noop)
+		Ω(goa.Routes(mux)).Should(Equal([]string{"GET /users", "POST /users", "GET /users/:id"}))
+	})
 })
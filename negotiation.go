@@ -0,0 +1,311 @@
+package goa
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MediaRange is a media range of an Accept header as defined by RFC 7231 section 5.3.2, e.g.
+// "text/*;q=0.5".
+type MediaRange struct {
+	// Type is the lowercase top-level type, "*" if the range matches any type.
+	Type string
+	// Subtype is the lowercase subtype, "*" if the range matches any subtype.
+	Subtype string
+	// Params contains the media type parameters that precede the q parameter indexed by
+	// lowercase name.
+	Params map[string]string
+	// Quality is the value of the q parameter, 1 if the range has none.
+	Quality float64
+}
+
+// ParseAccept parses the value of an Accept header. The parameters that follow the q parameter
+// are accept extensions and are ignored. Empty list elements are skipped.
+func ParseAccept(header string) ([]*MediaRange, error) {
+	var ranges []*MediaRange
+	for _, elem := range splitQuoted(header, ',') {
+		elem = strings.TrimSpace(elem)
+		if elem == "" {
+			continue
+		}
+		r, err := parseMediaRange(elem)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// parseMediaRange parses a single media range and its accept parameters.
+func parseMediaRange(s string) (*MediaRange, error) {
+	parts := splitQuoted(s, ';')
+	typ := strings.ToLower(strings.TrimSpace(parts[0]))
+	slash := strings.IndexByte(typ, '/')
+	if slash <= 0 || slash == len(typ)-1 || !isToken(typ[:slash]) || !isToken(typ[slash+1:]) {
+		return nil, fmt.Errorf("invalid media range %q", s)
+	}
+	r := &MediaRange{Type: typ[:slash], Subtype: typ[slash+1:], Quality: 1}
+	if r.Type == "*" && r.Subtype != "*" {
+		return nil, fmt.Errorf("invalid media range %q", s)
+	}
+	for _, p := range parts[1:] {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		eq := strings.IndexByte(p, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid parameter %q in media range %q", p, s)
+		}
+		name := strings.ToLower(strings.TrimSpace(p[:eq]))
+		value, err := unquote(strings.TrimSpace(p[eq+1:]))
+		if err != nil || !isToken(name) {
+			return nil, fmt.Errorf("invalid parameter %q in media range %q", p, s)
+		}
+		if name == "q" {
+			q, err := parseQuality(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quality value %q in media range %q", value, s)
+			}
+			r.Quality = q
+			break
+		}
+		if r.Params == nil {
+			r.Params = make(map[string]string)
+		}
+		r.Params[name] = value
+	}
+	return r, nil
+}
+
+// Match returns true if the media range matches the media type. The parameters of the range must
+// have the same values in the media type, the parameters that the media type does not define
+// match any value. The values of the charset parameters are compared case-insensitively.
+func (r *MediaRange) Match(mediaType string) bool {
+	mt, params, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+	slash := strings.IndexByte(mt, '/')
+	if slash < 0 {
+		return false
+	}
+	if r.Type != "*" && r.Type != mt[:slash] || r.Subtype != "*" && r.Subtype != mt[slash+1:] {
+		return false
+	}
+	for n, v := range r.Params {
+		pv, ok := params[n]
+		if !ok {
+			continue
+		}
+		if n == "charset" && strings.EqualFold(pv, v) {
+			continue
+		}
+		if pv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// specificity ranks the media ranges so that the most specific range that matches a media type
+// defines its quality: "*/*" < "type/*" < "type/subtype" < "type/subtype" with parameters.
+func (r *MediaRange) specificity() int {
+	switch {
+	case r.Type == "*":
+		return 0
+	case r.Subtype == "*":
+		return 1
+	default:
+		return 2 + len(r.Params)
+	}
+}
+
+// Acceptable returns the media types accepted by the Accept header value ordered by decreasing
+// quality, the media types with the same quality keep their order. The quality of a media type is
+// the quality of the most specific media range that matches it, the media types that no range
+// matches or whose quality is 0 are not acceptable. All the media types are acceptable if the
+// header is empty.
+func Acceptable(header string, mediaTypes []string) ([]string, error) {
+	if strings.TrimSpace(header) == "" {
+		return mediaTypes, nil
+	}
+	ranges, err := ParseAccept(header)
+	if err != nil {
+		return nil, err
+	}
+	type candidate struct {
+		mediaType string
+		quality   float64
+	}
+	var candidates []candidate
+	for _, mt := range mediaTypes {
+		best := -1
+		var q float64
+		for _, r := range ranges {
+			if s := r.specificity(); s > best && r.Match(mt) {
+				best, q = s, r.Quality
+			}
+		}
+		if best >= 0 && q > 0 {
+			candidates = append(candidates, candidate{mt, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	accepted := make([]string, len(candidates))
+	for i, c := range candidates {
+		accepted[i] = c.mediaType
+	}
+	return accepted, nil
+}
+
+// CheckAccept returns a ErrNotAcceptable error listing the media types the action produces if the
+// request Accept header accepts none of them. The code generated for the actions that negotiate
+// their media types calls it before the request is handled.
+func CheckAccept(req *http.Request, produces ...string) error {
+	header := strings.Join(req.Header["Accept"], ",")
+	accepted, err := Acceptable(header, produces)
+	if err != nil {
+		return ErrBadRequest(fmt.Sprintf("invalid Accept header: %s", err), "accept", header)
+	}
+	if len(accepted) == 0 {
+		return ErrNotAcceptable(
+			fmt.Sprintf("none of the media types accepted by %q is supported, supported media types are %s", header, strings.Join(produces, ", ")),
+			"accept", header, "supported", produces,
+		)
+	}
+	return nil
+}
+
+// CheckContentType returns a ErrUnsupportedMediaType error listing the media types the action
+// consumes if the request Content-Type header is not one of them. The media type parameters such
+// as charset or boundary are ignored and the consumed media types may be media ranges such as
+// "text/*". Requests without Content-Type header are accepted and decoded with the default
+// decoder.
+func CheckContentType(req *http.Request, consumes ...string) error {
+	header := req.Header.Get("Content-Type")
+	if header == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return ErrUnsupportedMediaType(fmt.Sprintf("invalid Content-Type header %q: %s", header, err),
+			"content_type", header, "supported", consumes)
+	}
+	for _, c := range consumes {
+		if r, err := parseMediaRange(c); err == nil && r.Match(mediaType) {
+			return nil
+		}
+	}
+	return ErrUnsupportedMediaType(
+		fmt.Sprintf("unsupported content type %q, supported content types are %s", mediaType, strings.Join(consumes, ", ")),
+		"content_type", mediaType, "supported", consumes,
+	)
+}
+
+// Negotiate returns a middleware that checks the request Accept header against the media types
+// the action produces, see CheckAccept. The requests rejected by CheckContentType when loading
+// the request body are reported first.
+func Negotiate(produces ...string) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			if e, ok := ContextError(ctx).(*ErrorResponse); ok && e.Status == http.StatusUnsupportedMediaType {
+				return e
+			}
+			if err := CheckAccept(req, produces...); err != nil {
+				return err
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// NegotiateContentType returns an unmarshaler that checks the request Content-Type header against
+// the media types the action consumes before unm decodes the body, see CheckContentType.
+func NegotiateContentType(unm Unmarshaler, consumes ...string) Unmarshaler {
+	return func(ctx context.Context, service *Service, req *http.Request) error {
+		if err := CheckContentType(req, consumes...); err != nil {
+			return err
+		}
+		return unm(ctx, service, req)
+	}
+}
+
+// parseQuality parses a qvalue: "0" or "1" optionally followed by up to three decimals.
+func parseQuality(s string) (float64, error) {
+	if len(s) == 0 || len(s) > 5 || s[0] != '0' && s[0] != '1' || len(s) > 1 && s[1] != '.' {
+		return 0, fmt.Errorf("invalid qvalue %q", s)
+	}
+	q, err := strconv.ParseFloat(s, 64)
+	if err != nil || q > 1 {
+		return 0, fmt.Errorf("invalid qvalue %q", s)
+	}
+	return q, nil
+}
+
+// splitQuoted splits s around sep ignoring the separators that appear in quoted strings.
+func splitQuoted(s string, sep byte) []string {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unquote returns the value of a token or of a quoted string.
+func unquote(s string) (string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		if !isToken(s) {
+			return "", fmt.Errorf("invalid token %q", s)
+		}
+		return s, nil
+	}
+	if len(s) < 2 || !strings.HasSuffix(s, `"`) {
+		return "", fmt.Errorf("invalid quoted string %s", s)
+	}
+	var b bytes.Buffer
+	for i := 1; i < len(s)-1; i++ {
+		c := s[i]
+		if c == '\\' {
+			i++
+			if i == len(s)-1 {
+				return "", fmt.Errorf("invalid quoted string %s", s)
+			}
+			c = s[i]
+		} else if c == '"' {
+			return "", fmt.Errorf("invalid quoted string %s", s)
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// isToken returns true if s is a non-empty RFC 7230 token.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+package goa_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Acceptable", func() {
+	produces := []string{"application/json", "application/xml", "text/html; level=1"}
+
+	cases := []struct {
+		name     string
+		header   string
+		accepted []string
+	}{
+		{"no header", "", produces},
+		{"any type", "*/*", produces},
+		{"exact type", "application/xml", []string{"application/xml"}},
+		{"case-insensitive type", "Application/JSON", []string{"application/json"}},
+		{"subtype wildcard", "application/*", []string{"application/json", "application/xml"}},
+		{"quality ordering", "application/json;q=0.5, application/xml", []string{"application/xml", "application/json"}},
+		{"whitespace around parameters", "application/json ; q=0.2 , application/xml ; q=0.4", []string{"application/xml", "application/json"}},
+		{"explicit refusal", "*/*, application/json;q=0", []string{"application/xml", "text/html; level=1"}},
+		{"most specific range wins", "application/*;q=0, application/json", []string{"application/json"}},
+		{"refused wildcard", "*/*;q=0", nil},
+		{"empty list elements", ", ,application/json,,", []string{"application/json"}},
+		{"accept extensions", "application/json;q=0.8;ext=\"a,b\"", []string{"application/json"}},
+		{"quoted comma in parameter", "text/html;level=\"1,2\", application/xml", []string{"application/xml"}},
+		{"matching parameter", "text/html;level=1", []string{"text/html; level=1"}},
+		{"mismatching parameter", "text/html;level=2", nil},
+		{"parameter absent from media type", "application/json;charset=UTF-8", []string{"application/json"}},
+		{"unknown type", "image/png", nil},
+		{"three decimals quality", "application/json;q=0.001", []string{"application/json"}},
+	}
+	for _, c := range cases {
+		c := c
+		It("handles "+c.name, func() {
+			accepted, err := goa.Acceptable(c.header, produces)
+			Ω(err).ShouldNot(HaveOccurred())
+			if c.accepted == nil {
+				Ω(accepted).Should(BeEmpty())
+			} else {
+				Ω(accepted).Should(Equal(c.accepted))
+			}
+		})
+	}
+
+	invalid := []string{
+		"application",
+		"*/json",
+		"application/",
+		"application/json;q=1.5",
+		"application/json;q=0.0001",
+		"application/json;q=abc",
+		"application/json;level",
+		"text/html;level=\"1",
+	}
+	for _, h := range invalid {
+		h := h
+		It("rejects "+h, func() {
+			_, err := goa.Acceptable(h, produces)
+			Ω(err).Should(HaveOccurred())
+		})
+	}
+})
+
+var _ = Describe("CheckContentType", func() {
+	var contentType string
+	var err error
+
+	JustBeforeEach(func() {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader("{}"))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		err = goa.CheckContentType(req, "application/json", "multipart/form-data", "text/*")
+	})
+
+	Context("with a consumed content type and parameters", func() {
+		BeforeEach(func() {
+			contentType = "multipart/form-data; boundary=----abc"
+		})
+
+		It("accepts the request", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a content type matching a consumed media range", func() {
+		BeforeEach(func() {
+			contentType = "text/plain; charset=utf-8"
+		})
+
+		It("accepts the request", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with no content type", func() {
+		BeforeEach(func() {
+			contentType = ""
+		})
+
+		It("accepts the request", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with an unsupported content type", func() {
+		BeforeEach(func() {
+			contentType = "application/xml"
+		})
+
+		It("returns a 415 error listing the supported content types", func() {
+			Ω(err).Should(HaveOccurred())
+			e := err.(*goa.ErrorResponse)
+			Ω(e.Status).Should(Equal(415))
+			Ω(e.Detail).Should(ContainSubstring("application/json, multipart/form-data, text/*"))
+			Ω(e.Meta).Should(HaveKeyWithValue("supported", []string{"application/json", "multipart/form-data", "text/*"}))
+		})
+	})
+})
+
+var _ = Describe("Negotiate", func() {
+	var accept string
+	var ctxErr error
+	var called bool
+	var err error
+
+	BeforeEach(func() {
+		accept = ""
+		ctxErr = nil
+		called = false
+	})
+
+	JustBeforeEach(func() {
+		req, _ := http.NewRequest("GET", "/", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		ctx := goa.NewContext(context.Background(), nil, req, nil)
+		if ctxErr != nil {
+			ctx = goa.WithError(ctx, ctxErr)
+		}
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			return nil
+		}
+		err = goa.Negotiate("application/json", "application/xml")(h)(ctx, nil, req)
+	})
+
+	It("calls the handler when the request accepts a produced media type", func() {
+		accept = "text/html, application/xml;q=0.9"
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(called).Should(BeTrue())
+	})
+
+	Context("with an Accept header refusing the produced media types", func() {
+		BeforeEach(func() {
+			accept = "text/html, application/*;q=0"
+		})
+
+		It("returns a 406 error listing the supported media types", func() {
+			Ω(called).Should(BeFalse())
+			e := err.(*goa.ErrorResponse)
+			Ω(e.Status).Should(Equal(406))
+			Ω(e.Detail).Should(ContainSubstring("application/json, application/xml"))
+			Ω(e.Meta).Should(HaveKeyWithValue("accept", accept))
+		})
+	})
+
+	Context("with an invalid Accept header", func() {
+		BeforeEach(func() {
+			accept = "application"
+		})
+
+		It("returns a 400 error", func() {
+			Ω(called).Should(BeFalse())
+			Ω(err.(*goa.ErrorResponse).Status).Should(Equal(400))
+		})
+	})
+
+	Context("with an unsupported content type", func() {
+		BeforeEach(func() {
+			accept = "text/html"
+			ctxErr = goa.ErrUnsupportedMediaType("unsupported")
+		})
+
+		It("reports the unsupported content type first", func() {
+			Ω(err.(*goa.ErrorResponse).Status).Should(Equal(415))
+		})
+	})
+})
@@ -0,0 +1,115 @@
+package goa
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+)
+
+// WebSocketInternalError is the close code sent to the client when a websocket action panics.
+const WebSocketInternalError = 1011
+
+// PanicHandler handles the panics raised by the controller actions. rec is the value returned by
+// recover and stack the corresponding stack trace. The returned error is handled as if it had been
+// returned by the action.
+type PanicHandler func(ctx context.Context, rec interface{}, stack []byte) error
+
+// DefaultPanicHandler logs the panic and its stack trace using the service logger and returns an
+// internal error.
+func DefaultPanicHandler(ctx context.Context, rec interface{}, stack []byte) error {
+	LogError(ctx, "panic", "err", fmt.Sprint(rec), "stack", string(stack))
+	return ErrInternal(fmt.Sprintf("panic: %v", rec))
+}
+
+// HandlePanics returns a middleware that recovers the panics raised by the handler and converts
+// them into the error returned by ph.
+func HandlePanics(ph PanicHandler) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) (err error) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					err = ph(ctx, rec, stack())
+				}
+			}()
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// HandleWebSocketPanics is the HandlePanics counterpart for websocket actions. The HTTP response
+// cannot be written once the connection is upgraded so the websocket is closed with the
+// WebSocketInternalError close code instead, the error returned by ph is still returned by the
+// handler so that it gets logged.
+func HandleWebSocketPanics(ph PanicHandler) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) (err error) {
+			var hr *hijackRecorder
+			if resp := ContextResponse(ctx); resp != nil {
+				if hj, ok := resp.ResponseWriter.(http.Hijacker); ok {
+					hr = &hijackRecorder{ResponseWriter: resp.ResponseWriter, hijacker: hj}
+					resp.SwitchWriter(hr)
+					defer resp.SwitchWriter(hr.ResponseWriter)
+				}
+			}
+			defer func() {
+				rec := recover()
+				if rec != nil {
+					err = ph(ctx, rec, stack())
+				}
+				if hr != nil && hr.conn != nil {
+					hr.conn.release(rec != nil)
+				}
+			}()
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// stack returns the stack trace of the goroutine that panicked.
+func stack() []byte {
+	buf := make([]byte, 64<<10)
+	return buf[:runtime.Stack(buf, false)]
+}
+
+type (
+	// hijackRecorder records the connection hijacked by websocket handlers.
+	hijackRecorder struct {
+		http.ResponseWriter
+		hijacker http.Hijacker
+		conn     *deferredConn
+	}
+
+	// deferredConn delays closing the hijacked connection until the handler returns so that
+	// a close frame can be sent if the handler panics.
+	deferredConn struct {
+		net.Conn
+	}
+)
+
+// Hijack hijacks the underlying connection.
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	c, rw, err := h.hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	h.conn = &deferredConn{Conn: c}
+	return h.conn, rw, nil
+}
+
+// Close does nothing, the connection is closed by release once the handler returns.
+func (c *deferredConn) Close() error {
+	return nil
+}
+
+// release closes the connection, sending a close frame with the WebSocketInternalError code
+// first if the handler panicked.
+func (c *deferredConn) release(panicked bool) {
+	if panicked {
+		// Server frames are not masked: FIN and close opcode, 2 bytes payload.
+		c.Conn.Write([]byte{0x88, 0x02, WebSocketInternalError >> 8, WebSocketInternalError & 0xff})
+	}
+	c.Conn.Close()
+}
@@ -0,0 +1,91 @@
+package goa_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// hijackableRecorder is a response recorder that can be hijacked.
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.conn, bufio.NewReadWriter(bufio.NewReader(h.conn), bufio.NewWriter(h.conn)), nil
+}
+
+var _ = Describe("HandlePanics", func() {
+	var ctx context.Context
+	var rw *httptest.ResponseRecorder
+	var req *http.Request
+
+	BeforeEach(func() {
+		rw = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/", nil)
+		ctx = goa.NewContext(nil, rw, req, nil)
+	})
+
+	panicking := func(context.Context, http.ResponseWriter, *http.Request) error {
+		panic("boom")
+	}
+
+	It("converts the panic into an internal error", func() {
+		err := goa.HandlePanics(goa.DefaultPanicHandler)(panicking)(ctx, rw, req)
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusInternalServerError))
+		Ω(err.Error()).Should(ContainSubstring("panic: boom"))
+	})
+
+	It("calls the given panic handler", func() {
+		var recovered interface{}
+		var stack []byte
+		custom := errors.New("reported")
+		ph := func(_ context.Context, rec interface{}, s []byte) error {
+			recovered, stack = rec, s
+			return custom
+		}
+		err := goa.HandlePanics(ph)(panicking)(ctx, rw, req)
+		Ω(err).Should(Equal(custom))
+		Ω(recovered).Should(Equal("boom"))
+		Ω(string(stack)).Should(ContainSubstring("goroutine"))
+	})
+
+	It("does not change the handler result when it does not panic", func() {
+		h := func(context.Context, http.ResponseWriter, *http.Request) error { return nil }
+		Ω(goa.HandlePanics(goa.DefaultPanicHandler)(h)(ctx, rw, req)).ShouldNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("HandleWebSocketPanics", func() {
+	It("closes the hijacked connection with the internal error close code", func() {
+		server, client := net.Pipe()
+		rw := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server}
+		req, _ := http.NewRequest("GET", "/", nil)
+		ctx := goa.NewContext(nil, rw, req, nil)
+		h := func(ctx context.Context, _ http.ResponseWriter, _ *http.Request) error {
+			conn, _, err := goa.ContextResponse(ctx).ResponseWriter.(http.Hijacker).Hijack()
+			Ω(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+			panic("boom")
+		}
+		received := make(chan []byte)
+		go func() {
+			b, _ := ioutil.ReadAll(client)
+			received <- b
+		}()
+		err := goa.HandleWebSocketPanics(goa.DefaultPanicHandler)(h)(ctx, rw, req)
+		Ω(err).Should(HaveOccurred())
+		Eventually(received).Should(Receive(Equal([]byte{0x88, 0x02, 0x03, 0xf3})))
+		Ω(goa.ContextResponse(ctx).ResponseWriter).Should(Equal(rw))
+	})
+})
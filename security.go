@@ -27,6 +27,25 @@ func WithRequiredScopes(ctx context.Context, scopes []string) context.Context {
 	return context.WithValue(ctx, securityScopesKey, scopes)
 }
 
+// ScopeExprFunc evaluates the scope expression required by an action against the scopes granted
+// to the request. It is generated from the RequiredScopes design DSL.
+type ScopeExprFunc func(granted map[string]bool) bool
+
+// ContextRequiredScopeExpr extracts the required scope expression from the given context, nil if
+// the action does not define one. Auth handlers should call it with the scopes extracted from the
+// request credentials and reject the request if it returns false.
+func ContextRequiredScopeExpr(ctx context.Context) ScopeExprFunc {
+	if f := ctx.Value(securityScopeExprKey); f != nil {
+		return f.(ScopeExprFunc)
+	}
+	return nil
+}
+
+// WithRequiredScopeExpr builds a context containing the given required scope expression.
+func WithRequiredScopeExpr(ctx context.Context, expr ScopeExprFunc) context.Context {
+	return context.WithValue(ctx, securityScopeExprKey, expr)
+}
+
 // OAuth2Security represents the `oauth2` security scheme. It is instantiated by the generated code
 // accordingly to the use of the different `*Security()` DSL functions and `Security()` in the
 // design.
@@ -39,6 +39,9 @@ type (
 		Decoder *HTTPDecoder
 		// Response body encoder
 		Encoder *HTTPEncoder
+		// Messages contains the translations of the error messages sent by the ErrorHandler
+		// middleware. Error messages are sent in English when nil.
+		Messages *MessageCatalog
 
 		middleware []Middleware       // Middleware chain
 		cancel     context.CancelFunc // Service context cancel signal trigger
@@ -211,6 +214,15 @@ func (service *Service) ListenAndServeTLS(addr, certFile, keyFile string) error
 	return service.Server.ListenAndServeTLS(certFile, keyFile)
 }
 
+// LogRoutes logs the routes registered with the service mux, one "route" entry per route
+// including the address of the service HTTP server. It does nothing if the service mux is not
+// the default mux, see Routes.
+func (service *Service) LogRoutes() {
+	for _, r := range Routes(service.Mux) {
+		service.LogInfo("route", "addr", service.Server.Addr, "route", r)
+	}
+}
+
 // Serve accepts incoming HTTP connections on the listener l, invoking the service mux handler for each.
 func (service *Service) Serve(l net.Listener) error {
 	return service.Server.Serve(l)
@@ -238,9 +250,26 @@ func (service *Service) Send(ctx context.Context, code int, body interface{}) er
 		return fmt.Errorf("no response data in context")
 	}
 	r.WriteHeader(code)
+	if !ResponseHasBody(ContextRequest(ctx), code) {
+		return nil
+	}
 	return service.EncodeResponse(ctx, body)
 }
 
+// ResponseHasBody returns false if the response to the given request with the given status code
+// cannot have a body: responses to HEAD requests and responses with status 1xx, 204 or 304.
+func ResponseHasBody(req *RequestData, code int) bool {
+	if req != nil && req.Request != nil && req.Method == "HEAD" {
+		return false
+	}
+	return !BodylessStatus(code)
+}
+
+// BodylessStatus returns true if responses with the given status code cannot have a body.
+func BodylessStatus(code int) bool {
+	return code >= 100 && code < 200 || code == http.StatusNoContent || code == http.StatusNotModified
+}
+
 // ServeFiles create a "FileServer" controller and calls ServerFiles on it.
 func (service *Service) ServeFiles(path, filename string) error {
 	ctrl := service.NewController("FileServer")
@@ -332,8 +361,10 @@ func (ctrl *Controller) MuxHandler(name string, hdlr Handler, unm Unmarshaler) M
 				if err.Error() == "http: request body too large" {
 					msg := fmt.Sprintf("request body length exceeds %d bytes", ctrl.MaxRequestBodyLength)
 					err = ErrRequestBodyTooLarge(msg)
-				} else {
-					err = ErrBadRequest(err)
+				} else if _, ok := err.(ServiceError); !ok {
+					// Keep the validation errors intact so that their message keys and
+					// metadata are preserved.
+					err = withMessageKey(ErrBadRequest(err), "invalid_request_body")
 				}
 				ctx = WithError(ctx, err)
 			}
@@ -410,11 +441,39 @@ func (ctrl *Controller) FileHandler(path, filename string) Handler {
 		if d.IsDir() {
 			return dirList(rw, f)
 		}
+		if c, ok := ctx.Value(fileCachingKey).(*fileCaching); ok {
+			if c.etag {
+				rw.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, d.ModTime().UnixNano(), d.Size()))
+			}
+			if c.maxAge >= 0 {
+				rw.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", c.maxAge))
+			}
+		}
 		http.ServeContent(rw, req, d.Name(), d.ModTime(), f)
 		return nil
 	}
 }
 
+// fileCaching holds the caching options of the file handlers set with FileCaching.
+type fileCaching struct {
+	etag   bool
+	maxAge int
+}
+
+// FileCaching returns a middleware that adds caching headers to the responses written by the
+// handlers created with FileHandler. If etag is true the responses include an ETag header computed
+// from the served file modification time and size and conditional requests that match it receive
+// a 304 Not Modified response. If maxAge is positive or zero the responses include a
+// Cache-Control header with the corresponding max-age directive.
+func FileCaching(etag bool, maxAge int) Middleware {
+	c := &fileCaching{etag: etag, maxAge: maxAge}
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			return h(context.WithValue(ctx, fileCachingKey, c), rw, req)
+		}
+	}
+}
+
 var replacer = strings.NewReplacer(
 	"&", "&amp;",
 	"<", "&lt;",
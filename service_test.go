@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -110,6 +111,51 @@ var _ = Describe("Service", func() {
 		})
 	})
 
+	Describe("Send", func() {
+		var rw *httptest.ResponseRecorder
+		var method string
+		var code int
+
+		BeforeEach(func() {
+			method = "GET"
+			code = 200
+		})
+
+		JustBeforeEach(func() {
+			rw = httptest.NewRecorder()
+			req, _ := http.NewRequest(method, "/foo", nil)
+			ctx := goa.NewContext(nil, rw, req, nil)
+			Ω(s.Send(ctx, code, map[string]string{"name": "foo"})).ShouldNot(HaveOccurred())
+		})
+
+		It("writes the body", func() {
+			Ω(rw.Code).Should(Equal(200))
+			Ω(rw.Body.String()).Should(Equal(`{"name":"foo"}` + "\n"))
+		})
+
+		Context("with a no content status", func() {
+			BeforeEach(func() {
+				code = 204
+			})
+
+			It("does not write the body", func() {
+				Ω(rw.Code).Should(Equal(204))
+				Ω(rw.Body.Len()).Should(BeZero())
+			})
+		})
+
+		Context("with a HEAD request", func() {
+			BeforeEach(func() {
+				method = "HEAD"
+			})
+
+			It("does not write the body", func() {
+				Ω(rw.Code).Should(Equal(200))
+				Ω(rw.Body.Len()).Should(BeZero())
+			})
+		})
+	})
+
 	Describe("MaxRequestBodyLength", func() {
 		var rw *TestResponseWriter
 		var req *http.Request
@@ -387,6 +433,11 @@ var _ = Describe("Service", func() {
 		var respContent = []byte(`{"foo":"bar"}`)
 
 		var muxHandler goa.MuxHandler
+		var caching goa.Middleware
+
+		BeforeEach(func() {
+			caching = nil
+		})
 
 		JustBeforeEach(func() {
 			gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
@@ -401,6 +452,9 @@ var _ = Describe("Service", func() {
 
 			ctrl := s.NewController("test")
 			handler = ctrl.FileHandler("/swagger.json", "public/swagger/swagger.json")
+			if caching != nil {
+				handler = caching(handler)
+			}
 			muxHandler = ctrl.MuxHandler("testAct", handler, nil)
 		})
 
@@ -432,6 +486,32 @@ var _ = Describe("Service", func() {
 				tw := rw.(*TestResponseWriter)
 				Ω(tw.Status).Should(Equal(respStatus))
 				Ω(tw.Body).Should(Equal(respContent))
+				Ω(tw.Header().Get("ETag")).Should(BeEmpty())
+				Ω(tw.Header().Get("Cache-Control")).Should(BeEmpty())
+			})
+
+			Context("with file caching", func() {
+				BeforeEach(func() {
+					caching = goa.FileCaching(true, 3600)
+				})
+
+				It("sets the caching headers", func() {
+					tw := rw.(*TestResponseWriter)
+					Ω(tw.Status).Should(Equal(respStatus))
+					Ω(tw.Header().Get("ETag")).ShouldNot(BeEmpty())
+					Ω(tw.Header().Get("Cache-Control")).Should(Equal("max-age=3600"))
+				})
+
+				It("replies to conditional requests", func() {
+					etag := rw.(*TestResponseWriter).Header().Get("ETag")
+					r2, err := http.NewRequest("GET", "/swagger.json", nil)
+					Ω(err).ShouldNot(HaveOccurred())
+					r2.Header.Set("If-None-Match", etag)
+					rw2 := &TestResponseWriter{ParentHeader: make(http.Header)}
+					muxHandler(rw2, r2, p)
+					Ω(rw2.Status).Should(Equal(http.StatusNotModified))
+					Ω(rw2.Body).Should(BeEmpty())
+				})
 			})
 		})
 	})
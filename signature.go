@@ -0,0 +1,113 @@
+package goa
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// SignatureKeyProvider returns the key used to compute the signature of the given request.
+type SignatureKeyProvider func(req *http.Request) ([]byte, error)
+
+// signatureHashes lists the hash functions used by the supported signature algorithms.
+var signatureHashes = map[string]func() hash.Hash{
+	"hmac-sha1":   sha1.New,
+	"hmac-sha256": sha256.New,
+	"hmac-sha512": sha512.New,
+}
+
+// WithSignatureKeyProvider creates a context containing the given signature key provider. The
+// generated servers use the provider stored in the service context to verify request signatures.
+func WithSignatureKeyProvider(ctx context.Context, p SignatureKeyProvider) context.Context {
+	return context.WithValue(ctx, signatureKeyProviderKey, p)
+}
+
+// ContextSignatureKeyProvider extracts the signature key provider from the given context, nil if
+// there is none.
+func ContextSignatureKeyProvider(ctx context.Context) SignatureKeyProvider {
+	if p := ctx.Value(signatureKeyProviderKey); p != nil {
+		return p.(SignatureKeyProvider)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded signature of body computed with the given algorithm and key.
+// The supported algorithms are "hmac-sha1", "hmac-sha256" and "hmac-sha512".
+func Sign(algorithm string, key, body []byte) (string, error) {
+	h, ok := signatureHashes[algorithm]
+	if !ok {
+		return "", fmt.Errorf("unsupported signature algorithm %#v", algorithm)
+	}
+	mac := hmac.New(h, key)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// VerifySignature returns a mux handler that verifies the signature of the request body before
+// calling h. The signature is read from the given header and compared with the signature computed
+// with the given algorithm and the key returned by the signature key provider stored in the
+// service context. The request body is read once and replaced with an in-memory reader so that h
+// may decode it. The handler responds with the given status if the signature is missing or
+// invalid and does not call h.
+func VerifySignature(service *Service, header, algorithm string, status int, h MuxHandler) MuxHandler {
+	invalid := NewErrorClass("invalid_signature", status)
+	return func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+		err := verifySignature(service.Context, req, header, algorithm)
+		if err == nil {
+			h(rw, req, params)
+			return
+		}
+		resp, ok := err.(*ErrorResponse)
+		if !ok {
+			resp = invalid(err).(*ErrorResponse)
+		}
+		ctx := NewContext(service.Context, rw, req, params)
+		if err := service.Send(ctx, resp.Status, resp); err != nil {
+			service.LogError("failed to send signature error", "err", err)
+		}
+	}
+}
+
+// verifySignature checks that the value of the given request header is the signature of the
+// request body.
+func verifySignature(ctx context.Context, req *http.Request, header, algorithm string) error {
+	provider := ContextSignatureKeyProvider(ctx)
+	if provider == nil {
+		return ErrInternal("no signature key provider, use WithSignatureKeyProvider to set one")
+	}
+	sig := req.Header.Get(header)
+	if sig == "" {
+		return fmt.Errorf("missing signature header %#v", header)
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return ErrBadRequest(err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	key, err := provider(req)
+	if err != nil {
+		return err
+	}
+	expected, err := Sign(algorithm, key, body)
+	if err != nil {
+		return ErrInternal(err)
+	}
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package goa_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sign", func() {
+	It("computes the hex encoded HMAC of the body", func() {
+		sig, err := goa.Sign("hmac-sha256", []byte("secret"), []byte(`{"name":"foo"}`))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(sig).Should(Equal("cc58c0415f9b0434f96dfa036eb259b229b9ae91f173db62158103bdffdaa378"))
+	})
+
+	It("rejects unsupported algorithms", func() {
+		_, err := goa.Sign("md5", []byte("secret"), nil)
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("VerifySignature", func() {
+	const body = `{"name":"foo"}`
+
+	var s *goa.Service
+	var signature string
+	var called bool
+	var read []byte
+	var rw *TestResponseWriter
+
+	BeforeEach(func() {
+		s = goa.New("test")
+		s.Encoder.Register(goa.NewJSONEncoder, "*/*")
+		s.Context = goa.WithSignatureKeyProvider(s.Context, func(*http.Request) ([]byte, error) {
+			return []byte("secret"), nil
+		})
+		signature = "cc58c0415f9b0434f96dfa036eb259b229b9ae91f173db62158103bdffdaa378"
+		called = false
+		read = nil
+		rw = &TestResponseWriter{ParentHeader: make(http.Header)}
+	})
+
+	JustBeforeEach(func() {
+		h := func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+			called = true
+			read, _ = ioutil.ReadAll(req.Body)
+		}
+		req, _ := http.NewRequest("POST", "/webhooks", bytes.NewBufferString(body))
+		req.Header.Set("X-Signature", signature)
+		goa.VerifySignature(s, "X-Signature", "hmac-sha256", 401, h)(rw, req, nil)
+	})
+
+	It("calls the handler with the request body", func() {
+		Ω(called).Should(BeTrue())
+		Ω(string(read)).Should(Equal(body))
+	})
+
+	Context("with an invalid signature", func() {
+		BeforeEach(func() {
+			signature = "invalid"
+		})
+
+		It("responds with the given status", func() {
+			Ω(called).Should(BeFalse())
+			Ω(rw.Status).Should(Equal(401))
+			Ω(string(rw.Body)).Should(ContainSubstring(`"code":"invalid_signature"`))
+		})
+	})
+
+	Context("with no signature key provider", func() {
+		BeforeEach(func() {
+			s.Context = goa.New("test").Context
+		})
+
+		It("responds with an internal error", func() {
+			Ω(called).Should(BeFalse())
+			Ω(rw.Status).Should(Equal(500))
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package goa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrStreamClosed is the error returned when sending a result on a closed result stream.
+var ErrStreamClosed = errors.New("result stream is closed")
+
+type (
+	// StreamFrame is the envelope of the results sent by actions that stream results. Terminal
+	// is true for the frame that carries the final result, clients stop reading the stream
+	// once they receive it.
+	StreamFrame struct {
+		// Terminal is true if Data is the final result.
+		Terminal bool `json:"terminal,omitempty"`
+		// Data is the JSON encoded result.
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+
+	// ResultStream sends the results of actions that stream results followed by an optional
+	// final result. The generated code wraps ResultStream with typed Send and CloseAndSend
+	// methods.
+	ResultStream struct {
+		send     func(*StreamFrame) error
+		close    func() error
+		terminal bool
+		closed   bool
+	}
+)
+
+// NewResultStream returns a result stream that sends the frames with send and closes the
+// underlying transport with close. terminal indicates whether the transport supports sending a
+// final result.
+func NewResultStream(send func(*StreamFrame) error, close func() error, terminal bool) *ResultStream {
+	return &ResultStream{send: send, close: close, terminal: terminal}
+}
+
+// NewNDJSONResultStream returns a result stream that writes each frame as a line of a newline
+// delimited JSON response body. Each frame is flushed if w implements http.Flusher.
+func NewNDJSONResultStream(w http.ResponseWriter) *ResultStream {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	send := func(f *StreamFrame) error {
+		if err := enc.Encode(f); err != nil {
+			return err
+		}
+		flush(w)
+		return nil
+	}
+	return NewResultStream(send, func() error { return nil }, true)
+}
+
+// NewSSEResultStream returns a result stream that writes each frame as a server-sent event.
+// Server-sent events cannot carry a final result.
+func NewSSEResultStream(w http.ResponseWriter) *ResultStream {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	send := func(f *StreamFrame) error {
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", f.Data); err != nil {
+			return err
+		}
+		flush(w)
+		return nil
+	}
+	return NewResultStream(send, func() error { return nil }, false)
+}
+
+// Send encodes v and sends it on the stream.
+func (s *ResultStream) Send(v interface{}) error {
+	return s.sendFrame(v, false)
+}
+
+// CloseAndSend encodes v, sends it as the final result and closes the stream.
+func (s *ResultStream) CloseAndSend(v interface{}) error {
+	if !s.terminal {
+		return errors.New("result stream transport cannot send a final result")
+	}
+	if err := s.sendFrame(v, true); err != nil {
+		return err
+	}
+	return s.Close()
+}
+
+// Close closes the stream, closing a closed stream has no effect.
+func (s *ResultStream) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.close()
+}
+
+// sendFrame encodes v in a frame and sends it.
+func (s *ResultStream) sendFrame(v interface{}, terminal bool) error {
+	if s.closed {
+		return ErrStreamClosed
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.send(&StreamFrame{Terminal: terminal, Data: data})
+}
+
+// flush sends the buffered response data to the client if w or the response writer wrapped by
+// ResponseData supports it.
+func flush(w http.ResponseWriter) {
+	if rd, ok := w.(*ResponseData); ok {
+		w = rd.ResponseWriter
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
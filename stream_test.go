@@ -0,0 +1,44 @@
+package goa_test
+
+import (
+	"net/http/httptest"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResultStream", func() {
+	var rw *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		rw = httptest.NewRecorder()
+	})
+
+	Context("with newline delimited JSON", func() {
+		It("writes the results followed by the terminal frame", func() {
+			s := goa.NewNDJSONResultStream(rw)
+			Ω(s.Send(map[string]int{"done": 1})).ShouldNot(HaveOccurred())
+			Ω(s.CloseAndSend(map[string]int{"imported": 2})).ShouldNot(HaveOccurred())
+			Ω(rw.Header().Get("Content-Type")).Should(Equal("application/x-ndjson"))
+			Ω(rw.Body.String()).Should(Equal(`{"data":{"done":1}}` + "\n" + `{"terminal":true,"data":{"imported":2}}` + "\n"))
+			Ω(rw.Flushed).Should(BeTrue())
+		})
+
+		It("rejects results sent after the stream is closed", func() {
+			s := goa.NewNDJSONResultStream(rw)
+			Ω(s.Close()).ShouldNot(HaveOccurred())
+			Ω(s.Send("late")).Should(Equal(goa.ErrStreamClosed))
+		})
+	})
+
+	Context("with server-sent events", func() {
+		It("writes the results as events and cannot send a final result", func() {
+			s := goa.NewSSEResultStream(rw)
+			Ω(s.Send(map[string]int{"done": 1})).ShouldNot(HaveOccurred())
+			Ω(s.CloseAndSend(map[string]int{"imported": 2})).Should(HaveOccurred())
+			Ω(rw.Header().Get("Content-Type")).Should(Equal("text/event-stream"))
+			Ω(rw.Body.String()).Should(Equal("data: {\"done\":1}\n\n"))
+		})
+	})
+})
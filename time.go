@@ -0,0 +1,128 @@
+package goa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// UnixTime is a time encoded as an integer number of seconds since the Unix epoch. It is
+	// the Go type of the DateTime attributes with the "unix" time format.
+	UnixTime struct{ time.Time }
+
+	// UnixMilliTime is a time encoded as an integer number of milliseconds since the Unix
+	// epoch. It is the Go type of the DateTime attributes with the "unix-ms" time format.
+	UnixMilliTime struct{ time.Time }
+
+	// Duration is a duration encoded as a Go duration string, e.g. "1h30m". It is the Go type
+	// of the Duration attributes of request and response bodies.
+	Duration struct{ time.Duration }
+
+	// MilliDuration is a duration encoded as an integer number of milliseconds. It is the Go
+	// type of the Duration attributes with the "ms" format.
+	MilliDuration struct{ time.Duration }
+)
+
+// ParseUnixTime parses a time encoded as an integer number of seconds since the Unix epoch.
+func ParseUnixTime(s string) (time.Time, error) {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid Unix time %q", s)
+	}
+	return time.Unix(sec, 0).UTC(), nil
+}
+
+// ParseUnixMilliTime parses a time encoded as an integer number of milliseconds since the Unix
+// epoch.
+func ParseUnixMilliTime(s string) (time.Time, error) {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid Unix time in milliseconds %q", s)
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)).UTC(), nil
+}
+
+// ParseMilliDuration parses a duration encoded as an integer number of milliseconds.
+func ParseMilliDuration(s string) (time.Duration, error) {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration in milliseconds %q", s)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// MarshalJSON encodes t as a JSON number.
+func (t UnixTime) MarshalJSON() ([]byte, error) { return t.MarshalText() }
+
+// UnmarshalJSON decodes t from a JSON number.
+func (t *UnixTime) UnmarshalJSON(b []byte) error { return t.UnmarshalText(b) }
+
+// MarshalText encodes t as a decimal number of seconds.
+func (t UnixTime) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
+
+// UnmarshalText decodes t from a decimal number of seconds.
+func (t *UnixTime) UnmarshalText(b []byte) (err error) {
+	t.Time, err = ParseUnixTime(string(b))
+	return
+}
+
+// MarshalJSON encodes t as a JSON number.
+func (t UnixMilliTime) MarshalJSON() ([]byte, error) { return t.MarshalText() }
+
+// UnmarshalJSON decodes t from a JSON number.
+func (t *UnixMilliTime) UnmarshalJSON(b []byte) error { return t.UnmarshalText(b) }
+
+// MarshalText encodes t as a decimal number of milliseconds.
+func (t UnixMilliTime) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)), nil
+}
+
+// UnmarshalText decodes t from a decimal number of milliseconds.
+func (t *UnixMilliTime) UnmarshalText(b []byte) (err error) {
+	t.Time, err = ParseUnixMilliTime(string(b))
+	return
+}
+
+// MarshalJSON encodes d as a JSON string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON decodes d from a JSON string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return fmt.Errorf("invalid duration %s, must be a string", b)
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalText encodes d as a Go duration string.
+func (d Duration) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// UnmarshalText decodes d from a Go duration string.
+func (d *Duration) UnmarshalText(b []byte) (err error) {
+	d.Duration, err = time.ParseDuration(strings.TrimSpace(string(b)))
+	return
+}
+
+// MarshalJSON encodes d as a JSON number.
+func (d MilliDuration) MarshalJSON() ([]byte, error) { return d.MarshalText() }
+
+// UnmarshalJSON decodes d from a JSON number.
+func (d *MilliDuration) UnmarshalJSON(b []byte) error { return d.UnmarshalText(b) }
+
+// MarshalText encodes d as a decimal number of milliseconds.
+func (d MilliDuration) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(d.Duration/time.Millisecond), 10)), nil
+}
+
+// UnmarshalText decodes d from a decimal number of milliseconds.
+func (d *MilliDuration) UnmarshalText(b []byte) (err error) {
+	d.Duration, err = ParseMilliDuration(string(b))
+	return
+}
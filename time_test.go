@@ -0,0 +1,50 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Time formats", func() {
+	type body struct {
+		At       goa.UnixTime      `json:"at"`
+		AtMilli  goa.UnixMilliTime `json:"at_ms"`
+		Timeout  goa.Duration      `json:"timeout"`
+		Interval goa.MilliDuration `json:"interval"`
+	}
+
+	var at = time.Date(2016, 5, 1, 10, 30, 0, 250000000, time.UTC)
+	var encoded = `{"at":1462098600,"at_ms":1462098600250,"timeout":"1m30s","interval":1500}`
+
+	It("encodes the values", func() {
+		b, err := json.Marshal(body{
+			At:       goa.UnixTime{Time: at},
+			AtMilli:  goa.UnixMilliTime{Time: at},
+			Timeout:  goa.Duration{Duration: 90 * time.Second},
+			Interval: goa.MilliDuration{Duration: 1500 * time.Millisecond},
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(encoded))
+	})
+
+	It("decodes the values", func() {
+		var v body
+		Ω(json.Unmarshal([]byte(encoded), &v)).ShouldNot(HaveOccurred())
+		Ω(v.At.Time).Should(Equal(at.Truncate(time.Second)))
+		Ω(v.AtMilli.Time).Should(Equal(at))
+		Ω(v.Timeout.Duration).Should(Equal(90 * time.Second))
+		Ω(v.Interval.Duration).Should(Equal(1500 * time.Millisecond))
+	})
+
+	It("rejects invalid values", func() {
+		var v body
+		Ω(json.Unmarshal([]byte(`{"at":"2016-05-01T10:30:00Z"}`), &v)).Should(HaveOccurred())
+		Ω(json.Unmarshal([]byte(`{"timeout":90}`), &v)).Should(HaveOccurred())
+		_, err := goa.ParseMilliDuration("1.5s")
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,22 @@
+package goa_test
+
+import (
+	"crypto/x509"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SelfSignedCertificate", func() {
+	It("returns a certificate valid for the given hosts", func() {
+		cert, err := goa.SelfSignedCertificate("localhost", "127.0.0.1")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(cert.Certificate).Should(HaveLen(1))
+		parsed, err := x509.ParseCertificate(cert.Certificate[0])
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(parsed.VerifyHostname("localhost")).ShouldNot(HaveOccurred())
+		Ω(parsed.VerifyHostname("127.0.0.1")).ShouldNot(HaveOccurred())
+		Ω(parsed.VerifyHostname("example.com")).Should(HaveOccurred())
+	})
+})
@@ -153,3 +153,26 @@ func ValidatePattern(p string, val string) bool {
 	}
 	return r.MatchString(val)
 }
+
+// ValidateTogether returns an error for each of the given parameters or fields that is not set if
+// at least one of them is. set indicates whether each of the names is set. ctx describes the
+// parent of the names in the error messages, e.g. "request parameters".
+func ValidateTogether(ctx string, names []string, set []bool) error {
+	present := -1
+	for i, s := range set {
+		if s {
+			present = i
+			break
+		}
+	}
+	if present < 0 {
+		return nil
+	}
+	var err error
+	for i, s := range set {
+		if !s {
+			err = MergeErrors(err, IncompleteGroupError(ctx, names[present], names[i]))
+		}
+	}
+	return err
+}
@@ -396,3 +396,22 @@ var _ = Describe("ValidateFormat", func() {
 		})
 	})
 })
+
+var _ = Describe("ValidateTogether", func() {
+	names := []string{"lat", "lng", "radius"}
+
+	It("accepts a complete group", func() {
+		Ω(goa.ValidateTogether("request parameters", names, []bool{true, true, true})).ShouldNot(HaveOccurred())
+	})
+
+	It("accepts an absent group", func() {
+		Ω(goa.ValidateTogether("request parameters", names, []bool{false, false, false})).ShouldNot(HaveOccurred())
+	})
+
+	It("rejects a partial group", func() {
+		err := goa.ValidateTogether("request parameters", names, []bool{false, true, false})
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring(`"lat" is missing in request parameters, it is required when "lng" is set`))
+		Ω(err.Error()).Should(ContainSubstring(`"radius" is missing in request parameters, it is required when "lng" is set`))
+	})
+})